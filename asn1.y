@@ -14,8 +14,12 @@ import (
 %union{
     name         string
     numberRepr   string
+    bstring      string
+    hstring      string
+    cstring      string
 
     Number       Number
+    BigNumber    BigNumber
     Real         Real
     TagDefault int
     ExtensionDefault bool
@@ -41,6 +45,11 @@ import (
     IntersectionElements IntersectionElements
     Exclusions Exclusions
     Elements Elements
+    InnerTypeConstraint InnerTypeConstraint
+    NamedConstraint NamedConstraint
+    NamedConstraints []NamedConstraint
+    ValueConstraint *Constraint
+    Presence int
     SubtypeConstraint SubtypeConstraint
     RangeEndpoint RangeEndpoint
     NamedType NamedType
@@ -63,11 +72,16 @@ import (
     ExtensionAdditionAlternative ChoiceExtension
     ExtensionAdditionAlternativesList []ChoiceExtension
     ExtensionAdditions []ExtensionAddition
+    ExtensionAdditionGroup ExtensionAdditionGroup
+    ExtensionAdditionAlternativesGroup ExtensionAdditionAlternativesGroup
     NamedNumberList []NamedNumber
     NamedNumber NamedNumber
     EnumeratedType EnumeratedType
     Enumeration []EnumerationItem
     EnumerationItem EnumerationItem
+    BracedValue BracedValue
+    BracedComponent BracedComponent
+    XMLValue XMLValue
 }
 
 %token WHITESPACE
@@ -75,22 +89,18 @@ import (
 %token <name> TYPEORMODULEREFERENCE
 %token <name> VALUEIDENTIFIER
 %token <Number> NUMBER
-%token <bstring> BSTRING          // TODO not implemented in lexer
-%token <bstring> XMLBSTRING       // TODO not implemented in lexer
-%token <hstring> HSTRING          // TODO not implemented in lexer
-%token <hstring> XMLHSTRING       // TODO not implemented in lexer
-%token <cstring> CSTRING          // TODO not implemented in lexer
-%token <cstring> XMLCSTRING       // TODO not implemented in lexer
+%token <BigNumber> BIGNUMBER
+%token <bstring> BSTRING
+%token <hstring> HSTRING
+%token <cstring> CSTRING
 %token ASSIGNMENT
 %token RANGE_SEPARATOR
 %token ELLIPSIS
 %token LEFT_VERSION_BRACKETS
 %token RIGHT_VERSION_BRACKETS
-%token XML_END_TAG_START    // TODO not implemented in lexer
-%token XML_SINGLE_START_END // TODO not implemented in lexer
-%token XML_BOOLEAN_TRUE     // TODO not implemented in lexer
-%token XML_BOOLEAN_FALSE    // TODO not implemented in lexer
-%token XMLASN1TYPENAME      // TODO not implemented in lexer
+// XMLTypedValue is lexed as a single token, since lexical items of XML value notation
+// depend on the governing type, see ASN1Lexer.consumeXMLTypedValue.
+%token <XMLValue> XML_TYPED_VALUE
 
 %token EXPONENT // differs from spec, for REAL values to work
 
@@ -236,7 +246,7 @@ import (
 %type <ObjectIdentifierValue> ObjIdComponentsList
 %type <ObjectIdentifierValue> ObjectIdentifierValue
 %type <Value> BuiltinValue
-%type <Value> Value
+%type <Value> Value ReferencedValue
 %type <Value> IntegerValue
 %type <Type> RealType
 %type <Value> RealValue
@@ -247,6 +257,7 @@ import (
 %type <Number> number
 %type <Assignment> Assignment
 %type <Assignment> ValueAssignment
+%type <Assignment> XMLValueAssignment
 %type <Assignment> TypeAssignment
 %type <AssignmentList> AssignmentList
 %type <ModuleBody> ModuleBody
@@ -254,7 +265,10 @@ import (
 %type <Type> ConstrainedType
 %type <Type> TypeWithConstraint
 %type <Constraint> Constraint
+%type <Constraint> SingleTypeConstraint
 %type <ConstraintSpec> ConstraintSpec
+%type <ConstraintSpec> GeneralConstraint
+%type <ConstraintSpec> ContentsConstraint
 %type <SubtypeConstraint> SubtypeConstraint
 %type <SubtypeConstraint> ElementSetSpecs
 %type <SubtypeConstraint> RootElementSetSpec
@@ -272,7 +286,15 @@ import (
 %type <Elements> ValueRange
 %type <Elements> SubtypeElements
 %type <Elements> TypeConstraint
+%type <Elements> ContainedSubtype
+%type <Elements> PermittedAlphabet
+%type <Elements> PatternConstraint
 %type <Elements> InnerTypeConstraints
+%type <InnerTypeConstraint> MultipleTypeConstraints FullSpecification PartialSpecification
+%type <NamedConstraints> TypeConstraints
+%type <NamedConstraint> NamedConstraint ComponentConstraint
+%type <ValueConstraint> ValueConstraint
+%type <Presence> PresenceConstraint
 %type <Elements> SizeConstraint
 %type <RangeEndpoint> LowerEndpoint UpperEndpoint
 %type <Value> LowerEndValue UpperEndValue
@@ -310,15 +332,22 @@ import (
 %type <NamedType> NamedType
 %type <ExtensionAdditionAlternative> ExtensionAdditionAlternative
 %type <ExtensionAdditionAlternativesList> ExtensionAdditionAlternatives
-%type <ExtensionAdditionAlternativesList> ExtensionAdditionAlternativesList
 %type <ExtensionAdditions> ExtensionAdditions
-%type <ExtensionAdditions> ExtensionAdditionList
 %type <ExtensionAdditions> ExtensionAddition
+%type <ExtensionAdditionGroup> ExtensionAdditionGroup
+%type <ExtensionAdditionAlternativesGroup> ExtensionAdditionAlternativesGroup
+%type <Number> VersionNumber
 %type <NamedNumber> NamedNumber
 %type <NamedNumberList> NamedNumberList
 %type <EnumeratedType> Enumerations
-%type <Enumeration> Enumeration RootEnumeration AdditionalEnumeration
+%type <Enumeration> Enumeration
 %type <EnumerationItem> EnumerationItem
+%type <Value> BitStringValue
+%type <Value> CharacterStringValue RestrictedCharacterStringValue
+%type <Value> ChoiceValue NullValue
+%type <Value> BracedValue BracedAtom ConstraintValue
+%type <BracedValue> BracedComponentList
+%type <BracedComponent> BracedComponent
 
 //
 // end declarations
@@ -333,6 +362,12 @@ import (
 // Code inside the grammar actions may refer to the variable yylex,
 // which holds the yyLexer passed to yyParse.
 
+// Not defined in the doc.
+// Allows several modules to be defined in a single input.
+ModuleDefinitionList : ModuleDefinition
+                     | ModuleDefinitionList ModuleDefinition
+;
+
 ModuleDefinition :
     ModuleIdentifier
     DEFINITIONS
@@ -342,7 +377,10 @@ ModuleDefinition :
     BEGIN
     ModuleBody
     END
-    { yylex.(*ASN1Lexer).result = &ModuleDefinition{ModuleIdentifier: $1, TagDefault: $3, ExtensibilityImplied: $4, ModuleBody: $7} }
+    {
+        lex := yylex.(*ASN1Lexer)
+        lex.results = append(lex.results, &ModuleDefinition{ModuleIdentifier: $1, TagDefault: $3, ExtensibilityImplied: $4, ModuleBody: $7})
+    }
 ;
 
 typereference: TYPEORMODULEREFERENCE  { $$ = TypeReference($1) }
@@ -453,7 +491,7 @@ AssignmentList : Assignment  { $$ = AssignmentList{$1} }
 
 Assignment : TypeAssignment
            | ValueAssignment
-//           | XMLValueAssignment
+           | XMLValueAssignment
 //           | ValueSetTypeAssignment
 //           | ObjectClassAssignment
 //           | ObjectAssignment
@@ -491,7 +529,10 @@ DefinedValue :
 TypeAssignment : typereference ASSIGNMENT Type  { $$ = TypeAssignment{$1, $3} }
 ;
 
-ValueAssignment : valuereference Type ASSIGNMENT Value  { $$ = ValueAssignment{$1, $2, $4} }
+ValueAssignment : valuereference Type ASSIGNMENT Value  { $$ = ValueAssignment{$1, $2, valueOfType($2, $4)} }
+;
+
+XMLValueAssignment : valuereference ASSIGNMENT XML_TYPED_VALUE  { $$ = ValueAssignment{$1, xmlValueType(yylex, $3.Name), $3} }
 ;
 
 // 16.1
@@ -544,32 +585,68 @@ NamedType : identifier Type  { $$ = NamedType{Identifier: Identifier($1), Type:
 // 16.7
 
 Value : BuiltinValue
-//      | ReferencedValue
+      | NullValue
+      | ReferencedValue
 //      | ObjectClassFieldValue
 ;
 
 // 16.8
 
 // TODO
-BuiltinValue : // BitStringValue
-             /*|*/ BooleanValue
-//             | CharacterStringValue
-//             | ChoiceValue
+BuiltinValue : BitStringValue
+             | BooleanValue
+             | CharacterStringValue
+             | ChoiceValue
 //             | EmbeddedPDVValue
 //             | EnumeratedValue
 //             | ExternalValue
 //             | InstanceOfValue
                | IntegerValue
-//             | NullValue
-               | ObjectIdentifierValue  { $$ = $1 }
-//             | OctetStringValue
+//             | NullValue  -- moved to Value, see ConstraintValue
+//             | ObjectIdentifierValue  -- parsed as BracedValue
+//             | OctetStringValue  -- parsed as BitStringValue, see 22.3
                | RealValue
 //             | RelativeOIDValue
-//             | SequenceValue
-//             | SequenceOfValue
-//             | SetValue
-//             | SetOfValue
+//             | SequenceValue  -- parsed as BracedValue
+//             | SequenceOfValue  -- parsed as BracedValue
+//             | SetValue  -- parsed as BracedValue
+//             | SetOfValue  -- parsed as BracedValue
 //             | TaggedValue
+               | BracedValue
+;
+
+// Not defined in the doc.
+// Value notation in curly braces is ambiguous without knowing the governing type, e.g. { a 1 } is valid both as
+// OBJECT IDENTIFIER and SEQUENCE value, and { a, b } is valid as BIT STRING, SEQUENCE OF and character string value.
+// Components are collected as they are written, and are converted with the governing type, see BracedValue.As.
+BracedValue : OPEN_CURLY CLOSE_CURLY  { $$ = BracedValue{} }
+            | OPEN_CURLY BracedComponentList CLOSE_CURLY  { $$ = $2 }
+;
+
+BracedComponentList : BracedComponent  { $$ = BracedValue{$1} }
+                    | BracedComponentList COMMA BracedComponent  { $$ = append($1, $3) }
+;
+
+BracedComponent : BracedAtom  { $$ = BracedComponent{$1} }
+                | BracedComponent BracedAtom  { $$ = append($1, $2) }
+;
+
+BracedAtom : Value
+           | NameAndNumberForm  { $$ = NameAndNumber($1) }
+;
+
+// Not defined in the doc.
+// NULL in constraints is parsed as TypeConstraint, since NULL type permits the same single value.
+ConstraintValue : BuiltinValue
+                | ReferencedValue
+;
+
+// 16.11
+
+// Bare valuereference can not be told apart from identifier of IntegerValue, so it is parsed as
+// IdentifiedIntegerValue and resolved during code generation.
+ReferencedValue : modulereference DOT valuereference  { $$ = DefinedValue{ModuleReference($1), $3} }
+//                | ValueFromObject
 ;
 
 // 17.3
@@ -601,7 +678,10 @@ SignedNumber : NUMBER  { $$ = $1 }
 
 // 18.9
 
+// Edited from the doc - numbers which do not fit Number are lexed as BIGNUMBER, and are valid only as integer values
 IntegerValue : SignedNumber  { $$ = $1 }
+             | BIGNUMBER  { $$ = $1 }
+             | MINUS BIGNUMBER  { $$ = $2.UnaryMinus() }
              | identifier  { $$ = IdentifiedIntegerValue{Name: $1} }
 ;
 
@@ -610,18 +690,15 @@ IntegerValue : SignedNumber  { $$ = $1 }
 EnumeratedType : ENUMERATED OPEN_CURLY Enumerations CLOSE_CURLY  { $$ = $3 }
 ;
 
-Enumerations : RootEnumeration  { $$ = EnumeratedType{RootEnumeration: $1} }
-             | RootEnumeration COMMA ELLIPSIS ExceptionSpec  { $$ = EnumeratedType{RootEnumeration: $1} }
-             | RootEnumeration COMMA ELLIPSIS ExceptionSpec COMMA AdditionalEnumeration  { $$ = EnumeratedType{RootEnumeration: $1, AdditionalEnumeration: $6} }
-
-RootEnumeration : Enumeration
-;
-
-AdditionalEnumeration : Enumeration
+// RootEnumeration and AdditionalEnumeration are inlined, and Enumeration is left-recursive,
+// so that parser does not need to look past the COMMA to tell extension marker from the next item.
+Enumerations : Enumeration  { $$ = EnumeratedType{RootEnumeration: $1} }
+             | Enumeration COMMA ELLIPSIS ExceptionSpec  { $$ = EnumeratedType{RootEnumeration: $1, Extensible: true} }
+             | Enumeration COMMA ELLIPSIS ExceptionSpec COMMA Enumeration  { $$ = EnumeratedType{RootEnumeration: $1, AdditionalEnumeration: $6, Extensible: true} }
 ;
 
 Enumeration : EnumerationItem  { $$ = []EnumerationItem{$1} }
-            | EnumerationItem COMMA Enumeration  { $$ = append([]EnumerationItem{$1}, $3...) }
+            | Enumeration COMMA EnumerationItem  { $$ = append($1, $3) }
 ;
 
 EnumerationItem : NamedNumber  { $$ = $1 }
@@ -649,8 +726,10 @@ SpecialRealValue : PLUS_INFINITY  { $$ = Real(math.Inf(1)) }
 ;
 
 // TODO this seem to be not strict enough (spaces can sneak in into composite value)
-realnumber : NUMBER  { $$ = parseRealNumber($1, 0, 0) }
-           | NUMBER DOT NUMBER  { $$ = parseRealNumber($1, $3, 0) }
+// Edited from the doc - NUMBER alone is parsed as IntegerValue, so that it does not conflict with SignedNumber
+// (keeping it adds reduce/reduce conflicts in every value context). Code generation converts a Number to
+// float64 when the governing type is REAL, see TestIntegerWrittenRealValues.
+realnumber : NUMBER DOT NUMBER  { $$ = parseRealNumber($1, $3, 0) }
            | NUMBER DOT NUMBER EXPONENT SignedExponent  { $$ = parseRealNumber($1, $3, $5) }
            | NUMBER EXPONENT SignedExponent  { $$ = parseRealNumber($1, 0, $3) }
 ;
@@ -673,21 +752,43 @@ NamedBit : identifier OPEN_ROUND number CLOSE_ROUND  { $$ = NamedBit{Name: Ident
          | identifier OPEN_ROUND DefinedValue CLOSE_ROUND  { $$ = NamedBit{Name: Identifier($1), Index: $3} }
 ;
 
+// 21.9
+
+// Named bits form is parsed as BracedValue.
+BitStringValue : BSTRING  { $$ = parseBString($1) }
+               | HSTRING  { $$ = parseHString($1) }
+//               | CONTAINING Value
+;
+
 // 22.1
 
 OctetStringType : OCTET STRING  { $$ = OctetStringType{} }
 ;
 
+// 22.3
+
+// Not used, as it is not distinguishable from BitStringValue without knowing governing type.
+// Such values are parsed as BitStringValue, see BitStringValue.OctetString.
+// OctetStringValue ::=
+//    bstring
+//    | hstring
+//    | CONTAINING Value
+
 // 23.1
 
 NullType : NULL  { $$ = NullType{} }
 ;
 
+// 23.3
+
+NullValue : NULL  { $$ = NullValue{} }
+;
+
 // 24.1
 
 SequenceType : SEQUENCE OPEN_CURLY CLOSE_CURLY  { $$ = SequenceType{} }
-             | SEQUENCE OPEN_CURLY ExtensionAndException OptionalExtensionMarker CLOSE_CURLY  { $$ = SequenceType{} }
-             | SEQUENCE OPEN_CURLY ComponentTypeLists CLOSE_CURLY  { $$ = SequenceType{Components: append($3.Components, $3.TrailingComponents...), ExtensionAdditions: $3.ExtensionAdditions} }
+             | SEQUENCE OPEN_CURLY ExtensionAndException OptionalExtensionMarker CLOSE_CURLY  { $$ = SequenceType{Extensible: true} }
+             | SEQUENCE OPEN_CURLY ComponentTypeLists CLOSE_CURLY  { $$ = SequenceType{Components: append($3.Components, $3.TrailingComponents...), ExtensionAdditions: $3.ExtensionAdditions, Extensible: $3.Extensible} }
 ;
 
 ExtensionAndException : ELLIPSIS
@@ -697,11 +798,11 @@ ExtensionAndException : ELLIPSIS
 OptionalExtensionMarker : COMMA ELLIPSIS | /*empty*/
 ;
 
-// TODO Extensions are not fully supported, extension information will be ignored.
 // Edited from the doc - ComponentTypeList used directly instead of RootComponentTypeList to avoid ambiguity around COMMA.
 ComponentTypeLists : ComponentTypeList  { $$ = ComponentTypeLists{Components: $1} }
-                   | ComponentTypeList COMMA ExtensionAndException ExtensionAdditions OptionalExtensionMarker  { $$ = ComponentTypeLists{Components: $1, ExtensionAdditions: $4} }
-                   | ComponentTypeList COMMA ExtensionAndException ExtensionAdditions ExtensionEndMarker COMMA ComponentTypeList  { $$ = ComponentTypeLists{Components: $1, ExtensionAdditions: $4, TrailingComponents: $7} }
+                   | ComponentTypeList COMMA ExtensionAndException ExtensionAdditions  { $$ = ComponentTypeLists{Components: $1, ExtensionAdditions: $4, Extensible: true} }
+                   | ComponentTypeList COMMA ExtensionAndException ExtensionAdditions ExtensionEndMarker  { $$ = ComponentTypeLists{Components: $1, ExtensionAdditions: $4, Extensible: true} }
+                   | ComponentTypeList COMMA ExtensionAndException ExtensionAdditions ExtensionEndMarker COMMA ComponentTypeList  { $$ = ComponentTypeLists{Components: $1, ExtensionAdditions: $4, TrailingComponents: $7, Extensible: true} }
 //                   | ExtensionAndException ExtensionAdditions ExtensionEndMarker "," RootComponentTypeList
 //                   | ExtensionAndException ExtensionAdditions OptionalExtensionMarker
 ;
@@ -713,22 +814,20 @@ RootComponentTypeList : ComponentTypeList
 ExtensionEndMarker : COMMA ELLIPSIS
 ;
 
-ExtensionAdditions : COMMA ExtensionAdditionList  { $$ = $2 }
+// Edited from the doc - leading COMMA is included into the list, so that the parser can tell
+// the next addition from ExtensionEndMarker by the token following COMMA.
+ExtensionAdditions : ExtensionAdditions COMMA ExtensionAddition  { $$ = append($1, $3...) }
                    | /*empty*/  { $$ = nil }
 ;
 
-ExtensionAdditionList : ExtensionAddition  { $$ = append([]ExtensionAddition{}, $1...) }
-                      | ExtensionAdditionList COMMA ExtensionAddition  { $$ = append($1, $3...) }
-;
-
 ExtensionAddition : ComponentType  { $$ = []ExtensionAddition{$1} }
-                  | ExtensionAdditionGroup  { $$ = nil }
+                  | ExtensionAdditionGroup  { $$ = []ExtensionAddition{$1} }
 
-ExtensionAdditionGroup : LEFT_VERSION_BRACKETS VersionNumber ComponentTypeList RIGHT_VERSION_BRACKETS
+ExtensionAdditionGroup : LEFT_VERSION_BRACKETS VersionNumber ComponentTypeList RIGHT_VERSION_BRACKETS  { $$ = ExtensionAdditionGroup{Version: $2, Components: $3} }
 ;
 
-VersionNumber : /*empty*/
-              | NUMBER COLON
+VersionNumber : /*empty*/  { $$ = Number(0) }
+              | NUMBER COLON  { $$ = $1 }
 ;
 
 ComponentTypeList : ComponentType  { $$ = append(make(ComponentTypeList, 0), $1) }
@@ -737,15 +836,15 @@ ComponentTypeList : ComponentType  { $$ = append(make(ComponentTypeList, 0), $1)
 
 ComponentType : NamedType  { $$ = NamedComponentType{NamedType: $1} }
               | NamedType OPTIONAL  { $$ = NamedComponentType{NamedType: $1, IsOptional: true} }
-              | NamedType DEFAULT Value  { $$ = NamedComponentType{NamedType: $1, Default: &$3} }
+              | NamedType DEFAULT Value  { defaultValue := valueOfType($1.Type, $3); $$ = NamedComponentType{NamedType: $1, Default: &defaultValue} }
               | COMPONENTS OF Type  { $$ = ComponentsOfComponentType{Type: $3} }
 ;
 
 // 26.1
 
 SetType :  SET OPEN_CURLY CLOSE_CURLY  { $$ = SetType{} }
-        |  SET OPEN_CURLY ExtensionAndException OptionalExtensionMarker CLOSE_CURLY  { $$ = SetType{} }
-        |  SET OPEN_CURLY ComponentTypeLists CLOSE_CURLY  { $$ = SetType{Components: append($3.Components, $3.TrailingComponents...), ExtensionAdditions: $3.ExtensionAdditions} }
+        |  SET OPEN_CURLY ExtensionAndException OptionalExtensionMarker CLOSE_CURLY  { $$ = SetType{Extensible: true} }
+        |  SET OPEN_CURLY ComponentTypeLists CLOSE_CURLY  { $$ = SetType{Components: append($3.Components, $3.TrailingComponents...), ExtensionAdditions: $3.ExtensionAdditions, Extensible: $3.Extensible} }
 
 
 // 27.1
@@ -764,35 +863,38 @@ AnyType : ANY  { $$ = AnyType{} }
 ChoiceType : CHOICE OPEN_CURLY AlternativeTypeLists CLOSE_CURLY  { $$ = $3 }
 ;
 
-AlternativeTypeLists : AlternativeTypeList COMMA ExtensionAndException ExtensionAdditionAlternatives OptionalExtensionMarker { $$ = ChoiceType{$1,$4} }
+AlternativeTypeLists : AlternativeTypeList COMMA ExtensionAndException ExtensionAdditionAlternatives { $$ = ChoiceType{AlternativeTypeList: $1, ExtensionTypes: $4, Extensible: true} }
+                     | AlternativeTypeList COMMA ExtensionAndException ExtensionAdditionAlternatives ExtensionEndMarker { $$ = ChoiceType{AlternativeTypeList: $1, ExtensionTypes: $4, Extensible: true} }
                      | AlternativeTypeList  { $$ = ChoiceType{AlternativeTypeList: $1} }
-                     | ExtensionAndException ExtensionAdditionAlternatives OptionalExtensionMarker { $$ = ChoiceType{nil, $2} }
+                     | ExtensionAndException ExtensionAdditionAlternatives { $$ = ChoiceType{ExtensionTypes: $2, Extensible: true} }
+                     | ExtensionAndException ExtensionAdditionAlternatives ExtensionEndMarker { $$ = ChoiceType{ExtensionTypes: $2, Extensible: true} }
 ;
 
 // defined in grammar, but screws up ExtensionAndException parsing
 RootAlternativeTypeList : AlternativeTypeList
 ;
 
-ExtensionAdditionAlternatives : COMMA ExtensionAdditionAlternativesList { $$ = $2 }
+// Edited from the doc - leading COMMA is included into the list, same as in ExtensionAdditions.
+ExtensionAdditionAlternatives : ExtensionAdditionAlternatives COMMA ExtensionAdditionAlternative { $$ = append($1, $3) }
                               | /*empty*/ { $$ = make([]ChoiceExtension, 0) }
 ;
 
-ExtensionAdditionAlternativesList : ExtensionAdditionAlternative  { $$ = append(make([]ChoiceExtension, 0), $1) }
-                                  | ExtensionAdditionAlternativesList COMMA ExtensionAdditionAlternative  { $$ = append($1, $3) }
-;
-
-ExtensionAdditionAlternative : /*ExtensionAdditionAlternativesGroup
-                             | */NamedType  { $$ = $1 }
+ExtensionAdditionAlternative : ExtensionAdditionAlternativesGroup  { $$ = $1 }
+                             | NamedType  { $$ = $1 }
 ;
 
-// TODO
-ExtensionAdditionAlternativesGroup : LEFT_VERSION_BRACKETS VersionNumber AlternativeTypeList RIGHT_VERSION_BRACKETS
+ExtensionAdditionAlternativesGroup : LEFT_VERSION_BRACKETS VersionNumber AlternativeTypeList RIGHT_VERSION_BRACKETS  { $$ = ExtensionAdditionAlternativesGroup{Version: $2, Alternatives: $3} }
 ;
 
 AlternativeTypeList : NamedType  { $$ = append(make([]NamedType, 0), $1) }
                     | AlternativeTypeList COMMA NamedType  { $$ = append($1, $3) }
 ;
 
+// 28.9
+
+ChoiceValue : identifier COLON Value  { $$ = ChoiceValue{Identifier: Identifier($1), Value: $3} }
+;
+
 // 30.1
 
 TaggedType : Tag Type  { $$ = TaggedType{Tag: $1, Type: $2} }
@@ -872,6 +974,18 @@ RestrictedCharacterStringType    : BMPString  { $$ = RestrictedStringType{LexTyp
                                  | VisibleString  { $$ = RestrictedStringType{LexType: VisibleString} }
 ;
 
+// 37.8
+
+// CharacterStringList, Quadruple and Tuple forms are parsed as BracedValue.
+RestrictedCharacterStringValue : CSTRING  { $$ = CharacterStringValue{CString($1)} }
+;
+
+// 37.12 (partially)
+
+CharacterStringValue : RestrictedCharacterStringValue
+//                     | UnrestrictedCharacterStringValue
+;
+
 // 40.1
 
 UnrestrictedCharacterStringType : CHARACTER STRING  { $$ = CharacterStringType{} }
@@ -907,7 +1021,19 @@ Constraint : OPEN_ROUND ConstraintSpec ExceptionSpec CLOSE_ROUND  { $$ = Constra
 ;
 
 ConstraintSpec : SubtypeConstraint  { $$ = $1 }
-//               | GeneralConstraint
+               | GeneralConstraint
+;
+
+// X.682, 8.1; only contents constraint is supported
+
+GeneralConstraint : ContentsConstraint
+;
+
+// X.682, 11.1
+
+ContentsConstraint : CONTAINING Type  { $$ = ContentsConstraint{Type: $2} }
+                   | ENCODED BY Value  { $$ = ContentsConstraint{EncodedBy: $3} }
+                   | CONTAINING Type ENCODED BY Value  { $$ = ContentsConstraint{Type: $2, EncodedBy: $5} }
 ;
 
 SubtypeConstraint : ElementSetSpecs
@@ -916,7 +1042,7 @@ SubtypeConstraint : ElementSetSpecs
 // 46.1
 
 ElementSetSpecs : RootElementSetSpec
-                | RootElementSetSpec COMMA ELLIPSIS  { $$ = $1 }
+                | RootElementSetSpec COMMA ELLIPSIS  { $$ = append($1, Unions{}) }
                 | RootElementSetSpec COMMA ELLIPSIS COMMA AdditionalElementSetSpec  { $$ = append($1, $5) }
 ;
 
@@ -966,18 +1092,23 @@ Elements : SubtypeElements { $$ = $1 }
 ;
 
 SubtypeElements : SingleValue
-//                | ContainedSubtype
+                | ContainedSubtype
                 | ValueRange
-//                | PermittedAlphabet
+                | PermittedAlphabet
                 | SizeConstraint
                 | TypeConstraint
                 | InnerTypeConstraints
-//                | PatternConstraint
+                | PatternConstraint
 ;
 
 // 47.2
 
-SingleValue : Value  { $$ = SingleValue{$1} }
+SingleValue : ConstraintValue  { $$ = SingleValue{$1} }
+;
+
+// 47.3
+
+ContainedSubtype : INCLUDES Type  { $$ = TypeConstraint{$2} }
 ;
 
 // 47.4
@@ -993,11 +1124,11 @@ UpperEndpoint : UpperEndValue  { $$ = RangeEndpoint{Value: $1} }
               | LESS UpperEndValue   { $$ = RangeEndpoint{Value: $2, IsOpen: true} }
 ;
 
-LowerEndValue : Value
+LowerEndValue : ConstraintValue
               | MIN  { $$ = nil }
 ;
 
-UpperEndValue : Value
+UpperEndValue : ConstraintValue
               | MAX  { $$ = nil }
 ;
 
@@ -1006,6 +1137,11 @@ UpperEndValue : Value
 SizeConstraint : SIZE Constraint  { $$ = SizeConstraint{$2} }
 ;
 
+// 47.7
+
+PermittedAlphabet : FROM Constraint  { $$ = PermittedAlphabet{$2} }
+;
+
 // 47.6.1
 
 TypeConstraint : Type  { $$ = TypeConstraint{$1} }
@@ -1013,8 +1149,8 @@ TypeConstraint : Type  { $$ = TypeConstraint{$1} }
 
 // 47.8.1
 
-InnerTypeConstraints :  WITH COMPONENT SingleTypeConstraint  { $$ = InnerTypeConstraint{} }
-                     | WITH COMPONENTS MultipleTypeConstraints  { $$ = InnerTypeConstraint{} }
+InnerTypeConstraints :  WITH COMPONENT SingleTypeConstraint  { c := $3; $$ = InnerTypeConstraint{Component: &c} }
+                     | WITH COMPONENTS MultipleTypeConstraints  { $$ = $3 }
 ;
 
 SingleTypeConstraint : Constraint
@@ -1024,25 +1160,35 @@ MultipleTypeConstraints : FullSpecification
                         | PartialSpecification
 ;
 
-FullSpecification : OPEN_CURLY TypeConstraints CLOSE_CURLY
+FullSpecification : OPEN_CURLY TypeConstraints CLOSE_CURLY  { $$ = InnerTypeConstraint{Components: $2} }
 ;
 
-PartialSpecification : OPEN_CURLY ELLIPSIS COMMA TypeConstraints CLOSE_CURLY
+PartialSpecification : OPEN_CURLY ELLIPSIS COMMA TypeConstraints CLOSE_CURLY  { $$ = InnerTypeConstraint{Components: $4, Partial: true} }
 ;
 
-TypeConstraints :  NamedConstraint
-                | NamedConstraint COMMA TypeConstraints
+TypeConstraints :  NamedConstraint  { $$ = []NamedConstraint{$1} }
+                | NamedConstraint COMMA TypeConstraints  { $$ = append([]NamedConstraint{$1}, $3...) }
+;
 
-NamedConstraint : identifier ComponentConstraint
+NamedConstraint : identifier ComponentConstraint  { $$ = $2; $$.Identifier = Identifier($1) }
 ;
 
-ComponentConstraint : ValueConstraint PresenceConstraint
+ComponentConstraint : ValueConstraint PresenceConstraint  { $$ = NamedConstraint{Constraint: $1, Presence: $2} }
 ;
 
-ValueConstraint : Constraint | /*empty*/
+ValueConstraint : Constraint  { c := $1; $$ = &c }
+                | /*empty*/  { $$ = nil }
 ;
 
-PresenceConstraint : PRESENT | ABSENT | OPTIONAL | /*empty*/
+PresenceConstraint : PRESENT  { $$ = PRESENCE_PRESENT }
+                   | ABSENT  { $$ = PRESENCE_ABSENT }
+                   | OPTIONAL  { $$ = PRESENCE_OPTIONAL }
+                   | /*empty*/  { $$ = PRESENCE_UNSPECIFIED }
+;
+
+// 47.9
+
+PatternConstraint : PATTERN Value  { $$ = PatternConstraint{$2} }
 ;
 
 // 49.4
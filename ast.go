@@ -1,5 +1,11 @@
 package asn1go
 
+import (
+	"math/big"
+	"slices"
+	"strings"
+)
+
 // ModuleDefinition represents ASN.1 ModuleName.
 // This and all other AST types are named according to their BNF in X.680 document,
 // if not specified otherwise.
@@ -103,8 +109,8 @@ func (l AssignmentList) GetType(name string) *TypeAssignment {
 }
 
 // Assignment is interface for Assignment nodes.
-// Only TypeAssignment and ValueAssignment are supported.
-// Other assignment types (value sets, xml values, objects) are not implemented.
+// Only TypeAssignment and ValueAssignment are supported, XML value assignments are represented
+// as ValueAssignment of XMLValue. Other assignment types (value sets, objects) are not implemented.
 type Assignment interface {
 	Reference() Reference
 }
@@ -229,6 +235,47 @@ func (x Number) UnaryMinus() Number {
 	return Number(-int(x))
 }
 
+// BigNumber is an integer value which does not fit Number, e.g. a bound of INTEGER (0..18446744073709551615).
+// Values fitting Number are always represented with Number.
+type BigNumber struct {
+	Int *big.Int
+}
+
+// newIntegerValue returns Number if n fits it, or BigNumber otherwise.
+func newIntegerValue(n *big.Int) Value {
+	if n.IsInt64() && int64(int(n.Int64())) == n.Int64() {
+		return Number(n.Int64())
+	}
+	return BigNumber{Int: n}
+}
+
+// Type implements Value.
+func (BigNumber) Type() Type {
+	return IntegerType{}
+}
+
+// String returns decimal representation of the number.
+func (x BigNumber) String() string {
+	return x.Int.String()
+}
+
+// UnaryMinus returns negated number, which is Number if it fits it.
+func (x BigNumber) UnaryMinus() Value {
+	return newIntegerValue(new(big.Int).Neg(x.Int))
+}
+
+// integerValue returns value of Number or BigNumber v. Returns false if v is not a number.
+func integerValue(v Value) (*big.Int, bool) {
+	switch n := v.(type) {
+	case Number:
+		return big.NewInt(int64(n)), true
+	case BigNumber:
+		return n.Int, true
+	default:
+		return nil, false
+	}
+}
+
 // Real is a floating point value.
 // This is a lexical construct, named `realnumber` in the doc.
 // See X.680, section 11.9.
@@ -302,6 +349,8 @@ type EnumeratedType struct {
 	// Alternatives of the enumeration.
 	RootEnumeration       []EnumerationItem
 	AdditionalEnumeration []EnumerationItem
+	// Extensible is set if type has extension marker, in which case encodings can contain unknown additions.
+	Extensible bool
 }
 
 // EnumerationItem is interface for items.
@@ -330,18 +379,46 @@ func (BooleanType) isType() {}
 type ChoiceType struct {
 	AlternativeTypeList []NamedType
 	ExtensionTypes      []ChoiceExtension
+	// Extensible is set if type has extension marker, in which case encodings can contain unknown additions.
+	Extensible bool
 	// TODO ExtensionAndException
 }
 
 // Zero implements Type.
 func (ChoiceType) isType() {}
 
+// Alternatives returns root alternatives followed by extension alternatives,
+// with alternatives of addition groups listed in place of the group.
+func (t ChoiceType) Alternatives() []NamedType {
+	res := slices.Clone(t.AlternativeTypeList)
+	for _, ext := range t.ExtensionTypes {
+		switch ext := ext.(type) {
+		case NamedType:
+			res = append(res, ext)
+		case ExtensionAdditionAlternativesGroup:
+			res = append(res, ext.Alternatives...)
+		}
+	}
+	return res
+}
+
 // ChoiceExtension is a type for choice extensions.
-// Only NamedType is implemented, ExtensionAdditionAlternativesGroup is not supported.
+// It is either NamedType or ExtensionAdditionAlternativesGroup.
 type ChoiceExtension interface {
 	isChoiceExtension()
 }
 
+// ExtensionAdditionAlternativesGroup is a group of CHOICE alternatives added in the same version of the type,
+// e.g. [[2: b INTEGER, c BOOLEAN ]].
+type ExtensionAdditionAlternativesGroup struct {
+	// Version is a version number of the group, or zero if it is not specified.
+	Version      Number
+	Alternatives []NamedType
+}
+
+// isChoiceExtension implements ChoiceExtension.
+func (ExtensionAdditionAlternativesGroup) isChoiceExtension() {}
+
 ////////////////////////////////////////////////
 // String types
 
@@ -377,6 +454,8 @@ func (OctetStringType) isType() {}
 type SequenceType struct {
 	Components         ComponentTypeList
 	ExtensionAdditions ExtensionAdditions
+	// Extensible is set if type has extension marker, in which case encodings can contain unknown additions.
+	Extensible bool
 }
 
 // Zero implements Type.
@@ -390,11 +469,37 @@ type ExtensionAddition interface {
 	isExtensionAddition()
 }
 
+// Components returns components of extension additions, with components of addition groups listed in place of the group.
+func (a ExtensionAdditions) Components() ComponentTypeList {
+	var res ComponentTypeList
+	for _, addition := range a {
+		switch addition := addition.(type) {
+		case ComponentType:
+			res = append(res, addition)
+		case ExtensionAdditionGroup:
+			res = append(res, addition.Components...)
+		}
+	}
+	return res
+}
+
+// ExtensionAdditionGroup is a group of SEQUENCE or SET components added in the same version of the type,
+// e.g. [[2: b INTEGER, c BOOLEAN ]].
+type ExtensionAdditionGroup struct {
+	// Version is a version number of the group, or zero if it is not specified.
+	Version    Number
+	Components ComponentTypeList
+}
+
+// isExtensionAddition implements ExtensionAddition.
+func (ExtensionAdditionGroup) isExtensionAddition() {}
+
 // ComponentTypeLists is not used in AST directly but is used in parser for intermediate representation.
 type ComponentTypeLists struct {
 	Components         ComponentTypeList
 	ExtensionAdditions ExtensionAdditions
 	TrailingComponents ComponentTypeList
+	Extensible         bool
 }
 
 // ComponentTypeList is a list of ComponentType.
@@ -436,6 +541,8 @@ func (ComponentsOfComponentType) isExtensionAddition() {}
 type SetType struct {
 	Components         ComponentTypeList
 	ExtensionAdditions ExtensionAdditions
+	// Extensible is set if type has extension marker, in which case encodings can contain unknown additions.
+	Extensible bool
 }
 
 // Zero implements Type.
@@ -539,11 +646,22 @@ type Constraint struct {
 }
 
 // ConstraintSpec can be SubtypeConstraint or GeneralConstraint.
-// GeneralConstraint is not implemented.
+// Of general constraints, only ContentsConstraint is implemented.
 type ConstraintSpec interface {
 	isConstraintSpec()
 }
 
+// ContentsConstraint is a constraint of OCTET STRING or BIT STRING type, which values hold encodings
+// of values of Type, or encodings produced by encoding rules identified by EncodedBy, see X.682, section 11.
+// Either of them can be nil.
+type ContentsConstraint struct {
+	Type      Type
+	EncodedBy Value
+}
+
+// IsConstraintSpec implements ConstraintSpec.
+func (ContentsConstraint) isConstraintSpec() {}
+
 // SingleElementConstraint is a Constraint of single intersection elements.
 func SingleElementConstraint(elem Elements) Constraint {
 	return Constraint{ConstraintSpec: SubtypeConstraint{
@@ -551,9 +669,16 @@ func SingleElementConstraint(elem Elements) Constraint {
 	}}
 }
 
-// SubtypeConstraint describes list of element sets that can be used in constainted type
+// SubtypeConstraint describes list of element sets that can be used in constainted type.
+// First element is the root element set. Extensible constraints have the second element,
+// which is the additional element set, or empty Unions if additions are not specified.
 type SubtypeConstraint []ElementSetSpec
 
+// Extensible returns true if constraint has extension marker.
+func (c SubtypeConstraint) Extensible() bool {
+	return len(c) > 1
+}
+
 // IsConstraintSpec implements ConstraintSpec.
 func (SubtypeConstraint) isConstraintSpec() {}
 
@@ -627,7 +752,8 @@ func (e RangeEndpoint) IsUnspecified() bool {
 	return e.Value == nil
 }
 
-// TypeConstraint is a type constraint.
+// TypeConstraint is a type constraint, or contained subtype constraint which includes values of the type,
+// written with or without INCLUDES keyword.
 type TypeConstraint struct {
 	Type Type
 }
@@ -643,13 +769,57 @@ type SizeConstraint struct {
 // IsElements implements Elements.
 func (SizeConstraint) isElements() {}
 
-// InnerTypeConstraint is WITH COMPONENT constraint.
-// Contents are not represented in parsed AST and are ignored.
-type InnerTypeConstraint struct{}
+// PermittedAlphabet is FROM constraint, which permits strings consisting of characters
+// permitted by Constraint, see X.680, section 47.7.
+type PermittedAlphabet struct {
+	Constraint Constraint
+}
+
+// IsElements implements Elements.
+func (PermittedAlphabet) isElements() {}
+
+// PatternConstraint is PATTERN constraint, which permits strings matching regular expression
+// given by character string Value, see X.680, section 47.9 and Annex A.
+type PatternConstraint struct {
+	Value Value
+}
+
+// IsElements implements Elements.
+func (PatternConstraint) isElements() {}
+
+// InnerTypeConstraint is WITH COMPONENT or WITH COMPONENTS constraint, see X.680, section 51.8.
+// WITH COMPONENT constrains elements of SEQUENCE OF and SET OF values with Component,
+// and WITH COMPONENTS constrains values and presence of components of SEQUENCE, SET and CHOICE values.
+type InnerTypeConstraint struct {
+	// Component is the constraint of elements, or nil for WITH COMPONENTS.
+	Component *Constraint
+	// Components are constraints of components listed in WITH COMPONENTS.
+	Components []NamedConstraint
+	// Partial is set for partial specification, which starts with ellipsis and does not constrain
+	// components which are not listed. With full specification, they must be absent.
+	Partial bool
+}
 
 // IsElements implements Elements.
 func (InnerTypeConstraint) isElements() {}
 
+// NamedConstraint constrains a component listed in WITH COMPONENTS.
+type NamedConstraint struct {
+	Identifier Identifier
+	// Constraint is the constraint of component value, or nil if value is not constrained.
+	Constraint *Constraint
+	// Presence is one of PRESENCE_ constants.
+	Presence int
+}
+
+// Presence constants of NamedConstraint, which are values of PresenceConstraint.
+const (
+	PRESENCE_UNSPECIFIED = iota
+	PRESENCE_PRESENT
+	PRESENCE_ABSENT
+	PRESENCE_OPTIONAL
+)
+
 // GeneralConstraint is not implemented.
 // It is defined by X.682.
 // TODO: implement or remove.
@@ -682,8 +852,16 @@ func (DefinedValue) Type() Type {
 
 func (DefinedValue) isNamedNumberValue() {}
 
+// String returns value reference in ASN.1 notation, qualified by module name if it is present.
+func (v DefinedValue) String() string {
+	if v.ModuleName != "" {
+		return string(v.ModuleName) + "." + v.ValueName.Name()
+	}
+	return v.ValueName.Name()
+}
+
 // IdentifiedIntegerValue is named value defined for the type.
-// TODO: use of these in assignments is not implemented.
+// Parser can not tell it apart from a bare value reference, so it can refer to a value assignment as well.
 type IdentifiedIntegerValue struct {
 	valueType Type
 	Name      string
@@ -719,6 +897,455 @@ type ObjectIdElement struct {
 // end OID
 //////////////////////////////
 
+//////////////////////////////
+// Strings
+
+// BitStringValue is a value of BIT STRING type.
+// Bstring and hstring literals are also valid OctetStringValue notation, and parser can not distinguish
+// between the two without knowing the governing type, so they are always parsed as BitStringValue.
+// Use OctetString to convert it when governing type is OCTET STRING.
+// Named bits form, e.g. { flagA, flagC }, is parsed as BracedValue.
+// See X.680, section 21.9.
+type BitStringValue struct {
+	// Bytes holds value bits, last octet is padded with zero bits.
+	// Set for bstring and hstring forms.
+	Bytes []byte
+	// BitLength is a number of bits in Bytes.
+	BitLength int
+	// NamedBits is set when value is specified as a list of identifiers, e.g. { flagA, flagC }.
+	NamedBits []Identifier
+}
+
+// Type implements Value.
+func (BitStringValue) Type() Type {
+	return BitStringType{}
+}
+
+// IsNamedBitList returns true if value was specified as a list of identifiers.
+func (v BitStringValue) IsNamedBitList() bool {
+	return v.NamedBits != nil
+}
+
+// OctetString converts value in bstring or hstring form to OctetStringValue.
+// Trailing bits of the last octet are filled with zeroes, as required by X.680, section 22.3.
+func (v BitStringValue) OctetString() OctetStringValue {
+	return OctetStringValue(v.Bytes)
+}
+
+// OctetStringValue is a value of OCTET STRING type.
+// Parser yields BitStringValue for bstring and hstring literals, see BitStringValue.OctetString.
+// See X.680, section 22.3.
+type OctetStringValue []byte
+
+// Type implements Value.
+func (OctetStringValue) Type() Type {
+	return OctetStringType{}
+}
+
+// CharacterStringValue is a value of restricted character string type.
+// Value is a concatenation of its parts, single cstring is represented as CharacterStringValue of one element.
+// It is defined as RestrictedCharacterStringValue in BNF.
+// See X.680, section 37.8.
+type CharacterStringValue []CharsDefn
+
+// Type implements Value.
+func (CharacterStringValue) Type() Type {
+	return CharacterStringType{}
+}
+
+// StringValue returns concatenated value of the string.
+// Returns false if some of the parts is a DefinedValue, which can't be computed without module context.
+func (v CharacterStringValue) StringValue() (string, bool) {
+	var sb strings.Builder
+	for _, part := range v {
+		switch p := part.(type) {
+		case CString:
+			sb.WriteString(string(p))
+		case Quadruple:
+			sb.WriteRune(p.Rune())
+		case Tuple:
+			sb.WriteRune(p.Rune())
+		default:
+			return "", false
+		}
+	}
+	return sb.String(), true
+}
+
+// CharsDefn is a part of CharacterStringValue.
+// It can be CString, Quadruple, Tuple, or DefinedValue.
+type CharsDefn interface {
+	isCharsDefn()
+}
+
+// CString is a character string literal.
+// This is a lexical construct, named `cstring` in the doc.
+// See X.680, section 11.14.
+type CString string
+
+func (CString) isCharsDefn() {}
+
+// Quadruple identifies a character by its position in ISO/IEC 10646.
+type Quadruple struct {
+	Group int
+	Plane int
+	Row   int
+	Cell  int
+}
+
+func (Quadruple) isCharsDefn() {}
+
+// Rune returns character identified by the Quadruple.
+func (q Quadruple) Rune() rune {
+	return rune(q.Group<<24 | q.Plane<<16 | q.Row<<8 | q.Cell)
+}
+
+// Tuple identifies a character by its position in ISO/IEC 646 table.
+type Tuple struct {
+	TableColumn int
+	TableRow    int
+}
+
+func (Tuple) isCharsDefn() {}
+
+// Rune returns character identified by the Tuple.
+func (t Tuple) Rune() rune {
+	return rune(t.TableColumn<<4 | t.TableRow)
+}
+
+func (DefinedValue) isCharsDefn() {}
+
+// end Strings
+//////////////////////////////
+
+//////////////////////////////
+// Structured values
+
+// NullValue is a value of NULL type.
+// See X.680, section 23.3.
+type NullValue struct{}
+
+// Type implements Value.
+func (NullValue) Type() Type {
+	return NullType{}
+}
+
+// SequenceValue is a value of SEQUENCE or SET type.
+// Components are listed in order of appearance in the value notation.
+// It is defined as SequenceValue and SetValue in BNF.
+// See X.680, sections 24.17 and 26.6.
+type SequenceValue []NamedValue
+
+// Type implements Value.
+func (SequenceValue) Type() Type {
+	return SequenceType{}
+}
+
+// Get returns value of the component with given name, or nil if not present.
+func (v SequenceValue) Get(name Identifier) Value {
+	for _, component := range v {
+		if component.Identifier == name {
+			return component.Value
+		}
+	}
+	return nil
+}
+
+// NamedValue is an identifier-value tuple.
+// It is used as element in SequenceValue.
+type NamedValue struct {
+	Identifier Identifier
+	Value      Value
+}
+
+// SequenceOfValue is a value of SEQUENCE OF or SET OF type.
+// Identifiers of elements in NamedValueList form are not kept.
+// It is defined as SequenceOfValue and SetOfValue in BNF.
+// See X.680, sections 25.3 and 27.3.
+type SequenceOfValue []Value
+
+// Type implements Value.
+func (SequenceOfValue) Type() Type {
+	return SequenceOfType{}
+}
+
+// ChoiceValue is a value of CHOICE type.
+// See X.680, section 28.9.
+type ChoiceValue struct {
+	// Identifier is a name of chosen alternative.
+	Identifier Identifier
+	// Value of chosen alternative.
+	Value Value
+}
+
+// Type implements Value.
+func (ChoiceValue) Type() Type {
+	return ChoiceType{}
+}
+
+// end Structured values
+//////////////////////////////
+
+//////////////////////////////
+// Braced values
+
+// BracedValue is value notation in curly braces, e.g. { a 1, b 2 }.
+// Such notation is ambiguous without knowing the governing type, e.g. { a 1 } is a value of OBJECT IDENTIFIER
+// as well as of SEQUENCE type, { a, b } is a value of BIT STRING as well as of SEQUENCE OF type, and { 1, 2 }
+// is a value of SEQUENCE OF INTEGER as well as of character string type in Tuple form.
+// So parser keeps components as they are written, and converts them when the governing type
+// is a built-in type, see BracedValue.As. Values of referenced types are converted by code generator.
+type BracedValue []BracedComponent
+
+// Type implements Value.
+func (BracedValue) Type() Type {
+	return nil
+}
+
+// BracedComponent is a comma-separated component of BracedValue, which is a list of
+// whitespace-separated values, e.g. a 1 in { a 1, b 2 }.
+type BracedComponent []Value
+
+// NameAndNumber is a component of OBJECT IDENTIFIER value in NameAndNumberForm, e.g. iso(1).
+// It is only found in BracedComponent.
+// See X.680, section 32.3.
+type NameAndNumber ObjectIdElement
+
+// Type implements Value.
+func (NameAndNumber) Type() Type {
+	return ObjectIdentifierType{}
+}
+
+// As converts value to the notation of built-in type t, which can be tagged or constrained:
+// BitStringValue for BIT STRING, ObjectIdentifierValue for OBJECT IDENTIFIER, CharacterStringValue for
+// character string types, SequenceValue for SEQUENCE and SET, and SequenceOfValue for SEQUENCE OF and SET OF.
+// Values of components and elements stay as they are written, and are converted with their own types.
+// Returns false if value is not valid notation for values of t, or if t is not one of these types.
+// See X.680, sections 21.9, 24.17, 25.3, 32.3 and 37.8.
+func (v BracedValue) As(t Type) (Value, bool) {
+	switch tt := t.(type) {
+	case TaggedType:
+		return v.As(tt.Type)
+	case ConstraintedType:
+		return v.As(tt.Type)
+	case BitStringType:
+		res := BitStringValue{NamedBits: make([]Identifier, 0, len(v))}
+		for _, c := range v {
+			name, ok := c.name()
+			if !ok || len(c) != 1 {
+				return nil, false
+			}
+			res.NamedBits = append(res.NamedBits, name)
+		}
+		return res, true
+	case ObjectIdentifierType:
+		if len(v) != 1 {
+			return nil, false
+		}
+		if oid, ok := v[0].objectIdentifier(); ok {
+			return oid, true
+		}
+		return nil, false
+	case RestrictedStringType, CharacterStringType:
+		if str, ok := v.characterString(); ok {
+			return str, true
+		}
+		return nil, false
+	case SequenceType, SetType:
+		res := make(SequenceValue, 0, len(v))
+		for _, c := range v {
+			named, ok := c.namedValue()
+			if !ok {
+				return nil, false
+			}
+			res = append(res, named)
+		}
+		return res, true
+	case SequenceOfType, SetOfType:
+		res := make(SequenceOfValue, 0, len(v))
+		for _, c := range v {
+			if named, ok := c.namedValue(); ok {
+				res = append(res, named.Value)
+			} else if _, isName := c[0].(NameAndNumber); len(c) == 1 && !isName {
+				res = append(res, c[0])
+			} else {
+				return nil, false
+			}
+		}
+		return res, true
+	default:
+		return nil, false
+	}
+}
+
+// characterString converts value in CharacterStringList, Quadruple or Tuple form.
+func (v BracedValue) characterString() (CharacterStringValue, bool) {
+	numbers := make([]int, 0, len(v))
+	for _, c := range v {
+		if n, ok := c[0].(Number); ok && len(c) == 1 {
+			numbers = append(numbers, n.IntValue())
+		}
+	}
+	switch {
+	case len(numbers) == 4 && len(v) == 4:
+		return CharacterStringValue{Quadruple{Group: numbers[0], Plane: numbers[1], Row: numbers[2], Cell: numbers[3]}}, true
+	case len(numbers) == 2 && len(v) == 2:
+		return CharacterStringValue{Tuple{TableColumn: numbers[0], TableRow: numbers[1]}}, true
+	case len(v) == 0:
+		return nil, false
+	}
+	res := make(CharacterStringValue, 0, len(v))
+	for _, c := range v {
+		if len(c) != 1 {
+			return nil, false
+		}
+		switch item := c[0].(type) {
+		case CharacterStringValue:
+			res = append(res, item...)
+		case BracedValue:
+			// only Quadruple and Tuple can be nested
+			str, ok := item.characterString()
+			if !ok || len(str) != 1 {
+				return nil, false
+			}
+			switch str[0].(type) {
+			case Quadruple, Tuple:
+				res = append(res, str[0])
+			default:
+				return nil, false
+			}
+		case DefinedValue:
+			res = append(res, item)
+		case IdentifiedIntegerValue:
+			res = append(res, DefinedValue{ValueName: ValueReference(item.Name)})
+		default:
+			return nil, false
+		}
+	}
+	return res, true
+}
+
+// name returns identifier, if component is a bare identifier, which can be a value reference or a named number.
+func (c BracedComponent) name() (Identifier, bool) {
+	switch item := c[0].(type) {
+	case IdentifiedIntegerValue:
+		return Identifier(item.Name), true
+	case DefinedValue:
+		return Identifier(item.ValueName), item.ModuleName == ""
+	default:
+		return "", false
+	}
+}
+
+// namedValue converts component in NamedValue form, e.g. a 1.
+func (c BracedComponent) namedValue() (NamedValue, bool) {
+	name, ok := c.name()
+	if !ok || len(c) != 2 {
+		return NamedValue{}, false
+	}
+	if _, isName := c[1].(NameAndNumber); isName {
+		return NamedValue{}, false
+	}
+	return NamedValue{Identifier: name, Value: c[1]}, true
+}
+
+// objectIdentifier converts component to ObjectIdentifierValue, if all of its values are valid ObjIdComponents.
+// Identifiers in NameForm are converted to references, see ObjectIdElement.
+func (c BracedComponent) objectIdentifier() (ObjectIdentifierValue, bool) {
+	res := make(ObjectIdentifierValue, 0, len(c))
+	for _, item := range c {
+		switch item := item.(type) {
+		case NameAndNumber:
+			res = append(res, ObjectIdElement(item))
+		case Number:
+			if item < 0 {
+				return nil, false
+			}
+			res = append(res, ObjectIdElement{ID: item.IntValue()})
+		case DefinedValue:
+			ref := item
+			res = append(res, ObjectIdElement{Reference: &ref})
+		case IdentifiedIntegerValue:
+			res = append(res, ObjectIdElement{Reference: &DefinedValue{ValueName: ValueReference(item.Name)}})
+		default:
+			return nil, false
+		}
+	}
+	return res, true
+}
+
+// end Braced values
+//////////////////////////////
+
+//////////////////////////////
+// XML values
+
+// XMLValue is an element of XML value notation, e.g. <Type><component>1</component></Type>.
+// Lexical items of XML value notation depend on the governing type, e.g. <a/> can be a value of BOOLEAN,
+// ENUMERATED, or named bit of BIT STRING, so parser keeps the tree of XML elements,
+// and code generator interprets it according to the type.
+// See X.680, section 16.
+type XMLValue struct {
+	// Name is a name of the element, which is either identifier, or name of the type.
+	Name string
+	// Content holds character data and child elements in order of their appearance.
+	Content []XMLContent
+}
+
+// Type implements Value.
+func (XMLValue) Type() Type {
+	return nil
+}
+
+func (XMLValue) isXMLContent() {}
+
+// Text returns character data of the element, or false if element has child elements.
+func (v XMLValue) Text() (string, bool) {
+	var sb strings.Builder
+	for _, c := range v.Content {
+		text, ok := c.(XMLText)
+		if !ok {
+			return "", false
+		}
+		sb.WriteString(string(text))
+	}
+	return sb.String(), true
+}
+
+// Elements returns child elements, or false if element has character data other than whitespace.
+func (v XMLValue) Elements() ([]XMLValue, bool) {
+	var res []XMLValue
+	for _, c := range v.Content {
+		switch c := c.(type) {
+		case XMLValue:
+			res = append(res, c)
+		case XMLText:
+			if strings.TrimSpace(string(c)) != "" {
+				return nil, false
+			}
+		}
+	}
+	return res, true
+}
+
+// IsEmpty returns true if element has no content, e.g. <true/>.
+func (v XMLValue) IsEmpty() bool {
+	return len(v.Content) == 0
+}
+
+// XMLContent is a part of XMLValue content, which is either XMLText or XMLValue.
+type XMLContent interface {
+	isXMLContent()
+}
+
+// XMLText is character data of XML element, with character and entity references replaced.
+type XMLText string
+
+func (XMLText) isXMLContent() {}
+
+// end XML values
+//////////////////////////////
+
 // Names for useful types.
 const (
 	GeneralizedTimeName = "GeneralizedTime"
@@ -728,10 +1355,12 @@ const (
 var (
 	// USEFUL_TYPES are defined in X.680, section 41.
 	// These are built-in types that behave like type assignments that are always in scope.
-	// TODO: clarify why UTCTimeName is missing here.
 	USEFUL_TYPES map[string]Type = map[string]Type{
 		GeneralizedTimeName: TaggedType{ // [UNIVERSAL 24] IMPLICIT VisibleString
 			Tag:  Tag{Class: CLASS_UNIVERSAL, ClassNumber: Number(24)},
 			Type: RestrictedStringType{VisibleString}},
+		UTCTimeName: TaggedType{ // [UNIVERSAL 23] IMPLICIT VisibleString
+			Tag:  Tag{Class: CLASS_UNIVERSAL, ClassNumber: Number(23)},
+			Type: RestrictedStringType{VisibleString}},
 	}
 )
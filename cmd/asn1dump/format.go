@@ -0,0 +1,262 @@
+package main
+
+import (
+	"bufio"
+	"encoding/asn1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/chemikadze/asn1go"
+	"github.com/chemikadze/asn1go/der"
+	"io"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// writeText writes indented tree of elements, where every line starts with offset of the element
+// and length of its contents, similar to dumpasn1.
+func writeText(w io.Writer, nodes []asn1go.Node) error {
+	bw := bufio.NewWriter(w)
+	for _, node := range nodes {
+		writeTextNode(bw, node, 0)
+	}
+	return bw.Flush()
+}
+
+func writeTextNode(w io.Writer, node asn1go.Node, depth int) {
+	indent := strings.Repeat("  ", depth)
+	var parts []string
+	if node.Name != "" {
+		parts = append(parts, node.Name)
+	}
+	if node.Tag.Class != der.ClassUniversal || node.Type == "" {
+		parts = append(parts, node.Tag.String())
+	}
+	if node.Type != "" {
+		parts = append(parts, node.Type)
+	}
+	if !isStructured(node) {
+		parts = append(parts, textValue(node))
+		fmt.Fprintf(w, "%5d %4d: %v%v\n", node.Offset, len(contents(node)), indent, strings.Join(parts, " "))
+		return
+	}
+	fmt.Fprintf(w, "%5d %4d: %v%v {\n", node.Offset, len(contents(node)), indent, strings.Join(parts, " "))
+	for _, child := range node.Children {
+		writeTextNode(w, child, depth+1)
+	}
+	fmt.Fprintf(w, "%10v: %v}\n", "", indent)
+}
+
+// textValue returns value of simple type in value notation, followed by its number if it is identified.
+func textValue(node asn1go.Node) string {
+	if isUnknown(node) {
+		return valueNotation(node) + " -- unknown extension"
+	}
+	switch v := node.Value.(type) {
+	case int64, *big.Int, asn1.Enumerated:
+		if node.Identifier != "" {
+			return fmt.Sprintf("%v (%v)", node.Identifier, v)
+		}
+	}
+	return valueNotation(node)
+}
+
+// isStructured returns true if node is a value of structured type, or an element with constructed encoding
+// which was decoded without type information.
+func isStructured(node asn1go.Node) bool {
+	return node.Value == nil && (len(node.Children) > 0 || len(node.Bytes) > 0 && node.Bytes[0]&0x20 != 0)
+}
+
+// isUnknown returns true if node is unknown extension addition, or unknown alternative of CHOICE.
+func isUnknown(node asn1go.Node) bool {
+	_, raw := node.Value.(asn1.RawValue)
+	return raw && node.Kind == ""
+}
+
+// contents returns contents octets of the encoding of node.
+func contents(node asn1go.Node) []byte {
+	var raw asn1.RawValue
+	if err := der.NewDecoder(node.Bytes).ReadRawValue(&raw); err != nil {
+		return nil
+	}
+	return raw.Bytes
+}
+
+// valueNotation returns value of simple type in ASN.1 value notation, see X.680.
+func valueNotation(node asn1go.Node) string {
+	switch v := node.Value.(type) {
+	case nil:
+		return "NULL"
+	case bool:
+		if v {
+			return "TRUE"
+		}
+		return "FALSE"
+	case int64, *big.Int, asn1.Enumerated:
+		if node.Identifier != "" {
+			return node.Identifier
+		}
+		return fmt.Sprint(v)
+	case float64:
+		switch {
+		case math.IsInf(v, 1):
+			return "PLUS-INFINITY"
+		case math.IsInf(v, -1):
+			return "MINUS-INFINITY"
+		case math.IsNaN(v):
+			return "NOT-A-NUMBER"
+		}
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case []byte:
+		return "'" + strings.ToUpper(hex.EncodeToString(v)) + "'H"
+	case asn1.BitString:
+		var bits strings.Builder
+		for i := 0; i < v.BitLength; i++ {
+			bits.WriteByte(byte('0' + v.At(i)))
+		}
+		return "'" + bits.String() + "'B"
+	case asn1.ObjectIdentifier:
+		return "{ " + strings.ReplaceAll(v.String(), ".", " ") + " }"
+	case string:
+		return quote(v)
+	case time.Time:
+		// times are written as they are encoded, unless encoding is constructed
+		if node.Bytes[0]&0x20 == 0 {
+			return quote(string(contents(node)))
+		}
+		if node.Kind == "UTCTime" {
+			return quote(v.UTC().Format("060102150405Z"))
+		}
+		return quote(v.UTC().Format("20060102150405.999999999Z"))
+	case asn1.RawValue:
+		return "'" + strings.ToUpper(hex.EncodeToString(v.FullBytes)) + "'H"
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// quote returns character string value, where quotation marks are doubled, see X.680, section 12.14.
+func quote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
+
+// jsonNode is JSON representation of asn1go.Node.
+type jsonNode struct {
+	Name       string     `json:"name,omitempty"`
+	Type       string     `json:"type,omitempty"`
+	Tag        string     `json:"tag"`
+	Offset     int        `json:"offset"`
+	Length     int        `json:"length"`
+	Value      any        `json:"value,omitempty"`
+	Identifier string     `json:"identifier,omitempty"`
+	Children   []jsonNode `json:"children,omitempty"`
+}
+
+// writeJSON writes tree of elements as JSON array of objects.
+func writeJSON(w io.Writer, nodes []asn1go.Node) error {
+	res := make([]jsonNode, 0, len(nodes))
+	for _, node := range nodes {
+		res = append(res, toJSONNode(node))
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(res)
+}
+
+func toJSONNode(node asn1go.Node) jsonNode {
+	res := jsonNode{
+		Name:       node.Name,
+		Type:       node.Type,
+		Tag:        node.Tag.String(),
+		Offset:     node.Offset,
+		Length:     len(contents(node)),
+		Identifier: node.Identifier,
+	}
+	switch v := node.Value.(type) {
+	case float64:
+		// special values are not representable as JSON numbers, and are written as in JER
+		switch {
+		case math.IsInf(v, 1):
+			res.Value = "INF"
+		case math.IsInf(v, -1):
+			res.Value = "-INF"
+		case math.IsNaN(v):
+			res.Value = "NaN"
+		default:
+			res.Value = v
+		}
+	case []byte:
+		res.Value = hex.EncodeToString(v)
+	case asn1.BitString:
+		res.Value = strings.Trim(valueNotation(node), "'B")
+	case asn1.ObjectIdentifier:
+		res.Value = v.String()
+	case time.Time:
+		res.Value = v.Format(time.RFC3339Nano)
+	case asn1.RawValue:
+		res.Value = hex.EncodeToString(v.FullBytes)
+	default:
+		res.Value = v
+	}
+	for _, child := range node.Children {
+		res.Children = append(res.Children, toJSONNode(child))
+	}
+	return res
+}
+
+// writeValues writes value assignments of the elements in ASN.1 value notation.
+// Unknown extension additions can not be represented in value notation, and are written as comments.
+func writeValues(w io.Writer, nodes []asn1go.Node) error {
+	bw := bufio.NewWriter(w)
+	for i, node := range nodes {
+		name := "value"
+		if len(nodes) > 1 {
+			name += strconv.Itoa(i + 1)
+		}
+		typeName := node.Type
+		if typeName == "" {
+			typeName = "ANY"
+		}
+		fmt.Fprintf(bw, "%v %v ::= %v\n", name, typeName, valueText(node, ""))
+	}
+	return bw.Flush()
+}
+
+// valueText returns value of node in value notation, where components of structured values are written
+// on separate lines with the indent.
+func valueText(node asn1go.Node, indent string) string {
+	if node.Kind == "CHOICE" && len(node.Children) == 1 {
+		alternative := node.Children[0]
+		if isUnknown(alternative) {
+			return "-- unknown alternative " + alternative.Tag.String() + " " + valueNotation(alternative) + "\n" + indent
+		}
+		return alternative.Name + " : " + valueText(alternative, indent)
+	}
+	if !isStructured(node) {
+		return valueNotation(node)
+	}
+	if len(node.Children) == 0 {
+		return "{}"
+	}
+	inner := indent + "  "
+	var items, unknown []string
+	for _, child := range node.Children {
+		switch {
+		case isUnknown(child):
+			unknown = append(unknown, inner+"-- unknown extension "+child.Tag.String()+" "+valueNotation(child))
+		case child.Name != "":
+			items = append(items, inner+child.Name+" "+valueText(child, inner))
+		default:
+			items = append(items, inner+valueText(child, inner))
+		}
+	}
+	lines := []string{strings.Join(items, ",\n")}
+	if len(items) == 0 {
+		lines = nil
+	}
+	lines = append(lines, unknown...)
+	return "{\n" + strings.Join(lines, "\n") + "\n" + indent + "}"
+}
@@ -0,0 +1,190 @@
+// Binary asn1dump prints BER and DER encodings, optionally annotated with ASN.1 definitions.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"github.com/chemikadze/asn1go"
+	"github.com/chemikadze/asn1go/internal/utils"
+	"io"
+	"os"
+	"strings"
+)
+
+var usage = `
+Prints the tree of elements of BER or DER encoded input.
+
+If input is omitted, it reads the encoding from stdin. Input is either binary,
+or hex dump copied from Wireshark, which is detected automatically or forced with -hex flag.
+
+If -schema and -type are specified, elements are decoded as a value of the type defined
+in the ASN.1 module, and annotated with identifiers of components and names of their types.
+Modules imported by the schema are looked up in directories provided with -I flag.
+
+Output formats are:
+  text   indented tree of elements with offsets and lengths, similar to dumpasn1
+  json   tree of elements as JSON objects
+  value  ASN.1 value notation`
+
+type flagsType struct {
+	inputName   string
+	schemaName  string
+	moduleName  string
+	typeName    string
+	includeDirs stringsFlag
+	format      string
+	hex         bool
+	strict      bool
+}
+
+// stringsFlag is a flag that can be specified several times.
+type stringsFlag []string
+
+// String implements flag.Value.
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+// Set implements flag.Value.
+func (f *stringsFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+func failWithError(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, format, args...)
+	fmt.Fprintln(os.Stderr)
+	os.Exit(1)
+}
+
+func parseFlags() (res flagsType) {
+	flag.Usage = func() {
+		o := flag.CommandLine.Output()
+		fmt.Fprintf(o, "Usage:\n  %s [options] [input]\n\n", os.Args[0])
+		flag.PrintDefaults()
+		fmt.Fprintln(o, usage)
+	}
+	flag.StringVar(&res.schemaName, "schema", "", "ASN.1 module file defining the type of the input")
+	flag.StringVar(&res.moduleName, "module", "", "name of ASN.1 module defining the type, if schema defines several modules")
+	flag.StringVar(&res.typeName, "type", "", "name of the type of the input, e.g. AS-REQ")
+	flag.Var(&res.includeDirs, "I", "directory to look up imported modules in, can be specified several times")
+	flag.StringVar(&res.format, "format", "text", "output format (text | json | value)")
+	flag.BoolVar(&res.hex, "hex", false, "read input as Wireshark hex dump")
+	flag.BoolVar(&res.strict, "strict", false, "reject encodings which are not valid DER")
+	flag.Parse()
+
+	switch flag.NArg() {
+	case 0:
+	case 1:
+		res.inputName = flag.Arg(0)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if res.format != "text" && res.format != "json" && res.format != "value" {
+		failWithError("Unknown output format %v, expected text, json or value", res.format)
+	}
+	if (res.schemaName == "") != (res.typeName == "") {
+		failWithError("Both -schema and -type should be specified to annotate the input")
+	}
+	return res
+}
+
+func readInput(inputName string, hex bool) []byte {
+	var data []byte
+	var err error
+	if len(inputName) != 0 {
+		data, err = os.ReadFile(inputName)
+	} else {
+		data, err = io.ReadAll(os.Stdin)
+	}
+	if err != nil {
+		failWithError("Failed to read input: %v", err)
+	}
+	if hex || isWiresharkHex(data) {
+		return parseWiresharkHex(data)
+	}
+	return data
+}
+
+// isWiresharkHex returns true if data looks like hex dump copied from Wireshark,
+// where every line starts with hexadecimal offset, e.g. "0000   30 81 aa".
+func isWiresharkHex(data []byte) bool {
+	text := strings.TrimSpace(string(data))
+	if !strings.HasPrefix(text, "0000 ") {
+		return false
+	}
+	for _, r := range text {
+		if !strings.ContainsRune("0123456789abcdefABCDEF \t\r\n", r) {
+			return false
+		}
+	}
+	return true
+}
+
+func parseWiresharkHex(data []byte) (res []byte) {
+	defer func() {
+		if r := recover(); r != nil {
+			failWithError("Failed to parse hex dump: %v", r)
+		}
+	}()
+	return utils.ParseWiresharkHex(string(bytes.ReplaceAll(data, []byte("\r"), nil)))
+}
+
+// decodeInput decodes elements of the input, annotating them with the type from the schema if it is specified.
+func decodeInput(flags flagsType, data []byte) []asn1go.Node {
+	if len(flags.schemaName) == 0 {
+		nodes, err := decodeElements(data, flags.strict)
+		if err != nil {
+			failWithError("Failed to decode input: %v", err)
+		}
+		return nodes
+	}
+	registry := asn1go.NewModuleRegistry(flags.includeDirs...)
+	modules, err := registry.LoadFile(flags.schemaName)
+	if err != nil {
+		failWithError("%v", err)
+	}
+	module := selectModule(modules, flags.moduleName)
+	node, err := asn1go.DecodeWithParams(module, flags.typeName, data, asn1go.DecodeParams{Registry: registry, Strict: flags.strict})
+	if err != nil {
+		failWithError("Failed to decode %v: %v", flags.typeName, err)
+	}
+	return []asn1go.Node{node}
+}
+
+func selectModule(modules []*asn1go.ModuleDefinition, moduleName string) *asn1go.ModuleDefinition {
+	names := make([]string, 0, len(modules))
+	for _, module := range modules {
+		if module.ModuleIdentifier.Reference == moduleName {
+			return module
+		}
+		names = append(names, module.ModuleIdentifier.Reference)
+	}
+	if len(moduleName) != 0 {
+		failWithError("Module %v is not defined in the schema, defined modules: %v", moduleName, strings.Join(names, ", "))
+	}
+	if len(modules) != 1 {
+		failWithError("Schema defines several modules, select one with -module flag: %v", strings.Join(names, ", "))
+	}
+	return modules[0]
+}
+
+func main() {
+	flags := parseFlags()
+	nodes := decodeInput(flags, readInput(flags.inputName, flags.hex))
+
+	var err error
+	switch flags.format {
+	case "text":
+		err = writeText(os.Stdout, nodes)
+	case "json":
+		err = writeJSON(os.Stdout, nodes)
+	case "value":
+		err = writeValues(os.Stdout, nodes)
+	}
+	if err != nil {
+		failWithError("Failed to write output: %v", err)
+	}
+}
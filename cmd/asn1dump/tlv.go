@@ -0,0 +1,145 @@
+package main
+
+import (
+	"encoding/asn1"
+	"fmt"
+	"github.com/chemikadze/asn1go"
+	"github.com/chemikadze/asn1go/der"
+	"math/big"
+)
+
+// universalTypeNames are names of built-in types by numbers of their UNIVERSAL tags, see X.680, section 8.6.
+var universalTypeNames = map[int]string{
+	1:  "BOOLEAN",
+	2:  "INTEGER",
+	3:  "BIT STRING",
+	4:  "OCTET STRING",
+	5:  "NULL",
+	6:  "OBJECT IDENTIFIER",
+	7:  "ObjectDescriptor",
+	8:  "EXTERNAL",
+	9:  "REAL",
+	10: "ENUMERATED",
+	11: "EMBEDDED PDV",
+	12: "UTF8String",
+	13: "RELATIVE-OID",
+	16: "SEQUENCE",
+	17: "SET",
+	18: "NumericString",
+	19: "PrintableString",
+	20: "TeletexString",
+	21: "VideotexString",
+	22: "IA5String",
+	23: "UTCTime",
+	24: "GeneralizedTime",
+	25: "GraphicString",
+	26: "VisibleString",
+	27: "GeneralString",
+	28: "UniversalString",
+	29: "CHARACTER STRING",
+	30: "BMPString",
+}
+
+// decodeElements decodes elements of data without type information. Elements with constructed encoding
+// are decoded recursively, and values of primitive elements with UNIVERSAL tags are decoded according to their tags.
+func decodeElements(data []byte, strict bool) ([]asn1go.Node, error) {
+	return (&tlvDecoder{data: data, strict: strict}).decode(data)
+}
+
+// tlvDecoder decodes elements without type information.
+type tlvDecoder struct {
+	// data is the whole decoded data, which is used to find offsets of elements.
+	data   []byte
+	strict bool
+}
+
+// decode decodes all elements of contents.
+func (td *tlvDecoder) decode(contents []byte) ([]asn1go.Node, error) {
+	d := der.NewDecoder(contents)
+	d.Strict = td.strict
+	var res []asn1go.Node
+	for d.More() {
+		var raw asn1.RawValue
+		if err := d.ReadRawValue(&raw); err != nil {
+			return nil, fmt.Errorf("offset %v: %w", cap(td.data)-cap(d.Rest()), err)
+		}
+		node := asn1go.Node{
+			Tag:    der.Tag{Class: der.Class(raw.Class), Number: raw.Tag},
+			Offset: cap(td.data) - cap(raw.FullBytes),
+			Bytes:  raw.FullBytes,
+		}
+		if node.Tag.Class == der.ClassUniversal {
+			node.Type = universalTypeNames[node.Tag.Number]
+			node.Kind = node.Type
+		}
+		var err error
+		switch {
+		case raw.IsCompound:
+			node.Children, err = td.decode(raw.Bytes)
+		case node.Tag.Class == der.ClassUniversal:
+			node.Value, err = td.decodeValue(node.Tag, raw.FullBytes)
+			if err != nil {
+				err = fmt.Errorf("offset %v: %w", node.Offset, err)
+			}
+		default:
+			node.Value = raw.Bytes
+		}
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, node)
+	}
+	return res, nil
+}
+
+// decodeValue decodes primitive element with UNIVERSAL tag, see asn1go.Node for types of values.
+// Contents of elements of unknown types are returned as []byte.
+func (td *tlvDecoder) decodeValue(tag der.Tag, data []byte) (any, error) {
+	d := der.NewDecoder(data)
+	d.Strict = td.strict
+	switch tag {
+	case der.TagBoolean:
+		return read(d.ReadBoolean, tag)
+	case der.TagInteger:
+		var v *big.Int
+		if err := d.ReadBigInteger(tag, &v); err != nil {
+			return nil, err
+		}
+		if v.IsInt64() {
+			return v.Int64(), nil
+		}
+		return v, nil
+	case der.TagBitString:
+		return read(d.ReadBitString, tag)
+	case der.TagNull:
+		return nil, d.ReadNull(tag)
+	case der.TagObjectIdentifier:
+		return read(d.ReadObjectIdentifier, tag)
+	case der.TagReal:
+		return read(d.ReadReal, tag)
+	case der.TagEnumerated:
+		return read(d.ReadEnumerated, tag)
+	case der.TagUTF8String, der.TagNumericString, der.TagPrintableString, der.TagTeletexString, der.TagVideotexString,
+		der.TagIA5String, der.TagGraphicString, der.TagVisibleString, der.TagGeneralString:
+		return read(d.ReadString, tag)
+	case der.TagUniversalString:
+		return read(d.ReadUniversalString, tag)
+	case der.TagBMPString:
+		return read(d.ReadBMPString, tag)
+	case der.TagUTCTime:
+		return read(d.ReadUTCTime, tag)
+	case der.TagGeneralizedTime:
+		return read(d.ReadGeneralizedTime, tag)
+	default:
+		return read(d.ReadOctetString, tag)
+	}
+}
+
+// read reads value with method of der.Decoder.
+func read[T any](method func(der.Tag, *T) error, tag der.Tag) (any, error) {
+	var v T
+	if err := method(tag, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
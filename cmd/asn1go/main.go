@@ -6,19 +6,56 @@ import (
 	"fmt"
 	"github.com/chemikadze/asn1go"
 	"os"
+	"path/filepath"
+	"strings"
 )
 
 var usage = `
 Generates a Go file representing the ASN.1 input, which should be an ASN.1 module file.
 
 If output is omitted, it writes Go code to stdout. 
-If input is omitted as well, it reads the ASN.1 module from stdin.`
+If input is omitted as well, it reads the ASN.1 module from stdin.
+If input defines several modules, module to generate should be selected with -module flag.
+
+Modules imported by the generated module are looked up in the input and in directories
+provided with -I flag, and their declarations are generated into the same output.
+
+If -import-path is specified, every module defined in the input, and every module imported
+by them, is generated into its own Go package, and output should be a directory. Packages
+are placed into subdirectories of the output named after modules, and are imported
+by each other using provided import path of the output directory.`
 
 type flagsType struct {
 	inputName      string
 	outputName     string
 	packageName    string
+	moduleName     string
 	defaultIntRepr string
+	choiceRepr     string
+	enumRepr       string
+	bitStringRepr  string
+	includeDirs    stringsFlag
+	importPath     string
+	der            bool
+	per            bool
+	oer            bool
+	jer            bool
+	xer            bool
+	validate       bool
+}
+
+// stringsFlag is a flag that can be specified several times.
+type stringsFlag []string
+
+// String implements flag.Value.
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+// Set implements flag.Value.
+func (f *stringsFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
 }
 
 func failWithError(format string, args ...any) {
@@ -35,7 +72,19 @@ func parseFlags() (res flagsType) {
 		fmt.Fprintln(o, usage)
 	}
 	flag.StringVar(&res.packageName, "package", "", "package name for generated code")
-	flag.StringVar(&res.defaultIntRepr, "default-integer-repr", "int64", "Go type for integer types (int64 | big.Int)")
+	flag.StringVar(&res.moduleName, "module", "", "name of ASN.1 module to generate, if input defines several modules")
+	flag.Var(&res.includeDirs, "I", "directory to look up imported modules in, can be specified several times")
+	flag.StringVar(&res.importPath, "import-path", "", "Go import path of the output directory, enables generation of Go package per module")
+	flag.StringVar(&res.defaultIntRepr, "default-integer-repr", "int64", "Go type for integer types (int64 | big.Int | auto)")
+	flag.StringVar(&res.choiceRepr, "choice-repr", "", "Go representation of CHOICE types (interface | raw), interface if encoding methods are generated and raw otherwise, as encoding/asn1 can only decode CHOICE into asn1.RawValue")
+	flag.StringVar(&res.enumRepr, "enum-repr", "", "Go representation of ENUMERATED types (type | alias), type if encoding methods are generated and alias otherwise, as encoding/asn1 encodes named types as INTEGER")
+	flag.StringVar(&res.bitStringRepr, "bit-string-repr", "", "Go representation of BIT STRING types with named bits (type | alias), type if encoding methods are generated and alias otherwise, as encoding/asn1 can not encode named types")
+	flag.BoolVar(&res.der, "der", false, "generate MarshalDER and UnmarshalBER methods encoding and decoding values without reflection")
+	flag.BoolVar(&res.per, "per", false, "generate MarshalPER and UnmarshalPER methods encoding and decoding values with ALIGNED or UNALIGNED PER")
+	flag.BoolVar(&res.oer, "oer", false, "generate MarshalOER and UnmarshalOER methods encoding and decoding values with canonical or BASIC OER")
+	flag.BoolVar(&res.jer, "jer", false, "generate MarshalJSON and UnmarshalJSON methods encoding and decoding values with JER")
+	flag.BoolVar(&res.xer, "xer", false, "generate MarshalXER and UnmarshalXER methods encoding values with CXER and decoding them from BASIC or canonical XER")
+	flag.BoolVar(&res.validate, "validate", false, "generate Validate methods checking values against subtype constraints")
 	flag.Parse()
 
 	switch flag.NArg() {
@@ -53,46 +102,121 @@ func parseFlags() (res flagsType) {
 	return res
 }
 
-func openFiles(inputName, outputName string) (input, output *os.File) {
+func openOutput(outputName string) (output *os.File) {
 	var err error
-	input = os.Stdin
 	output = os.Stdout
 
-	if len(inputName) != 0 {
-		input, err = os.Open(inputName)
+	if len(outputName) != 0 {
+		output, err = os.OpenFile(outputName, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
 		if err != nil {
-			failWithError("Can't open %s for reading: %v", inputName, err)
+			failWithError("File %v can not be written: %v", outputName, err)
 		}
 	}
 
-	if len(outputName) != 0 {
-		output, err = os.OpenFile(outputName, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	return output
+}
+
+// loadModules parses modules from the input and registers them in the registry.
+func loadModules(registry *asn1go.ModuleRegistry, inputName string) []*asn1go.ModuleDefinition {
+	if len(inputName) != 0 {
+		modules, err := registry.LoadFile(inputName)
 		if err != nil {
-			failWithError("File %v can not be written: %v", inputName, err)
+			failWithError("%v", err)
 		}
+		return modules
+	}
+	modules, err := asn1go.ParseModules(os.Stdin)
+	if err != nil {
+		failWithError("%v", err)
+	}
+	if err := registry.Add(modules...); err != nil {
+		failWithError("%v", err)
 	}
+	return modules
+}
 
-	return input, output
+func selectModule(modules []*asn1go.ModuleDefinition, moduleName string) *asn1go.ModuleDefinition {
+	names := make([]string, 0, len(modules))
+	for _, module := range modules {
+		if module.ModuleIdentifier.Reference == moduleName {
+			return module
+		}
+		names = append(names, module.ModuleIdentifier.Reference)
+	}
+	if len(moduleName) != 0 {
+		failWithError("Module %v is not defined in the input, defined modules: %v", moduleName, strings.Join(names, ", "))
+	}
+	if len(modules) != 1 {
+		failWithError("Input defines several modules, select one with -module flag: %v", strings.Join(names, ", "))
+	}
+	return modules[0]
+}
+
+// writePackages writes generated packages into the output directory.
+func writePackages(outputDir string, files map[string][]byte) {
+	if len(outputDir) == 0 {
+		failWithError("Output directory is required when -import-path is specified")
+	}
+	for name, content := range files {
+		path := filepath.Join(outputDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			failWithError("Directory %v can not be created: %v", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, content, 0644); err != nil {
+			failWithError("File %v can not be written: %v", path, err)
+		}
+	}
 }
 
 func main() {
 	flags := parseFlags()
 
-	input, output := openFiles(flags.inputName, flags.outputName)
-	defer output.Close()
-	defer input.Close()
+	registry := asn1go.NewModuleRegistry(flags.includeDirs...)
+	modules := loadModules(registry, flags.inputName)
 
-	module, err := asn1go.ParseStream(input)
-	if err != nil {
-		failWithError("%v", err)
+	params := asn1go.GenParams{
+		Package:       flags.packageName,
+		IntegerRepr:   asn1go.IntegerRepr(flags.defaultIntRepr),
+		ChoiceRepr:    asn1go.ChoiceRepr(flags.choiceRepr),
+		EnumRepr:      asn1go.EnumRepr(flags.enumRepr),
+		BitStringRepr: asn1go.BitStringRepr(flags.bitStringRepr),
+		Registry:      registry,
+	}
+	if flags.der {
+		params.Type |= asn1go.GEN_DER
+	}
+	if flags.per {
+		params.Type |= asn1go.GEN_PER
+	}
+	if flags.oer {
+		params.Type |= asn1go.GEN_OER
+	}
+	if flags.jer {
+		params.Type |= asn1go.GEN_JER
+	}
+	if flags.xer {
+		params.Type |= asn1go.GEN_XER
+	}
+	if flags.validate {
+		params.Type |= asn1go.GEN_VALIDATE
+	}
+	if len(flags.importPath) != 0 {
+		params.ImportPath = flags.importPath
+		files, err := asn1go.NewCodeGenerator(params).GeneratePackages(modules)
+		if err != nil {
+			failWithError("%v", err)
+		}
+		writePackages(flags.outputName, files)
 		return
 	}
 
-	params := asn1go.GenParams{
-		Package:     flags.packageName,
-		IntegerRepr: asn1go.IntegerRepr(flags.defaultIntRepr),
-	}
-	err = asn1go.NewCodeGenerator(params).Generate(*module, output)
+	module := selectModule(modules, flags.moduleName)
+
+	output := openOutput(flags.outputName)
+	defer output.Close()
+
+	params.IncludeImports = true
+	err := asn1go.NewCodeGenerator(params).Generate(*module, output)
 	if err != nil {
 		failWithError("%v", err)
 	}
@@ -1,18 +1,28 @@
 package asn1go
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	goast "go/ast"
 	goprint "go/printer"
 	gotoken "go/token"
 	"io"
+	"math"
+	"math/big"
+	"slices"
+	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 // CodeGenerator is an interface for code generation from ASN.1 modules.
 type CodeGenerator interface {
 	Generate(module ModuleDefinition, writer io.Writer) error
+	// GeneratePackages generates go package for every module and for every module imported by them.
+	// Returns file contents keyed by file path relative to the root of generated package tree.
+	GeneratePackages(modules []*ModuleDefinition) (map[string][]byte, error)
 }
 
 // GenParams is code generator configuration.
@@ -25,16 +35,66 @@ type GenParams struct {
 	Type GenType
 	// IntegerRepr controls how INTEGER type is expressed in generated go code.
 	IntegerRepr IntegerRepr
+	// ChoiceRepr controls how CHOICE type is expressed in generated go code.
+	// If not specified, ChoiceReprInterface is used when encoding methods are generated,
+	// and ChoiceReprRaw, which is compatible with encoding/asn1, otherwise.
+	ChoiceRepr ChoiceRepr
+	// EnumRepr controls how ENUMERATED type is expressed in generated go code.
+	// If not specified, EnumReprType is used when encoding methods are generated,
+	// and EnumReprAlias, which is compatible with encoding/asn1, otherwise.
+	EnumRepr EnumRepr
+	// BitStringRepr controls how BIT STRING type with named bits is expressed in generated go code.
+	// If not specified, BitStringReprType is used when encoding methods are generated,
+	// and BitStringReprAlias, which is compatible with encoding/asn1, otherwise.
+	BitStringRepr BitStringRepr
+	// Registry is used to resolve references to types and values imported from other modules.
+	// If not specified, imported references can not be resolved.
+	Registry *ModuleRegistry
+	// IncludeImports enables generation of declarations from modules imported by the generated module,
+	// directly or transitively, so that generated go package is self-contained. Requires Registry.
+	IncludeImports bool
+	// ImportPath is a go import path of the directory where packages of imported modules are generated,
+	// e.g. example.com/proto/asn1. If specified, every module is expected to be generated into its own go package
+	// ImportPath/<ModulePackageName>, and types imported from other modules are referenced as qualified identifiers.
+	// Can not be used together with IncludeImports.
+	ImportPath string
 }
 
-// GenType is code generator type.
+// GenType is code generator type. Types generating encoding methods are flags, which can be combined,
+// e.g. GEN_DER | GEN_PER.
 type GenType int
 
 const (
 	// GEN_DECLARATIONS is code generator that is
-	GEN_DECLARATIONS GenType = iota
+	GEN_DECLARATIONS GenType = 0
+	// GEN_DER is code generator that emits declarations together with MarshalDER and UnmarshalBER methods,
+	// which encode values with DER and decode them from BER or DER without reflection,
+	// using github.com/chemikadze/asn1go/der package.
+	GEN_DER GenType = 1 << (iota - 1)
+	// GEN_PER is code generator that emits declarations together with MarshalPER and UnmarshalPER methods,
+	// which encode and decode values with ALIGNED or UNALIGNED variant of PER honouring PER-visible constraints,
+	// using github.com/chemikadze/asn1go/per package.
+	GEN_PER
+	// GEN_OER is code generator that emits declarations together with MarshalOER and UnmarshalOER methods,
+	// which encode values with canonical OER, and decode them from BASIC-OER or canonical OER,
+	// using github.com/chemikadze/asn1go/oer package.
+	GEN_OER
+	// GEN_JER is code generator that emits declarations together with MarshalJSON and UnmarshalJSON methods,
+	// which encode and decode values with JER, using github.com/chemikadze/asn1go/jer package.
+	GEN_JER
+	// GEN_XER is code generator that emits declarations together with MarshalXER and UnmarshalXER methods,
+	// which encode values with CXER, and decode them from BASIC-XER or CXER,
+	// using github.com/chemikadze/asn1go/xer package.
+	GEN_XER
+	// GEN_VALIDATE is code generator that emits declarations together with Validate methods,
+	// which check values against subtype constraints of their types.
+	GEN_VALIDATE
 )
 
+// genEncoders is a set of code generator types emitting encoding methods,
+// so that generated types are not required to be compatible with encoding/asn1.
+const genEncoders = GEN_DER | GEN_PER | GEN_OER | GEN_JER | GEN_XER
+
 // IntegerRepr is enum controlling how INTEGER is represented.
 type IntegerRepr string
 
@@ -42,6 +102,9 @@ type IntegerRepr string
 const (
 	IntegerReprInt64  IntegerRepr = "int64"
 	IntegerReprBigInt IntegerRepr = "big.Int"
+	// IntegerReprAuto represents constrained INTEGER types with the narrowest go integer type fitting their values,
+	// e.g. uint8 for INTEGER (0..255), and types which are not bounded with big.Int.
+	IntegerReprAuto IntegerRepr = "auto"
 )
 
 // NewCodeGenerator creates a new code generator from provided params.
@@ -49,12 +112,28 @@ func NewCodeGenerator(params GenParams) CodeGenerator {
 	if params.IntegerRepr == "" {
 		params.IntegerRepr = IntegerReprInt64
 	}
-	switch params.Type {
-	case GEN_DECLARATIONS:
-		return &declCodeGen{params}
-	default:
+	if params.ChoiceRepr == "" {
+		params.ChoiceRepr = ChoiceReprRaw
+		if params.Type&genEncoders != 0 {
+			params.ChoiceRepr = ChoiceReprInterface
+		}
+	}
+	if params.EnumRepr == "" {
+		params.EnumRepr = EnumReprAlias
+		if params.Type&genEncoders != 0 {
+			params.EnumRepr = EnumReprType
+		}
+	}
+	if params.BitStringRepr == "" {
+		params.BitStringRepr = BitStringReprAlias
+		if params.Type&genEncoders != 0 {
+			params.BitStringRepr = BitStringReprType
+		}
+	}
+	if params.Type&^(GEN_DER|GEN_PER|GEN_OER|GEN_JER|GEN_XER|GEN_VALIDATE) != 0 {
 		return nil
 	}
+	return &declCodeGen{params}
 }
 
 type declCodeGen struct {
@@ -63,63 +142,184 @@ type declCodeGen struct {
 
 // moduleContext is context used to track state of the code generation.
 type moduleContext struct {
+	// moduleName is a name of the module being generated.
+	moduleName           ModuleReference
 	extensibilityImplied bool
 	// tagDefault is a ModuleDefinition.TagDefault value.
 	tagDefault int
 	// errors collected during conversion.
 	// TODO: switch to explicit error passing.
-	errors        []error
+	errors []error
+	// lookupContext is a body of the module, with automatic tagging applied if module has AUTOMATIC tag default,
+	// and inline types hoisted to type assignments if they are represented as named go types, see hoistInlineTypes.
 	lookupContext ModuleBody
 	// requiredModules holds go modules required by generated code.
 	requiredModules []string
 	params          GenParams
+	// parent is set for contexts of imported modules.
+	// Errors and required go modules are collected in the parent context.
+	parent *moduleContext
+	// imported holds contexts of imported modules, to avoid transforming same module many times.
+	// It is set only in the root context.
+	imported map[ModuleReference]*moduleContext
+}
+
+func newModuleContext(module ModuleDefinition, params GenParams) *moduleContext {
+	body := module.ModuleBody
+	if module.TagDefault == TAGS_AUTOMATIC {
+		body = applyAutomaticTagging(body)
+	}
+	body = hoistInlineTypes(body, params)
+	return &moduleContext{
+		moduleName:           ModuleReference(module.ModuleIdentifier.Reference),
+		extensibilityImplied: module.ExtensibilityImplied,
+		tagDefault:           module.TagDefault,
+		lookupContext:        body,
+		params:               params,
+		imported:             make(map[ModuleReference]*moduleContext),
+	}
+}
+
+// root returns context of the module being generated.
+func (ctx *moduleContext) root() *moduleContext {
+	if ctx.parent != nil {
+		return ctx.parent
+	}
+	return ctx
 }
 
 func (ctx *moduleContext) appendError(err error) {
-	ctx.errors = append(ctx.errors, err)
+	root := ctx.root()
+	for _, existing := range root.errors {
+		if existing.Error() == err.Error() {
+			// same reference can be resolved many times
+			return
+		}
+	}
+	root.errors = append(root.errors, err)
 }
 
 func (ctx *moduleContext) requireModule(module string) {
-	for _, existing := range ctx.requiredModules {
+	root := ctx.root()
+	for _, existing := range root.requiredModules {
 		if existing == module {
 			return
 		}
 	}
-	ctx.requiredModules = append(ctx.requiredModules, module)
+	root.requiredModules = append(root.requiredModules, module)
 }
 
 // Generate declarations from module to be used together with encoding/asn1.
 //
 // Feature support status:
 // - [x] ModuleIdentifier
-// - [x] TagDefault (except AUTOMATIC)
+// - [x] TagDefault
 // - [ ] ExtensibilityImplied
 // - [.] ModuleBody -- see moduleContext.generateDeclarations.
 func (gen declCodeGen) Generate(module ModuleDefinition, writer io.Writer) error {
-	if module.TagDefault == TAGS_AUTOMATIC {
-		// See x.680, section 12.3. It implies certain transformations to component and alternative lists that are not implemented.
-		return errors.New("AUTOMATIC tagged modules are not supported")
+	if gen.Params.IncludeImports && gen.Params.ImportPath != "" {
+		return errors.New("ImportPath can not be used together with IncludeImports")
 	}
-	ctx := moduleContext{
-		extensibilityImplied: module.ExtensibilityImplied,
-		tagDefault:           module.TagDefault,
-		lookupContext:        module.ModuleBody,
-		params:               gen.Params,
+	ast, _, err := gen.generateFile(module, gen.Params.Package)
+	if err != nil {
+		return err
+	}
+	return goprint.Fprint(writer, gotoken.NewFileSet(), ast)
+}
+
+// GeneratePackages generates go package for every module and for every module imported by them, directly or transitively.
+// Package of the module is placed into directory named after ModulePackageName, and references to types
+// from other modules are qualified with GenParams.ImportPath. Requires Registry and ImportPath.
+// Returns error if modules would be generated into the same package, or if generated packages import each other.
+func (gen declCodeGen) GeneratePackages(modules []*ModuleDefinition) (map[string][]byte, error) {
+	if gen.Params.Registry == nil {
+		return nil, errors.New("Registry is required to generate packages")
+	}
+	if gen.Params.ImportPath == "" {
+		return nil, errors.New("ImportPath is required to generate packages")
+	}
+	if gen.Params.IncludeImports {
+		return nil, errors.New("ImportPath can not be used together with IncludeImports")
+	}
+	var all []*ModuleDefinition
+	for _, module := range modules {
+		dependencies, err := gen.Params.Registry.Dependencies(module)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range append([]*ModuleDefinition{module}, dependencies...) {
+			if !slices.ContainsFunc(all, func(existing *ModuleDefinition) bool {
+				return existing.ModuleIdentifier.Reference == m.ModuleIdentifier.Reference
+			}) {
+				all = append(all, m)
+			}
+		}
+	}
+	res := make(map[string][]byte)
+	packageModules := make(map[string]string)
+	packageImports := make(map[string][]string)
+	for _, module := range all {
+		pkg := ModulePackageName(module.ModuleIdentifier.Reference)
+		if other, ok := packageModules[pkg]; ok {
+			return nil, fmt.Errorf("modules %v and %v are both generated into package %v", other, module.ModuleIdentifier.Reference, pkg)
+		}
+		packageModules[pkg] = module.ModuleIdentifier.Reference
+		ast, ctx, err := gen.generateFile(*module, pkg)
+		if err != nil {
+			return nil, fmt.Errorf("module %v: %w", module.ModuleIdentifier.Reference, err)
+		}
+		for _, required := range ctx.requiredModules {
+			if imported, ok := strings.CutPrefix(required, gen.Params.ImportPath+"/"); ok {
+				packageImports[pkg] = append(packageImports[pkg], imported)
+			}
+		}
+		var buf bytes.Buffer
+		if err := goprint.Fprint(&buf, gotoken.NewFileSet(), ast); err != nil {
+			return nil, err
+		}
+		res[pkg+"/"+pkg+".go"] = buf.Bytes()
+	}
+	if cycle := findImportCycle(packageImports); cycle != nil {
+		return nil, fmt.Errorf("generated packages form import cycle %v", strings.Join(cycle, " -> "))
+	}
+	return res, nil
+}
+
+// generateFile generates go file with declarations from the module.
+// Returns context used for generation, which holds go modules required by the file.
+func (gen declCodeGen) generateFile(module ModuleDefinition, packageName string) (*goast.File, *moduleContext, error) {
+	modules := []*ModuleDefinition{&module}
+	if gen.Params.IncludeImports {
+		if gen.Params.Registry == nil {
+			return nil, nil, errors.New("Registry is required to include imported modules")
+		}
+		dependencies, err := gen.Params.Registry.Dependencies(&module)
+		if err != nil {
+			return nil, nil, err
+		}
+		modules = append(modules, dependencies...)
 	}
+	ctx := newModuleContext(module, gen.Params)
 	moduleName := goast.NewIdent(goifyName(module.ModuleIdentifier.Reference))
-	if len(gen.Params.Package) > 0 {
-		moduleName = goast.NewIdent(gen.Params.Package)
+	if gen.Params.ImportPath != "" {
+		moduleName = goast.NewIdent(ModulePackageName(module.ModuleIdentifier.Reference))
+	}
+	if len(packageName) > 0 {
+		moduleName = goast.NewIdent(packageName)
 	}
 	ast := &goast.File{
 		Name:  moduleName,
-		Decls: ctx.generateDeclarations(module),
+		Decls: ctx.generateDeclarations(),
+	}
+	for _, dependency := range modules[1:] {
+		ast.Decls = append(ast.Decls, ctx.contextFor(dependency).generateDeclarations()...)
 	}
 	if len(ctx.errors) != 0 {
 		msg := "errors generating Go AST from module: \n"
 		for _, err := range ctx.errors {
 			msg += "  " + err.Error() + "\n"
 		}
-		return errors.New(msg)
+		return nil, nil, errors.New(msg)
 	}
 	importDecls := make([]goast.Decl, 0)
 	for _, moduleName := range ctx.requiredModules {
@@ -128,30 +328,120 @@ func (gen declCodeGen) Generate(module ModuleDefinition, writer io.Writer) error
 		importDecls = append(importDecls, &goast.GenDecl{Tok: gotoken.IMPORT, Specs: specs})
 	}
 	ast.Decls = append(importDecls, ast.Decls...)
-	return goprint.Fprint(writer, gotoken.NewFileSet(), ast)
+	return ast, ctx, nil
+}
+
+// findImportCycle returns packages forming import cycle, or nil if imports have no cycles.
+func findImportCycle(imports map[string][]string) []string {
+	packages := make([]string, 0, len(imports))
+	for pkg := range imports {
+		packages = append(packages, pkg)
+	}
+	slices.Sort(packages)
+	done := make(map[string]bool)
+	var visit func(path []string) []string
+	visit = func(path []string) []string {
+		pkg := path[len(path)-1]
+		if i := slices.Index(path[:len(path)-1], pkg); i >= 0 {
+			return path[i:]
+		}
+		if done[pkg] {
+			return nil
+		}
+		for _, imported := range imports[pkg] {
+			if cycle := visit(append(slices.Clip(path), imported)); cycle != nil {
+				return cycle
+			}
+		}
+		done[pkg] = true
+		return nil
+	}
+	for _, pkg := range packages {
+		if cycle := visit([]string{pkg}); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
+
+// ModulePackageName returns name of go package generated for ASN.1 module when GenParams.ImportPath is used,
+// e.g. pkix1explicit88 for PKIX1Explicit88.
+func ModulePackageName(moduleName string) string {
+	return strings.ToLower(strings.ReplaceAll(moduleName, "-", ""))
+}
+
+// qualifiedTypeIdent returns identifier of the type generated for referenced type assignment.
+// If type is defined in another module and packages are generated per module, identifier is qualified with
+// package name of that module.
+func (ctx *moduleContext) qualifiedTypeIdent(reference TypeReference) goast.Expr {
+	ident := goast.NewIdent(goifyName(reference.Name()))
+	if ctx.params.ImportPath == "" {
+		return ident
+	}
+	assignment, assignmentCtx, err := ctx.lookupTypeAssignment(reference)
+	if err != nil || assignment == nil || assignmentCtx.moduleName == ctx.root().moduleName {
+		return ident
+	}
+	return assignmentCtx.qualifiedIdent(ident)
+}
+
+// qualifiedIdent qualifies ident with the package name of the module, if module is not the one being generated.
+// Should be used only when packages are generated per module.
+func (ctx *moduleContext) qualifiedIdent(ident *goast.Ident) goast.Expr {
+	if ctx.moduleName == ctx.root().moduleName {
+		return ident
+	}
+	pkg := ModulePackageName(string(ctx.moduleName))
+	ctx.requireModule(ctx.params.ImportPath + "/" + pkg)
+	return &goast.SelectorExpr{X: goast.NewIdent(pkg), Sel: ident}
 }
 
 func goifyName(name string) string {
 	return strings.Title(strings.Replace(name, "-", "_", -1))
 }
 
-// generateDeclarations produces go declarations based on ModuleBody of module.
+// generateDeclarations produces go declarations based on ModuleBody of the module.
 //
 // Feature support status:
 // - [.] AssignmentList
 //   - [ ] ValueAssignment
 //   - [x] TypeAssignment
 //
-// - [ ] Imports
-func (ctx *moduleContext) generateDeclarations(module ModuleDefinition) []goast.Decl {
+// - [x] Imports -- imported references are resolved using GenParams.Registry
+func (ctx *moduleContext) generateDeclarations() []goast.Decl {
 	decls := make([]goast.Decl, 0)
-	for _, assignment := range module.ModuleBody.AssignmentList {
+	for _, assignment := range ctx.lookupContext.AssignmentList {
 		switch a := assignment.(type) {
 		case TypeAssignment:
-			decls = append(decls, ctx.generateTypeDecl(a.TypeReference, a.Type))
+			if choice, ok := ctx.removeWrapperTypes(a.Type).(ChoiceType); ok && ctx.params.ChoiceRepr == ChoiceReprInterface {
+				decls = append(decls, ctx.generateChoiceDecls(a.TypeReference, choice)...)
+				continue
+			}
+			decl := ctx.generateTypeDecl(a.TypeReference, a.Type)
+			decls = append(decls, decl)
+			if ctx.params.Type&GEN_DER != 0 {
+				decls = append(decls, ctx.generateDERDecls(a, decl)...)
+			}
+			if ctx.params.Type&GEN_PER != 0 {
+				decls = append(decls, ctx.generatePERDecls(a, decl)...)
+			}
+			if ctx.params.Type&GEN_OER != 0 {
+				decls = append(decls, ctx.generateOERDecls(a, decl)...)
+			}
+			if ctx.params.Type&GEN_JER != 0 {
+				decls = append(decls, ctx.generateJERDecls(a, decl)...)
+			}
+			if ctx.params.Type&GEN_XER != 0 {
+				decls = append(decls, ctx.generateXERDecls(a, decl)...)
+			}
+			if ctx.params.Type&GEN_VALIDATE != 0 {
+				decls = append(decls, ctx.generateValidateDecls(a, decl)...)
+			}
 			if decl := ctx.generateAssociatedValuesIfNeeded(a.TypeReference, a.Type); decl != nil {
 				decls = append(decls, decl)
 			}
+			decls = append(decls, ctx.generateEnumerationDecls(a.TypeReference, a.Type)...)
+			decls = append(decls, ctx.generateNamedBitsDecls(a.TypeReference, a.Type)...)
 		case ValueAssignment:
 			if decl := ctx.tryGenerateValueAssignment(a.ValueReference, a.Type, a.Value); decl != nil {
 				decls = append(decls, decl)
@@ -173,7 +463,7 @@ func (ctx *moduleContext) generateTypeDecl(reference TypeReference, typeDescr Ty
 		Tok:   gotoken.TYPE,
 		Specs: []goast.Spec{spec},
 	}
-	if _, ok := typeBody.(*goast.StructType); ok {
+	if _, ok := typeBody.(*goast.StructType); ok || ctx.isNamedEnumeration(typeDescr) || ctx.isNamedBitString(typeDescr) {
 		spec.Assign = 0
 	}
 	if isSet {
@@ -192,19 +482,8 @@ func (ctx *moduleContext) generateTypeDecl(reference TypeReference, typeDescr Ty
 
 func (ctx *moduleContext) tryGenerateValueAssignment(ref ValueReference, t Type, val Value) goast.Decl {
 	stubIsSet := false
-	var valExpr goast.Expr
-	switch val := val.(type) {
-	case Number:
-		valExpr = numberToExpr(val, ctx.params.IntegerRepr)
-	case Boolean:
-		if val {
-			valExpr = &goast.BasicLit{Value: "true"}
-		} else {
-			valExpr = &goast.BasicLit{Value: "false"}
-		}
-	case Real:
-		valExpr = &goast.BasicLit{Value: fmt.Sprint(val)}
-	default:
+	valExpr := ctx.valueToExpr(ref.Name(), ctx, t, val)
+	if valExpr == nil {
 		// TODO: produce a warning?
 		return nil
 	}
@@ -220,22 +499,507 @@ func (ctx *moduleContext) tryGenerateValueAssignment(ref ValueReference, t Type,
 	}
 }
 
+// valueToExpr converts val to go expression of the type generated for t.
+// Path is a name of the value used in error messages, e.g. myValue.field[1].
+// References in val are resolved in ctx, and references in t are resolved in typeCtx,
+// as value can be of the type defined in another module.
+// Returns nil if value can not be converted, conversion errors are appended to ctx.
+// Values that are not supported yet are silently ignored.
+func (ctx *moduleContext) valueToExpr(path string, typeCtx *moduleContext, t Type, val Value) goast.Expr {
+	resolved, resolvedCtx := typeCtx.underlyingType(t)
+	if xmlValue, ok := val.(XMLValue); ok {
+		converted, err := resolvedCtx.xmlToValue(resolved, xmlValue)
+		if err != nil {
+			ctx.appendError(fmt.Errorf("value %v: %w", path, err))
+			return nil
+		}
+		val = converted
+	}
+	if braced, ok := val.(BracedValue); ok {
+		converted, ok := braced.As(resolved)
+		if !ok {
+			ctx.appendError(fmt.Errorf("value %v: braced value is not valid for type %v, or is not supported", path, t))
+			return nil
+		}
+		val = converted
+	}
+	switch val := val.(type) {
+	case Number:
+		return numberToExpr(val, typeCtx.integerRepr(t))
+	case BigNumber:
+		return bigNumberToExpr(val, typeCtx.integerRepr(t))
+	case Boolean:
+		if val {
+			return &goast.BasicLit{Value: "true"}
+		} else {
+			return &goast.BasicLit{Value: "false"}
+		}
+	case Real:
+		return realToExpr(ctx, float64(val))
+	case BitStringValue:
+		return resolvedCtx.bitStringValueToExpr(path, typeCtx.generateTypeExpr(t), resolved, val)
+	case CharacterStringValue:
+		str, ok := val.StringValue()
+		if !ok {
+			ctx.appendError(fmt.Errorf("value %v: references in character string values are not supported", path))
+			return nil
+		}
+		return &goast.BasicLit{Kind: gotoken.STRING, Value: strconv.Quote(str)}
+	case NullValue:
+		ctx.requireModule("encoding/asn1")
+		return goast.NewIdent("asn1.NullRawValue")
+	case SequenceValue:
+		return ctx.sequenceValueToExpr(path, typeCtx.generateTypeExpr(t), resolvedCtx, resolved, val)
+	case SequenceOfValue:
+		return ctx.sequenceOfValueToExpr(path, typeCtx.generateTypeExpr(t), resolvedCtx, resolved, val)
+	case ChoiceValue:
+		return ctx.choiceValueToExpr(path, typeCtx.choiceTypeName(t), resolvedCtx, resolved, val)
+	case ObjectIdentifierValue:
+		return ctx.objectIdentifierValueToExpr(path, val)
+	case DefinedValue, IdentifiedIntegerValue:
+		return ctx.referencedValueToExpr(path, typeCtx, t, resolvedCtx, resolved, val)
+	default:
+		return nil
+	}
+}
+
+// objectIdentifierValueToExpr converts OBJECT IDENTIFIER value to asn1.ObjectIdentifier literal.
+func (ctx *moduleContext) objectIdentifierValueToExpr(path string, val ObjectIdentifierValue) goast.Expr {
+	arcs, err := ctx.resolveObjectIdentifier(val, nil)
+	if err != nil {
+		ctx.appendError(fmt.Errorf("value %v: %w", path, err))
+		return nil
+	}
+	elts := make([]goast.Expr, 0, len(arcs))
+	for _, arc := range arcs {
+		elts = append(elts, &goast.BasicLit{Kind: gotoken.INT, Value: strconv.Itoa(arc)})
+	}
+	ctx.requireModule("encoding/asn1")
+	return &goast.CompositeLit{Type: goast.NewIdent("asn1.ObjectIdentifier"), Elts: elts}
+}
+
+// resolveObjectIdentifier converts OBJECT IDENTIFIER value to the list of numeric arcs.
+// Name forms are resolved using well-known arc names, and references are resolved to numbers,
+// or to OBJECT IDENTIFIER values if reference is the first component. See X.680, section 31.
+// Visited holds names of OBJECT IDENTIFIER values being resolved, and is used to detect cycles.
+func (ctx *moduleContext) resolveObjectIdentifier(val ObjectIdentifierValue, visited []string) ([]int, error) {
+	arcs := make([]int, 0, len(val))
+	for i, elem := range val {
+		if elem.Reference == nil {
+			arcs = append(arcs, elem.ID)
+			continue
+		}
+		if elem.Name == "" && elem.Reference.ModuleName == "" {
+			if arc, ok := wellKnownArc(arcs, elem.Reference.ValueName.Name()); ok {
+				arcs = append(arcs, arc)
+				continue
+			}
+		}
+		referenced, referencedCtx, err := ctx.lookupValue(*elem.Reference)
+		if err != nil {
+			return nil, err
+		}
+		switch v := valueOfType(ObjectIdentifierType{}, referenced).(type) {
+		case Number:
+			arcs = append(arcs, v.IntValue())
+		case ObjectIdentifierValue:
+			name := elem.Reference.String()
+			if i != 0 || elem.Name != "" {
+				return nil, fmt.Errorf("%v: OBJECT IDENTIFIER reference is allowed only as first component", name)
+			}
+			qualifiedName := string(referencedCtx.moduleName) + "." + name
+			if slices.Contains(visited, qualifiedName) {
+				return nil, fmt.Errorf("%v: OBJECT IDENTIFIER value references itself", name)
+			}
+			prefix, err := referencedCtx.resolveObjectIdentifier(v, append(visited, qualifiedName))
+			if err != nil {
+				return nil, err
+			}
+			arcs = append(arcs, prefix...)
+		default:
+			return nil, fmt.Errorf("%v: expected Number or OBJECT IDENTIFIER value, got %#v", elem.Reference, referenced)
+		}
+	}
+	return arcs, nil
+}
+
+// wellKnownArcs are names of top level arcs and their children, which can be used in NameForm.
+// See X.660, Annex A.
+var wellKnownArcs = map[string]int{
+	"itu-t":                     0,
+	"ccitt":                     0,
+	"iso":                       1,
+	"joint-iso-itu-t":           2,
+	"joint-iso-ccitt":           2,
+	"0.recommendation":          0,
+	"0.question":                1,
+	"0.administration":          2,
+	"0.network-operator":        3,
+	"0.identified-organization": 4,
+	"1.standard":                0,
+	"1.registration-authority":  1,
+	"1.member-body":             2,
+	"1.identified-organization": 3,
+}
+
+// wellKnownArc returns number of the next arc in NameForm, if name is defined for it.
+func wellKnownArc(arcs []int, name string) (int, bool) {
+	switch len(arcs) {
+	case 0:
+		arc, ok := wellKnownArcs[name]
+		return arc, ok
+	case 1:
+		arc, ok := wellKnownArcs[fmt.Sprintf("%v.%v", arcs[0], name)]
+		return arc, ok
+	default:
+		return 0, false
+	}
+}
+
+// referencedValueToExpr converts referenced value, which is either a named number of the type,
+// or a reference to another value assignment.
+func (ctx *moduleContext) referencedValueToExpr(path string, typeCtx *moduleContext, t Type, resolvedCtx *moduleContext, resolved Type, val Value) goast.Expr {
+	lookupCtx := ctx
+	if et, ok := resolved.(EnumeratedType); ok {
+		n, ok, err := resolvedCtx.enumerationItemValue(et, val)
+		if err != nil {
+			ctx.appendError(fmt.Errorf("value %v: %w", path, err))
+			return nil
+		}
+		if ok {
+			return numberToExpr(n, IntegerReprInt64)
+		}
+	}
+	if ident, ok := val.(IdentifiedIntegerValue); ok {
+		if it, ok := resolved.(IntegerType); ok {
+			for _, namedNumber := range it.NamedNumberList {
+				if v, ok := namedNumber.Value.(Value); ok && namedNumber.Name == Identifier(ident.Name) {
+					val, lookupCtx = v, resolvedCtx
+				}
+			}
+		}
+	}
+	referenced, referencedCtx, err := lookupCtx.lookupValue(val)
+	if err != nil {
+		ctx.appendError(fmt.Errorf("value %v: %w", path, err))
+		return nil
+	}
+	return referencedCtx.nestedValueToExpr(path, typeCtx, t, referenced)
+}
+
+// nestedValueToExpr is same as valueToExpr, but reports values that are not supported as errors.
+func (ctx *moduleContext) nestedValueToExpr(path string, typeCtx *moduleContext, t Type, val Value) goast.Expr {
+	errorCount := len(ctx.root().errors)
+	expr := ctx.valueToExpr(path, typeCtx, t, val)
+	if expr == nil && len(ctx.root().errors) == errorCount {
+		ctx.appendError(fmt.Errorf("value %v: %T values are not supported", path, val))
+	}
+	return expr
+}
+
+// xmlControlCharacters are names of empty elements, which represent control characters in XML value notation
+// of character strings. Index of the name is the code of the character. See X.680, section 12.15.9.
+var xmlControlCharacters = []string{
+	"nul", "soh", "stx", "etx", "eot", "enq", "ack", "bel", "bs", "ht", "lf", "vt", "ff", "cr", "so", "si",
+	"dle", "dc1", "dc2", "dc3", "dc4", "nak", "syn", "etb", "can", "em", "sub", "esc", "is4", "is3", "is2", "is1",
+}
+
+// xmlToValue converts value in XML value notation to the form of basic value notation expected by underlying type t.
+// Values of components and elements stay in XML value notation, and are converted with their own types.
+// See X.680, sections 17-31 for XML value notation of each type.
+func (ctx *moduleContext) xmlToValue(t Type, v XMLValue) (Value, error) {
+	text, isText := v.Text()
+	elements, isElements := v.Elements()
+	// name returns name of the only empty child element, e.g. <true/>, or identifier in text form
+	name := func() (string, bool) {
+		if len(elements) == 1 && elements[0].IsEmpty() {
+			return elements[0].Name, true
+		}
+		text = strings.TrimSpace(text)
+		return text, isText && text != "" && unicode.IsLower([]rune(text)[0])
+	}
+	switch tt := t.(type) {
+	case BooleanType:
+		switch name, _ := name(); name {
+		case "true":
+			return Boolean(true), nil
+		case "false":
+			return Boolean(false), nil
+		}
+	case IntegerType:
+		if name, ok := name(); ok {
+			return IdentifiedIntegerValue{Name: name}, nil
+		}
+		if n, ok := new(big.Int).SetString(strings.TrimSpace(text), 10); isText && ok {
+			return newIntegerValue(n), nil
+		}
+	case EnumeratedType:
+		if name, ok := name(); ok {
+			return IdentifiedIntegerValue{Name: name}, nil
+		}
+	case RealType:
+		switch name, _ := name(); name {
+		case "PLUS-INFINITY", "INF":
+			return Real(math.Inf(1)), nil
+		case "MINUS-INFINITY", "-INF":
+			return Real(math.Inf(-1)), nil
+		case "NOT-A-NUMBER", "NaN":
+			return Real(math.NaN()), nil
+		}
+		if f, err := strconv.ParseFloat(strings.TrimSpace(text), 64); isText && err == nil {
+			return Real(f), nil
+		}
+	case BitStringType:
+		if len(elements) > 0 {
+			names := make([]Identifier, 0, len(elements))
+			for _, e := range elements {
+				if !e.IsEmpty() {
+					return nil, fmt.Errorf("named bit %v should be empty element", e.Name)
+				}
+				names = append(names, Identifier(e.Name))
+			}
+			return BitStringValue{NamedBits: names}, nil
+		}
+		bits := strings.Join(strings.Fields(text), "")
+		if isText && strings.Trim(bits, "01") == "" {
+			return parseBString(bits), nil
+		}
+	case OctetStringType:
+		hex := strings.ToUpper(strings.Join(strings.Fields(text), ""))
+		if isText && strings.Trim(hex, "0123456789ABCDEF") == "" {
+			return parseHString(hex), nil
+		}
+	case NullType:
+		if isElements && len(elements) == 0 {
+			return NullValue{}, nil
+		}
+	case ObjectIdentifierType:
+		if isText {
+			return xmlToObjectIdentifier(strings.Join(strings.Fields(text), ""))
+		}
+	case RestrictedStringType, CharacterStringType:
+		var sb strings.Builder
+		for _, c := range v.Content {
+			switch c := c.(type) {
+			case XMLText:
+				sb.WriteString(string(c))
+			case XMLValue:
+				code := slices.Index(xmlControlCharacters, c.Name)
+				if code < 0 || !c.IsEmpty() {
+					return nil, fmt.Errorf("unexpected element %v in character string value", c.Name)
+				}
+				sb.WriteByte(byte(code))
+			}
+		}
+		return CharacterStringValue{CString(sb.String())}, nil
+	case SequenceType, SetType:
+		if isElements {
+			res := make(SequenceValue, 0, len(elements))
+			for _, e := range elements {
+				res = append(res, NamedValue{Identifier: Identifier(e.Name), Value: e})
+			}
+			return res, nil
+		}
+	case SequenceOfType:
+		if isElements {
+			return ctx.xmlToSequenceOfValue(tt.Type, elements), nil
+		}
+	case SetOfType:
+		if isElements {
+			return ctx.xmlToSequenceOfValue(tt.Type, elements), nil
+		}
+	case ChoiceType:
+		if len(elements) == 1 {
+			return ChoiceValue{Identifier: Identifier(elements[0].Name), Value: elements[0]}, nil
+		}
+	default:
+		return nil, fmt.Errorf("XML values of %#v are not supported", t)
+	}
+	return nil, fmt.Errorf("invalid XML value of %#v", t)
+}
+
+// xmlToSequenceOfValue converts elements of SEQUENCE OF or SET OF value in XML value notation.
+// Values of BOOLEAN, ENUMERATED and CHOICE types are listed without enclosing elements,
+// and other values are enclosed into elements named by identifier or name of the type.
+// See X.680, section 25.
+func (ctx *moduleContext) xmlToSequenceOfValue(t Type, elements []XMLValue) SequenceOfValue {
+	valueList := ctx.xerValueList(t)
+	res := make(SequenceOfValue, 0, len(elements))
+	for _, e := range elements {
+		if valueList {
+			res = append(res, XMLValue{Content: []XMLContent{e}})
+		} else {
+			res = append(res, e)
+		}
+	}
+	return res
+}
+
+// xmlToObjectIdentifier converts OBJECT IDENTIFIER value in XML value notation, e.g. 1.2.840 or iso(1).member-body(2).
+func xmlToObjectIdentifier(text string) (Value, error) {
+	res := make(ObjectIdentifierValue, 0)
+	for _, component := range strings.Split(text, ".") {
+		name, number, hasNumber := strings.Cut(strings.TrimSuffix(component, ")"), "(")
+		if !hasNumber {
+			name, number = "", component
+		}
+		if n, err := strconv.Atoi(number); err == nil && n >= 0 && number[0] != '+' {
+			res = append(res, ObjectIdElement{Name: name, ID: n})
+		} else if r, _ := utf8.DecodeRuneInString(component); !hasNumber && unicode.IsLower(r) {
+			res = append(res, ObjectIdElement{Reference: &DefinedValue{ValueName: ValueReference(component)}})
+		} else {
+			return nil, fmt.Errorf("invalid OBJECT IDENTIFIER component %q", component)
+		}
+	}
+	return res, nil
+}
+
+// realToExpr converts REAL value to go expression, special values are converted to calls of math package.
+func realToExpr(ctx *moduleContext, v float64) goast.Expr {
+	switch {
+	case math.IsInf(v, 1):
+		ctx.requireModule("math")
+		return goast.NewIdent("math.Inf(1)")
+	case math.IsInf(v, -1):
+		ctx.requireModule("math")
+		return goast.NewIdent("math.Inf(-1)")
+	case math.IsNaN(v):
+		ctx.requireModule("math")
+		return goast.NewIdent("math.NaN()")
+	default:
+		return &goast.BasicLit{Value: fmt.Sprint(v)}
+	}
+}
+
+func (ctx *moduleContext) sequenceValueToExpr(path string, typeExpr goast.Expr, typeCtx *moduleContext, resolved Type, val SequenceValue) goast.Expr {
+	var components []ComponentType
+	var additions ExtensionAdditions
+	switch rt := resolved.(type) {
+	case SequenceType:
+		components, additions = rt.Components, rt.ExtensionAdditions
+	case SetType:
+		components, additions = rt.Components, rt.ExtensionAdditions
+	default:
+		ctx.appendError(fmt.Errorf("value %v: SEQUENCE value can not be assigned to %#v", path, resolved))
+		return nil
+	}
+	components = append(components, additions.Components()...)
+	elts := make([]goast.Expr, 0, len(val))
+	for _, namedValue := range val {
+		var componentType Type
+		var pointer bool
+		for _, component := range components {
+			if named, ok := component.(NamedComponentType); ok && named.NamedType.Identifier == namedValue.Identifier {
+				componentType = named.NamedType.Type
+				pointer = typeCtx.isPointerComponent(named)
+			}
+		}
+		componentPath := path + "." + namedValue.Identifier.Name()
+		if componentType == nil {
+			ctx.appendError(fmt.Errorf("value %v: component is not defined in the type", componentPath))
+			continue
+		}
+		expr := ctx.nestedValueToExpr(componentPath, typeCtx, componentType, namedValue.Value)
+		if expr == nil {
+			continue
+		}
+		if pointer {
+			// &[]T{value}[0] takes address of the value without declaring a variable
+			var stubBool bool
+			expr = &goast.UnaryExpr{Op: gotoken.AND, X: &goast.IndexExpr{
+				X:     &goast.CompositeLit{Type: &goast.ArrayType{Elt: typeCtx.generateTypeBody(componentType, &stubBool)}, Elts: []goast.Expr{expr}},
+				Index: &goast.BasicLit{Kind: gotoken.INT, Value: "0"},
+			}}
+		}
+		elts = append(elts, &goast.KeyValueExpr{Key: goast.NewIdent(goifyName(namedValue.Identifier.Name())), Value: expr})
+	}
+	return &goast.CompositeLit{Type: typeExpr, Elts: elts}
+}
+
+func (ctx *moduleContext) sequenceOfValueToExpr(path string, typeExpr goast.Expr, typeCtx *moduleContext, resolved Type, val SequenceOfValue) goast.Expr {
+	var elemType Type
+	switch rt := resolved.(type) {
+	case SequenceOfType:
+		elemType = rt.Type
+	case SetOfType:
+		elemType = rt.Type
+	default:
+		ctx.appendError(fmt.Errorf("value %v: SEQUENCE OF value can not be assigned to %#v", path, resolved))
+		return nil
+	}
+	if named, ok := elemType.(NamedType); ok {
+		elemType = named.Type
+	}
+	elts := make([]goast.Expr, 0, len(val))
+	for i, elem := range val {
+		if expr := ctx.nestedValueToExpr(fmt.Sprintf("%v[%v]", path, i), typeCtx, elemType, elem); expr != nil {
+			elts = append(elts, expr)
+		}
+	}
+	return &goast.CompositeLit{Type: typeExpr, Elts: elts}
+}
+
+// choiceValueToExpr converts CHOICE value. If CHOICE is represented as interface,
+// choiceName is the name of generated interface type.
+func (ctx *moduleContext) choiceValueToExpr(path string, choiceName goast.Expr, typeCtx *moduleContext, resolved Type, val ChoiceValue) goast.Expr {
+	choice, ok := resolved.(ChoiceType)
+	if !ok {
+		ctx.appendError(fmt.Errorf("value %v: CHOICE value can not be assigned to %#v", path, resolved))
+		return nil
+	}
+	if ctx.params.ChoiceRepr == ChoiceReprInterface {
+		if choiceName == nil {
+			ctx.appendError(fmt.Errorf("value %v: values of inline CHOICE types are not supported", path))
+			return nil
+		}
+		for _, alternative := range choice.Alternatives() {
+			if alternative.Identifier != val.Identifier {
+				continue
+			}
+			expr := ctx.nestedValueToExpr(path+"."+val.Identifier.Name(), typeCtx, alternative.Type, val.Value)
+			if expr == nil {
+				return nil
+			}
+			return &goast.CompositeLit{
+				Type: choiceMemberExpr(choiceName, "", goifyName(alternative.Identifier.Name())),
+				Elts: []goast.Expr{&goast.KeyValueExpr{Key: goast.NewIdent("Value"), Value: expr}},
+			}
+		}
+		ctx.appendError(fmt.Errorf("value %v: alternative %v is not defined in the type", path, val.Identifier))
+		return nil
+	}
+	if typeCtx.hasTaggedAlternatives(choice) {
+		ctx.appendError(fmt.Errorf("value %v: values of CHOICE with tagged alternatives are not supported", path))
+		return nil
+	}
+	for _, alternative := range choice.Alternatives() {
+		if alternative.Identifier != val.Identifier {
+			continue
+		}
+		expr := ctx.nestedValueToExpr(path+"."+val.Identifier.Name(), typeCtx, alternative.Type, val.Value)
+		if lit, ok := expr.(*goast.BasicLit); ok {
+			// CHOICE is represented as interface, so literal should be converted to the alternative type
+			return &goast.CallExpr{Fun: typeCtx.generateTypeExpr(alternative.Type), Args: []goast.Expr{lit}}
+		}
+		return expr
+	}
+	ctx.appendError(fmt.Errorf("value %v: alternative %v is not defined in the type", path, val.Identifier))
+	return nil
+}
+
+// generateTypeExpr is same as generateTypeBody, but is used where type is referenced rather than declared.
+func (ctx *moduleContext) generateTypeExpr(typeDescr Type) goast.Expr {
+	stubIsSet := false
+	return ctx.generateTypeBody(typeDescr, &stubIsSet)
+}
+
 func (ctx *moduleContext) generateTypeBody(typeDescr Type, isSet *bool) goast.Expr {
 	switch t := typeDescr.(type) {
 	case BooleanType:
 		return goast.NewIdent("bool")
 	case IntegerType:
 		// TODO: generate consts
-		switch ctx.params.IntegerRepr {
-		case IntegerReprInt64:
-			return goast.NewIdent("int64") // TODO signed, unsigned, range constraints
-		case IntegerReprBigInt:
-			ctx.requireModule("math/big")
-			return &goast.StarExpr{X: goast.NewIdent("big.Int")}
-		default:
-			ctx.appendError(fmt.Errorf("unknown int type mode: %v", ctx.params.IntegerRepr))
-			return goast.NewIdent("int64")
-		}
+		return ctx.integerTypeExpr(ctx.defaultIntegerType())
 	case CharacterStringType:
 		return goast.NewIdent("string")
 	case RealType:
@@ -254,7 +1018,15 @@ func (ctx *moduleContext) generateTypeBody(typeDescr Type, isSet *bool) goast.Ex
 		return &goast.ArrayType{Elt: ctx.generateTypeBody(t.Type, isSet)}
 	case TaggedType: // TODO should put tags in go code?
 		return ctx.generateTypeBody(t.Type, isSet)
-	case ConstraintedType: // TODO should generate checking code?
+	case NamedType: // element of SEQUENCE OF or SET OF can be named
+		return ctx.generateTypeBody(t.Type, isSet)
+	case ConstraintedType:
+		if contained, ok := ctx.contentsOf(t); ok {
+			return ctx.generateTypeBody(contained, isSet)
+		}
+		if goType, ok := ctx.constrainedIntegerType(t); ok {
+			return ctx.integerTypeExpr(goType)
+		}
 		return ctx.generateTypeBody(t.Type, isSet)
 	case TypeReference: // TODO should useful types be separate type by itself?
 		nameAndType := ctx.resolveTypeReference(t)
@@ -264,7 +1036,7 @@ func (ctx *moduleContext) generateTypeBody(typeDescr Type, isSet *bool) goast.Ex
 				return specialCase
 			}
 		}
-		return goast.NewIdent(goifyName(t.Name()))
+		return ctx.qualifiedTypeIdent(t)
 	case RestrictedStringType: // TODO should generate checking code?
 		return goast.NewIdent("string")
 	case BitStringType:
@@ -280,31 +1052,39 @@ func (ctx *moduleContext) generateTypeBody(typeDescr Type, isSet *bool) goast.Ex
 		ctx.requireModule("encoding/asn1")
 		return goast.NewIdent("asn1.ObjectIdentifier")
 	case ChoiceType:
-		return ctx.generateChoiceType(t, isSet)
+		switch ctx.params.ChoiceRepr {
+		case ChoiceReprRaw:
+			return ctx.generateChoiceType(t, isSet)
+		case ChoiceReprInterface:
+			ctx.appendError(fmt.Errorf("CHOICE type %#v should be declared by type assignment", t))
+			return nil
+		default:
+			ctx.appendError(fmt.Errorf("unknown choice type mode: %v", ctx.params.ChoiceRepr))
+			return nil
+		}
+	case NullType:
+		ctx.requireModule("encoding/asn1")
+		return goast.NewIdent("asn1.RawValue")
 	default:
-		// NullType
 		ctx.appendError(fmt.Errorf("ignoring unsupported type %#v", typeDescr))
 		return nil
 	}
 }
 
 func (ctx *moduleContext) generateAssociatedValuesIfNeeded(reference TypeReference, typeDescr Type) goast.Decl {
-	switch typeDescr := ctx.removeWrapperTypes(typeDescr).(type) {
+	switch t := ctx.removeWrapperTypes(typeDescr).(type) {
 	case IntegerType:
-		if len(typeDescr.NamedNumberList) == 0 {
+		if len(t.NamedNumberList) == 0 {
 			return nil
 		}
 		var specs []goast.Spec
-		for _, namedNumber := range typeDescr.NamedNumberList {
+		for _, namedNumber := range t.NamedNumberList {
 			var valueExpr goast.Expr
 			switch v := namedNumber.Value.(type) {
 			case Number:
-				valueExpr = numberToExpr(v, ctx.params.IntegerRepr)
+				valueExpr = numberToExpr(v, ctx.integerRepr(typeDescr))
 			case DefinedValue:
-				if v.ModuleName != "" {
-					ctx.appendError(fmt.Errorf("%v.%v: value references from other modules are not supported", v.ModuleName, v.ValueName))
-				}
-				valueExpr = valueRefToIdent(v.ValueName)
+				valueExpr = ctx.valueRefToExpr(v)
 			}
 			typeName := goifyName(string(reference))
 			specs = append(specs, &goast.ValueSpec{
@@ -322,10 +1102,99 @@ func (ctx *moduleContext) generateAssociatedValuesIfNeeded(reference TypeReferen
 	}
 }
 
+// bitStringValueToExpr converts BitStringValue to go expression according to underlying type t,
+// which can be BIT STRING or OCTET STRING. BIT STRING values are literals of go type typeExpr.
+func (ctx *moduleContext) bitStringValueToExpr(path string, typeExpr goast.Expr, t Type, val BitStringValue) goast.Expr {
+	switch tt := t.(type) {
+	case OctetStringType:
+		if val.IsNamedBitList() {
+			ctx.appendError(fmt.Errorf("value %v: named bits can not be used as OCTET STRING value", path))
+			return nil
+		}
+		return bytesToExpr(val.OctetString())
+	case BitStringType:
+		if val.IsNamedBitList() {
+			val = ctx.namedBitsToBitString(path, tt, val.NamedBits)
+		}
+		return &goast.CompositeLit{
+			Type: typeExpr,
+			Elts: []goast.Expr{
+				&goast.KeyValueExpr{Key: goast.NewIdent("Bytes"), Value: bytesToExpr(val.Bytes)},
+				&goast.KeyValueExpr{Key: goast.NewIdent("BitLength"), Value: &goast.BasicLit{Kind: gotoken.INT, Value: fmt.Sprint(val.BitLength)}},
+			},
+		}
+	default:
+		ctx.appendError(fmt.Errorf("value %v: bit string value can not be assigned to %#v", path, t))
+		return nil
+	}
+}
+
+// namedBitsToBitString converts list of named bits to bits, using definitions from BIT STRING type.
+// Resulting value has no trailing zero bits, see X.680, section 21.7.
+func (ctx *moduleContext) namedBitsToBitString(path string, t BitStringType, names []Identifier) BitStringValue {
+	indices := make([]int, 0, len(names))
+	maxIndex := -1
+	for _, name := range names {
+		found := false
+		for _, namedBit := range t.NamedBits {
+			if namedBit.Name != name {
+				continue
+			}
+			found = true
+			index, _, err := ctx.lookupValue(namedBit.Index)
+			if err != nil {
+				ctx.appendError(fmt.Errorf("value %v: index of bit %v: %w", path, name, err))
+				continue
+			}
+			switch index := index.(type) {
+			case Number:
+				indices = append(indices, index.IntValue())
+				maxIndex = max(maxIndex, index.IntValue())
+			default:
+				ctx.appendError(fmt.Errorf("value %v: index of bit %v should be Number, got %#v", path, name, index))
+			}
+		}
+		if !found {
+			ctx.appendError(fmt.Errorf("value %v: bit %v is not defined in BIT STRING type", path, name))
+		}
+	}
+	val := BitStringValue{Bytes: make([]byte, (maxIndex+8)/8), BitLength: maxIndex + 1}
+	for _, index := range indices {
+		val.Bytes[index/8] |= 0x80 >> uint(index%8)
+	}
+	return val
+}
+
+func bytesToExpr(data []byte) goast.Expr {
+	elts := make([]goast.Expr, 0, len(data))
+	for _, b := range data {
+		elts = append(elts, &goast.BasicLit{Kind: gotoken.INT, Value: fmt.Sprintf("0x%02x", b)})
+	}
+	return &goast.CompositeLit{Type: &goast.ArrayType{Elt: goast.NewIdent("byte")}, Elts: elts}
+}
+
 func valueRefToIdent(ref ValueReference) *goast.Ident {
 	return goast.NewIdent("Val" + goifyName(string(ref)))
 }
 
+// valueRefToExpr returns expression referencing variable generated for the value assignment.
+// References to values from other modules are supported only if packages are generated per module.
+func (ctx *moduleContext) valueRefToExpr(ref DefinedValue) goast.Expr {
+	ident := valueRefToIdent(ref.ValueName)
+	if ctx.params.ImportPath == "" {
+		if ref.ModuleName != "" {
+			ctx.appendError(fmt.Errorf("%v: value references from other modules are not supported", ref))
+		}
+		return ident
+	}
+	_, assignmentCtx, err := ctx.lookupValueAssignment(ref)
+	if err != nil {
+		ctx.appendError(err)
+		return ident
+	}
+	return assignmentCtx.qualifiedIdent(ident)
+}
+
 func numberToExpr(val Number, repr IntegerRepr) goast.Expr {
 	var valueExpr goast.Expr
 	valueExpr = &goast.BasicLit{Value: fmt.Sprint(val.IntValue())}
@@ -335,8 +1204,29 @@ func numberToExpr(val Number, repr IntegerRepr) goast.Expr {
 	return valueExpr
 }
 
+// bigNumberToExpr converts integer value exceeding Number to go literal, or to big.Int expression.
+func bigNumberToExpr(val BigNumber, repr IntegerRepr) goast.Expr {
+	if repr == IntegerReprBigInt {
+		return goast.NewIdent(bigIntExpr(val.Int))
+	}
+	return &goast.BasicLit{Kind: gotoken.INT, Value: val.String()}
+}
+
+// bigIntExpr returns go expression of big.Int value n. Values exceeding uint64 are parsed from their decimal representation.
+func bigIntExpr(n *big.Int) string {
+	switch {
+	case n.IsInt64():
+		return fmt.Sprintf("big.NewInt(%v)", n)
+	case n.IsUint64():
+		return fmt.Sprintf("new(big.Int).SetUint64(%v)", n)
+	default:
+		return fmt.Sprintf("func() *big.Int { n, _ := new(big.Int).SetString(%q, 10); return n }()", n.String())
+	}
+}
+
 func (ctx *moduleContext) generateChoiceType(t ChoiceType, isSet *bool) goast.Expr {
 	if ctx.hasTaggedAlternatives(t) {
+		ctx.requireModule("encoding/asn1")
 		return goast.NewIdent("asn1.RawValue")
 	}
 	if len(t.AlternativeTypeList) == 1 {
@@ -362,11 +1252,11 @@ func (ctx *moduleContext) taggedChoiceTypeAlternative(name Identifier, t Type) b
 		if t.Name() == GeneralizedTimeName || t.Name() == UTCTimeName {
 			return false
 		}
-		realType := ctx.resolveTypeReference(t)
+		realType, realTypeCtx := ctx.resolveTypeReferenceContext(t)
 		if realType == nil {
 			return false
 		}
-		return ctx.taggedChoiceTypeAlternative(name, realType.Type)
+		return realTypeCtx.taggedChoiceTypeAlternative(name, realType.Type)
 	case ConstraintedType:
 		return ctx.taggedChoiceTypeAlternative(name, t.Type)
 	default:
@@ -384,7 +1274,7 @@ func (ctx *moduleContext) structFromComponents(components ComponentTypeList, ext
 			ctx.appendError(errors.New("COMPONENTS OF is not supported"))
 		}
 	}
-	for _, field := range extensions {
+	for _, field := range extensions.Components() {
 		switch f := field.(type) {
 		case NamedComponentType:
 			fields.List = append(fields.List, ctx.generateStructField(f))
@@ -399,14 +1289,31 @@ func (ctx *moduleContext) structFromComponents(components ComponentTypeList, ext
 
 func (ctx *moduleContext) generateStructField(f NamedComponentType) *goast.Field {
 	var stubBool bool // we care about isSet / shouldAssign only for top-level decls
+	fieldType := ctx.generateTypeBody(f.NamedType.Type, &stubBool)
+	if ctx.isPointerComponent(f) {
+		fieldType = &goast.StarExpr{X: fieldType}
+	}
 	return &goast.Field{
 		Names: append(make([]*goast.Ident, 0), goast.NewIdent(goifyName(f.NamedType.Identifier.Name()))),
-		Type:  ctx.generateTypeBody(f.NamedType.Type, &stubBool),
+		Type:  fieldType,
 		Tag:   ctx.asn1TagFromType(f),
 	}
 }
 
 func (ctx *moduleContext) asn1TagFromType(nt NamedComponentType) *goast.BasicLit {
+	components := ctx.asn1FieldParams(nt)
+	if len(components) > 0 {
+		return &goast.BasicLit{
+			Value: fmt.Sprintf("`asn1:\"%s\"`", strings.Join(components, ",")),
+			Kind:  gotoken.STRING,
+		}
+	} else {
+		return nil
+	}
+}
+
+// asn1FieldParams returns encoding/asn1 parameters of the field, e.g. optional or explicit.
+func (ctx *moduleContext) asn1FieldParams(nt NamedComponentType) []string {
 	t := nt.NamedType.Type
 	components := make([]string, 0)
 	if nt.IsOptional {
@@ -431,22 +1338,13 @@ unwrap:
 			case CLASS_PRIVATE:
 				components = append(components, "private")
 			}
-			tagType := ctx.tagDefault
-			if tt.HasTagType {
-				tagType = tt.TagType
-			}
-			switch tagType {
-			case TAGS_EXPLICIT:
+			if ctx.isExplicitTag(tt) {
 				components = append(components, "explicit")
-			case TAGS_IMPLICIT: // nothing to do
-			case TAGS_AUTOMATIC:
-				ctx.appendError(fmt.Errorf("type %v: AUTOMATIC tags are not supported", nt.NamedType.Identifier))
 			}
-			switch cn := ctx.lookupValue(tt.Tag.ClassNumber).(type) {
-			case Number:
-				components = append(components, fmt.Sprintf("tag:%v", cn.IntValue()))
-			default:
-				ctx.appendError(fmt.Errorf("tag value should be Number, got %#v", cn))
+			if tag, err := ctx.tagOf(tt); err != nil {
+				ctx.appendError(fmt.Errorf("type %v: %w", nt.NamedType.Identifier, err))
+			} else {
+				components = append(components, fmt.Sprintf("tag:%v", tag.Number))
 			}
 			t = tt.Type
 		case ConstraintedType:
@@ -481,13 +1379,34 @@ unwrap:
 		}
 		// TODO omitempty    causes empty slices to be skipped\
 	}
-	if len(components) > 0 {
-		return &goast.BasicLit{
-			Value: fmt.Sprintf("`asn1:\"%s\"`", strings.Join(components, ",")),
-			Kind:  gotoken.STRING,
+	return components
+}
+
+// isUntaggedChoiceOrOpenType returns true if t is CHOICE or ANY type which is not tagged,
+// either directly or via type reference. Such types can only be tagged explicitly.
+func (ctx *moduleContext) isUntaggedChoiceOrOpenType(t Type) bool {
+	current := ctx
+	var visited []string
+	for {
+		switch tt := t.(type) {
+		case ConstraintedType:
+			t = tt.Type
+		case TypeReference:
+			name := string(current.moduleName) + "." + tt.Name()
+			if slices.Contains(visited, name) {
+				return false
+			}
+			visited = append(visited, name)
+			assignment, assignmentCtx, err := current.lookupTypeAssignment(tt)
+			if err != nil || assignment == nil {
+				return false
+			}
+			t, current = assignment.Type, assignmentCtx
+		case ChoiceType, AnyType:
+			return true
+		default:
+			return false
 		}
-	} else {
-		return nil
 	}
 }
 
@@ -496,30 +1415,187 @@ func (ctx *moduleContext) generateSpecialCase(resolved TypeAssignment) goast.Exp
 		// time types in encoding/asn1go don't support wrapping of time.Time
 		ctx.requireModule("time")
 		return goast.NewIdent("time.Time")
-	} else if _, ok := ctx.removeWrapperTypes(resolved.Type).(BitStringType); ok {
+	} else if _, ok := ctx.removeWrapperTypes(resolved.Type).(BitStringType); ok && !ctx.isNamedBitString(resolved.Type) {
 		ctx.requireModule("encoding/asn1")
 		return goast.NewIdent("asn1.BitString")
 	}
 	return nil
 }
 
-// TODO really lookup values from module and imports
-func (ctx *moduleContext) lookupValue(val Value) Value {
-	return val
+// lookupValue follows value references until reaches value which is not a reference.
+// Bare identifiers are treated as value references too, as parser can not tell them apart from
+// named numbers without knowing the governing type.
+// Returns resolved value together with context of the module where it is defined.
+// Returns error if referenced value is not defined, or if references form a cycle.
+func (ctx *moduleContext) lookupValue(val Value) (Value, *moduleContext, error) {
+	current := ctx
+	var visited []string
+	for {
+		var ref DefinedValue
+		switch v := val.(type) {
+		case DefinedValue:
+			ref = v
+		case IdentifiedIntegerValue:
+			ref = DefinedValue{ValueName: ValueReference(v.Name)}
+		default:
+			return val, current, nil
+		}
+		if ref.ModuleName == "" {
+			ref.ModuleName = current.moduleName
+		}
+		name := ref
+		if name.ModuleName == ctx.moduleName {
+			name.ModuleName = ""
+		}
+		for i, seen := range visited {
+			if seen == name.String() {
+				return nil, nil, fmt.Errorf("reference cycle %v", strings.Join(append(visited[i:], name.String()), " -> "))
+			}
+		}
+		visited = append(visited, name.String())
+		assignment, assignmentCtx, err := current.lookupValueAssignment(ref)
+		if err != nil {
+			return nil, nil, err
+		}
+		val, current = assignment.Value, assignmentCtx
+	}
+}
+
+// lookupValueAssignment finds assignment of referenced value in the module or in its imports.
+// Returns assignment together with context of the module where it is defined.
+func (ctx *moduleContext) lookupValueAssignment(ref DefinedValue) (*ValueAssignment, *moduleContext, error) {
+	current := ctx
+	if ref.ModuleName != "" && ref.ModuleName != ctx.moduleName {
+		var err error
+		if current, err = ctx.lookupModule(ctx.moduleReference(ref.ModuleName)); err != nil {
+			return nil, nil, fmt.Errorf("module of value %v: %w", ref, err)
+		}
+	}
+	var visited []ModuleReference
+	for {
+		if slices.Contains(visited, current.moduleName) {
+			return nil, nil, fmt.Errorf("import cycle between modules %v while resolving value %v", visited, ref.ValueName)
+		}
+		visited = append(visited, current.moduleName)
+		if assignment := current.lookupContext.AssignmentList.GetValue(ref.ValueName.Name()); assignment != nil {
+			return assignment, current, nil
+		}
+		imported, err := current.importedModule(ref.ValueName)
+		if err != nil {
+			return nil, nil, fmt.Errorf("import of value %v: %w", ref.ValueName, err)
+		}
+		if imported == nil {
+			if current != ctx {
+				return nil, nil, fmt.Errorf("value %v is not defined in module %v", ref.ValueName, current.moduleName)
+			}
+			return nil, nil, fmt.Errorf("value %v is not defined", ref.ValueName)
+		}
+		current = imported
+	}
+}
+
+// lookupTypeAssignment finds assignment of referenced type in the module or in its imports.
+// Returns assignment together with context of the module where it is defined,
+// or nil if type is neither defined nor imported.
+func (ctx *moduleContext) lookupTypeAssignment(reference TypeReference) (*TypeAssignment, *moduleContext, error) {
+	current := ctx
+	var visited []ModuleReference
+	for {
+		if slices.Contains(visited, current.moduleName) {
+			return nil, nil, fmt.Errorf("type %v: import cycle between modules %v", reference, visited)
+		}
+		visited = append(visited, current.moduleName)
+		if assignment := current.lookupContext.AssignmentList.GetType(reference.Name()); assignment != nil {
+			return assignment, current, nil
+		}
+		imported, err := current.importedModule(reference)
+		if err != nil {
+			return nil, nil, fmt.Errorf("type %v: %w", reference, err)
+		}
+		if imported == nil {
+			if current != ctx {
+				return nil, nil, fmt.Errorf("type %v is not defined in module %v", reference, current.moduleName)
+			}
+			return nil, nil, nil
+		}
+		current = imported
+	}
+}
+
+// importedModule returns context of the module from which symbol is imported, or nil if symbol is not imported.
+func (ctx *moduleContext) importedModule(symbol Symbol) (*moduleContext, error) {
+	for _, imported := range ctx.lookupContext.Imports {
+		for _, s := range imported.SymbolList {
+			if s == symbol {
+				return ctx.lookupModule(imported.Module)
+			}
+		}
+	}
+	return nil, nil
+}
+
+// moduleReference returns reference to the module with provided name, as it is specified in imports.
+func (ctx *moduleContext) moduleReference(name ModuleReference) GlobalModuleReference {
+	for _, imported := range ctx.lookupContext.Imports {
+		if imported.Module.Reference == string(name) {
+			return imported.Module
+		}
+	}
+	return GlobalModuleReference{Reference: string(name)}
+}
+
+// lookupModule finds referenced module in the registry and returns context for it.
+func (ctx *moduleContext) lookupModule(ref GlobalModuleReference) (*moduleContext, error) {
+	if ctx.params.Registry == nil {
+		return nil, fmt.Errorf("module %v can not be resolved without module registry", ref.Reference)
+	}
+	module, err := ctx.params.Registry.Lookup(ref)
+	if err != nil {
+		return nil, err
+	}
+	return ctx.contextFor(module), nil
+}
+
+// contextFor returns context for the module, which shares errors and required go modules with ctx.
+func (ctx *moduleContext) contextFor(module *ModuleDefinition) *moduleContext {
+	root := ctx.root()
+	name := ModuleReference(module.ModuleIdentifier.Reference)
+	if name == root.moduleName {
+		return root
+	}
+	if res, ok := root.imported[name]; ok {
+		return res
+	}
+	res := newModuleContext(*module, ctx.params)
+	res.parent = root
+	res.imported = nil
+	root.imported[name] = res
+	return res
 }
 
 // resolveTypeReference resolves references until reaches unresolved type, useful type, or declared type
 // returns type reference of most nested type which is not type reference itself
 // returns nil if type is not resolved
 func (ctx *moduleContext) resolveTypeReference(reference TypeReference) *TypeAssignment {
-	unwrapped := ctx.unwrapToLeafType(reference)
+	resolved, _ := ctx.resolveTypeReferenceContext(reference)
+	return resolved
+}
+
+// resolveTypeReferenceContext is same as resolveTypeReference, but also returns context of the module
+// where resolved type is defined.
+func (ctx *moduleContext) resolveTypeReferenceContext(reference TypeReference) (*TypeAssignment, *moduleContext) {
+	unwrapped, unwrappedCtx, err := ctx.lookupLeafType(reference)
+	if err != nil {
+		ctx.appendError(err)
+		return nil, nil
+	}
 	if unwrapped.Type != nil {
-		return &unwrapped
+		return &unwrapped, unwrappedCtx
 	} else if tt := ctx.lookupUsefulType(unwrapped.TypeReference); tt != nil {
-		return &TypeAssignment{unwrapped.TypeReference, tt}
+		return &TypeAssignment{unwrapped.TypeReference, tt}, unwrappedCtx
 	} else {
 		ctx.appendError(fmt.Errorf("can not resolve TypeReference %v", reference.Name()))
-		return nil
+		return nil, nil
 	}
 }
 
@@ -531,6 +1607,64 @@ func (ctx *moduleContext) lookupUsefulType(reference TypeReference) Type {
 	}
 }
 
+// underlyingType removes wrapper types and resolves type references.
+// Returns resolved type together with context of the module where it is defined.
+// Returns nil if type reference can not be resolved.
+func (ctx *moduleContext) underlyingType(t Type) (Type, *moduleContext) {
+	t = ctx.removeWrapperTypes(t)
+	if ref, ok := t.(TypeReference); ok {
+		resolved, resolvedCtx := ctx.resolveTypeReferenceContext(ref)
+		if resolved == nil {
+			return nil, ctx
+		}
+		return ctx.removeWrapperTypes(resolved.Type), resolvedCtx
+	}
+	return t, ctx
+}
+
+// builtinTypeName returns name of built-in type t, e.g. OCTET STRING, or empty string if t is not a built-in type.
+func builtinTypeName(t Type) string {
+	switch tt := t.(type) {
+	case BooleanType:
+		return "BOOLEAN"
+	case IntegerType:
+		return "INTEGER"
+	case EnumeratedType:
+		return "ENUMERATED"
+	case RealType:
+		return "REAL"
+	case BitStringType:
+		return "BIT STRING"
+	case OctetStringType:
+		return "OCTET STRING"
+	case NullType:
+		return "NULL"
+	case ObjectIdentifierType:
+		return "OBJECT IDENTIFIER"
+	case SequenceType:
+		return "SEQUENCE"
+	case SetType:
+		return "SET"
+	case SequenceOfType:
+		return "SEQUENCE OF"
+	case SetOfType:
+		return "SET OF"
+	case ChoiceType:
+		return "CHOICE"
+	case AnyType:
+		return "ANY"
+	case CharacterStringType:
+		return "CHARACTER STRING"
+	case RestrictedStringType:
+		for name, code := range reservedWords {
+			if code == tt.LexType {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
 func (ctx *moduleContext) removeWrapperTypes(t Type) Type {
 	for {
 		switch tt := t.(type) {
@@ -546,13 +1680,35 @@ func (ctx *moduleContext) removeWrapperTypes(t Type) Type {
 
 // unwrapToLeafType walks over transitive type references, tags and constraints and yields "root" type reference
 func (ctx *moduleContext) unwrapToLeafType(reference TypeReference) TypeAssignment {
-	if assignment := ctx.lookupContext.AssignmentList.GetType(reference.Name()); assignment != nil {
-		t := assignment.Type
-		if tt, ok := ctx.removeWrapperTypes(t).(TypeReference); ok {
-			return ctx.unwrapToLeafType(tt)
-		} else {
-			return *assignment
+	unwrapped, _, err := ctx.lookupLeafType(reference)
+	if err != nil {
+		ctx.appendError(err)
+	}
+	return unwrapped
+}
+
+// lookupLeafType is same as unwrapToLeafType, but also returns context of the module where type is defined.
+// Returns assignment with nil type if type is not defined, e.g. for useful types.
+func (ctx *moduleContext) lookupLeafType(reference TypeReference) (TypeAssignment, *moduleContext, error) {
+	current := ctx
+	var visited []string
+	for {
+		name := string(current.moduleName) + "." + reference.Name()
+		if slices.Contains(visited, name) {
+			return TypeAssignment{reference, nil}, current, fmt.Errorf("type %v: reference cycle %v", reference, strings.Join(append(visited, name), " -> "))
+		}
+		visited = append(visited, name)
+		assignment, assignmentCtx, err := current.lookupTypeAssignment(reference)
+		if err != nil {
+			return TypeAssignment{reference, nil}, current, err
+		}
+		if assignment == nil {
+			return TypeAssignment{reference, nil}, current, nil
+		}
+		if tt, ok := ctx.removeWrapperTypes(assignment.Type).(TypeReference); ok {
+			reference, current = tt, assignmentCtx
+			continue
 		}
+		return *assignment, assignmentCtx, nil
 	}
-	return TypeAssignment{reference, nil}
 }
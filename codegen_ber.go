@@ -0,0 +1,426 @@
+package asn1go
+
+import (
+	"bytes"
+	"fmt"
+	goast "go/ast"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// berMethodsTemplate generates methods decoding the type from BER.
+var berMethodsTemplate = template.Must(template.New("ber").Parse(`
+func (v *{{.Name}}) UnmarshalBER(data []byte) ([]byte, error) {
+	return der.UnmarshalBER(data, v)
+}
+
+func (v *{{.Name}}) DecodeBER(d *der.Decoder, tag der.Tag) error {
+{{.Body -}}
+	return nil
+}
+`))
+
+// berChoiceTemplate generates functions decoding CHOICE type from BER.
+var berChoiceTemplate = template.Must(template.New("berChoice").Parse(`
+func UnmarshalBER{{.Name}}(data []byte) ({{.Name}}, []byte, error) {
+	var v {{.Name}}
+	d := der.NewDecoder(data)
+	if err := DecodeBER{{.Name}}(d, &v); err != nil {
+		return nil, nil, err
+	}
+	return v, d.Rest(), nil
+}
+
+func DecodeBER{{.Name}}(d *der.Decoder, v *{{.Name}}) error {
+	switch {
+{{- range .Alternatives}}
+	case {{.Match}}:
+		var alt {{.Name}}
+{{.Body -}}
+		*v = alt
+{{- end}}
+	default:
+		return d.Unexpected({{printf "%q" .Name}})
+	}
+	return nil
+}
+`))
+
+type berChoiceAlternativeParams struct {
+	// Name is a name of the wrapper type.
+	Name string
+	// Match is a go expression matching the next element by tag.
+	Match string
+	// Body holds statements decoding the value into alt.Value.
+	Body string
+}
+
+// generateBERMethods generates UnmarshalBER and DecodeBER methods of go type typeName,
+// which decode go expression expr of type t. Expression should be either addressable,
+// or a pointer to type which has DecodeBER method.
+func (ctx *moduleContext) generateBERMethods(typeName string, expr string, t Type, tag derTag) []goast.Decl {
+	ctx.requireModule(derPackage)
+	g := &berDecoderGen{}
+	g.decode(ctx, "d", expr, t, tag)
+	var buf bytes.Buffer
+	if err := berMethodsTemplate.Execute(&buf, map[string]string{"Name": typeName, "Body": g.buf.String()}); err != nil {
+		ctx.appendError(fmt.Errorf("type %v: %w", typeName, err))
+		return nil
+	}
+	decls, err := parseGoDecls(buf.String())
+	if err != nil {
+		ctx.appendError(fmt.Errorf("type %v: failed to parse generated code: %w", typeName, err))
+		return nil
+	}
+	return decls
+}
+
+// generateBERChoiceDecls generates functions decoding CHOICE type from BER. Alternatives are selected
+// by the tag of the next element, and alternatives of open type match any tag.
+func (ctx *moduleContext) generateBERChoiceDecls(name string, alternatives []choiceAlternativeParams) []goast.Decl {
+	ctx.requireModule(derPackage)
+	var params, matchAny []berChoiceAlternativeParams
+	for _, alternative := range alternatives {
+		tags, isAny := ctx.outermostTags(alternative.alternative.Type, nil)
+		g := &berDecoderGen{}
+		g.decode(ctx, "d", "alt.Value", alternative.alternative.Type, derTag{})
+		alt := berChoiceAlternativeParams{Name: alternative.Name, Body: g.buf.String()}
+		if isAny {
+			alt.Match = "d.More()"
+			matchAny = append(matchAny, alt)
+		} else {
+			alt.Match = "d.Peek(" + derTagList(tags) + ")"
+			params = append(params, alt)
+		}
+	}
+	var buf bytes.Buffer
+	err := berChoiceTemplate.Execute(&buf, map[string]any{"Name": name, "Alternatives": append(params, matchAny...)})
+	if err != nil {
+		ctx.appendError(fmt.Errorf("type %v: %w", name, err))
+		return nil
+	}
+	decls, err := parseGoDecls(buf.String())
+	if err != nil {
+		ctx.appendError(fmt.Errorf("type %v: failed to parse generated code: %w", name, err))
+		return nil
+	}
+	return decls
+}
+
+// derTagList returns comma separated go expressions of the tags.
+func derTagList(tags []asn1Tag) string {
+	exprs := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		exprs = append(exprs, tag.derExpr())
+	}
+	return strings.Join(exprs, ", ")
+}
+
+// berDecoderGen generates go statements decoding values from BER.
+type berDecoderGen struct {
+	derEncoderGen
+}
+
+// begin writes statements reading header of constructed value with method of decoder d,
+// and returns name of the decoder of its contents.
+func (g *berDecoderGen) begin(d string, method string, tag string) string {
+	inner := g.newVar("inner")
+	g.line("%v, err := %v.%v(%v)", inner, d, method, tag)
+	g.line("if err != nil {\n\t\treturn err\n\t}")
+	return inner
+}
+
+// decode writes statements decoding the next element of decoder d into go expression expr of type t.
+func (g *berDecoderGen) decode(ctx *moduleContext, d string, expr string, t Type, tag derTag) {
+	switch tt := t.(type) {
+	case TaggedType:
+		own, err := ctx.tagOf(tt)
+		if err != nil {
+			ctx.appendError(err)
+			return
+		}
+		outer := tag.or(own.derExpr())
+		if ctx.isExplicitTag(tt) {
+			inner := g.begin(d, "BeginConstructed", outer)
+			g.decode(ctx, inner, expr, tt.Type, derTag{})
+			g.check("%v.End()", inner)
+		} else {
+			g.decode(ctx, d, expr, tt.Type, derTag{expr: outer})
+		}
+	case ConstraintedType:
+		if contained, ok := ctx.contentsOf(tt); ok {
+			inner := g.newVar("inner")
+			g.line("if err := %v.ReadContaining(%v, func(%v *der.Decoder) error {", d, tag.or("der.TagOctetString"), inner)
+			g.decode(ctx, inner, expr, contained, derTag{})
+			g.line("return nil")
+			g.line("}); err != nil {\n\t\treturn err\n\t}")
+			return
+		}
+		g.constrain(ctx, tt)
+		g.decode(ctx, d, expr, tt.Type, tag)
+	case NamedType:
+		g.decode(ctx, d, expr, tt.Type, tag)
+	case TypeReference:
+		g.decodeReference(ctx, d, expr, tt, tag)
+	case SequenceType:
+		g.decodeSequence(ctx, d, expr, tt, tag.or("der.TagSequence"))
+	case SetType:
+		g.decodeSet(ctx, d, expr, tt, tag.or("der.TagSet"))
+	case SequenceOfType:
+		g.decodeElements(ctx, d, expr, tt.Type, "BeginConstructed", tag.or("der.TagSequence"))
+	case SetOfType:
+		g.decodeElements(ctx, d, expr, tt.Type, "BeginSetOf", tag.or("der.TagSet"))
+	case ChoiceType:
+		switch {
+		case ctx.params.ChoiceRepr == ChoiceReprInterface:
+			ctx.appendError(fmt.Errorf("CHOICE type %#v should be declared by type assignment", t))
+		case ctx.hasTaggedAlternatives(tt):
+			g.check("%v.ReadRawValue(&%v)", d, expr)
+		case len(tt.AlternativeTypeList) == 1:
+			g.decode(ctx, d, expr, tt.AlternativeTypeList[0].Type, tag) // see generateChoiceType
+		default:
+			g.check("%v.ReadAny(&%v)", d, expr)
+		}
+	case AnyType:
+		g.check("%v.ReadAny(&%v)", d, expr)
+	case BooleanType:
+		g.check("%v.ReadBoolean(%v, &%v)", d, tag.or("der.TagBoolean"), expr)
+	case IntegerType:
+		goType := g.currentIntegerType(ctx)
+		v, big := g.integerTarget(ctx, expr, goType)
+		if big {
+			g.check("%v.ReadBigInteger(%v, &%v)", d, tag.or("der.TagInteger"), v)
+		} else {
+			g.check("%v.ReadInteger(%v, &%v)", d, tag.or("der.TagInteger"), v)
+		}
+		g.assignInteger(ctx, expr, v, goType)
+	case EnumeratedType:
+		ctx.requireModule("encoding/asn1")
+		g.check("%v.ReadEnumerated(%v, (*asn1.Enumerated)(&%v))", d, tag.or("der.TagEnumerated"), expr)
+	case RealType:
+		g.check("%v.ReadReal(%v, &%v)", d, tag.or("der.TagReal"), expr)
+	case OctetStringType:
+		g.check("%v.ReadOctetString(%v, &%v)", d, tag.or("der.TagOctetString"), expr)
+	case BitStringType:
+		if len(tt.NamedBits) > 0 {
+			g.check("%v.ReadNamedBitString(%v, %v)", d, tag.or("der.TagBitString"), ctx.bitStringPointerExpr(tt, expr))
+		} else {
+			g.check("%v.ReadBitString(%v, &%v)", d, tag.or("der.TagBitString"), expr)
+		}
+	case NullType:
+		g.check("%v.ReadNull(%v)", d, tag.or("der.TagNull"))
+	case ObjectIdentifierType:
+		g.check("%v.ReadObjectIdentifier(%v, &%v)", d, tag.or("der.TagObjectIdentifier"), expr)
+	case RestrictedStringType:
+		number, ok := restrictedStringTags[tt.LexType]
+		if !ok {
+			ctx.appendError(fmt.Errorf("restricted string type %#v is not supported by BER decoder", tt))
+			return
+		}
+		own := asn1Tag{Class: CLASS_UNIVERSAL, Number: number}.derExpr()
+		switch tt.LexType {
+		case BMPString:
+			g.check("%v.ReadBMPString(%v, &%v)", d, tag.or(own), expr)
+		case UniversalString:
+			g.check("%v.ReadUniversalString(%v, &%v)", d, tag.or(own), expr)
+		default:
+			g.check("%v.ReadString(%v, &%v)", d, tag.or(own), expr)
+		}
+	default:
+		ctx.appendError(fmt.Errorf("type %#v is not supported by BER decoder", t))
+	}
+}
+
+// decodeReference writes statements decoding value of referenced type. Types having DER methods
+// are decoded by calling them, and other types are decoded inline.
+func (g *berDecoderGen) decodeReference(ctx *moduleContext, d string, expr string, t TypeReference, tag derTag) {
+	assignment, assignmentCtx, err := ctx.lookupTypeAssignment(t)
+	if err != nil {
+		ctx.appendError(err)
+		return
+	}
+	if assignment == nil {
+		switch t.Name() {
+		case GeneralizedTimeName:
+			g.check("%v.ReadGeneralizedTime(%v, &%v)", d, tag.or("der.TagGeneralizedTime"), expr)
+		case UTCTimeName:
+			g.check("%v.ReadUTCTime(%v, &%v)", d, tag.or("der.TagUTCTime"), expr)
+		default:
+			if useful := ctx.lookupUsefulType(t); useful != nil {
+				g.decode(ctx, d, expr, useful, tag)
+			} else {
+				ctx.appendError(fmt.Errorf("can not resolve TypeReference %v", t.Name()))
+			}
+		}
+		return
+	}
+	if assignmentCtx.hasEncodingMethods(assignment.Type) {
+		if choiceName := ctx.choiceTypeName(t); choiceName != nil {
+			if tag.expr != "" {
+				ctx.appendError(fmt.Errorf("type %v: CHOICE can not be tagged implicitly", t))
+				return
+			}
+			g.check("%v(%v, &%v)", exprString(choiceMemberExpr(choiceName, "DecodeBER", "")), d, expr)
+			return
+		}
+		g.check("%v.DecodeBER(%v, %v)", expr, d, tag.orZero())
+		return
+	}
+	name := string(assignmentCtx.moduleName) + "." + t.Name()
+	if slices.Contains(g.inlined, name) {
+		ctx.appendError(fmt.Errorf("type %v: reference cycle %v", t, strings.Join(append(g.inlined, name), " -> ")))
+		return
+	}
+	g.inlined = append(g.inlined, name)
+	g.decode(assignmentCtx, d, assignmentCtx.definedTypeVariable(assignment.Type, expr), assignment.Type, tag)
+	g.inlined = g.inlined[:len(g.inlined)-1]
+}
+
+// berComponent is a component of SEQUENCE or SET to decode.
+type berComponent struct {
+	named NamedComponentType
+	field string
+	// pointer is go expression of the struct field if component is represented with pointer, see componentField.
+	pointer string
+	// match is go expression of tags which encoding of the component can start with,
+	// or empty if component can have any tag.
+	match string
+	// optional is set if component can be absent.
+	optional bool
+}
+
+// berComponents returns components of SEQUENCE or SET with go expressions of their fields.
+// Extension additions can be absent in encodings produced by earlier versions of the specification,
+// so they are treated as OPTIONAL.
+func (ctx *moduleContext) berComponents(expr string, components ComponentTypeList, extensions ExtensionAdditions) []berComponent {
+	var res []berComponent
+	for i, component := range slices.Concat(components, extensions.Components()) {
+		named, ok := component.(NamedComponentType)
+		if !ok {
+			continue // COMPONENTS OF is reported as unsupported by structFromComponents
+		}
+		tags, isAny := ctx.outermostTags(named.NamedType.Type, nil)
+		c := berComponent{
+			named:    named,
+			optional: named.IsOptional || named.Default != nil || i >= len(components),
+		}
+		c.field, c.pointer = ctx.componentField(expr, named)
+		if !isAny {
+			c.match = derTagList(tags)
+		}
+		res = append(res, c)
+	}
+	return res
+}
+
+// decodeSequence writes statements decoding components of SEQUENCE value in order.
+// Absent OPTIONAL components are reset, and absent components with DEFAULT values are set to them.
+func (g *berDecoderGen) decodeSequence(ctx *moduleContext, d string, expr string, t SequenceType, tag string) {
+	inner := g.begin(d, "BeginConstructed", tag)
+	for _, c := range ctx.berComponents(expr, t.Components, t.ExtensionAdditions) {
+		if !c.optional {
+			g.decode(ctx, inner, c.field, c.named.NamedType.Type, derTag{})
+			continue
+		}
+		if c.match == "" {
+			g.line("if %v.More() {", inner)
+		} else {
+			g.line("if %v.Peek(%v) {", inner, c.match)
+		}
+		g.decodeComponent(ctx, inner, c)
+		if c.named.Default != nil {
+			g.line("} else {")
+			g.line("%v = %v", c.field, ctx.derDefaultExpr(c.named.NamedType, *c.named.Default))
+		} else if absent := ctx.absentComponent(c.field, c.pointer, c.named); absent != "" {
+			g.line("} else {")
+			g.line(absent)
+		}
+		g.line("}")
+	}
+	g.end(ctx, inner, t.Extensible)
+}
+
+// decodeSet writes statements decoding components of SET value, which can be encoded in any order.
+func (g *berDecoderGen) decodeSet(ctx *moduleContext, d string, expr string, t SetType, tag string) {
+	inner := g.begin(d, "BeginSet", tag)
+	components := ctx.berComponents(expr, t.Components, t.ExtensionAdditions)
+	for _, c := range components {
+		if c.match == "" {
+			ctx.appendError(fmt.Errorf("component %v: components of SET should have distinct tags", c.named.NamedType.Identifier))
+			return
+		}
+		if c.named.Default != nil {
+			g.line("if !%v.Contains(%v) {", inner, c.match)
+			g.line("%v = %v", c.field, ctx.derDefaultExpr(c.named.NamedType, *c.named.Default))
+			g.line("}")
+		} else if absent := ctx.absentComponent(c.field, c.pointer, c.named); absent != "" {
+			g.line("if !%v.Contains(%v) {", inner, c.match)
+			g.line(absent)
+			g.line("}")
+		} else if !c.optional {
+			g.line("if !%v.Contains(%v) {", inner, c.match)
+			g.line("return der.StructuralError{Msg: %q}", "missing component "+c.named.NamedType.Identifier.Name())
+			g.line("}")
+		}
+	}
+	g.line("for %v.More() {", inner)
+	g.line("switch {")
+	for _, c := range components {
+		g.line("case %v.Peek(%v):", inner, c.match)
+		g.decodeComponent(ctx, inner, c)
+	}
+	g.line("default:")
+	if t.Extensible || ctx.extensibilityImplied {
+		g.check("%v.EndExtensible()", inner)
+	} else {
+		g.line("return %v.End()", inner)
+	}
+	g.line("}")
+	g.line("}")
+}
+
+// decodeComponent writes statements decoding present OPTIONAL or DEFAULT component.
+// In strict mode, components equal to their DEFAULT values are rejected, see X.690, section 11.5.
+func (g *berDecoderGen) decodeComponent(ctx *moduleContext, d string, c berComponent) {
+	if c.pointer != "" {
+		g.line(ctx.allocComponent(c.pointer, c.named))
+	}
+	g.decode(ctx, d, c.field, c.named.NamedType.Type, derTag{})
+	if present := ctx.presentComponent(c.field, c.named); present != "" {
+		g.line(present)
+	}
+	if c.named.Default == nil {
+		return
+	}
+	equal := ctx.derDefaultComparison(c.field, c.named.NamedType, *c.named.Default, "==")
+	if equal == "" {
+		return
+	}
+	g.line("if %v.Strict && %v {", d, equal)
+	g.line("return der.SyntaxError{Msg: %q}", "DEFAULT value of component "+c.named.NamedType.Identifier.Name()+" is encoded")
+	g.line("}")
+}
+
+// end writes statements verifying that all elements of constructed value were read.
+// Unknown elements of extensible types are skipped.
+func (g *berDecoderGen) end(ctx *moduleContext, inner string, extensible bool) {
+	if extensible || ctx.extensibilityImplied {
+		g.check("%v.EndExtensible()", inner)
+	} else {
+		g.check("%v.End()", inner)
+	}
+}
+
+// decodeElements writes statements decoding elements of SEQUENCE OF or SET OF value.
+func (g *berDecoderGen) decodeElements(ctx *moduleContext, d string, expr string, t Type, method string, tag string) {
+	inner := g.begin(d, method, tag)
+	elem := g.newVar("elem")
+	g.line("%v = %v[:0]", expr, expr)
+	g.line("for %v.More() {", inner)
+	g.line("var %v %v", elem, exprString(ctx.generateTypeExpr(t)))
+	g.decode(ctx, inner, elem, t, derTag{})
+	g.line("%v = append(%v, %v)", expr, expr, elem)
+	g.line("}")
+	g.check("%v.End()", inner)
+}
@@ -0,0 +1,185 @@
+package asn1go
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	goast "go/ast"
+	"slices"
+	"text/template"
+)
+
+// BitStringRepr is enum controlling how BIT STRING with named bits is represented.
+type BitStringRepr string
+
+// BitStringRepr modes supported.
+const (
+	// BitStringReprType represents BIT STRING with named bits as named go type with constant for every named bit,
+	// and Has, Set, Clear and Names methods. Clear method removes trailing zero bits, see X.680, 22.7. Inline types
+	// are declared as separate types named after enclosing type and component. Note that encoding/asn1 can not
+	// encode named types, so they should be used with generated encoding methods.
+	BitStringReprType BitStringRepr = "type"
+	// BitStringReprAlias represents BIT STRING with named bits as alias of asn1.BitString with constant for every
+	// named bit, and Has, Set, Clear and Names functions named after the type, which is compatible with encoding/asn1.
+	// Clear function removes trailing zero bits as well.
+	BitStringReprAlias BitStringRepr = "alias"
+)
+
+// namedBitsTemplate generates constants of named bits of BIT STRING type, and methods of the type
+// if it is declared as a named go type, or helper functions otherwise.
+var namedBitsTemplate = template.Must(template.New("namedBits").Parse(`
+const (
+{{- range .Bits}}
+	{{.Const}} = {{.Index}}
+{{- end}}
+)
+{{- if .Methods}}
+
+func (v {{.Name}}) Has(bit int) bool {
+	return asn1.BitString(v).At(bit) == 1
+}
+
+func (v *{{.Name}}) Set(bit int) {
+	length := max(v.BitLength, bit+1)
+	bytes := make([]byte, (length+7)/8)
+	copy(bytes, v.Bytes)
+	bytes[bit/8] |= 0x80 >> (bit % 8)
+	v.Bytes, v.BitLength = bytes, length
+}
+
+func (v *{{.Name}}) Clear(bit int) {
+	if !v.Has(bit) {
+		return
+	}
+	bytes := make([]byte, len(v.Bytes))
+	copy(bytes, v.Bytes)
+	bytes[bit/8] &^= 0x80 >> (bit % 8)
+	length := v.BitLength
+	for length > 0 && bytes[(length-1)/8]&(0x80>>((length-1)%8)) == 0 {
+		length--
+	}
+	v.Bytes, v.BitLength = bytes[:(length+7)/8], length
+}
+
+func (v {{.Name}}) Names() []string {
+	var res []string
+	{{- range .Bits}}
+	if v.Has({{.Const}}) {
+		res = append(res, {{printf "%q" .Identifier}})
+	}
+	{{- end}}
+	return res
+}
+{{- else}}
+
+func {{.Name}}Has(v {{.Name}}, bit int) bool {
+	return v.At(bit) == 1
+}
+
+func {{.Name}}Set(v *{{.Name}}, bit int) {
+	length := max(v.BitLength, bit+1)
+	bytes := make([]byte, (length+7)/8)
+	copy(bytes, v.Bytes)
+	bytes[bit/8] |= 0x80 >> (bit % 8)
+	v.Bytes, v.BitLength = bytes, length
+}
+
+func {{.Name}}Clear(v *{{.Name}}, bit int) {
+	if !{{.Name}}Has(*v, bit) {
+		return
+	}
+	bytes := make([]byte, len(v.Bytes))
+	copy(bytes, v.Bytes)
+	bytes[bit/8] &^= 0x80 >> (bit % 8)
+	length := v.BitLength
+	for length > 0 && bytes[(length-1)/8]&(0x80>>((length-1)%8)) == 0 {
+		length--
+	}
+	v.Bytes, v.BitLength = bytes[:(length+7)/8], length
+}
+
+func {{.Name}}Names(v {{.Name}}) []string {
+	var res []string
+	{{- range .Bits}}
+	if {{$.Name}}Has(v, {{.Const}}) {
+		res = append(res, {{printf "%q" .Identifier}})
+	}
+	{{- end}}
+	return res
+}
+{{- end}}
+`))
+
+// namedBitParams are parameters of namedBitsTemplate for a single named bit.
+type namedBitParams struct {
+	Const      string
+	Identifier string
+	Index      int
+}
+
+// isNamedBitString returns true if BIT STRING type t of type assignment is declared as a named go type
+// with Has, Set, Clear and Names methods. Otherwise, it is an alias of asn1.BitString,
+// which is compatible with encoding/asn1.
+func (ctx *moduleContext) isNamedBitString(t Type) bool {
+	bitString, ok := ctx.removeWrapperTypes(t).(BitStringType)
+	return ok && len(bitString.NamedBits) > 0 && ctx.params.BitStringRepr == BitStringReprType
+}
+
+// generateNamedBitsDecls generates constants of named bits of BIT STRING type declared by type assignment.
+// Constants are indices of the bits named after the type and the identifier, e.g. KeyUsageBitDigitalSignature.
+func (ctx *moduleContext) generateNamedBitsDecls(reference TypeReference, t Type) []goast.Decl {
+	bitString, ok := ctx.removeWrapperTypes(t).(BitStringType)
+	if !ok || len(bitString.NamedBits) == 0 {
+		return nil
+	}
+	name := goifyName(reference.Name())
+	var bits []namedBitParams
+	for _, namedBit := range bitString.NamedBits {
+		index, _, err := ctx.lookupValue(namedBit.Index)
+		if err != nil {
+			ctx.appendError(fmt.Errorf("type %v: index of bit %v: %w", reference.Name(), namedBit.Name, err))
+			return nil
+		}
+		n, ok := index.(Number)
+		if !ok || n < 0 {
+			ctx.appendError(fmt.Errorf("type %v: index of bit %v should be non-negative Number, got %#v", reference.Name(), namedBit.Name, index))
+			return nil
+		}
+		bits = append(bits, namedBitParams{Const: name + "Bit" + goifyName(namedBit.Name.Name()), Identifier: namedBit.Name.Name(), Index: n.IntValue()})
+	}
+	slices.SortStableFunc(bits, func(a, b namedBitParams) int { return cmp.Compare(a.Index, b.Index) })
+	methods := ctx.isNamedBitString(t)
+	if methods {
+		ctx.requireModule("encoding/asn1")
+	}
+	var buf bytes.Buffer
+	if err := namedBitsTemplate.Execute(&buf, map[string]any{"Name": name, "Bits": bits, "Methods": methods}); err != nil {
+		ctx.appendError(fmt.Errorf("type %v: %w", reference.Name(), err))
+		return nil
+	}
+	decls, err := parseGoDecls(buf.String())
+	if err != nil {
+		ctx.appendError(fmt.Errorf("type %v: failed to parse generated code: %w", reference.Name(), err))
+		return nil
+	}
+	return decls
+}
+
+// bitStringExpr returns go expression expr of BIT STRING type t converted to asn1.BitString,
+// which is needed if the type has named bits, and is declared as named go type.
+func (ctx *moduleContext) bitStringExpr(t BitStringType, expr string) string {
+	if len(t.NamedBits) == 0 {
+		return expr
+	}
+	ctx.requireModule("encoding/asn1")
+	return fmt.Sprintf("asn1.BitString(%v)", expr)
+}
+
+// bitStringPointerExpr is same as bitStringExpr, but returns pointer to asn1.BitString.
+func (ctx *moduleContext) bitStringPointerExpr(t BitStringType, expr string) string {
+	if len(t.NamedBits) == 0 {
+		return "&" + expr
+	}
+	ctx.requireModule("encoding/asn1")
+	return fmt.Sprintf("(*asn1.BitString)(&%v)", expr)
+}
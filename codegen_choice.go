@@ -0,0 +1,523 @@
+package asn1go
+
+import (
+	"bytes"
+	"fmt"
+	goast "go/ast"
+	goparser "go/parser"
+	goprint "go/printer"
+	gotoken "go/token"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// ChoiceRepr is enum controlling how CHOICE is represented.
+type ChoiceRepr string
+
+// ChoiceRepr modes supported.
+const (
+	// ChoiceReprInterface represents CHOICE as sealed interface implemented by wrapper type of every alternative.
+	// Marshal and Unmarshal functions are generated for every CHOICE type to select alternative by tag.
+	// Note that encoding/asn1 can not encode structs which have fields of CHOICE types.
+	ChoiceReprInterface ChoiceRepr = "interface"
+	// ChoiceReprRaw represents CHOICE as interface{}, or as asn1.RawValue if alternatives are tagged.
+	// It is compatible with encoding/asn1, but alternatives have to be decoded manually.
+	ChoiceReprRaw ChoiceRepr = "raw"
+)
+
+// choiceTemplate generates declarations of CHOICE type in ChoiceReprInterface mode.
+var choiceTemplate = template.Must(template.New("choice").Parse(`
+type {{.Name}} interface {
+	is{{.Name}}()
+	{{- if .DER}}
+	MarshalDER() ([]byte, error)
+	EncodeDER(e *der.Encoder, tag der.Tag) error
+	{{- end}}
+	{{- if .PER}}
+	MarshalPER(variant per.Variant) ([]byte, error)
+	EncodePER(e *per.Encoder) error
+	{{- end}}
+	{{- if .OER}}
+	MarshalOER() ([]byte, error)
+	EncodeOER(e *oer.Encoder) error
+	{{- end}}
+	{{- if .JER}}
+	MarshalJSON() ([]byte, error)
+	EncodeJER(e *jer.Encoder) error
+	{{- end}}
+	{{- if .XER}}
+	MarshalXER() ([]byte, error)
+	EncodeXER(e *xer.Encoder) error
+	{{- end}}
+	{{- if .Validate}}
+	Validate() error
+	{{- end}}
+}
+{{range .Alternatives}}
+type {{.Name}} struct {
+	Value {{.ValueType}}
+}
+
+func ({{.Name}}) is{{$.Name}}() {}
+{{end}}
+func Marshal{{.Name}}(v {{.Name}}) ([]byte, error) {
+	switch v := v.(type) {
+	{{- range .Alternatives}}
+	case {{.Name}}:
+		{{- if not .Choice}}
+		return asn1.MarshalWithParams(v.Value, {{printf "%q" .Params}})
+		{{- else if .Tag}}
+		data, err := {{.Choice.Marshal}}(v.Value)
+		if err != nil {
+			return nil, err
+		}
+		return asn1.Marshal(asn1.RawValue{Class: {{.Tag.Class}}, Tag: {{.Tag.Number}}, IsCompound: true, Bytes: data})
+		{{- else}}
+		return {{.Choice.Marshal}}(v.Value)
+		{{- end}}
+	{{- end}}
+	default:
+		return nil, fmt.Errorf("unknown alternative of {{.Name}}: %T", v)
+	}
+}
+
+func Unmarshal{{.Name}}(data []byte) ({{.Name}}, []byte, error) {
+	var raw asn1.RawValue
+	rest, err := asn1.Unmarshal(data, &raw)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch {
+	{{- range .Unmarshal}}
+	case {{.Match}}:
+		var v {{.Name}}
+		{{- if .Any}}
+		v.Value = raw
+		{{- else if not .Choice}}
+		if _, err := asn1.UnmarshalWithParams(raw.FullBytes, &v.Value, {{printf "%q" .Params}}); err != nil {
+			return nil, nil, err
+		}
+		{{- else}}
+		if v.Value, _, err = {{.Choice.Unmarshal}}(raw.{{if .Tag}}Bytes{{else}}FullBytes{{end}}); err != nil {
+			return nil, nil, err
+		}
+		{{- end}}
+		return v, rest, nil
+	{{- end}}
+	}
+	return nil, nil, fmt.Errorf("unexpected tag of {{.Name}}: class %v, tag %v", raw.Class, raw.Tag)
+}
+`))
+
+type choiceTemplateParams struct {
+	Name string
+	// DER is set if alternatives have DER encoding methods.
+	DER bool
+	// PER is set if alternatives have PER encoding methods.
+	PER bool
+	// OER is set if alternatives have OER encoding methods.
+	OER bool
+	// JER is set if alternatives have JER encoding methods.
+	JER bool
+	// XER is set if alternatives have XER encoding methods.
+	XER bool
+	// Validate is set if alternatives have Validate methods.
+	Validate     bool
+	Alternatives []choiceAlternativeParams
+	// Unmarshal holds alternatives in order they should be matched, with alternatives matching any tag last.
+	Unmarshal []choiceAlternativeParams
+}
+
+type choiceAlternativeParams struct {
+	// Name is a name of the wrapper type.
+	Name      string
+	ValueType string
+	// Params are encoding/asn1 parameters of the value.
+	Params string
+	// Match is a go expression matching raw value of the alternative by tag.
+	Match string
+	// Choice is set if value is a CHOICE itself, which is encoded using its own functions.
+	Choice *nestedChoiceParams
+	// Tag is set if value is a tagged CHOICE.
+	Tag *choiceTagParams
+	// Any is set if value is of open type, which is unmarshalled as asn1.RawValue.
+	Any bool
+	// alternative is definition of the alternative.
+	alternative NamedType
+}
+
+type nestedChoiceParams struct {
+	Marshal   string
+	Unmarshal string
+}
+
+type choiceTagParams struct {
+	Class  string
+	Number int
+}
+
+// asn1Tag is a tag of encoded value.
+type asn1Tag struct {
+	// Class is one of CLASS_ constants.
+	Class  int
+	Number int
+}
+
+// goClass returns encoding/asn1 constant for tag class.
+func (t asn1Tag) goClass() string {
+	switch t.Class {
+	case CLASS_UNIVERSAL:
+		return "asn1.ClassUniversal"
+	case CLASS_APPLICATION:
+		return "asn1.ClassApplication"
+	case CLASS_PRIVATE:
+		return "asn1.ClassPrivate"
+	default:
+		return "asn1.ClassContextSpecific"
+	}
+}
+
+// generateChoiceDecls generates sealed interface for the CHOICE type, wrapper types for its alternatives,
+// and functions to marshal and unmarshal it.
+func (ctx *moduleContext) generateChoiceDecls(reference TypeReference, t ChoiceType) []goast.Decl {
+	ctx.requireModule("encoding/asn1")
+	ctx.requireModule("fmt")
+	name := goifyName(reference.Name())
+	params := choiceTemplateParams{Name: name, DER: ctx.params.Type&GEN_DER != 0, PER: ctx.params.Type&GEN_PER != 0, OER: ctx.params.Type&GEN_OER != 0, JER: ctx.params.Type&GEN_JER != 0, XER: ctx.params.Type&GEN_XER != 0, Validate: ctx.params.Type&GEN_VALIDATE != 0}
+	var matchAny []choiceAlternativeParams
+	for _, alternative := range t.Alternatives() {
+		alt := choiceAlternativeParams{
+			Name:        name + goifyName(alternative.Identifier.Name()),
+			ValueType:   exprString(ctx.generateTypeExpr(alternative.Type)),
+			alternative: alternative,
+		}
+		if nested := ctx.choiceTypeName(alternative.Type); nested != nil {
+			alt.Choice = &nestedChoiceParams{
+				Marshal:   exprString(choiceMemberExpr(nested, "Marshal", "")),
+				Unmarshal: exprString(choiceMemberExpr(nested, "Unmarshal", "")),
+			}
+			if isTaggedType(alternative.Type) {
+				tags, _ := ctx.outermostTags(alternative.Type, nil)
+				if len(tags) == 1 {
+					alt.Tag = &choiceTagParams{Class: tags[0].goClass(), Number: tags[0].Number}
+				}
+			}
+		} else {
+			alt.Params = strings.Join(ctx.asn1FieldParams(NamedComponentType{NamedType: alternative}), ",")
+		}
+		tags, isAny := ctx.outermostTags(alternative.Type, nil)
+		if isAny {
+			alt.Match = "true"
+			_, alt.Any = ctx.removeWrapperTypes(alternative.Type).(AnyType)
+			matchAny = append(matchAny, alt)
+		} else {
+			var conditions []string
+			for _, tag := range tags {
+				conditions = append(conditions, fmt.Sprintf("raw.Class == %v && raw.Tag == %v", tag.goClass(), tag.Number))
+			}
+			if len(conditions) == 0 {
+				ctx.appendError(fmt.Errorf("type %v: can not determine tag of alternative %v", reference, alternative.Identifier))
+				continue
+			}
+			alt.Match = strings.Join(conditions, " || ")
+			params.Unmarshal = append(params.Unmarshal, alt)
+		}
+		params.Alternatives = append(params.Alternatives, alt)
+	}
+	params.Unmarshal = append(params.Unmarshal, matchAny...)
+	var buf bytes.Buffer
+	if err := choiceTemplate.Execute(&buf, params); err != nil {
+		ctx.appendError(fmt.Errorf("type %v: %w", reference, err))
+		return nil
+	}
+	decls, err := parseGoDecls(buf.String())
+	if err != nil {
+		ctx.appendError(fmt.Errorf("type %v: failed to parse generated code: %w", reference, err))
+		return nil
+	}
+	if params.DER {
+		// CHOICE can only be tagged explicitly, so tag passed to EncodeDER of alternatives is ignored
+		for _, alt := range params.Alternatives {
+			decls = append(decls, ctx.generateDERMethods(alt.Name, "v.Value", alt.alternative.Type, derTag{})...)
+		}
+		decls = append(decls, ctx.generateBERChoiceDecls(name, params.Alternatives)...)
+	}
+	if params.PER {
+		decls = append(decls, ctx.generatePERChoiceDecls(name, t, params.Alternatives)...)
+	}
+	if params.OER {
+		decls = append(decls, ctx.generateOERChoiceDecls(name, t, params.Alternatives)...)
+	}
+	if params.JER {
+		decls = append(decls, ctx.generateJERChoiceDecls(name, t, params.Alternatives)...)
+	}
+	if params.XER {
+		decls = append(decls, ctx.generateXERChoiceDecls(name, reference.Name(), t, params.Alternatives)...)
+	}
+	if params.Validate {
+		decls = append(decls, ctx.generateValidateChoiceDecls(params.Alternatives)...)
+	}
+	return decls
+}
+
+// choiceTypeName returns name of go interface generated for CHOICE type which t refers to,
+// or nil if t is not a reference to CHOICE type.
+func (ctx *moduleContext) choiceTypeName(t Type) goast.Expr {
+	ref, ok := ctx.removeWrapperTypes(t).(TypeReference)
+	if !ok {
+		return nil
+	}
+	leaf, leafCtx, err := ctx.lookupLeafType(ref)
+	if err != nil || leaf.Type == nil {
+		return nil
+	}
+	if _, ok := ctx.removeWrapperTypes(leaf.Type).(ChoiceType); !ok {
+		return nil
+	}
+	return leafCtx.qualifiedTypeIdent(leaf.TypeReference)
+}
+
+// choiceMemberExpr returns name of the declaration generated for CHOICE type, e.g. wrapper type of alternative,
+// or Marshal function. Name of CHOICE type can be qualified with package name.
+func choiceMemberExpr(choiceName goast.Expr, prefix, suffix string) goast.Expr {
+	switch name := choiceName.(type) {
+	case *goast.SelectorExpr:
+		return &goast.SelectorExpr{X: name.X, Sel: goast.NewIdent(prefix + name.Sel.Name + suffix)}
+	case *goast.Ident:
+		return goast.NewIdent(prefix + name.Name + suffix)
+	default:
+		return choiceName
+	}
+}
+
+// outermostTags returns tags which encoding of the value of type t can start with.
+// Returns true if value can have any tag, e.g. for ANY type.
+func (ctx *moduleContext) outermostTags(t Type, visited []string) ([]asn1Tag, bool) {
+	switch tt := t.(type) {
+	case TaggedType:
+		tag, err := ctx.tagOf(tt)
+		if err != nil {
+			ctx.appendError(err)
+			return nil, false
+		}
+		return []asn1Tag{tag}, false
+	case ConstraintedType:
+		return ctx.outermostTags(tt.Type, visited)
+	case TypeReference:
+		name := string(ctx.moduleName) + "." + tt.Name()
+		if slices.Contains(visited, name) {
+			ctx.appendError(fmt.Errorf("type %v: reference cycle %v", tt, strings.Join(append(visited, name), " -> ")))
+			return nil, false
+		}
+		assignment, assignmentCtx, err := ctx.lookupTypeAssignment(tt)
+		if err != nil {
+			ctx.appendError(err)
+			return nil, false
+		}
+		if assignment == nil {
+			if useful := ctx.lookupUsefulType(tt); useful != nil {
+				return ctx.outermostTags(useful, visited)
+			}
+			ctx.appendError(fmt.Errorf("can not resolve TypeReference %v", tt.Name()))
+			return nil, false
+		}
+		return assignmentCtx.outermostTags(assignment.Type, append(visited, name))
+	case ChoiceType:
+		var res []asn1Tag
+		for _, alternative := range tt.Alternatives() {
+			tags, isAny := ctx.outermostTags(alternative.Type, visited)
+			if isAny {
+				return nil, true
+			}
+			res = append(res, tags...)
+		}
+		return res, false
+	case AnyType:
+		return nil, true
+	default:
+		if number, ok := universalTag(t); ok {
+			return []asn1Tag{{Class: CLASS_UNIVERSAL, Number: number}}, false
+		}
+		return nil, false
+	}
+}
+
+// hoistInlineTypes replaces CHOICE, ENUMERATED and BIT STRING types with named bits nested in other types
+// with references to new type assignments, so that every CHOICE is generated as named go interface,
+// and every ENUMERATED and BIT STRING with named bits as named go type with constants.
+// Types are hoisted only if params select such representation, e.g. CHOICE with ChoiceReprInterface.
+// Names of new types are derived from the name of enclosing type and the component, e.g. CHOICE in component
+// value of type Item is named ItemValue.
+func hoistInlineTypes(body ModuleBody, params GenParams) ModuleBody {
+	h := typeHoister{used: make(map[string]bool), params: params}
+	for _, assignment := range body.AssignmentList {
+		h.used[assignment.Reference().Name()] = true
+	}
+	assignments := make(AssignmentList, 0, len(body.AssignmentList))
+	for _, assignment := range body.AssignmentList {
+		h.hoisted = nil
+		switch a := assignment.(type) {
+		case TypeAssignment:
+			a.Type = h.hoist(a.TypeReference.Name(), a.Type, false)
+			assignment = a
+		case ValueAssignment:
+			a.Type = h.hoist(goifyName(a.ValueReference.Name()), a.Type, true)
+			assignment = a
+		}
+		assignments = append(assignments, assignment)
+		assignments = append(assignments, h.hoisted...)
+	}
+	body.AssignmentList = assignments
+	return body
+}
+
+type typeHoister struct {
+	params GenParams
+	// used holds names of types defined in the module.
+	used map[string]bool
+	// hoisted holds assignments created for the current assignment.
+	hoisted AssignmentList
+}
+
+// hoist replaces CHOICE, ENUMERATED and BIT STRING types with named bits nested in t. If nested is set, t itself is replaced too.
+func (h *typeHoister) hoist(name string, t Type, nested bool) Type {
+	switch tt := t.(type) {
+	case EnumeratedType:
+		if !nested || h.params.EnumRepr != EnumReprType {
+			return t
+		}
+		ref := h.uniqueName(name)
+		h.hoisted = append(h.hoisted, TypeAssignment{TypeReference: ref, Type: tt})
+		return ref
+	case BitStringType:
+		if !nested || len(tt.NamedBits) == 0 || h.params.BitStringRepr != BitStringReprType {
+			return t
+		}
+		ref := h.uniqueName(name)
+		h.hoisted = append(h.hoisted, TypeAssignment{TypeReference: ref, Type: tt})
+		return ref
+	case ChoiceType:
+		if h.params.ChoiceRepr != ChoiceReprInterface {
+			return t
+		}
+		var ref TypeReference
+		index := len(h.hoisted)
+		if nested {
+			// reserve name and position before nested types are hoisted, so that they are named after it
+			ref = h.uniqueName(name)
+			name = ref.Name()
+			h.hoisted = append(h.hoisted, nil)
+		}
+		hoisted := ChoiceType{Extensible: tt.Extensible}
+		for _, alternative := range tt.AlternativeTypeList {
+			hoisted.AlternativeTypeList = append(hoisted.AlternativeTypeList, h.hoistNamedType(name, alternative))
+		}
+		for _, ext := range tt.ExtensionTypes {
+			switch e := ext.(type) {
+			case NamedType:
+				ext = h.hoistNamedType(name, e)
+			case ExtensionAdditionAlternativesGroup:
+				alternatives := make([]NamedType, 0, len(e.Alternatives))
+				for _, alternative := range e.Alternatives {
+					alternatives = append(alternatives, h.hoistNamedType(name, alternative))
+				}
+				e.Alternatives = alternatives
+				ext = e
+			}
+			hoisted.ExtensionTypes = append(hoisted.ExtensionTypes, ext)
+		}
+		if !nested {
+			return hoisted
+		}
+		h.hoisted[index] = TypeAssignment{TypeReference: ref, Type: hoisted}
+		return ref
+	case SequenceType:
+		tt.Components, tt.ExtensionAdditions = h.hoistComponents(name, tt.Components, tt.ExtensionAdditions)
+		return tt
+	case SetType:
+		tt.Components, tt.ExtensionAdditions = h.hoistComponents(name, tt.Components, tt.ExtensionAdditions)
+		return tt
+	case SequenceOfType:
+		tt.Type = h.hoistElement(name, tt.Type)
+		return tt
+	case SetOfType:
+		tt.Type = h.hoistElement(name, tt.Type)
+		return tt
+	case TaggedType:
+		tt.Type = h.hoist(name, tt.Type, nested)
+		return tt
+	case ConstraintedType:
+		tt.Type = h.hoist(name, tt.Type, nested)
+		return tt
+	default:
+		return t
+	}
+}
+
+func (h *typeHoister) hoistNamedType(parent string, t NamedType) NamedType {
+	t.Type = h.hoist(parent+goifyName(t.Identifier.Name()), t.Type, true)
+	return t
+}
+
+func (h *typeHoister) hoistElement(parent string, t Type) Type {
+	if named, ok := t.(NamedType); ok {
+		return h.hoistNamedType(parent, named)
+	}
+	return h.hoist(parent+"Item", t, true)
+}
+
+func (h *typeHoister) hoistComponents(parent string, components ComponentTypeList, additions ExtensionAdditions) (ComponentTypeList, ExtensionAdditions) {
+	hoistList := func(list ComponentTypeList) ComponentTypeList {
+		res := make(ComponentTypeList, 0, len(list))
+		for _, component := range list {
+			if named, ok := component.(NamedComponentType); ok {
+				named.NamedType = h.hoistNamedType(parent, named.NamedType)
+				component = named
+			}
+			res = append(res, component)
+		}
+		return res
+	}
+	var hoistedAdditions ExtensionAdditions
+	for _, addition := range additions {
+		switch a := addition.(type) {
+		case ComponentType:
+			addition = hoistList(ComponentTypeList{a})[0]
+		case ExtensionAdditionGroup:
+			a.Components = hoistList(a.Components)
+			addition = a
+		}
+		hoistedAdditions = append(hoistedAdditions, addition)
+	}
+	return hoistList(components), hoistedAdditions
+}
+
+// uniqueName returns type reference with the name which is not used in the module yet.
+func (h *typeHoister) uniqueName(name string) TypeReference {
+	res := name
+	for i := 2; h.used[res]; i++ {
+		res = fmt.Sprintf("%v%v", name, i)
+	}
+	h.used[res] = true
+	return TypeReference(res)
+}
+
+// exprString returns go source of the expression.
+// Errors of generating the expression are expected to be reported already, so they are ignored.
+func exprString(expr goast.Expr) string {
+	var buf bytes.Buffer
+	if err := goprint.Fprint(&buf, gotoken.NewFileSet(), expr); err != nil {
+		return "invalid"
+	}
+	return buf.String()
+}
+
+// parseGoDecls parses go declarations from the source.
+func parseGoDecls(src string) ([]goast.Decl, error) {
+	file, err := goparser.ParseFile(gotoken.NewFileSet(), "", "package generated\n"+src, 0)
+	if err != nil {
+		return nil, err
+	}
+	return file.Decls, nil
+}
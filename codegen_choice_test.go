@@ -0,0 +1,208 @@
+package asn1go
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestHoistInlineChoices(t *testing.T) {
+	m := parseModule(t, `
+	TestSpec DEFINITIONS IMPLICIT TAGS ::= BEGIN
+		Msg ::= SEQUENCE {
+			body CHOICE { a INTEGER, b CHOICE { x BOOLEAN, y NULL } },
+			items SEQUENCE OF CHOICE { c INTEGER, d BOOLEAN }
+		}
+		MsgBody ::= INTEGER
+		value CHOICE { e INTEGER, f BOOLEAN } ::= e : 1
+	END
+	`)
+	expected := AssignmentList{
+		TypeAssignment{TypeReference: "Msg", Type: SequenceType{Components: ComponentTypeList{
+			NamedComponentType{NamedType: NamedType{Identifier: "body", Type: TypeReference("MsgBody2")}},
+			NamedComponentType{NamedType: NamedType{Identifier: "items", Type: SequenceOfType{Type: TypeReference("MsgItemsItem")}}},
+		}}},
+		TypeAssignment{TypeReference: "MsgBody2", Type: ChoiceType{AlternativeTypeList: []NamedType{
+			{Identifier: "a", Type: IntegerType{}},
+			{Identifier: "b", Type: TypeReference("MsgBody2B")},
+		}}},
+		TypeAssignment{TypeReference: "MsgBody2B", Type: ChoiceType{AlternativeTypeList: []NamedType{
+			{Identifier: "x", Type: BooleanType{}},
+			{Identifier: "y", Type: NullType{}},
+		}}},
+		TypeAssignment{TypeReference: "MsgItemsItem", Type: ChoiceType{AlternativeTypeList: []NamedType{
+			{Identifier: "c", Type: IntegerType{}},
+			{Identifier: "d", Type: BooleanType{}},
+		}}},
+		TypeAssignment{TypeReference: "MsgBody", Type: IntegerType{}},
+		ValueAssignment{ValueReference: "value", Type: TypeReference("Value"), Value: ChoiceValue{Identifier: "e", Value: Number(1)}},
+		TypeAssignment{TypeReference: "Value", Type: ChoiceType{AlternativeTypeList: []NamedType{
+			{Identifier: "e", Type: IntegerType{}},
+			{Identifier: "f", Type: BooleanType{}},
+		}}},
+	}
+	if diff := cmp.Diff(expected, hoistInlineTypes(m.ModuleBody, GenParams{ChoiceRepr: ChoiceReprInterface}).AssignmentList); diff != "" {
+		t.Errorf("Hoisted assignments did not match expected, diff (-want, +got): %v", diff)
+	}
+}
+
+func TestHoistInlineNamedBits(t *testing.T) {
+	m := parseModule(t, `
+	TestSpec DEFINITIONS IMPLICIT TAGS ::= BEGIN
+		Msg ::= SEQUENCE { mode [0] BIT STRING { read(0), write(1) }, mask BIT STRING }
+	END
+	`)
+	expected := parseModule(t, `
+	TestSpec DEFINITIONS IMPLICIT TAGS ::= BEGIN
+		Msg ::= SEQUENCE { mode [0] MsgMode, mask BIT STRING }
+		MsgMode ::= BIT STRING { read(0), write(1) }
+	END
+	`)
+	if diff := cmp.Diff(expected.ModuleBody.AssignmentList, hoistInlineTypes(m.ModuleBody, GenParams{BitStringRepr: BitStringReprType}).AssignmentList); diff != "" {
+		t.Errorf("Hoisted assignments did not match expected, diff (-want, +got): %v", diff)
+	}
+}
+
+func TestChoiceInterface(t *testing.T) {
+	m := parseModule(t, `
+	TestSpec DEFINITIONS IMPLICIT TAGS ::= BEGIN
+		Value ::= CHOICE { num [0] INTEGER, inner [1] Inner }
+		Inner ::= CHOICE { flag BOOLEAN, text UTF8String }
+		value Value ::= inner : flag : TRUE
+	END
+	`)
+	expected := `package TestSpec
+
+import "encoding/asn1"
+import "fmt"
+
+type Value interface{ isValue() }
+type ValueNum struct{ Value int64 }
+
+func (ValueNum) isValue() {
+}
+
+type ValueInner struct{ Value Inner }
+
+func (ValueInner) isValue() {
+}
+func MarshalValue(v Value) ([]byte, error) {
+	switch v := v.(type) {
+	case ValueNum:
+		return asn1.MarshalWithParams(v.Value, "tag:0")
+	case ValueInner:
+		data, err := MarshalInner(v.Value)
+		if err != nil {
+			return nil, err
+		}
+		return asn1.Marshal(asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 1, IsCompound: true, Bytes: data})
+	default:
+		return nil, fmt.Errorf("unknown alternative of Value: %T", v)
+	}
+}
+func UnmarshalValue(data []byte) (Value, []byte, error) {
+	var raw asn1.RawValue
+	rest, err := asn1.Unmarshal(data, &raw)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch {
+	case raw.Class == asn1.ClassContextSpecific && raw.Tag == 0:
+		var v ValueNum
+		if _, err := asn1.UnmarshalWithParams(raw.FullBytes, &v.Value, "tag:0"); err != nil {
+			return nil, nil, err
+		}
+		return v, rest, nil
+	case raw.Class == asn1.ClassContextSpecific && raw.Tag == 1:
+		var v ValueInner
+		if v.Value, _, err = UnmarshalInner(raw.Bytes); err != nil {
+			return nil, nil, err
+		}
+		return v, rest, nil
+	}
+	return nil, nil, fmt.Errorf("unexpected tag of Value: class %v, tag %v", raw.Class, raw.Tag)
+}
+
+type Inner interface{ isInner() }
+type InnerFlag struct{ Value bool }
+
+func (InnerFlag) isInner() {
+}
+
+type InnerText struct{ Value string }
+
+func (InnerText) isInner() {
+}
+func MarshalInner(v Inner) ([]byte, error) {
+	switch v := v.(type) {
+	case InnerFlag:
+		return asn1.MarshalWithParams(v.Value, "")
+	case InnerText:
+		return asn1.MarshalWithParams(v.Value, "utf8")
+	default:
+		return nil, fmt.Errorf("unknown alternative of Inner: %T", v)
+	}
+}
+func UnmarshalInner(data []byte) (Inner, []byte, error) {
+	var raw asn1.RawValue
+	rest, err := asn1.Unmarshal(data, &raw)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch {
+	case raw.Class == asn1.ClassUniversal && raw.Tag == 1:
+		var v InnerFlag
+		if _, err := asn1.UnmarshalWithParams(raw.FullBytes, &v.Value, ""); err != nil {
+			return nil, nil, err
+		}
+		return v, rest, nil
+	case raw.Class == asn1.ClassUniversal && raw.Tag == 12:
+		var v InnerText
+		if _, err := asn1.UnmarshalWithParams(raw.FullBytes, &v.Value, "utf8"); err != nil {
+			return nil, nil, err
+		}
+		return v, rest, nil
+	}
+	return nil, nil, fmt.Errorf("unexpected tag of Inner: class %v, tag %v", raw.Class, raw.Tag)
+}
+
+var ValValue Value = ValueInner{Value: InnerFlag{Value: true}}
+`
+	buf := &bytes.Buffer{}
+	if err := NewCodeGenerator(GenParams{ChoiceRepr: ChoiceReprInterface}).Generate(*m, buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(formatGoSource(t, expected), formatGoSource(t, buf.String())); diff != "" {
+		t.Errorf("Generated module did not match expected, diff (-want, +got): %v", diff)
+	}
+}
+
+func TestChoiceReprDefault(t *testing.T) {
+	m := parseModule(t, `
+	TestSpec DEFINITIONS AUTOMATIC TAGS ::= BEGIN
+		Value ::= CHOICE { number INTEGER, flag BOOLEAN }
+	END
+	`)
+	testCases := []struct {
+		name     string
+		params   GenParams
+		expected string
+	}{
+		// encoding/asn1 can only decode CHOICE into asn1.RawValue
+		{name: "without encoding methods", params: GenParams{}, expected: "type Value = asn1.RawValue"},
+		{name: "with encoding methods", params: GenParams{Type: GEN_DER}, expected: "type Value interface {"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := generateString(*m, tc.params)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !strings.Contains(got, tc.expected) {
+				t.Errorf("Generated module does not contain %v:\n%v", tc.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package asn1go
+
+// contentsOf returns the type of values contained in OCTET STRING type t, if t is constrained with
+// contents constraint, see X.682, section 11. Values of such types are represented with go types
+// of contained values, which generated methods encode as contents of OCTET STRING.
+//
+// Returns false if values are represented with bytes, i.e. if encoding is specified with ENCODED BY,
+// constrained type is not OCTET STRING, or no encoding methods are generated to decode contents with.
+func (ctx *moduleContext) contentsOf(t ConstraintedType) (Type, bool) {
+	if ctx.params.Type&(GEN_DER|GEN_PER|GEN_OER|GEN_JER|GEN_XER) == 0 {
+		return nil, false
+	}
+	contents, ok := t.Constraint.ConstraintSpec.(ContentsConstraint)
+	if !ok || contents.Type == nil || contents.EncodedBy != nil {
+		return nil, false
+	}
+	// tags of OCTET STRING type are not allowed, so that encoders only need to replace its tag
+	inner := t.Type
+	for {
+		constrained, ok := inner.(ConstraintedType)
+		if !ok {
+			break
+		}
+		inner = constrained.Type
+	}
+	if _, ok := inner.(OctetStringType); !ok {
+		return nil, false
+	}
+	return contents.Type, true
+}
+
+// containedType returns the type of values contained in values of type t, which can be tagged
+// or referenced, and context of the module where it is declared. See contentsOf.
+func (ctx *moduleContext) containedType(t Type) (Type, *moduleContext, bool) {
+	switch tt := t.(type) {
+	case TaggedType:
+		return ctx.containedType(tt.Type)
+	case NamedType:
+		return ctx.containedType(tt.Type)
+	case ConstraintedType:
+		if contained, ok := ctx.contentsOf(tt); ok {
+			return contained, ctx, true
+		}
+		return ctx.containedType(tt.Type)
+	case TypeReference:
+		assignment, assignmentCtx, err := ctx.lookupTypeAssignment(tt)
+		if err != nil || assignment == nil {
+			return nil, ctx, false
+		}
+		return assignmentCtx.containedType(assignment.Type)
+	default:
+		return nil, ctx, false
+	}
+}
@@ -0,0 +1,616 @@
+package asn1go
+
+import (
+	"bytes"
+	"fmt"
+	goast "go/ast"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// derPackage is import path of runtime package used by generated DER encoders.
+const derPackage = "github.com/chemikadze/asn1go/der"
+
+// derMethodsTemplate generates methods encoding the type with DER.
+var derMethodsTemplate = template.Must(template.New("der").Parse(`
+func (v {{.Name}}) MarshalDER() ([]byte, error) {
+	var e der.Encoder
+	if err := v.EncodeDER(&e, der.Tag{}); err != nil {
+		return nil, err
+	}
+	return e.Bytes(), nil
+}
+
+func (v {{.Name}}) EncodeDER(e *der.Encoder, tag der.Tag) error {
+{{.Body -}}
+	return nil
+}
+`))
+
+// derTag is go expression of the tag which replaces the outermost tag of the encoding.
+type derTag struct {
+	expr string
+	// dynamic is set if expression can evaluate to zero tag, in which case own tag of the type is used.
+	dynamic bool
+}
+
+// or returns go expression of the tag, using def if tag is not set.
+func (t derTag) or(def string) string {
+	switch {
+	case t.expr == "":
+		return def
+	case t.dynamic:
+		return t.expr + ".Or(" + def + ")"
+	default:
+		return t.expr
+	}
+}
+
+// orZero returns go expression of the tag, which is zero tag if tag is not set.
+func (t derTag) orZero() string {
+	return t.or("der.Tag{}")
+}
+
+// derClasses maps CLASS_ constants to tag classes of der package.
+var derClasses = map[int]string{
+	CLASS_UNIVERSAL:        "der.ClassUniversal",
+	CLASS_APPLICATION:      "der.ClassApplication",
+	CLASS_CONTEXT_SPECIFIC: "der.ClassContextSpecific",
+	CLASS_PRIVATE:          "der.ClassPrivate",
+}
+
+// derExpr returns go expression of the tag for der package.
+func (t asn1Tag) derExpr() string {
+	return fmt.Sprintf("der.Tag{Class: %v, Number: %v}", derClasses[t.Class], t.Number)
+}
+
+// hasEncodingMethods returns true if go type generated for t has methods of enabled encodings, e.g. DER or PER.
+// Methods are generated for SEQUENCE, SET and CHOICE types, and are shared by type aliases.
+func (ctx *moduleContext) hasEncodingMethods(t Type) bool {
+	switch tt := ctx.removeWrapperTypes(t).(type) {
+	case SequenceType, SetType:
+		return true
+	case ChoiceType:
+		return ctx.params.ChoiceRepr == ChoiceReprInterface
+	case TypeReference:
+		leaf, leafCtx, err := ctx.lookupLeafType(tt)
+		if err != nil || leaf.Type == nil {
+			return false
+		}
+		return leafCtx.hasEncodingMethods(leaf.Type)
+	default:
+		return false
+	}
+}
+
+// isDefinedReference returns true if type t of type assignment is a reference to SEQUENCE or SET type, which is
+// declared as defined type rather than alias, and does not share methods with the referenced type.
+// These are tagged references when DER methods are generated, so that their methods can encode and decode the tag,
+// and constrained references when Validate methods are generated, so that their methods can check the constraint.
+func (ctx *moduleContext) isDefinedReference(t Type) bool {
+	ref, ok := ctx.removeWrapperTypes(t).(TypeReference)
+	if !ok || !ctx.hasEncodingMethods(ref) || ctx.choiceTypeName(ref) != nil {
+		return false
+	}
+	return ctx.params.Type&GEN_DER != 0 && isTaggedType(t) || ctx.isValidatedType(t)
+}
+
+// generateDERDecls generates DER encoding and BER decoding methods of the type declared by decl.
+//
+// Types which are not SEQUENCE, SET or CHOICE are generated as aliases of go built-in types, and are encoded
+// as part of enclosing types. References declared as defined types, see isDefinedReference,
+// get methods encoding values of referenced types.
+func (ctx *moduleContext) generateDERDecls(a TypeAssignment, decl goast.Decl) []goast.Decl {
+	name := goifyName(a.TypeReference.Name())
+	switch t := ctx.removeWrapperTypes(a.Type).(type) {
+	case SequenceType, SetType:
+		tag := derTag{expr: "tag", dynamic: true}
+		return append(ctx.generateDERMethods(name, "v", a.Type, tag), ctx.generateBERMethods(name, "v", a.Type, tag)...)
+	case TypeReference:
+		if isTaggedType(a.Type) && ctx.hasEncodingMethods(t) && ctx.choiceTypeName(t) != nil {
+			ctx.appendError(fmt.Errorf("type %v: tagged references to CHOICE types are not supported by DER encoder", a.TypeReference))
+			return nil
+		}
+		if !ctx.isDefinedReference(a.Type) {
+			return nil
+		}
+		if genDecl, ok := decl.(*goast.GenDecl); ok {
+			genDecl.Specs[0].(*goast.TypeSpec).Assign = 0
+		}
+		typeName := exprString(ctx.generateTypeExpr(t))
+		tag := derTag{expr: "tag", dynamic: true}
+		return append(ctx.generateDERMethods(name, typeName+"(v)", a.Type, tag), ctx.generateBERMethods(name, "(*"+typeName+")(v)", a.Type, tag)...)
+	default:
+		return nil
+	}
+}
+
+// generateDERMethods generates MarshalDER and EncodeDER methods of go type typeName,
+// which encode go expression expr of type t.
+func (ctx *moduleContext) generateDERMethods(typeName string, expr string, t Type, tag derTag) []goast.Decl {
+	ctx.requireModule(derPackage)
+	g := &derEncoderGen{}
+	g.encode(ctx, expr, t, tag)
+	var buf bytes.Buffer
+	if err := derMethodsTemplate.Execute(&buf, map[string]string{"Name": typeName, "Body": g.buf.String()}); err != nil {
+		ctx.appendError(fmt.Errorf("type %v: %w", typeName, err))
+		return nil
+	}
+	decls, err := parseGoDecls(buf.String())
+	if err != nil {
+		ctx.appendError(fmt.Errorf("type %v: failed to parse generated code: %w", typeName, err))
+		return nil
+	}
+	return decls
+}
+
+// derEncoderGen generates go statements encoding values with DER.
+type derEncoderGen struct {
+	buf strings.Builder
+	// vars holds number of local variables declared with given prefix.
+	vars map[string]int
+	// inlined holds type references which are being inlined, to detect reference cycles.
+	inlined []string
+	// integer is go type of INTEGER value being generated, if it is selected by constraints, see constrain.
+	integer string
+}
+
+func (g *derEncoderGen) line(format string, args ...any) {
+	fmt.Fprintf(&g.buf, "\t"+format+"\n", args...)
+}
+
+// check writes a call returning error.
+func (g *derEncoderGen) check(format string, args ...any) {
+	g.line("if err := "+format+"; err != nil {\n\t\treturn err\n\t}", args...)
+}
+
+// newVar returns name of a new local variable.
+func (g *derEncoderGen) newVar(prefix string) string {
+	if g.vars == nil {
+		g.vars = make(map[string]int)
+	}
+	g.vars[prefix]++
+	if n := g.vars[prefix]; n > 1 {
+		return fmt.Sprintf("%v%v", prefix, n)
+	}
+	return prefix
+}
+
+// encode writes statements appending encoding of go expression expr of type t to encoder e.
+func (g *derEncoderGen) encode(ctx *moduleContext, expr string, t Type, tag derTag) {
+	switch tt := t.(type) {
+	case TaggedType:
+		own, err := ctx.tagOf(tt)
+		if err != nil {
+			ctx.appendError(err)
+			return
+		}
+		outer := tag.or(own.derExpr())
+		if ctx.isExplicitTag(tt) {
+			start := g.newVar("start")
+			g.line("%v := e.BeginConstructed(%v)", start, outer)
+			g.encode(ctx, expr, tt.Type, derTag{})
+			g.line("e.End(%v)", start)
+		} else {
+			g.encode(ctx, expr, tt.Type, derTag{expr: outer})
+		}
+	case ConstraintedType:
+		if contained, ok := ctx.contentsOf(tt); ok {
+			g.line("if err := e.WriteContaining(%v, func(e *der.Encoder) error {", tag.or("der.TagOctetString"))
+			g.encode(ctx, expr, contained, derTag{})
+			g.line("return nil")
+			g.line("}); err != nil {\n\t\treturn err\n\t}")
+			return
+		}
+		g.constrain(ctx, tt)
+		g.encode(ctx, expr, tt.Type, tag)
+	case NamedType:
+		g.encode(ctx, expr, tt.Type, tag)
+	case TypeReference:
+		g.encodeReference(ctx, expr, tt, tag)
+	case SequenceType:
+		g.encodeComponents(ctx, expr, slices.Concat(tt.Components, tt.ExtensionAdditions.Components()), tag.or("der.TagSequence"), "End")
+	case SetType:
+		g.encodeComponents(ctx, expr, slices.Concat(tt.Components, tt.ExtensionAdditions.Components()), tag.or("der.TagSet"), "EndSet")
+	case SequenceOfType:
+		g.encodeElements(ctx, expr, tt.Type, tag.or("der.TagSequence"), "End")
+	case SetOfType:
+		g.encodeElements(ctx, expr, tt.Type, tag.or("der.TagSet"), "EndSetOf")
+	case ChoiceType:
+		switch {
+		case ctx.params.ChoiceRepr == ChoiceReprInterface:
+			ctx.appendError(fmt.Errorf("CHOICE type %#v should be declared by type assignment", t))
+		case ctx.hasTaggedAlternatives(tt):
+			g.line("e.WriteRawValue(%v)", expr)
+		case len(tt.AlternativeTypeList) == 1:
+			g.encode(ctx, expr, tt.AlternativeTypeList[0].Type, tag) // see generateChoiceType
+		default:
+			g.check("e.WriteAny(%v)", expr)
+		}
+	case AnyType:
+		g.check("e.WriteAny(%v)", expr)
+	case BooleanType:
+		g.line("e.WriteBoolean(%v, %v)", tag.or("der.TagBoolean"), expr)
+	case IntegerType:
+		if v, big := ctx.integerArg(expr, g.currentIntegerType(ctx)); big {
+			g.check("e.WriteBigInteger(%v, %v)", tag.or("der.TagInteger"), v)
+		} else {
+			g.line("e.WriteInteger(%v, %v)", tag.or("der.TagInteger"), v)
+		}
+	case EnumeratedType:
+		g.line("e.WriteInteger(%v, int64(%v))", tag.or("der.TagEnumerated"), expr)
+	case RealType:
+		g.line("e.WriteReal(%v, %v)", tag.or("der.TagReal"), expr)
+	case OctetStringType:
+		g.line("e.WriteOctetString(%v, %v)", tag.or("der.TagOctetString"), expr)
+	case BitStringType:
+		if len(tt.NamedBits) > 0 {
+			g.line("e.WriteNamedBitString(%v, %v)", tag.or("der.TagBitString"), ctx.bitStringExpr(tt, expr))
+		} else {
+			g.line("e.WriteBitString(%v, %v)", tag.or("der.TagBitString"), expr)
+		}
+	case NullType:
+		g.line("e.WriteNull(%v)", tag.or("der.TagNull"))
+	case ObjectIdentifierType:
+		g.check("e.WriteObjectIdentifier(%v, %v)", tag.or("der.TagObjectIdentifier"), expr)
+	case RestrictedStringType:
+		number, ok := restrictedStringTags[tt.LexType]
+		if !ok {
+			ctx.appendError(fmt.Errorf("restricted string type %#v is not supported by DER encoder", tt))
+			return
+		}
+		own := asn1Tag{Class: CLASS_UNIVERSAL, Number: number}.derExpr()
+		switch tt.LexType {
+		case BMPString:
+			g.check("e.WriteBMPString(%v, %v)", tag.or(own), expr)
+		case UniversalString:
+			g.line("e.WriteUniversalString(%v, %v)", tag.or(own), expr)
+		default:
+			g.line("e.WriteString(%v, %v)", tag.or(own), expr)
+		}
+	default:
+		ctx.appendError(fmt.Errorf("type %#v is not supported by DER encoder", t))
+	}
+}
+
+// encodeReference writes statements encoding value of referenced type. Types having DER methods
+// are encoded by calling them, and other types are encoded inline.
+func (g *derEncoderGen) encodeReference(ctx *moduleContext, expr string, t TypeReference, tag derTag) {
+	assignment, assignmentCtx, err := ctx.lookupTypeAssignment(t)
+	if err != nil {
+		ctx.appendError(err)
+		return
+	}
+	if assignment == nil {
+		switch t.Name() {
+		case GeneralizedTimeName:
+			g.check("e.WriteGeneralizedTime(%v, %v)", tag.or("der.TagGeneralizedTime"), expr)
+		case UTCTimeName:
+			g.check("e.WriteUTCTime(%v, %v)", tag.or("der.TagUTCTime"), expr)
+		default:
+			if useful := ctx.lookupUsefulType(t); useful != nil {
+				g.encode(ctx, expr, useful, tag)
+			} else {
+				ctx.appendError(fmt.Errorf("can not resolve TypeReference %v", t.Name()))
+			}
+		}
+		return
+	}
+	if assignmentCtx.hasEncodingMethods(assignment.Type) {
+		if ctx.choiceTypeName(t) != nil {
+			g.line("if %v == nil {\n\t\treturn der.ErrAbsentValue\n\t}", expr)
+		}
+		g.check("%v.EncodeDER(e, %v)", expr, tag.orZero())
+		return
+	}
+	name := string(assignmentCtx.moduleName) + "." + t.Name()
+	if slices.Contains(g.inlined, name) {
+		ctx.appendError(fmt.Errorf("type %v: reference cycle %v", t, strings.Join(append(g.inlined, name), " -> ")))
+		return
+	}
+	g.inlined = append(g.inlined, name)
+	g.encode(assignmentCtx, assignmentCtx.definedTypeValue(assignment.Type, expr), assignment.Type, tag)
+	g.inlined = g.inlined[:len(g.inlined)-1]
+}
+
+// encodeComponents writes statements encoding components of SEQUENCE or SET value.
+// Absent OPTIONAL components, and components equal to their DEFAULT values, are omitted.
+func (g *derEncoderGen) encodeComponents(ctx *moduleContext, expr string, components ComponentTypeList, tag string, end string) {
+	start := g.newVar("start")
+	g.line("%v := e.BeginConstructed(%v)", start, tag)
+	for _, component := range components {
+		named, ok := component.(NamedComponentType)
+		if !ok {
+			continue // COMPONENTS OF is reported as unsupported by structFromComponents
+		}
+		field, pointer := ctx.componentField(expr, named)
+		var condition string
+		switch {
+		case named.Default != nil:
+			condition = ctx.derNonDefaultCheck(field, named.NamedType, *named.Default)
+		case named.IsOptional:
+			condition = ctx.optionalPresenceCheck(field, pointer, named.NamedType.Type)
+		}
+		if condition != "" {
+			g.line("if %v {", condition)
+		}
+		g.encode(ctx, field, named.NamedType.Type, derTag{})
+		if condition != "" {
+			g.line("}")
+		}
+	}
+	g.line("e.%v(%v)", end, start)
+}
+
+// encodeElements writes statements encoding elements of SEQUENCE OF or SET OF value.
+func (g *derEncoderGen) encodeElements(ctx *moduleContext, expr string, t Type, tag string, end string) {
+	start := g.newVar("start")
+	elem := g.newVar("elem")
+	g.line("%v := e.BeginConstructed(%v)", start, tag)
+	g.line("for _, %v := range %v {", elem, expr)
+	g.encode(ctx, elem, t, derTag{})
+	g.line("}")
+	g.line("e.%v(%v)", end, start)
+}
+
+// derLeafType resolves type references in t. Returns nil type and name of the useful type
+// if t refers to it.
+func (ctx *moduleContext) derLeafType(t Type) (Type, *moduleContext, string) {
+	t = ctx.removeWrapperTypes(t)
+	ref, ok := t.(TypeReference)
+	if !ok {
+		return t, ctx, ""
+	}
+	leaf, leafCtx, err := ctx.lookupLeafType(ref)
+	if err != nil {
+		ctx.appendError(err)
+		return nil, ctx, ""
+	}
+	if leaf.Type == nil {
+		return nil, leafCtx, leaf.TypeReference.Name()
+	}
+	return leafCtx.removeWrapperTypes(leaf.Type), leafCtx, ""
+}
+
+// isPointerComponent returns true if OPTIONAL component is represented with pointer to go type of its values,
+// so that present values equal to zero value of the go type, e.g. FALSE, 0 or empty string, are not taken
+// for absent ones. Pointers are used if encoding methods are generated, as encoding/asn1 does not support them,
+// and treats OPTIONAL components with zero values as absent. Components of go types which can be nil,
+// see isNilableType, are not represented with pointers.
+func (ctx *moduleContext) isPointerComponent(named NamedComponentType) bool {
+	if !named.IsOptional || named.Default != nil || ctx.params.Type&genEncoders == 0 {
+		return false
+	}
+	return !ctx.isNilableType(named.NamedType.Type)
+}
+
+// isNilableType returns true if go type of values of t can be nil. OPTIONAL components of such types
+// are absent if they are nil, and decoders never leave present ones nil, see presentComponent.
+func (ctx *moduleContext) isNilableType(t Type) bool {
+	if contained, containedCtx, ok := ctx.containedType(t); ok {
+		return containedCtx.isNilableType(contained)
+	}
+	leaf, leafCtx, _ := ctx.derLeafType(t)
+	switch leaf.(type) {
+	case OctetStringType, SequenceOfType, SetOfType, ObjectIdentifierType, AnyType:
+		return true
+	case IntegerType:
+		return ctx.integerType(t) == "*big.Int"
+	case ChoiceType:
+		return leafCtx.params.ChoiceRepr == ChoiceReprInterface
+	default:
+		return false
+	}
+}
+
+// isSliceType returns true if go type of values of t is a slice, which is empty but not nil
+// if value without elements is present.
+func (ctx *moduleContext) isSliceType(t Type) bool {
+	if contained, containedCtx, ok := ctx.containedType(t); ok {
+		return containedCtx.isSliceType(contained)
+	}
+	leaf, _, _ := ctx.derLeafType(t)
+	switch leaf.(type) {
+	case OctetStringType, SequenceOfType, SetOfType, ObjectIdentifierType:
+		return true
+	default:
+		return false
+	}
+}
+
+// componentField returns go expression of value of the component of SEQUENCE or SET value expr.
+// If component is represented with pointer, see isPointerComponent, the expression dereferences the field,
+// and go expression of the field itself is returned as well.
+func (ctx *moduleContext) componentField(expr string, named NamedComponentType) (field string, pointer string) {
+	field = expr + "." + goifyName(named.NamedType.Identifier.Name())
+	if ctx.isPointerComponent(named) {
+		return "(*" + field + ")", field
+	}
+	return field, ""
+}
+
+// optionalPresenceCheck returns go condition which is true if OPTIONAL component with value field is present.
+// Pointer is go expression of the field if component is represented with pointer, see componentField.
+// If encoding methods are not generated, components equal to zero values of their go types are absent,
+// as encoding/asn1 treats them so.
+func (ctx *moduleContext) optionalPresenceCheck(field string, pointer string, t Type) string {
+	if pointer != "" {
+		return pointer + " != nil"
+	}
+	if ctx.params.Type&genEncoders != 0 && ctx.isNilableType(t) {
+		return field + " != nil"
+	}
+	return ctx.derNonZeroCheck(field, t)
+}
+
+// allocComponent returns go statement allocating value of the component represented with pointer,
+// which is used by decoders before decoding present component.
+func (ctx *moduleContext) allocComponent(pointer string, named NamedComponentType) string {
+	var stubBool bool
+	return fmt.Sprintf("%v = new(%v)", pointer, exprString(ctx.generateTypeBody(named.NamedType.Type, &stubBool)))
+}
+
+// presentComponent returns go statement which is used by decoders after decoding present OPTIONAL component
+// of slice type, so that decoded value without elements is not taken for absent one,
+// or empty string if the statement is not needed.
+func (ctx *moduleContext) presentComponent(field string, named NamedComponentType) string {
+	if !named.IsOptional || named.Default != nil || ctx.params.Type&genEncoders == 0 || !ctx.isSliceType(named.NamedType.Type) {
+		return ""
+	}
+	var stubBool bool
+	return fmt.Sprintf("if %v == nil {\n\t%v = %v{}\n}", field, field, exprString(ctx.generateTypeBody(named.NamedType.Type, &stubBool)))
+}
+
+// absentComponent returns go statement which is used by decoders if OPTIONAL component is not encoded,
+// so that decoded value does not keep the component of the value it held before,
+// or empty string if the statement is not needed.
+func (ctx *moduleContext) absentComponent(field string, pointer string, named NamedComponentType) string {
+	switch {
+	case !named.IsOptional || named.Default != nil:
+		return ""
+	case pointer != "":
+		return pointer + " = nil"
+	case ctx.params.Type&genEncoders != 0 && ctx.isNilableType(named.NamedType.Type):
+		return field + " = nil"
+	default:
+		return ""
+	}
+}
+
+// derNonZeroCheck returns go condition which is true if OPTIONAL component is present,
+// i.e. if it is not a zero value of its go type, as encoding/asn1 does.
+func (ctx *moduleContext) derNonZeroCheck(expr string, t Type) string {
+	if contained, containedCtx, ok := ctx.containedType(t); ok {
+		return containedCtx.derNonZeroCheck(expr, contained)
+	}
+	leaf, leafCtx, usefulName := ctx.derLeafType(t)
+	switch tt := leaf.(type) {
+	case nil:
+		if usefulName == GeneralizedTimeName || usefulName == UTCTimeName {
+			return "!" + expr + ".IsZero()"
+		}
+	case BooleanType:
+		return expr
+	case IntegerType:
+		if ctx.integerType(t) == "*big.Int" {
+			return expr + " != nil"
+		}
+		return expr + " != 0"
+	case EnumeratedType, RealType:
+		return expr + " != 0"
+	case RestrictedStringType:
+		return expr + ` != ""`
+	case OctetStringType, SequenceOfType, SetOfType, ObjectIdentifierType:
+		return "len(" + expr + ") != 0"
+	case BitStringType:
+		return expr + ".BitLength != 0"
+	case AnyType:
+		return expr + " != nil"
+	case ChoiceType:
+		if leafCtx.params.ChoiceRepr == ChoiceReprInterface {
+			return expr + " != nil"
+		}
+		if !leafCtx.hasTaggedAlternatives(tt) && len(tt.AlternativeTypeList) != 1 {
+			return expr + " != nil"
+		}
+	}
+	ctx.requireModule(derPackage)
+	return "!der.IsZero(" + expr + ")"
+}
+
+// derNonDefaultCheck returns go condition which is true if component with DEFAULT value should be encoded,
+// i.e. if its value is not equal to default. Nil big.Int values are treated as absent.
+func (ctx *moduleContext) derNonDefaultCheck(expr string, t NamedType, defaultValue Value) string {
+	return ctx.derDefaultComparison(expr, t, defaultValue, "!=")
+}
+
+// derDefaultComparison returns go condition comparing component value with its DEFAULT value using operator op,
+// which is either == or !=. Nil big.Int values are not equal to any value.
+// Values of types which can not be compared with go operators are compared with functions of der package.
+func (ctx *moduleContext) derDefaultComparison(expr string, t NamedType, defaultValue Value, op string) string {
+	def := ctx.derDefaultExpr(t, defaultValue)
+	if def == "" {
+		return ""
+	}
+	not := ""
+	if op == "!=" {
+		not = "!"
+	}
+	leaf, _, usefulName := ctx.derLeafType(t.Type)
+	switch tt := leaf.(type) {
+	case nil:
+		if usefulName == GeneralizedTimeName || usefulName == UTCTimeName {
+			return fmt.Sprintf("%v%v.Equal(%v)", not, expr, def)
+		}
+	case IntegerType:
+		if ctx.integerType(t.Type) == "*big.Int" {
+			return fmt.Sprintf("%v != nil && %v.Cmp(%v) %v 0", expr, expr, def, op)
+		}
+		return fmt.Sprintf("%v %v %v", expr, op, def)
+	case BooleanType, EnumeratedType, RealType, RestrictedStringType:
+		return fmt.Sprintf("%v %v %v", expr, op, def)
+	case SequenceOfType, SetOfType:
+		if isEmptyListValue(defaultValue) {
+			return fmt.Sprintf("len(%v) %v 0", expr, op)
+		}
+	case OctetStringType:
+		ctx.requireModule("bytes")
+		return fmt.Sprintf("%vbytes.Equal(%v, %v)", not, expr, def)
+	case ObjectIdentifierType:
+		return fmt.Sprintf("%v%v.Equal(%v)", not, expr, def)
+	case BitStringType:
+		ctx.requireModule(derPackage)
+		return fmt.Sprintf("%vder.BitStringEqual(%v, %v, %v)", not, ctx.bitStringExpr(tt, expr), ctx.bitStringExpr(tt, def), len(tt.NamedBits) > 0)
+	}
+	ctx.requireModule(derPackage)
+	return fmt.Sprintf("%vder.Equal(%v, %v)", not, expr, def)
+}
+
+// isEmptyListValue returns true if value is {}.
+func isEmptyListValue(v Value) bool {
+	switch vv := v.(type) {
+	case BracedValue:
+		return len(vv) == 0
+	case SequenceOfValue:
+		return len(vv) == 0
+	default:
+		return false
+	}
+}
+
+// derDefaultExpr returns go expression of DEFAULT value of the component.
+func (ctx *moduleContext) derDefaultExpr(t NamedType, defaultValue Value) string {
+	defaultExpr := ctx.valueToExpr(t.Identifier.Name()+" DEFAULT", ctx, t.Type, defaultValue)
+	if defaultExpr == nil {
+		ctx.appendError(fmt.Errorf("component %v: DEFAULT value %v is not supported by DER encoder", t.Identifier, defaultValue))
+		return ""
+	}
+	return exprString(defaultExpr)
+}
+
+// tagOf returns the tag of tagged type.
+func (ctx *moduleContext) tagOf(t TaggedType) (asn1Tag, error) {
+	number, _, err := ctx.lookupValue(t.Tag.ClassNumber)
+	if err != nil {
+		return asn1Tag{}, fmt.Errorf("tag value: %w", err)
+	}
+	n, ok := number.(Number)
+	if !ok {
+		return asn1Tag{}, fmt.Errorf("tag value should be Number, got %#v", number)
+	}
+	return asn1Tag{Class: t.Tag.Class, Number: n.IntValue()}, nil
+}
+
+// isExplicitTag returns true if tag of t is explicit, either declared so,
+// or implied by module tag default or by tagged type.
+func (ctx *moduleContext) isExplicitTag(t TaggedType) bool {
+	tagType := ctx.tagDefault
+	if t.HasTagType {
+		tagType = t.TagType
+	} else if ctx.isUntaggedChoiceOrOpenType(t.Type) {
+		// See X.680, section 31.2.7.
+		tagType = TAGS_EXPLICIT
+	}
+	return tagType == TAGS_EXPLICIT
+}
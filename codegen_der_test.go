@@ -0,0 +1,215 @@
+package asn1go
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDERMethods(t *testing.T) {
+	m := parseModule(t, `
+	TestSpec DEFINITIONS IMPLICIT TAGS ::= BEGIN
+		Msg ::= [APPLICATION 1] EXPLICIT SEQUENCE {
+			id [0] INTEGER,
+			flag [1] BOOLEAN DEFAULT TRUE,
+			name [2] UTF8String OPTIONAL,
+			items SEQUENCE OF OCTET STRING
+		}
+		TaggedMsg ::= [APPLICATION 2] Msg
+		Num ::= INTEGER
+	END
+	`)
+	expected := `package TestSpec
+
+import "github.com/chemikadze/asn1go/der"
+
+type Msg struct {
+	Id    int64   ` + "`asn1:\"tag:0\"`" + `
+	Flag  bool    ` + "`asn1:\"optional,tag:1\"`" + `
+	Name  *string ` + "`asn1:\"optional,tag:2,utf8\"`" + `
+	Items [][]byte
+}
+
+func (v Msg) MarshalDER() ([]byte, error) {
+	var e der.Encoder
+	if err := v.EncodeDER(&e, der.Tag{}); err != nil {
+		return nil, err
+	}
+	return e.Bytes(), nil
+}
+func (v Msg) EncodeDER(e *der.Encoder, tag der.Tag) error {
+	start := e.BeginConstructed(tag.Or(der.Tag{Class: der.ClassApplication, Number: 1}))
+	start2 := e.BeginConstructed(der.TagSequence)
+	e.WriteInteger(der.Tag{Class: der.ClassContextSpecific, Number: 0}, v.Id)
+	if v.Flag != true {
+		e.WriteBoolean(der.Tag{Class: der.ClassContextSpecific, Number: 1}, v.Flag)
+	}
+	if v.Name != nil {
+		e.WriteString(der.Tag{Class: der.ClassContextSpecific, Number: 2}, (*v.Name))
+	}
+	start3 := e.BeginConstructed(der.TagSequence)
+	for _, elem := range v.Items {
+		e.WriteOctetString(der.TagOctetString, elem)
+	}
+	e.End(start3)
+	e.End(start2)
+	e.End(start)
+	return nil
+}
+func (v *Msg) UnmarshalBER(data []byte) ([]byte, error) {
+	return der.UnmarshalBER(data, v)
+}
+func (v *Msg) DecodeBER(d *der.Decoder, tag der.Tag) error {
+	inner, err := d.BeginConstructed(tag.Or(der.Tag{Class: der.ClassApplication, Number: 1}))
+	if err != nil {
+		return err
+	}
+	inner2, err := inner.BeginConstructed(der.TagSequence)
+	if err != nil {
+		return err
+	}
+	if err := inner2.ReadInteger(der.Tag{Class: der.ClassContextSpecific, Number: 0}, &v.Id); err != nil {
+		return err
+	}
+	if inner2.Peek(der.Tag{Class: der.ClassContextSpecific, Number: 1}) {
+		if err := inner2.ReadBoolean(der.Tag{Class: der.ClassContextSpecific, Number: 1}, &v.Flag); err != nil {
+			return err
+		}
+		if inner2.Strict && v.Flag == true {
+			return der.SyntaxError{Msg: "DEFAULT value of component flag is encoded"}
+		}
+	} else {
+		v.Flag = true
+	}
+	if inner2.Peek(der.Tag{Class: der.ClassContextSpecific, Number: 2}) {
+		v.Name = new(string)
+		if err := inner2.ReadString(der.Tag{Class: der.ClassContextSpecific, Number: 2}, &(*v.Name)); err != nil {
+			return err
+		}
+	} else {
+		v.Name = nil
+	}
+	inner3, err := inner2.BeginConstructed(der.TagSequence)
+	if err != nil {
+		return err
+	}
+	v.Items = v.Items[:0]
+	for inner3.More() {
+		var elem []byte
+		if err := inner3.ReadOctetString(der.TagOctetString, &elem); err != nil {
+			return err
+		}
+		v.Items = append(v.Items, elem)
+	}
+	if err := inner3.End(); err != nil {
+		return err
+	}
+	if err := inner2.End(); err != nil {
+		return err
+	}
+	if err := inner.End(); err != nil {
+		return err
+	}
+	return nil
+}
+
+type TaggedMsg Msg
+
+func (v TaggedMsg) MarshalDER() ([]byte, error) {
+	var e der.Encoder
+	if err := v.EncodeDER(&e, der.Tag{}); err != nil {
+		return nil, err
+	}
+	return e.Bytes(), nil
+}
+func (v TaggedMsg) EncodeDER(e *der.Encoder, tag der.Tag) error {
+	if err := Msg(v).EncodeDER(e, tag.Or(der.Tag{Class: der.ClassApplication, Number: 2})); err != nil {
+		return err
+	}
+	return nil
+}
+func (v *TaggedMsg) UnmarshalBER(data []byte) ([]byte, error) {
+	return der.UnmarshalBER(data, v)
+}
+func (v *TaggedMsg) DecodeBER(d *der.Decoder, tag der.Tag) error {
+	if err := (*Msg)(v).DecodeBER(d, tag.Or(der.Tag{Class: der.ClassApplication, Number: 2})); err != nil {
+		return err
+	}
+	return nil
+}
+
+type Num = int64
+`
+	buf := &bytes.Buffer{}
+	if err := NewCodeGenerator(GenParams{Type: GEN_DER}).Generate(*m, buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(formatGoSource(t, expected), formatGoSource(t, buf.String())); diff != "" {
+		t.Errorf("Generated module did not match expected, diff (-want, +got): %v", diff)
+	}
+}
+
+func TestDERMethodsErrors(t *testing.T) {
+	m := parseModule(t, `
+	TestSpec DEFINITIONS ::= BEGIN
+		Value ::= CHOICE { num [0] INTEGER, flag [1] BOOLEAN }
+		TaggedValue ::= [APPLICATION 1] IMPLICIT Value
+	END
+	`)
+	err := NewCodeGenerator(GenParams{Type: GEN_DER}).Generate(*m, &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "TaggedValue") {
+		t.Errorf("Expected error about implicitly tagged CHOICE, got %v", err)
+	}
+}
+
+func TestDERMethodsOptionalComponents(t *testing.T) {
+	m := parseModule(t, `
+	TestSpec DEFINITIONS ::= BEGIN
+		Inner ::= SEQUENCE { id INTEGER }
+		Opts ::= SEQUENCE {
+			verbose BOOLEAN OPTIONAL,
+			retries INTEGER OPTIONAL,
+			name UTF8String OPTIONAL,
+			data OCTET STRING OPTIONAL,
+			items SEQUENCE OF INTEGER OPTIONAL,
+			inner Inner OPTIONAL,
+			label OCTET STRING DEFAULT '0102'H,
+			policy OBJECT IDENTIFIER DEFAULT { 1 2 3 }
+		}
+		defaultOpts Opts ::= { verbose FALSE, retries 0, name "", data ''H, items {}, inner { id 0 } }
+	END
+	`)
+	buf := &bytes.Buffer{}
+	if err := NewCodeGenerator(GenParams{Type: GEN_DER}).Generate(*m, buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	generated := buf.String()
+	for _, snippet := range []string{
+		"Verbose *bool",
+		"Retries *int64",
+		"Name    *string",
+		"Data    []byte",
+		"Items   []int64",
+		"Inner   *Inner",
+		"if v.Verbose != nil {",
+		"if v.Name != nil {",
+		"if v.Data != nil {",
+		"if v.Items != nil {",
+		"if v.Inner != nil {",
+		"v.Verbose = new(bool)",
+		"if v.Data == nil {\n\t\t\tv.Data = []byte{}\n\t\t}",
+		"if v.Items == nil {\n\t\t\tv.Items = []int64{}\n\t\t}",
+		"} else {\n\t\tv.Verbose = nil\n\t}",
+		"} else {\n\t\tv.Data = nil\n\t}",
+		"} else {\n\t\tv.Inner = nil\n\t}",
+		"if !bytes.Equal(v.Label, []byte{0x01, 0x02}) {",
+		"if !v.Policy.Equal(asn1.ObjectIdentifier{1, 2, 3}) {",
+		"Opts{Verbose: &[]bool{false}[0], Retries: &[]int64{0}[0], Name: &[]string{\"\"}[0], Data: []byte{}, Items: []int64{}, Inner: &[]Inner{Inner{Id: 0}}[0]}",
+	} {
+		if !strings.Contains(formatGoSource(t, generated), snippet) {
+			t.Errorf("Expected generated code to contain %q, got:\n%v", snippet, formatGoSource(t, generated))
+		}
+	}
+}
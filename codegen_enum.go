@@ -0,0 +1,129 @@
+package asn1go
+
+import (
+	"bytes"
+	"fmt"
+	goast "go/ast"
+	"text/template"
+)
+
+// EnumRepr is enum controlling how ENUMERATED is represented.
+type EnumRepr string
+
+// EnumRepr modes supported.
+const (
+	// EnumReprType represents ENUMERATED as named go type with constant for every item, String and IsValid methods.
+	// Inline ENUMERATED types are declared as separate types named after enclosing type and component.
+	// Note that encoding/asn1 encodes named types as INTEGER, so they should be used with generated encoding methods.
+	EnumReprType EnumRepr = "type"
+	// EnumReprAlias represents ENUMERATED as alias of asn1.Enumerated with constant for every item,
+	// which is compatible with encoding/asn1.
+	EnumReprAlias EnumRepr = "alias"
+)
+
+// enumerationTemplate generates constants of ENUMERATED type, and methods of the type
+// if it is declared as a named go type.
+var enumerationTemplate = template.Must(template.New("enumeration").Parse(`
+const (
+{{- range .Items}}
+	{{.Const}} {{$.Name}} = {{.Value}}
+{{- end}}
+)
+{{- if .Methods}}
+
+func (v {{.Name}}) String() string {
+	switch v {
+	{{- range .Items}}
+	case {{.Const}}:
+		return {{printf "%q" .Identifier}}
+	{{- end}}
+	}
+	return "{{.Name}}(" + strconv.Itoa(int(v)) + ")"
+}
+
+func (v {{.Name}}) IsValid() bool {
+	switch v {
+	case {{range $i, $item := .Items}}{{if $i}}, {{end}}{{$item.Const}}{{end}}:
+		return true
+	}
+	return false
+}
+{{- end}}
+`))
+
+// enumerationItemParams are parameters of enumerationTemplate for a single item.
+type enumerationItemParams struct {
+	Const      string
+	Identifier string
+	Value      int64
+}
+
+// isNamedEnumeration returns true if ENUMERATED type t of type assignment is declared as a named go type
+// with constant for every item, String and IsValid methods. Otherwise, it is an alias of asn1.Enumerated,
+// which is compatible with encoding/asn1.
+func (ctx *moduleContext) isNamedEnumeration(t Type) bool {
+	_, ok := ctx.removeWrapperTypes(t).(EnumeratedType)
+	return ok && ctx.params.EnumRepr == EnumReprType
+}
+
+// generateEnumerationDecls generates constants of items of ENUMERATED type declared by type assignment.
+// Items are numbered as defined by X.680, section 20, and are named after the type and the identifier,
+// e.g. ColorValRed, same as named numbers of INTEGER types.
+func (ctx *moduleContext) generateEnumerationDecls(reference TypeReference, t Type) []goast.Decl {
+	enum, ok := ctx.removeWrapperTypes(t).(EnumeratedType)
+	if !ok {
+		return nil
+	}
+	name := goifyName(reference.Name())
+	root, additions, err := ctx.enumerationItems(enum)
+	if err != nil {
+		ctx.appendError(fmt.Errorf("type %v: %w", reference.Name(), err))
+		return nil
+	}
+	var items []enumerationItemParams
+	for _, item := range append(root, additions...) {
+		items = append(items, enumerationItemParams{Const: name + "Val" + goifyName(item.name), Identifier: item.name, Value: item.value})
+	}
+	methods := ctx.isNamedEnumeration(t)
+	if methods {
+		ctx.requireModule("strconv")
+	}
+	var buf bytes.Buffer
+	if err := enumerationTemplate.Execute(&buf, map[string]any{"Name": name, "Items": items, "Methods": methods}); err != nil {
+		ctx.appendError(fmt.Errorf("type %v: %w", reference.Name(), err))
+		return nil
+	}
+	decls, err := parseGoDecls(buf.String())
+	if err != nil {
+		ctx.appendError(fmt.Errorf("type %v: failed to parse generated code: %w", reference.Name(), err))
+		return nil
+	}
+	return decls
+}
+
+// enumerationItemValue returns value of the item of ENUMERATED type identified by val, which is parsed
+// either as IdentifiedIntegerValue or as unqualified DefinedValue.
+func (ctx *moduleContext) enumerationItemValue(t EnumeratedType, val Value) (Number, bool, error) {
+	var name string
+	switch v := val.(type) {
+	case IdentifiedIntegerValue:
+		name = v.Name
+	case DefinedValue:
+		if v.ModuleName != "" {
+			return 0, false, nil
+		}
+		name = v.ValueName.Name()
+	default:
+		return 0, false, nil
+	}
+	root, additions, err := ctx.enumerationItems(t)
+	if err != nil {
+		return 0, false, err
+	}
+	for _, item := range append(root, additions...) {
+		if item.name == name {
+			return Number(item.value), true, nil
+		}
+	}
+	return 0, false, nil
+}
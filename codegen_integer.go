@@ -0,0 +1,204 @@
+package asn1go
+
+import (
+	"fmt"
+	goast "go/ast"
+	"math"
+	"math/big"
+)
+
+// sizedIntegerTypes are go types INTEGER values can be represented with in IntegerReprAuto mode,
+// from the narrowest one. If no encoding methods are generated, only types supported by encoding/asn1 are used.
+var sizedIntegerTypes = []struct {
+	name         string
+	lower, upper *big.Int
+	isUnsigned   bool
+	encodingAsn1 bool
+}{
+	{"uint8", big.NewInt(0), big.NewInt(math.MaxUint8), true, false},
+	{"uint16", big.NewInt(0), big.NewInt(math.MaxUint16), true, false},
+	{"uint32", big.NewInt(0), big.NewInt(math.MaxUint32), true, false},
+	{"uint64", big.NewInt(0), new(big.Int).SetUint64(math.MaxUint64), true, false},
+	{"int8", big.NewInt(math.MinInt8), big.NewInt(math.MaxInt8), false, false},
+	{"int16", big.NewInt(math.MinInt16), big.NewInt(math.MaxInt16), false, false},
+	{"int32", big.NewInt(math.MinInt32), big.NewInt(math.MaxInt32), false, true},
+	{"int64", big.NewInt(math.MinInt64), big.NewInt(math.MaxInt64), false, true},
+}
+
+// defaultIntegerType returns go type of values of INTEGER type without constraints.
+func (ctx *moduleContext) defaultIntegerType() string {
+	switch ctx.params.IntegerRepr {
+	case IntegerReprInt64:
+		return "int64"
+	case IntegerReprBigInt, IntegerReprAuto:
+		return "*big.Int"
+	default:
+		ctx.appendError(fmt.Errorf("unknown int type mode: %v", ctx.params.IntegerRepr))
+		return "int64"
+	}
+}
+
+// constrainedIntegerType returns go type of values of constrained INTEGER type t with IntegerReprAuto.
+// Only constraints applied to INTEGER type directly select the type, as referenced types
+// are represented with their own go types. Returns false if t is not a constrained INTEGER type.
+//
+// Extensible and semi-constrained types are represented with big.Int, as their values are not bounded,
+// and so are types which values do not fit any sized type. Unless encoding methods are generated,
+// bounded types are represented with int32 or int64, as encoding/asn1 does not support other go integer types.
+func (ctx *moduleContext) constrainedIntegerType(t ConstraintedType) (string, bool) {
+	if ctx.params.IntegerRepr != IntegerReprAuto {
+		return "", false
+	}
+	var cs perConstraints
+	var inner Type = t
+	for {
+		constrained, ok := inner.(ConstraintedType)
+		if !ok {
+			break
+		}
+		cs = cs.with(ctx, constrained.Constraint)
+		inner = constrained.Type
+	}
+	if _, ok := inner.(IntegerType); !ok {
+		return "", false
+	}
+	bounds := cs.value()
+	if bounds.extensible || bounds.lower == nil || bounds.upper == nil {
+		return ctx.defaultIntegerType(), true
+	}
+	encodingAsn1 := ctx.params.Type&genEncoders == 0
+	for _, sized := range sizedIntegerTypes {
+		if encodingAsn1 {
+			if !sized.encodingAsn1 {
+				continue
+			}
+		} else if sized.isUnsigned != (bounds.lower.Sign() >= 0) {
+			continue
+		}
+		if bounds.lower.Cmp(sized.lower) >= 0 && bounds.upper.Cmp(sized.upper) <= 0 {
+			return sized.name, true
+		}
+	}
+	return ctx.defaultIntegerType(), true
+}
+
+// integerType returns go type of values of INTEGER type t, which can be tagged or referenced.
+// Returns empty string if t is not INTEGER type.
+func (ctx *moduleContext) integerType(t Type) string {
+	switch tt := t.(type) {
+	case TaggedType:
+		return ctx.integerType(tt.Type)
+	case NamedType:
+		return ctx.integerType(tt.Type)
+	case ConstraintedType:
+		if goType, ok := ctx.constrainedIntegerType(tt); ok {
+			return goType
+		}
+		return ctx.integerType(tt.Type)
+	case TypeReference:
+		assignment, assignmentCtx, err := ctx.lookupTypeAssignment(tt)
+		if err != nil || assignment == nil {
+			return ""
+		}
+		return assignmentCtx.integerType(assignment.Type)
+	case IntegerType:
+		return ctx.defaultIntegerType()
+	default:
+		return ""
+	}
+}
+
+// integerTypeExpr returns go expression of integer type goType.
+func (ctx *moduleContext) integerTypeExpr(goType string) goast.Expr {
+	if goType == "*big.Int" {
+		ctx.requireModule("math/big")
+		return &goast.StarExpr{X: goast.NewIdent("big.Int")}
+	}
+	return goast.NewIdent(goType)
+}
+
+// integerRepr returns IntegerReprBigInt if values of INTEGER type t are represented with big.Int,
+// and IntegerReprInt64 if they are represented with go integer types.
+func (ctx *moduleContext) integerRepr(t Type) IntegerRepr {
+	if ctx.integerType(t) == "*big.Int" {
+		return IntegerReprBigInt
+	}
+	return IntegerReprInt64
+}
+
+// constrain is called by generators when entering constrained type t, and records go type of INTEGER value
+// selected by constraints of t, which is used once INTEGER type is reached, see constrainedIntegerType.
+func (g *derEncoderGen) constrain(ctx *moduleContext, t ConstraintedType) {
+	if g.integer != "" {
+		return // constraints of the outermost type were already evaluated
+	}
+	if goType, ok := ctx.constrainedIntegerType(t); ok {
+		g.integer = goType
+	}
+}
+
+// currentIntegerType returns go type of INTEGER value being generated.
+func (g *derEncoderGen) currentIntegerType(ctx *moduleContext) string {
+	if goType := g.integer; goType != "" {
+		g.integer = ""
+		return goType
+	}
+	return ctx.defaultIntegerType()
+}
+
+// integerArg returns go expression converting INTEGER value expr of go type goType to int64,
+// or to *big.Int if big is set, as accepted by WriteInteger and WriteBigInteger methods of encoders.
+func (ctx *moduleContext) integerArg(expr string, goType string) (arg string, big bool) {
+	switch goType {
+	case "*big.Int":
+		return expr, true
+	case "int64":
+		return expr, false
+	case "uint64":
+		ctx.requireModule("math/big")
+		return fmt.Sprintf("new(big.Int).SetUint64(%v)", expr), true
+	default:
+		return fmt.Sprintf("int64(%v)", expr), false
+	}
+}
+
+// integerTarget returns addressable go expression of int64 variable, or of *big.Int variable if big is set,
+// which INTEGER value of go type goType is decoded into by ReadInteger or ReadBigInteger methods of decoders.
+// If the variable is not expr itself, it is declared, and assignInteger should be called once value is decoded.
+func (g *derEncoderGen) integerTarget(ctx *moduleContext, expr string, goType string) (target string, big bool) {
+	switch goType {
+	case "*big.Int":
+		return expr, true
+	case "int64":
+		return expr, false
+	case "uint64":
+		ctx.requireModule("math/big")
+		target = g.newVar("n")
+		g.line("var %v *big.Int", target)
+		return target, true
+	default:
+		target = g.newVar("n")
+		g.line("var %v int64", target)
+		return target, false
+	}
+}
+
+// assignInteger writes statements converting decoded value of variable target to go type goType,
+// and assigning it to expr. Values which do not fit into the type are rejected.
+func (g *derEncoderGen) assignInteger(ctx *moduleContext, expr string, target string, goType string) {
+	if target == expr {
+		return
+	}
+	ctx.requireModule("fmt")
+	if goType == "uint64" {
+		g.line("if !%v.IsUint64() {", target)
+		g.line("\treturn fmt.Errorf(\"INTEGER value %%v overflows uint64\", %v)", target)
+		g.line("}")
+		g.line("%v = %v.Uint64()", expr, target)
+		return
+	}
+	g.line("if int64(%v(%v)) != %v {", goType, target, target)
+	g.line("\treturn fmt.Errorf(\"INTEGER value %%v overflows %v\", %v)", goType, target)
+	g.line("}")
+	g.line("%v = %v(%v)", expr, goType, target)
+}
@@ -0,0 +1,193 @@
+package asn1go
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestIntegerReprAuto(t *testing.T) {
+	testCases := []struct {
+		name     string
+		typeDecl string
+		expected string
+	}{
+		{
+			name:     "unsigned byte",
+			typeDecl: "INTEGER (0..255)",
+			expected: "F uint8",
+		},
+		{
+			name:     "signed byte",
+			typeDecl: "INTEGER (-128..127)",
+			expected: "F int8",
+		},
+		{
+			name:     "unsigned 32 bits",
+			typeDecl: "INTEGER (0..4294967295)",
+			expected: "F uint32",
+		},
+		{
+			name:     "signed 32 bits",
+			typeDecl: "INTEGER (-2147483648..2147483647)",
+			expected: "F int32",
+		},
+		{
+			name:     "unsigned 64 bits",
+			typeDecl: "INTEGER (0..9223372036854775807)",
+			expected: "F uint64",
+		},
+		{
+			name:     "full unsigned 64 bits",
+			typeDecl: "INTEGER (0..18446744073709551615)",
+			expected: "F uint64",
+		},
+		{
+			name:     "full signed 64 bits",
+			typeDecl: "INTEGER (-9223372036854775808..9223372036854775807)",
+			expected: "F int64",
+		},
+		{
+			name:     "beyond unsigned 64 bits",
+			typeDecl: "INTEGER (0..18446744073709551616)",
+			expected: "F *big.Int",
+		},
+		{
+			name:     "beyond signed 64 bits",
+			typeDecl: "INTEGER (-9223372036854775809..0)",
+			expected: "F *big.Int",
+		},
+		{
+			name:     "union of values",
+			typeDecl: "INTEGER (1 | 300)",
+			expected: "F uint16",
+		},
+		{
+			name:     "serially applied constraints",
+			typeDecl: "INTEGER (0..1000) (0..10)",
+			expected: "F uint8",
+		},
+		{
+			name:     "tagged type",
+			typeDecl: "[5] INTEGER (-1..1)",
+			expected: "F int8",
+		},
+		{
+			name:     "unconstrained",
+			typeDecl: "INTEGER",
+			expected: "F *big.Int",
+		},
+		{
+			name:     "semi-constrained",
+			typeDecl: "INTEGER (0..MAX)",
+			expected: "F *big.Int",
+		},
+		{
+			name:     "extensible",
+			typeDecl: "INTEGER (0..7, ...)",
+			expected: "F *big.Int",
+		},
+		{
+			name:     "referenced type",
+			typeDecl: "Small (2..MAX)",
+			expected: "F Small",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := parseModule(t, `
+			TestSpec DEFINITIONS AUTOMATIC TAGS ::= BEGIN
+				Small ::= INTEGER (0..7)
+				Msg ::= SEQUENCE { f `+tc.typeDecl+` }
+			END
+			`)
+			buf := &bytes.Buffer{}
+			if err := NewCodeGenerator(GenParams{Type: GEN_DER, IntegerRepr: IntegerReprAuto}).Generate(*m, buf); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			generated := strings.Join(strings.Fields(buf.String()), " ")
+			if !strings.Contains(generated, tc.expected) || !strings.Contains(generated, "type Small = uint8") {
+				t.Errorf("Generated module does not contain %v:\n%v", tc.expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestIntegerReprAutoEncodingAsn1(t *testing.T) {
+	m := parseModule(t, `
+	TestSpec DEFINITIONS AUTOMATIC TAGS ::= BEGIN
+		Small ::= INTEGER (0..7)
+		Msg ::= SEQUENCE {
+			byte INTEGER (-128..127),
+			word INTEGER (0..4294967295),
+			long INTEGER (0..9223372036854775807),
+			wide INTEGER (0..18446744073709551615),
+			big INTEGER
+		}
+	END
+	`)
+	buf := &bytes.Buffer{}
+	if err := NewCodeGenerator(GenParams{IntegerRepr: IntegerReprAuto}).Generate(*m, buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	generated := strings.Join(strings.Fields(buf.String()), " ")
+	for _, expected := range []string{"type Small = int32", "Byte int32", "Word int64", "Long int64", "Wide *big.Int", "Big *big.Int"} {
+		if !strings.Contains(generated, expected) {
+			t.Errorf("Generated module does not contain %v:\n%v", expected, buf.String())
+		}
+	}
+}
+
+func TestIntegerReprAutoMethods(t *testing.T) {
+	m := parseModule(t, `
+	TestSpec DEFINITIONS AUTOMATIC TAGS ::= BEGIN
+		Msg ::= SEQUENCE { f INTEGER (0..255) }
+	END
+	`)
+	buf := &bytes.Buffer{}
+	if err := NewCodeGenerator(GenParams{Type: GEN_DER, IntegerRepr: IntegerReprAuto}).Generate(*m, buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, expected := range []string{
+		"e.WriteInteger(der.Tag{Class: der.ClassContextSpecific, Number: 0}, int64(v.F))",
+		`if int64(uint8(n)) != n {
+		return fmt.Errorf("INTEGER value %v overflows uint8", n)
+	}
+	v.F = uint8(n)`,
+	} {
+		if !strings.Contains(buf.String(), expected) {
+			t.Errorf("Generated module does not contain %v:\n%v", expected, buf.String())
+		}
+	}
+}
+
+func TestIntegerReprAutoWideValues(t *testing.T) {
+	m := parseModule(t, `
+	TestSpec DEFINITIONS AUTOMATIC TAGS ::= BEGIN
+		Msg ::= SEQUENCE {
+			counter INTEGER (1..18446744073709551615) DEFAULT 18446744073709551615,
+			huge INTEGER (0..36893488147419103231) DEFAULT 18446744073709551616
+		}
+	END
+	`)
+	buf := &bytes.Buffer{}
+	if err := NewCodeGenerator(GenParams{Type: GEN_DER | GEN_VALIDATE, IntegerRepr: IntegerReprAuto}).Generate(*m, buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, expected := range []string{
+		"if v.Counter != 18446744073709551615 {",
+		"if !(v.Counter >= 1) {",
+		`v.Huge = func() *big.Int {
+			n, _ := new(big.Int).SetString("18446744073709551616", 10)
+			return n
+		}()`,
+		`v.Huge.Cmp(func() *big.Int {
+		n, _ := new(big.Int).SetString("36893488147419103231", 10)
+		return n
+	}()) <= 0`,
+	} {
+		if !strings.Contains(buf.String(), expected) {
+			t.Errorf("Generated module does not contain %v:\n%v", expected, buf.String())
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package asn1go
+
+import (
+	"bytes"
+	"fmt"
+	goast "go/ast"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// jerPackage is import path of runtime package used by generated JER encoders.
+const jerPackage = "github.com/chemikadze/asn1go/jer"
+
+// jerMethodsTemplate generates methods encoding and decoding the type with JER.
+var jerMethodsTemplate = template.Must(template.New("jer").Parse(`
+func (v {{.Name}}) MarshalJSON() ([]byte, error) {
+	return jer.Marshal(v)
+}
+
+func (v {{.Name}}) EncodeJER(e *jer.Encoder) error {
+{{.Encode -}}
+	return nil
+}
+{{- if .Decodable}}
+
+func (v *{{.Name}}) UnmarshalJSON(data []byte) error {
+	return jer.Unmarshal(data, v)
+}
+
+func (v *{{.Name}}) DecodeJER(d *jer.Decoder) error {
+{{.Decode -}}
+	return nil
+}
+{{- end}}
+`))
+
+// generateJERDecls generates JER encoding and decoding methods of the type declared by decl.
+// See generatePERDecls for types which get methods.
+func (ctx *moduleContext) generateJERDecls(a TypeAssignment, decl goast.Decl) []goast.Decl {
+	name := goifyName(a.TypeReference.Name())
+	switch t := ctx.removeWrapperTypes(a.Type).(type) {
+	case SequenceType, SetType:
+		return ctx.generateJERMethods(name, "v", "v", a.Type)
+	case TypeReference:
+		// see generatePERDecls
+		if !ctx.isDefinedReference(a.Type) {
+			return nil
+		}
+		typeName := exprString(ctx.generateTypeExpr(t))
+		return ctx.generateJERMethods(name, typeName+"(v)", "(*"+typeName+")(v)", a.Type)
+	default:
+		return nil
+	}
+}
+
+// generateJERMethods generates MarshalJSON, EncodeJER, UnmarshalJSON and DecodeJER methods of go type typeName,
+// which encode go expression encodeExpr of type t, and decode into addressable go expression decodeExpr.
+func (ctx *moduleContext) generateJERMethods(typeName string, encodeExpr string, decodeExpr string, t Type) []goast.Decl {
+	enc := &jerEncoderGen{}
+	enc.encode(ctx, encodeExpr, t, nil)
+	dec := &jerDecoderGen{}
+	dec.decode(ctx, decodeExpr, t, nil)
+	return ctx.executeJERMethodsTemplate(perMethodsParams{Name: typeName, Encode: enc.buf.String(), Decode: dec.buf.String(), Decodable: true})
+}
+
+func (ctx *moduleContext) executeJERMethodsTemplate(params perMethodsParams) []goast.Decl {
+	ctx.requireModule(jerPackage)
+	var buf bytes.Buffer
+	if err := jerMethodsTemplate.Execute(&buf, params); err != nil {
+		ctx.appendError(fmt.Errorf("type %v: %w", params.Name, err))
+		return nil
+	}
+	decls, err := parseGoDecls(buf.String())
+	if err != nil {
+		ctx.appendError(fmt.Errorf("type %v: failed to parse generated code: %w", params.Name, err))
+		return nil
+	}
+	return decls
+}
+
+// jerFixedSize returns size of BIT STRING values, if it is fixed by constraint which is not extensible.
+// Values of fixed size are encoded without their length.
+func jerFixedSize(b perBounds) (int64, bool) {
+	if b.extensible || b.lower == nil || b.upper == nil || b.lower.Cmp(b.upper) != 0 {
+		return 0, false
+	}
+	return b.lower.Int64(), true
+}
+
+// jerEnumeration returns go expression of jer.Enumeration value describing ENUMERATED type.
+func (ctx *moduleContext) jerEnumeration(t EnumeratedType) string {
+	root, additions, err := ctx.enumerationItems(t)
+	if err != nil {
+		ctx.appendError(err)
+		return "jer.Enumeration{}"
+	}
+	items := make([]string, 0, len(root)+len(additions))
+	for _, item := range append(root, additions...) {
+		items = append(items, fmt.Sprintf("{Name: %q, Value: %v}", item.name, item.value))
+	}
+	return "jer.Enumeration{" + strings.Join(items, ", ") + "}"
+}
+
+// jerEncoderGen generates go statements encoding values with JER.
+type jerEncoderGen struct {
+	derEncoderGen
+	// present is go expression of OPTIONAL component being encoded, which is known to be present.
+	present string
+}
+
+// encode writes statements appending encoding of go expression expr of type t to encoder e.
+// Constraints cs are constraints applied to t by enclosing types.
+func (g *jerEncoderGen) encode(ctx *moduleContext, expr string, t Type, cs perConstraints) {
+	switch tt := t.(type) {
+	case TaggedType:
+		g.encode(ctx, expr, tt.Type, cs)
+	case ConstraintedType:
+		if contained, ok := ctx.contentsOf(tt); ok {
+			// contained value is encoded in place of the string, as required by X.697
+			g.encode(ctx, expr, contained, nil)
+			return
+		}
+		g.constrain(ctx, tt)
+		g.encode(ctx, expr, tt.Type, cs.with(ctx, tt.Constraint))
+	case NamedType:
+		g.encode(ctx, expr, tt.Type, cs)
+	case TypeReference:
+		g.encodeReference(ctx, expr, tt, cs)
+	case SequenceType:
+		g.encodeSequence(ctx, expr, tt.Components, tt.ExtensionAdditions)
+	case SetType:
+		g.encodeSequence(ctx, expr, tt.Components, tt.ExtensionAdditions)
+	case SequenceOfType:
+		g.encodeElements(ctx, expr, tt.Type)
+	case SetOfType:
+		g.encodeElements(ctx, expr, tt.Type)
+	case ChoiceType:
+		if ctx.params.ChoiceRepr == ChoiceReprInterface {
+			ctx.appendError(fmt.Errorf("CHOICE type %#v should be declared by type assignment", t))
+		} else {
+			ctx.appendError(fmt.Errorf("CHOICE types with %v representation are not supported by JER encoder", ctx.params.ChoiceRepr))
+		}
+	case BooleanType:
+		g.line("e.WriteBoolean(%v)", expr)
+	case IntegerType:
+		if v, big := ctx.integerArg(expr, g.currentIntegerType(ctx)); big {
+			g.check("e.WriteBigInteger(%v)", v)
+		} else {
+			g.line("e.WriteInteger(%v)", v)
+		}
+	case EnumeratedType:
+		ctx.requireModule("encoding/asn1")
+		g.check("e.WriteEnumerated(asn1.Enumerated(%v), %v)", expr, ctx.jerEnumeration(tt))
+	case RealType:
+		g.line("e.WriteReal(%v)", expr)
+	case OctetStringType:
+		g.line("e.WriteOctetString(%v)", expr)
+	case BitStringType:
+		if size, ok := jerFixedSize(cs.size()); ok {
+			g.check("e.WriteFixedBitString(%v, %v)", ctx.bitStringExpr(tt, expr), size)
+		} else {
+			g.check("e.WriteBitString(%v)", ctx.bitStringExpr(tt, expr))
+		}
+	case NullType:
+		g.line("e.WriteNull()")
+	case ObjectIdentifierType:
+		g.check("e.WriteObjectIdentifier(%v)", expr)
+	case RestrictedStringType:
+		if _, ok := restrictedStringTags[tt.LexType]; ok {
+			g.line("e.WriteString(%v)", expr)
+		} else {
+			ctx.appendError(fmt.Errorf("restricted string type %#v is not supported by JER encoder", tt))
+		}
+	default:
+		ctx.appendError(fmt.Errorf("type %#v is not supported by JER encoder", t))
+	}
+}
+
+// encodeReference writes statements encoding value of referenced type. Types having JER methods
+// are encoded by calling them, and other types are encoded inline with constraints of the reference.
+func (g *jerEncoderGen) encodeReference(ctx *moduleContext, expr string, t TypeReference, cs perConstraints) {
+	assignment, assignmentCtx, err := ctx.lookupTypeAssignment(t)
+	if err != nil {
+		ctx.appendError(err)
+		return
+	}
+	if assignment == nil {
+		switch t.Name() {
+		case GeneralizedTimeName:
+			g.check("e.WriteGeneralizedTime(%v)", expr)
+		case UTCTimeName:
+			g.check("e.WriteUTCTime(%v)", expr)
+		default:
+			if useful := ctx.lookupUsefulType(t); useful != nil {
+				g.encode(ctx, expr, useful, cs)
+			} else {
+				ctx.appendError(fmt.Errorf("can not resolve TypeReference %v", t.Name()))
+			}
+		}
+		return
+	}
+	if assignmentCtx.hasEncodingMethods(assignment.Type) {
+		if ctx.choiceTypeName(t) != nil && expr != g.present {
+			g.line("if %v == nil {\n\t\treturn jer.ErrAbsentValue\n\t}", expr)
+		}
+		g.check("%v.EncodeJER(e)", expr)
+		return
+	}
+	name := string(assignmentCtx.moduleName) + "." + t.Name()
+	if slices.Contains(g.inlined, name) {
+		ctx.appendError(fmt.Errorf("type %v: reference cycle %v", t, strings.Join(append(g.inlined, name), " -> ")))
+		return
+	}
+	g.inlined = append(g.inlined, name)
+	g.encode(assignmentCtx, assignmentCtx.definedTypeValue(assignment.Type, expr), assignment.Type, cs)
+	g.inlined = g.inlined[:len(g.inlined)-1]
+}
+
+// encodeSequence writes statements encoding SEQUENCE or SET value as JSON object with a member per present
+// component, named by its identifier. Components equal to their DEFAULT values are omitted,
+// and components of extension addition groups are encoded as members of the same object.
+func (g *jerEncoderGen) encodeSequence(ctx *moduleContext, expr string, components ComponentTypeList, additions ExtensionAdditions) {
+	g.line("e.BeginObject()")
+	g.encodeComponents(ctx, ctx.perComponents(expr, components, false))
+	for _, a := range ctx.perAdditions(expr, additions) {
+		if !a.group {
+			g.line("if %v {", a.present)
+			g.present = a.components[0].field
+			g.encodeComponents(ctx, []perComponent{{named: a.components[0].named, field: a.components[0].field}})
+			g.present = ""
+			g.line("}")
+			continue
+		}
+		g.line("if %v {", a.present)
+		g.encodeComponents(ctx, a.components)
+		g.line("}")
+	}
+	g.line("e.EndObject()")
+}
+
+// encodeComponents writes statements encoding present components as members of JSON object.
+func (g *jerEncoderGen) encodeComponents(ctx *moduleContext, components []perComponent) {
+	for _, c := range components {
+		if c.present != "" {
+			g.line("if %v {", c.present)
+			g.present = c.field
+		}
+		g.line("e.WriteName(%q)", c.named.NamedType.Identifier.Name())
+		g.encode(ctx, c.field, c.named.NamedType.Type, nil)
+		if c.present != "" {
+			g.line("}")
+			g.present = ""
+		}
+	}
+}
+
+// encodeElements writes statements encoding elements of SEQUENCE OF or SET OF value as JSON array.
+func (g *jerEncoderGen) encodeElements(ctx *moduleContext, expr string, t Type) {
+	i := g.newVar("i")
+	g.line("if err := e.WriteList(len(%v), func(%v int) error {", expr, i)
+	g.encode(ctx, expr+"["+i+"]", t, nil)
+	g.line("return nil")
+	g.line("}); err != nil {\n\t\treturn err\n\t}")
+}
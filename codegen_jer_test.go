@@ -0,0 +1,64 @@
+package asn1go
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestJERTypes(t *testing.T) {
+	testCases := []struct {
+		name     string
+		typeDecl string
+		expected string
+	}{
+		{
+			name:     "bit string of fixed size",
+			typeDecl: "BIT STRING (SIZE (8))",
+			expected: "e.WriteFixedBitString(v.F, 8)",
+		},
+		{
+			name:     "bit string of extensible size",
+			typeDecl: "BIT STRING (SIZE (8, ...))",
+			expected: "e.WriteBitString(v.F)",
+		},
+		{
+			name:     "enumeration with additions",
+			typeDecl: "ENUMERATED { b(1), a(0), ..., c }",
+			expected: `e.WriteEnumerated(asn1.Enumerated(v.F), jer.Enumeration{{Name: "a", Value: 0}, {Name: "b", Value: 1}, {Name: "c", Value: 2}})`,
+		},
+		{
+			name:     "member named by identifier",
+			typeDecl: "INTEGER",
+			expected: `case "f":`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := parseModule(t, `
+			TestSpec DEFINITIONS AUTOMATIC TAGS ::= BEGIN
+				Msg ::= SEQUENCE { f `+tc.typeDecl+` }
+			END
+			`)
+			buf := &bytes.Buffer{}
+			if err := NewCodeGenerator(GenParams{Type: GEN_JER}).Generate(*m, buf); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !strings.Contains(buf.String(), tc.expected) {
+				t.Errorf("Generated module does not contain %v:\n%v", tc.expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestJERMethodsErrors(t *testing.T) {
+	m := parseModule(t, `
+	TestSpec DEFINITIONS ::= BEGIN
+		Msg ::= SEQUENCE { value ANY }
+	END
+	`)
+	err := NewCodeGenerator(GenParams{Type: GEN_JER}).Generate(*m, &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "not supported by JER") {
+		t.Errorf("Expected error about ANY type, got %v", err)
+	}
+}
@@ -0,0 +1,317 @@
+package asn1go
+
+import (
+	"bytes"
+	"fmt"
+	goast "go/ast"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// oerPackage is import path of runtime package used by generated OER encoders.
+const oerPackage = "github.com/chemikadze/asn1go/oer"
+
+// oerMethodsTemplate generates methods encoding and decoding the type with OER.
+var oerMethodsTemplate = template.Must(template.New("oer").Parse(`
+func (v {{.Name}}) MarshalOER() ([]byte, error) {
+	return oer.Marshal(v)
+}
+
+func (v {{.Name}}) EncodeOER(e *oer.Encoder) error {
+{{.Encode -}}
+	return nil
+}
+{{- if .Decodable}}
+
+func (v *{{.Name}}) UnmarshalOER(data []byte) ([]byte, error) {
+	return oer.Unmarshal(data, v)
+}
+
+func (v *{{.Name}}) DecodeOER(d *oer.Decoder) error {
+{{.Decode -}}
+	return nil
+}
+{{- end}}
+`))
+
+// generateOERDecls generates OER encoding and decoding methods of the type declared by decl.
+// See generatePERDecls for types which get methods.
+func (ctx *moduleContext) generateOERDecls(a TypeAssignment, decl goast.Decl) []goast.Decl {
+	name := goifyName(a.TypeReference.Name())
+	switch t := ctx.removeWrapperTypes(a.Type).(type) {
+	case SequenceType, SetType:
+		return ctx.generateOERMethods(name, "v", "v", a.Type)
+	case TypeReference:
+		// see generatePERDecls
+		if !ctx.isDefinedReference(a.Type) {
+			return nil
+		}
+		typeName := exprString(ctx.generateTypeExpr(t))
+		return ctx.generateOERMethods(name, typeName+"(v)", "(*"+typeName+")(v)", a.Type)
+	default:
+		return nil
+	}
+}
+
+// generateOERMethods generates MarshalOER, EncodeOER, UnmarshalOER and DecodeOER methods of go type typeName,
+// which encode go expression encodeExpr of type t, and decode into addressable go expression decodeExpr.
+func (ctx *moduleContext) generateOERMethods(typeName string, encodeExpr string, decodeExpr string, t Type) []goast.Decl {
+	enc := &oerEncoderGen{}
+	enc.encode(ctx, encodeExpr, t, nil)
+	dec := &oerDecoderGen{}
+	dec.decode(ctx, decodeExpr, t, nil)
+	return ctx.executeOERMethodsTemplate(perMethodsParams{Name: typeName, Encode: enc.buf.String(), Decode: dec.buf.String(), Decodable: true})
+}
+
+func (ctx *moduleContext) executeOERMethodsTemplate(params perMethodsParams) []goast.Decl {
+	ctx.requireModule(oerPackage)
+	var buf bytes.Buffer
+	if err := oerMethodsTemplate.Execute(&buf, params); err != nil {
+		ctx.appendError(fmt.Errorf("type %v: %w", params.Name, err))
+		return nil
+	}
+	decls, err := parseGoDecls(buf.String())
+	if err != nil {
+		ctx.appendError(fmt.Errorf("type %v: failed to parse generated code: %w", params.Name, err))
+		return nil
+	}
+	return decls
+}
+
+// oerExpr returns go expression of oer.Constraint value. Extensible constraints are not OER-visible.
+func (b perBounds) oerExpr() string {
+	if b.extensible {
+		return "oer.Constraint{}"
+	}
+	return "oer." + strings.TrimPrefix(b.expr(), "per.")
+}
+
+// oerEnumeration returns go expression of oer.Enumeration value describing ENUMERATED type.
+func (ctx *moduleContext) oerEnumeration(t EnumeratedType) string {
+	root, additions, err := ctx.enumerationValues(t)
+	if err != nil {
+		ctx.appendError(err)
+		return "oer.Enumeration{}"
+	}
+	values := make([]string, 0, len(root)+len(additions))
+	for _, v := range append(root, additions...) {
+		values = append(values, fmt.Sprint(v))
+	}
+	res := "oer.Enumeration{Values: []int64{" + strings.Join(values, ", ") + "}"
+	if t.Extensible || ctx.extensibilityImplied {
+		res += ", Extensible: true"
+	}
+	return res + "}"
+}
+
+// oerChoiceTag returns tag which identifies alternative of CHOICE type in OER encoding.
+func (ctx *moduleContext) oerChoiceTag(t Type) (asn1Tag, bool) {
+	tags, isAny := ctx.outermostTags(t, nil)
+	if isAny || len(tags) != 1 {
+		return asn1Tag{}, false
+	}
+	return tags[0], true
+}
+
+// oerEncoderGen generates go statements encoding values with OER.
+// Components of SEQUENCE and SET types are encoded in the same order as with PER, see perComponents.
+type oerEncoderGen struct {
+	derEncoderGen
+	// present is go expression of OPTIONAL component being encoded, which is known to be present.
+	present string
+}
+
+// encode writes statements appending encoding of go expression expr of type t to encoder e.
+// Constraints cs are constraints applied to t by enclosing types.
+func (g *oerEncoderGen) encode(ctx *moduleContext, expr string, t Type, cs perConstraints) {
+	switch tt := t.(type) {
+	case TaggedType:
+		g.encode(ctx, expr, tt.Type, cs)
+	case ConstraintedType:
+		if contained, ok := ctx.contentsOf(tt); ok {
+			// contained value is encoded as open type, which is unconstrained OCTET STRING
+			g.line("if err := e.WriteOpenType(func(e *oer.Encoder) error {")
+			g.encode(ctx, expr, contained, nil)
+			g.line("return nil")
+			g.line("}); err != nil {\n\t\treturn err\n\t}")
+			return
+		}
+		g.constrain(ctx, tt)
+		g.encode(ctx, expr, tt.Type, cs.with(ctx, tt.Constraint))
+	case NamedType:
+		g.encode(ctx, expr, tt.Type, cs)
+	case TypeReference:
+		g.encodeReference(ctx, expr, tt, cs)
+	case SequenceType:
+		g.encodeSequence(ctx, expr, tt.Components, tt.ExtensionAdditions, tt.Extensible, false)
+	case SetType:
+		g.encodeSequence(ctx, expr, tt.Components, tt.ExtensionAdditions, tt.Extensible, true)
+	case SequenceOfType:
+		g.encodeElements(ctx, expr, tt.Type)
+	case SetOfType:
+		g.encodeElements(ctx, expr, tt.Type)
+	case ChoiceType:
+		if ctx.params.ChoiceRepr == ChoiceReprInterface {
+			ctx.appendError(fmt.Errorf("CHOICE type %#v should be declared by type assignment", t))
+		} else {
+			ctx.appendError(fmt.Errorf("CHOICE types with %v representation are not supported by OER encoder", ctx.params.ChoiceRepr))
+		}
+	case BooleanType:
+		g.line("e.WriteBoolean(%v)", expr)
+	case IntegerType:
+		if v, big := ctx.integerArg(expr, g.currentIntegerType(ctx)); big {
+			g.check("e.WriteBigInteger(%v, %v)", v, cs.int64Value(ctx).oerExpr())
+		} else {
+			g.check("e.WriteInteger(%v, %v)", v, cs.int64Value(ctx).oerExpr())
+		}
+	case EnumeratedType:
+		ctx.requireModule("encoding/asn1")
+		g.check("e.WriteEnumerated(asn1.Enumerated(%v), %v)", expr, ctx.oerEnumeration(tt))
+	case RealType:
+		g.check("e.WriteReal(%v)", expr)
+	case OctetStringType:
+		g.check("e.WriteOctetString(%v, %v)", expr, cs.size().oerExpr())
+	case BitStringType:
+		if len(tt.NamedBits) > 0 {
+			g.check("e.WriteNamedBitString(%v, %v)", ctx.bitStringExpr(tt, expr), cs.size().oerExpr())
+		} else {
+			g.check("e.WriteBitString(%v, %v)", expr, cs.size().oerExpr())
+		}
+	case NullType:
+		// NULL values have empty encoding
+	case ObjectIdentifierType:
+		g.check("e.WriteObjectIdentifier(%v)", expr)
+	case RestrictedStringType:
+		switch {
+		case tt.LexType == BMPString:
+			g.check("e.WriteBMPString(%v, %v)", expr, cs.size().oerExpr())
+		case tt.LexType == UniversalString:
+			g.check("e.WriteUniversalString(%v, %v)", expr, cs.size().oerExpr())
+		case perCharsets[tt.LexType] != "":
+			g.check("e.WriteString(%v, %v)", expr, cs.size().oerExpr())
+		case restrictedStringTags[tt.LexType] != 0:
+			g.check("e.WriteString(%v, oer.Constraint{})", expr)
+		default:
+			ctx.appendError(fmt.Errorf("restricted string type %#v is not supported by OER encoder", tt))
+		}
+	default:
+		ctx.appendError(fmt.Errorf("type %#v is not supported by OER encoder", t))
+	}
+}
+
+// encodeReference writes statements encoding value of referenced type. Types having OER methods
+// are encoded by calling them, and other types are encoded inline with constraints of the reference.
+func (g *oerEncoderGen) encodeReference(ctx *moduleContext, expr string, t TypeReference, cs perConstraints) {
+	assignment, assignmentCtx, err := ctx.lookupTypeAssignment(t)
+	if err != nil {
+		ctx.appendError(err)
+		return
+	}
+	if assignment == nil {
+		switch t.Name() {
+		case GeneralizedTimeName:
+			g.check("e.WriteGeneralizedTime(%v)", expr)
+		case UTCTimeName:
+			g.check("e.WriteUTCTime(%v)", expr)
+		default:
+			if useful := ctx.lookupUsefulType(t); useful != nil {
+				g.encode(ctx, expr, useful, cs)
+			} else {
+				ctx.appendError(fmt.Errorf("can not resolve TypeReference %v", t.Name()))
+			}
+		}
+		return
+	}
+	if assignmentCtx.hasEncodingMethods(assignment.Type) {
+		if ctx.choiceTypeName(t) != nil && expr != g.present {
+			g.line("if %v == nil {\n\t\treturn oer.ErrAbsentValue\n\t}", expr)
+		}
+		g.check("%v.EncodeOER(e)", expr)
+		return
+	}
+	name := string(assignmentCtx.moduleName) + "." + t.Name()
+	if slices.Contains(g.inlined, name) {
+		ctx.appendError(fmt.Errorf("type %v: reference cycle %v", t, strings.Join(append(g.inlined, name), " -> ")))
+		return
+	}
+	g.inlined = append(g.inlined, name)
+	g.encode(assignmentCtx, assignmentCtx.definedTypeValue(assignment.Type, expr), assignment.Type, cs)
+	g.inlined = g.inlined[:len(g.inlined)-1]
+}
+
+// encodeSequence writes statements encoding SEQUENCE or SET value. Preamble holds extension bit
+// and presence bits of OPTIONAL and DEFAULT components, and is followed by root components.
+// Present extension additions are encoded as open types following presence bitmap of extension additions.
+func (g *oerEncoderGen) encodeSequence(ctx *moduleContext, expr string, components ComponentTypeList, additions ExtensionAdditions, extensible bool, set bool) {
+	extensible = extensible || ctx.extensibilityImplied || len(additions) > 0
+	additionList := ctx.perAdditions(expr, additions)
+	var preamble []string
+	var extensions string
+	if extensible {
+		if len(additionList) == 0 {
+			preamble = append(preamble, "false")
+		} else {
+			conditions := make([]string, 0, len(additionList))
+			for _, a := range additionList {
+				conditions = append(conditions, a.present)
+			}
+			extensions = g.newVar("extensions")
+			g.line("%v := [...]bool{%v}", extensions, strings.Join(conditions, ", "))
+			preamble = append(preamble, fmt.Sprintf("%v != [%v]bool{}", extensions, len(additionList)))
+		}
+	}
+	g.encodeComponents(ctx, ctx.perComponents(expr, components, set), preamble)
+	if extensions == "" {
+		return
+	}
+	g.line("if %v != [%v]bool{} {", extensions, len(additionList))
+	g.line("e.WriteExtensionBitmap(%v[:])", extensions)
+	for i, a := range additionList {
+		g.line("if %v[%v] {", extensions, i)
+		g.line("if err := e.WriteOpenType(func(e *oer.Encoder) error {")
+		if a.group {
+			g.encodeComponents(ctx, a.components, nil)
+		} else {
+			g.encode(ctx, a.components[0].field, a.components[0].named.NamedType.Type, nil)
+		}
+		g.line("return nil")
+		g.line("}); err != nil {\n\t\treturn err\n\t}")
+		g.line("}")
+	}
+	g.line("}")
+}
+
+// encodeComponents writes statements encoding preamble, which starts with bits given by go conditions
+// in preamble and is followed by presence bits of OPTIONAL and DEFAULT components, and present components.
+func (g *oerEncoderGen) encodeComponents(ctx *moduleContext, components []perComponent, preamble []string) {
+	for _, c := range components {
+		if c.present != "" {
+			preamble = append(preamble, c.present)
+		}
+	}
+	if len(preamble) > 0 {
+		g.line("e.WriteBitmap(%v)", strings.Join(preamble, ", "))
+	}
+	for _, c := range components {
+		if c.present != "" {
+			g.line("if %v {", c.present)
+			g.present = c.field
+		}
+		g.encode(ctx, c.field, c.named.NamedType.Type, nil)
+		if c.present != "" {
+			g.line("}")
+			g.present = ""
+		}
+	}
+}
+
+// encodeElements writes statements encoding elements of SEQUENCE OF or SET OF value.
+// Elements of SET OF are encoded in order of the value.
+func (g *oerEncoderGen) encodeElements(ctx *moduleContext, expr string, t Type) {
+	i := g.newVar("i")
+	g.line("if err := e.WriteList(len(%v), func(%v int) error {", expr, i)
+	g.encode(ctx, expr+"["+i+"]", t, nil)
+	g.line("return nil")
+	g.line("}); err != nil {\n\t\treturn err\n\t}")
+}
@@ -0,0 +1,366 @@
+package asn1go
+
+import (
+	"bytes"
+	"fmt"
+	goast "go/ast"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// oerChoiceTemplate generates functions decoding CHOICE type from OER.
+var oerChoiceTemplate = template.Must(template.New("oerChoice").Parse(`
+func UnmarshalOER{{.Name}}(data []byte) ({{.Name}}, []byte, error) {
+	var v {{.Name}}
+	d := oer.NewDecoder(data)
+	if err := DecodeOER{{.Name}}(d, &v); err != nil {
+		return nil, nil, err
+	}
+	return v, d.Rest(), nil
+}
+
+func DecodeOER{{.Name}}(d *oer.Decoder, v *{{.Name}}) error {
+	tag, err := d.ReadTag()
+	if err != nil {
+		return err
+	}
+	switch tag {
+{{- range .Alternatives}}
+	case {{.Tag}}:
+		var alt {{.Name}}
+{{.Body -}}
+		*v = alt
+{{- end}}
+	default:
+{{- if .Extensible}}
+		// value of unknown extension addition can not be represented
+		*v = nil
+		return d.SkipOpenType()
+{{- else}}
+		return oer.SyntaxError{Msg: fmt.Sprintf("unexpected alternative of {{.Name}} with tag %v", tag)}
+{{- end}}
+	}
+	return nil
+}
+`))
+
+type oerChoiceAlternativeParams struct {
+	// Name is a name of the wrapper type.
+	Name string
+	// Tag is go expression of der.Tag identifying the alternative.
+	Tag string
+	// Body holds statements decoding the value into alt.Value.
+	Body string
+}
+
+// generateOERChoiceDecls generates OER encoding methods of wrapper types of CHOICE alternatives,
+// which encode tag of the alternative followed by its value, and functions decoding CHOICE type.
+// Values of extension additions are encoded as open types.
+func (ctx *moduleContext) generateOERChoiceDecls(name string, t ChoiceType, alternatives []choiceAlternativeParams) []goast.Decl {
+	extensible := t.Extensible || ctx.extensibilityImplied || len(t.ExtensionTypes) > 0
+	indices, root := ctx.perChoiceIndices(t)
+	ctx.requireModule(derPackage)
+	ctx.requireModule("fmt")
+	var decls []goast.Decl
+	var params []oerChoiceAlternativeParams
+	for _, alternative := range alternatives {
+		tag, ok := ctx.oerChoiceTag(alternative.alternative.Type)
+		if !ok {
+			ctx.appendError(fmt.Errorf("type %v: can not determine tag of alternative %v", name, alternative.alternative.Identifier.Name()))
+			continue
+		}
+		enc := &oerEncoderGen{}
+		enc.line("e.WriteTag(%v)", tag.derExpr())
+		dec := &oerDecoderGen{}
+		if indices[alternative.alternative.Identifier] < root {
+			enc.encode(ctx, "v.Value", alternative.alternative.Type, nil)
+			dec.decode(ctx, "alt.Value", alternative.alternative.Type, nil)
+		} else {
+			enc.line("if err := e.WriteOpenType(func(e *oer.Encoder) error {")
+			enc.encode(ctx, "v.Value", alternative.alternative.Type, nil)
+			enc.line("return nil")
+			enc.line("}); err != nil {\n\t\treturn err\n\t}")
+			dec.line("if err := d.ReadOpenType(func(d *oer.Decoder) error {")
+			dec.decode(ctx, "alt.Value", alternative.alternative.Type, nil)
+			dec.line("return nil")
+			dec.line("}); err != nil {\n\t\treturn err\n\t}")
+		}
+		decls = append(decls, ctx.executeOERMethodsTemplate(perMethodsParams{Name: alternative.Name, Encode: enc.buf.String()})...)
+		params = append(params, oerChoiceAlternativeParams{Name: alternative.Name, Tag: tag.derExpr(), Body: dec.buf.String()})
+	}
+	var buf bytes.Buffer
+	err := oerChoiceTemplate.Execute(&buf, map[string]any{"Name": name, "Extensible": extensible, "Alternatives": params})
+	if err != nil {
+		ctx.appendError(fmt.Errorf("type %v: %w", name, err))
+		return nil
+	}
+	choiceDecls, err := parseGoDecls(buf.String())
+	if err != nil {
+		ctx.appendError(fmt.Errorf("type %v: failed to parse generated code: %w", name, err))
+		return nil
+	}
+	return append(decls, choiceDecls...)
+}
+
+// oerDecoderGen generates go statements decoding values from OER.
+type oerDecoderGen struct {
+	derEncoderGen
+}
+
+// checkVar writes statements assigning result of a call returning error to a new variable.
+func (g *oerDecoderGen) checkVar(prefix string, format string, args ...any) string {
+	name := g.newVar(prefix)
+	g.line("%v, err := "+format, append([]any{name}, args...)...)
+	g.line("if err != nil {\n\t\treturn err\n\t}")
+	return name
+}
+
+// decode writes statements decoding the next value of decoder d into addressable go expression expr of type t.
+// Constraints cs are constraints applied to t by enclosing types.
+func (g *oerDecoderGen) decode(ctx *moduleContext, expr string, t Type, cs perConstraints) {
+	switch tt := t.(type) {
+	case TaggedType:
+		g.decode(ctx, expr, tt.Type, cs)
+	case ConstraintedType:
+		if contained, ok := ctx.contentsOf(tt); ok {
+			g.line("if err := d.ReadOpenType(func(d *oer.Decoder) error {")
+			g.decode(ctx, expr, contained, nil)
+			g.line("return nil")
+			g.line("}); err != nil {\n\t\treturn err\n\t}")
+			return
+		}
+		g.constrain(ctx, tt)
+		g.decode(ctx, expr, tt.Type, cs.with(ctx, tt.Constraint))
+	case NamedType:
+		g.decode(ctx, expr, tt.Type, cs)
+	case TypeReference:
+		g.decodeReference(ctx, expr, tt, cs)
+	case SequenceType:
+		g.decodeSequence(ctx, expr, tt.Components, tt.ExtensionAdditions, tt.Extensible, false)
+	case SetType:
+		g.decodeSequence(ctx, expr, tt.Components, tt.ExtensionAdditions, tt.Extensible, true)
+	case SequenceOfType:
+		g.decodeElements(ctx, expr, tt.Type)
+	case SetOfType:
+		g.decodeElements(ctx, expr, tt.Type)
+	case ChoiceType:
+		if ctx.params.ChoiceRepr == ChoiceReprInterface {
+			ctx.appendError(fmt.Errorf("CHOICE type %#v should be declared by type assignment", t))
+		} else {
+			ctx.appendError(fmt.Errorf("CHOICE types with %v representation are not supported by OER decoder", ctx.params.ChoiceRepr))
+		}
+	case BooleanType:
+		g.check("d.ReadBoolean(&%v)", expr)
+	case IntegerType:
+		goType := g.currentIntegerType(ctx)
+		v, big := g.integerTarget(ctx, expr, goType)
+		if big {
+			g.check("d.ReadBigInteger(&%v, %v)", v, cs.int64Value(ctx).oerExpr())
+		} else {
+			g.check("d.ReadInteger(&%v, %v)", v, cs.int64Value(ctx).oerExpr())
+		}
+		g.assignInteger(ctx, expr, v, goType)
+	case EnumeratedType:
+		ctx.requireModule("encoding/asn1")
+		g.check("d.ReadEnumerated((*asn1.Enumerated)(&%v), %v)", expr, ctx.oerEnumeration(tt))
+	case RealType:
+		g.check("d.ReadReal(&%v)", expr)
+	case OctetStringType:
+		g.check("d.ReadOctetString(&%v, %v)", expr, cs.size().oerExpr())
+	case BitStringType:
+		if len(tt.NamedBits) > 0 {
+			g.check("d.ReadNamedBitString(%v, %v)", ctx.bitStringPointerExpr(tt, expr), cs.size().oerExpr())
+		} else {
+			g.check("d.ReadBitString(&%v, %v)", expr, cs.size().oerExpr())
+		}
+	case NullType:
+		// NULL values have empty encoding
+	case ObjectIdentifierType:
+		g.check("d.ReadObjectIdentifier(&%v)", expr)
+	case RestrictedStringType:
+		switch {
+		case tt.LexType == BMPString:
+			g.check("d.ReadBMPString(&%v, %v)", expr, cs.size().oerExpr())
+		case tt.LexType == UniversalString:
+			g.check("d.ReadUniversalString(&%v, %v)", expr, cs.size().oerExpr())
+		case perCharsets[tt.LexType] != "":
+			g.check("d.ReadString(&%v, %v)", expr, cs.size().oerExpr())
+		case restrictedStringTags[tt.LexType] != 0:
+			g.check("d.ReadString(&%v, oer.Constraint{})", expr)
+		default:
+			ctx.appendError(fmt.Errorf("restricted string type %#v is not supported by OER decoder", tt))
+		}
+	default:
+		ctx.appendError(fmt.Errorf("type %#v is not supported by OER decoder", t))
+	}
+}
+
+// decodeReference writes statements decoding value of referenced type. Types having OER methods
+// are decoded by calling them, and other types are decoded inline with constraints of the reference.
+func (g *oerDecoderGen) decodeReference(ctx *moduleContext, expr string, t TypeReference, cs perConstraints) {
+	assignment, assignmentCtx, err := ctx.lookupTypeAssignment(t)
+	if err != nil {
+		ctx.appendError(err)
+		return
+	}
+	if assignment == nil {
+		switch t.Name() {
+		case GeneralizedTimeName:
+			g.check("d.ReadGeneralizedTime(&%v)", expr)
+		case UTCTimeName:
+			g.check("d.ReadUTCTime(&%v)", expr)
+		default:
+			if useful := ctx.lookupUsefulType(t); useful != nil {
+				g.decode(ctx, expr, useful, cs)
+			} else {
+				ctx.appendError(fmt.Errorf("can not resolve TypeReference %v", t.Name()))
+			}
+		}
+		return
+	}
+	if assignmentCtx.hasEncodingMethods(assignment.Type) {
+		if choiceName := ctx.choiceTypeName(t); choiceName != nil {
+			g.check("%v(d, &%v)", exprString(choiceMemberExpr(choiceName, "DecodeOER", "")), expr)
+			return
+		}
+		g.check("%v.DecodeOER(d)", expr)
+		return
+	}
+	name := string(assignmentCtx.moduleName) + "." + t.Name()
+	if slices.Contains(g.inlined, name) {
+		ctx.appendError(fmt.Errorf("type %v: reference cycle %v", t, strings.Join(append(g.inlined, name), " -> ")))
+		return
+	}
+	g.inlined = append(g.inlined, name)
+	g.decode(assignmentCtx, assignmentCtx.definedTypeVariable(assignment.Type, expr), assignment.Type, cs)
+	g.inlined = g.inlined[:len(g.inlined)-1]
+}
+
+// decodeSequence writes statements decoding SEQUENCE or SET value, see oerEncoderGen.encodeSequence.
+// Absent OPTIONAL components are reset, and absent components with DEFAULT values are set to them.
+// Unknown extension additions are skipped.
+func (g *oerDecoderGen) decodeSequence(ctx *moduleContext, expr string, components ComponentTypeList, additions ExtensionAdditions, extensible bool, set bool) {
+	extensible = extensible || ctx.extensibilityImplied || len(additions) > 0
+	extended := g.decodeComponents(ctx, ctx.perComponents(expr, components, set), extensible)
+	if !extensible {
+		return
+	}
+	// absent additions are reset or set to their DEFAULT values before decoding present ones
+	additionList := ctx.perAdditions(expr, additions)
+	for _, a := range additionList {
+		for _, c := range a.components {
+			if c.named.Default != nil {
+				g.line("%v = %v", c.field, ctx.derDefaultExpr(c.named.NamedType, *c.named.Default))
+			} else if absent := ctx.absentComponent(c.field, c.pointer, c.named); absent != "" {
+				g.line(absent)
+			}
+		}
+	}
+	g.line("if %v {", extended)
+	extensions := g.checkVar("extensions", "d.ReadExtensionBitmap()")
+	if len(additionList) == 0 {
+		present := g.newVar("present")
+		g.line("for _, %v := range %v {", present, extensions)
+		g.line("if %v {", present)
+		g.check("d.SkipOpenType()")
+		g.line("}")
+		g.line("}")
+		g.line("}")
+		return
+	}
+	i, present := g.newVar("i"), g.newVar("present")
+	g.line("for %v, %v := range %v {", i, present, extensions)
+	g.line("switch {")
+	g.line("case !%v:", present)
+	for j, a := range additionList {
+		g.line("case %v == %v:", i, j)
+		g.line("if err := d.ReadOpenType(func(d *oer.Decoder) error {")
+		if a.group {
+			g.decodeComponents(ctx, a.components, false)
+		} else {
+			c := a.components[0]
+			if c.pointer != "" {
+				g.line(ctx.allocComponent(c.pointer, c.named))
+			}
+			g.decode(ctx, c.field, c.named.NamedType.Type, nil)
+			if present := ctx.presentComponent(c.field, c.named); present != "" {
+				g.line(present)
+			}
+		}
+		g.line("return nil")
+		g.line("}); err != nil {\n\t\treturn err\n\t}")
+	}
+	g.line("default:")
+	g.check("d.SkipOpenType()")
+	g.line("}")
+	g.line("}")
+	g.line("}")
+}
+
+// decodeComponents writes statements decoding preamble, followed by present components.
+// If extensible is set, preamble starts with extension bit, and go expression of the bit is returned.
+// In canonical mode, components equal to their DEFAULT values are rejected.
+func (g *oerDecoderGen) decodeComponents(ctx *moduleContext, components []perComponent, extensible bool) string {
+	n := 0
+	if extensible {
+		n++
+	}
+	for _, c := range components {
+		if c.present != "" {
+			n++
+		}
+	}
+	var preamble string
+	if n > 0 {
+		preamble = g.checkVar("preamble", "d.ReadBitmap(%v)", n)
+	}
+	k := 0
+	if extensible {
+		k++
+	}
+	for _, c := range components {
+		if c.present == "" {
+			g.decode(ctx, c.field, c.named.NamedType.Type, nil)
+			continue
+		}
+		g.line("if %v[%v] {", preamble, k)
+		if c.pointer != "" {
+			g.line(ctx.allocComponent(c.pointer, c.named))
+		}
+		g.decode(ctx, c.field, c.named.NamedType.Type, nil)
+		if present := ctx.presentComponent(c.field, c.named); present != "" {
+			g.line(present)
+		}
+		if c.named.Default != nil {
+			if equal := ctx.derDefaultComparison(c.field, c.named.NamedType, *c.named.Default, "=="); equal != "" {
+				g.line("if d.Canonical && %v {", equal)
+				g.line("return oer.SyntaxError{Msg: %q}", "DEFAULT value of component "+c.named.NamedType.Identifier.Name()+" is encoded")
+				g.line("}")
+			}
+			g.line("} else {")
+			g.line("%v = %v", c.field, ctx.derDefaultExpr(c.named.NamedType, *c.named.Default))
+		} else if absent := ctx.absentComponent(c.field, c.pointer, c.named); absent != "" {
+			g.line("} else {")
+			g.line(absent)
+		}
+		g.line("}")
+		k++
+	}
+	if extensible {
+		return preamble + "[0]"
+	}
+	return ""
+}
+
+// decodeElements writes statements decoding elements of SEQUENCE OF or SET OF value.
+func (g *oerDecoderGen) decodeElements(ctx *moduleContext, expr string, t Type) {
+	elem := g.newVar("elem")
+	g.line("%v = %v[:0]", expr, expr)
+	g.line("if err := d.ReadList(func() error {")
+	g.line("var %v %v", elem, exprString(ctx.generateTypeExpr(t)))
+	g.decode(ctx, elem, t, nil)
+	g.line("%v = append(%v, %v)", expr, expr, elem)
+	g.line("return nil")
+	g.line("}); err != nil {\n\t\treturn err\n\t}")
+}
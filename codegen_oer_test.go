@@ -0,0 +1,102 @@
+package asn1go
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestOERConstraints(t *testing.T) {
+	testCases := []struct {
+		name     string
+		typeDecl string
+		expected string
+	}{
+		{
+			name:     "value range",
+			typeDecl: "INTEGER (0..255)",
+			expected: "e.WriteInteger(v.F, oer.Constraint{Lower: 0, Upper: 255, HasLower: true, HasUpper: true})",
+		},
+		{
+			name:     "extensible range is not visible",
+			typeDecl: "INTEGER (0..255, ...)",
+			expected: "e.WriteInteger(v.F, oer.Constraint{})",
+		},
+		{
+			name:     "fixed size of string",
+			typeDecl: "OCTET STRING (SIZE (8))",
+			expected: "e.WriteOctetString(v.F, oer.Constraint{Lower: 8, Upper: 8, HasLower: true, HasUpper: true})",
+		},
+		{
+			name:     "size of list is not visible",
+			typeDecl: "SEQUENCE (SIZE (1..8)) OF BOOLEAN",
+			expected: "e.WriteList(len(v.F), func(i int) error {",
+		},
+		{
+			name:     "size of UTF8String is not visible",
+			typeDecl: "UTF8String (SIZE (1..4))",
+			expected: "e.WriteString(v.F, oer.Constraint{})",
+		},
+		{
+			name:     "extensible enumeration",
+			typeDecl: "ENUMERATED { a, b, ..., c(10) }",
+			expected: "e.WriteEnumerated(asn1.Enumerated(v.F), oer.Enumeration{Values: []int64{0, 1, 10}, Extensible: true})",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := parseModule(t, `
+			TestSpec DEFINITIONS AUTOMATIC TAGS ::= BEGIN
+				Msg ::= SEQUENCE { f `+tc.typeDecl+` }
+			END
+			`)
+			buf := &bytes.Buffer{}
+			if err := NewCodeGenerator(GenParams{Type: GEN_OER}).Generate(*m, buf); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !strings.Contains(buf.String(), tc.expected) {
+				t.Errorf("Generated module does not contain %v:\n%v", tc.expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestOERMethodsErrors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		module   string
+		expected string
+	}{
+		{
+			name: "ANY type",
+			module: `TestSpec DEFINITIONS ::= BEGIN
+				Msg ::= SEQUENCE { value ANY }
+			END`,
+			expected: "not supported by OER",
+		},
+		{
+			name: "untagged CHOICE alternative",
+			module: `TestSpec DEFINITIONS ::= BEGIN
+				Inner ::= CHOICE { a [0] BOOLEAN, b [1] NULL }
+				Msg ::= CHOICE { inner Inner, c [2] INTEGER }
+			END`,
+			expected: "can not determine tag of alternative inner",
+		},
+		{
+			name: "bounds exceeding int64",
+			module: `TestSpec DEFINITIONS ::= BEGIN
+				Msg ::= SEQUENCE { counter INTEGER (0..18446744073709551615) }
+			END`,
+			expected: "INTEGER constraint 0..18446744073709551615: PER and OER encoding of bounds exceeding int64 is not supported",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := parseModule(t, tc.module)
+			err := NewCodeGenerator(GenParams{Type: GEN_OER}).Generate(*m, &bytes.Buffer{})
+			if err == nil || !strings.Contains(err.Error(), tc.expected) {
+				t.Errorf("Expected error containing %q, got %v", tc.expected, err)
+			}
+		})
+	}
+}
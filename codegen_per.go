@@ -0,0 +1,658 @@
+package asn1go
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	goast "go/ast"
+	"math"
+	"math/big"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// perPackage is import path of runtime package used by generated PER encoders.
+const perPackage = "github.com/chemikadze/asn1go/per"
+
+// perMethodsTemplate generates methods encoding and decoding the type with PER.
+var perMethodsTemplate = template.Must(template.New("per").Parse(`
+func (v {{.Name}}) MarshalPER(variant per.Variant) ([]byte, error) {
+	return per.Marshal(v, variant)
+}
+
+func (v {{.Name}}) EncodePER(e *per.Encoder) error {
+{{.Encode -}}
+	return nil
+}
+{{- if .Decodable}}
+
+func (v *{{.Name}}) UnmarshalPER(data []byte, variant per.Variant) error {
+	return per.Unmarshal(data, v, variant)
+}
+
+func (v *{{.Name}}) DecodePER(d *per.Decoder) error {
+{{.Decode -}}
+	return nil
+}
+{{- end}}
+`))
+
+type perMethodsParams struct {
+	Name string
+	// Encode holds statements encoding the value to e.
+	Encode string
+	// Decode holds statements decoding the value from d.
+	Decode string
+	// Decodable is set if decoding methods should be generated. Wrapper types of CHOICE alternatives
+	// are decoded by functions of CHOICE type instead.
+	Decodable bool
+}
+
+// perCharsets maps lexem types of known-multiplier character string types to their alphabets in per package.
+// Values of other restricted string types are encoded as octets, see X.691, section 30.6.
+var perCharsets = map[int]string{
+	BMPString:       "per.BMPString",
+	IA5String:       "per.IA5String",
+	ISO646String:    "per.VisibleString",
+	NumericString:   "per.NumericString",
+	PrintableString: "per.PrintableString",
+	UniversalString: "per.UniversalString",
+	VisibleString:   "per.VisibleString",
+}
+
+// generatePERDecls generates PER encoding and decoding methods of the type declared by decl.
+// See generateDERDecls for types which get methods.
+func (ctx *moduleContext) generatePERDecls(a TypeAssignment, decl goast.Decl) []goast.Decl {
+	name := goifyName(a.TypeReference.Name())
+	switch t := ctx.removeWrapperTypes(a.Type).(type) {
+	case SequenceType, SetType:
+		return ctx.generatePERMethods(name, "v", "v", a.Type)
+	case TypeReference:
+		// references declared as defined types do not share methods with referenced types, see isDefinedReference
+		if !ctx.isDefinedReference(a.Type) {
+			return nil
+		}
+		typeName := exprString(ctx.generateTypeExpr(t))
+		return ctx.generatePERMethods(name, typeName+"(v)", "(*"+typeName+")(v)", a.Type)
+	default:
+		return nil
+	}
+}
+
+// generatePERMethods generates MarshalPER, EncodePER, UnmarshalPER and DecodePER methods of go type typeName,
+// which encode go expression encodeExpr of type t, and decode into addressable go expression decodeExpr.
+func (ctx *moduleContext) generatePERMethods(typeName string, encodeExpr string, decodeExpr string, t Type) []goast.Decl {
+	enc := &perEncoderGen{}
+	enc.encode(ctx, encodeExpr, t, nil)
+	dec := &perDecoderGen{}
+	dec.decode(ctx, decodeExpr, t, nil)
+	return ctx.executePERMethodsTemplate(perMethodsParams{Name: typeName, Encode: enc.buf.String(), Decode: dec.buf.String(), Decodable: true})
+}
+
+func (ctx *moduleContext) executePERMethodsTemplate(params perMethodsParams) []goast.Decl {
+	ctx.requireModule(perPackage)
+	var buf bytes.Buffer
+	if err := perMethodsTemplate.Execute(&buf, params); err != nil {
+		ctx.appendError(fmt.Errorf("type %v: %w", params.Name, err))
+		return nil
+	}
+	decls, err := parseGoDecls(buf.String())
+	if err != nil {
+		ctx.appendError(fmt.Errorf("type %v: failed to parse generated code: %w", params.Name, err))
+		return nil
+	}
+	return decls
+}
+
+// perBounds is effective PER-visible constraint of INTEGER values, or of sizes, see X.691, section 10.3.
+// It is generated as per.Constraint value.
+// Bounds are nil if values are not bounded from below or from above.
+type perBounds struct {
+	lower, upper *big.Int
+	extensible   bool
+}
+
+// perConstraints holds constraints applied to the type by enclosing types, starting from the outermost one.
+// Constraints are evaluated only if encoding of the type depends on them.
+type perConstraints []perConstraint
+
+type perConstraint struct {
+	constraint Constraint
+	// ctx is context of the module where constraint is defined.
+	ctx *moduleContext
+}
+
+// with returns constraints with constraint c of the nested type added.
+func (cs perConstraints) with(ctx *moduleContext, c Constraint) perConstraints {
+	return append(slices.Clip(cs), perConstraint{constraint: c, ctx: ctx})
+}
+
+// value returns effective constraint of INTEGER values.
+func (cs perConstraints) value() perBounds {
+	return perDimensionBounds(cs.evaluate(true).values)
+}
+
+// int64Value returns effective constraint of INTEGER values, which bounds are limited to int64 by per.Constraint
+// and oer.Constraint. Bounds exceeding int64 are reported to ctx, and values are encoded as not bounded.
+func (cs perConstraints) int64Value(ctx *moduleContext) perBounds {
+	b := cs.value()
+	if (b.lower != nil && !b.lower.IsInt64()) || (b.upper != nil && !b.upper.IsInt64()) {
+		ctx.appendError(fmt.Errorf("INTEGER constraint %v: PER and OER encoding of bounds exceeding int64 is not supported", Range{Lower: b.lower, Upper: b.upper}))
+		return perBounds{}
+	}
+	return b
+}
+
+// size returns effective constraint of sizes.
+func (cs perConstraints) size() perBounds {
+	return perDimensionBounds(cs.evaluate(true).sizes)
+}
+
+// charset returns go expression of the alphabet of known-multiplier character string type, which is charset
+// restricted by PER-visible permitted alphabet constraint, see X.691, section 30.1.
+func (cs perConstraints) charset(charset string) string {
+	alphabet := cs.evaluate(true).alphabet
+	if !alphabet.constrained || alphabet.extensible || len(alphabet.set) == 0 {
+		return charset
+	}
+	ranges := make([]string, 0, len(alphabet.set))
+	for _, r := range alphabet.set {
+		upper := int64(math.MaxInt32)
+		if r.Upper != nil {
+			upper = r.Upper.Int64()
+		}
+		ranges = append(ranges, fmt.Sprintf("[2]rune{%v, %v}", runeLiteral(r.Lower.Int64()), runeLiteral(upper)))
+	}
+	return charset + ".Restrict(" + strings.Join(ranges, ", ") + ")"
+}
+
+// evaluate returns values permitted by serially applied constraints, see constraintEvaluator.
+// Constraints are applied from the innermost one, so that the outermost one determines whether values are extensible.
+func (cs perConstraints) evaluate(perVisible bool) constraintSet {
+	e := &constraintEvaluator{perVisible: perVisible}
+	var res constraintSet
+	for _, c := range slices.Backward(cs) {
+		res = res.apply(e.constraint(c.ctx, c.constraint))
+	}
+	return res
+}
+
+// perDimensionBounds returns bounds of PER-visible values, which are encoded as if any value between them
+// was permitted.
+func perDimensionBounds(d constraintDimension[RangeSet]) perBounds {
+	bounds, ok := d.set.Bounds()
+	if !d.constrained || !ok {
+		return perBounds{}
+	}
+	return perBounds{lower: bounds.Lower, upper: bounds.Upper, extensible: d.extensible}
+}
+
+// expr returns go expression of per.Constraint value.
+func (b perBounds) expr() string {
+	var fields []string
+	if b.lower != nil {
+		fields = append(fields, fmt.Sprintf("Lower: %v", b.lower))
+	}
+	if b.upper != nil {
+		fields = append(fields, fmt.Sprintf("Upper: %v", b.upper))
+	}
+	if b.lower != nil {
+		fields = append(fields, "HasLower: true")
+	}
+	if b.upper != nil {
+		fields = append(fields, "HasUpper: true")
+	}
+	if b.extensible && (b.lower != nil || b.upper != nil) {
+		fields = append(fields, "Extensible: true")
+	}
+	return "per.Constraint{" + strings.Join(fields, ", ") + "}"
+}
+
+// perEnumeration returns go expression of per.Enumeration value describing ENUMERATED type.
+func (ctx *moduleContext) perEnumeration(t EnumeratedType) string {
+	root, additions, err := ctx.enumerationValues(t)
+	if err != nil {
+		ctx.appendError(err)
+		return "per.Enumeration{}"
+	}
+	join := func(values []int64) string {
+		res := make([]string, 0, len(values))
+		for _, v := range values {
+			res = append(res, fmt.Sprint(v))
+		}
+		return strings.Join(res, ", ")
+	}
+	fields := []string{"Root: []int64{" + join(root) + "}"}
+	if len(additions) > 0 {
+		fields = append(fields, "Additions: []int64{"+join(additions)+"}")
+	}
+	if t.Extensible || ctx.extensibilityImplied {
+		fields = append(fields, "Extensible: true")
+	}
+	return "per.Enumeration{" + strings.Join(fields, ", ") + "}"
+}
+
+// enumerationValues returns values of root enumeration in ascending order, and values of additional enumeration
+// in order of their definition, see enumerationItems.
+func (ctx *moduleContext) enumerationValues(t EnumeratedType) ([]int64, []int64, error) {
+	root, additions, err := ctx.enumerationItems(t)
+	if err != nil {
+		return nil, nil, err
+	}
+	values := func(items []enumerationItem) []int64 {
+		res := make([]int64, 0, len(items))
+		for _, item := range items {
+			res = append(res, item.value)
+		}
+		return res
+	}
+	return values(root), values(additions), nil
+}
+
+// enumerationItem is an identifier of ENUMERATED type with its value.
+type enumerationItem struct {
+	name  string
+	value int64
+}
+
+// enumerationItems returns items of root enumeration in ascending order of their values, and items of additional
+// enumeration in order of their definition. Items without numbers are assigned values as defined by X.680, section 20.
+func (ctx *moduleContext) enumerationItems(t EnumeratedType) ([]enumerationItem, []enumerationItem, error) {
+	nameOf := func(item EnumerationItem) string {
+		if named, ok := item.(NamedNumber); ok {
+			return named.Name.Name()
+		}
+		return item.(Identifier).Name()
+	}
+	valueOf := func(item EnumerationItem) (int64, bool, error) {
+		named, ok := item.(NamedNumber)
+		if !ok {
+			return 0, false, nil
+		}
+		value, ok := named.Value.(Value)
+		if !ok {
+			return 0, false, fmt.Errorf("enumeration item %v: unexpected value %#v", named.Name, named.Value)
+		}
+		v, _, err := ctx.lookupValue(value)
+		if err != nil {
+			return 0, false, fmt.Errorf("enumeration item %v: %w", named.Name, err)
+		}
+		n, ok := v.(Number)
+		if !ok {
+			return 0, false, fmt.Errorf("enumeration item %v: value should be Number, got %#v", named.Name, v)
+		}
+		return int64(n), true, nil
+	}
+	used := make(map[int64]bool)
+	root := make([]enumerationItem, len(t.RootEnumeration))
+	numbered := make([]bool, len(t.RootEnumeration))
+	for i, item := range t.RootEnumeration {
+		v, ok, err := valueOf(item)
+		if err != nil {
+			return nil, nil, err
+		}
+		root[i], numbered[i], used[v] = enumerationItem{name: nameOf(item), value: v}, ok, used[v] || ok
+	}
+	next := int64(0)
+	for i := range root {
+		if numbered[i] {
+			continue
+		}
+		for used[next] {
+			next++
+		}
+		root[i].value, used[next] = next, true
+	}
+	additions := make([]enumerationItem, 0, len(t.AdditionalEnumeration))
+	last := int64(-1)
+	for _, item := range t.AdditionalEnumeration {
+		v, ok, err := valueOf(item)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !ok {
+			for v = last + 1; used[v]; v++ {
+			}
+		}
+		additions = append(additions, enumerationItem{name: nameOf(item), value: v})
+		used[v], last = true, v
+	}
+	slices.SortFunc(root, func(a, b enumerationItem) int { return cmp.Compare(a.value, b.value) })
+	return root, additions, nil
+}
+
+// canonicalTagOrder sorts types by canonical order of their tags, see X.680, section 8.6.
+// Untagged CHOICE types are ordered by the smallest tag of their alternatives.
+// Returns permutation of indices of types.
+func (ctx *moduleContext) canonicalTagOrder(types []Type) []int {
+	rank := map[int]int{CLASS_UNIVERSAL: 0, CLASS_APPLICATION: 1, CLASS_CONTEXT_SPECIFIC: 2, CLASS_PRIVATE: 3}
+	compareTags := func(a, b asn1Tag) int {
+		return cmp.Or(cmp.Compare(rank[a.Class], rank[b.Class]), cmp.Compare(a.Number, b.Number))
+	}
+	keys := make([]*asn1Tag, len(types))
+	order := make([]int, len(types))
+	for i, t := range types {
+		order[i] = i
+		if tags, isAny := ctx.outermostTags(t, nil); !isAny && len(tags) > 0 {
+			tag := slices.MinFunc(tags, compareTags)
+			keys[i] = &tag
+		}
+	}
+	slices.SortStableFunc(order, func(i, j int) int {
+		switch {
+		case keys[i] == nil || keys[j] == nil:
+			return 0
+		default:
+			return compareTags(*keys[i], *keys[j])
+		}
+	})
+	return order
+}
+
+// perComponent is a component of SEQUENCE or SET value.
+type perComponent struct {
+	named NamedComponentType
+	field string
+	// pointer is go expression of the struct field if component is represented with pointer, see componentField.
+	pointer string
+	// present is go condition which is true if OPTIONAL or DEFAULT component should be encoded.
+	// It is empty for mandatory components.
+	present string
+}
+
+// perAddition is an extension addition of SEQUENCE or SET, which is either a single component,
+// or a group of components.
+type perAddition struct {
+	components []perComponent
+	group      bool
+	// present is go condition which is true if addition should be encoded.
+	present string
+}
+
+// perComponents returns components of SEQUENCE or SET with go expressions of their fields,
+// in order of their encoding. Components of SET are encoded in canonical order of their tags, see X.691, section 21.
+func (ctx *moduleContext) perComponents(expr string, list ComponentTypeList, set bool) []perComponent {
+	var res []perComponent
+	for _, component := range list {
+		named, ok := component.(NamedComponentType)
+		if !ok {
+			continue // COMPONENTS OF is reported as unsupported by structFromComponents
+		}
+		c := perComponent{named: named}
+		c.field, c.pointer = ctx.componentField(expr, named)
+		switch {
+		case named.Default != nil:
+			c.present = ctx.derNonDefaultCheck(c.field, named.NamedType, *named.Default)
+		case named.IsOptional:
+			c.present = ctx.optionalPresenceCheck(c.field, c.pointer, named.NamedType.Type)
+		}
+		res = append(res, c)
+	}
+	if !set {
+		return res
+	}
+	types := make([]Type, len(res))
+	for i, c := range res {
+		types[i] = c.named.NamedType.Type
+	}
+	sorted := make([]perComponent, 0, len(res))
+	for _, i := range ctx.canonicalTagOrder(types) {
+		sorted = append(sorted, res[i])
+	}
+	return sorted
+}
+
+// perAdditions returns extension additions of SEQUENCE or SET in order of their definition.
+// Values of earlier versions of the specification do not have mandatory additions, so they are treated
+// as absent if they have zero values.
+func (ctx *moduleContext) perAdditions(expr string, additions ExtensionAdditions) []perAddition {
+	present := func(c perComponent) string {
+		if c.present != "" {
+			return c.present
+		}
+		return ctx.derNonZeroCheck(c.field, c.named.NamedType.Type)
+	}
+	var res []perAddition
+	for _, addition := range additions {
+		switch a := addition.(type) {
+		case ExtensionAdditionGroup:
+			group := perAddition{components: ctx.perComponents(expr, a.Components, false), group: true}
+			conditions := make([]string, 0, len(group.components))
+			for _, c := range group.components {
+				conditions = append(conditions, "("+present(c)+")")
+			}
+			if len(conditions) == 0 {
+				continue
+			}
+			group.present = strings.Join(conditions, " || ")
+			res = append(res, group)
+		case ComponentType:
+			components := ctx.perComponents(expr, ComponentTypeList{a}, false)
+			if len(components) == 0 {
+				continue
+			}
+			res = append(res, perAddition{components: components, present: present(components[0])})
+		}
+	}
+	return res
+}
+
+// perChoiceIndices returns indices of CHOICE alternatives in PER encoding, and number of alternatives
+// in extension root. Root alternatives and extension additions are numbered in canonical order of their tags,
+// see X.691, section 23.
+func (ctx *moduleContext) perChoiceIndices(t ChoiceType) (map[Identifier]int, int) {
+	var additions []NamedType
+	for _, ext := range t.ExtensionTypes {
+		switch e := ext.(type) {
+		case NamedType:
+			additions = append(additions, e)
+		case ExtensionAdditionAlternativesGroup:
+			additions = append(additions, e.Alternatives...)
+		}
+	}
+	res := make(map[Identifier]int)
+	next := 0
+	for _, list := range [][]NamedType{t.AlternativeTypeList, additions} {
+		types := make([]Type, len(list))
+		for i, alternative := range list {
+			types[i] = alternative.Type
+		}
+		for _, i := range ctx.canonicalTagOrder(types) {
+			res[list[i].Identifier] = next
+			next++
+		}
+	}
+	return res, len(t.AlternativeTypeList)
+}
+
+// perEncoderGen generates go statements encoding values with PER.
+type perEncoderGen struct {
+	derEncoderGen
+	// present is go expression of OPTIONAL component being encoded, which is known to be present.
+	present string
+}
+
+// encode writes statements appending encoding of go expression expr of type t to encoder e.
+// Constraints cs are PER-visible constraints applied to t by enclosing types.
+func (g *perEncoderGen) encode(ctx *moduleContext, expr string, t Type, cs perConstraints) {
+	switch tt := t.(type) {
+	case TaggedType:
+		g.encode(ctx, expr, tt.Type, cs)
+	case ConstraintedType:
+		if contained, ok := ctx.contentsOf(tt); ok {
+			// contained value is encoded as open type, which is unconstrained OCTET STRING
+			g.line("if err := e.WriteOpenType(func(e *per.Encoder) error {")
+			g.encode(ctx, expr, contained, nil)
+			g.line("return nil")
+			g.line("}); err != nil {\n\t\treturn err\n\t}")
+			return
+		}
+		g.constrain(ctx, tt)
+		g.encode(ctx, expr, tt.Type, cs.with(ctx, tt.Constraint))
+	case NamedType:
+		g.encode(ctx, expr, tt.Type, cs)
+	case TypeReference:
+		g.encodeReference(ctx, expr, tt, cs)
+	case SequenceType:
+		g.encodeSequence(ctx, expr, tt.Components, tt.ExtensionAdditions, tt.Extensible, false)
+	case SetType:
+		g.encodeSequence(ctx, expr, tt.Components, tt.ExtensionAdditions, tt.Extensible, true)
+	case SequenceOfType:
+		g.encodeElements(ctx, expr, tt.Type, cs)
+	case SetOfType:
+		g.encodeElements(ctx, expr, tt.Type, cs)
+	case ChoiceType:
+		if ctx.params.ChoiceRepr == ChoiceReprInterface {
+			ctx.appendError(fmt.Errorf("CHOICE type %#v should be declared by type assignment", t))
+		} else {
+			ctx.appendError(fmt.Errorf("CHOICE types with %v representation are not supported by PER encoder", ctx.params.ChoiceRepr))
+		}
+	case BooleanType:
+		g.line("e.WriteBoolean(%v)", expr)
+	case IntegerType:
+		if v, big := ctx.integerArg(expr, g.currentIntegerType(ctx)); big {
+			g.check("e.WriteBigInteger(%v, %v)", v, cs.int64Value(ctx).expr())
+		} else {
+			g.check("e.WriteInteger(%v, %v)", v, cs.int64Value(ctx).expr())
+		}
+	case EnumeratedType:
+		ctx.requireModule("encoding/asn1")
+		g.check("e.WriteEnumerated(asn1.Enumerated(%v), %v)", expr, ctx.perEnumeration(tt))
+	case RealType:
+		g.check("e.WriteReal(%v)", expr)
+	case OctetStringType:
+		g.check("e.WriteOctetString(%v, %v)", expr, cs.size().expr())
+	case BitStringType:
+		if len(tt.NamedBits) > 0 {
+			g.check("e.WriteNamedBitString(%v, %v)", ctx.bitStringExpr(tt, expr), cs.size().expr())
+		} else {
+			g.check("e.WriteBitString(%v, %v)", expr, cs.size().expr())
+		}
+	case NullType:
+		// NULL values have empty encoding
+	case ObjectIdentifierType:
+		g.check("e.WriteObjectIdentifier(%v)", expr)
+	case RestrictedStringType:
+		if charset, ok := perCharsets[tt.LexType]; ok {
+			g.check("e.WriteString(%v, %v, %v)", expr, cs.charset(charset), cs.size().expr())
+		} else if _, ok := restrictedStringTags[tt.LexType]; ok {
+			g.check("e.WriteUTF8String(%v)", expr)
+		} else {
+			ctx.appendError(fmt.Errorf("restricted string type %#v is not supported by PER encoder", tt))
+		}
+	default:
+		ctx.appendError(fmt.Errorf("type %#v is not supported by PER encoder", t))
+	}
+}
+
+// encodeReference writes statements encoding value of referenced type. Types having PER methods
+// are encoded by calling them, and other types are encoded inline with constraints of the reference.
+func (g *perEncoderGen) encodeReference(ctx *moduleContext, expr string, t TypeReference, cs perConstraints) {
+	assignment, assignmentCtx, err := ctx.lookupTypeAssignment(t)
+	if err != nil {
+		ctx.appendError(err)
+		return
+	}
+	if assignment == nil {
+		switch t.Name() {
+		case GeneralizedTimeName:
+			g.check("e.WriteGeneralizedTime(%v)", expr)
+		case UTCTimeName:
+			g.check("e.WriteUTCTime(%v)", expr)
+		default:
+			if useful := ctx.lookupUsefulType(t); useful != nil {
+				g.encode(ctx, expr, useful, cs)
+			} else {
+				ctx.appendError(fmt.Errorf("can not resolve TypeReference %v", t.Name()))
+			}
+		}
+		return
+	}
+	if assignmentCtx.hasEncodingMethods(assignment.Type) {
+		if ctx.choiceTypeName(t) != nil && expr != g.present {
+			g.line("if %v == nil {\n\t\treturn per.ErrAbsentValue\n\t}", expr)
+		}
+		g.check("%v.EncodePER(e)", expr)
+		return
+	}
+	name := string(assignmentCtx.moduleName) + "." + t.Name()
+	if slices.Contains(g.inlined, name) {
+		ctx.appendError(fmt.Errorf("type %v: reference cycle %v", t, strings.Join(append(g.inlined, name), " -> ")))
+		return
+	}
+	g.inlined = append(g.inlined, name)
+	g.encode(assignmentCtx, assignmentCtx.definedTypeValue(assignment.Type, expr), assignment.Type, cs)
+	g.inlined = g.inlined[:len(g.inlined)-1]
+}
+
+// encodeSequence writes statements encoding SEQUENCE or SET value, see X.691, sections 19 and 21.
+// Extension bit is followed by root components, and present extension additions are encoded as open types.
+func (g *perEncoderGen) encodeSequence(ctx *moduleContext, expr string, components ComponentTypeList, additions ExtensionAdditions, extensible bool, set bool) {
+	extensible = extensible || ctx.extensibilityImplied || len(additions) > 0
+	additionList := ctx.perAdditions(expr, additions)
+	var extensions string
+	if extensible {
+		if len(additionList) == 0 {
+			g.line("e.WriteBit(false)")
+		} else {
+			conditions := make([]string, 0, len(additionList))
+			for _, a := range additionList {
+				conditions = append(conditions, a.present)
+			}
+			extensions = g.newVar("extensions")
+			g.line("%v := [...]bool{%v}", extensions, strings.Join(conditions, ", "))
+			g.line("e.WriteBit(%v != [%v]bool{})", extensions, len(additionList))
+		}
+	}
+	g.encodeComponents(ctx, ctx.perComponents(expr, components, set))
+	if extensions == "" {
+		return
+	}
+	g.line("if %v != [%v]bool{} {", extensions, len(additionList))
+	g.line("e.WriteExtensionBitmap(%v[:])", extensions)
+	for i, a := range additionList {
+		g.line("if %v[%v] {", extensions, i)
+		g.line("if err := e.WriteOpenType(func(e *per.Encoder) error {")
+		if a.group {
+			g.encodeComponents(ctx, a.components)
+		} else {
+			g.encode(ctx, a.components[0].field, a.components[0].named.NamedType.Type, nil)
+		}
+		g.line("return nil")
+		g.line("}); err != nil {\n\t\treturn err\n\t}")
+		g.line("}")
+	}
+	g.line("}")
+}
+
+// encodeComponents writes statements encoding presence bitmap of OPTIONAL and DEFAULT components,
+// followed by present components.
+func (g *perEncoderGen) encodeComponents(ctx *moduleContext, components []perComponent) {
+	for _, c := range components {
+		if c.present != "" {
+			g.line("e.WriteBit(%v)", c.present)
+		}
+	}
+	for _, c := range components {
+		if c.present != "" {
+			g.line("if %v {", c.present)
+			g.present = c.field
+		}
+		g.encode(ctx, c.field, c.named.NamedType.Type, nil)
+		if c.present != "" {
+			g.line("}")
+			g.present = ""
+		}
+	}
+}
+
+// encodeElements writes statements encoding elements of SEQUENCE OF or SET OF value with number of elements
+// constrained by cs. Elements of SET OF are encoded in order of the value.
+func (g *perEncoderGen) encodeElements(ctx *moduleContext, expr string, t Type, cs perConstraints) {
+	i := g.newVar("i")
+	g.line("if err := e.WriteList(len(%v), %v, func(%v int) error {", expr, cs.size().expr(), i)
+	g.encode(ctx, expr+"["+i+"]", t, nil)
+	g.line("return nil")
+	g.line("}); err != nil {\n\t\treturn err\n\t}")
+}
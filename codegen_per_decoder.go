@@ -0,0 +1,341 @@
+package asn1go
+
+import (
+	"bytes"
+	"fmt"
+	goast "go/ast"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// perChoiceTemplate generates functions decoding CHOICE type from PER.
+var perChoiceTemplate = template.Must(template.New("perChoice").Parse(`
+func UnmarshalPER{{.Name}}(data []byte, variant per.Variant) ({{.Name}}, error) {
+	var v {{.Name}}
+	if err := DecodePER{{.Name}}(per.NewDecoder(data, variant), &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func DecodePER{{.Name}}(d *per.Decoder, v *{{.Name}}) error {
+	index, err := d.ReadChoiceIndex({{.Root}}, {{.Extensible}})
+	if err != nil {
+		return err
+	}
+	switch index {
+{{- range .Alternatives}}
+	case {{.Index}}:
+		var alt {{.Name}}
+{{.Body -}}
+		*v = alt
+{{- end}}
+{{- if .Extensible}}
+	default:
+		// value of unknown extension addition can not be represented
+		*v = nil
+		return d.SkipOpenType()
+{{- end}}
+	}
+	return nil
+}
+`))
+
+type perChoiceAlternativeParams struct {
+	// Name is a name of the wrapper type.
+	Name  string
+	Index int
+	// Body holds statements decoding the value into alt.Value.
+	Body string
+}
+
+// generatePERChoiceDecls generates PER encoding methods of wrapper types of CHOICE alternatives,
+// which encode index of the alternative followed by its value, and functions decoding CHOICE type.
+// Values of extension additions are encoded as open types.
+func (ctx *moduleContext) generatePERChoiceDecls(name string, t ChoiceType, alternatives []choiceAlternativeParams) []goast.Decl {
+	indices, root := ctx.perChoiceIndices(t)
+	extensible := t.Extensible || ctx.extensibilityImplied || len(t.ExtensionTypes) > 0
+	var decls []goast.Decl
+	var params []perChoiceAlternativeParams
+	for _, alternative := range alternatives {
+		index := indices[alternative.alternative.Identifier]
+		enc := &perEncoderGen{}
+		enc.check("e.WriteChoiceIndex(%v, %v, %v)", index, root, extensible)
+		dec := &perDecoderGen{}
+		if index < root {
+			enc.encode(ctx, "v.Value", alternative.alternative.Type, nil)
+			dec.decode(ctx, "alt.Value", alternative.alternative.Type, nil)
+		} else {
+			enc.line("if err := e.WriteOpenType(func(e *per.Encoder) error {")
+			enc.encode(ctx, "v.Value", alternative.alternative.Type, nil)
+			enc.line("return nil")
+			enc.line("}); err != nil {\n\t\treturn err\n\t}")
+			dec.line("if err := d.ReadOpenType(func(d *per.Decoder) error {")
+			dec.decode(ctx, "alt.Value", alternative.alternative.Type, nil)
+			dec.line("return nil")
+			dec.line("}); err != nil {\n\t\treturn err\n\t}")
+		}
+		decls = append(decls, ctx.executePERMethodsTemplate(perMethodsParams{Name: alternative.Name, Encode: enc.buf.String()})...)
+		params = append(params, perChoiceAlternativeParams{Name: alternative.Name, Index: index, Body: dec.buf.String()})
+	}
+	slices.SortFunc(params, func(a, b perChoiceAlternativeParams) int { return a.Index - b.Index })
+	var buf bytes.Buffer
+	err := perChoiceTemplate.Execute(&buf, map[string]any{"Name": name, "Root": root, "Extensible": extensible, "Alternatives": params})
+	if err != nil {
+		ctx.appendError(fmt.Errorf("type %v: %w", name, err))
+		return nil
+	}
+	choiceDecls, err := parseGoDecls(buf.String())
+	if err != nil {
+		ctx.appendError(fmt.Errorf("type %v: failed to parse generated code: %w", name, err))
+		return nil
+	}
+	return append(decls, choiceDecls...)
+}
+
+// perDecoderGen generates go statements decoding values from PER.
+type perDecoderGen struct {
+	derEncoderGen
+}
+
+// checkVar writes statements assigning result of a call returning error to a new variable.
+func (g *perDecoderGen) checkVar(prefix string, format string, args ...any) string {
+	name := g.newVar(prefix)
+	g.line("%v, err := "+format, append([]any{name}, args...)...)
+	g.line("if err != nil {\n\t\treturn err\n\t}")
+	return name
+}
+
+// decode writes statements decoding the next value of decoder d into addressable go expression expr of type t.
+// Constraints cs are PER-visible constraints applied to t by enclosing types.
+func (g *perDecoderGen) decode(ctx *moduleContext, expr string, t Type, cs perConstraints) {
+	switch tt := t.(type) {
+	case TaggedType:
+		g.decode(ctx, expr, tt.Type, cs)
+	case ConstraintedType:
+		if contained, ok := ctx.contentsOf(tt); ok {
+			g.line("if err := d.ReadOpenType(func(d *per.Decoder) error {")
+			g.decode(ctx, expr, contained, nil)
+			g.line("return nil")
+			g.line("}); err != nil {\n\t\treturn err\n\t}")
+			return
+		}
+		g.constrain(ctx, tt)
+		g.decode(ctx, expr, tt.Type, cs.with(ctx, tt.Constraint))
+	case NamedType:
+		g.decode(ctx, expr, tt.Type, cs)
+	case TypeReference:
+		g.decodeReference(ctx, expr, tt, cs)
+	case SequenceType:
+		g.decodeSequence(ctx, expr, tt.Components, tt.ExtensionAdditions, tt.Extensible, false)
+	case SetType:
+		g.decodeSequence(ctx, expr, tt.Components, tt.ExtensionAdditions, tt.Extensible, true)
+	case SequenceOfType:
+		g.decodeElements(ctx, expr, tt.Type, cs)
+	case SetOfType:
+		g.decodeElements(ctx, expr, tt.Type, cs)
+	case ChoiceType:
+		if ctx.params.ChoiceRepr == ChoiceReprInterface {
+			ctx.appendError(fmt.Errorf("CHOICE type %#v should be declared by type assignment", t))
+		} else {
+			ctx.appendError(fmt.Errorf("CHOICE types with %v representation are not supported by PER decoder", ctx.params.ChoiceRepr))
+		}
+	case BooleanType:
+		g.check("d.ReadBoolean(&%v)", expr)
+	case IntegerType:
+		goType := g.currentIntegerType(ctx)
+		v, big := g.integerTarget(ctx, expr, goType)
+		if big {
+			g.check("d.ReadBigInteger(&%v, %v)", v, cs.int64Value(ctx).expr())
+		} else {
+			g.check("d.ReadInteger(&%v, %v)", v, cs.int64Value(ctx).expr())
+		}
+		g.assignInteger(ctx, expr, v, goType)
+	case EnumeratedType:
+		ctx.requireModule("encoding/asn1")
+		g.check("d.ReadEnumerated((*asn1.Enumerated)(&%v), %v)", expr, ctx.perEnumeration(tt))
+	case RealType:
+		g.check("d.ReadReal(&%v)", expr)
+	case OctetStringType:
+		g.check("d.ReadOctetString(&%v, %v)", expr, cs.size().expr())
+	case BitStringType:
+		if len(tt.NamedBits) > 0 {
+			g.check("d.ReadNamedBitString(%v, %v)", ctx.bitStringPointerExpr(tt, expr), cs.size().expr())
+		} else {
+			g.check("d.ReadBitString(&%v, %v)", expr, cs.size().expr())
+		}
+	case NullType:
+		// NULL values have empty encoding
+	case ObjectIdentifierType:
+		g.check("d.ReadObjectIdentifier(&%v)", expr)
+	case RestrictedStringType:
+		if charset, ok := perCharsets[tt.LexType]; ok {
+			g.check("d.ReadString(&%v, %v, %v)", expr, cs.charset(charset), cs.size().expr())
+		} else if _, ok := restrictedStringTags[tt.LexType]; ok {
+			g.check("d.ReadUTF8String(&%v)", expr)
+		} else {
+			ctx.appendError(fmt.Errorf("restricted string type %#v is not supported by PER decoder", tt))
+		}
+	default:
+		ctx.appendError(fmt.Errorf("type %#v is not supported by PER decoder", t))
+	}
+}
+
+// decodeReference writes statements decoding value of referenced type. Types having PER methods
+// are decoded by calling them, and other types are decoded inline with constraints of the reference.
+func (g *perDecoderGen) decodeReference(ctx *moduleContext, expr string, t TypeReference, cs perConstraints) {
+	assignment, assignmentCtx, err := ctx.lookupTypeAssignment(t)
+	if err != nil {
+		ctx.appendError(err)
+		return
+	}
+	if assignment == nil {
+		switch t.Name() {
+		case GeneralizedTimeName:
+			g.check("d.ReadGeneralizedTime(&%v)", expr)
+		case UTCTimeName:
+			g.check("d.ReadUTCTime(&%v)", expr)
+		default:
+			if useful := ctx.lookupUsefulType(t); useful != nil {
+				g.decode(ctx, expr, useful, cs)
+			} else {
+				ctx.appendError(fmt.Errorf("can not resolve TypeReference %v", t.Name()))
+			}
+		}
+		return
+	}
+	if assignmentCtx.hasEncodingMethods(assignment.Type) {
+		if choiceName := ctx.choiceTypeName(t); choiceName != nil {
+			g.check("%v(d, &%v)", exprString(choiceMemberExpr(choiceName, "DecodePER", "")), expr)
+			return
+		}
+		g.check("%v.DecodePER(d)", expr)
+		return
+	}
+	name := string(assignmentCtx.moduleName) + "." + t.Name()
+	if slices.Contains(g.inlined, name) {
+		ctx.appendError(fmt.Errorf("type %v: reference cycle %v", t, strings.Join(append(g.inlined, name), " -> ")))
+		return
+	}
+	g.inlined = append(g.inlined, name)
+	g.decode(assignmentCtx, assignmentCtx.definedTypeVariable(assignment.Type, expr), assignment.Type, cs)
+	g.inlined = g.inlined[:len(g.inlined)-1]
+}
+
+// decodeSequence writes statements decoding SEQUENCE or SET value, see perEncoderGen.encodeSequence.
+// Absent OPTIONAL components are reset, and absent components with DEFAULT values are set to them.
+// Unknown extension additions are skipped.
+func (g *perDecoderGen) decodeSequence(ctx *moduleContext, expr string, components ComponentTypeList, additions ExtensionAdditions, extensible bool, set bool) {
+	extensible = extensible || ctx.extensibilityImplied || len(additions) > 0
+	var extended string
+	if extensible {
+		extended = g.checkVar("extended", "d.ReadBit()")
+	}
+	g.decodeComponents(ctx, ctx.perComponents(expr, components, set))
+	if !extensible {
+		return
+	}
+	// absent additions are reset or set to their DEFAULT values before decoding present ones
+	additionList := ctx.perAdditions(expr, additions)
+	for _, a := range additionList {
+		for _, c := range a.components {
+			if c.named.Default != nil {
+				g.line("%v = %v", c.field, ctx.derDefaultExpr(c.named.NamedType, *c.named.Default))
+			} else if absent := ctx.absentComponent(c.field, c.pointer, c.named); absent != "" {
+				g.line(absent)
+			}
+		}
+	}
+	g.line("if %v {", extended)
+	extensions := g.checkVar("extensions", "d.ReadExtensionBitmap()")
+	if len(additionList) == 0 {
+		present := g.newVar("present")
+		g.line("for _, %v := range %v {", present, extensions)
+		g.line("if %v {", present)
+		g.check("d.SkipOpenType()")
+		g.line("}")
+		g.line("}")
+		g.line("}")
+		return
+	}
+	i, present := g.newVar("i"), g.newVar("present")
+	g.line("for %v, %v := range %v {", i, present, extensions)
+	g.line("switch {")
+	g.line("case !%v:", present)
+	for j, a := range additionList {
+		g.line("case %v == %v:", i, j)
+		g.line("if err := d.ReadOpenType(func(d *per.Decoder) error {")
+		if a.group {
+			g.decodeComponents(ctx, a.components)
+		} else {
+			c := a.components[0]
+			if c.pointer != "" {
+				g.line(ctx.allocComponent(c.pointer, c.named))
+			}
+			g.decode(ctx, c.field, c.named.NamedType.Type, nil)
+			if present := ctx.presentComponent(c.field, c.named); present != "" {
+				g.line(present)
+			}
+		}
+		g.line("return nil")
+		g.line("}); err != nil {\n\t\treturn err\n\t}")
+	}
+	g.line("default:")
+	g.check("d.SkipOpenType()")
+	g.line("}")
+	g.line("}")
+	g.line("}")
+}
+
+// decodeComponents writes statements decoding presence bitmap of OPTIONAL and DEFAULT components,
+// followed by present components.
+func (g *perDecoderGen) decodeComponents(ctx *moduleContext, components []perComponent) {
+	n := 0
+	for _, c := range components {
+		if c.present != "" {
+			n++
+		}
+	}
+	var preamble string
+	if n > 0 {
+		preamble = g.checkVar("preamble", "d.ReadBitmap(%v)", n)
+	}
+	k := 0
+	for _, c := range components {
+		if c.present == "" {
+			g.decode(ctx, c.field, c.named.NamedType.Type, nil)
+			continue
+		}
+		g.line("if %v[%v] {", preamble, k)
+		if c.pointer != "" {
+			g.line(ctx.allocComponent(c.pointer, c.named))
+		}
+		g.decode(ctx, c.field, c.named.NamedType.Type, nil)
+		if present := ctx.presentComponent(c.field, c.named); present != "" {
+			g.line(present)
+		}
+		if c.named.Default != nil {
+			g.line("} else {")
+			g.line("%v = %v", c.field, ctx.derDefaultExpr(c.named.NamedType, *c.named.Default))
+		} else if absent := ctx.absentComponent(c.field, c.pointer, c.named); absent != "" {
+			g.line("} else {")
+			g.line(absent)
+		}
+		g.line("}")
+		k++
+	}
+}
+
+// decodeElements writes statements decoding elements of SEQUENCE OF or SET OF value with number of elements
+// constrained by cs.
+func (g *perDecoderGen) decodeElements(ctx *moduleContext, expr string, t Type, cs perConstraints) {
+	elem := g.newVar("elem")
+	g.line("%v = %v[:0]", expr, expr)
+	g.line("if err := d.ReadList(%v, func() error {", cs.size().expr())
+	g.line("var %v %v", elem, exprString(ctx.generateTypeExpr(t)))
+	g.decode(ctx, elem, t, nil)
+	g.line("%v = append(%v, %v)", expr, expr, elem)
+	g.line("return nil")
+	g.line("}); err != nil {\n\t\treturn err\n\t}")
+}
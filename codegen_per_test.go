@@ -0,0 +1,100 @@
+package asn1go
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestPERConstraints(t *testing.T) {
+	testCases := []struct {
+		name     string
+		typeDecl string
+		expected string
+	}{
+		{
+			name:     "value range",
+			typeDecl: "INTEGER (0..7)",
+			expected: "e.WriteInteger(v.F, per.Constraint{Lower: 0, Upper: 7, HasLower: true, HasUpper: true})",
+		},
+		{
+			name:     "semi-constrained range",
+			typeDecl: "INTEGER (-1..MAX)",
+			expected: "e.WriteInteger(v.F, per.Constraint{Lower: -1, HasLower: true})",
+		},
+		{
+			name:     "single value",
+			typeDecl: "INTEGER (5)",
+			expected: "e.WriteInteger(v.F, per.Constraint{Lower: 5, Upper: 5, HasLower: true, HasUpper: true})",
+		},
+		{
+			name:     "union of ranges",
+			typeDecl: "INTEGER (1..3 | 10..12)",
+			expected: "e.WriteInteger(v.F, per.Constraint{Lower: 1, Upper: 12, HasLower: true, HasUpper: true})",
+		},
+		{
+			name:     "intersection of ranges",
+			typeDecl: "INTEGER (0..10 ^ 5..20)",
+			expected: "e.WriteInteger(v.F, per.Constraint{Lower: 5, Upper: 10, HasLower: true, HasUpper: true})",
+		},
+		{
+			name:     "extensible range",
+			typeDecl: "INTEGER (0..7, ...)",
+			expected: "e.WriteInteger(v.F, per.Constraint{Lower: 0, Upper: 7, HasLower: true, HasUpper: true, Extensible: true})",
+		},
+		{
+			name:     "referenced constrained type",
+			typeDecl: "Small (2..MAX)",
+			expected: "e.WriteInteger(v.F, per.Constraint{Lower: 2, Upper: 7, HasLower: true, HasUpper: true})",
+		},
+		{
+			name:     "size of string",
+			typeDecl: "OCTET STRING (SIZE (4))",
+			expected: "e.WriteOctetString(v.F, per.Constraint{Lower: 4, Upper: 4, HasLower: true, HasUpper: true})",
+		},
+		{
+			name:     "extensible size of list",
+			typeDecl: "SEQUENCE (SIZE (1..8, ...)) OF BOOLEAN",
+			expected: "e.WriteList(len(v.F), per.Constraint{Lower: 1, Upper: 8, HasLower: true, HasUpper: true, Extensible: true}",
+		},
+		{
+			name:     "extensible size of string",
+			typeDecl: "IA5String (SIZE (1..4, ...))",
+			expected: "e.WriteString(v.F, per.IA5String, per.Constraint{Lower: 1, Upper: 4, HasLower: true, HasUpper: true, Extensible: true})",
+		},
+		{
+			name:     "size of UTF8String is not visible",
+			typeDecl: "UTF8String (SIZE (1..4))",
+			expected: "e.WriteUTF8String(v.F)",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := parseModule(t, `
+			TestSpec DEFINITIONS AUTOMATIC TAGS ::= BEGIN
+				Small ::= INTEGER (0..7)
+				Msg ::= SEQUENCE { f `+tc.typeDecl+` }
+			END
+			`)
+			buf := &bytes.Buffer{}
+			if err := NewCodeGenerator(GenParams{Type: GEN_PER}).Generate(*m, buf); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !strings.Contains(buf.String(), tc.expected) {
+				t.Errorf("Generated module does not contain %v:\n%v", tc.expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestPERMethodsErrors(t *testing.T) {
+	m := parseModule(t, `
+	TestSpec DEFINITIONS ::= BEGIN
+		Msg ::= SEQUENCE { value ANY }
+	END
+	`)
+	err := NewCodeGenerator(GenParams{Type: GEN_PER}).Generate(*m, &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "not supported by PER") {
+		t.Errorf("Expected error about ANY type, got %v", err)
+	}
+}
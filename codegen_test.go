@@ -3,7 +3,9 @@ package asn1go
 import (
 	"bytes"
 	"github.com/google/go-cmp/cmp"
+	"go/format"
 	"go/token"
+	"strings"
 	"testing"
 
 	goparser "go/parser"
@@ -11,8 +13,12 @@ import (
 )
 
 func generateDeclarationsString(m ModuleDefinition) (string, error) {
+	return generateString(m, GenParams{})
+}
+
+func generateString(m ModuleDefinition, params GenParams) (string, error) {
 	bufw := bytes.NewBufferString("")
-	gen := NewCodeGenerator(GenParams{})
+	gen := NewCodeGenerator(params)
 	err := gen.Generate(m, bufw)
 	if err != nil {
 		return "", err
@@ -165,6 +171,8 @@ type e2eTestCase struct {
 	name      string
 	asnModule string
 	goModule  string
+	// params are generator params, GenParams{} is used if not set.
+	params GenParams
 }
 
 func testParsingAndGeneration(t *testing.T, testCases []e2eTestCase) {
@@ -182,10 +190,11 @@ func testParsingAndGeneration(t *testing.T, testCases []e2eTestCase) {
 			}
 			expected = normalizedBuf.String()
 
-			got, err := generateDeclarationsString(*m)
-			if err != nil {
+			bufw := &bytes.Buffer{}
+			if err := NewCodeGenerator(tc.params).Generate(*m, bufw); err != nil {
 				t.Fatalf("Unexpected error: %v", err.Error())
 			}
+			got := bufw.String()
 			if diff := cmp.Diff(expected, got); diff != "" {
 				t.Errorf("Generated module did not match expected, diff (-want, +got): %v", diff)
 			}
@@ -308,6 +317,209 @@ var (
 	testParsingAndGeneration(t, testCases)
 }
 
+func TestEnumeratedType(t *testing.T) {
+	asnModule := `
+	TestSpec DEFINITIONS IMPLICIT TAGS ::= BEGIN
+		Color ::= ENUMERATED { red, green(5), ..., blue }
+		Paint ::= SEQUENCE { color Color DEFAULT green }
+		favorite Color ::= blue
+	END
+	`
+	testCases := []e2eTestCase{
+		{
+			name:      "named type",
+			asnModule: asnModule,
+			params:    GenParams{EnumRepr: EnumReprType},
+			goModule: `package TestSpec
+
+import "encoding/asn1"
+import "strconv"
+
+type Color asn1.Enumerated
+
+const (
+	ColorValRed	Color	= 0
+	ColorValGreen	Color	= 5
+	ColorValBlue	Color	= 1
+)
+
+func (v Color) String() string {
+	switch v {
+	case ColorValRed:
+		return "red"
+	case ColorValGreen:
+		return "green"
+	case ColorValBlue:
+		return "blue"
+	}
+	return "Color(" + strconv.Itoa(int(v)) + ")"
+}
+func (v Color) IsValid() bool {
+	switch v {
+	case ColorValRed, ColorValGreen, ColorValBlue:
+		return true
+	}
+	return false
+}
+
+type Paint struct {
+	Color Color ` + "`asn1:\"optional\"`" + `
+}
+
+var ValFavorite Color = 1
+`,
+		},
+		{
+			name:      "alias representation",
+			asnModule: asnModule,
+			goModule: `package TestSpec
+
+import "encoding/asn1"
+
+type Color = asn1.Enumerated
+
+const (
+	ColorValRed	Color	= 0
+	ColorValGreen	Color	= 5
+	ColorValBlue	Color	= 1
+)
+
+type Paint struct {
+	Color Color ` + "`asn1:\"optional\"`" + `
+}
+
+var ValFavorite Color = 1
+`,
+		},
+	}
+	testParsingAndGeneration(t, testCases)
+}
+
+func TestEnumReprDefault(t *testing.T) {
+	m := parseModule(t, `
+	TestSpec DEFINITIONS AUTOMATIC TAGS ::= BEGIN
+		Color ::= ENUMERATED { red, green }
+	END
+	`)
+	testCases := []struct {
+		name     string
+		params   GenParams
+		expected string
+	}{
+		// encoding/asn1 encodes named types as INTEGER, so only asn1.Enumerated itself is ENUMERATED
+		{name: "without encoding methods", params: GenParams{}, expected: "type Color = asn1.Enumerated"},
+		{name: "with encoding methods", params: GenParams{Type: GEN_DER}, expected: "func (v Color) IsValid() bool {"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := generateString(*m, tc.params)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !strings.Contains(got, tc.expected) {
+				t.Errorf("Generated module does not contain %v:\n%v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestBitStringReprDefault(t *testing.T) {
+	m := parseModule(t, `
+	TestSpec DEFINITIONS AUTOMATIC TAGS ::= BEGIN
+		KeyUsage ::= BIT STRING { digitalSignature(0), keyCertSign(5) }
+	END
+	`)
+	testCases := []struct {
+		name     string
+		params   GenParams
+		expected string
+	}{
+		// encoding/asn1 can not encode named types, so only asn1.BitString itself is BIT STRING
+		{name: "without encoding methods", params: GenParams{}, expected: "func KeyUsageSet(v *KeyUsage, bit int) {"},
+		{name: "with encoding methods", params: GenParams{Type: GEN_DER}, expected: "func (v *KeyUsage) Set(bit int) {"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := generateString(*m, tc.params)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !strings.Contains(got, tc.expected) {
+				t.Errorf("Generated module does not contain %v:\n%v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestNamedBitString(t *testing.T) {
+	testCases := []e2eTestCase{
+		{
+			name:   "named type",
+			params: GenParams{BitStringRepr: BitStringReprType},
+			asnModule: `
+	TestSpec DEFINITIONS IMPLICIT TAGS ::= BEGIN
+		KeyUsage ::= BIT STRING { keyCertSign(5), digitalSignature(0) }
+		Key ::= SEQUENCE { id INTEGER, usage KeyUsage }
+		signing KeyUsage ::= { digitalSignature, keyCertSign }
+	END
+	`,
+			goModule: `package TestSpec
+
+import "encoding/asn1"
+
+type KeyUsage asn1.BitString
+
+const (
+	KeyUsageBitDigitalSignature	= 0
+	KeyUsageBitKeyCertSign		= 5
+)
+
+func (v KeyUsage) Has(bit int) bool {
+	return asn1.BitString(v).At(bit) == 1
+}
+func (v *KeyUsage) Set(bit int) {
+	length := max(v.BitLength, bit+1)
+	bytes := make([]byte, (length+7)/8)
+	copy(bytes, v.Bytes)
+	bytes[bit/8] |= 0x80 >> (bit % 8)
+	v.Bytes, v.BitLength = bytes, length
+}
+func (v *KeyUsage) Clear(bit int) {
+	if !v.Has(bit) {
+		return
+	}
+	bytes := make([]byte, len(v.Bytes))
+	copy(bytes, v.Bytes)
+	bytes[bit/8] &^= 0x80 >> (bit % 8)
+	length := v.BitLength
+	for length > 0 && bytes[(length-1)/8]&(0x80>>((length-1)%8)) == 0 {
+		length--
+	}
+	v.Bytes, v.BitLength = bytes[:(length+7)/8], length
+}
+func (v KeyUsage) Names() []string {
+	var res []string
+	if v.Has(KeyUsageBitDigitalSignature) {
+		res = append(res, "digitalSignature")
+	}
+	if v.Has(KeyUsageBitKeyCertSign) {
+		res = append(res, "keyCertSign")
+	}
+	return res
+}
+
+type Key struct {
+	Id	int64
+	Usage	KeyUsage
+}
+
+var ValSigning KeyUsage = KeyUsage{Bytes: []byte{0x84}, BitLength: 6}
+`,
+		},
+	}
+	testParsingAndGeneration(t, testCases)
+}
+
 func TestValueAssignments(t *testing.T) {
 	testCases := []e2eTestCase{
 		{
@@ -332,6 +544,483 @@ var ValMyReal float64 = 3.14
 	testParsingAndGeneration(t, testCases)
 }
 
+func TestIntegerWrittenRealValues(t *testing.T) {
+	testCases := []e2eTestCase{
+		{
+			name: "REAL values without fraction or exponent",
+			asnModule: `
+	TestSpec DEFINITIONS IMPLICIT TAGS ::= BEGIN
+		S ::= SEQUENCE { x REAL DEFAULT 1, y REAL }
+		plusReal REAL ::= 5
+		minusReal REAL ::= -5
+		s S ::= { x 2, y -3 }
+	END
+	`,
+			goModule: `package TestSpec
+
+type S struct {
+	X float64 ` + "`asn1:\"default:1,optional\"`" + `
+	Y float64
+}
+
+var ValPlusReal float64 = 5
+
+var ValMinusReal float64 = -5
+
+var ValS S = S{X: 2, Y: -3}
+`,
+		},
+	}
+	testParsingAndGeneration(t, testCases)
+}
+
+func TestReferencedValues(t *testing.T) {
+	testCases := []e2eTestCase{
+		{
+			name: "value references",
+			asnModule: `
+	TestSpec DEFINITIONS IMPLICIT TAGS ::= BEGIN
+		Int ::= INTEGER { low(1), high(ub-int) }
+		Pair ::= SEQUENCE { a INTEGER, b Int }
+		ub-int INTEGER ::= 10
+		maxInt INTEGER ::= ub-int
+		qualified INTEGER ::= TestSpec.maxInt
+		named Int ::= high
+		pair Pair ::= { a maxInt, b low }
+	END
+	`,
+			goModule: `package TestSpec
+
+type Int = int64
+
+var (
+	IntValLow  Int = 1
+	IntValHigh Int = ValUb_int
+)
+
+type Pair struct {
+	A int64
+	B Int
+}
+
+var ValUb_int int64 = 10
+
+var ValMaxInt int64 = 10
+
+var ValQualified int64 = 10
+
+var ValNamed Int = 10
+
+var ValPair Pair = Pair{A: 10, B: 1}
+`,
+		},
+		{
+			name: "tag number references",
+			asnModule: `
+	TestSpec DEFINITIONS IMPLICIT TAGS ::= BEGIN
+		Message ::= SEQUENCE {
+			id [APPLICATION app-tag-base] INTEGER,
+			name [context-tag] UTF8String (SIZE (1..ub-name))
+		}
+		app-tag-base INTEGER ::= 10
+		context-tag INTEGER ::= app-tag-base
+		ub-name INTEGER ::= 64
+	END
+	`,
+			goModule: `package TestSpec
+
+type Message struct {
+	Id   int64  ` + "`asn1:\"application,tag:10\"`" + `
+	Name string ` + "`asn1:\"tag:10,utf8\"`" + `
+}
+
+var ValApp_tag_base int64 = 10
+
+var ValContext_tag int64 = 10
+
+var ValUb_name int64 = 64
+`,
+		},
+	}
+	testParsingAndGeneration(t, testCases)
+}
+
+func TestReferencedValueErrors(t *testing.T) {
+	testCases := []struct {
+		name      string
+		asnModule string
+		expected  string
+	}{
+		{
+			name: "undefined value",
+			asnModule: `
+	TestSpec DEFINITIONS IMPLICIT TAGS ::= BEGIN
+		a INTEGER ::= b
+	END
+	`,
+			expected: "value a: value b is not defined",
+		},
+		{
+			name: "braced value of wrong type",
+			asnModule: `
+	TestSpec DEFINITIONS IMPLICIT TAGS ::= BEGIN
+		Int ::= INTEGER
+		a Int ::= { 1, 2 }
+	END
+	`,
+			expected: "value a: braced value is not valid for type Int, or is not supported",
+		},
+		{
+			name: "reference cycle",
+			asnModule: `
+	TestSpec DEFINITIONS IMPLICIT TAGS ::= BEGIN
+		a INTEGER ::= b
+		b INTEGER ::= TestSpec.c
+		c INTEGER ::= b
+	END
+	`,
+			expected: "value a: reference cycle b -> c -> b",
+		},
+		{
+			name: "undefined tag value",
+			asnModule: `
+	TestSpec DEFINITIONS IMPLICIT TAGS ::= BEGIN
+		Message ::= SEQUENCE { id [APPLICATION app-tag-base] INTEGER }
+	END
+	`,
+			expected: "type id: tag value: value app-tag-base is not defined",
+		},
+		{
+			name: "self-referencing object identifier",
+			asnModule: `
+	TestSpec DEFINITIONS IMPLICIT TAGS ::= BEGIN
+		id-a OBJECT IDENTIFIER ::= { id-b 1 }
+		id-b OBJECT IDENTIFIER ::= { id-a 2 }
+	END
+	`,
+			expected: "value id-a: id-b: OBJECT IDENTIFIER value references itself",
+		},
+		{
+			name: "object identifier reference in the middle",
+			asnModule: `
+	TestSpec DEFINITIONS IMPLICIT TAGS ::= BEGIN
+		id-a OBJECT IDENTIFIER ::= { 1 2 }
+		id-b OBJECT IDENTIFIER ::= { 1 id-a }
+	END
+	`,
+			expected: "value id-b: id-a: OBJECT IDENTIFIER reference is allowed only as first component",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := parseModule(t, tc.asnModule)
+			_, err := generateDeclarationsString(*m)
+			if err == nil {
+				t.Fatalf("Expected error %q, got nil", tc.expected)
+			}
+			if !strings.Contains(err.Error(), tc.expected) {
+				t.Errorf("Expected error %q, got %q", tc.expected, err.Error())
+			}
+		})
+	}
+}
+
+func TestStringValueAssignments(t *testing.T) {
+	testCases := []e2eTestCase{
+		{
+			name: "bit and octet strings",
+			asnModule: `
+	TestSpec DEFINITIONS IMPLICIT TAGS ::= BEGIN
+		KerberosFlags ::= BIT STRING (SIZE (32..MAX))
+		TicketFlags ::= BIT STRING { reserved(0), forwardable(1), proxiable(3) }
+		defaultFlags KerberosFlags ::= '00000000'H
+		someBits BIT STRING ::= '101'B
+		ticketFlags TicketFlags ::= { forwardable, proxiable }
+		noFlags TicketFlags ::= { }
+		octets OCTET STRING ::= '0AF'H
+	END
+	`,
+			params: GenParams{ChoiceRepr: ChoiceReprRaw},
+			goModule: `package TestSpec
+
+import "encoding/asn1"
+
+type KerberosFlags = asn1.BitString
+type TicketFlags = asn1.BitString
+
+const (
+	TicketFlagsBitReserved		= 0
+	TicketFlagsBitForwardable	= 1
+	TicketFlagsBitProxiable		= 3
+)
+
+func TicketFlagsHas(v TicketFlags, bit int) bool {
+	return v.At(bit) == 1
+}
+func TicketFlagsSet(v *TicketFlags, bit int) {
+	length := max(v.BitLength, bit+1)
+	bytes := make([]byte, (length+7)/8)
+	copy(bytes, v.Bytes)
+	bytes[bit/8] |= 0x80 >> (bit % 8)
+	v.Bytes, v.BitLength = bytes, length
+}
+func TicketFlagsClear(v *TicketFlags, bit int) {
+	if !TicketFlagsHas(*v, bit) {
+		return
+	}
+	bytes := make([]byte, len(v.Bytes))
+	copy(bytes, v.Bytes)
+	bytes[bit/8] &^= 0x80 >> (bit % 8)
+	length := v.BitLength
+	for length > 0 && bytes[(length-1)/8]&(0x80>>((length-1)%8)) == 0 {
+		length--
+	}
+	v.Bytes, v.BitLength = bytes[:(length+7)/8], length
+}
+func TicketFlagsNames(v TicketFlags) []string {
+	var res []string
+	if TicketFlagsHas(v, TicketFlagsBitReserved) {
+		res = append(res, "reserved")
+	}
+	if TicketFlagsHas(v, TicketFlagsBitForwardable) {
+		res = append(res, "forwardable")
+	}
+	if TicketFlagsHas(v, TicketFlagsBitProxiable) {
+		res = append(res, "proxiable")
+	}
+	return res
+}
+
+var ValDefaultFlags asn1.BitString = asn1.BitString{Bytes: []byte{0x00, 0x00, 0x00, 0x00}, BitLength: 32}
+
+var ValSomeBits asn1.BitString = asn1.BitString{Bytes: []byte{0xa0}, BitLength: 3}
+
+var ValTicketFlags asn1.BitString = asn1.BitString{Bytes: []byte{0x50}, BitLength: 4}
+
+var ValNoFlags asn1.BitString = asn1.BitString{Bytes: []byte{}, BitLength: 0}
+
+var ValOctets []byte = []byte{0x0a, 0xf0}
+`,
+		},
+		{
+			name: "character strings",
+			asnModule: `
+	TestSpec DEFINITIONS IMPLICIT TAGS ::= BEGIN
+		greeting UTF8String ::= "say ""hi"""
+		letters IA5String ::= { "a", {0, 0, 0, 98}, {6, 3} }
+	END
+	`,
+			goModule: `package TestSpec
+
+var ValGreeting string = "say \"hi\""
+
+var ValLetters string = "abc"
+`,
+		},
+	}
+	testParsingAndGeneration(t, testCases)
+}
+
+func TestObjectIdentifierValueAssignments(t *testing.T) {
+	testCases := []e2eTestCase{
+		{
+			name: "object identifiers",
+			asnModule: `
+	TestSpec DEFINITIONS IMPLICIT TAGS ::= BEGIN
+		id-pkix OBJECT IDENTIFIER ::= { iso(1) identified-organization(3) dod(6) internet(1) security(5) mechanisms(5) pkix(7) }
+		id-pe OBJECT IDENTIFIER ::= { id-pkix 1 }
+		id-pe-alias OBJECT IDENTIFIER ::= { id-pe }
+		id-at OBJECT IDENTIFIER ::= { joint-iso-ccitt ds(5) 4 }
+		id-member OBJECT IDENTIFIER ::= { iso member-body us(840) rsadsi(113549) }
+		id-arc OBJECT IDENTIFIER ::= { 1 3 arc(id-arc-num) }
+		id-arc-num INTEGER ::= 42
+		id-single OBJECT IDENTIFIER ::= { 2 }
+	END
+	`,
+			goModule: `package TestSpec
+
+import "encoding/asn1"
+
+var ValId_pkix asn1.ObjectIdentifier = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7}
+
+var ValId_pe asn1.ObjectIdentifier = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 1}
+
+var ValId_pe_alias asn1.ObjectIdentifier = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 1}
+
+var ValId_at asn1.ObjectIdentifier = asn1.ObjectIdentifier{2, 5, 4}
+
+var ValId_member asn1.ObjectIdentifier = asn1.ObjectIdentifier{1, 2, 840, 113549}
+
+var ValId_arc asn1.ObjectIdentifier = asn1.ObjectIdentifier{1, 3, 42}
+
+var ValId_arc_num int64 = 42
+
+var ValId_single asn1.ObjectIdentifier = asn1.ObjectIdentifier{2}
+`,
+		},
+	}
+	testParsingAndGeneration(t, testCases)
+}
+
+func TestStructuredValueAssignments(t *testing.T) {
+	testCases := []e2eTestCase{
+		{
+			name: "sequence values",
+			asnModule: `
+	TestSpec DEFINITIONS IMPLICIT TAGS ::= BEGIN
+		Inner ::= SEQUENCE { id INTEGER, flag BOOLEAN OPTIONAL }
+		Outer ::= SEQUENCE { name UTF8String, inner Inner, count INTEGER, ... }
+		empty Inner ::= {}
+		single Inner ::= { id 1 }
+		outer Outer ::= { name "abc", inner { id 2, flag TRUE }, count -1 }
+	END
+	`,
+			goModule: `package TestSpec
+
+type Inner struct {
+	Id   int64
+	Flag bool ` + "`asn1:\"optional\"`" + `
+}
+type Outer struct {
+	Name  string ` + "`asn1:\"utf8\"`" + `
+	Inner Inner
+	Count int64
+}
+
+var ValEmpty Inner = Inner{}
+
+var ValSingle Inner = Inner{Id: 1}
+
+var ValOuter Outer = Outer{Name: "abc", Inner: Inner{Id: 2, Flag: true}, Count: -1}
+`,
+		},
+		{
+			name: "sequence of values",
+			asnModule: `
+	TestSpec DEFINITIONS IMPLICIT TAGS ::= BEGIN
+		Point ::= SEQUENCE { x INTEGER, y INTEGER }
+		Numbers ::= SEQUENCE OF INTEGER
+		numbers Numbers ::= { 1, 2, 3 }
+		one Numbers ::= { 1 }
+		path SEQUENCE OF Point ::= { { x 1, y 2 }, { x 3, y 4 } }
+		names SET OF IA5String ::= { "a", "b" }
+	END
+	`,
+			goModule: `package TestSpec
+
+type Point struct {
+	X int64
+	Y int64
+}
+type Numbers = []int64
+
+var ValNumbers Numbers = Numbers{1, 2, 3}
+
+var ValOne Numbers = Numbers{1}
+
+var ValPath []Point = []Point{Point{X: 1, Y: 2}, Point{X: 3, Y: 4}}
+
+var ValNames []string = []string{"a", "b"}
+`,
+		},
+		{
+			name: "null values",
+			asnModule: `
+	TestSpec DEFINITIONS IMPLICIT TAGS ::= BEGIN
+		nothing NULL ::= NULL
+	END
+	`,
+			goModule: `package TestSpec
+
+import "encoding/asn1"
+
+var ValNothing asn1.RawValue = asn1.NullRawValue
+`,
+		},
+	}
+	testParsingAndGeneration(t, testCases)
+}
+
+func TestChoiceValueAssignment(t *testing.T) {
+	m := parseModule(t, `
+	TestSpec DEFINITIONS IMPLICIT TAGS ::= BEGIN
+		Choice ::= CHOICE { num INTEGER, str UTF8String }
+		choice Choice ::= num : 42
+	END
+	`)
+	got, err := generateString(*m, GenParams{ChoiceRepr: ChoiceReprInterface})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+	// compare value declaration only
+	if expected := "var ValChoice Choice = ChoiceNum{Value: 42}"; !strings.Contains(got, expected) {
+		t.Errorf("Generated module does not contain %q:\n%v", expected, got)
+	}
+}
+
+func TestXMLValueAssignments(t *testing.T) {
+	testParsingAndGeneration(t, []e2eTestCase{
+		{
+			name: "built-in types",
+			asnModule: `
+	TestSpec DEFINITIONS IMPLICIT TAGS ::= BEGIN
+		num ::= <INTEGER>-5</INTEGER>
+		flag ::= <BOOLEAN><true/></BOOLEAN>
+		bits ::= <BIT_STRING>0101 1</BIT_STRING>
+		data ::= <OCTET_STRING>0AFF</OCTET_STRING>
+		oid ::= <OBJECT_IDENTIFIER>iso(1).2.840</OBJECT_IDENTIFIER>
+		str ::= <IA5String>a&lt;<lf/>b</IA5String>
+		inf ::= <REAL><MINUS-INFINITY/></REAL>
+	END
+	`,
+			goModule: `package TestSpec
+
+import "encoding/asn1"
+import "math"
+
+var ValNum int64 = -5
+
+var ValFlag bool = true
+
+var ValBits asn1.BitString = asn1.BitString{Bytes: []byte{0x58}, BitLength: 5}
+
+var ValData []byte = []byte{0x0a, 0xff}
+
+var ValOid asn1.ObjectIdentifier = asn1.ObjectIdentifier{1, 2, 840}
+
+var ValStr string = "a<\nb"
+
+var ValInf float64 = math.Inf(-1)
+`,
+		},
+	})
+	m := parseModule(t, `
+	TestSpec DEFINITIONS IMPLICIT TAGS ::= BEGIN
+		Choice ::= CHOICE { num INTEGER, str UTF8String }
+		Point ::= SEQUENCE { x INTEGER, y INTEGER OPTIONAL, flags SEQUENCE OF BOOLEAN, tags SET OF Choice }
+		Path ::= SEQUENCE OF Point
+		point ::= <Point>
+			<x>1</x>
+			<flags><true/><false/></flags>
+			<tags><str>a</str><num>2</num></tags>
+		</Point>
+		path ::= <Path><Point><x>1</x><flags/><tags/></Point></Path>
+	END
+	`)
+	got, err := generateString(*m, GenParams{ChoiceRepr: ChoiceReprInterface})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+	for _, expected := range []string{
+		"var ValPoint Point = Point{X: 1, Flags: []bool{true, false}, Tags: []Choice{ChoiceStr{Value: \"a\"}, ChoiceNum{Value: 2}}}",
+		"var ValPath Path = Path{Point{X: 1, Flags: []bool{}, Tags: []Choice{}}}",
+	} {
+		if !strings.Contains(got, expected) {
+			t.Errorf("Generated module does not contain %q:\n%v", expected, got)
+		}
+	}
+}
+
 func TestExtensionsE2E(t *testing.T) {
 	testcases := []e2eTestCase{
 		{
@@ -379,6 +1068,16 @@ type (
 	testParsingAndGeneration(t, testcases)
 }
 
+// formatGoSource formats go source with go/format, so that sources can be compared regardless of formatting.
+func formatGoSource(t *testing.T, src string) string {
+	t.Helper()
+	res, err := format.Source([]byte(src))
+	if err != nil {
+		t.Fatalf("Failed to format go source: %v\n%v", err, src)
+	}
+	return string(res)
+}
+
 func parseModule(t *testing.T, s string) *ModuleDefinition {
 	t.Helper()
 	def, err := ParseString(s)
@@ -468,3 +1167,418 @@ type MySequence struct {
 		t.Errorf("Output did not match expected, diff (-want, +got): %v", diff)
 	}
 }
+
+func TestImportedReferences(t *testing.T) {
+	modules, err := ParseModules(strings.NewReader(`
+	Main DEFINITIONS IMPLICIT TAGS ::= BEGIN
+		IMPORTS Flags, Base, id-base, app-tag FROM Common { iso 3 42 }
+			Time FROM Reexport;
+		Message ::= SEQUENCE {
+			flags [APPLICATION app-tag] Flags,
+			time Time
+		}
+		id-main OBJECT IDENTIFIER ::= { id-base 1 }
+		base Base ::= { size Common.max-size }
+	END
+
+	Common { iso(1) identified-organization(3) 42 } DEFINITIONS IMPLICIT TAGS ::= BEGIN
+		Flags ::= BIT STRING { a(0), b(1) }
+		Base ::= SEQUENCE { size INTEGER (0..max-size) }
+		id-base OBJECT IDENTIFIER ::= { iso identified-organization(3) 42 }
+		app-tag INTEGER ::= 5
+		max-size INTEGER ::= 64
+	END
+
+	Reexport DEFINITIONS ::= BEGIN
+		IMPORTS Time FROM Leaf;
+		Unused ::= BOOLEAN
+	END
+
+	Leaf DEFINITIONS ::= BEGIN
+		Time ::= GeneralizedTime
+	END
+	`))
+	if err != nil {
+		t.Fatalf("Failed to parse ASN.1 modules: %v", err)
+	}
+	registry := NewModuleRegistry()
+	if err := registry.Add(modules...); err != nil {
+		t.Fatalf("Failed to register modules: %v", err)
+	}
+	expected := `package Main
+
+import "time"
+import "encoding/asn1"
+
+type Message struct {
+	Flags Flags     ` + "`asn1:\"application,tag:5\"`" + `
+	Time  time.Time ` + "`asn1:\"generalized\"`" + `
+}
+
+var ValId_main asn1.ObjectIdentifier = asn1.ObjectIdentifier{1, 3, 42, 1}
+var ValBase Base = Base{Size: 64}
+`
+	testCases := []struct {
+		name     string
+		params   GenParams
+		expected string
+	}{
+		{
+			name:     "imported references",
+			params:   GenParams{Registry: registry, BitStringRepr: BitStringReprType},
+			expected: expected,
+		},
+		{
+			name:   "include imported modules",
+			params: GenParams{Registry: registry, IncludeImports: true, BitStringRepr: BitStringReprType},
+			expected: expected + `
+type Flags asn1.BitString
+
+const (
+	FlagsBitA = 0
+	FlagsBitB = 1
+)
+
+func (v Flags) Has(bit int) bool {
+	return asn1.BitString(v).At(bit) == 1
+}
+func (v *Flags) Set(bit int) {
+	length := max(v.BitLength, bit+1)
+	bytes := make([]byte, (length+7)/8)
+	copy(bytes, v.Bytes)
+	bytes[bit/8] |= 0x80 >> (bit % 8)
+	v.Bytes, v.BitLength = bytes, length
+}
+func (v *Flags) Clear(bit int) {
+	if !v.Has(bit) {
+		return
+	}
+	bytes := make([]byte, len(v.Bytes))
+	copy(bytes, v.Bytes)
+	bytes[bit/8] &^= 0x80 >> (bit % 8)
+	length := v.BitLength
+	for length > 0 && bytes[(length-1)/8]&(0x80>>((length-1)%8)) == 0 {
+		length--
+	}
+	v.Bytes, v.BitLength = bytes[:(length+7)/8], length
+}
+func (v Flags) Names() []string {
+	var res []string
+	if v.Has(FlagsBitA) {
+		res = append(res, "a")
+	}
+	if v.Has(FlagsBitB) {
+		res = append(res, "b")
+	}
+	return res
+}
+
+type Base struct {
+	Size int64
+}
+
+var ValId_base asn1.ObjectIdentifier = asn1.ObjectIdentifier{1, 3, 42}
+var ValApp_tag int64 = 5
+var ValMax_size int64 = 64
+
+type Unused = bool
+type Time = time.Time
+`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			if err := NewCodeGenerator(tc.params).Generate(*modules[0], buf); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(formatGoSource(t, tc.expected), formatGoSource(t, buf.String())); diff != "" {
+				t.Errorf("Generated module did not match expected, diff (-want, +got): %v", diff)
+			}
+		})
+	}
+}
+
+func TestImportErrors(t *testing.T) {
+	testCases := []struct {
+		name      string
+		asnModule string
+		params    GenParams
+		expected  string
+	}{
+		{
+			name: "no registry",
+			asnModule: `
+	TestSpec DEFINITIONS IMPLICIT TAGS ::= BEGIN
+		IMPORTS limit FROM Other;
+		a INTEGER ::= limit
+	END
+	`,
+			expected: "value a: import of value limit: module Other can not be resolved without module registry",
+		},
+		{
+			name: "missing module",
+			asnModule: `
+	TestSpec DEFINITIONS IMPLICIT TAGS ::= BEGIN
+		IMPORTS Other-Type FROM Other;
+		T ::= SEQUENCE { a Other-Type }
+	END
+	`,
+			params:   GenParams{Registry: NewModuleRegistry()},
+			expected: "type Other-Type: module Other is not found",
+		},
+		{
+			name: "type reference cycle",
+			asnModule: `
+	TestSpec DEFINITIONS IMPLICIT TAGS ::= BEGIN
+		A ::= B
+		B ::= [1] A
+		T ::= SEQUENCE { a A }
+	END
+	`,
+			expected: "type A: reference cycle TestSpec.A -> TestSpec.B -> TestSpec.A",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := parseModule(t, tc.asnModule)
+			err := NewCodeGenerator(tc.params).Generate(*m, &bytes.Buffer{})
+			if err == nil {
+				t.Fatalf("Expected error %q, got nil", tc.expected)
+			}
+			if !strings.Contains(err.Error(), tc.expected) {
+				t.Errorf("Expected error %q, got %q", tc.expected, err.Error())
+			}
+		})
+	}
+}
+
+func TestGeneratePackages(t *testing.T) {
+	modules, err := ParseModules(strings.NewReader(`
+	Main DEFINITIONS IMPLICIT TAGS ::= BEGIN
+		IMPORTS Base, max-size FROM Common-Types;
+		Message ::= SEQUENCE {
+			base Base,
+			size Size
+		}
+		Size ::= INTEGER { max(max-size) }
+		message Message ::= { base { size 1 }, size 2 }
+	END
+
+	Common-Types DEFINITIONS IMPLICIT TAGS ::= BEGIN
+		Base ::= SEQUENCE { size INTEGER (0..max-size) }
+		max-size INTEGER ::= 64
+	END
+	`))
+	if err != nil {
+		t.Fatalf("Failed to parse ASN.1 modules: %v", err)
+	}
+	registry := NewModuleRegistry()
+	if err := registry.Add(modules...); err != nil {
+		t.Fatalf("Failed to register modules: %v", err)
+	}
+	files, err := NewCodeGenerator(GenParams{Registry: registry, ImportPath: "example.com/gen"}).GeneratePackages(modules[:1])
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := map[string]string{
+		"main/main.go": `package main
+
+import "example.com/gen/commontypes"
+
+type Message struct {
+	Base commontypes.Base
+	Size Size
+}
+type Size = int64
+
+var SizeValMax Size = commontypes.ValMax_size
+var ValMessage Message = Message{Base: commontypes.Base{Size: 1}, Size: 2}
+`,
+		"commontypes/commontypes.go": `package commontypes
+
+type Base struct {
+	Size int64
+}
+
+var ValMax_size int64 = 64
+`,
+	}
+	got := make(map[string]string)
+	for name, content := range files {
+		got[name] = formatGoSource(t, string(content))
+	}
+	for name, content := range expected {
+		expected[name] = formatGoSource(t, content)
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("Generated packages did not match expected, diff (-want, +got): %v", diff)
+	}
+}
+
+func TestGeneratePackagesErrors(t *testing.T) {
+	testCases := []struct {
+		name       string
+		asnModules string
+		params     GenParams
+		expected   string
+	}{
+		{
+			name: "import cycle",
+			asnModules: `
+	A DEFINITIONS ::= BEGIN
+		IMPORTS TB FROM B;
+		TA ::= SEQUENCE OF TB
+		Leaf ::= INTEGER
+	END
+	B DEFINITIONS ::= BEGIN
+		IMPORTS Leaf FROM A;
+		TB ::= SEQUENCE { leaf Leaf }
+	END
+	`,
+			params:   GenParams{ImportPath: "example.com/gen"},
+			expected: "generated packages form import cycle a -> b -> a",
+		},
+		{
+			name: "package name collision",
+			asnModules: `
+	Common-Types DEFINITIONS ::= BEGIN T ::= INTEGER END
+	CommonTypes DEFINITIONS ::= BEGIN T ::= INTEGER END
+	`,
+			params:   GenParams{ImportPath: "example.com/gen"},
+			expected: "modules Common-Types and CommonTypes are both generated into package commontypes",
+		},
+		{
+			name:       "no import path",
+			asnModules: `A DEFINITIONS ::= BEGIN T ::= INTEGER END`,
+			expected:   "ImportPath is required to generate packages",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			modules, err := ParseModules(strings.NewReader(tc.asnModules))
+			if err != nil {
+				t.Fatalf("Failed to parse ASN.1 modules: %v", err)
+			}
+			tc.params.Registry = NewModuleRegistry()
+			if err := tc.params.Registry.Add(modules...); err != nil {
+				t.Fatalf("Failed to register modules: %v", err)
+			}
+			_, err = NewCodeGenerator(tc.params).GeneratePackages(modules)
+			if err == nil {
+				t.Fatalf("Expected error %q, got nil", tc.expected)
+			}
+			if !strings.Contains(err.Error(), tc.expected) {
+				t.Errorf("Expected error %q, got %q", tc.expected, err.Error())
+			}
+		})
+	}
+}
+
+func TestAutomaticTagging(t *testing.T) {
+	testParsingAndGeneration(t, []e2eTestCase{
+		{
+			name: "components are tagged in order",
+			asnModule: `
+			TestSpec DEFINITIONS AUTOMATIC TAGS ::= BEGIN
+				Record ::= SEQUENCE {
+					id INTEGER,
+					name UTF8String OPTIONAL,
+					inner SEQUENCE { flag BOOLEAN }
+				}
+				Flags ::= SET { a BOOLEAN, b BOOLEAN }
+			END
+			`,
+			goModule: `
+			package TestSpec
+
+			type Record struct {
+				Id    int64  ` + "`asn1:\"tag:0\"`" + `
+				Name  string ` + "`asn1:\"optional,tag:1,utf8\"`" + `
+				Inner struct {
+					Flag bool ` + "`asn1:\"tag:0\"`" + `
+				} ` + "`asn1:\"tag:2\"`" + `
+			}
+			type (
+				FlagsSET struct {
+					A bool ` + "`asn1:\"tag:0\"`" + `
+					B bool ` + "`asn1:\"tag:1\"`" + `
+				}
+				Flags = FlagsSET
+			)
+			`,
+		},
+		{
+			name: "root components are tagged before extension additions",
+			asnModule: `
+			TestSpec DEFINITIONS AUTOMATIC TAGS ::= BEGIN
+				Record ::= SEQUENCE {
+					a INTEGER,
+					...,
+					b INTEGER,
+					[[ 2: c INTEGER, d INTEGER ]],
+					...,
+					e INTEGER
+				}
+			END
+			`,
+			goModule: `
+			package TestSpec
+
+			type Record struct {
+				A int64 ` + "`asn1:\"tag:0\"`" + `
+				E int64 ` + "`asn1:\"tag:1\"`" + `
+				B int64 ` + "`asn1:\"tag:2\"`" + `
+				C int64 ` + "`asn1:\"tag:3\"`" + `
+				D int64 ` + "`asn1:\"tag:4\"`" + `
+			}
+			`,
+		},
+		{
+			name: "components are not tagged if some are tagged already",
+			asnModule: `
+			TestSpec DEFINITIONS AUTOMATIC TAGS ::= BEGIN
+				Record ::= SEQUENCE {
+					a INTEGER,
+					...,
+					b [5] INTEGER
+				}
+			END
+			`,
+			goModule: `
+			package TestSpec
+
+			type Record struct {
+				A int64
+				B int64 ` + "`asn1:\"tag:5\"`" + `
+			}
+			`,
+		},
+		{
+			name: "CHOICE is tagged explicitly",
+			asnModule: `
+			TestSpec DEFINITIONS AUTOMATIC TAGS ::= BEGIN
+				Item ::= SEQUENCE {
+					code INTEGER,
+					value Value,
+					inline CHOICE { a INTEGER, b BOOLEAN }
+				}
+				Value ::= CHOICE { number INTEGER, text UTF8String }
+			END
+			`,
+			goModule: `
+			package TestSpec
+
+			import "encoding/asn1"
+
+			type Item struct {
+				Code   int64         ` + "`asn1:\"tag:0\"`" + `
+				Value  Value         ` + "`asn1:\"explicit,tag:1\"`" + `
+				Inline asn1.RawValue ` + "`asn1:\"explicit,tag:2\"`" + `
+			}
+			type Value = asn1.RawValue
+			`,
+			params: GenParams{ChoiceRepr: ChoiceReprRaw},
+		},
+	})
+}
@@ -0,0 +1,809 @@
+package asn1go
+
+import (
+	"bytes"
+	"fmt"
+	goast "go/ast"
+	"math/big"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+)
+
+// validateMethodTemplate generates method checking that the value satisfies constraints of its type,
+// and variables holding compiled patterns of PATTERN constraints it checks.
+var validateMethodTemplate = template.Must(template.New("validate").Parse(`
+{{- range $i, $p := .Patterns}}
+var validate{{$.Name}}Pattern{{$i}} = regexp.MustCompile({{$p}})
+{{- end}}
+func (v {{.Name}}) Validate() error {
+{{.Body -}}
+	return nil
+}
+`))
+
+// permittedCharacters maps lexem types of restricted string types to go conditions which are true
+// if rune r is a character of the type, see X.680, section 41.
+var permittedCharacters = map[int]string{
+	IA5String:       "r < 0x80",
+	ISO646String:    "r >= 0x20 && r < 0x7f",
+	NumericString:   "r >= '0' && r <= '9' || r == ' '",
+	PrintableString: `r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || strings.ContainsRune(" '()+,-./:=?", r)`,
+	VisibleString:   "r >= 0x20 && r < 0x7f",
+}
+
+// generateValidateDecls generates Validate method of the type declared by decl.
+// See generateDERDecls for types which get methods. Constrained types are declared as defined types
+// rather than aliases to get methods too, see isValidatedType.
+// Other types are aliases of go built-in types, and are validated as part of enclosing types.
+func (ctx *moduleContext) generateValidateDecls(a TypeAssignment, decl goast.Decl) []goast.Decl {
+	name := goifyName(a.TypeReference.Name())
+	if ctx.isValidatedType(a.Type) {
+		if genDecl, ok := decl.(*goast.GenDecl); ok {
+			spec := genDecl.Specs[0].(*goast.TypeSpec)
+			spec.Assign = 0
+			// encoding/asn1 encodes structs as SET if names of their types end with SET, see generateTypeDecl
+			if leaf, _, _ := ctx.derLeafType(a.Type); ctx.params.Type&genEncoders == 0 && len(genDecl.Specs) == 1 {
+				if _, ok := leaf.(SetType); ok {
+					spec.Name = goast.NewIdent(name + "SET")
+					genDecl.Specs = append(genDecl.Specs, &goast.TypeSpec{Name: goast.NewIdent(name), Assign: 1, Type: goast.NewIdent(name + "SET")})
+				}
+			}
+		}
+		return ctx.generateValidateMethod(name, ctx.definedTypeValue(a.Type, "v"), a.Type)
+	}
+	switch t := ctx.removeWrapperTypes(a.Type).(type) {
+	case SequenceType, SetType:
+		return ctx.generateValidateMethod(name, "v", a.Type)
+	case TypeReference:
+		if isConstrainedType(a.Type) && ctx.choiceTypeName(t) != nil && ctx.params.ChoiceRepr == ChoiceReprInterface {
+			// interface types can not have methods, and aliases would share methods of referenced types
+			ctx.appendError(fmt.Errorf("type %v: constrained references to CHOICE types are not supported by validator", a.TypeReference))
+			return nil
+		}
+		if !ctx.hasValidateMethod(a.Type) {
+			return nil
+		}
+		typeName := exprString(ctx.generateTypeExpr(t))
+		return ctx.generateValidateMethod(name, typeName+"(v)", a.Type)
+	default:
+		return nil
+	}
+}
+
+// hasValidateMethod returns true if go type declared for type t has its own Validate method, or is CHOICE
+// interface requiring one. Other types with Validate methods are aliases of such types, and share their methods.
+func (ctx *moduleContext) hasValidateMethod(t Type) bool {
+	switch ctx.removeWrapperTypes(t).(type) {
+	case SequenceType, SetType:
+		return true
+	case ChoiceType:
+		return ctx.params.ChoiceRepr == ChoiceReprInterface
+	case TypeReference:
+		return ctx.isDefinedReference(t)
+	default:
+		return false
+	}
+}
+
+// isValidatedType returns true if go type declared for type t of type assignment is a defined type with
+// Validate method checking constraints of the type, rather than an alias of go built-in type or of referenced type.
+// These are constrained simple types, SEQUENCE OF and SET OF types, except types which encoding/asn1 handles
+// differently from their underlying go types, e.g. asn1.ObjectIdentifier, and *big.Int, which can not have methods,
+// and constrained references to SEQUENCE and SET types, e.g. T (WITH COMPONENTS { a PRESENT }).
+// Values of simple and list types are validated inline by enclosing types, so that errors have paths of invalid values.
+func (ctx *moduleContext) isValidatedType(t Type) bool {
+	if ctx.params.Type&GEN_VALIDATE == 0 {
+		return false
+	}
+	for {
+		tagged, ok := t.(TaggedType)
+		if !ok {
+			break
+		}
+		t = tagged.Type
+	}
+	constrained, ok := t.(ConstraintedType)
+	if !ok {
+		return hasConstrainedElements(t)
+	}
+	if _, ok := ctx.contentsOf(constrained); ok {
+		return false
+	}
+	switch leaf, _, _ := ctx.derLeafType(t); leaf.(type) {
+	case BooleanType, RealType, OctetStringType, RestrictedStringType, SequenceOfType, SetOfType:
+		return true
+	case IntegerType:
+		return ctx.integerType(t) != "*big.Int"
+	case SequenceType, SetType:
+		// SEQUENCE and SET types check their constraints in their own Validate methods,
+		// but constrained references can not share methods of referenced types
+		_, ok := ctx.removeWrapperTypes(t).(TypeReference)
+		return ok
+	default:
+		return false
+	}
+}
+
+// isConstrainedType returns true if t is a constrained type, which can be tagged.
+func isConstrainedType(t Type) bool {
+	for {
+		switch tt := t.(type) {
+		case TaggedType:
+			t = tt.Type
+		case ConstraintedType:
+			return true
+		default:
+			return false
+		}
+	}
+}
+
+// hasConstrainedElements returns true if t is SEQUENCE OF or SET OF type with constrained elements,
+// e.g. SEQUENCE OF INTEGER (0..9), or with elements of such types.
+func hasConstrainedElements(t Type) bool {
+	var elem Type
+	switch tt := t.(type) {
+	case SequenceOfType:
+		elem = tt.Type
+	case SetOfType:
+		elem = tt.Type
+	default:
+		return false
+	}
+	for {
+		switch tt := elem.(type) {
+		case TaggedType:
+			elem = tt.Type
+		case NamedType:
+			elem = tt.Type
+		case ConstraintedType:
+			return true
+		default:
+			return hasConstrainedElements(elem)
+		}
+	}
+}
+
+// definedTypeValue returns go expression of value expr of go type declared for type t of type assignment,
+// which is converted to go type of its definition if it is a defined type, see isValidatedType,
+// so that generators can handle it as value of go built-in type.
+func (ctx *moduleContext) definedTypeValue(t Type, expr string) string {
+	if !ctx.isValidatedType(t) {
+		return expr
+	}
+	var isSet bool
+	return exprString(ctx.generateTypeBody(t, &isSet)) + "(" + expr + ")"
+}
+
+// definedTypeVariable is same as definedTypeValue, but returns addressable expression, which is used by decoders.
+func (ctx *moduleContext) definedTypeVariable(t Type, expr string) string {
+	if !ctx.isValidatedType(t) {
+		return expr
+	}
+	var isSet bool
+	return "(*(*" + exprString(ctx.generateTypeBody(t, &isSet)) + ")(" + addressOf(expr) + "))"
+}
+
+// addressOf returns go expression of the address of variable expr, which is the pointer itself
+// if expr dereferences it, e.g. v.F for (*v.F).
+func addressOf(expr string) string {
+	inner, ok := strings.CutPrefix(expr, "(*")
+	if !ok || !strings.HasSuffix(inner, ")") {
+		return "&" + expr
+	}
+	inner = inner[:len(inner)-1]
+	depth := 0
+	for _, r := range inner {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		}
+		if depth < 0 {
+			return "&" + expr
+		}
+	}
+	if depth != 0 {
+		return "&" + expr
+	}
+	return inner
+}
+
+// generateValidateMethod generates Validate method of go type typeName, which validates go expression expr of type t.
+// Errors of nested values are prefixed with path of the value relative to the validated one.
+func (ctx *moduleContext) generateValidateMethod(typeName string, expr string, t Type) []goast.Decl {
+	return ctx.generateValidateMethodAt(typeName, expr, validatePath{}, t)
+}
+
+func (ctx *moduleContext) generateValidateMethodAt(typeName string, expr string, path validatePath, t Type) []goast.Decl {
+	g := &validatorGen{typeName: typeName, patterns: new([]string)}
+	g.validate(ctx, expr, path, t, nil)
+	var buf bytes.Buffer
+	if err := validateMethodTemplate.Execute(&buf, map[string]any{"Name": typeName, "Body": g.buf.String(), "Patterns": *g.patterns}); err != nil {
+		ctx.appendError(fmt.Errorf("type %v: %w", typeName, err))
+		return nil
+	}
+	decls, err := parseGoDecls(buf.String())
+	if err != nil {
+		ctx.appendError(fmt.Errorf("type %v: failed to parse generated code: %w", typeName, err))
+		return nil
+	}
+	return decls
+}
+
+// generateValidateChoiceDecls generates Validate methods of wrapper types of CHOICE alternatives.
+// Errors are prefixed with the name of the alternative.
+func (ctx *moduleContext) generateValidateChoiceDecls(alternatives []choiceAlternativeParams) []goast.Decl {
+	var decls []goast.Decl
+	for _, alt := range alternatives {
+		path := validatePath{format: goifyName(alt.alternative.Identifier.Name())}
+		decls = append(decls, ctx.generateValidateMethodAt(alt.Name, "v.Value", path, alt.alternative.Type)...)
+	}
+	return decls
+}
+
+// validatePath is a path of the validated value used in error messages, e.g. Req_body.Etype[3].
+// It is a format string with go expressions of its arguments, as indices of elements are known at run time only.
+type validatePath struct {
+	format string
+	args   []string
+}
+
+// field returns path of the component of the value.
+func (p validatePath) field(name string) validatePath {
+	if p.format == "" {
+		return validatePath{format: name}
+	}
+	return validatePath{format: p.format + "." + name, args: p.args}
+}
+
+// index returns path of the element of the value, where index is go expression of its index.
+func (p validatePath) index(index string) validatePath {
+	return validatePath{format: p.format + "[%v]", args: append(slices.Clip(p.args), index)}
+}
+
+// validatorGen generates go statements validating values.
+type validatorGen struct {
+	derEncoderGen
+	typeName string
+	// patterns are go expressions of regular expressions, which are compiled into package variables.
+	patterns *[]string
+}
+
+// nested returns generator of statements which are written by the caller only if there are any.
+func (g *validatorGen) nested() *validatorGen {
+	if g.vars == nil {
+		g.vars = make(map[string]int)
+	}
+	return &validatorGen{derEncoderGen: derEncoderGen{vars: g.vars, inlined: g.inlined}, typeName: g.typeName, patterns: g.patterns}
+}
+
+// fail writes statement returning error with the message prefixed with the path.
+func (g *validatorGen) fail(ctx *moduleContext, path validatePath, format string, args ...string) {
+	ctx.requireModule("fmt")
+	if path.format != "" {
+		format = path.format + ": " + format
+	}
+	g.line("return fmt.Errorf(%q, %v)", format, strings.Join(slices.Concat(path.args, args), ", "))
+}
+
+// call writes a call of Validate method, prefixing its errors with the path.
+func (g *validatorGen) call(ctx *moduleContext, path validatePath, expr string) {
+	if path.format == "" {
+		g.check("%v.Validate()", expr)
+		return
+	}
+	ctx.requireModule("fmt")
+	g.line("if err := %v.Validate(); err != nil {", expr)
+	g.line("\treturn fmt.Errorf(%q, %v)", path.format+".%w", strings.Join(append(slices.Clip(path.args), "err"), ", "))
+	g.line("}")
+}
+
+// validate writes statements validating go expression expr of type t, where cs are constraints applied to t
+// by enclosing types.
+func (g *validatorGen) validate(ctx *moduleContext, expr string, path validatePath, t Type, cs perConstraints) {
+	switch tt := t.(type) {
+	case TaggedType:
+		g.validate(ctx, expr, path, tt.Type, cs)
+	case NamedType:
+		g.validate(ctx, expr, path, tt.Type, cs)
+	case ConstraintedType:
+		if contained, ok := ctx.contentsOf(tt); ok {
+			g.validate(ctx, expr, path, contained, nil)
+			return
+		}
+		g.constrain(ctx, tt)
+		g.validate(ctx, expr, path, tt.Type, cs.with(ctx, tt.Constraint))
+	case TypeReference:
+		g.validateReference(ctx, expr, path, tt, cs)
+	case SequenceType:
+		g.validateComponents(ctx, expr, path, slices.Concat(tt.Components, tt.ExtensionAdditions.Components()))
+		g.validateInner(ctx, expr, path, tt, cs)
+	case SetType:
+		g.validateComponents(ctx, expr, path, slices.Concat(tt.Components, tt.ExtensionAdditions.Components()))
+		g.validateInner(ctx, expr, path, tt, cs)
+	case SequenceOfType:
+		g.validateElements(ctx, expr, path, tt.Type, cs)
+		g.validateInner(ctx, expr, path, tt, cs)
+	case SetOfType:
+		g.validateElements(ctx, expr, path, tt.Type, cs)
+		g.validateInner(ctx, expr, path, tt, cs)
+	case IntegerType:
+		goType := g.currentIntegerType(ctx)
+		kind := constraintInteger
+		if goType == "*big.Int" {
+			kind = constraintBigInteger
+		}
+		g.checkConstraints(ctx, path, constraintSubject{value: expr, kind: kind, goType: goType}, cs)
+		g.validateInner(ctx, expr, path, tt, cs)
+	case EnumeratedType:
+		g.validateEnumeration(ctx, expr, path, tt)
+		g.validateInner(ctx, expr, path, tt, cs)
+	case OctetStringType:
+		g.checkConstraints(ctx, path, constraintSubject{value: expr, size: "len(" + expr + ")"}, cs)
+		g.validateInner(ctx, expr, path, tt, cs)
+	case BitStringType:
+		// values of types with named bits may have trailing zero bits added or removed
+		// to satisfy SIZE constraint, see X.680, section 22.7
+		if len(tt.NamedBits) == 0 {
+			g.checkConstraints(ctx, path, constraintSubject{value: expr, size: expr + ".BitLength"}, cs)
+		}
+		g.validateInner(ctx, expr, path, tt, cs)
+	case RestrictedStringType:
+		g.checkConstraints(ctx, path, constraintSubject{value: expr, kind: constraintString, size: "utf8.RuneCountInString(" + expr + ")"}, cs)
+		g.validateCharacters(ctx, expr, path, tt)
+		g.validateInner(ctx, expr, path, tt, cs)
+	}
+}
+
+// validateReference writes statements validating value of referenced type. Types having Validate methods
+// are validated by calling them, followed by checks of inner type constraints applied to the reference,
+// and other types, including aliases of types with methods, are validated inline.
+func (g *validatorGen) validateReference(ctx *moduleContext, expr string, path validatePath, t TypeReference, cs perConstraints) {
+	assignment, assignmentCtx, err := ctx.lookupTypeAssignment(t)
+	if err != nil {
+		ctx.appendError(err)
+		return
+	}
+	if assignment == nil {
+		// useful types are time types, which are not constrained
+		return
+	}
+	if assignmentCtx.hasValidateMethod(assignment.Type) {
+		if ctx.choiceTypeName(t) != nil {
+			g.line("if %v != nil {", expr)
+			g.call(ctx, path, expr)
+			g.validateInner(ctx, expr, path, t, cs)
+			g.line("}")
+		} else {
+			g.call(ctx, path, expr)
+			g.validateInner(ctx, expr, path, t, cs)
+		}
+		return
+	}
+	name := string(assignmentCtx.moduleName) + "." + t.Name()
+	if slices.Contains(g.inlined, name) {
+		ctx.appendError(fmt.Errorf("type %v: reference cycle %v", t, strings.Join(append(g.inlined, name), " -> ")))
+		return
+	}
+	g.inlined = append(g.inlined, name)
+	g.validate(assignmentCtx, assignmentCtx.definedTypeValue(assignment.Type, expr), path, assignment.Type, cs)
+	g.inlined = g.inlined[:len(g.inlined)-1]
+}
+
+// validateComponents writes statements validating components of SEQUENCE or SET value.
+// Absent OPTIONAL components are not validated.
+func (g *validatorGen) validateComponents(ctx *moduleContext, expr string, path validatePath, components ComponentTypeList) {
+	for _, component := range components {
+		named, ok := component.(NamedComponentType)
+		if !ok {
+			continue // COMPONENTS OF is reported as unsupported by structFromComponents
+		}
+		name := goifyName(named.NamedType.Identifier.Name())
+		field, pointer := ctx.componentField(expr, named)
+		inner := g.nested()
+		inner.validate(ctx, field, path.field(name), named.NamedType.Type, nil)
+		if inner.buf.Len() == 0 {
+			continue
+		}
+		// values of CHOICE types are checked for nil by validateReference
+		guard := named.IsOptional && ctx.choiceTypeName(named.NamedType.Type) == nil
+		if guard {
+			g.line("if %v {", ctx.validatePresenceCheck(field, pointer, named.NamedType.Type))
+		}
+		g.buf.WriteString(inner.buf.String())
+		if guard {
+			g.line("}")
+		}
+	}
+}
+
+// validatePresenceCheck returns go condition which is true if OPTIONAL component is present, see optionalPresenceCheck.
+func (ctx *moduleContext) validatePresenceCheck(expr string, pointer string, t Type) string {
+	if pointer != "" || ctx.params.Type&genEncoders != 0 {
+		return ctx.optionalPresenceCheck(expr, pointer, t)
+	}
+	switch leaf, _, _ := ctx.derLeafType(t); leaf.(type) {
+	case SequenceType, SetType:
+		ctx.requireModule("reflect")
+		return "!reflect.ValueOf(" + expr + ").IsZero()"
+	default:
+		return ctx.derNonZeroCheck(expr, t)
+	}
+}
+
+// validateElements writes statements validating SIZE constraints of SEQUENCE OF or SET OF value,
+// and its elements of type t.
+func (g *validatorGen) validateElements(ctx *moduleContext, expr string, path validatePath, t Type, cs perConstraints) {
+	g.checkConstraints(ctx, path, constraintSubject{value: expr, size: "len(" + expr + ")"}, cs)
+	index := g.newVar("i")
+	elem := g.newVar("elem")
+	inner := g.nested()
+	inner.validate(ctx, elem, path.index(index), t, nil)
+	if inner.buf.Len() == 0 {
+		return
+	}
+	g.line("for %v, %v := range %v {", index, elem, expr)
+	g.buf.WriteString(inner.buf.String())
+	g.line("}")
+}
+
+// validateEnumeration writes statements checking that value of ENUMERATED type is one of its items.
+// Values of extensible types are not checked, as they can be items added in later versions.
+func (g *validatorGen) validateEnumeration(ctx *moduleContext, expr string, path validatePath, t EnumeratedType) {
+	if t.Extensible || ctx.extensibilityImplied {
+		return
+	}
+	root, _, err := ctx.enumerationValues(t)
+	if err != nil {
+		ctx.appendError(err)
+		return
+	}
+	conditions := make([]string, 0, len(root))
+	for _, v := range root {
+		conditions = append(conditions, fmt.Sprintf("%v != %v", expr, v))
+	}
+	g.line("if %v {", strings.Join(conditions, " && "))
+	g.fail(ctx, path, "value %v is not an item of ENUMERATED type", expr)
+	g.line("}")
+}
+
+// validateCharacters writes statements checking that value of restricted string type consists of characters
+// permitted by the type.
+func (g *validatorGen) validateCharacters(ctx *moduleContext, expr string, path validatePath, t RestrictedStringType) {
+	condition, ok := permittedCharacters[t.LexType]
+	if !ok {
+		return
+	}
+	if strings.Contains(condition, "strings.") {
+		ctx.requireModule("strings")
+	}
+	g.line("for _, r := range %v {", expr)
+	g.line("if !(%v) {", condition)
+	g.fail(ctx, path, "character %q is not permitted in "+builtinTypeName(t), "r")
+	g.line("}")
+	g.line("}")
+}
+
+// constraintKind is a kind of go value which constraints are applied to.
+type constraintKind int
+
+const (
+	// constraintOther is a value which can only be constrained by SIZE.
+	constraintOther constraintKind = iota
+	constraintInteger
+	constraintBigInteger
+	constraintString
+	// constraintCharacter is a rune of string constrained by permitted alphabet.
+	constraintCharacter
+)
+
+// constraintSubject holds go expressions of the constrained value.
+type constraintSubject struct {
+	value string
+	kind  constraintKind
+	// goType is go type of INTEGER value, which bounds make some comparisons redundant, see integerComparison.
+	goType string
+	// size is go expression of the size of the value, or empty if value can not be constrained by SIZE.
+	size string
+}
+
+// checkConstraints writes statements checking that value satisfies constraints cs, see constraintEvaluator.
+// Extensible constraints are not checked, as values outside of their root can be permitted by later versions.
+func (g *validatorGen) checkConstraints(ctx *moduleContext, path validatePath, s constraintSubject, cs perConstraints) {
+	if len(cs) == 0 {
+		return
+	}
+	set := cs.evaluate(false)
+	switch s.kind {
+	case constraintInteger, constraintBigInteger:
+		if condition := rangeCondition(s, set.values); condition != "" {
+			if s.kind == constraintBigInteger {
+				g.line("if %v != nil && !(%v) {", s.value, condition)
+			} else {
+				g.line("if !(%v) {", condition)
+			}
+			g.fail(ctx, path, "value %v is not permitted by constraint", s.value)
+			g.line("}")
+		}
+	case constraintString:
+		if condition := stringCondition(s, set.strings); condition != "" {
+			g.line("if !(%v) {", condition)
+			g.fail(ctx, path, "value %q is not permitted by constraint", s.value)
+			g.line("}")
+		}
+		// characters are never negative, as are sizes below
+		alphabet := set.alphabet
+		if alphabet.constrained && len(alphabet.set) > 0 && alphabet.set[0].Lower.Sign() == 0 {
+			alphabet.set = slices.Clone(alphabet.set)
+			alphabet.set[0].Lower = nil
+		}
+		if condition := rangeCondition(constraintSubject{value: "r", kind: constraintCharacter}, alphabet); condition != "" {
+			g.line("for _, r := range %v {", s.value)
+			g.line("if !(%v) {", condition)
+			g.fail(ctx, path, "character %q is not permitted by constraint", "r")
+			g.line("}")
+			g.line("}")
+		}
+		if set.patterns.constrained && !set.patterns.extensible {
+			for _, pattern := range set.patterns.set {
+				if name := g.pattern(ctx, pattern); name != "" {
+					g.line("if !%v.MatchString(%v) {", name, s.value)
+					g.fail(ctx, path, "value %q does not match pattern", s.value)
+					g.line("}")
+				}
+			}
+		}
+	}
+	if s.size == "" {
+		return
+	}
+	// sizes are never negative, so that lower bound of zero does not need to be checked
+	sizes := set.sizes
+	if sizes.constrained && len(sizes.set) > 0 && sizes.set[0].Lower.Sign() == 0 {
+		sizes.set = slices.Clone(sizes.set)
+		sizes.set[0].Lower = nil
+	}
+	if condition := rangeCondition(constraintSubject{value: s.size, kind: constraintInteger}, sizes); condition != "" {
+		if strings.Contains(condition, "utf8.") {
+			ctx.requireModule("unicode/utf8")
+		}
+		g.line("if !(%v) {", condition)
+		g.fail(ctx, path, "size %v is not permitted by constraint", s.size)
+		g.line("}")
+	}
+}
+
+// rangeCondition returns go condition which is true if integer value is in the set of permitted values.
+// Returns empty string if any value is permitted, or if the set is extensible.
+func rangeCondition(s constraintSubject, values constraintDimension[RangeSet]) string {
+	if !values.constrained || values.extensible {
+		return ""
+	}
+	var conditions []string
+	for _, r := range values.set {
+		var condition string
+		if r.Lower != nil && r.Upper != nil && r.Lower.Cmp(r.Upper) == 0 {
+			condition = integerComparison(s, "==", r.Lower)
+		} else {
+			var bounds []string
+			if r.Lower != nil {
+				bounds = append(bounds, integerComparison(s, ">=", r.Lower))
+			}
+			if r.Upper != nil {
+				bounds = append(bounds, integerComparison(s, "<=", r.Upper))
+			}
+			if slices.Contains(bounds, "false") {
+				continue
+			}
+			condition = joinConditions(bounds, " && ")
+		}
+		switch condition {
+		case "":
+			return "" // the range contains all values of go type
+		case "false":
+		default:
+			conditions = append(conditions, condition)
+		}
+	}
+	if len(conditions) == 0 {
+		return "false"
+	}
+	return joinConditions(conditions, " || ")
+}
+
+// stringCondition returns go condition which is true if value of string is one of permitted values.
+// Returns empty string if any value is permitted, or if the set is extensible.
+func stringCondition(s constraintSubject, values constraintDimension[stringSet]) string {
+	if !values.constrained || values.extensible {
+		return ""
+	}
+	if len(values.set) == 0 {
+		return "false"
+	}
+	conditions := make([]string, 0, len(values.set))
+	for _, v := range values.set {
+		conditions = append(conditions, s.value+" == "+strconv.Quote(v))
+	}
+	return strings.Join(conditions, " || ")
+}
+
+// integerComparison returns go condition comparing integer value with n using operator op.
+// Comparisons which are always true for values of go type of the value are omitted, and comparisons
+// which are always false are replaced with false, as n can be out of range of the type.
+func integerComparison(s constraintSubject, op string, n *big.Int) string {
+	switch s.kind {
+	case constraintBigInteger:
+		return fmt.Sprintf("%v.Cmp(%v) %v 0", s.value, bigIntExpr(n), op)
+	case constraintCharacter:
+		return fmt.Sprintf("%v %v %v", s.value, op, runeLiteral(n.Int64()))
+	}
+	for _, sized := range sizedIntegerTypes {
+		if sized.name != s.goType {
+			continue
+		}
+		lower, upper := n.Cmp(sized.lower), n.Cmp(sized.upper)
+		var always, never bool
+		switch op {
+		case ">=":
+			always, never = lower <= 0, upper > 0
+		case ">":
+			always, never = lower < 0, upper >= 0
+		case "<=":
+			always, never = upper >= 0, lower < 0
+		case "<":
+			always, never = upper > 0, lower <= 0
+		case "==":
+			never = lower < 0 || upper > 0
+		}
+		switch {
+		case always:
+			return ""
+		case never:
+			return "false"
+		}
+	}
+	return fmt.Sprintf("%v %v %v", s.value, op, n)
+}
+
+// runeLiteral returns go rune literal of character n, or its code if it is not a valid character.
+func runeLiteral(n int64) string {
+	if n < 0 || n > utf8.MaxRune || !utf8.ValidRune(rune(n)) {
+		return fmt.Sprintf("0x%x", n)
+	}
+	return strconv.QuoteRune(rune(n))
+}
+
+// joinConditions joins non-empty go conditions with operator op, parenthesizing them if there are several.
+func joinConditions(conditions []string, op string) string {
+	conditions = slices.DeleteFunc(slices.Clone(conditions), func(c string) bool { return c == "" })
+	if len(conditions) == 1 {
+		return conditions[0]
+	}
+	for i, c := range conditions {
+		conditions[i] = parenthesize(c)
+	}
+	return strings.Join(conditions, op)
+}
+
+// parenthesize wraps go expression in parentheses unless it is a simple comparison.
+func parenthesize(expr string) string {
+	if !strings.Contains(expr, "&&") && !strings.Contains(expr, "||") {
+		return expr
+	}
+	return "(" + expr + ")"
+}
+
+// pattern returns name of the package variable holding compiled regular expression of PATTERN constraint,
+// or empty string if the pattern is not valid.
+func (g *validatorGen) pattern(ctx *moduleContext, pattern string) string {
+	expr, err := goRegexp(pattern)
+	if err != nil {
+		ctx.appendError(fmt.Errorf("type %v: pattern %q: %w", g.typeName, pattern, err))
+		return ""
+	}
+	ctx.requireModule("regexp")
+	*g.patterns = append(*g.patterns, strconv.Quote(expr))
+	return fmt.Sprintf("validate%vPattern%v", g.typeName, len(*g.patterns)-1)
+}
+
+// goRegexp translates regular expression of PATTERN constraint, see X.680, Annex A, into go regular expression
+// matching whole strings. Syntax is mostly shared, except for quantifiers #n and #(n,m), which are translated into
+// {n} and {n,m}, and Quadruple {g,p,r,c} and Tuple {t,c} characters, which are translated into \x{...}.
+// Named characters \N{name} would need value references resolved and are rejected.
+func goRegexp(pattern string) (string, error) {
+	var b strings.Builder
+	b.WriteString("^(?:")
+	inClass := false
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == '\\' && strings.HasPrefix(pattern[i+1:], "N{"):
+			return "", fmt.Errorf("named character reference at offset %v is not supported", i)
+		case c == '\\' && i+1 < len(pattern):
+			b.WriteString(pattern[i : i+2])
+			i++
+			continue
+		case c == '[':
+			inClass = true
+		case c == ']':
+			inClass = false
+		case c == '{':
+			end := strings.IndexByte(pattern[i:], '}')
+			if end < 0 {
+				return "", fmt.Errorf("unterminated character reference at offset %v", i)
+			}
+			code, err := patternCharacter(pattern[i+1 : i+end])
+			if err != nil {
+				return "", fmt.Errorf("invalid character reference %v at offset %v: %w", pattern[i:i+end+1], i, err)
+			}
+			fmt.Fprintf(&b, "\\x{%X}", code)
+			i += end
+			continue
+		case c == '#' && !inClass:
+			rest := pattern[i+1:]
+			var n int
+			if strings.HasPrefix(rest, "(") {
+				end := strings.IndexByte(rest, ')')
+				if end < 0 {
+					return "", fmt.Errorf("unterminated quantifier at offset %v", i)
+				}
+				lower, upper, ok := strings.Cut(rest[1:end], ",")
+				if !ok || lower == "" && upper == "" {
+					return "", fmt.Errorf("invalid quantifier %v at offset %v", rest[:end+1], i)
+				}
+				if lower == "" {
+					lower = "0"
+				}
+				fmt.Fprintf(&b, "{%v,%v}", lower, upper)
+				n = end + 1
+			} else {
+				for n < len(rest) && rest[n] >= '0' && rest[n] <= '9' {
+					n++
+				}
+				if n == 0 {
+					return "", fmt.Errorf("invalid quantifier at offset %v", i)
+				}
+				fmt.Fprintf(&b, "{%v}", rest[:n])
+			}
+			i += n
+			continue
+		}
+		b.WriteByte(c)
+	}
+	b.WriteString(")$")
+	if _, err := regexp.Compile(b.String()); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// patternCharacter returns code point of Quadruple "g,p,r,c" or Tuple "t,c" character, see X.680, 41.8.
+func patternCharacter(ref string) (rune, error) {
+	parts := strings.Split(ref, ",")
+	if len(parts) != 2 && len(parts) != 4 {
+		return 0, fmt.Errorf("expected quadruple or tuple")
+	}
+	limits := []int{127, 255, 255, 255}
+	if len(parts) == 2 {
+		limits = []int{7, 15}
+	}
+	var code rune
+	for i, part := range parts {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || n < 0 || n > limits[i] {
+			return 0, fmt.Errorf("component %q is not a number in range 0..%v", strings.TrimSpace(part), limits[i])
+		}
+		if len(parts) == 2 {
+			code = code*16 + rune(n)
+		} else {
+			code = code<<8 | rune(n)
+		}
+	}
+	return code, nil
+}
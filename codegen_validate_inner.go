@@ -0,0 +1,256 @@
+package asn1go
+
+import (
+	"fmt"
+	"slices"
+)
+
+// innerSubject is a value constrained by inner type constraints.
+type innerSubject struct {
+	expr string
+	path validatePath
+	// t is the type of the value, which is resolved in context of module ctx.
+	t   Type
+	ctx *moduleContext
+}
+
+// validateInner writes statements checking inner type constraints among constraints cs of go expression expr
+// of type t, see X.680, section 51.8. Type t is SEQUENCE, SET, SEQUENCE OF or SET OF type, or a reference to one of them,
+// or to CHOICE type. Extensible constraints are not checked, and other elements of constraints are checked elsewhere.
+func (g *validatorGen) validateInner(ctx *moduleContext, expr string, path validatePath, t Type, cs perConstraints) {
+	s := innerSubject{expr: expr, path: path, t: t, ctx: ctx}
+	for _, c := range cs {
+		spec, ok := c.constraint.ConstraintSpec.(SubtypeConstraint)
+		if !ok || len(spec) == 0 || spec.Extensible() {
+			continue
+		}
+		g.innerElements(c.ctx, s, spec[0])
+	}
+}
+
+// innerElements writes statements checking inner type constraints among elements, which are defined in module of ctx.
+// Nothing is written if elements permit any value as far as inner type constraints are concerned.
+func (g *validatorGen) innerElements(ctx *moduleContext, s innerSubject, elements Elements) {
+	switch el := elements.(type) {
+	case Unions:
+		if len(el) == 1 {
+			g.innerIntersections(ctx, s, el[0])
+			return
+		}
+		alternatives := make([]*validatorGen, 0, len(el))
+		for _, intersections := range el {
+			inner := g.nested()
+			inner.innerIntersections(ctx, s, intersections)
+			if inner.buf.Len() == 0 {
+				return // the alternative permits any value
+			}
+			alternatives = append(alternatives, inner)
+		}
+		// value has to satisfy one of alternatives, otherwise error of the last one is returned
+		err, check := g.newVar("err"), g.newVar("check")
+		g.line("var %v error", err)
+		g.line("for _, %v := range []func() error{", check)
+		for _, alternative := range alternatives {
+			g.line("func() error {")
+			g.buf.WriteString(alternative.buf.String())
+			g.line("return nil")
+			g.line("},")
+		}
+		g.line("} {")
+		g.line("if %v = %v(); %v == nil {", err, check, err)
+		g.line("break")
+		g.line("}")
+		g.line("}")
+		g.line("if %v != nil {\n\t\treturn %v\n\t}", err, err)
+	case InnerTypeConstraint:
+		g.innerType(ctx, s, el)
+	}
+}
+
+// innerIntersections writes statements checking inner type constraints among intersections.
+// Exclusions are not checked, so that values they exclude are permitted.
+func (g *validatorGen) innerIntersections(ctx *moduleContext, s innerSubject, intersections Intersections) {
+	for _, el := range intersections {
+		g.innerElements(ctx, s, el.Elements)
+	}
+}
+
+// innerType writes statements checking WITH COMPONENT or WITH COMPONENTS constraint c defined in module of ctx.
+func (g *validatorGen) innerType(ctx *moduleContext, s innerSubject, c InnerTypeConstraint) {
+	leaf, leafCtx, _ := s.ctx.derLeafType(s.t)
+	switch tt := leaf.(type) {
+	case SequenceOfType:
+		g.innerElementsOf(ctx, s, leafCtx, tt.Type, c)
+	case SetOfType:
+		g.innerElementsOf(ctx, s, leafCtx, tt.Type, c)
+	case SequenceType:
+		g.innerComponents(ctx, s, leafCtx, slices.Concat(tt.Components, tt.ExtensionAdditions.Components()), c)
+	case SetType:
+		g.innerComponents(ctx, s, leafCtx, slices.Concat(tt.Components, tt.ExtensionAdditions.Components()), c)
+	case ChoiceType:
+		g.innerAlternatives(ctx, s, leafCtx, tt, c)
+	default:
+		// e.g. SEQUENCE OF INTEGER (WITH COMPONENT (0..9)), where the constraint is applied to INTEGER
+		got := builtinTypeName(s.t)
+		if got == "" {
+			got = fmt.Sprint(s.t)
+		}
+		ctx.appendError(fmt.Errorf("type %v: inner type constraint can only be applied to SEQUENCE, SET, CHOICE, SEQUENCE OF or SET OF types, not %v", g.typeName, got))
+	}
+}
+
+// innerElementsOf writes statements checking WITH COMPONENT constraint of elements of type t,
+// which is resolved in context of module typeCtx.
+func (g *validatorGen) innerElementsOf(ctx *moduleContext, s innerSubject, typeCtx *moduleContext, t Type, c InnerTypeConstraint) {
+	if c.Component == nil {
+		ctx.appendError(fmt.Errorf("type %v: WITH COMPONENTS can not be applied to SEQUENCE OF or SET OF types", s.t))
+		return
+	}
+	index := g.newVar("i")
+	elem := g.newVar("elem")
+	inner := g.nested()
+	inner.validate(typeCtx, elem, s.path.index(index), t, perConstraints{}.with(ctx, *c.Component))
+	if inner.buf.Len() == 0 {
+		return
+	}
+	g.line("for %v, %v := range %v {", index, elem, s.expr)
+	g.buf.WriteString(inner.buf.String())
+	g.line("}")
+}
+
+// innerComponents writes statements checking WITH COMPONENTS constraint of SEQUENCE or SET value with components,
+// which are resolved in context of module typeCtx. Presence of components which are not OPTIONAL is not checked.
+func (g *validatorGen) innerComponents(ctx *moduleContext, s innerSubject, typeCtx *moduleContext, components ComponentTypeList, c InnerTypeConstraint) {
+	if !g.withComponents(ctx, s, c) {
+		return
+	}
+	var names []Identifier
+	for _, component := range components {
+		named, ok := component.(NamedComponentType)
+		if !ok {
+			continue // COMPONENTS OF is reported as unsupported by structFromComponents
+		}
+		names = append(names, named.NamedType.Identifier)
+		constraint, presence, ok := c.constraintOf(named.NamedType.Identifier)
+		if !ok {
+			continue
+		}
+		name := goifyName(named.NamedType.Identifier.Name())
+		field, pointer := typeCtx.componentField(s.expr, named)
+		path := s.path.field(name)
+		t := named.NamedType.Type
+		if named.IsOptional {
+			switch presence {
+			case PRESENCE_PRESENT:
+				g.line("if !(%v) {", typeCtx.validatePresenceCheck(field, pointer, t))
+				g.fail(ctx, path, "component is required by constraint")
+				g.line("}")
+			case PRESENCE_ABSENT:
+				g.line("if %v {", typeCtx.validatePresenceCheck(field, pointer, t))
+				g.fail(ctx, path, "component is not permitted by constraint")
+				g.line("}")
+				continue
+			}
+		}
+		if constraint == nil {
+			continue
+		}
+		inner := g.nested()
+		inner.validate(typeCtx, field, path, t, perConstraints{}.with(ctx, *constraint))
+		if inner.buf.Len() == 0 {
+			continue
+		}
+		// values of CHOICE types are checked for nil by validateReference
+		guard := named.IsOptional && presence != PRESENCE_PRESENT && typeCtx.choiceTypeName(t) == nil
+		if guard {
+			g.line("if %v {", typeCtx.validatePresenceCheck(field, pointer, t))
+		}
+		g.buf.WriteString(inner.buf.String())
+		if guard {
+			g.line("}")
+		}
+	}
+	g.checkConstrainedNames(ctx, s, c, names)
+}
+
+// innerAlternatives writes statements checking WITH COMPONENTS constraint of CHOICE value of type t,
+// which is resolved in context of module typeCtx. Alternatives which are PRESENT must be chosen,
+// and alternatives which are ABSENT must not.
+func (g *validatorGen) innerAlternatives(ctx *moduleContext, s innerSubject, typeCtx *moduleContext, t ChoiceType, c InnerTypeConstraint) {
+	if !g.withComponents(ctx, s, c) {
+		return
+	}
+	choiceName := s.ctx.choiceTypeName(s.t)
+	if choiceName == nil {
+		return // CHOICE types without wrapper types of alternatives are not validated
+	}
+	var names []Identifier
+	for _, alternative := range t.Alternatives() {
+		names = append(names, alternative.Identifier)
+		constraint, presence, ok := c.constraintOf(alternative.Identifier)
+		if !ok {
+			continue
+		}
+		name := goifyName(alternative.Identifier.Name())
+		wrapper := exprString(choiceMemberExpr(choiceName, "", name))
+		path := s.path.field(name)
+		switch presence {
+		case PRESENCE_PRESENT:
+			g.line("if _, ok := %v.(%v); !ok {", s.expr, wrapper)
+			g.fail(ctx, path, "alternative is required by constraint")
+			g.line("}")
+		case PRESENCE_ABSENT:
+			g.line("if _, ok := %v.(%v); ok {", s.expr, wrapper)
+			g.fail(ctx, path, "alternative is not permitted by constraint")
+			g.line("}")
+			continue
+		}
+		if constraint == nil {
+			continue
+		}
+		alt := g.newVar("alt")
+		inner := g.nested()
+		inner.validate(typeCtx, alt+".Value", path, alternative.Type, perConstraints{}.with(ctx, *constraint))
+		if inner.buf.Len() == 0 {
+			continue
+		}
+		g.line("if %v, ok := %v.(%v); ok {", alt, s.expr, wrapper)
+		g.buf.WriteString(inner.buf.String())
+		g.line("}")
+	}
+	g.checkConstrainedNames(ctx, s, c, names)
+}
+
+// withComponents returns true if c is WITH COMPONENTS constraint, and reports error otherwise,
+// as WITH COMPONENT constraint can not be applied to types with components.
+func (g *validatorGen) withComponents(ctx *moduleContext, s innerSubject, c InnerTypeConstraint) bool {
+	if c.Component != nil {
+		ctx.appendError(fmt.Errorf("type %v: WITH COMPONENT can only be applied to SEQUENCE OF or SET OF types", s.t))
+		return false
+	}
+	return true
+}
+
+// checkConstrainedNames reports error if WITH COMPONENTS constraint c lists components which are not among names.
+func (g *validatorGen) checkConstrainedNames(ctx *moduleContext, s innerSubject, c InnerTypeConstraint, names []Identifier) {
+	for _, named := range c.Components {
+		if !slices.Contains(names, named.Identifier) {
+			ctx.appendError(fmt.Errorf("type %v: WITH COMPONENTS constrains unknown component %v", s.t, named.Identifier))
+		}
+	}
+}
+
+// constraintOf returns value constraint and presence of the component with identifier id. Components which are
+// not listed are not constrained with partial specification, and are absent with full specification.
+// Returns false if component is not constrained.
+func (c InnerTypeConstraint) constraintOf(id Identifier) (*Constraint, int, bool) {
+	for _, named := range c.Components {
+		if named.Identifier == id {
+			return named.Constraint, named.Presence, true
+		}
+	}
+	if c.Partial {
+		return nil, PRESENCE_UNSPECIFIED, false
+	}
+	return nil, PRESENCE_ABSENT, true
+}
@@ -0,0 +1,307 @@
+package asn1go
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestValidateConstraints(t *testing.T) {
+	testCases := []struct {
+		name     string
+		typeDecl string
+		expected string
+	}{
+		{
+			name:     "value range",
+			typeDecl: "INTEGER (0..7)",
+			expected: "if !(v.F >= 0 && v.F <= 7) {",
+		},
+		{
+			name:     "open range",
+			typeDecl: "INTEGER (0<..<MAX)",
+			expected: "if !(v.F >= 1) {",
+		},
+		{
+			name:     "bounds exceeding int64",
+			typeDecl: "INTEGER (1..18446744073709551615)",
+			expected: "if !(v.F >= 1) {",
+		},
+		{
+			name:     "union of values",
+			typeDecl: "INTEGER (1..3 | 10)",
+			expected: "if !((v.F >= 1 && v.F <= 3) || v.F == 10) {",
+		},
+		{
+			name:     "exclusion",
+			typeDecl: "INTEGER (0..10 EXCEPT 5)",
+			expected: "if !((v.F >= 0 && v.F <= 4) || (v.F >= 6 && v.F <= 10)) {",
+		},
+		{
+			name:     "complement",
+			typeDecl: "INTEGER (ALL EXCEPT 0)",
+			expected: "if !(v.F <= -1 || v.F >= 1) {",
+		},
+		{
+			name:     "referenced constrained type",
+			typeDecl: "Small (2..MAX)",
+			expected: "if !(int64(v.F) >= 2 && int64(v.F) <= 7) {",
+		},
+		{
+			name:     "contained subtype",
+			typeDecl: "INTEGER (Small)",
+			expected: "if !(v.F >= 0 && v.F <= 7) {",
+		},
+		{
+			name:     "included subtype",
+			typeDecl: "INTEGER (INCLUDES Small EXCEPT 0)",
+			expected: "if !(v.F >= 1 && v.F <= 7) {",
+		},
+		{
+			name:     "size of list",
+			typeDecl: "SEQUENCE (SIZE (1..8)) OF Small",
+			expected: `if !(len(v.F) >= 1 && len(v.F) <= 8) {
+		return fmt.Errorf("F: size %v is not permitted by constraint", len(v.F))
+	}
+	for i, elem := range v.F {
+		if !(int64(elem) >= 0 && int64(elem) <= 7) {
+			return fmt.Errorf("F[%v]: value %v is not permitted by constraint", i, int64(elem))
+		}
+	}`,
+		},
+		{
+			name:     "size of string",
+			typeDecl: "UTF8String (SIZE (4))",
+			expected: "if !(utf8.RuneCountInString(v.F) == 4) {",
+		},
+		{
+			name:     "string value",
+			typeDecl: `UTF8String ("abc")`,
+			expected: `return fmt.Errorf("F: value %q is not permitted by constraint", v.F)`,
+		},
+		{
+			name:     "alphabet",
+			typeDecl: "NumericString",
+			expected: `for _, r := range v.F {
+		if !(r >= '0' && r <= '9' || r == ' ') {
+			return fmt.Errorf("F: character %q is not permitted in NumericString", r)
+		}
+	}`,
+		},
+		{
+			name:     "permitted alphabet",
+			typeDecl: `IA5String (FROM ("a".."f" | "x"))`,
+			expected: `for _, r := range v.F {
+		if !((r >= 'a' && r <= 'f') || r == 'x') {
+			return fmt.Errorf("F: character %q is not permitted by constraint", r)
+		}
+	}`,
+		},
+		{
+			name:     "pattern",
+			typeDecl: `UTF8String (PATTERN "\d#3")`,
+			expected: `var validateMsgPattern0 = regexp.MustCompile("^(?:\\d{3})$")`,
+		},
+		{
+			name:     "enumeration",
+			typeDecl: "ENUMERATED { a, b(5) }",
+			expected: "if v.F != 0 && v.F != 5 {",
+		},
+		{
+			name:     "inner type constraint",
+			typeDecl: "Inner (WITH COMPONENTS { n (1..3) })",
+			expected: "if !(int64(v.F.N) >= 1 && int64(v.F.N) <= 3) {",
+		},
+		{
+			name:     "inner type constraint of elements",
+			typeDecl: "SEQUENCE (WITH COMPONENT (1..3)) OF Small",
+			expected: "if !(int64(elem2) >= 1 && int64(elem2) <= 3) {",
+		},
+		{
+			name:     "absent component",
+			typeDecl: "Inner (WITH COMPONENTS { ..., o ABSENT })",
+			expected: `if v.F.O != 0 {
+		return fmt.Errorf("F.O: component is not permitted by constraint")
+	}`,
+		},
+		{
+			name:     "nested type",
+			typeDecl: "Inner OPTIONAL",
+			expected: `if !reflect.ValueOf(v.F).IsZero() {
+		if err := v.F.Validate(); err != nil {
+			return fmt.Errorf("F.%w", err)
+		}
+	}`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := parseModule(t, `
+			TestSpec DEFINITIONS AUTOMATIC TAGS ::= BEGIN
+				Small ::= INTEGER (0..7)
+				Inner ::= SEQUENCE { n Small, o INTEGER OPTIONAL }
+				Msg ::= SEQUENCE { f `+tc.typeDecl+` }
+			END
+			`)
+			buf := &bytes.Buffer{}
+			if err := NewCodeGenerator(GenParams{Type: GEN_VALIDATE}).Generate(*m, buf); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !strings.Contains(buf.String(), tc.expected) {
+				t.Errorf("Generated module does not contain %v:\n%v", tc.expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestValidateConstrainedTypes(t *testing.T) {
+	m := parseModule(t, `
+	TestSpec DEFINITIONS AUTOMATIC TAGS ::= BEGIN
+		Small ::= INTEGER (0..7)
+		Code ::= UTF8String (SIZE (3))
+		Codes ::= SEQUENCE (SIZE (1..2)) OF Code
+		Alias ::= Small
+	END
+	`)
+	buf := &bytes.Buffer{}
+	if err := NewCodeGenerator(GenParams{Type: GEN_VALIDATE}).Generate(*m, buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, expected := range []string{
+		`type Small int64
+
+func (v Small) Validate() error {
+	if !(int64(v) >= 0 && int64(v) <= 7) {
+		return fmt.Errorf("value %v is not permitted by constraint", int64(v))
+	}
+	return nil
+}`,
+		`type Code string
+
+func (v Code) Validate() error {
+	if !(utf8.RuneCountInString(string(v)) == 3) {`,
+		`type Codes []Code
+
+func (v Codes) Validate() error {
+	if !(len([]Code(v)) >= 1 && len([]Code(v)) <= 2) {`,
+		"type Alias = Small",
+	} {
+		if !strings.Contains(buf.String(), expected) {
+			t.Errorf("Generated module does not contain %v:\n%v", expected, buf.String())
+		}
+	}
+}
+
+func TestValidateConstrainedReferences(t *testing.T) {
+	m := parseModule(t, `
+	TestSpec DEFINITIONS AUTOMATIC TAGS ::= BEGIN
+		T ::= SEQUENCE { a INTEGER OPTIONAL, b INTEGER OPTIONAL, c INTEGER }
+		Part ::= T (WITH COMPONENTS { ..., b ABSENT, c (1..3) })
+		Alias ::= Part
+	END
+	`)
+	buf := &bytes.Buffer{}
+	if err := NewCodeGenerator(GenParams{Type: GEN_VALIDATE}).Generate(*m, buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, expected := range []string{
+		`type Part T
+
+func (v Part) Validate() error {
+	if err := T(v).Validate(); err != nil {
+		return err
+	}
+	if T(v).B != 0 {
+		return fmt.Errorf("B: component is not permitted by constraint")
+	}
+	if !(T(v).C >= 1 && T(v).C <= 3) {`,
+		"type Alias = Part",
+	} {
+		if !strings.Contains(buf.String(), expected) {
+			t.Errorf("Generated module does not contain %v:\n%v", expected, buf.String())
+		}
+	}
+}
+
+func TestValidateConstraintErrors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		typeDecl string
+		expected string
+	}{
+		{
+			name:     "inner type constraint of elements",
+			typeDecl: "L ::= SEQUENCE OF INTEGER (WITH COMPONENT (0..9))",
+			expected: "type L: inner type constraint can only be applied to SEQUENCE, SET, CHOICE, SEQUENCE OF or SET OF types, not INTEGER",
+		},
+		{
+			name:     "constrained reference to CHOICE",
+			typeDecl: "D ::= C (WITH COMPONENTS { a ABSENT })",
+			expected: "type D: constrained references to CHOICE types are not supported by validator",
+		},
+		{
+			name:     "named character in pattern",
+			typeDecl: `N ::= UTF8String (PATTERN "\N{greekCapitalLetterSigma}")`,
+			expected: `type N: pattern "\\N{greekCapitalLetterSigma}": named character reference at offset 0 is not supported`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := parseModule(t, `
+			TestSpec DEFINITIONS AUTOMATIC TAGS ::= BEGIN
+				C ::= CHOICE { a INTEGER, b BOOLEAN }
+				`+tc.typeDecl+`
+			END
+			`)
+			err := NewCodeGenerator(GenParams{Type: GEN_VALIDATE, ChoiceRepr: ChoiceReprInterface}).Generate(*m, &bytes.Buffer{})
+			if err == nil || !strings.Contains(err.Error(), tc.expected) {
+				t.Errorf("Expected error %q, got %v", tc.expected, err)
+			}
+		})
+	}
+}
+
+func TestValidateExtensibleConstraints(t *testing.T) {
+	m := parseModule(t, `
+	TestSpec DEFINITIONS AUTOMATIC TAGS ::= BEGIN
+		Msg ::= SEQUENCE { f INTEGER (0..7, ...), e ENUMERATED { a, ... } }
+	END
+	`)
+	buf := &bytes.Buffer{}
+	if err := NewCodeGenerator(GenParams{Type: GEN_VALIDATE}).Generate(*m, buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := `func (v Msg) Validate() error {
+	return nil
+}`
+	if !strings.Contains(buf.String(), expected) {
+		t.Errorf("Generated module does not contain %v:\n%v", expected, buf.String())
+	}
+}
+
+func TestGoRegexp(t *testing.T) {
+	testCases := []struct {
+		pattern  string
+		expected string
+	}{
+		{pattern: "[a-z]+", expected: "^(?:[a-z]+)$"},
+		{pattern: "\\d#4", expected: "^(?:\\d{4})$"},
+		{pattern: "(ab)#(1,3)c#(2,)d#(,5)", expected: "^(?:(ab){1,3}c{2,}d{0,5})$"},
+		{pattern: "[#\\]]x", expected: "^(?:[#\\]]x)$"},
+		{pattern: "{0,0,0,65}{0, 0, 4, 16}#2", expected: "^(?:\\x{41}\\x{410}{2})$"},
+		{pattern: "[{2,0}-{7,14}]", expected: "^(?:[\\x{20}-\\x{7E}])$"},
+	}
+	for _, tc := range testCases {
+		got, err := goRegexp(tc.pattern)
+		if err != nil {
+			t.Errorf("Unexpected error for %q: %v", tc.pattern, err)
+		} else if got != tc.expected {
+			t.Errorf("Expected %q for %q, got %q", tc.expected, tc.pattern, got)
+		}
+	}
+	for _, pattern := range []string{"a#", "a#(1", "a#(,)", "(a", "{0,0,0", "{1,2,3}", "{8,0}", "{0,0,0,x}", "\\N{greekCapitalLetterSigma}"} {
+		if _, err := goRegexp(pattern); err == nil {
+			t.Errorf("Expected error for %q", pattern)
+		}
+	}
+}
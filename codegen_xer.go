@@ -0,0 +1,303 @@
+package asn1go
+
+import (
+	"bytes"
+	"fmt"
+	goast "go/ast"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// xerPackage is import path of runtime package used by generated XER encoders.
+const xerPackage = "github.com/chemikadze/asn1go/xer"
+
+// xerMethodsTemplate generates methods encoding and decoding the type with XER.
+var xerMethodsTemplate = template.Must(template.New("xer").Parse(`
+func (v {{.Name}}) MarshalXER() ([]byte, error) {
+	return xer.Marshal({{printf "%q" .TypeName}}, v)
+}
+
+func (v {{.Name}}) EncodeXER(e *xer.Encoder) error {
+{{.Encode -}}
+	return nil
+}
+{{- if .Decodable}}
+
+func (v *{{.Name}}) UnmarshalXER(data []byte) error {
+	return xer.Unmarshal(data, {{printf "%q" .TypeName}}, v)
+}
+
+func (v *{{.Name}}) DecodeXER(d *xer.Decoder) error {
+{{.Decode -}}
+	return nil
+}
+{{- end}}
+`))
+
+type xerMethodsParams struct {
+	perMethodsParams
+	// TypeName is a name of ASN.1 type, which names the element holding encoded value.
+	TypeName string
+}
+
+// generateXERDecls generates XER encoding and decoding methods of the type declared by decl.
+// See generatePERDecls for types which get methods.
+func (ctx *moduleContext) generateXERDecls(a TypeAssignment, decl goast.Decl) []goast.Decl {
+	name := goifyName(a.TypeReference.Name())
+	switch t := ctx.removeWrapperTypes(a.Type).(type) {
+	case SequenceType, SetType:
+		return ctx.generateXERMethods(name, a.TypeReference.Name(), "v", "v", a.Type)
+	case TypeReference:
+		// see generatePERDecls
+		if !ctx.isDefinedReference(a.Type) {
+			return nil
+		}
+		typeName := exprString(ctx.generateTypeExpr(t))
+		return ctx.generateXERMethods(name, a.TypeReference.Name(), typeName+"(v)", "(*"+typeName+")(v)", a.Type)
+	default:
+		return nil
+	}
+}
+
+// generateXERMethods generates MarshalXER, EncodeXER, UnmarshalXER and DecodeXER methods of go type typeName,
+// declared for ASN.1 type asn1Name, which encode go expression encodeExpr of type t,
+// and decode into addressable go expression decodeExpr.
+func (ctx *moduleContext) generateXERMethods(typeName string, asn1Name string, encodeExpr string, decodeExpr string, t Type) []goast.Decl {
+	enc := &xerEncoderGen{}
+	enc.encode(ctx, encodeExpr, t)
+	dec := &xerDecoderGen{}
+	dec.decode(ctx, decodeExpr, t)
+	return ctx.executeXERMethodsTemplate(xerMethodsParams{
+		perMethodsParams: perMethodsParams{Name: typeName, Encode: enc.buf.String(), Decode: dec.buf.String(), Decodable: true},
+		TypeName:         asn1Name,
+	})
+}
+
+func (ctx *moduleContext) executeXERMethodsTemplate(params xerMethodsParams) []goast.Decl {
+	ctx.requireModule(xerPackage)
+	var buf bytes.Buffer
+	if err := xerMethodsTemplate.Execute(&buf, params); err != nil {
+		ctx.appendError(fmt.Errorf("type %v: %w", params.Name, err))
+		return nil
+	}
+	decls, err := parseGoDecls(buf.String())
+	if err != nil {
+		ctx.appendError(fmt.Errorf("type %v: failed to parse generated code: %w", params.Name, err))
+		return nil
+	}
+	return decls
+}
+
+// xerEnumeration returns go expression of xer.Enumeration value describing ENUMERATED type.
+func (ctx *moduleContext) xerEnumeration(t EnumeratedType) string {
+	root, additions, err := ctx.enumerationItems(t)
+	if err != nil {
+		ctx.appendError(err)
+		return "xer.Enumeration{}"
+	}
+	items := make([]string, 0, len(root)+len(additions))
+	for _, item := range append(root, additions...) {
+		items = append(items, fmt.Sprintf("{Name: %q, Value: %v}", item.name, item.value))
+	}
+	return "xer.Enumeration{" + strings.Join(items, ", ") + "}"
+}
+
+// xerTypeName returns name of element enclosing element of SEQUENCE OF or SET OF value of type t,
+// which is either its identifier, name of referenced type, or name of built-in type, e.g. OCTET_STRING.
+// See X.693, section 8.3.
+func (ctx *moduleContext) xerTypeName(t Type) string {
+	switch tt := ctx.removeWrapperTypes(t).(type) {
+	case NamedType:
+		return tt.Identifier.Name()
+	case TypeReference:
+		return tt.Name()
+	}
+	if name := builtinTypeName(ctx.removeWrapperTypes(t)); name != "" {
+		return strings.ReplaceAll(name, " ", "_")
+	}
+	ctx.appendError(fmt.Errorf("type %#v is not supported by XER encoder", t))
+	return ""
+}
+
+// xerValueList returns true if elements of SEQUENCE OF or SET OF type with elements of type t
+// are encoded without enclosing elements, which is the case for BOOLEAN, ENUMERATED and CHOICE types.
+func (ctx *moduleContext) xerValueList(t Type) bool {
+	if named, ok := ctx.removeWrapperTypes(t).(NamedType); ok {
+		t = named.Type
+	}
+	switch resolved, _ := ctx.underlyingType(t); resolved.(type) {
+	case BooleanType, EnumeratedType, ChoiceType:
+		return true
+	default:
+		return false
+	}
+}
+
+// xerEncoderGen generates go statements encoding values with XER.
+type xerEncoderGen struct {
+	derEncoderGen
+	// present is go expression of OPTIONAL component being encoded, which is known to be present.
+	present string
+}
+
+// encode writes statements appending encoding of go expression expr of type t to encoder e,
+// which is the content of the element holding the value.
+func (g *xerEncoderGen) encode(ctx *moduleContext, expr string, t Type) {
+	switch tt := t.(type) {
+	case TaggedType:
+		g.encode(ctx, expr, tt.Type)
+	case ConstraintedType:
+		if contained, ok := ctx.contentsOf(tt); ok {
+			// contained value is encoded in place of the string, as required by X.693
+			g.encode(ctx, expr, contained)
+			return
+		}
+		g.constrain(ctx, tt)
+		g.encode(ctx, expr, tt.Type)
+	case NamedType:
+		g.encode(ctx, expr, tt.Type)
+	case TypeReference:
+		g.encodeReference(ctx, expr, tt)
+	case SequenceType:
+		g.encodeSequence(ctx, expr, tt.Components, tt.ExtensionAdditions, false)
+	case SetType:
+		g.encodeSequence(ctx, expr, tt.Components, tt.ExtensionAdditions, true)
+	case SequenceOfType:
+		g.encodeElements(ctx, expr, tt.Type, "e.WriteList")
+	case SetOfType:
+		g.encodeElements(ctx, expr, tt.Type, "e.WriteSetOf")
+	case ChoiceType:
+		if ctx.params.ChoiceRepr == ChoiceReprInterface {
+			ctx.appendError(fmt.Errorf("CHOICE type %#v should be declared by type assignment", t))
+		} else {
+			ctx.appendError(fmt.Errorf("CHOICE types with %v representation are not supported by XER encoder", ctx.params.ChoiceRepr))
+		}
+	case BooleanType:
+		g.line("e.WriteBoolean(%v)", expr)
+	case IntegerType:
+		if v, big := ctx.integerArg(expr, g.currentIntegerType(ctx)); big {
+			g.check("e.WriteBigInteger(%v)", v)
+		} else {
+			g.line("e.WriteInteger(%v)", v)
+		}
+	case EnumeratedType:
+		ctx.requireModule("encoding/asn1")
+		g.check("e.WriteEnumerated(asn1.Enumerated(%v), %v)", expr, ctx.xerEnumeration(tt))
+	case RealType:
+		g.line("e.WriteReal(%v)", expr)
+	case OctetStringType:
+		g.line("e.WriteOctetString(%v)", expr)
+	case BitStringType:
+		g.check("e.WriteBitString(%v)", ctx.bitStringExpr(tt, expr))
+	case NullType:
+		g.line("e.WriteNull()")
+	case ObjectIdentifierType:
+		g.check("e.WriteObjectIdentifier(%v)", expr)
+	case RestrictedStringType:
+		if _, ok := restrictedStringTags[tt.LexType]; ok {
+			g.line("e.WriteString(%v)", expr)
+		} else {
+			ctx.appendError(fmt.Errorf("restricted string type %#v is not supported by XER encoder", tt))
+		}
+	default:
+		ctx.appendError(fmt.Errorf("type %#v is not supported by XER encoder", t))
+	}
+}
+
+// encodeReference writes statements encoding value of referenced type. Types having XER methods
+// are encoded by calling them, and other types are encoded inline.
+func (g *xerEncoderGen) encodeReference(ctx *moduleContext, expr string, t TypeReference) {
+	assignment, assignmentCtx, err := ctx.lookupTypeAssignment(t)
+	if err != nil {
+		ctx.appendError(err)
+		return
+	}
+	if assignment == nil {
+		switch t.Name() {
+		case GeneralizedTimeName:
+			g.check("e.WriteGeneralizedTime(%v)", expr)
+		case UTCTimeName:
+			g.check("e.WriteUTCTime(%v)", expr)
+		default:
+			if useful := ctx.lookupUsefulType(t); useful != nil {
+				g.encode(ctx, expr, useful)
+			} else {
+				ctx.appendError(fmt.Errorf("can not resolve TypeReference %v", t.Name()))
+			}
+		}
+		return
+	}
+	if assignmentCtx.hasEncodingMethods(assignment.Type) {
+		if ctx.choiceTypeName(t) != nil && expr != g.present {
+			g.line("if %v == nil {\n\t\treturn xer.ErrAbsentValue\n\t}", expr)
+		}
+		g.check("%v.EncodeXER(e)", expr)
+		return
+	}
+	name := string(assignmentCtx.moduleName) + "." + t.Name()
+	if slices.Contains(g.inlined, name) {
+		ctx.appendError(fmt.Errorf("type %v: reference cycle %v", t, strings.Join(append(g.inlined, name), " -> ")))
+		return
+	}
+	g.inlined = append(g.inlined, name)
+	g.encode(assignmentCtx, assignmentCtx.definedTypeValue(assignment.Type, expr), assignment.Type)
+	g.inlined = g.inlined[:len(g.inlined)-1]
+}
+
+// encodeSequence writes statements encoding SEQUENCE or SET value as an element per present component,
+// named by its identifier. Components equal to their DEFAULT values are omitted, components of SET
+// are encoded in canonical order of their tags, and components of extension addition groups
+// are encoded in the same way as other components.
+func (g *xerEncoderGen) encodeSequence(ctx *moduleContext, expr string, components ComponentTypeList, additions ExtensionAdditions, set bool) {
+	g.encodeComponents(ctx, ctx.perComponents(expr, components, set))
+	for _, a := range ctx.perAdditions(expr, additions) {
+		if !a.group {
+			g.line("if %v {", a.present)
+			g.present = a.components[0].field
+			g.encodeComponents(ctx, []perComponent{{named: a.components[0].named, field: a.components[0].field}})
+			g.present = ""
+			g.line("}")
+			continue
+		}
+		g.line("if %v {", a.present)
+		g.encodeComponents(ctx, a.components)
+		g.line("}")
+	}
+}
+
+// encodeComponents writes statements encoding present components as elements.
+func (g *xerEncoderGen) encodeComponents(ctx *moduleContext, components []perComponent) {
+	for _, c := range components {
+		if c.present != "" {
+			g.line("if %v {", c.present)
+			g.present = c.field
+		}
+		identifier := c.named.NamedType.Identifier.Name()
+		g.line("e.BeginElement(%q)", identifier)
+		g.encode(ctx, c.field, c.named.NamedType.Type)
+		g.line("e.EndElement(%q)", identifier)
+		if c.present != "" {
+			g.line("}")
+			g.present = ""
+		}
+	}
+}
+
+// encodeElements writes statements encoding elements of SEQUENCE OF or SET OF value with write function,
+// which is either e.WriteList or e.WriteSetOf. Each element is enclosed into element named by its type,
+// unless it is encoded as a list of values.
+func (g *xerEncoderGen) encodeElements(ctx *moduleContext, expr string, t Type, write string) {
+	i := g.newVar("i")
+	g.line("if err := %v(len(%v), func(%v int) error {", write, expr, i)
+	if ctx.xerValueList(t) {
+		g.encode(ctx, expr+"["+i+"]", t)
+	} else {
+		name := ctx.xerTypeName(t)
+		g.line("e.BeginElement(%q)", name)
+		g.encode(ctx, expr+"["+i+"]", t)
+		g.line("e.EndElement(%q)", name)
+	}
+	g.line("return nil")
+	g.line("}); err != nil {\n\t\treturn err\n\t}")
+}
@@ -0,0 +1,267 @@
+package asn1go
+
+import (
+	"bytes"
+	"fmt"
+	goast "go/ast"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// xerChoiceTemplate generates functions decoding CHOICE type from XER.
+var xerChoiceTemplate = template.Must(template.New("xerChoice").Parse(`
+func UnmarshalXER{{.Name}}(data []byte) ({{.Name}}, error) {
+	var v {{.Name}}
+	if err := xer.Unmarshal(data, {{printf "%q" .TypeName}}, xer.UnmarshalerFunc(func(d *xer.Decoder) error {
+		return DecodeXER{{.Name}}(d, &v)
+	})); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func DecodeXER{{.Name}}(d *xer.Decoder, v *{{.Name}}) error {
+	return d.ReadChoice(func(name string, d *xer.Decoder) error {
+		switch name {
+{{- range .Alternatives}}
+		case {{printf "%q" .Identifier}}:
+			var alt {{.Name}}
+{{.Body -}}
+			*v = alt
+{{- end}}
+		default:
+{{- if .Extensible}}
+			// value of unknown extension addition can not be represented
+			*v = nil
+{{- else}}
+			return xer.SyntaxError{Msg: "unexpected alternative " + name + " of {{.TypeName}}"}
+{{- end}}
+		}
+		return nil
+	})
+}
+`))
+
+// generateXERChoiceDecls generates XER encoding methods of wrapper types of CHOICE alternatives,
+// which encode a single element named by identifier of the alternative, and functions decoding CHOICE type.
+// Encodings of the alternatives are enclosed into element named typeName, which is the name of CHOICE type.
+func (ctx *moduleContext) generateXERChoiceDecls(name string, typeName string, t ChoiceType, alternatives []choiceAlternativeParams) []goast.Decl {
+	extensible := t.Extensible || ctx.extensibilityImplied || len(t.ExtensionTypes) > 0
+	var decls []goast.Decl
+	var params []jerChoiceAlternativeParams
+	for _, alternative := range alternatives {
+		identifier := alternative.alternative.Identifier.Name()
+		enc := &xerEncoderGen{}
+		enc.line("e.BeginElement(%q)", identifier)
+		enc.encode(ctx, "v.Value", alternative.alternative.Type)
+		enc.line("e.EndElement(%q)", identifier)
+		dec := &xerDecoderGen{}
+		dec.decode(ctx, "alt.Value", alternative.alternative.Type)
+		decls = append(decls, ctx.executeXERMethodsTemplate(xerMethodsParams{
+			perMethodsParams: perMethodsParams{Name: alternative.Name, Encode: enc.buf.String()},
+			TypeName:         typeName,
+		})...)
+		params = append(params, jerChoiceAlternativeParams{Name: alternative.Name, Identifier: identifier, Body: dec.buf.String()})
+	}
+	var buf bytes.Buffer
+	err := xerChoiceTemplate.Execute(&buf, map[string]any{"Name": name, "TypeName": typeName, "Extensible": extensible, "Alternatives": params})
+	if err != nil {
+		ctx.appendError(fmt.Errorf("type %v: %w", name, err))
+		return nil
+	}
+	choiceDecls, err := parseGoDecls(buf.String())
+	if err != nil {
+		ctx.appendError(fmt.Errorf("type %v: failed to parse generated code: %w", name, err))
+		return nil
+	}
+	return append(decls, choiceDecls...)
+}
+
+// xerDecoderGen generates go statements decoding values from XER.
+type xerDecoderGen struct {
+	derEncoderGen
+}
+
+// decode writes statements decoding the value from content of the element of decoder d
+// into addressable go expression expr of type t.
+func (g *xerDecoderGen) decode(ctx *moduleContext, expr string, t Type) {
+	switch tt := t.(type) {
+	case TaggedType:
+		g.decode(ctx, expr, tt.Type)
+	case ConstraintedType:
+		if contained, ok := ctx.contentsOf(tt); ok {
+			g.decode(ctx, expr, contained)
+			return
+		}
+		g.constrain(ctx, tt)
+		g.decode(ctx, expr, tt.Type)
+	case NamedType:
+		g.decode(ctx, expr, tt.Type)
+	case TypeReference:
+		g.decodeReference(ctx, expr, tt)
+	case SequenceType:
+		g.decodeSequence(ctx, expr, tt.Components, tt.ExtensionAdditions, tt.Extensible)
+	case SetType:
+		g.decodeSequence(ctx, expr, tt.Components, tt.ExtensionAdditions, tt.Extensible)
+	case SequenceOfType:
+		g.decodeElements(ctx, expr, tt.Type)
+	case SetOfType:
+		g.decodeElements(ctx, expr, tt.Type)
+	case ChoiceType:
+		if ctx.params.ChoiceRepr == ChoiceReprInterface {
+			ctx.appendError(fmt.Errorf("CHOICE type %#v should be declared by type assignment", t))
+		} else {
+			ctx.appendError(fmt.Errorf("CHOICE types with %v representation are not supported by XER decoder", ctx.params.ChoiceRepr))
+		}
+	case BooleanType:
+		g.check("d.ReadBoolean(&%v)", expr)
+	case IntegerType:
+		goType := g.currentIntegerType(ctx)
+		v, big := g.integerTarget(ctx, expr, goType)
+		if big {
+			g.check("d.ReadBigInteger(&%v)", v)
+		} else {
+			g.check("d.ReadInteger(&%v)", v)
+		}
+		g.assignInteger(ctx, expr, v, goType)
+	case EnumeratedType:
+		ctx.requireModule("encoding/asn1")
+		g.check("d.ReadEnumerated((*asn1.Enumerated)(&%v), %v)", expr, ctx.xerEnumeration(tt))
+	case RealType:
+		g.check("d.ReadReal(&%v)", expr)
+	case OctetStringType:
+		g.check("d.ReadOctetString(&%v)", expr)
+	case BitStringType:
+		g.check("d.ReadBitString(%v)", ctx.bitStringPointerExpr(tt, expr))
+	case NullType:
+		g.check("d.ReadNull()")
+	case ObjectIdentifierType:
+		g.check("d.ReadObjectIdentifier(&%v)", expr)
+	case RestrictedStringType:
+		if _, ok := restrictedStringTags[tt.LexType]; ok {
+			g.check("d.ReadString(&%v)", expr)
+		} else {
+			ctx.appendError(fmt.Errorf("restricted string type %#v is not supported by XER decoder", tt))
+		}
+	default:
+		ctx.appendError(fmt.Errorf("type %#v is not supported by XER decoder", t))
+	}
+}
+
+// decodeReference writes statements decoding value of referenced type. Types having XER methods
+// are decoded by calling them, and other types are decoded inline.
+func (g *xerDecoderGen) decodeReference(ctx *moduleContext, expr string, t TypeReference) {
+	assignment, assignmentCtx, err := ctx.lookupTypeAssignment(t)
+	if err != nil {
+		ctx.appendError(err)
+		return
+	}
+	if assignment == nil {
+		switch t.Name() {
+		case GeneralizedTimeName:
+			g.check("d.ReadGeneralizedTime(&%v)", expr)
+		case UTCTimeName:
+			g.check("d.ReadUTCTime(&%v)", expr)
+		default:
+			if useful := ctx.lookupUsefulType(t); useful != nil {
+				g.decode(ctx, expr, useful)
+			} else {
+				ctx.appendError(fmt.Errorf("can not resolve TypeReference %v", t.Name()))
+			}
+		}
+		return
+	}
+	if assignmentCtx.hasEncodingMethods(assignment.Type) {
+		if choiceName := ctx.choiceTypeName(t); choiceName != nil {
+			g.check("%v(d, &%v)", exprString(choiceMemberExpr(choiceName, "DecodeXER", "")), expr)
+			return
+		}
+		g.check("%v.DecodeXER(d)", expr)
+		return
+	}
+	name := string(assignmentCtx.moduleName) + "." + t.Name()
+	if slices.Contains(g.inlined, name) {
+		ctx.appendError(fmt.Errorf("type %v: reference cycle %v", t, strings.Join(append(g.inlined, name), " -> ")))
+		return
+	}
+	g.inlined = append(g.inlined, name)
+	g.decode(assignmentCtx, assignmentCtx.definedTypeVariable(assignment.Type, expr), assignment.Type)
+	g.inlined = g.inlined[:len(g.inlined)-1]
+}
+
+// decodeSequence writes statements decoding SEQUENCE or SET value, see xerEncoderGen.encodeSequence.
+// Components are accepted in any order. Absent OPTIONAL components are reset, and absent components
+// with DEFAULT values are set to them. Mandatory components of extension root should be present,
+// and unknown elements of extensible types are ignored.
+func (g *xerDecoderGen) decodeSequence(ctx *moduleContext, expr string, components ComponentTypeList, additions ExtensionAdditions, extensible bool) {
+	extensible = extensible || ctx.extensibilityImplied || len(additions) > 0
+	root := ctx.perComponents(expr, components, false)
+	all := slices.Clone(root)
+	for _, a := range ctx.perAdditions(expr, additions) {
+		all = append(all, a.components...)
+	}
+	for _, c := range all {
+		if c.named.Default != nil {
+			g.line("%v = %v", c.field, ctx.derDefaultExpr(c.named.NamedType, *c.named.Default))
+		} else if absent := ctx.absentComponent(c.field, c.pointer, c.named); absent != "" {
+			g.line(absent)
+		}
+	}
+	var mandatory []perComponent
+	for _, c := range root {
+		if c.present == "" {
+			mandatory = append(mandatory, c)
+		}
+	}
+	var present string
+	if len(mandatory) > 0 {
+		present = g.newVar("present")
+		g.line("var %v [%v]bool", present, len(mandatory))
+	}
+	g.line("if err := d.ReadElements(func(name string, d *xer.Decoder) error {")
+	g.line("switch name {")
+	for _, c := range all {
+		g.line("case %q:", c.named.NamedType.Identifier.Name())
+		if i := slices.IndexFunc(mandatory, func(m perComponent) bool { return m.field == c.field }); i >= 0 {
+			g.line("%v[%v] = true", present, i)
+		}
+		if c.pointer != "" {
+			g.line(ctx.allocComponent(c.pointer, c.named))
+		}
+		g.decode(ctx, c.field, c.named.NamedType.Type)
+		if present := ctx.presentComponent(c.field, c.named); present != "" {
+			g.line(present)
+		}
+	}
+	g.line("default:")
+	if extensible {
+		g.line("// unknown extension additions are ignored")
+	} else {
+		g.line("return xer.SyntaxError{Msg: \"unexpected component \" + name}")
+	}
+	g.line("}")
+	g.line("return nil")
+	g.line("}); err != nil {\n\t\treturn err\n\t}")
+	for i, c := range mandatory {
+		g.line("if !%v[%v] {", present, i)
+		g.line("return xer.SyntaxError{Msg: %q}", "component "+c.named.NamedType.Identifier.Name()+" is missing")
+		g.line("}")
+	}
+}
+
+// decodeElements writes statements decoding elements of SEQUENCE OF or SET OF value, see xerEncoderGen.encodeElements.
+func (g *xerDecoderGen) decodeElements(ctx *moduleContext, expr string, t Type) {
+	elem := g.newVar("elem")
+	g.line("%v = %v[:0]", expr, expr)
+	if ctx.xerValueList(t) {
+		g.line("if err := d.ReadValueList(%q, func(d *xer.Decoder) error {", ctx.xerTypeName(t))
+	} else {
+		g.line("if err := d.ReadList(func(d *xer.Decoder) error {")
+	}
+	g.line("var %v %v", elem, exprString(ctx.generateTypeExpr(t)))
+	g.decode(ctx, elem, t)
+	g.line("%v = append(%v, %v)", expr, expr, elem)
+	g.line("return nil")
+	g.line("}); err != nil {\n\t\treturn err\n\t}")
+}
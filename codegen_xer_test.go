@@ -0,0 +1,90 @@
+package asn1go
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestXERTypes(t *testing.T) {
+	testCases := []struct {
+		name     string
+		typeDecl string
+		expected string
+	}{
+		{
+			name:     "component named by identifier",
+			typeDecl: "INTEGER",
+			expected: `e.BeginElement("f")`,
+		},
+		{
+			name:     "enumeration with additions",
+			typeDecl: "ENUMERATED { b(1), a(0), ..., c }",
+			expected: `e.WriteEnumerated(asn1.Enumerated(v.F), xer.Enumeration{{Name: "a", Value: 0}, {Name: "b", Value: 1}, {Name: "c", Value: 2}})`,
+		},
+		{
+			name:     "list of elements named by built-in type",
+			typeDecl: "SEQUENCE OF OCTET STRING",
+			expected: `e.BeginElement("OCTET_STRING")`,
+		},
+		{
+			name:     "list of elements named by identifier",
+			typeDecl: "SEQUENCE OF item IA5String",
+			expected: `e.BeginElement("item")`,
+		},
+		{
+			name:     "list of boolean values",
+			typeDecl: "SEQUENCE OF BOOLEAN",
+			expected: `d.ReadValueList("BOOLEAN", func(d *xer.Decoder) error {`,
+		},
+		{
+			name:     "set of elements sorted by encoding",
+			typeDecl: "SET OF INTEGER",
+			expected: "e.WriteSetOf(len(v.F), func(i int) error {",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := parseModule(t, `
+			TestSpec DEFINITIONS AUTOMATIC TAGS ::= BEGIN
+				Msg ::= SEQUENCE { f `+tc.typeDecl+` }
+			END
+			`)
+			buf := &bytes.Buffer{}
+			if err := NewCodeGenerator(GenParams{Type: GEN_XER}).Generate(*m, buf); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !strings.Contains(buf.String(), tc.expected) {
+				t.Errorf("Generated module does not contain %v:\n%v", tc.expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestXERSetComponentsOrder(t *testing.T) {
+	m := parseModule(t, `
+	TestSpec DEFINITIONS ::= BEGIN
+		Msg ::= SET { b [2] INTEGER, a [1] INTEGER }
+	END
+	`)
+	buf := &bytes.Buffer{}
+	if err := NewCodeGenerator(GenParams{Type: GEN_XER}).Generate(*m, buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	code := buf.String()
+	if a, b := strings.Index(code, `e.BeginElement("a")`), strings.Index(code, `e.BeginElement("b")`); a < 0 || b < 0 || a > b {
+		t.Errorf("Expected components encoded in canonical order:\n%v", code)
+	}
+}
+
+func TestXERMethodsErrors(t *testing.T) {
+	m := parseModule(t, `
+	TestSpec DEFINITIONS ::= BEGIN
+		Msg ::= SEQUENCE { value ANY }
+	END
+	`)
+	err := NewCodeGenerator(GenParams{Type: GEN_XER}).Generate(*m, &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "not supported by XER") {
+		t.Errorf("Expected error about ANY type, got %v", err)
+	}
+}
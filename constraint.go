@@ -0,0 +1,672 @@
+package asn1go
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// Range is a range of integer values. It is not bounded from below if Lower is nil,
+// and is not bounded from above if Upper is nil. Bounds are shared between ranges, and are never modified.
+type Range struct {
+	Lower, Upper *big.Int
+}
+
+// NewRange returns range of values from lower to upper, inclusive.
+func NewRange(lower, upper int64) Range {
+	return Range{Lower: big.NewInt(lower), Upper: big.NewInt(upper)}
+}
+
+// Contains returns true if n is in the range.
+func (r Range) Contains(n *big.Int) bool {
+	return (r.Lower == nil || n.Cmp(r.Lower) >= 0) && (r.Upper == nil || n.Cmp(r.Upper) <= 0)
+}
+
+// String returns the range in value range notation, e.g. 0..MAX.
+func (r Range) String() string {
+	lower, upper := "MIN", "MAX"
+	if r.Lower != nil {
+		lower = r.Lower.String()
+	}
+	if r.Upper != nil {
+		upper = r.Upper.String()
+	}
+	if r.Lower != nil && r.Upper != nil && r.Lower.Cmp(r.Upper) == 0 {
+		return lower
+	}
+	return lower + ".." + upper
+}
+
+func (r Range) isEmpty() bool {
+	return r.Lower != nil && r.Upper != nil && r.Lower.Cmp(r.Upper) > 0
+}
+
+// RangeSet is a set of integer values, represented with sorted ranges which neither overlap nor adjoin.
+// Empty set contains no values.
+type RangeSet []Range
+
+// NewRangeSet returns set of values which are contained in any of ranges.
+func NewRangeSet(ranges ...Range) RangeSet {
+	sorted := slices.DeleteFunc(slices.Clone(ranges), Range.isEmpty)
+	slices.SortFunc(sorted, func(a, b Range) int {
+		switch {
+		case a.Lower == nil && b.Lower == nil:
+			return 0
+		case a.Lower == nil:
+			return -1
+		case b.Lower == nil:
+			return 1
+		default:
+			return a.Lower.Cmp(b.Lower)
+		}
+	})
+	res := RangeSet{}
+	for _, r := range sorted {
+		if len(res) == 0 {
+			res = append(res, r)
+			continue
+		}
+		last := &res[len(res)-1]
+		if last.Upper != nil && r.Lower != nil && r.Lower.Cmp(successor(last.Upper)) > 0 {
+			res = append(res, r)
+			continue
+		}
+		switch {
+		case last.Upper == nil:
+		case r.Upper == nil:
+			last.Upper = nil
+		case r.Upper.Cmp(last.Upper) > 0:
+			last.Upper = r.Upper
+		}
+	}
+	return res
+}
+
+// Contains returns true if n is in the set.
+func (s RangeSet) Contains(n *big.Int) bool {
+	return slices.ContainsFunc(s, func(r Range) bool { return r.Contains(n) })
+}
+
+// IsUnbounded returns true if the set contains all values.
+func (s RangeSet) IsUnbounded() bool {
+	return len(s) == 1 && s[0].Lower == nil && s[0].Upper == nil
+}
+
+// Bounds returns the smallest range containing all values of the set. Returns false if the set is empty.
+func (s RangeSet) Bounds() (Range, bool) {
+	if len(s) == 0 {
+		return Range{}, false
+	}
+	return Range{Lower: s[0].Lower, Upper: s[len(s)-1].Upper}, true
+}
+
+// Union returns set of values contained in either s or other.
+func (s RangeSet) Union(other RangeSet) RangeSet {
+	return NewRangeSet(slices.Concat(s, other)...)
+}
+
+// Intersect returns set of values contained in both s and other.
+func (s RangeSet) Intersect(other RangeSet) RangeSet {
+	var res []Range
+	for _, a := range s {
+		for _, b := range other {
+			r := a
+			if b.Lower != nil && (r.Lower == nil || b.Lower.Cmp(r.Lower) > 0) {
+				r.Lower = b.Lower
+			}
+			if b.Upper != nil && (r.Upper == nil || b.Upper.Cmp(r.Upper) < 0) {
+				r.Upper = b.Upper
+			}
+			res = append(res, r)
+		}
+	}
+	return NewRangeSet(res...)
+}
+
+// Except returns set of values contained in s, but not in other.
+func (s RangeSet) Except(other RangeSet) RangeSet {
+	return s.Intersect(other.complement())
+}
+
+// complement returns set of values not contained in s.
+func (s RangeSet) complement() RangeSet {
+	res := RangeSet{}
+	gap := Range{}
+	for _, r := range s {
+		if r.Lower != nil {
+			gap.Upper = predecessor(r.Lower)
+			res = append(res, gap)
+		}
+		if r.Upper == nil {
+			return res
+		}
+		gap = Range{Lower: successor(r.Upper)}
+	}
+	return append(res, gap)
+}
+
+// String returns the set in constraint notation, e.g. (0..7 | 10).
+func (s RangeSet) String() string {
+	ranges := make([]string, 0, len(s))
+	for _, r := range s {
+		ranges = append(ranges, r.String())
+	}
+	return "(" + strings.Join(ranges, " | ") + ")"
+}
+
+// stringSet is a set of string values, which are sorted and unique.
+type stringSet []string
+
+func newStringSet(values ...string) stringSet {
+	return slices.Compact(stringSet(slices.Sorted(slices.Values(values))))
+}
+
+func (s stringSet) Union(other stringSet) stringSet {
+	return newStringSet(slices.Concat(s, other)...)
+}
+
+func (s stringSet) Intersect(other stringSet) stringSet {
+	return slices.DeleteFunc(slices.Clone(s), func(v string) bool { return !slices.Contains(other, v) })
+}
+
+func (s stringSet) Except(other stringSet) stringSet {
+	return slices.DeleteFunc(slices.Clone(s), func(v string) bool { return slices.Contains(other, v) })
+}
+
+// patternSet is a set of regular expressions, see X.680, Annex A, all of which permitted strings match.
+// Empty set permits any string.
+type patternSet []string
+
+// Union returns alternation of single patterns. Strings permitted by several patterns can not be
+// described by a single regular expression, and any string is permitted by their union.
+func (s patternSet) Union(other patternSet) patternSet {
+	if len(s) != 1 || len(other) != 1 {
+		return patternSet{}
+	}
+	return patternSet{"(" + s[0] + ")|(" + other[0] + ")"}
+}
+
+func (s patternSet) Intersect(other patternSet) patternSet {
+	return slices.Concat(s, other)
+}
+
+// Except returns s, as exclusion of strings matching patterns can not be described by patterns.
+func (s patternSet) Except(other patternSet) patternSet {
+	return s
+}
+
+// EffectiveConstraint describes values permitted by constraints of the type, see X.680, Annex B.
+// Permitted values are described by independent sets of INTEGER values, of sizes, and of values of strings,
+// each of which is restricted only by constraint elements applicable to it, e.g. sizes are restricted
+// by SIZE constraints only.
+//
+// Extensible sets hold values of the extension root only, as values outside of it can be permitted
+// by later versions of the type.
+type EffectiveConstraint struct {
+	// Values are permitted INTEGER values.
+	Values           RangeSet
+	ValuesExtensible bool
+	// Sizes are permitted numbers of characters of strings, of octets of OCTET STRING values,
+	// of bits of BIT STRING values, or of elements of SEQUENCE OF and SET OF values.
+	Sizes           RangeSet
+	SizesExtensible bool
+	// Strings are permitted values of character string types, or nil if values are not restricted
+	// by single value constraints.
+	Strings           []string
+	StringsExtensible bool
+	// Alphabet are code points of characters permitted in values of character string types.
+	Alphabet           RangeSet
+	AlphabetExtensible bool
+	// Patterns are regular expressions, see X.680, Annex A, which values of character string types match.
+	// Patterns are not PER-visible.
+	Patterns []string
+}
+
+// ConstraintParams control evaluation of constraints by EvaluateConstraintWithParams.
+type ConstraintParams struct {
+	// Registry is used to resolve types and values imported from other modules.
+	// If not specified, imported types and values can not be resolved.
+	Registry *ModuleRegistry
+	// PERVisible restricts evaluation to PER-visible constraints, see X.691, section 10.3.
+	// Exclusions, constraints of values of strings and patterns are not PER-visible, and are ignored.
+	PERVisible bool
+}
+
+// EvaluateConstraint returns effective constraint of type t defined in module, which can be
+// a reference to the type, and is constrained by constraints of the types it refers to as well.
+//
+// Constraint elements which can not be evaluated, e.g. WITH COMPONENTS, are treated as permitting any value,
+// and exclusions of such elements are ignored.
+func EvaluateConstraint(module *ModuleDefinition, t Type) (EffectiveConstraint, error) {
+	return EvaluateConstraintWithParams(module, t, ConstraintParams{})
+}
+
+// EvaluateConstraintWithParams is same as EvaluateConstraint, but allows to resolve imported types,
+// and to evaluate PER-visible constraints only.
+func EvaluateConstraintWithParams(module *ModuleDefinition, t Type, params ConstraintParams) (EffectiveConstraint, error) {
+	ctx := newModuleContext(*module, GenParams{Registry: params.Registry})
+	e := &constraintEvaluator{perVisible: params.PERVisible}
+	res := e.typeConstraint(ctx, t).effective()
+	return res, errors.Join(ctx.errors...)
+}
+
+// constraintDimension is a set of permitted values of one kind, see EffectiveConstraint.
+// It is not constrained if no constraint element restricts values of its kind, in which case
+// any value is permitted.
+type constraintDimension[S interface {
+	Union(S) S
+	Intersect(S) S
+	Except(S) S
+}] struct {
+	set         S
+	extensible  bool
+	constrained bool
+}
+
+func (d constraintDimension[S]) union(other constraintDimension[S]) constraintDimension[S] {
+	if !d.constrained || !other.constrained {
+		return constraintDimension[S]{}
+	}
+	return constraintDimension[S]{set: d.set.Union(other.set), extensible: d.extensible || other.extensible, constrained: true}
+}
+
+func (d constraintDimension[S]) intersect(other constraintDimension[S]) constraintDimension[S] {
+	switch {
+	case !d.constrained:
+		return other
+	case !other.constrained:
+		return d
+	}
+	return constraintDimension[S]{set: d.set.Intersect(other.set), extensible: d.extensible || other.extensible, constrained: true}
+}
+
+// except returns values of d not contained in other, where all is the set of all values of the kind.
+func (d constraintDimension[S]) except(other constraintDimension[S], all S) constraintDimension[S] {
+	if !d.constrained {
+		d.set = all
+	}
+	return constraintDimension[S]{set: d.set.Except(other.set), extensible: d.extensible, constrained: true}
+}
+
+// apply returns values of d restricted by serially applied constraint c, which determines whether they are extensible.
+func (d constraintDimension[S]) apply(c constraintDimension[S]) constraintDimension[S] {
+	switch {
+	case !c.constrained:
+		return d
+	case !d.constrained:
+		return c
+	}
+	return constraintDimension[S]{set: d.set.Intersect(c.set), extensible: c.extensible, constrained: true}
+}
+
+// constraintSet is a set of values permitted by constraint elements. Its zero value permits any value.
+type constraintSet struct {
+	values   constraintDimension[RangeSet]
+	sizes    constraintDimension[RangeSet]
+	strings  constraintDimension[stringSet]
+	alphabet constraintDimension[RangeSet]
+	patterns constraintDimension[patternSet]
+	// inexact is set if some elements were not evaluated, and were treated as permitting any value.
+	inexact bool
+}
+
+func (s constraintSet) union(other constraintSet) constraintSet {
+	return constraintSet{
+		values:   s.values.union(other.values),
+		sizes:    s.sizes.union(other.sizes),
+		strings:  s.strings.union(other.strings),
+		alphabet: s.alphabet.union(other.alphabet),
+		patterns: s.patterns.union(other.patterns),
+		inexact:  s.inexact || other.inexact,
+	}
+}
+
+func (s constraintSet) intersect(other constraintSet) constraintSet {
+	return constraintSet{
+		values:   s.values.intersect(other.values),
+		sizes:    s.sizes.intersect(other.sizes),
+		strings:  s.strings.intersect(other.strings),
+		alphabet: s.alphabet.intersect(other.alphabet),
+		patterns: s.patterns.intersect(other.patterns),
+		inexact:  s.inexact || other.inexact,
+	}
+}
+
+func (s constraintSet) apply(c constraintSet) constraintSet {
+	return constraintSet{
+		values:   s.values.apply(c.values),
+		sizes:    s.sizes.apply(c.sizes),
+		strings:  s.strings.apply(c.strings),
+		alphabet: s.alphabet.apply(c.alphabet),
+		patterns: s.patterns.apply(c.patterns),
+		inexact:  s.inexact || c.inexact,
+	}
+}
+
+// extend returns values of constraint with extension marker, which makes all restricted values extensible.
+func (s constraintSet) extend() constraintSet {
+	s.values.extensible = s.values.extensible || s.values.constrained
+	s.sizes.extensible = s.sizes.extensible || s.sizes.constrained
+	s.strings.extensible = s.strings.extensible || s.strings.constrained
+	s.alphabet.extensible = s.alphabet.extensible || s.alphabet.constrained
+	s.patterns.extensible = s.patterns.extensible || s.patterns.constrained
+	return s
+}
+
+// constrainedDimensions returns number of kinds of values restricted by the constraint.
+func (s constraintSet) constrainedDimensions() int {
+	n := 0
+	for _, constrained := range []bool{s.values.constrained, s.sizes.constrained, s.strings.constrained, s.alphabet.constrained, s.patterns.constrained} {
+		if constrained {
+			n++
+		}
+	}
+	return n
+}
+
+func (s constraintSet) effective() EffectiveConstraint {
+	res := EffectiveConstraint{
+		Values:             RangeSet{{}},
+		ValuesExtensible:   s.values.extensible,
+		Sizes:              RangeSet{{Lower: new(big.Int)}},
+		SizesExtensible:    s.sizes.extensible,
+		StringsExtensible:  s.strings.extensible,
+		Alphabet:           RangeSet{{Lower: new(big.Int)}},
+		AlphabetExtensible: s.alphabet.extensible,
+	}
+	if s.values.constrained {
+		res.Values = s.values.set
+	}
+	if s.sizes.constrained {
+		res.Sizes = s.sizes.set
+	}
+	if s.strings.constrained {
+		res.Strings = append([]string{}, s.strings.set...)
+	}
+	if s.alphabet.constrained {
+		res.Alphabet = s.alphabet.set
+	}
+	if s.patterns.constrained && len(s.patterns.set) > 0 {
+		res.Patterns = slices.Clone(s.patterns.set)
+	}
+	return res
+}
+
+// constraintEvaluator evaluates constraints to sets of permitted values.
+type constraintEvaluator struct {
+	// perVisible restricts evaluation to PER-visible constraints, see X.691, section 10.3.
+	perVisible bool
+	// characters is set when constraint of permitted alphabet is evaluated, which values are characters,
+	// i.e. code points of single characters of strings.
+	characters bool
+	// included are types included by contained subtype constraints being evaluated, used to detect cycles.
+	included []string
+}
+
+// typeConstraint evaluates constraints of type t, including constraints of referenced types.
+func (e *constraintEvaluator) typeConstraint(ctx *moduleContext, t Type) constraintSet {
+	switch tt := t.(type) {
+	case TaggedType:
+		return e.typeConstraint(ctx, tt.Type)
+	case NamedType:
+		return e.typeConstraint(ctx, tt.Type)
+	case ConstraintedType:
+		return e.typeConstraint(ctx, tt.Type).apply(e.constraint(ctx, tt.Constraint))
+	case TypeReference:
+		assignment, assignmentCtx, err := ctx.lookupTypeAssignment(tt)
+		if err != nil {
+			ctx.appendError(err)
+			return constraintSet{inexact: true}
+		}
+		if assignment == nil {
+			// useful types are time types, which are not constrained
+			return constraintSet{}
+		}
+		name := string(assignmentCtx.moduleName) + "." + tt.Name()
+		if slices.Contains(e.included, name) {
+			ctx.appendError(fmt.Errorf("type %v: reference cycle %v", tt, strings.Join(append(e.included, name), " -> ")))
+			return constraintSet{inexact: true}
+		}
+		e.included = append(e.included, name)
+		defer func() { e.included = e.included[:len(e.included)-1] }()
+		return e.typeConstraint(assignmentCtx, assignment.Type)
+	default:
+		return constraintSet{}
+	}
+}
+
+// constraint evaluates root of constraint c. General constraints are not evaluated.
+func (e *constraintEvaluator) constraint(ctx *moduleContext, c Constraint) constraintSet {
+	spec, ok := c.ConstraintSpec.(SubtypeConstraint)
+	if !ok || len(spec) == 0 {
+		return constraintSet{inexact: true}
+	}
+	res := e.elements(ctx, spec[0])
+	if spec.Extensible() {
+		res = res.extend()
+	}
+	return res
+}
+
+// elements evaluates constraint elements.
+func (e *constraintEvaluator) elements(ctx *moduleContext, elements Elements) constraintSet {
+	switch el := elements.(type) {
+	case Unions:
+		if len(el) == 0 {
+			return constraintSet{inexact: true}
+		}
+		res := e.intersections(ctx, el[0])
+		for _, intersections := range el[1:] {
+			res = res.union(e.intersections(ctx, intersections))
+		}
+		return res
+	case Exclusions:
+		return e.exclude(ctx, constraintSet{}, el.Elements)
+	case SingleValue:
+		return e.singleValue(ctx, el.Value)
+	case ValueRange:
+		return e.valueRange(ctx, el)
+	case SizeConstraint:
+		if e.characters {
+			return constraintSet{inexact: true}
+		}
+		inner := e.constraint(ctx, el.Constraint)
+		sizes := inner.values
+		if sizes.constrained {
+			sizes.set = sizes.set.Intersect(RangeSet{{Lower: new(big.Int)}})
+		}
+		return constraintSet{sizes: sizes, inexact: inner.inexact || inner.sizes.constrained || inner.strings.constrained}
+	case PermittedAlphabet:
+		characters := *e
+		characters.characters = true
+		inner := characters.constraint(ctx, el.Constraint)
+		alphabet := inner.values
+		if alphabet.constrained {
+			alphabet.set = alphabet.set.Intersect(RangeSet{{Lower: new(big.Int)}})
+		}
+		return constraintSet{alphabet: alphabet, inexact: inner.inexact || inner.constrainedDimensions() > 1}
+	case PatternConstraint:
+		return e.pattern(ctx, el.Value)
+	case TypeConstraint:
+		if e.characters {
+			// permitted alphabet of included type
+			types := *e
+			types.characters = false
+			included := types.typeConstraint(ctx, el.Type)
+			return constraintSet{values: included.alphabet, inexact: included.inexact}
+		}
+		return e.typeConstraint(ctx, el.Type)
+	default:
+		return constraintSet{inexact: true}
+	}
+}
+
+func (e *constraintEvaluator) intersections(ctx *moduleContext, intersections Intersections) constraintSet {
+	var res constraintSet
+	for _, elements := range intersections {
+		set := e.elements(ctx, elements.Elements)
+		if elements.Exclusions.Elements != nil {
+			set = e.exclude(ctx, set, elements.Exclusions.Elements)
+		}
+		res = res.intersect(set)
+	}
+	return res
+}
+
+// exclude returns values of s which are not permitted by excluded elements.
+// Exclusions are not PER-visible, and are evaluated only if they restrict values of a single kind,
+// as values of other kinds are not excluded otherwise.
+func (e *constraintEvaluator) exclude(ctx *moduleContext, s constraintSet, excluded Elements) constraintSet {
+	if e.perVisible {
+		return s
+	}
+	x := e.elements(ctx, excluded)
+	switch {
+	case x.inexact || x.constrainedDimensions() != 1:
+	case x.values.constrained:
+		s.values = s.values.except(x.values, RangeSet{{}})
+		return s
+	case x.sizes.constrained:
+		s.sizes = s.sizes.except(x.sizes, RangeSet{{Lower: new(big.Int)}})
+		return s
+	case x.alphabet.constrained:
+		s.alphabet = s.alphabet.except(x.alphabet, RangeSet{{Lower: new(big.Int)}})
+		return s
+	case x.strings.constrained && s.strings.constrained:
+		s.strings = s.strings.except(x.strings, nil)
+		return s
+	}
+	// strings not matching patterns, and strings other than excluded ones, can not be described
+	s.inexact = true
+	return s
+}
+
+// singleValue evaluates single value constraint, which restricts INTEGER values or values of strings.
+// Strings in permitted alphabet constraints are sets of characters.
+func (e *constraintEvaluator) singleValue(ctx *moduleContext, v Value) constraintSet {
+	resolved, _, err := ctx.lookupValue(v)
+	if err != nil {
+		ctx.appendError(fmt.Errorf("constraint: %w", err))
+		return constraintSet{inexact: true}
+	}
+	switch value := valueOfType(CharacterStringType{}, resolved).(type) {
+	case Number, BigNumber:
+		if e.characters {
+			return constraintSet{inexact: true}
+		}
+		n, _ := integerValue(value)
+		return constraintSet{values: constraintDimension[RangeSet]{set: RangeSet{{Lower: n, Upper: n}}, constrained: true}}
+	case CharacterStringValue:
+		str, ok := value.StringValue()
+		switch {
+		case !ok:
+			return constraintSet{inexact: true}
+		case e.characters:
+			var characters []Range
+			for _, r := range str {
+				characters = append(characters, NewRange(int64(r), int64(r)))
+			}
+			return constraintSet{values: constraintDimension[RangeSet]{set: NewRangeSet(characters...), constrained: true}}
+		case e.perVisible:
+			return constraintSet{}
+		}
+		return constraintSet{strings: constraintDimension[stringSet]{set: newStringSet(str), constrained: true}}
+	default:
+		return constraintSet{inexact: true}
+	}
+}
+
+// pattern evaluates PATTERN constraint, which is not PER-visible.
+func (e *constraintEvaluator) pattern(ctx *moduleContext, v Value) constraintSet {
+	if e.perVisible {
+		return constraintSet{}
+	}
+	resolved, _, err := ctx.lookupValue(v)
+	if err != nil {
+		ctx.appendError(fmt.Errorf("constraint: %w", err))
+		return constraintSet{inexact: true}
+	}
+	str, ok := resolved.(CharacterStringValue)
+	if !ok {
+		return constraintSet{inexact: true}
+	}
+	pattern, ok := str.StringValue()
+	if !ok {
+		return constraintSet{inexact: true}
+	}
+	return constraintSet{patterns: constraintDimension[patternSet]{set: patternSet{pattern}, constrained: true}}
+}
+
+// valueRange evaluates range of INTEGER values, or of characters in permitted alphabet constraints.
+// Open endpoints are converted to closed ones.
+func (e *constraintEvaluator) valueRange(ctx *moduleContext, r ValueRange) constraintSet {
+	var res Range
+	if !r.LowerEndpoint.IsUnspecified() {
+		n, ok := e.endpoint(ctx, r.LowerEndpoint.Value)
+		if !ok {
+			return constraintSet{inexact: true}
+		}
+		if r.LowerEndpoint.IsOpen {
+			n = successor(n)
+		}
+		res.Lower = n
+	}
+	if !r.UpperEndpoint.IsUnspecified() {
+		n, ok := e.endpoint(ctx, r.UpperEndpoint.Value)
+		if !ok {
+			return constraintSet{inexact: true}
+		}
+		if r.UpperEndpoint.IsOpen {
+			n = predecessor(n)
+		}
+		res.Upper = n
+	}
+	return constraintSet{values: constraintDimension[RangeSet]{set: NewRangeSet(res), constrained: true}}
+}
+
+// endpoint resolves endpoint of value range, which is a number, or a string of single character
+// in permitted alphabet constraints.
+func (e *constraintEvaluator) endpoint(ctx *moduleContext, v Value) (*big.Int, bool) {
+	if !e.characters {
+		return ctx.constraintNumber(v)
+	}
+	resolved, _, err := ctx.lookupValue(v)
+	if err != nil {
+		ctx.appendError(fmt.Errorf("constraint: %w", err))
+		return nil, false
+	}
+	str, ok := valueOfType(CharacterStringType{}, resolved).(CharacterStringValue)
+	if !ok {
+		return nil, false
+	}
+	value, ok := str.StringValue()
+	if !ok || utf8.RuneCountInString(value) != 1 {
+		return nil, false
+	}
+	r, _ := utf8.DecodeRuneInString(value)
+	return big.NewInt(int64(r)), true
+}
+
+// constraintNumber resolves value used in constraint of INTEGER type. Returns false if value is not a number,
+// e.g. if constraint is applied to a string type.
+func (ctx *moduleContext) constraintNumber(v Value) (*big.Int, bool) {
+	resolved, _, err := ctx.lookupValue(v)
+	if err != nil {
+		ctx.appendError(fmt.Errorf("constraint: %w", err))
+		return nil, false
+	}
+	return integerValue(resolved)
+}
+
+// successor returns n+1.
+func successor(n *big.Int) *big.Int {
+	return new(big.Int).Add(n, big.NewInt(1))
+}
+
+// predecessor returns n-1.
+func predecessor(n *big.Int) *big.Int {
+	return new(big.Int).Sub(n, big.NewInt(1))
+}
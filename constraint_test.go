@@ -0,0 +1,218 @@
+package asn1go
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRangeSet(t *testing.T) {
+	testCases := []struct {
+		name     string
+		set      RangeSet
+		expected string
+	}{
+		{
+			name:     "normalized",
+			set:      NewRangeSet(NewRange(10, 20), NewRange(0, 5), NewRange(6, 8)),
+			expected: "(0..8 | 10..20)",
+		},
+		{
+			name:     "empty ranges",
+			set:      NewRangeSet(NewRange(5, 4)),
+			expected: "()",
+		},
+		{
+			name:     "union",
+			set:      NewRangeSet(Range{Upper: big.NewInt(0)}).Union(NewRangeSet(Range{Lower: big.NewInt(1)})),
+			expected: "(MIN..MAX)",
+		},
+		{
+			name:     "intersection",
+			set:      NewRangeSet(Range{Lower: big.NewInt(0)}).Intersect(NewRangeSet(NewRange(-5, 3), NewRange(7, 7))),
+			expected: "(0..3 | 7)",
+		},
+		{
+			name:     "exclusion",
+			set:      NewRangeSet(Range{}).Except(NewRangeSet(NewRange(0, 9))),
+			expected: "(MIN..-1 | 10..MAX)",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.set.String(); got != tc.expected {
+				t.Errorf("Expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+// bigIntComparer compares big.Int values by their value, as cmp can not compare their unexported fields.
+var bigIntComparer = cmp.Comparer(func(a, b *big.Int) bool {
+	return a == b || a != nil && b != nil && a.Cmp(b) == 0
+})
+
+// bigInt returns big.Int value parsed from its decimal representation.
+func bigInt(t *testing.T, s string) *big.Int {
+	t.Helper()
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		t.Fatalf("Invalid number %v", s)
+	}
+	return n
+}
+
+func TestEvaluateConstraint(t *testing.T) {
+	m := parseModule(t, `
+	TestSpec DEFINITIONS ::= BEGIN
+		Small ::= INTEGER (0..7)
+		Open ::= INTEGER (0<..<10)
+		Values ::= INTEGER (1..3 | 10 | 5..MAX)
+		Excluded ::= INTEGER (0..10 EXCEPT (3..4 | 7))
+		AllExcept ::= INTEGER (ALL EXCEPT 0)
+		Included ::= INTEGER (INCLUDES Small ^ 5..10)
+		Serial ::= Small (2..MAX, ...)
+		Extended ::= INTEGER (0..7, ..., 8)
+		Name ::= IA5String (SIZE (1..8, ...) ^ ("a" | "bc" | "def") EXCEPT "a")
+		Names ::= SEQUENCE (SIZE (MIN..4)) OF Name
+		Inexact ::= INTEGER (0..10 EXCEPT (5 | SIZE (1)))
+		Digits ::= IA5String (FROM ("0".."9" | "ab") ^ SIZE (1..4))
+		Hex ::= Digits (FROM (("0".."9" | "a".."f") EXCEPT "5"))
+		Word ::= UTF8String (PATTERN "[a-z]+" | PATTERN "[0-9]+")
+		Huge ::= INTEGER (-18446744073709551616<..18446744073709551615 EXCEPT -9223372036854775808)
+	END
+	`)
+	testCases := []struct {
+		typeName string
+		expected EffectiveConstraint
+		// perVisible is expected PER-visible constraint, if it differs
+		perVisible *EffectiveConstraint
+	}{
+		{
+			typeName: "Small",
+			expected: EffectiveConstraint{Values: RangeSet{NewRange(0, 7)}},
+		},
+		{
+			typeName: "Open",
+			expected: EffectiveConstraint{Values: RangeSet{NewRange(1, 9)}},
+		},
+		{
+			typeName: "Values",
+			expected: EffectiveConstraint{Values: RangeSet{NewRange(1, 3), {Lower: big.NewInt(5)}}},
+		},
+		{
+			typeName: "Excluded",
+			expected: EffectiveConstraint{Values: RangeSet{
+				NewRange(0, 2),
+				NewRange(5, 6),
+				NewRange(8, 10),
+			}},
+			perVisible: &EffectiveConstraint{Values: RangeSet{NewRange(0, 10)}},
+		},
+		{
+			typeName:   "AllExcept",
+			expected:   EffectiveConstraint{Values: RangeSet{{Upper: big.NewInt(-1)}, {Lower: big.NewInt(1)}}},
+			perVisible: &EffectiveConstraint{},
+		},
+		{
+			typeName: "Included",
+			expected: EffectiveConstraint{Values: RangeSet{NewRange(5, 7)}},
+		},
+		{
+			typeName: "Serial",
+			expected: EffectiveConstraint{Values: RangeSet{NewRange(2, 7)}, ValuesExtensible: true},
+		},
+		{
+			typeName: "Extended",
+			expected: EffectiveConstraint{Values: RangeSet{NewRange(0, 7)}, ValuesExtensible: true},
+		},
+		{
+			typeName:   "Name",
+			expected:   EffectiveConstraint{Sizes: RangeSet{NewRange(1, 8)}, SizesExtensible: true, Strings: []string{"bc", "def"}},
+			perVisible: &EffectiveConstraint{Sizes: RangeSet{NewRange(1, 8)}, SizesExtensible: true},
+		},
+		{
+			typeName: "Names",
+			expected: EffectiveConstraint{Sizes: RangeSet{NewRange(0, 4)}},
+		},
+		{
+			typeName:   "Inexact",
+			expected:   EffectiveConstraint{Values: RangeSet{NewRange(0, 10)}},
+			perVisible: &EffectiveConstraint{Values: RangeSet{NewRange(0, 10)}},
+		},
+		{
+			typeName: "Hex",
+			expected: EffectiveConstraint{
+				Sizes:    RangeSet{NewRange(1, 4)},
+				Alphabet: RangeSet{NewRange('0', '4'), NewRange('6', '9'), NewRange('a', 'b')},
+			},
+			perVisible: &EffectiveConstraint{
+				Sizes:    RangeSet{NewRange(1, 4)},
+				Alphabet: RangeSet{NewRange('0', '9'), NewRange('a', 'b')},
+			},
+		},
+		{
+			typeName:   "Word",
+			expected:   EffectiveConstraint{Patterns: []string{"([a-z]+)|([0-9]+)"}},
+			perVisible: &EffectiveConstraint{},
+		},
+		{
+			typeName: "Huge",
+			expected: EffectiveConstraint{Values: RangeSet{
+				{Lower: bigInt(t, "-18446744073709551615"), Upper: bigInt(t, "-9223372036854775809")},
+				{Lower: bigInt(t, "-9223372036854775807"), Upper: bigInt(t, "18446744073709551615")},
+			}},
+			perVisible: &EffectiveConstraint{Values: RangeSet{{Lower: bigInt(t, "-18446744073709551615"), Upper: bigInt(t, "18446744073709551615")}}},
+		},
+	}
+	// sets which are not specified by test cases are not constrained
+	unconstrained := func(c EffectiveConstraint) EffectiveConstraint {
+		if c.Values == nil {
+			c.Values = RangeSet{{}}
+		}
+		if c.Sizes == nil {
+			c.Sizes = RangeSet{{Lower: big.NewInt(0)}}
+		}
+		if c.Alphabet == nil {
+			c.Alphabet = RangeSet{{Lower: big.NewInt(0)}}
+		}
+		return c
+	}
+	for _, tc := range testCases {
+		t.Run(tc.typeName, func(t *testing.T) {
+			got, err := EvaluateConstraint(m, TypeReference(tc.typeName))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(unconstrained(tc.expected), got, bigIntComparer); diff != "" {
+				t.Errorf("Unexpected constraint (-want +got):\n%v", diff)
+			}
+			expected := tc.expected
+			if tc.perVisible != nil {
+				expected = *tc.perVisible
+			}
+			got, err = EvaluateConstraintWithParams(m, TypeReference(tc.typeName), ConstraintParams{PERVisible: true})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(unconstrained(expected), got, bigIntComparer); diff != "" {
+				t.Errorf("Unexpected PER-visible constraint (-want +got):\n%v", diff)
+			}
+		})
+	}
+}
+
+func TestEvaluateConstraintErrors(t *testing.T) {
+	m := parseModule(t, `
+	TestSpec DEFINITIONS ::= BEGIN
+		Unknown ::= INTEGER (0..max-value)
+		Cycle ::= INTEGER (INCLUDES Cycle)
+	END
+	`)
+	for _, typeName := range []string{"Unknown", "Cycle"} {
+		if _, err := EvaluateConstraint(m, TypeReference(typeName)); err == nil {
+			t.Errorf("Expected error for %v", typeName)
+		}
+	}
+}
@@ -0,0 +1,848 @@
+package der
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// SyntaxError reports that encoding is malformed, or, in strict mode, is not valid DER.
+type SyntaxError struct {
+	Msg string
+}
+
+func (e SyntaxError) Error() string {
+	return "der: syntax error: " + e.Msg
+}
+
+// StructuralError reports that encoding does not match the type it is decoded into.
+type StructuralError struct {
+	Msg string
+}
+
+func (e StructuralError) Error() string {
+	return "der: structure error: " + e.Msg
+}
+
+// Unmarshaler is implemented by generated types, which decode themselves without reflection.
+type Unmarshaler interface {
+	// DecodeBER decodes the next element of d into the value.
+	// If tag is not zero, it is expected instead of the outermost tag of the encoding.
+	DecodeBER(d *Decoder, tag Tag) error
+}
+
+// UnmarshalBER decodes the first element of data into v, and returns remaining data.
+// It accepts any valid BER encoding.
+func UnmarshalBER(data []byte, v Unmarshaler) ([]byte, error) {
+	return unmarshal(NewDecoder(data), v)
+}
+
+// UnmarshalDER is same as UnmarshalBER, but rejects encodings which are not valid DER.
+func UnmarshalDER(data []byte, v Unmarshaler) ([]byte, error) {
+	d := NewDecoder(data)
+	d.Strict = true
+	return unmarshal(d, v)
+}
+
+func unmarshal(d *Decoder, v Unmarshaler) ([]byte, error) {
+	if err := v.DecodeBER(d, Tag{}); err != nil {
+		return nil, err
+	}
+	return d.Rest(), nil
+}
+
+// maxDepth limits nesting of indefinite length encodings, which are parsed recursively.
+const maxDepth = 64
+
+// Decoder reads BER encoded elements one by one.
+//
+// By default, Decoder accepts any valid BER encoding, including indefinite lengths and constructed strings.
+// With Strict set, it also rejects encodings which are not valid DER, e.g. non-minimal lengths,
+// unsorted SET OF elements, or encoded DEFAULT values. Decoders of constructed values inherit the mode.
+type Decoder struct {
+	// Strict enables restrictions of Distinguished Encoding Rules, see X.690, section 10.
+	Strict bool
+	data   []byte
+	// set holds tags of all elements if decoder reads components of SET.
+	set []Tag
+}
+
+// NewDecoder returns decoder reading elements from data.
+func NewDecoder(data []byte) *Decoder {
+	return &Decoder{data: data}
+}
+
+// Rest returns data which was not read yet.
+func (d *Decoder) Rest() []byte {
+	return d.data
+}
+
+// More returns true if there are elements left to read.
+func (d *Decoder) More() bool {
+	return len(d.data) > 0
+}
+
+// Peek returns true if the next element has one of the tags.
+func (d *Decoder) Peek(tags ...Tag) bool {
+	tag, _, _, err := parseIdentifier(d.data)
+	if err != nil {
+		return false
+	}
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// Contains returns true if one of the components of SET being decoded has one of the tags.
+// It can only be used with decoders returned by BeginSet.
+func (d *Decoder) Contains(tags ...Tag) bool {
+	for _, tag := range d.set {
+		for _, t := range tags {
+			if t == tag {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Unexpected returns error reporting that the next element does not match any alternative
+// or component of the type.
+func (d *Decoder) Unexpected(typeName string) error {
+	tag, _, _, err := parseIdentifier(d.data)
+	if err != nil {
+		return StructuralError{fmt.Sprintf("missing value of %v", typeName)}
+	}
+	return StructuralError{fmt.Sprintf("unexpected tag %v of %v", tag, typeName)}
+}
+
+// BeginConstructed reads the next constructed element with the tag, and returns decoder of its contents.
+func (d *Decoder) BeginConstructed(tag Tag) (*Decoder, error) {
+	h, contents, err := d.element(tag)
+	if err != nil {
+		return nil, err
+	}
+	if !h.constructed {
+		return nil, StructuralError{fmt.Sprintf("expected constructed encoding of %v", tag)}
+	}
+	return &Decoder{Strict: d.Strict, data: contents}, nil
+}
+
+// BeginSet is same as BeginConstructed, but also verifies that tags of SET components are distinct,
+// and, in strict mode, that components are sorted by their tags, see X.690, section 10.3.
+func (d *Decoder) BeginSet(tag Tag) (*Decoder, error) {
+	inner, err := d.BeginConstructed(tag)
+	if err != nil {
+		return nil, err
+	}
+	for data := inner.data; len(data) > 0; {
+		h, _, size, err := inner.parse(data, 0)
+		if err != nil {
+			return nil, err
+		}
+		for _, seen := range inner.set {
+			if seen == h.tag {
+				return nil, StructuralError{fmt.Sprintf("duplicate component with tag %v in SET", h.tag)}
+			}
+			if d.Strict && h.tag.less(seen) {
+				return nil, SyntaxError{fmt.Sprintf("SET components are not sorted, %v follows %v", h.tag, seen)}
+			}
+		}
+		inner.set = append(inner.set, h.tag)
+		data = data[size:]
+	}
+	return inner, nil
+}
+
+// BeginSetOf is same as BeginConstructed, but also verifies in strict mode that elements of SET OF
+// are sorted by their encodings, see X.690, section 11.6.
+func (d *Decoder) BeginSetOf(tag Tag) (*Decoder, error) {
+	inner, err := d.BeginConstructed(tag)
+	if err != nil || !d.Strict {
+		return inner, err
+	}
+	var prev []byte
+	for data := inner.data; len(data) > 0; {
+		_, _, size, err := inner.parse(data, 0)
+		if err != nil {
+			return nil, err
+		}
+		if prev != nil && bytes.Compare(prev, data[:size]) > 0 {
+			return nil, SyntaxError{"SET OF elements are not sorted"}
+		}
+		prev = data[:size]
+		data = data[size:]
+	}
+	return inner, nil
+}
+
+// End verifies that all elements of constructed value were read.
+func (d *Decoder) End() error {
+	if !d.More() {
+		return nil
+	}
+	tag, _, _, err := parseIdentifier(d.data)
+	if err != nil {
+		return err
+	}
+	return StructuralError{fmt.Sprintf("unexpected trailing element with tag %v", tag)}
+}
+
+// EndExtensible skips elements of constructed value which were not read, e.g. unknown extension additions.
+func (d *Decoder) EndExtensible() error {
+	for d.More() {
+		_, _, size, err := d.parse(d.data, 0)
+		if err != nil {
+			return err
+		}
+		d.data = d.data[size:]
+	}
+	return nil
+}
+
+// ReadBoolean reads BOOLEAN value.
+func (d *Decoder) ReadBoolean(tag Tag, v *bool) error {
+	contents, err := d.primitive(tag)
+	if err != nil {
+		return err
+	}
+	if len(contents) != 1 {
+		return SyntaxError{"invalid BOOLEAN length"}
+	}
+	if d.Strict && contents[0] != 0x00 && contents[0] != 0xff {
+		return SyntaxError{"BOOLEAN TRUE should be encoded as 0xff"}
+	}
+	*v = contents[0] != 0
+	return nil
+}
+
+// ReadInteger reads INTEGER value which fits into int64.
+func (d *Decoder) ReadInteger(tag Tag, v *int64) error {
+	contents, err := d.integer(tag)
+	if err != nil {
+		return err
+	}
+	if len(contents) > 8 {
+		return StructuralError{"INTEGER value does not fit into int64"}
+	}
+	var res int64
+	for _, b := range contents {
+		res = res<<8 | int64(b)
+	}
+	// sign extension
+	res <<= 64 - 8*uint(len(contents))
+	res >>= 64 - 8*uint(len(contents))
+	*v = res
+	return nil
+}
+
+// ReadBigInteger reads INTEGER value of any size.
+func (d *Decoder) ReadBigInteger(tag Tag, v **big.Int) error {
+	contents, err := d.integer(tag)
+	if err != nil {
+		return err
+	}
+	res := new(big.Int).SetBytes(contents)
+	if contents[0]&0x80 != 0 {
+		res.Sub(res, new(big.Int).Lsh(big.NewInt(1), 8*uint(len(contents))))
+	}
+	*v = res
+	return nil
+}
+
+// ReadEnumerated reads ENUMERATED value.
+func (d *Decoder) ReadEnumerated(tag Tag, v *asn1.Enumerated) error {
+	var res int64
+	if err := d.ReadInteger(tag, &res); err != nil {
+		return err
+	}
+	if int64(int(res)) != res {
+		return StructuralError{"ENUMERATED value does not fit into int"}
+	}
+	*v = asn1.Enumerated(res)
+	return nil
+}
+
+// integer reads contents of INTEGER or ENUMERATED value, with redundant leading octets removed.
+func (d *Decoder) integer(tag Tag) ([]byte, error) {
+	contents, err := d.primitive(tag)
+	if err != nil {
+		return nil, err
+	}
+	if len(contents) == 0 {
+		return nil, SyntaxError{"empty INTEGER"}
+	}
+	redundant := 0
+	for redundant+1 < len(contents) {
+		first, second := contents[redundant], contents[redundant+1]
+		if !(first == 0x00 && second&0x80 == 0) && !(first == 0xff && second&0x80 != 0) {
+			break
+		}
+		redundant++
+	}
+	if redundant > 0 && d.Strict {
+		return nil, SyntaxError{"INTEGER is not minimally encoded"}
+	}
+	return contents[redundant:], nil
+}
+
+// ReadReal reads REAL value, see X.690, section 8.5.
+func (d *Decoder) ReadReal(tag Tag, v *float64) error {
+	contents, err := d.primitive(tag)
+	if err != nil {
+		return err
+	}
+	if len(contents) == 0 {
+		*v = 0
+		return nil
+	}
+	first := contents[0]
+	switch {
+	case first&0x80 != 0:
+		return d.binaryReal(contents, v)
+	case first&0x40 != 0:
+		switch first {
+		case 0x40:
+			*v = math.Inf(1)
+		case 0x41:
+			*v = math.Inf(-1)
+		case 0x42:
+			*v = math.NaN()
+		case 0x43:
+			*v = math.Copysign(0, -1)
+		default:
+			return SyntaxError{fmt.Sprintf("invalid special REAL value 0x%x", first)}
+		}
+		if len(contents) != 1 {
+			return SyntaxError{"invalid length of special REAL value"}
+		}
+		return nil
+	default:
+		// decimal encoding, ISO 6093 number representation
+		if d.Strict && first&0x3f != 3 {
+			return SyntaxError{"decimal REAL should be encoded in NR3 form"}
+		}
+		s := strings.ReplaceAll(strings.TrimSpace(string(contents[1:])), ",", ".")
+		res, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return SyntaxError{fmt.Sprintf("invalid decimal REAL %q", s)}
+		}
+		*v = res
+		return nil
+	}
+}
+
+func (d *Decoder) binaryReal(contents []byte, v *float64) error {
+	first := contents[0]
+	var base int
+	switch (first >> 4) & 0x03 {
+	case 0:
+		base = 1
+	case 1:
+		base = 3
+	case 2:
+		base = 4
+	default:
+		return SyntaxError{"reserved REAL base"}
+	}
+	scale := int((first >> 2) & 0x03)
+	if d.Strict && (base != 1 || scale != 0) {
+		return SyntaxError{"REAL should be encoded with base 2 and zero scale factor"}
+	}
+	rest := contents[1:]
+	var exponentLength int
+	switch first & 0x03 {
+	case 3:
+		if len(rest) == 0 {
+			return SyntaxError{"truncated REAL"}
+		}
+		exponentLength = int(rest[0])
+		rest = rest[1:]
+	default:
+		exponentLength = int(first&0x03) + 1
+	}
+	if exponentLength == 0 || len(rest) < exponentLength || exponentLength > 8 {
+		return SyntaxError{"invalid REAL exponent"}
+	}
+	var exponent int64
+	for _, b := range rest[:exponentLength] {
+		exponent = exponent<<8 | int64(b)
+	}
+	exponent <<= 64 - 8*uint(exponentLength)
+	exponent >>= 64 - 8*uint(exponentLength)
+	mantissa := new(big.Int).SetBytes(rest[exponentLength:])
+	if d.Strict && mantissa.Sign() != 0 && mantissa.Bit(0) == 0 {
+		return SyntaxError{"REAL mantissa should be odd"}
+	}
+	mantissa.Lsh(mantissa, uint(scale))
+	res, _ := new(big.Float).SetInt(mantissa).Float64()
+	res = math.Ldexp(res, int(exponent)*base)
+	if first&0x40 != 0 {
+		res = -res
+	}
+	*v = res
+	return nil
+}
+
+// ReadOctetString reads OCTET STRING value.
+func (d *Decoder) ReadOctetString(tag Tag, v *[]byte) error {
+	contents, err := d.octets(tag, 0)
+	if err != nil {
+		return err
+	}
+	*v = append([]byte{}, contents...)
+	return nil
+}
+
+// ReadContaining reads OCTET STRING value which contains encoding of value read by fn,
+// see X.682, section 11 (contents constraint). Decoder passed to fn inherits the mode,
+// and the value must take all contents.
+func (d *Decoder) ReadContaining(tag Tag, fn func(d *Decoder) error) error {
+	contents, err := d.octets(tag, 0)
+	if err != nil {
+		return err
+	}
+	inner := &Decoder{Strict: d.Strict, data: contents}
+	if err := fn(inner); err != nil {
+		return err
+	}
+	if inner.More() {
+		return StructuralError{"trailing data after contained value"}
+	}
+	return nil
+}
+
+// ReadString reads value of restricted character string type which is encoded as is,
+// e.g. UTF8String or IA5String.
+func (d *Decoder) ReadString(tag Tag, v *string) error {
+	contents, err := d.octets(tag, 0)
+	if err != nil {
+		return err
+	}
+	*v = string(contents)
+	return nil
+}
+
+// ReadBMPString reads BMPString value, which is encoded as UCS-2.
+func (d *Decoder) ReadBMPString(tag Tag, v *string) error {
+	contents, err := d.octets(tag, 0)
+	if err != nil {
+		return err
+	}
+	if len(contents)%2 != 0 {
+		return SyntaxError{"odd length of BMPString"}
+	}
+	chars := make([]uint16, 0, len(contents)/2)
+	for i := 0; i < len(contents); i += 2 {
+		chars = append(chars, uint16(contents[i])<<8|uint16(contents[i+1]))
+	}
+	*v = string(utf16.Decode(chars))
+	return nil
+}
+
+// ReadUniversalString reads UniversalString value, which is encoded as UCS-4.
+func (d *Decoder) ReadUniversalString(tag Tag, v *string) error {
+	contents, err := d.octets(tag, 0)
+	if err != nil {
+		return err
+	}
+	if len(contents)%4 != 0 {
+		return SyntaxError{"invalid length of UniversalString"}
+	}
+	var sb strings.Builder
+	for i := 0; i < len(contents); i += 4 {
+		sb.WriteRune(rune(contents[i])<<24 | rune(contents[i+1])<<16 | rune(contents[i+2])<<8 | rune(contents[i+3]))
+	}
+	*v = sb.String()
+	return nil
+}
+
+// ReadBitString reads BIT STRING value.
+func (d *Decoder) ReadBitString(tag Tag, v *asn1.BitString) error {
+	h, contents, err := d.element(tag)
+	if err != nil {
+		return err
+	}
+	if h.constructed {
+		if d.Strict {
+			return SyntaxError{"constructed encoding of BIT STRING"}
+		}
+		if contents, err = d.bitStringSegments(contents, 0); err != nil {
+			return err
+		}
+	}
+	if len(contents) == 0 || contents[0] > 7 || (len(contents) == 1 && contents[0] != 0) {
+		return SyntaxError{"invalid BIT STRING"}
+	}
+	unused := contents[0]
+	bits := append([]byte{}, contents[1:]...)
+	if len(bits) > 0 && bits[len(bits)-1]&(1<<unused-1) != 0 {
+		if d.Strict {
+			return SyntaxError{"unused bits of BIT STRING should be zero"}
+		}
+		bits[len(bits)-1] &= 0xff << unused
+	}
+	*v = asn1.BitString{Bytes: bits, BitLength: 8*len(bits) - int(unused)}
+	return nil
+}
+
+// ReadNamedBitString reads value of BIT STRING type with named bits.
+// In strict mode, trailing zero bits are rejected, see X.690, section 11.2.2.
+func (d *Decoder) ReadNamedBitString(tag Tag, v *asn1.BitString) error {
+	var res asn1.BitString
+	if err := d.ReadBitString(tag, &res); err != nil {
+		return err
+	}
+	if d.Strict && res.BitLength > 0 && res.At(res.BitLength-1) == 0 {
+		return SyntaxError{"trailing zero bits of BIT STRING with named bits"}
+	}
+	*v = res
+	return nil
+}
+
+// bitStringSegments concatenates segments of BIT STRING in constructed form.
+// Returns contents in primitive form, i.e. prefixed with number of unused bits.
+func (d *Decoder) bitStringSegments(data []byte, depth int) ([]byte, error) {
+	res := []byte{0}
+	for len(data) > 0 {
+		if res[0] != 0 {
+			return nil, SyntaxError{"only last segment of BIT STRING can have unused bits"}
+		}
+		h, contents, size, err := d.parse(data, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		if h.tag != TagBitString {
+			return nil, SyntaxError{fmt.Sprintf("unexpected tag %v of BIT STRING segment", h.tag)}
+		}
+		if h.constructed {
+			if contents, err = d.bitStringSegments(contents, depth+1); err != nil {
+				return nil, err
+			}
+		}
+		if len(contents) == 0 || contents[0] > 7 {
+			return nil, SyntaxError{"invalid BIT STRING segment"}
+		}
+		res[0] = contents[0]
+		res = append(res, contents[1:]...)
+		data = data[size:]
+	}
+	return res, nil
+}
+
+// ReadNull reads NULL value.
+func (d *Decoder) ReadNull(tag Tag) error {
+	contents, err := d.primitive(tag)
+	if err != nil {
+		return err
+	}
+	if len(contents) != 0 {
+		return SyntaxError{"NULL with non-empty contents"}
+	}
+	return nil
+}
+
+// ReadObjectIdentifier reads OBJECT IDENTIFIER value.
+func (d *Decoder) ReadObjectIdentifier(tag Tag, v *asn1.ObjectIdentifier) error {
+	contents, err := d.primitive(tag)
+	if err != nil {
+		return err
+	}
+	if len(contents) == 0 {
+		return SyntaxError{"empty OBJECT IDENTIFIER"}
+	}
+	var res asn1.ObjectIdentifier
+	for len(contents) > 0 {
+		if d.Strict && contents[0] == 0x80 {
+			return SyntaxError{"OBJECT IDENTIFIER arc is not minimally encoded"}
+		}
+		var arc uint64
+		i := 0
+		for {
+			if i >= len(contents) {
+				return SyntaxError{"truncated OBJECT IDENTIFIER"}
+			}
+			if arc > math.MaxInt32 {
+				return StructuralError{"OBJECT IDENTIFIER arc is too large"}
+			}
+			b := contents[i]
+			arc = arc<<7 | uint64(b&0x7f)
+			i++
+			if b&0x80 == 0 {
+				break
+			}
+		}
+		contents = contents[i:]
+		if res == nil {
+			switch {
+			case arc < 40:
+				res = append(res, 0, int(arc))
+			case arc < 80:
+				res = append(res, 1, int(arc-40))
+			default:
+				res = append(res, 2, int(arc-80))
+			}
+		} else {
+			res = append(res, int(arc))
+		}
+	}
+	*v = res
+	return nil
+}
+
+// ReadUTCTime reads UTCTime value. Years 50 to 99 are interpreted as 1950 to 1999.
+// In strict mode, time should be in UTC and include seconds, see X.690, section 11.8.
+func (d *Decoder) ReadUTCTime(tag Tag, v *time.Time) error {
+	var s string
+	if err := d.ReadString(tag, &s); err != nil {
+		return err
+	}
+	layouts := []string{"060102150405Z0700", "0601021504Z0700"}
+	if d.Strict {
+		layouts = []string{"060102150405Z"}
+	}
+	res, err := parseTime(s, layouts)
+	if err != nil {
+		return err
+	}
+	if res.Year() >= 2050 {
+		res = res.AddDate(-100, 0, 0)
+	}
+	*v = res
+	return nil
+}
+
+// ReadGeneralizedTime reads GeneralizedTime value. Local time without time zone is interpreted as UTC.
+// In strict mode, time should be in UTC and include seconds, and fraction of seconds should not
+// have trailing zeroes, see X.690, section 11.7.
+func (d *Decoder) ReadGeneralizedTime(tag Tag, v *time.Time) error {
+	var s string
+	if err := d.ReadString(tag, &s); err != nil {
+		return err
+	}
+	layouts := []string{
+		"20060102150405Z0700", "200601021504Z0700", "2006010215Z0700",
+		"20060102150405", "200601021504", "2006010215",
+	}
+	if d.Strict {
+		if fraction, ok := strings.CutPrefix(s[min(len(s), 14):], "."); ok || strings.Contains(s, ",") {
+			if !ok || strings.HasSuffix(fraction, "0Z") || fraction == "Z" {
+				return SyntaxError{fmt.Sprintf("invalid fraction of seconds in GeneralizedTime %q", s)}
+			}
+		}
+		layouts = []string{"20060102150405Z"}
+	}
+	res, err := parseTime(s, layouts)
+	if err != nil {
+		return err
+	}
+	*v = res
+	return nil
+}
+
+func parseTime(s string, layouts []string) (time.Time, error) {
+	for _, layout := range layouts {
+		if res, err := time.Parse(layout, s); err == nil {
+			return res, nil
+		}
+	}
+	return time.Time{}, SyntaxError{fmt.Sprintf("invalid time %q", s)}
+}
+
+// ReadRawValue reads the next element with any tag.
+func (d *Decoder) ReadRawValue(v *asn1.RawValue) error {
+	h, contents, size, err := d.parse(d.data, 0)
+	if err != nil {
+		return err
+	}
+	*v = asn1.RawValue{
+		Class:      int(h.tag.Class),
+		Tag:        h.tag.Number,
+		IsCompound: h.constructed,
+		Bytes:      contents,
+		FullBytes:  d.data[:size],
+	}
+	d.data = d.data[size:]
+	return nil
+}
+
+// ReadAny reads value of open type, e.g. ANY, as asn1.RawValue.
+func (d *Decoder) ReadAny(v *any) error {
+	var raw asn1.RawValue
+	if err := d.ReadRawValue(&raw); err != nil {
+		return err
+	}
+	*v = raw
+	return nil
+}
+
+// header is a parsed identifier and length of an element.
+type header struct {
+	tag         Tag
+	constructed bool
+}
+
+// element reads the next element, which should have the tag.
+func (d *Decoder) element(tag Tag) (header, []byte, error) {
+	h, contents, size, err := d.parse(d.data, 0)
+	if err != nil {
+		return header{}, nil, err
+	}
+	if h.tag != tag {
+		return header{}, nil, StructuralError{fmt.Sprintf("expected tag %v, got %v", tag, h.tag)}
+	}
+	d.data = d.data[size:]
+	return h, contents, nil
+}
+
+// primitive reads contents of the next element, which should have primitive encoding.
+func (d *Decoder) primitive(tag Tag) ([]byte, error) {
+	h, contents, err := d.element(tag)
+	if err != nil {
+		return nil, err
+	}
+	if h.constructed {
+		return nil, SyntaxError{fmt.Sprintf("expected primitive encoding of %v", tag)}
+	}
+	return contents, nil
+}
+
+// octets reads contents of OCTET STRING or restricted character string. Segments of constructed encoding
+// are concatenated, see X.690, section 8.7.3.
+func (d *Decoder) octets(tag Tag, depth int) ([]byte, error) {
+	h, contents, err := d.element(tag)
+	if err != nil {
+		return nil, err
+	}
+	if !h.constructed {
+		return contents, nil
+	}
+	if d.Strict {
+		return nil, SyntaxError{fmt.Sprintf("constructed encoding of string %v", tag)}
+	}
+	if depth >= maxDepth {
+		return nil, SyntaxError{"string segments are nested too deep"}
+	}
+	var res []byte
+	segments := &Decoder{data: contents}
+	for segments.More() {
+		segment, err := segments.octets(TagOctetString, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, segment...)
+	}
+	return res, nil
+}
+
+// parse parses the first element of data, and returns its header, contents and size of whole encoding.
+// Contents of indefinite length encoding do not include end-of-contents octets.
+func (d *Decoder) parse(data []byte, depth int) (h header, contents []byte, size int, err error) {
+	tag, constructed, offset, err := parseIdentifier(data)
+	if err != nil {
+		return header{}, nil, 0, err
+	}
+	h = header{tag: tag, constructed: constructed}
+	if offset >= len(data) {
+		return header{}, nil, 0, SyntaxError{"truncated length"}
+	}
+	first := data[offset]
+	offset++
+	switch {
+	case first < 0x80:
+		size = offset + int(first)
+	case first == 0x80:
+		if d.Strict {
+			return header{}, nil, 0, SyntaxError{"indefinite length"}
+		}
+		if !constructed {
+			return header{}, nil, 0, SyntaxError{"indefinite length of primitive encoding"}
+		}
+		if depth >= maxDepth {
+			return header{}, nil, 0, SyntaxError{"indefinite length encodings are nested too deep"}
+		}
+		end := offset
+		for {
+			if end+2 > len(data) {
+				return header{}, nil, 0, SyntaxError{"missing end-of-contents octets"}
+			}
+			if data[end] == 0 && data[end+1] == 0 {
+				return h, data[offset:end], end + 2, nil
+			}
+			_, _, childSize, err := d.parse(data[end:], depth+1)
+			if err != nil {
+				return header{}, nil, 0, err
+			}
+			end += childSize
+		}
+	case first == 0xff:
+		return header{}, nil, 0, SyntaxError{"reserved length octet"}
+	default:
+		n := int(first & 0x7f)
+		if offset+n > len(data) {
+			return header{}, nil, 0, SyntaxError{"truncated length"}
+		}
+		length := 0
+		for _, b := range data[offset : offset+n] {
+			if length > (math.MaxInt32 >> 8) {
+				return header{}, nil, 0, SyntaxError{"length is too large"}
+			}
+			length = length<<8 | int(b)
+		}
+		if d.Strict && (length < 0x80 || data[offset] == 0) {
+			return header{}, nil, 0, SyntaxError{"length is not minimally encoded"}
+		}
+		offset += n
+		size = offset + length
+	}
+	if size > len(data) {
+		return header{}, nil, 0, SyntaxError{"truncated contents"}
+	}
+	return h, data[offset:size], size, nil
+}
+
+// parseIdentifier parses identifier octets, see X.690, section 8.1.2.
+// Returns the tag, constructed flag, and size of identifier octets.
+func parseIdentifier(data []byte) (tag Tag, constructed bool, size int, err error) {
+	if len(data) == 0 {
+		return Tag{}, false, 0, SyntaxError{"unexpected end of data"}
+	}
+	tag.Class = Class(data[0] >> 6)
+	constructed = data[0]&0x20 != 0
+	tag.Number = int(data[0] & 0x1f)
+	size = 1
+	if tag.Number != 0x1f {
+		return tag, constructed, size, nil
+	}
+	tag.Number = 0
+	for {
+		if size >= len(data) {
+			return Tag{}, false, 0, SyntaxError{"truncated tag"}
+		}
+		b := data[size]
+		if size == 1 && b == 0x80 {
+			return Tag{}, false, 0, SyntaxError{"tag number is not minimally encoded"}
+		}
+		if tag.Number > (math.MaxInt32 >> 7) {
+			return Tag{}, false, 0, SyntaxError{"tag number is too large"}
+		}
+		tag.Number = tag.Number<<7 | int(b&0x7f)
+		size++
+		if b&0x80 == 0 {
+			break
+		}
+	}
+	if tag.Number < 0x1f {
+		return Tag{}, false, 0, SyntaxError{"tag number is not minimally encoded"}
+	}
+	return tag, constructed, size, nil
+}
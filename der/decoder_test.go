@@ -0,0 +1,380 @@
+package der
+
+import (
+	"encoding/asn1"
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestDecoder(t *testing.T) {
+	testCases := []struct {
+		name     string
+		data     []byte
+		read     func(d *Decoder) (any, error)
+		expected any
+		// strictErr is set if data is valid BER, but not valid DER.
+		strictErr bool
+	}{
+		{
+			name:     "boolean",
+			data:     []byte{0x01, 0x01, 0xff},
+			read:     func(d *Decoder) (v any, err error) { var b bool; err = d.ReadBoolean(TagBoolean, &b); return b, err },
+			expected: true,
+		},
+		{
+			name:      "boolean true which is not 0xff",
+			data:      []byte{0x01, 0x01, 0x01},
+			read:      func(d *Decoder) (v any, err error) { var b bool; err = d.ReadBoolean(TagBoolean, &b); return b, err },
+			expected:  true,
+			strictErr: true,
+		},
+		{
+			name:     "negative integer",
+			data:     []byte{0x02, 0x02, 0xff, 0x7f},
+			read:     func(d *Decoder) (v any, err error) { var i int64; err = d.ReadInteger(TagInteger, &i); return i, err },
+			expected: int64(-129),
+		},
+		{
+			name:     "largest integer",
+			data:     []byte{0x02, 0x08, 0x7f, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+			read:     func(d *Decoder) (v any, err error) { var i int64; err = d.ReadInteger(TagInteger, &i); return i, err },
+			expected: int64(math.MaxInt64),
+		},
+		{
+			name:      "integer with redundant leading octets",
+			data:      []byte{0x02, 0x03, 0x00, 0x00, 0x80},
+			read:      func(d *Decoder) (v any, err error) { var i int64; err = d.ReadInteger(TagInteger, &i); return i, err },
+			expected:  int64(128),
+			strictErr: true,
+		},
+		{
+			name: "negative big integer",
+			data: []byte{0x02, 0x09, 0xff, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+			read: func(d *Decoder) (v any, err error) {
+				var i *big.Int
+				err = d.ReadBigInteger(TagInteger, &i)
+				return i.String(), err
+			},
+			expected: "-18446744073709551616",
+		},
+		{
+			name: "implicitly tagged enumerated",
+			data: []byte{0x82, 0x01, 0x03},
+			read: func(d *Decoder) (v any, err error) {
+				var e asn1.Enumerated
+				err = d.ReadEnumerated(Tag{ClassContextSpecific, 2}, &e)
+				return e, err
+			},
+			expected: asn1.Enumerated(3),
+		},
+		{
+			name:     "binary real",
+			data:     []byte{0x09, 0x03, 0x80, 0xfd, 0x03},
+			read:     func(d *Decoder) (v any, err error) { var r float64; err = d.ReadReal(TagReal, &r); return r, err },
+			expected: 0.375,
+		},
+		{
+			name:      "binary real with base 16",
+			data:      []byte{0x09, 0x03, 0xa0, 0x01, 0x01},
+			read:      func(d *Decoder) (v any, err error) { var r float64; err = d.ReadReal(TagReal, &r); return r, err },
+			expected:  16.0,
+			strictErr: true,
+		},
+		{
+			name:     "decimal real",
+			data:     append([]byte{0x09, 0x07, 0x03}, "-25E-1"...),
+			read:     func(d *Decoder) (v any, err error) { var r float64; err = d.ReadReal(TagReal, &r); return r, err },
+			expected: -2.5,
+		},
+		{
+			name:     "real minus infinity",
+			data:     []byte{0x09, 0x01, 0x41},
+			read:     func(d *Decoder) (v any, err error) { var r float64; err = d.ReadReal(TagReal, &r); return r, err },
+			expected: math.Inf(-1),
+		},
+		{
+			name: "bit string",
+			data: []byte{0x03, 0x02, 0x02, 0xfc},
+			read: func(d *Decoder) (v any, err error) {
+				var b asn1.BitString
+				err = d.ReadBitString(TagBitString, &b)
+				return b, err
+			},
+			expected: asn1.BitString{Bytes: []byte{0xfc}, BitLength: 6},
+		},
+		{
+			name: "bit string with non-zero unused bits",
+			data: []byte{0x03, 0x02, 0x02, 0xff},
+			read: func(d *Decoder) (v any, err error) {
+				var b asn1.BitString
+				err = d.ReadBitString(TagBitString, &b)
+				return b, err
+			},
+			expected:  asn1.BitString{Bytes: []byte{0xfc}, BitLength: 6},
+			strictErr: true,
+		},
+		{
+			name: "constructed bit string",
+			data: []byte{0x23, 0x80, 0x03, 0x02, 0x00, 0xff, 0x03, 0x02, 0x04, 0xf0, 0x00, 0x00},
+			read: func(d *Decoder) (v any, err error) {
+				var b asn1.BitString
+				err = d.ReadBitString(TagBitString, &b)
+				return b, err
+			},
+			expected:  asn1.BitString{Bytes: []byte{0xff, 0xf0}, BitLength: 12},
+			strictErr: true,
+		},
+		{
+			name: "named bit string with trailing zero bits",
+			data: []byte{0x03, 0x02, 0x00, 0x20},
+			read: func(d *Decoder) (v any, err error) {
+				var b asn1.BitString
+				err = d.ReadNamedBitString(TagBitString, &b)
+				return b, err
+			},
+			expected:  asn1.BitString{Bytes: []byte{0x20}, BitLength: 8},
+			strictErr: true,
+		},
+		{
+			name: "constructed octet string",
+			data: []byte{0x24, 0x08, 0x04, 0x02, 0x01, 0x02, 0x04, 0x02, 0x03, 0x04},
+			read: func(d *Decoder) (v any, err error) {
+				var b []byte
+				err = d.ReadOctetString(TagOctetString, &b)
+				return b, err
+			},
+			expected:  []byte{0x01, 0x02, 0x03, 0x04},
+			strictErr: true,
+		},
+		{
+			name: "containing",
+			data: []byte{0x04, 0x03, 0x02, 0x01, 0x05},
+			read: func(d *Decoder) (v any, err error) {
+				var i int64
+				err = d.ReadContaining(TagOctetString, func(d *Decoder) error { return d.ReadInteger(TagInteger, &i) })
+				return i, err
+			},
+			expected: int64(5),
+		},
+		{
+			name: "BMPString",
+			data: []byte{0x1e, 0x04, 0x00, 'h', 0x00, 0xe9},
+			read: func(d *Decoder) (v any, err error) {
+				var s string
+				err = d.ReadBMPString(TagBMPString, &s)
+				return s, err
+			},
+			expected: "hé",
+		},
+		{
+			name: "object identifier",
+			data: []byte{0x06, 0x06, 0x2a, 0x86, 0x48, 0x86, 0xf7, 0x0d},
+			read: func(d *Decoder) (v any, err error) {
+				var oid asn1.ObjectIdentifier
+				err = d.ReadObjectIdentifier(TagObjectIdentifier, &oid)
+				return oid, err
+			},
+			expected: asn1.ObjectIdentifier{1, 2, 840, 113549},
+		},
+		{
+			name:     "null",
+			data:     []byte{0x05, 0x00},
+			read:     func(d *Decoder) (v any, err error) { return nil, d.ReadNull(TagNull) },
+			expected: nil,
+		},
+		{
+			name: "GeneralizedTime with fraction",
+			data: append([]byte{0x18, 0x11}, "20180103060407.5Z"...),
+			read: func(d *Decoder) (v any, err error) {
+				var tm time.Time
+				err = d.ReadGeneralizedTime(TagGeneralizedTime, &tm)
+				return tm, err
+			},
+			expected: time.Date(2018, 1, 3, 6, 4, 7, 500000000, time.UTC),
+		},
+		{
+			name: "GeneralizedTime with trailing zero in fraction",
+			data: append([]byte{0x18, 0x12}, "20180103060407.50Z"...),
+			read: func(d *Decoder) (v any, err error) {
+				var tm time.Time
+				err = d.ReadGeneralizedTime(TagGeneralizedTime, &tm)
+				return tm, err
+			},
+			expected:  time.Date(2018, 1, 3, 6, 4, 7, 500000000, time.UTC),
+			strictErr: true,
+		},
+		{
+			name: "GeneralizedTime without seconds and with time zone",
+			data: append([]byte{0x18, 0x11}, "201801030704+0100"...),
+			read: func(d *Decoder) (v any, err error) {
+				var tm time.Time
+				err = d.ReadGeneralizedTime(TagGeneralizedTime, &tm)
+				return tm.UTC(), err
+			},
+			expected:  time.Date(2018, 1, 3, 6, 4, 0, 0, time.UTC),
+			strictErr: true,
+		},
+		{
+			name: "UTCTime in previous century",
+			data: append([]byte{0x17, 0x0d}, "500103060407Z"...),
+			read: func(d *Decoder) (v any, err error) {
+				var tm time.Time
+				err = d.ReadUTCTime(TagUTCTime, &tm)
+				return tm, err
+			},
+			expected: time.Date(1950, 1, 3, 6, 4, 7, 0, time.UTC),
+		},
+		{
+			name: "high tag number",
+			data: []byte{0x5f, 0x81, 0x48, 0x00},
+			read: func(d *Decoder) (v any, err error) {
+				return nil, d.ReadNull(Tag{ClassApplication, 200})
+			},
+			expected: nil,
+		},
+		{
+			name: "explicit tag with indefinite length",
+			data: []byte{0xa1, 0x80, 0x02, 0x01, 0x05, 0x00, 0x00},
+			read: func(d *Decoder) (v any, err error) {
+				inner, err := d.BeginConstructed(Tag{ClassContextSpecific, 1})
+				if err != nil {
+					return nil, err
+				}
+				var i int64
+				if err := inner.ReadInteger(TagInteger, &i); err != nil {
+					return nil, err
+				}
+				return i, inner.End()
+			},
+			expected:  int64(5),
+			strictErr: true,
+		},
+		{
+			name: "raw value",
+			data: []byte{0xa2, 0x02, 0x05, 0x00},
+			read: func(d *Decoder) (v any, err error) {
+				var raw any
+				err = d.ReadAny(&raw)
+				return raw, err
+			},
+			expected: asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 2, IsCompound: true, Bytes: []byte{0x05, 0x00}, FullBytes: []byte{0xa2, 0x02, 0x05, 0x00}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := NewDecoder(append(tc.data, 0xff))
+			v, err := tc.read(d)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if fmt.Sprintf("%#v", v) != fmt.Sprintf("%#v", tc.expected) {
+				t.Errorf("Decoded value did not match expected:\nwant %#v\ngot  %#v", tc.expected, v)
+			}
+			if rest := d.Rest(); len(rest) != 1 {
+				t.Errorf("Expected trailing data to be left, got %x", rest)
+			}
+
+			strict := NewDecoder(tc.data)
+			strict.Strict = true
+			_, err = tc.read(strict)
+			if tc.strictErr && err == nil {
+				t.Errorf("Expected error in strict mode")
+			} else if !tc.strictErr && err != nil {
+				t.Errorf("Unexpected error in strict mode: %v", err)
+			}
+		})
+	}
+}
+
+func TestDecoderErrors(t *testing.T) {
+	testCases := []struct {
+		name string
+		data []byte
+		read func(d *Decoder) error
+	}{
+		{
+			name: "unexpected tag",
+			data: []byte{0x02, 0x01, 0x05},
+			read: func(d *Decoder) error { var b bool; return d.ReadBoolean(TagBoolean, &b) },
+		},
+		{
+			name: "integer overflow",
+			data: []byte{0x02, 0x09, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+			read: func(d *Decoder) error { var i int64; return d.ReadInteger(TagInteger, &i) },
+		},
+		{
+			name: "empty integer",
+			data: []byte{0x02, 0x00},
+			read: func(d *Decoder) error { var i int64; return d.ReadInteger(TagInteger, &i) },
+		},
+		{
+			name: "truncated length",
+			data: []byte{0x04, 0x82, 0x01},
+			read: func(d *Decoder) error { var b []byte; return d.ReadOctetString(TagOctetString, &b) },
+		},
+		{
+			name: "indefinite length of primitive encoding",
+			data: []byte{0x04, 0x80, 0x01, 0x00, 0x00},
+			read: func(d *Decoder) error { var b []byte; return d.ReadOctetString(TagOctetString, &b) },
+		},
+		{
+			name: "tag number in long form below 31",
+			data: []byte{0x1f, 0x05, 0x00},
+			read: func(d *Decoder) error { return d.ReadNull(TagNull) },
+		},
+		{
+			name: "primitive encoding of constructed value",
+			data: []byte{0x10, 0x00},
+			read: func(d *Decoder) error { _, err := d.BeginConstructed(TagSequence); return err },
+		},
+		{
+			name: "trailing elements",
+			data: []byte{0x30, 0x02, 0x05, 0x00},
+			read: func(d *Decoder) error {
+				inner, err := d.BeginConstructed(TagSequence)
+				if err != nil {
+					return err
+				}
+				return inner.End()
+			},
+		},
+		{
+			name: "trailing data after contained value",
+			data: []byte{0x04, 0x04, 0x02, 0x01, 0x05, 0x00},
+			read: func(d *Decoder) error {
+				return d.ReadContaining(TagOctetString, func(d *Decoder) error { var i int64; return d.ReadInteger(TagInteger, &i) })
+			},
+		},
+		{
+			name: "invalid time",
+			data: append([]byte{0x17, 0x04}, "1801"...),
+			read: func(d *Decoder) error { var tm time.Time; return d.ReadUTCTime(TagUTCTime, &tm) },
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.read(NewDecoder(tc.data)); err == nil {
+				t.Errorf("Expected error")
+			}
+		})
+	}
+}
+
+func TestDecoderRoundTrip(t *testing.T) {
+	values := []float64{1, -1, 0.1, math.Pi, 1e300, -1e-300, math.SmallestNonzeroFloat64}
+	for _, v := range values {
+		var e Encoder
+		e.WriteReal(TagReal, v)
+		var decoded float64
+		d := NewDecoder(e.Bytes())
+		d.Strict = true
+		if err := d.ReadReal(TagReal, &decoded); err != nil {
+			t.Errorf("Failed to decode %v: %v", v, err)
+		} else if decoded != v {
+			t.Errorf("Decoded value %v did not match encoded %v", decoded, v)
+		}
+	}
+}
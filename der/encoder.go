@@ -0,0 +1,483 @@
+package der
+
+import (
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// ErrAbsentValue is returned when value of CHOICE or open type to encode is nil.
+var ErrAbsentValue = errors.New("der: value of CHOICE or open type is not set")
+
+// Marshaler is implemented by generated types, which encode themselves without reflection.
+type Marshaler interface {
+	// EncodeDER appends DER encoding of the value to e.
+	// If tag is not zero, it replaces the outermost tag of the encoding.
+	EncodeDER(e *Encoder, tag Tag) error
+}
+
+// Encoder accumulates DER encoding of values.
+// Zero value is an empty encoder ready to use.
+type Encoder struct {
+	buf []byte
+}
+
+// Bytes returns encoding accumulated so far.
+func (e *Encoder) Bytes() []byte {
+	return e.buf
+}
+
+// Reset discards accumulated encoding, keeping allocated buffer.
+func (e *Encoder) Reset() {
+	e.buf = e.buf[:0]
+}
+
+// BeginConstructed starts constructed encoding with the tag, and returns position of its contents,
+// which should be passed to End, EndSet or EndSetOf after contents are written.
+func (e *Encoder) BeginConstructed(tag Tag) int {
+	e.buf = appendIdentifier(e.buf, tag, true)
+	return len(e.buf)
+}
+
+// End finishes constructed encoding started at start by inserting length of its contents.
+func (e *Encoder) End(start int) {
+	length := len(e.buf) - start
+	header := appendLength(nil, length)
+	e.buf = append(e.buf, header...)
+	copy(e.buf[start+len(header):], e.buf[start:start+length])
+	copy(e.buf[start:], header)
+}
+
+// EndSet is same as End, but also sorts components of SET by their tags, see X.690, section 10.3.
+func (e *Encoder) EndSet(start int) {
+	e.sortElements(start, func(a, b element) int {
+		switch {
+		case a.tag.less(b.tag):
+			return -1
+		case b.tag.less(a.tag):
+			return 1
+		default:
+			return 0
+		}
+	})
+	e.End(start)
+}
+
+// EndSetOf is same as End, but also sorts elements of SET OF by their encodings, see X.690, section 11.6.
+func (e *Encoder) EndSetOf(start int) {
+	e.sortElements(start, func(a, b element) int {
+		return strings.Compare(string(a.data), string(b.data))
+	})
+	e.End(start)
+}
+
+type element struct {
+	tag  Tag
+	data []byte
+}
+
+// sortElements sorts encodings of elements written after start.
+func (e *Encoder) sortElements(start int, cmp func(a, b element) int) {
+	var elements []element
+	for data := e.buf[start:]; len(data) > 0; {
+		tag, size, ok := parseElement(data)
+		if !ok {
+			// can not happen, as contents are written by the encoder itself
+			return
+		}
+		elements = append(elements, element{tag, data[:size]})
+		data = data[size:]
+	}
+	if len(elements) < 2 {
+		return
+	}
+	slices.SortStableFunc(elements, cmp)
+	sorted := make([]byte, 0, len(e.buf)-start)
+	for _, el := range elements {
+		sorted = append(sorted, el.data...)
+	}
+	copy(e.buf[start:], sorted)
+}
+
+// WriteBoolean appends BOOLEAN value.
+func (e *Encoder) WriteBoolean(tag Tag, v bool) {
+	if v {
+		e.writePrimitive(tag, []byte{0xff})
+	} else {
+		e.writePrimitive(tag, []byte{0x00})
+	}
+}
+
+// WriteInteger appends INTEGER or ENUMERATED value.
+func (e *Encoder) WriteInteger(tag Tag, v int64) {
+	var contents [8]byte
+	e.writePrimitive(tag, appendSigned(contents[:0], v))
+}
+
+// WriteBigInteger appends INTEGER value.
+func (e *Encoder) WriteBigInteger(tag Tag, v *big.Int) error {
+	if v == nil {
+		return errors.New("der: nil big.Int")
+	}
+	var contents []byte
+	if v.Sign() >= 0 {
+		contents = v.Bytes()
+		if len(contents) == 0 || contents[0]&0x80 != 0 {
+			contents = append([]byte{0x00}, contents...)
+		}
+	} else {
+		// two's complement of negative number is bitwise NOT of its absolute value minus one
+		contents = new(big.Int).Sub(new(big.Int).Neg(v), big.NewInt(1)).Bytes()
+		for i := range contents {
+			contents[i] = ^contents[i]
+		}
+		if len(contents) == 0 || contents[0]&0x80 == 0 {
+			contents = append([]byte{0xff}, contents...)
+		}
+	}
+	e.writePrimitive(tag, contents)
+	return nil
+}
+
+// WriteReal appends REAL value using binary encoding, see X.690, sections 8.5 and 11.3.
+func (e *Encoder) WriteReal(tag Tag, v float64) {
+	switch {
+	case v == 0 && !math.Signbit(v):
+		e.writePrimitive(tag, nil)
+		return
+	case v == 0:
+		e.writePrimitive(tag, []byte{0x43})
+		return
+	case math.IsInf(v, 1):
+		e.writePrimitive(tag, []byte{0x40})
+		return
+	case math.IsInf(v, -1):
+		e.writePrimitive(tag, []byte{0x41})
+		return
+	case math.IsNaN(v):
+		e.writePrimitive(tag, []byte{0x42})
+		return
+	}
+	first := byte(0x80)
+	if v < 0 {
+		first |= 0x40
+		v = -v
+	}
+	frac, exp := math.Frexp(v)
+	mantissa := uint64(math.Ldexp(frac, 53))
+	exp -= 53
+	// DER requires mantissa to be odd
+	for mantissa&1 == 0 {
+		mantissa >>= 1
+		exp++
+	}
+	exponent := appendSigned(nil, int64(exp))
+	switch len(exponent) {
+	case 1, 2, 3:
+		first |= byte(len(exponent) - 1)
+	default:
+		first |= 0x03
+		exponent = append([]byte{byte(len(exponent))}, exponent...)
+	}
+	contents := append([]byte{first}, exponent...)
+	var mantissaBytes []byte
+	for ; mantissa > 0; mantissa >>= 8 {
+		mantissaBytes = append(mantissaBytes, byte(mantissa))
+	}
+	slices.Reverse(mantissaBytes)
+	e.writePrimitive(tag, append(contents, mantissaBytes...))
+}
+
+// WriteOctetString appends OCTET STRING value.
+func (e *Encoder) WriteOctetString(tag Tag, v []byte) {
+	e.writePrimitive(tag, v)
+}
+
+// WriteContaining appends OCTET STRING value which contains DER encoding of value written by fn,
+// see X.682, section 11 (contents constraint).
+func (e *Encoder) WriteContaining(tag Tag, fn func(e *Encoder) error) error {
+	e.buf = appendIdentifier(e.buf, tag, false)
+	start := len(e.buf)
+	if err := fn(e); err != nil {
+		return err
+	}
+	e.End(start)
+	return nil
+}
+
+// WriteString appends value of restricted character string type which is encoded as is,
+// e.g. UTF8String or IA5String.
+func (e *Encoder) WriteString(tag Tag, v string) {
+	e.buf = appendIdentifier(e.buf, tag, false)
+	e.buf = appendLength(e.buf, len(v))
+	e.buf = append(e.buf, v...)
+}
+
+// WriteBMPString appends BMPString value, which is encoded as UCS-2.
+func (e *Encoder) WriteBMPString(tag Tag, v string) error {
+	contents := make([]byte, 0, 2*len(v))
+	for _, r := range v {
+		if r > 0xffff || utf16.IsSurrogate(r) {
+			return fmt.Errorf("der: character %q can not be encoded as BMPString", r)
+		}
+		contents = append(contents, byte(r>>8), byte(r))
+	}
+	e.writePrimitive(tag, contents)
+	return nil
+}
+
+// WriteUniversalString appends UniversalString value, which is encoded as UCS-4.
+func (e *Encoder) WriteUniversalString(tag Tag, v string) {
+	contents := make([]byte, 0, 4*len(v))
+	for _, r := range v {
+		contents = append(contents, byte(r>>24), byte(r>>16), byte(r>>8), byte(r))
+	}
+	e.writePrimitive(tag, contents)
+}
+
+// WriteBitString appends BIT STRING value. Unused bits of the last octet are encoded as zeroes.
+func (e *Encoder) WriteBitString(tag Tag, v asn1.BitString) {
+	size := (v.BitLength + 7) / 8
+	contents := make([]byte, 1+size)
+	contents[0] = byte(size*8 - v.BitLength)
+	copy(contents[1:], v.Bytes)
+	if size > 0 {
+		contents[size] &= 0xff << contents[0]
+	}
+	e.writePrimitive(tag, contents)
+}
+
+// WriteNamedBitString appends value of BIT STRING type with named bits.
+// Trailing zero bits are not encoded, see X.690, section 11.2.2.
+func (e *Encoder) WriteNamedBitString(tag Tag, v asn1.BitString) {
+	e.WriteBitString(tag, trimBitString(v))
+}
+
+// WriteNull appends NULL value.
+func (e *Encoder) WriteNull(tag Tag) {
+	e.writePrimitive(tag, nil)
+}
+
+// WriteObjectIdentifier appends OBJECT IDENTIFIER value.
+func (e *Encoder) WriteObjectIdentifier(tag Tag, v asn1.ObjectIdentifier) error {
+	if len(v) < 2 || v[0] < 0 || v[0] > 2 || v[1] < 0 || (v[0] < 2 && v[1] >= 40) {
+		return fmt.Errorf("der: invalid object identifier %v", v)
+	}
+	contents := appendBase128(nil, uint64(v[0]*40+v[1]))
+	for _, arc := range v[2:] {
+		if arc < 0 {
+			return fmt.Errorf("der: invalid object identifier %v", v)
+		}
+		contents = appendBase128(contents, uint64(arc))
+	}
+	e.writePrimitive(tag, contents)
+	return nil
+}
+
+// WriteUTCTime appends UTCTime value. Time is converted to UTC, and should be in years 1950 to 2049.
+func (e *Encoder) WriteUTCTime(tag Tag, v time.Time) error {
+	v = v.UTC()
+	if v.Year() < 1950 || v.Year() >= 2050 {
+		return fmt.Errorf("der: time %v can not be encoded as UTCTime", v)
+	}
+	e.WriteString(tag, v.Format("060102150405Z"))
+	return nil
+}
+
+// WriteGeneralizedTime appends GeneralizedTime value. Time is converted to UTC,
+// fractional seconds are encoded without trailing zeroes, see X.690, section 11.7.
+func (e *Encoder) WriteGeneralizedTime(tag Tag, v time.Time) error {
+	v = v.UTC()
+	if v.Year() < 0 || v.Year() > 9999 {
+		return fmt.Errorf("der: time %v can not be encoded as GeneralizedTime", v)
+	}
+	s := v.Format("20060102150405")
+	if nanos := v.Nanosecond(); nanos != 0 {
+		s += "." + strings.TrimRight(fmt.Sprintf("%09d", nanos), "0")
+	}
+	e.WriteString(tag, s+"Z")
+	return nil
+}
+
+// WriteRawValue appends value encoded elsewhere. If FullBytes are set, they are written as is.
+func (e *Encoder) WriteRawValue(v asn1.RawValue) {
+	if len(v.FullBytes) > 0 {
+		e.buf = append(e.buf, v.FullBytes...)
+		return
+	}
+	e.buf = appendIdentifier(e.buf, Tag{Class(v.Class), v.Tag}, v.IsCompound)
+	e.buf = appendLength(e.buf, len(v.Bytes))
+	e.buf = append(e.buf, v.Bytes...)
+}
+
+// WriteAny appends value of open type, e.g. ANY. Value can be asn1.RawValue, Marshaler,
+// or any other value supported by encoding/asn1, which is used as a fallback.
+func (e *Encoder) WriteAny(v any) error {
+	switch v := v.(type) {
+	case nil:
+		return ErrAbsentValue
+	case asn1.RawValue:
+		e.WriteRawValue(v)
+		return nil
+	case Marshaler:
+		return v.EncodeDER(e, Tag{})
+	default:
+		data, err := asn1.Marshal(v)
+		if err != nil {
+			return err
+		}
+		e.buf = append(e.buf, data...)
+		return nil
+	}
+}
+
+// IsZero returns true if v is zero value of its type.
+// It is used to omit absent OPTIONAL components which do not have a cheaper check.
+func IsZero(v any) bool {
+	return v == nil || reflect.ValueOf(v).IsZero()
+}
+
+// Equal returns true if values a and b are deeply equal.
+// It is used to omit components equal to their DEFAULT values which do not have a cheaper check.
+func Equal(a, b any) bool {
+	return reflect.DeepEqual(a, b)
+}
+
+// BitStringEqual returns true if BIT STRING values a and b have the same bits.
+// If named is set, trailing zero bits are ignored, as they are not significant for types with named bits,
+// see X.680, section 22.7.
+func BitStringEqual(a, b asn1.BitString, named bool) bool {
+	if named {
+		a, b = trimBitString(a), trimBitString(b)
+	}
+	if a.BitLength != b.BitLength {
+		return false
+	}
+	for i := 0; i < a.BitLength; i++ {
+		if a.At(i) != b.At(i) {
+			return false
+		}
+	}
+	return true
+}
+
+// trimBitString returns v without trailing zero bits.
+func trimBitString(v asn1.BitString) asn1.BitString {
+	for v.BitLength > 0 && v.At(v.BitLength-1) == 0 {
+		v.BitLength--
+	}
+	return v
+}
+
+func (e *Encoder) writePrimitive(tag Tag, contents []byte) {
+	e.buf = appendIdentifier(e.buf, tag, false)
+	e.buf = appendLength(e.buf, len(contents))
+	e.buf = append(e.buf, contents...)
+}
+
+// appendIdentifier appends identifier octets, see X.690, section 8.1.2.
+func appendIdentifier(dst []byte, tag Tag, constructed bool) []byte {
+	b := byte(tag.Class) << 6
+	if constructed {
+		b |= 0x20
+	}
+	if tag.Number < 31 {
+		return append(dst, b|byte(tag.Number))
+	}
+	return appendBase128(append(dst, b|0x1f), uint64(tag.Number))
+}
+
+// appendLength appends length octets in definite form, see X.690, section 10.1.
+func appendLength(dst []byte, length int) []byte {
+	if length < 128 {
+		return append(dst, byte(length))
+	}
+	n := 0
+	for l := length; l > 0; l >>= 8 {
+		n++
+	}
+	dst = append(dst, 0x80|byte(n))
+	for i := n - 1; i >= 0; i-- {
+		dst = append(dst, byte(length>>(8*i)))
+	}
+	return dst
+}
+
+// appendSigned appends minimal two's complement representation of v.
+func appendSigned(dst []byte, v int64) []byte {
+	n := 1
+	for i := v; i > 127 || i < -128; i >>= 8 {
+		n++
+	}
+	for i := n - 1; i >= 0; i-- {
+		dst = append(dst, byte(v>>(8*i)))
+	}
+	return dst
+}
+
+// appendBase128 appends v as base 128 number, with high bit set in all octets but the last.
+func appendBase128(dst []byte, v uint64) []byte {
+	n := 1
+	for i := v; i >= 0x80; i >>= 7 {
+		n++
+	}
+	for i := n - 1; i >= 0; i-- {
+		b := byte(v>>(7*i)) & 0x7f
+		if i != 0 {
+			b |= 0x80
+		}
+		dst = append(dst, b)
+	}
+	return dst
+}
+
+// parseElement parses tag and size of the first encoded element in data.
+func parseElement(data []byte) (tag Tag, size int, ok bool) {
+	if len(data) < 2 {
+		return Tag{}, 0, false
+	}
+	tag.Class = Class(data[0] >> 6)
+	tag.Number = int(data[0] & 0x1f)
+	offset := 1
+	if tag.Number == 0x1f {
+		tag.Number = 0
+		for {
+			if offset >= len(data) {
+				return Tag{}, 0, false
+			}
+			b := data[offset]
+			offset++
+			tag.Number = tag.Number<<7 | int(b&0x7f)
+			if b&0x80 == 0 {
+				break
+			}
+		}
+	}
+	if offset >= len(data) {
+		return Tag{}, 0, false
+	}
+	length := int(data[offset])
+	offset++
+	if length&0x80 != 0 {
+		n := length & 0x7f
+		if offset+n > len(data) {
+			return Tag{}, 0, false
+		}
+		length = 0
+		for _, b := range data[offset : offset+n] {
+			length = length<<8 | int(b)
+		}
+		offset += n
+	}
+	if offset+length > len(data) {
+		return Tag{}, 0, false
+	}
+	return tag, offset + length, true
+}
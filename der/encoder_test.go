@@ -0,0 +1,241 @@
+package der
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestEncoder(t *testing.T) {
+	testCases := []struct {
+		name     string
+		write    func(e *Encoder) error
+		expected []byte
+	}{
+		{
+			name:     "boolean",
+			write:    func(e *Encoder) error { e.WriteBoolean(TagBoolean, true); return nil },
+			expected: []byte{0x01, 0x01, 0xff},
+		},
+		{
+			name:     "small integer",
+			write:    func(e *Encoder) error { e.WriteInteger(TagInteger, 127); return nil },
+			expected: []byte{0x02, 0x01, 0x7f},
+		},
+		{
+			name:     "integer with leading zero",
+			write:    func(e *Encoder) error { e.WriteInteger(TagInteger, 128); return nil },
+			expected: []byte{0x02, 0x02, 0x00, 0x80},
+		},
+		{
+			name:     "negative integer",
+			write:    func(e *Encoder) error { e.WriteInteger(TagInteger, -129); return nil },
+			expected: []byte{0x02, 0x02, 0xff, 0x7f},
+		},
+		{
+			name:     "negative big integer",
+			write:    func(e *Encoder) error { return e.WriteBigInteger(TagInteger, big.NewInt(-129)) },
+			expected: []byte{0x02, 0x02, 0xff, 0x7f},
+		},
+		{
+			name:     "negative big integer power of two",
+			write:    func(e *Encoder) error { return e.WriteBigInteger(TagInteger, big.NewInt(-128)) },
+			expected: []byte{0x02, 0x01, 0x80},
+		},
+		{
+			name:     "big integer with leading zero",
+			write:    func(e *Encoder) error { return e.WriteBigInteger(TagInteger, big.NewInt(255)) },
+			expected: []byte{0x02, 0x02, 0x00, 0xff},
+		},
+		{
+			name:     "implicitly tagged integer",
+			write:    func(e *Encoder) error { e.WriteInteger(Tag{ClassContextSpecific, 3}, 0); return nil },
+			expected: []byte{0x83, 0x01, 0x00},
+		},
+		{
+			name:     "high tag number",
+			write:    func(e *Encoder) error { e.WriteNull(Tag{ClassApplication, 200}); return nil },
+			expected: []byte{0x5f, 0x81, 0x48, 0x00},
+		},
+		{
+			name:     "real zero",
+			write:    func(e *Encoder) error { e.WriteReal(TagReal, 0); return nil },
+			expected: []byte{0x09, 0x00},
+		},
+		{
+			name:     "real minus infinity",
+			write:    func(e *Encoder) error { e.WriteReal(TagReal, math.Inf(-1)); return nil },
+			expected: []byte{0x09, 0x01, 0x41},
+		},
+		{
+			name:     "real with odd mantissa",
+			write:    func(e *Encoder) error { e.WriteReal(TagReal, 0.375); return nil }, // 3 * 2^-3
+			expected: []byte{0x09, 0x03, 0x80, 0xfd, 0x03},
+		},
+		{
+			name:     "negative real",
+			write:    func(e *Encoder) error { e.WriteReal(TagReal, -1024); return nil }, // -1 * 2^10
+			expected: []byte{0x09, 0x03, 0xc0, 0x0a, 0x01},
+		},
+		{
+			name: "bit string with unused bits",
+			write: func(e *Encoder) error {
+				e.WriteBitString(TagBitString, asn1.BitString{Bytes: []byte{0xff}, BitLength: 6})
+				return nil
+			},
+			expected: []byte{0x03, 0x02, 0x02, 0xfc},
+		},
+		{
+			name: "named bit string",
+			write: func(e *Encoder) error {
+				e.WriteNamedBitString(TagBitString, asn1.BitString{Bytes: []byte{0x20, 0x00}, BitLength: 16})
+				return nil
+			},
+			expected: []byte{0x03, 0x02, 0x05, 0x20},
+		},
+		{
+			name: "object identifier",
+			write: func(e *Encoder) error {
+				return e.WriteObjectIdentifier(TagObjectIdentifier, asn1.ObjectIdentifier{1, 2, 840, 113549})
+			},
+			expected: []byte{0x06, 0x06, 0x2a, 0x86, 0x48, 0x86, 0xf7, 0x0d},
+		},
+		{
+			name:     "BMPString",
+			write:    func(e *Encoder) error { return e.WriteBMPString(TagBMPString, "hé") },
+			expected: []byte{0x1e, 0x04, 0x00, 'h', 0x00, 0xe9},
+		},
+		{
+			name: "GeneralizedTime with fraction",
+			write: func(e *Encoder) error {
+				return e.WriteGeneralizedTime(TagGeneralizedTime, time.Date(2018, 1, 3, 6, 4, 7, 500000000, time.UTC))
+			},
+			expected: append([]byte{0x18, 0x11}, "20180103060407.5Z"...),
+		},
+		{
+			name: "UTCTime is converted to UTC",
+			write: func(e *Encoder) error {
+				return e.WriteUTCTime(TagUTCTime, time.Date(2018, 1, 3, 7, 4, 7, 0, time.FixedZone("CET", 3600)))
+			},
+			expected: append([]byte{0x17, 0x0d}, "180103060407Z"...),
+		},
+		{
+			name: "explicit tag",
+			write: func(e *Encoder) error {
+				start := e.BeginConstructed(Tag{ClassContextSpecific, 1})
+				e.WriteInteger(TagInteger, 5)
+				e.End(start)
+				return nil
+			},
+			expected: []byte{0xa1, 0x03, 0x02, 0x01, 0x05},
+		},
+		{
+			name: "long form length",
+			write: func(e *Encoder) error {
+				start := e.BeginConstructed(TagSequence)
+				e.WriteOctetString(TagOctetString, make([]byte, 200))
+				e.End(start)
+				return nil
+			},
+			expected: append([]byte{0x30, 0x81, 0xcb, 0x04, 0x81, 0xc8}, make([]byte, 200)...),
+		},
+		{
+			name: "set components are sorted by tag",
+			write: func(e *Encoder) error {
+				start := e.BeginConstructed(TagSet)
+				e.WriteBoolean(Tag{ClassContextSpecific, 1}, true)
+				e.WriteInteger(Tag{ClassApplication, 2}, 1)
+				start2 := e.BeginConstructed(Tag{ClassContextSpecific, 0})
+				e.WriteNull(TagNull)
+				e.End(start2)
+				e.EndSet(start)
+				return nil
+			},
+			expected: []byte{0x31, 0x0a, 0x42, 0x01, 0x01, 0xa0, 0x02, 0x05, 0x00, 0x81, 0x01, 0xff},
+		},
+		{
+			name: "set of elements are sorted by encoding",
+			write: func(e *Encoder) error {
+				start := e.BeginConstructed(TagSet)
+				e.WriteInteger(TagInteger, 256)
+				e.WriteInteger(TagInteger, 2)
+				e.WriteInteger(TagInteger, 1)
+				e.EndSetOf(start)
+				return nil
+			},
+			expected: []byte{0x31, 0x0a, 0x02, 0x01, 0x01, 0x02, 0x01, 0x02, 0x02, 0x02, 0x01, 0x00},
+		},
+		{
+			name: "raw value",
+			write: func(e *Encoder) error {
+				return e.WriteAny(asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 2, IsCompound: true, Bytes: []byte{0x05, 0x00}})
+			},
+			expected: []byte{0xa2, 0x02, 0x05, 0x00},
+		},
+		{
+			name: "containing",
+			write: func(e *Encoder) error {
+				return e.WriteContaining(TagOctetString, func(e *Encoder) error { e.WriteInteger(TagInteger, 5); return nil })
+			},
+			expected: []byte{0x04, 0x03, 0x02, 0x01, 0x05},
+		},
+		{
+			name:     "encoding/asn1 fallback",
+			write:    func(e *Encoder) error { return e.WriteAny(int64(5)) },
+			expected: []byte{0x02, 0x01, 0x05},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var e Encoder
+			if err := tc.write(&e); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !bytes.Equal(e.Bytes(), tc.expected) {
+				t.Errorf("Encoding did not match expected:\nwant %x\ngot  %x", tc.expected, e.Bytes())
+			}
+		})
+	}
+}
+
+func TestEncoderErrors(t *testing.T) {
+	var e Encoder
+	if err := e.WriteObjectIdentifier(TagObjectIdentifier, asn1.ObjectIdentifier{1, 40}); err == nil {
+		t.Errorf("Expected error for invalid object identifier")
+	}
+	if err := e.WriteUTCTime(TagUTCTime, time.Date(2050, 1, 1, 0, 0, 0, 0, time.UTC)); err == nil {
+		t.Errorf("Expected error for UTCTime out of range")
+	}
+	if err := e.WriteBMPString(TagBMPString, "\U0001F600"); err == nil {
+		t.Errorf("Expected error for character outside of BMP")
+	}
+	if err := e.WriteAny(nil); err == nil {
+		t.Errorf("Expected error for nil open type value")
+	}
+	if len(e.Bytes()) != 0 {
+		t.Errorf("Expected nothing to be written on error, got %x", e.Bytes())
+	}
+}
+
+func TestBitStringEqual(t *testing.T) {
+	testCases := []struct {
+		a, b     asn1.BitString
+		named    bool
+		expected bool
+	}{
+		{asn1.BitString{Bytes: []byte{0x80}, BitLength: 1}, asn1.BitString{Bytes: []byte{0x80}, BitLength: 1}, false, true},
+		{asn1.BitString{Bytes: []byte{0x80}, BitLength: 1}, asn1.BitString{Bytes: []byte{0x80, 0x00}, BitLength: 9}, false, false},
+		{asn1.BitString{Bytes: []byte{0x80}, BitLength: 1}, asn1.BitString{Bytes: []byte{0x80, 0x00}, BitLength: 9}, true, true},
+		{asn1.BitString{Bytes: []byte{0x81}, BitLength: 1}, asn1.BitString{Bytes: []byte{0x80}, BitLength: 1}, false, true},
+		{asn1.BitString{}, asn1.BitString{Bytes: []byte{0x00}, BitLength: 3}, true, true},
+		{asn1.BitString{Bytes: []byte{0x40}, BitLength: 2}, asn1.BitString{Bytes: []byte{0x80}, BitLength: 2}, true, false},
+	}
+	for _, tc := range testCases {
+		if got := BitStringEqual(tc.a, tc.b, tc.named); got != tc.expected {
+			t.Errorf("BitStringEqual(%v, %v, %v) = %v, expected %v", tc.a, tc.b, tc.named, got, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+// Package der implements Distinguished Encoding Rules of ASN.1, as defined in X.690.
+//
+// It is a runtime library of the code generated by asn1go with GEN_DER code generator type,
+// and is not intended to be used directly.
+package der
+
+import "strconv"
+
+// Class is a class of ASN.1 tag.
+type Class uint8
+
+// Tag classes, see X.680, section 8.1.
+const (
+	ClassUniversal Class = iota
+	ClassApplication
+	ClassContextSpecific
+	ClassPrivate
+)
+
+// Tag is ASN.1 tag.
+// Zero value is not a valid tag, and is used to denote absence of tag where tag is optional.
+type Tag struct {
+	Class  Class
+	Number int
+}
+
+// IsZero returns true if t is zero value.
+func (t Tag) IsZero() bool {
+	return t == Tag{}
+}
+
+// Or returns t, or def if t is zero value.
+func (t Tag) Or(def Tag) Tag {
+	if t.IsZero() {
+		return def
+	}
+	return t
+}
+
+// less returns true if t precedes other in canonical order of tags, see X.680, section 8.6.
+func (t Tag) less(other Tag) bool {
+	if t.Class != other.Class {
+		return t.Class < other.Class
+	}
+	return t.Number < other.Number
+}
+
+// String returns tag in ASN.1 notation, e.g. [APPLICATION 1].
+func (t Tag) String() string {
+	switch t.Class {
+	case ClassUniversal:
+		return "[UNIVERSAL " + strconv.Itoa(t.Number) + "]"
+	case ClassApplication:
+		return "[APPLICATION " + strconv.Itoa(t.Number) + "]"
+	case ClassPrivate:
+		return "[PRIVATE " + strconv.Itoa(t.Number) + "]"
+	default:
+		return "[" + strconv.Itoa(t.Number) + "]"
+	}
+}
+
+// UNIVERSAL tags of built-in types, see X.680, section 8.6.
+var (
+	TagBoolean          = Tag{ClassUniversal, 1}
+	TagInteger          = Tag{ClassUniversal, 2}
+	TagBitString        = Tag{ClassUniversal, 3}
+	TagOctetString      = Tag{ClassUniversal, 4}
+	TagNull             = Tag{ClassUniversal, 5}
+	TagObjectIdentifier = Tag{ClassUniversal, 6}
+	TagReal             = Tag{ClassUniversal, 9}
+	TagEnumerated       = Tag{ClassUniversal, 10}
+	TagUTF8String       = Tag{ClassUniversal, 12}
+	TagSequence         = Tag{ClassUniversal, 16}
+	TagSet              = Tag{ClassUniversal, 17}
+	TagNumericString    = Tag{ClassUniversal, 18}
+	TagPrintableString  = Tag{ClassUniversal, 19}
+	TagTeletexString    = Tag{ClassUniversal, 20}
+	TagVideotexString   = Tag{ClassUniversal, 21}
+	TagIA5String        = Tag{ClassUniversal, 22}
+	TagUTCTime          = Tag{ClassUniversal, 23}
+	TagGeneralizedTime  = Tag{ClassUniversal, 24}
+	TagGraphicString    = Tag{ClassUniversal, 25}
+	TagVisibleString    = Tag{ClassUniversal, 26}
+	TagGeneralString    = Tag{ClassUniversal, 27}
+	TagUniversalString  = Tag{ClassUniversal, 28}
+	TagBMPString        = Tag{ClassUniversal, 30}
+)
@@ -0,0 +1,529 @@
+package asn1go
+
+import (
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/chemikadze/asn1go/der"
+)
+
+// Node is a value decoded by Decode, annotated with its description in the module.
+type Node struct {
+	// Name is identifier of SEQUENCE or SET component, or of CHOICE alternative. It is empty for other values.
+	Name string
+	// Type is name of the type of the value as it is written in the module, which is either type reference,
+	// or name of built-in type, e.g. INTEGER or SEQUENCE OF. It is empty for unknown extension additions.
+	Type string
+	// Kind is name of the built-in type of the value, e.g. SEQUENCE for reference to SEQUENCE type,
+	// or GeneralizedTime. It is empty for unknown extension additions.
+	Kind string
+	// Tag is the outermost tag of the encoding.
+	Tag der.Tag
+	// Offset is position of the encoding in decoded data.
+	Offset int
+	// Bytes hold the whole encoding of the value, including identifier and length octets.
+	Bytes []byte
+	// Value holds decoded value of simple type:
+	//  - bool for BOOLEAN,
+	//  - int64 for INTEGER, or *big.Int if it does not fit into 64 bits,
+	//  - asn1.Enumerated for ENUMERATED,
+	//  - float64 for REAL,
+	//  - asn1.BitString for BIT STRING,
+	//  - []byte for OCTET STRING,
+	//  - asn1.ObjectIdentifier for OBJECT IDENTIFIER,
+	//  - string for character string types,
+	//  - time.Time for GeneralizedTime and UTCTime,
+	//  - asn1.RawValue for ANY and for unknown extension additions.
+	// It is nil for NULL and for values of structured types.
+	Value any
+	// Identifier is identifier of ENUMERATED value, or of named number of INTEGER value, if the value has one.
+	Identifier string
+	// Children are components of SEQUENCE or SET value in order of their encoding, elements of SEQUENCE OF
+	// or SET OF value, or the chosen alternative of CHOICE value.
+	Children []Node
+}
+
+// Child returns component or alternative with given identifier.
+func (n Node) Child(name string) (Node, bool) {
+	for _, child := range n.Children {
+		if child.Name == name {
+			return child, true
+		}
+	}
+	return Node{}, false
+}
+
+// DecodeParams control decoding of values by DecodeWithParams.
+type DecodeParams struct {
+	// Registry is used to resolve types imported from other modules.
+	// If not specified, imported types can not be resolved.
+	Registry *ModuleRegistry
+	// Strict enables strict mode of der.Decoder, which rejects encodings which are not valid DER.
+	Strict bool
+}
+
+// Decode decodes BER encoding of the value of type typeName defined in module, without generating code.
+// Unknown extension additions are kept as children with raw values, and absent components are omitted.
+func Decode(module *ModuleDefinition, typeName string, data []byte) (Node, error) {
+	return DecodeWithParams(module, typeName, data, DecodeParams{})
+}
+
+// DecodeWithParams is same as Decode, but allows to resolve imported types and to enable strict DER mode.
+func DecodeWithParams(module *ModuleDefinition, typeName string, data []byte, params DecodeParams) (Node, error) {
+	// CHOICE types are not hoisted with raw representation, so that they are decoded as written in the module
+	ctx := newModuleContext(*module, GenParams{ChoiceRepr: ChoiceReprRaw, Registry: params.Registry})
+	dec := &dynamicDecoder{data: data}
+	d := der.NewDecoder(data)
+	d.Strict = params.Strict
+	node, err := dec.decode(ctx, d, TypeReference(typeName), der.Tag{})
+	if err == nil && d.More() {
+		err = fmt.Errorf("unexpected %v bytes after the value", len(d.Rest()))
+	}
+	if len(ctx.errors) > 0 {
+		return Node{}, errors.Join(ctx.errors...)
+	}
+	if err != nil {
+		return Node{}, err
+	}
+	return node, nil
+}
+
+// dynamicDecoder walks types of the module, decoding elements of BER encoding.
+type dynamicDecoder struct {
+	// data is the whole decoded data, which is used to find offsets of elements.
+	data []byte
+	// references holds names of types resolved since the last element was read, and is used to detect cycles.
+	references []string
+}
+
+// derClassOf maps CLASS_ constants to tag classes of der package.
+var derClassOf = map[int]der.Class{
+	CLASS_UNIVERSAL:        der.ClassUniversal,
+	CLASS_APPLICATION:      der.ClassApplication,
+	CLASS_CONTEXT_SPECIFIC: der.ClassContextSpecific,
+	CLASS_PRIVATE:          der.ClassPrivate,
+}
+
+// der returns the tag for der package.
+func (t asn1Tag) der() der.Tag {
+	return der.Tag{Class: derClassOf[t.Class], Number: t.Number}
+}
+
+// next returns node describing the next element of d, which is not read.
+func (dd *dynamicDecoder) next(d *der.Decoder) (Node, error) {
+	probe := *d
+	var raw asn1.RawValue
+	if err := probe.ReadRawValue(&raw); err != nil {
+		return Node{}, err
+	}
+	// encodings of elements are slices of the data, so their offset follows from capacity
+	return Node{
+		Tag:    der.Tag{Class: der.Class(raw.Class), Number: raw.Tag},
+		Offset: cap(dd.data) - cap(raw.FullBytes),
+		Bytes:  raw.FullBytes,
+	}, nil
+}
+
+// children returns decoder of elements nested into the next element, e.g. components of SEQUENCE.
+func (dd *dynamicDecoder) children() *dynamicDecoder {
+	return &dynamicDecoder{data: dd.data}
+}
+
+// decode decodes the next element of d as value of type t. If tag is not zero, it replaces the outermost tag.
+func (dd *dynamicDecoder) decode(ctx *moduleContext, d *der.Decoder, t Type, tag der.Tag) (Node, error) {
+	switch tt := t.(type) {
+	case TaggedType:
+		own, err := ctx.tagOf(tt)
+		if err != nil {
+			return Node{}, err
+		}
+		outer := tag.Or(own.der())
+		if !ctx.isExplicitTag(tt) {
+			return dd.decode(ctx, d, tt.Type, outer)
+		}
+		node, err := dd.next(d)
+		if err != nil {
+			return Node{}, err
+		}
+		inner, err := d.BeginConstructed(outer)
+		if err != nil {
+			return Node{}, err
+		}
+		value, err := dd.children().decode(ctx, inner, tt.Type, der.Tag{})
+		if err != nil {
+			return Node{}, err
+		}
+		if err := inner.End(); err != nil {
+			return Node{}, err
+		}
+		value.Tag, value.Offset, value.Bytes = node.Tag, node.Offset, node.Bytes
+		return value, nil
+	case ConstraintedType:
+		return dd.decode(ctx, d, tt.Type, tag)
+	case NamedType:
+		node, err := dd.decode(ctx, d, tt.Type, tag)
+		node.Name = tt.Identifier.Name()
+		return node, err
+	case TypeReference:
+		return dd.decodeReference(ctx, d, tt, tag)
+	case ChoiceType:
+		return dd.decodeChoice(ctx, d, tt, tag)
+	}
+	node, err := dd.next(d)
+	if err != nil {
+		return Node{}, err
+	}
+	node.Type = builtinTypeName(t)
+	node.Kind = node.Type
+	switch tt := t.(type) {
+	case SequenceType:
+		node.Children, err = dd.decodeSequence(ctx, d, tt, tag.Or(der.TagSequence))
+	case SetType:
+		node.Children, err = dd.decodeSet(ctx, d, tt, tag.Or(der.TagSet))
+	case SequenceOfType:
+		node.Children, err = dd.decodeElements(ctx, d, tt.Type, false, tag.Or(der.TagSequence))
+	case SetOfType:
+		node.Children, err = dd.decodeElements(ctx, d, tt.Type, true, tag.Or(der.TagSet))
+	case AnyType:
+		var raw asn1.RawValue
+		err = d.ReadRawValue(&raw)
+		node.Value = raw
+	case BooleanType:
+		var v bool
+		err = d.ReadBoolean(tag.Or(der.TagBoolean), &v)
+		node.Value = v
+	case IntegerType:
+		var v *big.Int
+		if err = d.ReadBigInteger(tag.Or(der.TagInteger), &v); err == nil {
+			node.Value, node.Identifier = ctx.dynamicInteger(v, tt)
+		}
+	case EnumeratedType:
+		var v asn1.Enumerated
+		if err = d.ReadEnumerated(tag.Or(der.TagEnumerated), &v); err == nil {
+			node.Value, node.Identifier = v, ctx.dynamicEnumerationItem(int64(v), tt)
+		}
+	case RealType:
+		var v float64
+		err = d.ReadReal(tag.Or(der.TagReal), &v)
+		node.Value = v
+	case OctetStringType:
+		var v []byte
+		err = d.ReadOctetString(tag.Or(der.TagOctetString), &v)
+		node.Value = v
+	case BitStringType:
+		var v asn1.BitString
+		err = d.ReadBitString(tag.Or(der.TagBitString), &v)
+		node.Value = v
+	case NullType:
+		err = d.ReadNull(tag.Or(der.TagNull))
+	case ObjectIdentifierType:
+		var v asn1.ObjectIdentifier
+		err = d.ReadObjectIdentifier(tag.Or(der.TagObjectIdentifier), &v)
+		node.Value = v
+	case RestrictedStringType:
+		number, ok := restrictedStringTags[tt.LexType]
+		if !ok {
+			return Node{}, fmt.Errorf("restricted string type %v is not supported", node.Type)
+		}
+		own := asn1Tag{Class: CLASS_UNIVERSAL, Number: number}.der()
+		var v string
+		switch tt.LexType {
+		case BMPString:
+			err = d.ReadBMPString(tag.Or(own), &v)
+		case UniversalString:
+			err = d.ReadUniversalString(tag.Or(own), &v)
+		default:
+			err = d.ReadString(tag.Or(own), &v)
+		}
+		node.Value = v
+	default:
+		return Node{}, fmt.Errorf("type %#v is not supported", t)
+	}
+	if err != nil {
+		return Node{}, err
+	}
+	return node, nil
+}
+
+// decodeReference decodes value of referenced type, which is annotated with name of the reference.
+func (dd *dynamicDecoder) decodeReference(ctx *moduleContext, d *der.Decoder, t TypeReference, tag der.Tag) (Node, error) {
+	assignment, assignmentCtx, err := ctx.lookupTypeAssignment(t)
+	if err != nil {
+		return Node{}, err
+	}
+	if assignment == nil {
+		if useful := ctx.lookupUsefulType(t); useful != nil && t.Name() != GeneralizedTimeName && t.Name() != UTCTimeName {
+			node, err := dd.decode(ctx, d, useful, tag)
+			node.Type = t.Name()
+			return node, err
+		}
+		node, err := dd.next(d)
+		if err != nil {
+			return Node{}, err
+		}
+		node.Type, node.Kind = t.Name(), t.Name()
+		var v time.Time
+		switch t.Name() {
+		case GeneralizedTimeName:
+			err = d.ReadGeneralizedTime(tag.Or(der.TagGeneralizedTime), &v)
+		case UTCTimeName:
+			err = d.ReadUTCTime(tag.Or(der.TagUTCTime), &v)
+		default:
+			return Node{}, fmt.Errorf("can not resolve TypeReference %v", t.Name())
+		}
+		if err != nil {
+			return Node{}, err
+		}
+		node.Value = v
+		return node, nil
+	}
+	name := string(assignmentCtx.moduleName) + "." + t.Name()
+	if slices.Contains(dd.references, name) {
+		return Node{}, fmt.Errorf("type %v: reference cycle %v", t, strings.Join(append(dd.references, name), " -> "))
+	}
+	dd.references = append(dd.references, name)
+	node, err := dd.decode(assignmentCtx, d, assignment.Type, tag)
+	dd.references = dd.references[:len(dd.references)-1]
+	if err != nil {
+		return Node{}, err
+	}
+	node.Type = t.Name()
+	return node, nil
+}
+
+// decodeChoice decodes value of CHOICE type, which is matched by the tag of the encoding.
+// Values of unknown alternatives of extensible CHOICE are decoded as raw values.
+func (dd *dynamicDecoder) decodeChoice(ctx *moduleContext, d *der.Decoder, t ChoiceType, tag der.Tag) (Node, error) {
+	if !tag.IsZero() {
+		return Node{}, fmt.Errorf("CHOICE can not be tagged implicitly")
+	}
+	alternatives := slices.Clone(t.AlternativeTypeList)
+	for _, ext := range t.ExtensionTypes {
+		switch e := ext.(type) {
+		case NamedType:
+			alternatives = append(alternatives, e)
+		case ExtensionAdditionAlternativesGroup:
+			alternatives = append(alternatives, e.Alternatives...)
+		}
+	}
+	node, err := dd.next(d)
+	if err != nil {
+		return Node{}, err
+	}
+	node.Type, node.Kind = builtinTypeName(t), builtinTypeName(t)
+	for _, alternative := range alternatives {
+		tags, isAny := ctx.outermostTags(alternative.Type, nil)
+		if !isAny && !d.Peek(dynamicTags(tags)...) {
+			continue
+		}
+		value, err := dd.decode(ctx, d, alternative, der.Tag{})
+		if err != nil {
+			return Node{}, err
+		}
+		node.Children = []Node{value}
+		return node, nil
+	}
+	if t.Extensible || ctx.extensibilityImplied || len(t.ExtensionTypes) > 0 {
+		unknown, err := dd.decodeUnknown(d)
+		if err != nil {
+			return Node{}, err
+		}
+		node.Children = []Node{unknown}
+		return node, nil
+	}
+	return Node{}, d.Unexpected("CHOICE")
+}
+
+// dynamicComponent is a component of SEQUENCE or SET to decode.
+type dynamicComponent struct {
+	named NamedComponentType
+	// tags are tags which encoding of the component can start with, or nil if component can have any tag.
+	tags []der.Tag
+	// optional is set if component can be absent.
+	optional bool
+}
+
+// dynamicComponents returns components of SEQUENCE or SET, see berComponents.
+func (ctx *moduleContext) dynamicComponents(components ComponentTypeList, extensions ExtensionAdditions) ([]dynamicComponent, error) {
+	var res []dynamicComponent
+	for i, component := range slices.Concat(components, extensions.Components()) {
+		named, ok := component.(NamedComponentType)
+		if !ok {
+			return nil, fmt.Errorf("COMPONENTS OF is not supported")
+		}
+		tags, isAny := ctx.outermostTags(named.NamedType.Type, nil)
+		c := dynamicComponent{
+			named:    named,
+			optional: named.IsOptional || named.Default != nil || i >= len(components),
+		}
+		if !isAny {
+			c.tags = dynamicTags(tags)
+		}
+		res = append(res, c)
+	}
+	return res, nil
+}
+
+// dynamicTags converts tags for der package.
+func dynamicTags(tags []asn1Tag) []der.Tag {
+	res := make([]der.Tag, 0, len(tags))
+	for _, tag := range tags {
+		res = append(res, tag.der())
+	}
+	return res
+}
+
+// decodeSequence decodes components of SEQUENCE value in order. Absent components are omitted.
+func (dd *dynamicDecoder) decodeSequence(ctx *moduleContext, d *der.Decoder, t SequenceType, tag der.Tag) ([]Node, error) {
+	components, err := ctx.dynamicComponents(t.Components, t.ExtensionAdditions)
+	if err != nil {
+		return nil, err
+	}
+	inner, err := d.BeginConstructed(tag)
+	if err != nil {
+		return nil, err
+	}
+	dec := dd.children()
+	var res []Node
+	for _, c := range components {
+		if c.optional && (!inner.More() || c.tags != nil && !inner.Peek(c.tags...)) {
+			continue
+		}
+		if !inner.More() {
+			return nil, der.StructuralError{Msg: "missing component " + c.named.NamedType.Identifier.Name()}
+		}
+		node, err := dec.decode(ctx, inner, c.named.NamedType, der.Tag{})
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, node)
+	}
+	return dec.end(ctx, inner, res, t.Extensible || len(t.ExtensionAdditions) > 0)
+}
+
+// decodeSet decodes components of SET value, which can be encoded in any order.
+func (dd *dynamicDecoder) decodeSet(ctx *moduleContext, d *der.Decoder, t SetType, tag der.Tag) ([]Node, error) {
+	components, err := ctx.dynamicComponents(t.Components, t.ExtensionAdditions)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range components {
+		if c.tags == nil {
+			return nil, fmt.Errorf("component %v: components of SET should have distinct tags", c.named.NamedType.Identifier)
+		}
+	}
+	inner, err := d.BeginSet(tag)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range components {
+		if !c.optional && !inner.Contains(c.tags...) {
+			return nil, der.StructuralError{Msg: "missing component " + c.named.NamedType.Identifier.Name()}
+		}
+	}
+	dec := dd.children()
+	var res []Node
+	for inner.More() {
+		i := slices.IndexFunc(components, func(c dynamicComponent) bool { return inner.Peek(c.tags...) })
+		if i < 0 {
+			break
+		}
+		node, err := dec.decode(ctx, inner, components[i].named.NamedType, der.Tag{})
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, node)
+	}
+	return dec.end(ctx, inner, res, t.Extensible || len(t.ExtensionAdditions) > 0)
+}
+
+// end verifies that all elements of constructed value were read, and appends unknown elements
+// of extensible types to nodes.
+func (dd *dynamicDecoder) end(ctx *moduleContext, d *der.Decoder, nodes []Node, extensible bool) ([]Node, error) {
+	if !extensible && !ctx.extensibilityImplied {
+		return nodes, d.End()
+	}
+	for d.More() {
+		node, err := dd.decodeUnknown(d)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+// decodeUnknown decodes the next element, which is unknown extension addition, as raw value.
+func (dd *dynamicDecoder) decodeUnknown(d *der.Decoder) (Node, error) {
+	node, err := dd.next(d)
+	if err != nil {
+		return Node{}, err
+	}
+	var raw asn1.RawValue
+	if err := d.ReadRawValue(&raw); err != nil {
+		return Node{}, err
+	}
+	node.Value = raw
+	return node, nil
+}
+
+// decodeElements decodes elements of SEQUENCE OF or SET OF value.
+func (dd *dynamicDecoder) decodeElements(ctx *moduleContext, d *der.Decoder, t Type, set bool, tag der.Tag) ([]Node, error) {
+	begin := d.BeginConstructed
+	if set {
+		begin = d.BeginSetOf
+	}
+	inner, err := begin(tag)
+	if err != nil {
+		return nil, err
+	}
+	dec := dd.children()
+	var res []Node
+	for inner.More() {
+		node, err := dec.decode(ctx, inner, t, der.Tag{})
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, node)
+	}
+	return res, inner.End()
+}
+
+// dynamicInteger returns decoded INTEGER value, together with identifier of the named number if it has one.
+func (ctx *moduleContext) dynamicInteger(v *big.Int, t IntegerType) (any, string) {
+	var res any = v
+	if v.IsInt64() {
+		res = v.Int64()
+	}
+	for _, named := range t.NamedNumberList {
+		value, ok := named.Value.(Value)
+		if !ok {
+			continue
+		}
+		resolved, _, err := ctx.lookupValue(value)
+		if n, ok := resolved.(Number); err == nil && ok && big.NewInt(int64(n)).Cmp(v) == 0 {
+			return res, named.Name.Name()
+		}
+	}
+	return res, ""
+}
+
+// dynamicEnumerationItem returns identifier of ENUMERATED value, or empty string if the value is unknown.
+func (ctx *moduleContext) dynamicEnumerationItem(v int64, t EnumeratedType) string {
+	root, additions, err := ctx.enumerationItems(t)
+	if err != nil {
+		ctx.appendError(err)
+		return ""
+	}
+	for _, item := range append(root, additions...) {
+		if item.value == v {
+			return item.name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,149 @@
+package asn1go
+
+import (
+	"encoding/asn1"
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chemikadze/asn1go/der"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+const dynamicTestModule = `
+	TestSpec DEFINITIONS EXPLICIT TAGS ::= BEGIN
+		Message ::= [APPLICATION 1] SEQUENCE {
+			id       [0] INTEGER { zero(0), answer(42) },
+			color    [1] Color DEFAULT red,
+			name     [2] IA5String OPTIONAL,
+			flags    [3] IMPLICIT SEQUENCE OF BOOLEAN,
+			body     Body,
+			...
+		}
+		Color ::= ENUMERATED { red, green, ..., blue }
+		Body ::= CHOICE {
+			text  UTF8String,
+			data  OCTET STRING,
+			...
+		}
+		Record ::= SET {
+			when   [0] GeneralizedTime,
+			oid    [1] IMPLICIT OBJECT IDENTIFIER,
+			big    [2] IMPLICIT INTEGER OPTIONAL
+		}
+		Shape ::= CHOICE { size INTEGER, round BOOLEAN }
+		Loop ::= Alias
+		Alias ::= Loop
+	END
+`
+
+func decodeHex(t *testing.T, s string) []byte {
+	data, err := hex.DecodeString(strings.ReplaceAll(s, " ", ""))
+	if err != nil {
+		t.Fatalf("Invalid hex %q: %v", s, err)
+	}
+	return data
+}
+
+func TestDynamicDecoding(t *testing.T) {
+	module := parseModule(t, dynamicTestModule)
+	message := decodeHex(t, "61 17 30 15 a0 03 02 01 2a a1 03 0a 01 02 a3 03 01 01 ff 0c 02 68 69 80 00")
+	expected := Node{Type: "Message", Kind: "SEQUENCE", Tag: der.Tag{Class: der.ClassApplication, Number: 1}, Bytes: message, Children: []Node{
+		{Name: "id", Type: "INTEGER", Kind: "INTEGER", Tag: der.Tag{Class: der.ClassContextSpecific, Number: 0}, Offset: 4, Bytes: message[4:9], Value: int64(42), Identifier: "answer"},
+		{Name: "color", Type: "Color", Kind: "ENUMERATED", Tag: der.Tag{Class: der.ClassContextSpecific, Number: 1}, Offset: 9, Bytes: message[9:14], Value: asn1.Enumerated(2), Identifier: "blue"},
+		{Name: "flags", Type: "SEQUENCE OF", Kind: "SEQUENCE OF", Tag: der.Tag{Class: der.ClassContextSpecific, Number: 3}, Offset: 14, Bytes: message[14:19], Children: []Node{
+			{Type: "BOOLEAN", Kind: "BOOLEAN", Tag: der.TagBoolean, Offset: 16, Bytes: message[16:19], Value: true},
+		}},
+		{Name: "body", Type: "Body", Kind: "CHOICE", Tag: der.TagUTF8String, Offset: 19, Bytes: message[19:23], Children: []Node{
+			{Name: "text", Type: "UTF8String", Kind: "UTF8String", Tag: der.TagUTF8String, Offset: 19, Bytes: message[19:23], Value: "hi"},
+		}},
+		{Tag: der.Tag{Class: der.ClassContextSpecific, Number: 0}, Offset: 23, Bytes: message[23:25], Value: asn1.RawValue{
+			Class: asn1.ClassContextSpecific, Bytes: []byte{}, FullBytes: message[23:25],
+		}},
+	}}
+	node, err := Decode(module, "Message", message)
+	if err != nil {
+		t.Fatalf("Failed to decode: %v", err)
+	}
+	if diff := cmp.Diff(expected, node, cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("Decoded node mismatch (-want +got):\n%v", diff)
+	}
+	if id, ok := node.Child("id"); !ok || id.Value != int64(42) {
+		t.Errorf("Expected component id to be 42, got %+v", id)
+	}
+	if _, ok := node.Child("name"); ok {
+		t.Errorf("Expected absent component to be omitted")
+	}
+}
+
+func TestDynamicDecodingSet(t *testing.T) {
+	module := parseModule(t, dynamicTestModule)
+	// components are encoded in reverse order
+	record := decodeHex(t, "31 22 82 09 01 00 00 00 00 00 00 00 00 81 02 2a 03 a0 11 18 0f 32 30 32 34 30 31 30 32 30 33 30 34 30 35 5a")
+	node, err := Decode(module, "Record", record)
+	if err != nil {
+		t.Fatalf("Failed to decode: %v", err)
+	}
+	var names []string
+	for _, child := range node.Children {
+		names = append(names, child.Name)
+	}
+	if diff := cmp.Diff([]string{"big", "oid", "when"}, names); diff != "" {
+		t.Errorf("Component names mismatch (-want +got):\n%v", diff)
+	}
+	expected := new(big.Int).Lsh(big.NewInt(1), 64)
+	if v, ok := node.Children[0].Value.(*big.Int); !ok || v.Cmp(expected) != 0 {
+		t.Errorf("Expected big integer %v, got %#v", expected, node.Children[0].Value)
+	}
+	if v := node.Children[1].Value; !cmp.Equal(v, asn1.ObjectIdentifier{1, 2, 3}) {
+		t.Errorf("Expected object identifier 1.2.3, got %#v", v)
+	}
+	if v := node.Children[2]; v.Type != GeneralizedTimeName || !cmp.Equal(v.Value, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("Expected GeneralizedTime value, got %+v", v)
+	}
+	if _, err := DecodeWithParams(module, "Record", record, DecodeParams{Strict: true}); err == nil {
+		t.Errorf("Expected unsorted SET components to be rejected in strict mode")
+	}
+}
+
+func TestDynamicDecodingErrors(t *testing.T) {
+	module := parseModule(t, dynamicTestModule)
+	testCases := []struct {
+		name     string
+		typeName string
+		data     string
+		err      string
+	}{
+		{name: "unknown type", typeName: "Missing", data: "0500", err: "can not resolve TypeReference Missing"},
+		{name: "reference cycle", typeName: "Loop", data: "0500", err: "reference cycle"},
+		{name: "unexpected tag", typeName: "Message", data: "3000", err: "tag"},
+		{name: "missing component of SEQUENCE", typeName: "Message", data: "6109 3007 a003020100 a300", err: "missing component body"},
+		{name: "missing component of SET", typeName: "Record", data: "3103 830100", err: "missing component when"},
+		{name: "unknown component of SET", typeName: "Record", data: "3119 a011 180f 32303234303130323033303430355a 81022a03 8300", err: "unexpected trailing element with tag [3]"},
+		{name: "unknown alternative", typeName: "Shape", data: "0400", err: "unexpected tag"},
+		{name: "trailing data", typeName: "Body", data: "0400 00", err: "unexpected 1 bytes after the value"},
+		{name: "truncated data", typeName: "Body", data: "0402 00", err: ""},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Decode(module, tc.typeName, decodeHex(t, tc.data))
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("Expected error containing %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestDynamicDecodingUnknownAlternative(t *testing.T) {
+	module := parseModule(t, dynamicTestModule)
+	node, err := Decode(module, "Body", decodeHex(t, "0101ff"))
+	if err != nil {
+		t.Fatalf("Failed to decode: %v", err)
+	}
+	if len(node.Children) != 1 || node.Children[0].Name != "" || node.Children[0].Tag != der.TagBoolean {
+		t.Errorf("Expected unknown alternative to be decoded as raw value, got %+v", node)
+	}
+}
@@ -0,0 +1,72 @@
+package examples
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+//go:generate go run ../cmd/asn1go/main.go -package examples automatic.asn1 automatic_generated.go
+
+func TestAutomaticTagsEncoding(t *testing.T) {
+	testCases := []struct {
+		name     string
+		encoded  []byte
+		value    any // should be pointer
+		expected any // should be value
+	}{
+		{
+			name: "sequence with extension additions",
+			encoded: []byte{
+				0x30, 0x0f,
+				0x80, 0x01, 0x01, // id [0]
+				0x81, 0x02, 'a', 'b', // name [1]
+				0xa2, 0x03, 0x80, 0x01, 0xff, // attributes [2] { critical [0] }
+				0x83, 0x01, 0x02, // revision [3]
+			},
+			value: &Record{},
+			expected: Record{Id: 1, Name: "ab", Attributes: struct {
+				Critical bool `asn1:"tag:0"`
+			}{Critical: true}, Revision: 2},
+		},
+		{
+			name: "choice is tagged explicitly",
+			encoded: []byte{
+				0x30, 0x09,
+				0x80, 0x01, 0x06, // code [0]
+				0xa1, 0x04, 0x81, 0x02, 'h', 'i', // value [1] { text [1] }
+			},
+			value: &Item{},
+			// encoding/asn1 does not strip explicit tag of RawValue fields
+			expected: Item{Code: 6, Value: asn1.RawValue{
+				Class: asn1.ClassContextSpecific, Tag: 1, IsCompound: true,
+				Bytes:     []byte{0x81, 0x02, 'h', 'i'},
+				FullBytes: []byte{0xa1, 0x04, 0x81, 0x02, 'h', 'i'},
+			}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rest, err := asn1.Unmarshal(tc.encoded, tc.value)
+			if err != nil {
+				t.Fatalf("Failed to unmarshal: %v", err)
+			}
+			if len(rest) != 0 {
+				t.Errorf("Expected no trailing data, got %v bytes", len(rest))
+			}
+			if diff := cmp.Diff(tc.expected, reflect.ValueOf(tc.value).Elem().Interface()); diff != "" {
+				t.Errorf("Unmarshaled value did not match expected, diff (-want, +got):\n%v", diff)
+			}
+			encoded, err := asn1.Marshal(tc.expected)
+			if err != nil {
+				t.Fatalf("Failed to marshal: %v", err)
+			}
+			if !bytes.Equal(tc.encoded, encoded) {
+				t.Errorf("Encoding did not match expected:\n exp: %x\n got: %x", tc.encoded, encoded)
+			}
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package examples
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"fmt"
+	"testing"
+
+	"github.com/chemikadze/asn1go/der"
+)
+
+//go:generate go run ../cmd/asn1go/main.go -der -package examples ber.asn1 ber_generated.go
+
+func TestBERDecoding(t *testing.T) {
+	testCases := []struct {
+		name     string
+		encoded  []byte
+		value    der.Unmarshaler
+		expected any
+		// strictErr is set if encoding is valid BER, but not valid DER.
+		strictErr bool
+	}{
+		{
+			name:     "absent optional and default components",
+			encoded:  []byte{0x30, 0x03, 0x02, 0x01, 0x05},
+			value:    new(Entry),
+			expected: Entry{Id: 5, Version: 1},
+		},
+		{
+			name:     "extension addition",
+			encoded:  []byte{0x30, 0x0a, 0x02, 0x01, 0x05, 0x81, 0x01, 0x02, 0x82, 0x02, 'o', 'k'},
+			value:    new(Entry),
+			expected: Entry{Id: 5, Version: 2, Comment: "ok"},
+		},
+		{
+			name:     "unknown extension additions are skipped",
+			encoded:  []byte{0x30, 0x0d, 0x02, 0x01, 0x05, 0x82, 0x02, 'o', 'k', 0xa3, 0x02, 0x05, 0x00, 0x84, 0x00},
+			value:    new(Entry),
+			expected: Entry{Id: 5, Version: 1, Comment: "ok"},
+		},
+		{
+			name:      "encoded default value",
+			encoded:   []byte{0x30, 0x06, 0x02, 0x01, 0x05, 0x81, 0x01, 0x01},
+			value:     new(Entry),
+			expected:  Entry{Id: 5, Version: 1},
+			strictErr: true,
+		},
+		{
+			name:      "non-minimal length",
+			encoded:   []byte{0x30, 0x81, 0x03, 0x02, 0x01, 0x05},
+			value:     new(Entry),
+			expected:  Entry{Id: 5, Version: 1},
+			strictErr: true,
+		},
+		{
+			name:      "constructed string with indefinite length",
+			encoded:   []byte{0x30, 0x80, 0x02, 0x01, 0x05, 0xa0, 0x80, 0x04, 0x02, 'h', 'i', 0x04, 0x01, '!', 0x00, 0x00, 0x00, 0x00},
+			value:     new(Entry),
+			expected:  Entry{Id: 5, Name: ptr("hi!"), Version: 1},
+			strictErr: true,
+		},
+		{
+			name:     "set components in canonical order",
+			encoded:  []byte{0x31, 0x09, 0x80, 0x01, 0x02, 0x81, 0x01, 'a', 0x82, 0x01, 0x00},
+			value:    new(Attributes),
+			expected: Attributes{Size: 2, Label: []byte("a"), Enabled: false},
+		},
+		{
+			name:      "set components in any order",
+			encoded:   []byte{0x31, 0x06, 0x81, 0x01, 'a', 0x80, 0x01, 0x02},
+			value:     new(Attributes),
+			expected:  Attributes{Size: 2, Label: []byte("a"), Enabled: true},
+			strictErr: true,
+		},
+		{
+			name:      "unsorted set of",
+			encoded:   []byte{0x31, 0x0b, 0x80, 0x01, 0x02, 0xa3, 0x06, 0x04, 0x01, 'b', 0x04, 0x01, 'a'},
+			value:     new(Attributes),
+			expected:  Attributes{Size: 2, Enabled: true, Aliases: [][]byte{[]byte("b"), []byte("a")}},
+			strictErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rest, err := der.UnmarshalBER(tc.encoded, tc.value)
+			if err != nil {
+				t.Fatalf("Failed to unmarshal: %v", err)
+			}
+			if len(rest) != 0 {
+				t.Errorf("Expected no trailing data, got %x", rest)
+			}
+			if es, ps := "&"+repr(tc.expected), repr(tc.value); es != ps {
+				t.Errorf("Repr mismatch:\n exp: %v\n got: %v", es, ps)
+			}
+			_, err = der.UnmarshalDER(tc.encoded, tc.value)
+			if tc.strictErr && err == nil {
+				t.Errorf("Expected strict decoding to fail")
+			} else if !tc.strictErr && err != nil {
+				t.Errorf("Failed to unmarshal DER: %v", err)
+			}
+		})
+	}
+}
+
+func TestBERDecodingErrors(t *testing.T) {
+	testCases := []struct {
+		name    string
+		encoded []byte
+		value   der.Unmarshaler
+	}{
+		{
+			name:    "missing mandatory component",
+			encoded: []byte{0x30, 0x03, 0x80, 0x01, 'a'},
+			value:   new(Entry),
+		},
+		{
+			name:    "unknown component of non-extensible type",
+			encoded: []byte{0x31, 0x05, 0x80, 0x01, 0x02, 0x84, 0x00},
+			value:   new(Attributes),
+		},
+		{
+			name:    "missing mandatory set component",
+			encoded: []byte{0x31, 0x03, 0x81, 0x01, 'a'},
+			value:   new(Attributes),
+		},
+		{
+			name:    "duplicate set component",
+			encoded: []byte{0x31, 0x06, 0x80, 0x01, 0x02, 0x80, 0x01, 0x03},
+			value:   new(Attributes),
+		},
+		{
+			name:    "truncated contents",
+			encoded: []byte{0x30, 0x05, 0x02, 0x01, 0x05},
+			value:   new(Entry),
+		},
+		{
+			name:    "missing end-of-contents",
+			encoded: []byte{0x30, 0x80, 0x02, 0x01, 0x05},
+			value:   new(Entry),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := der.UnmarshalBER(tc.encoded, tc.value); err == nil {
+				t.Errorf("Expected error, got %+v", tc.value)
+			}
+		})
+	}
+}
+
+func TestBERRoundTrip(t *testing.T) {
+	entry := Entry{Id: 5, Name: ptr("name"), Version: 1, Comment: "comment"}
+	encoded, err := entry.MarshalDER()
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	var decoded Entry
+	if _, err := der.UnmarshalDER(encoded, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+	if es, ps := repr(entry), repr(decoded); es != ps {
+		t.Errorf("Repr mismatch:\n exp: %v\n got: %v", es, ps)
+	}
+}
+
+func TestNamedBits(t *testing.T) {
+	var grant Grant
+	grant.Usage.Set(KeyUsageBitKeyCertSign)
+	grant.Usage.Set(KeyUsageBitDigitalSignature)
+	length := grant.Usage.BitLength
+	grant.Usage.Set(9)
+	grant.Usage.Clear(9)
+	if grant.Usage.BitLength != length {
+		t.Errorf("Expected trailing zero bits to be removed by Clear, got bit length %v, want %v", grant.Usage.BitLength, length)
+	}
+	grant.Mode = new(GrantMode)
+	grant.Mode.Set(GrantModeBitRead)
+	if !grant.Usage.Has(KeyUsageBitKeyCertSign) || grant.Usage.Has(KeyUsageBitKeyEncipherment) {
+		t.Errorf("Unexpected bits %+v", grant.Usage)
+	}
+	// trailing zero bits are not encoded, see X.680, section 22.7
+	expected := []byte{0x30, 0x08, 0x03, 0x02, 0x02, 0x84, 0x80, 0x02, 0x07, 0x80}
+	encoded, err := grant.MarshalDER()
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	if !bytes.Equal(encoded, expected) {
+		t.Errorf("Encoding mismatch:\n exp: %x\n got: %x", expected, encoded)
+	}
+	var decoded Grant
+	if _, err := der.UnmarshalDER(encoded, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+	if names := fmt.Sprint(decoded.Usage.Names()); names != "[digitalSignature keyCertSign]" {
+		t.Errorf("Unexpected names of bits: %v", names)
+	}
+	if decoded.Usage.BitLength != 6 {
+		t.Errorf("Expected 6 bits to be decoded, got %v", decoded.Usage.BitLength)
+	}
+}
+
+func TestOptionalAndDefaultValues(t *testing.T) {
+	value := Options{
+		Verbose: ptr(false),
+		Retries: ptr[int64](0),
+		Label:   []byte{0x01, 0x02},
+		// trailing zero bits do not make a difference for named bits
+		Mode:   OptionsMode{Bytes: []byte{0x80, 0x00}, BitLength: 9},
+		Policy: asn1.ObjectIdentifier{1, 2, 3},
+		Digest: []byte{},
+		Note:   ptr(""),
+	}
+	// present zero values are encoded, DEFAULT values are not
+	expected := []byte{0x30, 0x0a, 0x80, 0x01, 0x00, 0x81, 0x01, 0x00, 0x85, 0x00, 0x86, 0x00}
+	encoded, err := value.MarshalDER()
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	if !bytes.Equal(encoded, expected) {
+		t.Errorf("Encoding mismatch:\n exp: %x\n got: %x", expected, encoded)
+	}
+	var decoded Options
+	if _, err := der.UnmarshalDER(encoded, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+	value.Mode = OptionsMode{Bytes: []byte{0x80}, BitLength: 1}
+	if es, ps := repr(value), repr(decoded); es != ps {
+		t.Errorf("Repr mismatch:\n exp: %v\n got: %v", es, ps)
+	}
+	if decoded.Digest == nil {
+		t.Errorf("Expected present empty OCTET STRING not to be nil")
+	}
+	// absent components of reused value are reset
+	if _, err := der.UnmarshalDER([]byte{0x30, 0x00}, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+	if decoded.Verbose != nil || decoded.Retries != nil || decoded.Digest != nil || decoded.Note != nil {
+		t.Errorf("Expected absent components to be nil, got %v", repr(decoded))
+	}
+	for _, encoded := range [][]byte{
+		{0x30, 0x04, 0x82, 0x02, 0x01, 0x02},
+		{0x30, 0x04, 0x83, 0x02, 0x07, 0x80},
+		{0x30, 0x04, 0x84, 0x02, 0x2a, 0x03},
+	} {
+		if _, err := der.UnmarshalDER(encoded, new(Options)); err == nil {
+			t.Errorf("Expected encoded DEFAULT value %x to be rejected", encoded)
+		}
+	}
+}
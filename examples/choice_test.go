@@ -0,0 +1,85 @@
+package examples
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+//go:generate go run ../cmd/asn1go/main.go -der -package examples choice.asn1 choice_generated.go
+
+func TestChoiceEncoding(t *testing.T) {
+	testCases := []struct {
+		name    string
+		value   Shape
+		encoded []byte
+	}{
+		{
+			name:    "implicitly tagged sequence",
+			value:   ShapeCircle{Value: Circle{Radius: 2}},
+			encoded: []byte{0xa0, 0x03, 0x02, 0x01, 0x02},
+		},
+		{
+			name:    "implicitly tagged integer",
+			value:   ShapeSquare{Value: 5},
+			encoded: []byte{0x81, 0x01, 0x05},
+		},
+		{
+			name:    "untagged string",
+			value:   ShapeLabel{Value: "hi"},
+			encoded: []byte{0x0c, 0x02, 'h', 'i'},
+		},
+		{
+			name:    "choice is tagged explicitly",
+			value:   ShapeColor{Value: ColorRgb{Value: []byte{0x01, 0x02}}},
+			encoded: []byte{0xa2, 0x04, 0x04, 0x02, 0x01, 0x02},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			encoded, err := MarshalShape(tc.value)
+			if err != nil {
+				t.Fatalf("Failed to marshal: %v", err)
+			}
+			if !bytes.Equal(encoded, tc.encoded) {
+				t.Errorf("Marshalled bytes did not match expected:\nwant %x\ngot  %x", tc.encoded, encoded)
+			}
+			derEncoded, err := tc.value.MarshalDER()
+			if err != nil {
+				t.Fatalf("Failed to marshal DER: %v", err)
+			}
+			if !bytes.Equal(derEncoded, tc.encoded) {
+				t.Errorf("DER bytes did not match expected:\nwant %x\ngot  %x", tc.encoded, derEncoded)
+			}
+			decoded, rest, err := UnmarshalShape(append(tc.encoded, 0xff))
+			if err != nil {
+				t.Fatalf("Failed to unmarshal: %v", err)
+			}
+			if !bytes.Equal(rest, []byte{0xff}) {
+				t.Errorf("Expected trailing data to be returned, got %x", rest)
+			}
+			if fmt.Sprintf("%#v", decoded) != fmt.Sprintf("%#v", tc.value) {
+				t.Errorf("Unmarshalled value did not match expected:\nwant %#v\ngot  %#v", tc.value, decoded)
+			}
+			berDecoded, rest, err := UnmarshalBERShape(append(tc.encoded, 0xff))
+			if err != nil {
+				t.Fatalf("Failed to unmarshal BER: %v", err)
+			}
+			if !bytes.Equal(rest, []byte{0xff}) {
+				t.Errorf("Expected trailing data to be returned, got %x", rest)
+			}
+			if fmt.Sprintf("%#v", berDecoded) != fmt.Sprintf("%#v", tc.value) {
+				t.Errorf("BER decoded value did not match expected:\nwant %#v\ngot  %#v", tc.value, berDecoded)
+			}
+		})
+	}
+}
+
+func TestChoiceUnknownTag(t *testing.T) {
+	if _, _, err := UnmarshalShape([]byte{0x83, 0x01, 0x00}); err == nil {
+		t.Errorf("Expected error for unknown alternative")
+	}
+	if _, _, err := UnmarshalBERShape([]byte{0x83, 0x01, 0x00}); err == nil {
+		t.Errorf("Expected BER decoding error for unknown alternative")
+	}
+}
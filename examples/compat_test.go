@@ -0,0 +1,84 @@
+package examples
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+//go:generate go run ../cmd/asn1go/main.go -default-integer-repr auto -package examples compat.asn1 compat_generated.go
+
+func TestEncodingAsn1Compatibility(t *testing.T) {
+	testCases := []struct {
+		name     string
+		encoded  []byte
+		value    any // should be pointer
+		expected any // should be value
+	}{
+		{
+			name: "enumerated and named bits",
+			encoded: []byte{
+				0x30, 0x10,
+				0x02, 0x01, 0x07, // id
+				0x02, 0x01, 0x03, // level
+				0x0a, 0x01, 0x33, // state
+				0x0a, 0x01, 0x01, // reason
+				0x03, 0x02, 0x06, 0x40, // permissions { read }
+			},
+			value: &Account{},
+			expected: Account{
+				Id: 7, Level: 3, State: AccountStateValSuspended, Reason: 1,
+				Permissions: asn1.BitString{Bytes: []byte{0x40}, BitLength: 2},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rest, err := asn1.Unmarshal(tc.encoded, tc.value)
+			if err != nil {
+				t.Fatalf("Failed to unmarshal: %v", err)
+			}
+			if len(rest) != 0 {
+				t.Errorf("Expected no trailing data, got %v bytes", len(rest))
+			}
+			if diff := cmp.Diff(tc.expected, reflect.ValueOf(tc.value).Elem().Interface()); diff != "" {
+				t.Errorf("Unmarshaled value did not match expected, diff (-want, +got):\n%v", diff)
+			}
+			encoded, err := asn1.Marshal(tc.expected)
+			if err != nil {
+				t.Fatalf("Failed to marshal: %v", err)
+			}
+			if !bytes.Equal(tc.encoded, encoded) {
+				t.Errorf("Encoding did not match expected:\n exp: %x\n got: %x", tc.encoded, encoded)
+			}
+		})
+	}
+}
+
+func TestEncodingAsn1NamedBitsHelpers(t *testing.T) {
+	var permissions Permissions
+	PermissionsSet(&permissions, PermissionsBitWrite)
+	length := permissions.BitLength
+	PermissionsSet(&permissions, PermissionsBitDelete)
+	if diff := cmp.Diff([]string{"write", "delete"}, PermissionsNames(permissions)); diff != "" {
+		t.Errorf("Names did not match expected, diff (-want, +got):\n%v", diff)
+	}
+	PermissionsClear(&permissions, PermissionsBitDelete)
+	if PermissionsHas(permissions, PermissionsBitDelete) || !PermissionsHas(permissions, PermissionsBitWrite) {
+		t.Errorf("Expected only write bit to be set, got %v", PermissionsNames(permissions))
+	}
+	if permissions.BitLength != length {
+		t.Errorf("Expected trailing zero bits to be removed by Clear, got bit length %v, want %v", permissions.BitLength, length)
+	}
+	encoded, err := asn1.Marshal(permissions)
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	// trailing zero bits are removed as required by DER
+	if expected := []byte{0x03, 0x02, 0x06, 0x40}; !bytes.Equal(expected, encoded) {
+		t.Errorf("Encoding did not match expected:\n exp: %x\n got: %x", expected, encoded)
+	}
+}
@@ -0,0 +1,152 @@
+package examples
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/chemikadze/asn1go/der"
+	"github.com/chemikadze/asn1go/oer"
+	"github.com/chemikadze/asn1go/per"
+	"github.com/chemikadze/asn1go/xer"
+)
+
+//go:generate go run ../cmd/asn1go/main.go -der -per -oer -jer -xer -validate -package examples contents.asn1 contents_generated.go
+
+func TestContainedTypes(t *testing.T) {
+	var e Envelope
+	var (
+		_ Property   = e.Content
+		_ []Property = e.Properties
+		_ *int64     = e.Counter
+		_ []byte     = e.Digest
+	)
+}
+
+func TestContainedEncodings(t *testing.T) {
+	value := Envelope{
+		Version:    1,
+		Serial:     "0A1B",
+		Content:    Property{Type: "cn", Value: "Jane"},
+		Properties: []Property{{Type: "uid", Value: "jane"}, {Type: "mail", Value: "jane@example.com"}},
+		Counter:    ptr[int64](42),
+		Digest:     []byte{1, 2, 3, 4},
+	}
+	if err := value.Validate(); err != nil {
+		t.Fatalf("Expected value to be valid, got %v", err)
+	}
+
+	derBytes, err := value.MarshalDER()
+	if err != nil {
+		t.Fatalf("Failed to marshal DER: %v", err)
+	}
+	content, err := value.Content.MarshalDER()
+	if err != nil {
+		t.Fatalf("Failed to marshal DER of content: %v", err)
+	}
+	// content is encoded as primitive [2] IMPLICIT OCTET STRING holding encoding of Property
+	if !bytes.Contains(derBytes, append([]byte{0x82, byte(len(content))}, content...)) {
+		t.Errorf("Expected DER encoding %x to contain encoding of content %x", derBytes, content)
+	}
+	var fromDER Envelope
+	if _, err := der.UnmarshalDER(derBytes, &fromDER); err != nil {
+		t.Fatalf("Failed to unmarshal DER: %v", err)
+	}
+	perBytes, err := value.MarshalPER(per.Aligned)
+	if err != nil {
+		t.Fatalf("Failed to marshal PER: %v", err)
+	}
+	var fromPER Envelope
+	if err := fromPER.UnmarshalPER(perBytes, per.Aligned); err != nil {
+		t.Fatalf("Failed to unmarshal PER: %v", err)
+	}
+	oerBytes, err := value.MarshalOER()
+	if err != nil {
+		t.Fatalf("Failed to marshal OER: %v", err)
+	}
+	var fromOER Envelope
+	if _, err := oer.UnmarshalCanonical(oerBytes, &fromOER); err != nil {
+		t.Fatalf("Failed to unmarshal OER: %v", err)
+	}
+	jerBytes, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("Failed to marshal JER: %v", err)
+	}
+	var fromJER Envelope
+	if err := json.Unmarshal(jerBytes, &fromJER); err != nil {
+		t.Fatalf("Failed to unmarshal JER: %v", err)
+	}
+	xerBytes, err := value.MarshalXER()
+	if err != nil {
+		t.Fatalf("Failed to marshal XER: %v", err)
+	}
+	var fromXER Envelope
+	if err := xer.Unmarshal(xerBytes, "Envelope", &fromXER); err != nil {
+		t.Fatalf("Failed to unmarshal XER: %v", err)
+	}
+	for name, decoded := range map[string]Envelope{"DER": fromDER, "PER": fromPER, "OER": fromOER, "JER": fromJER, "XER": fromXER} {
+		if !reflect.DeepEqual(decoded, value) {
+			t.Errorf("%v round trip mismatch:\n exp: %+v\n got: %+v", name, value, decoded)
+		}
+	}
+}
+
+func TestContainedTrailingData(t *testing.T) {
+	value := Envelope{Serial: "00", Content: Property{Type: "cn", Value: "Jane"}, Digest: []byte{1, 2, 3, 4}}
+	derBytes, err := value.MarshalDER()
+	if err != nil {
+		t.Fatalf("Failed to marshal DER: %v", err)
+	}
+	content, err := value.Content.MarshalDER()
+	if err != nil {
+		t.Fatalf("Failed to marshal DER of content: %v", err)
+	}
+	// append NULL to contents of the content OCTET STRING
+	patched := bytes.Replace(derBytes, append([]byte{0x82, byte(len(content))}, content...),
+		append(append([]byte{0x82, byte(len(content) + 2)}, content...), 0x05, 0x00), 1)
+	patched[1] += 2
+	var decoded Envelope
+	if _, err := der.UnmarshalDER(patched, &decoded); err == nil {
+		t.Errorf("Expected error for trailing data after contained value")
+	}
+}
+
+func TestPermittedAlphabetAndPattern(t *testing.T) {
+	valid := Envelope{Serial: "0A1B", Content: Property{Type: "cn", Value: "Jane"}, Digest: []byte{1, 2, 3, 4}}
+	testCases := []struct {
+		name     string
+		modify   func(v *Envelope)
+		expected string
+	}{
+		{
+			name:     "character outside of permitted alphabet",
+			modify:   func(v *Envelope) { v.Content.Type = "c_n" },
+			expected: "Content.Type: character '_' is not permitted by constraint",
+		},
+		{
+			name:     "contained element",
+			modify:   func(v *Envelope) { v.Properties = []Property{{Type: "", Value: "x"}} },
+			expected: "Properties[0].Type: size 0 is not permitted by constraint",
+		},
+		{
+			name:     "pattern mismatch",
+			modify:   func(v *Envelope) { v.Serial = "0a1b" },
+			expected: `Serial: value "0a1b" does not match pattern`,
+		},
+		{
+			name:     "pattern quantifier",
+			modify:   func(v *Envelope) { v.Serial = "0123456789" },
+			expected: `Serial: value "0123456789" does not match pattern`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			value := valid
+			tc.modify(&value)
+			if err := value.Validate(); err == nil || err.Error() != tc.expected {
+				t.Errorf("Expected error %q, got %v", tc.expected, err)
+			}
+		})
+	}
+}
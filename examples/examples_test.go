@@ -2,11 +2,64 @@ package examples
 
 import (
 	"bytes"
+	"fmt"
 	"github.com/chemikadze/asn1go"
 	"os"
+	"reflect"
+	"strings"
 	"testing"
 )
 
+// ptr returns pointer to v, which is used for values of OPTIONAL components represented with pointers.
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// repr returns string representation of v, same as fmt with %+v verb, but with values of nested pointers
+// instead of their addresses, so that values with pointers to OPTIONAL components can be compared.
+// Unlike comparison with ==, it distinguishes negative zero, and treats NaN values as equal.
+func repr(v any) string {
+	return reprValue(reflect.ValueOf(v))
+}
+
+func reprValue(v reflect.Value) string {
+	if !v.IsValid() {
+		return "<nil>"
+	}
+	if _, ok := v.Interface().(fmt.Stringer); ok {
+		return fmt.Sprintf("%+v", v.Interface())
+	}
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return "<nil>"
+		}
+		return "&" + reprValue(v.Elem())
+	case reflect.Interface:
+		return reprValue(v.Elem())
+	case reflect.Struct:
+		fields := make([]string, 0, v.NumField())
+		for i := 0; i < v.NumField(); i++ {
+			if !v.Type().Field(i).IsExported() {
+				return fmt.Sprintf("%+v", v.Interface())
+			}
+			fields = append(fields, v.Type().Field(i).Name+":"+reprValue(v.Field(i)))
+		}
+		return "{" + strings.Join(fields, " ") + "}"
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return fmt.Sprintf("%+v", v.Interface())
+		}
+		elems := make([]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			elems = append(elems, reprValue(v.Index(i)))
+		}
+		return "[" + strings.Join(elems, " ") + "]"
+	default:
+		return fmt.Sprintf("%+v", v.Interface())
+	}
+}
+
 func testExampleParsing(t *testing.T, filename string) *asn1go.ModuleDefinition {
 	content, err := os.ReadFile(filename)
 	if err != nil {
@@ -0,0 +1,131 @@
+package examples
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/chemikadze/asn1go/der"
+	"github.com/chemikadze/asn1go/oer"
+	"github.com/chemikadze/asn1go/per"
+	"github.com/chemikadze/asn1go/xer"
+)
+
+//go:generate go run ../cmd/asn1go/main.go -der -per -oer -jer -xer -validate -default-integer-repr auto -package examples integers.asn1 integers_generated.go
+
+func TestIntegerTypes(t *testing.T) {
+	var m Measurement
+	var (
+		_ int8     = m.Offset
+		_ uint64   = m.Sequence
+		_ *big.Int = m.Total
+	)
+	// constrained types are defined types, with go types selected by constraints as underlying types
+	for _, tc := range []struct {
+		t    reflect.Type
+		kind reflect.Kind
+	}{
+		{reflect.TypeOf(m.Sensor), reflect.Uint32},
+		{reflect.TypeOf(m.Temperature), reflect.Int16},
+		{reflect.TypeOf(m.Humidity).Elem(), reflect.Uint8},
+	} {
+		if tc.t.Kind() != tc.kind {
+			t.Errorf("Expected %v to be %v, got %v", tc.t, tc.kind, tc.t.Kind())
+		}
+	}
+}
+
+func TestConstrainedTypeValidation(t *testing.T) {
+	if err := Percent(100).Validate(); err != nil {
+		t.Errorf("Expected value to be valid, got %v", err)
+	}
+	if err := Percent(101).Validate(); err == nil || err.Error() != "value 101 is not permitted by constraint" {
+		t.Errorf("Expected error for value out of range, got %v", err)
+	}
+	if err := Temperature(-274).Validate(); err == nil {
+		t.Errorf("Expected error for value out of range")
+	}
+}
+
+func TestIntegerEncodings(t *testing.T) {
+	value := Measurement{
+		Sensor:      math.MaxUint32,
+		Temperature: -40,
+		Humidity:    ptr[Percent](55),
+		Offset:      -3,
+		Sequence:    math.MaxInt64,
+		Total:       big.NewInt(1 << 40),
+	}
+	if err := value.Validate(); err != nil {
+		t.Fatalf("Expected value to be valid, got %v", err)
+	}
+
+	derBytes, err := value.MarshalDER()
+	if err != nil {
+		t.Fatalf("Failed to marshal DER: %v", err)
+	}
+	var fromDER Measurement
+	if _, err := der.UnmarshalDER(derBytes, &fromDER); err != nil {
+		t.Fatalf("Failed to unmarshal DER: %v", err)
+	}
+	perBytes, err := value.MarshalPER(per.Aligned)
+	if err != nil {
+		t.Fatalf("Failed to marshal PER: %v", err)
+	}
+	var fromPER Measurement
+	if err := fromPER.UnmarshalPER(perBytes, per.Aligned); err != nil {
+		t.Fatalf("Failed to unmarshal PER: %v", err)
+	}
+	oerBytes, err := value.MarshalOER()
+	if err != nil {
+		t.Fatalf("Failed to marshal OER: %v", err)
+	}
+	var fromOER Measurement
+	if _, err := oer.UnmarshalCanonical(oerBytes, &fromOER); err != nil {
+		t.Fatalf("Failed to unmarshal OER: %v", err)
+	}
+	jerBytes, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("Failed to marshal JER: %v", err)
+	}
+	var fromJER Measurement
+	if err := json.Unmarshal(jerBytes, &fromJER); err != nil {
+		t.Fatalf("Failed to unmarshal JER: %v", err)
+	}
+	xerBytes, err := value.MarshalXER()
+	if err != nil {
+		t.Fatalf("Failed to marshal XER: %v", err)
+	}
+	var fromXER Measurement
+	if err := xer.Unmarshal(xerBytes, "Measurement", &fromXER); err != nil {
+		t.Fatalf("Failed to unmarshal XER: %v", err)
+	}
+	for name, decoded := range map[string]Measurement{"DER": fromDER, "PER": fromPER, "OER": fromOER, "JER": fromJER, "XER": fromXER} {
+		if decoded.Sensor != value.Sensor || decoded.Temperature != value.Temperature || *decoded.Humidity != *value.Humidity ||
+			decoded.Offset != value.Offset || decoded.Sequence != value.Sequence || decoded.Total.Cmp(value.Total) != 0 {
+			t.Errorf("%v round trip mismatch:\n exp: %+v\n got: %+v", name, value, decoded)
+		}
+	}
+}
+
+func TestIntegerOverflow(t *testing.T) {
+	value := Measurement{Sensor: 1, Humidity: ptr[Percent](101), Total: big.NewInt(0)}
+	if err := value.Validate(); err == nil || err.Error() != "Humidity: value 101 is not permitted by constraint" {
+		t.Errorf("Expected error about humidity, got %v", err)
+	}
+	derBytes, err := value.MarshalDER()
+	if err != nil {
+		t.Fatalf("Failed to marshal DER: %v", err)
+	}
+	// replace humidity with value which does not fit into uint8
+	patched := bytes.Replace(derBytes, []byte{0x82, 0x01, 101}, []byte{0x82, 0x02, 0x01, 0x00}, 1)
+	patched[1]++
+	var decoded Measurement
+	if _, err := der.UnmarshalDER(patched, &decoded); err == nil || !strings.Contains(err.Error(), "INTEGER value 256 overflows uint8") {
+		t.Errorf("Expected overflow error, got %v", err)
+	}
+}
@@ -0,0 +1,154 @@
+package examples
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chemikadze/asn1go/der"
+)
+
+//go:generate go run ../cmd/asn1go/main.go -der -jer -package examples jer.asn1 jer_generated.go
+
+var report = Report{
+	Id:       7,
+	Status:   10,
+	Flags:    asn1.BitString{Bytes: []byte{0xa0}, BitLength: 4},
+	Mask:     asn1.BitString{Bytes: []byte{0xf0}, BitLength: 5},
+	Digest:   []byte{0xde, 0xad},
+	Value:    1.5,
+	Origin:   asn1.ObjectIdentifier{1, 2, 840, 113549},
+	Observed: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	Source:   SourceRemote{Value: Remote{Host: "gw", Port: 8080}},
+	Tags:     []string{"a", "b"},
+	Enabled:  true,
+	Priority: ptr[int64](3),
+}
+
+func TestJEREncoding(t *testing.T) {
+	expected := `{"id":7,"status":"failed","flags":"A0","mask":{"value":"F0","length":5},"digest":"DEAD","value":1.5,` +
+		`"origin":"1.2.840.113549","observed":"20240102030405Z","source":{"remote":{"host":"gw","port":8080}},` +
+		`"tags":["a","b"],"priority":3}`
+	encoded, err := json.Marshal(report)
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	if string(encoded) != expected {
+		t.Errorf("Marshalled JSON did not match expected:\n exp: %s\n got: %s", expected, encoded)
+	}
+	var decoded Report
+	if err := json.Unmarshal([]byte(expected), &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+	if es, ps := repr(report), repr(decoded); es != ps {
+		t.Errorf("Repr mismatch:\n exp: %v\n got: %v", es, ps)
+	}
+}
+
+func TestJERRoundTrip(t *testing.T) {
+	withValue := func(v float64) Report {
+		res := report
+		res.Value = v
+		return res
+	}
+	testCases := []struct {
+		name  string
+		value Report
+	}{
+		{name: "extension alternative", value: report},
+		{
+			name: "root alternative and default value",
+			value: Report{
+				Status:   1,
+				Flags:    report.Flags,
+				Origin:   report.Origin,
+				Observed: report.Observed,
+				Source:   SourceName{Value: "<probe & co>"},
+				Enabled:  true,
+			},
+		},
+		{name: "positive infinity", value: withValue(math.Inf(1))},
+		{name: "negative infinity", value: withValue(math.Inf(-1))},
+		{name: "not a number", value: withValue(math.NaN())},
+		{name: "negative zero", value: withValue(math.Copysign(0, -1))},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			derBytes, err := tc.value.MarshalDER()
+			if err != nil {
+				t.Fatalf("Failed to marshal DER: %v", err)
+			}
+			var fromDER, fromJER Report
+			if _, err := der.UnmarshalDER(derBytes, &fromDER); err != nil {
+				t.Fatalf("Failed to unmarshal DER: %v", err)
+			}
+			jerBytes, err := json.Marshal(fromDER)
+			if err != nil {
+				t.Fatalf("Failed to marshal JER: %v", err)
+			}
+			if err := json.Unmarshal(jerBytes, &fromJER); err != nil {
+				t.Fatalf("Failed to unmarshal JER %s: %v", jerBytes, err)
+			}
+			if es, ps := repr(fromDER), repr(fromJER); es != ps {
+				t.Errorf("Repr mismatch:\n exp: %v\n got: %v", es, ps)
+			}
+			generatedBytes, err := fromJER.MarshalDER()
+			if err != nil {
+				t.Fatalf("Failed to marshal DER: %v", err)
+			}
+			if !bytes.Equal(generatedBytes, derBytes) {
+				t.Errorf("DER encoding mismatch:\n exp: %x\n got: %x", derBytes, generatedBytes)
+			}
+		})
+	}
+}
+
+func TestJERUnknownExtensions(t *testing.T) {
+	encoded, err := json.Marshal(report)
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	var decoded ReportV1
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+	if decoded.Source != nil {
+		t.Errorf("Expected unknown alternative to be skipped, got %#v", decoded.Source)
+	}
+	if decoded.Id != report.Id || decoded.Observed != report.Observed {
+		t.Errorf("Root components mismatch: %+v", decoded)
+	}
+}
+
+func TestJERDecodingErrors(t *testing.T) {
+	valid := `"id":7,"status":"ok","flags":"A0","mask":{"value":"","length":0},"digest":"","value":0,` +
+		`"origin":"1.2","observed":"20240102030405Z","tags":[]`
+	testCases := []struct {
+		name string
+		data string
+	}{
+		{name: "invalid JSON", data: `{` + valid + `,"source":{"sensor":1}`},
+		{name: "missing mandatory component", data: `{` + valid + `}`},
+		{name: "unknown component of non-extensible type", data: `{` + valid + `,"source":{"remote":{"host":"gw","port":1,"user":"x"}}}`},
+		{name: "duplicate component", data: `{` + valid + `,"source":{"sensor":1},"id":8}`},
+		{name: "choice with several members", data: `{` + valid + `,"source":{"sensor":1,"name":"x"}}`},
+		{name: "unknown enumeration identifier", data: `{` + strings.Replace(valid, `"ok"`, `"lost"`, 1) + `,"source":{"sensor":1}}`},
+		{name: "size differs from fixed size", data: `{` + strings.Replace(valid, `"A0"`, `"A000"`, 1) + `,"source":{"sensor":1}}`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var decoded Report
+			if err := json.Unmarshal([]byte(tc.data), &decoded); err == nil {
+				t.Errorf("Expected error, got %+v", decoded)
+			}
+		})
+	}
+	var decoded Report
+	if err := json.Unmarshal([]byte(`{`+valid+`,"source":{"sensor":1}}`), &decoded); err != nil {
+		t.Errorf("Failed to unmarshal valid message: %v", err)
+	}
+}
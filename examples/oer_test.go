@@ -0,0 +1,174 @@
+package examples
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"testing"
+
+	"github.com/chemikadze/asn1go/oer"
+)
+
+//go:generate go run ../cmd/asn1go/main.go -oer -package examples oer.asn1 oer_generated.go
+
+func TestOEREncoding(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    oer.Marshaler
+		decoded  oer.Unmarshaler
+		expected []byte
+	}{
+		{
+			name:     "fixed size integers",
+			value:    HeaderInfo{Psid: 0x20, GenerationTime: ptr[Time32](0x12345678), HopLimit: 1},
+			decoded:  new(HeaderInfo),
+			expected: []byte{0x40, 0x01, 0x20, 0x12, 0x34, 0x56, 0x78},
+		},
+		{
+			name: "signed integers and non-default value",
+			value: HeaderInfo{
+				Psid:               0x8000,
+				GenerationLocation: &ThreeDLocation{Latitude: 1, Longitude: -1, Elevation: 10},
+				HopLimit:           5,
+			},
+			decoded: new(HeaderInfo),
+			expected: []byte{
+				0x30, 0x02, 0x80, 0x00, 0x00, 0x00, 0x00, 0x01, 0xff, 0xff, 0xff, 0xff, 0x00, 0x0a, 0x05,
+			},
+		},
+		{
+			name:    "extension additions",
+			value:   HeaderInfo{Psid: 0x20, GenerationTime: ptr[Time32](0x12345678), HopLimit: 1, InlineP2pcdRequest: []HashedId8{{1, 2, 3, 4, 5, 6, 7, 8}}},
+			decoded: new(HeaderInfo),
+			expected: []byte{
+				0xc0, 0x01, 0x20, 0x12, 0x34, 0x56, 0x78, 0x02, 0x06, 0x80,
+				0x0a, 0x01, 0x01, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08,
+			},
+		},
+		{
+			name: "choice extension alternative",
+			value: SignedHeader{
+				Signer:  SignerIdentifierCertificate{Value: []byte("ab")},
+				Header:  HeaderInfo{Psid: 1, HopLimit: 1},
+				Flags:   asn1.BitString{Bytes: []byte{0xa5}, BitLength: 8},
+				Level:   200,
+				Comment: ptr("hi"),
+			},
+			decoded: new(SignedHeader),
+			expected: []byte{
+				0x80, 0x82, 0x03, 0x02, 0x61, 0x62, 0x00, 0x01, 0x01, 0xa5, 0x82, 0x00, 0xc8, 0x02, 0x68, 0x69,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			encoded, err := oer.Marshal(tc.value)
+			if err != nil {
+				t.Fatalf("Failed to marshal: %v", err)
+			}
+			if !bytes.Equal(encoded, tc.expected) {
+				t.Errorf("Marshalled bytes did not match expected:\nwant %x\ngot  %x", tc.expected, encoded)
+			}
+			rest, err := oer.UnmarshalCanonical(tc.expected, tc.decoded)
+			if err != nil {
+				t.Fatalf("Failed to unmarshal: %v", err)
+			}
+			if len(rest) > 0 {
+				t.Errorf("Unexpected trailing data %x", rest)
+			}
+			if es, ps := "&"+repr(tc.value), repr(tc.decoded); es != ps {
+				t.Errorf("Repr mismatch:\n exp: %v\n got: %v", es, ps)
+			}
+		})
+	}
+}
+
+func TestOERUnknownExtensions(t *testing.T) {
+	value := HeaderInfo{Psid: 1, HopLimit: 1, InlineP2pcdRequest: []HashedId8{make([]byte, 8)}, RequestedCertificate: []byte{1}}
+	encoded, err := value.MarshalOER()
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	var decoded HeaderInfoV1
+	if _, err := oer.UnmarshalCanonical(encoded, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+	if expected := (HeaderInfoV1{Psid: 1, HopLimit: 1}); decoded != expected {
+		t.Errorf("Decoded value mismatch:\n exp: %+v\n got: %+v", expected, decoded)
+	}
+
+	encoded, err = SignerIdentifierCertificate{Value: []byte("ab")}.MarshalOER()
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	signer, rest, err := UnmarshalOERSignerIdentifierV1(encoded)
+	if err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+	if signer != nil || len(rest) > 0 {
+		t.Errorf("Expected unknown alternative to be skipped, got %#v, rest %x", signer, rest)
+	}
+}
+
+func TestOERCanonicalDecoding(t *testing.T) {
+	testCases := []struct {
+		name     string
+		data     []byte
+		expected HeaderInfo
+	}{
+		{
+			name:     "encoded DEFAULT value",
+			data:     []byte{0x10, 0x01, 0x20, 0x01},
+			expected: HeaderInfo{Psid: 0x20, HopLimit: 1},
+		},
+		{
+			name:     "non-minimal length-prefixed integer",
+			data:     []byte{0x00, 0x02, 0x00, 0x20},
+			expected: HeaderInfo{Psid: 0x20, HopLimit: 1},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var decoded HeaderInfo
+			if _, err := decoded.UnmarshalOER(tc.data); err != nil {
+				t.Fatalf("Failed to unmarshal: %v", err)
+			}
+			if es, ps := repr(tc.expected), repr(decoded); es != ps {
+				t.Errorf("Repr mismatch:\n exp: %v\n got: %v", es, ps)
+			}
+			if _, err := oer.UnmarshalCanonical(tc.data, new(HeaderInfo)); err == nil {
+				t.Errorf("Expected canonical decoding to fail")
+			}
+		})
+	}
+}
+
+func TestOEREncodingErrors(t *testing.T) {
+	testCases := []struct {
+		name  string
+		value oer.Marshaler
+	}{
+		{
+			name:  "integer outside of range",
+			value: HeaderInfo{Psid: 1, GenerationTime: ptr[Time32](1 << 32), HopLimit: 1},
+		},
+		{
+			name:  "size differs from fixed size",
+			value: HeaderInfo{Psid: 1, HopLimit: 1, InlineP2pcdRequest: []HashedId8{{1}}},
+		},
+		{
+			name:  "unknown enumeration value",
+			value: SignedHeader{Signer: SignerIdentifierSelf{}, Header: HeaderInfo{HopLimit: 1}, Flags: asn1.BitString{Bytes: []byte{0}, BitLength: 8}, Level: 1},
+		},
+		{
+			name:  "absent choice value",
+			value: SignedHeader{Header: HeaderInfo{HopLimit: 1}, Flags: asn1.BitString{Bytes: []byte{0}, BitLength: 8}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if encoded, err := oer.Marshal(tc.value); err == nil {
+				t.Errorf("Expected error, got %x", encoded)
+			}
+		})
+	}
+}
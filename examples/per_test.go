@@ -0,0 +1,200 @@
+package examples
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/chemikadze/asn1go/per"
+)
+
+//go:generate go run ../cmd/asn1go/main.go -per -package examples per.asn1 per_generated.go
+
+// personnelRecord is the value from ITU-T X.691 Annex A.1.
+var personnelRecord = PersonnelRecord{
+	Name:         PersonName{GivenName: "John", Initial: "P", FamilyName: "Smith"},
+	Title:        "Director",
+	Number:       51,
+	DateOfHire:   "19710917",
+	NameOfSpouse: PersonName{GivenName: "Mary", Initial: "T", FamilyName: "Smith"},
+	Children: []ChildInformation{
+		{Name: PersonName{GivenName: "Ralph", Initial: "T", FamilyName: "Smith"}, DateOfBirth: "19571111"},
+		{Name: PersonName{GivenName: "Susan", Initial: "B", FamilyName: "Jones"}, DateOfBirth: "19590717"},
+	},
+}
+
+func TestPEREncoding(t *testing.T) {
+	testCases := []struct {
+		name      string
+		value     per.Marshaler
+		decoded   per.Unmarshaler
+		aligned   []byte
+		unaligned []byte
+	}{
+		{
+			name:    "personnel record",
+			value:   personnelRecord,
+			decoded: new(PersonnelRecord),
+			aligned: []byte{
+				0x80, 0x04, 0x4a, 0x6f, 0x68, 0x6e, 0x01, 0x50, 0x05, 0x53, 0x6d, 0x69, 0x74, 0x68, 0x01, 0x33,
+				0x08, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x08, 0x31, 0x39, 0x37, 0x31, 0x30, 0x39,
+				0x31, 0x37, 0x04, 0x4d, 0x61, 0x72, 0x79, 0x01, 0x54, 0x05, 0x53, 0x6d, 0x69, 0x74, 0x68, 0x02,
+				0x05, 0x52, 0x61, 0x6c, 0x70, 0x68, 0x01, 0x54, 0x05, 0x53, 0x6d, 0x69, 0x74, 0x68, 0x08, 0x31,
+				0x39, 0x35, 0x37, 0x31, 0x31, 0x31, 0x31, 0x05, 0x53, 0x75, 0x73, 0x61, 0x6e, 0x01, 0x42, 0x05,
+				0x4a, 0x6f, 0x6e, 0x65, 0x73, 0x08, 0x31, 0x39, 0x35, 0x39, 0x30, 0x37, 0x31, 0x37,
+			},
+			unaligned: []byte{
+				0x82, 0x4a, 0xdf, 0xa3, 0x70, 0x0d, 0x00, 0x5a, 0x7b, 0x74, 0xf4, 0xd0, 0x02, 0x66, 0x11, 0x13,
+				0x4f, 0x2c, 0xb8, 0xfa, 0x6f, 0xe4, 0x10, 0xc5, 0xcb, 0x76, 0x2c, 0x1c, 0xb1, 0x6e, 0x09, 0x37,
+				0x0f, 0x2f, 0x20, 0x35, 0x01, 0x69, 0xed, 0xd3, 0xd3, 0x40, 0x10, 0x2d, 0x2c, 0x3b, 0x38, 0x68,
+				0x01, 0xa8, 0x0b, 0x4f, 0x6e, 0x9e, 0x9a, 0x02, 0x18, 0xb9, 0x6a, 0xdd, 0x8b, 0x16, 0x2c, 0x41,
+				0x69, 0xf5, 0xe7, 0x87, 0x70, 0x0c, 0x20, 0x59, 0x5b, 0xf7, 0x65, 0xe6, 0x10, 0xc5, 0xcb, 0x57,
+				0x2c, 0x1b, 0xb1, 0x6e,
+			},
+		},
+		{
+			name:      "constrained root components",
+			value:     Message{Id: 5, Priority: 2, Ttl: 64, Payload: PayloadCount{Value: 3}},
+			decoded:   new(Message),
+			aligned:   []byte{0x20, 0x00, 0x05, 0x4b},
+			unaligned: []byte{0x20, 0x00, 0x54, 0xb0},
+		},
+		{
+			name:      "extension additions",
+			value:     Message{Id: 1, Priority: 3, Ttl: 64, Trace: true, Note: ptr("hi")},
+			decoded:   new(Message),
+			aligned:   []byte{0x80, 0x00, 0x01, 0x80, 0x03, 0x80, 0x01, 0x40, 0x03, 0x04, 0x68, 0x69},
+			unaligned: []byte{0x80, 0x00, 0x18, 0x00, 0x38, 0x0a, 0x00, 0x18, 0x3a, 0x34, 0x80},
+		},
+	}
+	for _, tc := range testCases {
+		for _, variant := range []per.Variant{per.Aligned, per.Unaligned} {
+			expected := tc.aligned
+			if variant == per.Unaligned {
+				expected = tc.unaligned
+			}
+			t.Run(fmt.Sprintf("%v/%v", tc.name, variant), func(t *testing.T) {
+				encoded, err := per.Marshal(tc.value, variant)
+				if err != nil {
+					t.Fatalf("Failed to marshal: %v", err)
+				}
+				if !bytes.Equal(encoded, expected) {
+					t.Errorf("Marshalled bytes did not match expected:\nwant %x\ngot  %x", expected, encoded)
+				}
+				if err := per.Unmarshal(expected, tc.decoded, variant); err != nil {
+					t.Fatalf("Failed to unmarshal: %v", err)
+				}
+				if es, ps := "&"+repr(tc.value), repr(tc.decoded); es != ps {
+					t.Errorf("Repr mismatch:\n exp: %v\n got: %v", es, ps)
+				}
+			})
+		}
+	}
+}
+
+func TestPERAbsentComponentsReset(t *testing.T) {
+	var decoded Message
+	for _, value := range []Message{
+		{Id: 1, Priority: 3, Ttl: 64, Note: ptr("hi")},
+		{Id: 1, Priority: 3, Ttl: 64},
+	} {
+		encoded, err := per.Marshal(value, per.Aligned)
+		if err != nil {
+			t.Fatalf("Failed to marshal: %v", err)
+		}
+		if err := per.Unmarshal(encoded, &decoded, per.Aligned); err != nil {
+			t.Fatalf("Failed to unmarshal: %v", err)
+		}
+		if es, ps := repr(value), repr(decoded); es != ps {
+			t.Errorf("Repr mismatch:\n exp: %v\n got: %v", es, ps)
+		}
+	}
+}
+
+func TestPERRoundTrip(t *testing.T) {
+	testCases := []struct {
+		name  string
+		value Message
+	}{
+		{
+			name:  "non-default value",
+			value: Message{Id: 65535, Priority: 0, Ttl: 0, Payload: PayloadRaw{Value: []byte("raw")}},
+		},
+		{
+			name:  "extension alternative",
+			value: Message{Id: 7, Priority: 1, Ttl: 64, Payload: PayloadText{Value: "text"}},
+		},
+		{
+			name:  "extensible integer outside of root",
+			value: Message{Id: 7, Priority: 1, Ttl: 64, Readings: []int64{-100, 0, 1000}},
+		},
+		{
+			name:  "extension group with optional component",
+			value: Message{Id: 7, Priority: 1, Ttl: 64, Hops: ptr[int64](15)},
+		},
+	}
+	for _, tc := range testCases {
+		for _, variant := range []per.Variant{per.Aligned, per.Unaligned} {
+			t.Run(fmt.Sprintf("%v/%v", tc.name, variant), func(t *testing.T) {
+				encoded, err := per.Marshal(tc.value, variant)
+				if err != nil {
+					t.Fatalf("Failed to marshal: %v", err)
+				}
+				var decoded Message
+				if err := decoded.UnmarshalPER(encoded, variant); err != nil {
+					t.Fatalf("Failed to unmarshal: %v", err)
+				}
+				if es, ps := repr(tc.value), repr(decoded); es != ps {
+					t.Errorf("Repr mismatch:\n exp: %v\n got: %v", es, ps)
+				}
+			})
+		}
+	}
+}
+
+func TestPERUnknownExtensions(t *testing.T) {
+	value := Message{Id: 1, Priority: 2, Ttl: 64, Payload: PayloadText{Value: "text"}, Readings: []int64{1}, Trace: true, Note: ptr("note")}
+	expected := MessageV1{Id: 1, Priority: 2, Ttl: 64, Readings: []int64{1}}
+	for _, variant := range []per.Variant{per.Aligned, per.Unaligned} {
+		t.Run(fmt.Sprint(variant), func(t *testing.T) {
+			encoded, err := value.MarshalPER(variant)
+			if err != nil {
+				t.Fatalf("Failed to marshal: %v", err)
+			}
+			var decoded MessageV1
+			if err := decoded.UnmarshalPER(encoded, variant); err != nil {
+				t.Fatalf("Failed to unmarshal: %v", err)
+			}
+			if es, ps := repr(expected), repr(decoded); es != ps {
+				t.Errorf("Repr mismatch:\n exp: %v\n got: %v", es, ps)
+			}
+		})
+	}
+}
+
+func TestPEREncodingErrors(t *testing.T) {
+	testCases := []struct {
+		name  string
+		value per.Marshaler
+	}{
+		{
+			name:  "integer outside of non-extensible range",
+			value: Message{Id: 65536, Ttl: 64},
+		},
+		{
+			name:  "size outside of range",
+			value: Message{Id: 1, Ttl: 64, Readings: make([]int64, 9)},
+		},
+		{
+			name:  "unknown enumeration value",
+			value: MessageV1{Id: 1, Priority: 3, Ttl: 64},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if encoded, err := per.Marshal(tc.value, per.Aligned); err == nil {
+				t.Errorf("Expected error, got %x", encoded)
+			}
+		})
+	}
+}
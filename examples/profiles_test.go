@@ -0,0 +1,145 @@
+package examples
+
+import (
+	"encoding/asn1"
+	"testing"
+
+	"github.com/chemikadze/asn1go/der"
+)
+
+//go:generate go run ../cmd/asn1go/main.go -der -validate -package examples profiles.asn1 profiles_generated.go
+
+func TestInnerTypeConstraints(t *testing.T) {
+	// valid returns new value for every test case, as OPTIONAL components are pointers
+	valid := func() Deployment {
+		return Deployment{
+			Current: CredentialV3{Version: 2, Extensions: []int64{1}},
+			Server:  &ServerCredential{Version: 2, Holder: HolderDnsName{Value: "example.com"}},
+			Unique:  &UniqueCredential{Version: 1, SubjectUniqueID: &asn1.BitString{Bytes: []byte{0x80}, BitLength: 1}},
+			Ports:   Ports{443},
+			Minimal: Credential{Version: 0},
+		}
+	}
+	if err := valid().Validate(); err != nil {
+		t.Fatalf("Expected value to be valid, got %v", err)
+	}
+	testCases := []struct {
+		name     string
+		modify   func(v *Deployment)
+		expected string
+	}{
+		{
+			name:     "value constraint",
+			modify:   func(v *Deployment) { v.Current.Version = 1 },
+			expected: "Current.Version: value 1 is not permitted by constraint",
+		},
+		{
+			name:     "absent component",
+			modify:   func(v *Deployment) { v.Current.IssuerUniqueID = &asn1.BitString{Bytes: []byte{0x80}, BitLength: 1} },
+			expected: "Current.IssuerUniqueID: component is not permitted by constraint",
+		},
+		{
+			name:     "present component",
+			modify:   func(v *Deployment) { v.Current.Extensions = nil },
+			expected: "Current.Extensions: component is required by constraint",
+		},
+		{
+			name:     "constraint of component type",
+			modify:   func(v *Deployment) { v.Current.Version = 3 },
+			expected: "Current.Version: value 3 is not permitted by constraint",
+		},
+		{
+			name:     "absent alternative",
+			modify:   func(v *Deployment) { v.Server.Holder = HolderEmail{Value: "admin@example.com"} },
+			expected: "Server.Holder.Email: alternative is not permitted by constraint",
+		},
+		{
+			name:     "present choice",
+			modify:   func(v *Deployment) { v.Server.Holder = nil },
+			expected: "Server.Holder: component is required by constraint",
+		},
+		{
+			name:     "no alternative of union",
+			modify:   func(v *Deployment) { v.Unique.SubjectUniqueID = nil },
+			expected: "Unique.SubjectUniqueID: component is required by constraint",
+		},
+		{
+			name:     "value constraint in union",
+			modify:   func(v *Deployment) { v.Unique.Version = 0 },
+			expected: "Unique.Version: value 0 is not permitted by constraint",
+		},
+		{
+			name:     "elements",
+			modify:   func(v *Deployment) { v.Ports = Ports{443, 0} },
+			expected: "Ports[1]: value 0 is not permitted by constraint",
+		},
+		{
+			name:     "full specification",
+			modify:   func(v *Deployment) { v.Minimal.Holder = HolderDnsName{Value: "example.com"} },
+			expected: "Minimal.Holder: component is not permitted by constraint",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			value := valid()
+			tc.modify(&value)
+			if err := value.Validate(); err == nil || err.Error() != tc.expected {
+				t.Errorf("Expected error %q, got %v", tc.expected, err)
+			}
+		})
+	}
+}
+
+func TestConstrainedTypes(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    interface{ Validate() error }
+		expected string
+	}{
+		{
+			name:  "valid profile",
+			value: CredentialV3{Version: 2, Extensions: []int64{1}},
+		},
+		{
+			name:     "value constraint of profile",
+			value:    CredentialV3{Version: 1, Extensions: []int64{1}},
+			expected: "Version: value 1 is not permitted by constraint",
+		},
+		{
+			name:     "absent component of profile",
+			value:    CredentialV3{Version: 2, Extensions: []int64{1}, SubjectUniqueID: &asn1.BitString{Bytes: []byte{0x80}, BitLength: 1}},
+			expected: "SubjectUniqueID: component is not permitted by constraint",
+		},
+		{
+			name:  "general type",
+			value: Credential{Version: 1},
+		},
+		{
+			name:     "elements",
+			value:    Ports{0},
+			expected: "[0]: value 0 is not permitted by constraint",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.value.Validate()
+			if tc.expected == "" && err != nil {
+				t.Errorf("Expected value to be valid, got %v", err)
+			} else if tc.expected != "" && (err == nil || err.Error() != tc.expected) {
+				t.Errorf("Expected error %q, got %v", tc.expected, err)
+			}
+		})
+	}
+	value := CredentialV3{Version: 2, Extensions: []int64{1}}
+	encoded, err := value.MarshalDER()
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	var decoded CredentialV3
+	if _, err := der.UnmarshalDER(encoded, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+	if es, ps := repr(value), repr(decoded); es != ps {
+		t.Errorf("Repr mismatch:\n exp: %v\n got: %v", es, ps)
+	}
+}
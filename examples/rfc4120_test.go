@@ -1,13 +1,19 @@
 package examples
 
 import (
+	"bytes"
 	"encoding/asn1"
+	"encoding/json"
 	"fmt"
+	"github.com/chemikadze/asn1go"
+	"github.com/chemikadze/asn1go/der"
 	"github.com/chemikadze/asn1go/internal/utils"
+	"github.com/chemikadze/asn1go/xer"
+	"os"
 	"testing"
 )
 
-//go:generate go run ../cmd/asn1go/main.go -package examples rfc4120.asn1 rfc4120_generated.go
+//go:generate go run ../cmd/asn1go/main.go -der -jer -xer -validate -package examples rfc4120.asn1 rfc4120_generated.go
 
 func TestMessagesDeclared(t *testing.T) {
 	var (
@@ -26,34 +32,165 @@ type testCase struct {
 	expected any // should be value
 }
 
+// messageTest verifies that message can be parsed by generated DER decoder, and that encoding of the expected
+// value can be parsed back. Types with generated encoding methods represent OPTIONAL components with pointers,
+// so they can not be used with encoding/asn1.
 func messageTest(t *testing.T, item testCase) {
 	// verify it can be parsed
-	parsed := item.value
-	rest, err := asn1.Unmarshal(item.bytes, parsed)
+	parsed := item.value.(der.Unmarshaler)
+	rest, err := der.UnmarshalDER(item.bytes, parsed)
 	if err != nil {
 		t.Errorf("Failed to parse: %v", err.Error())
 	}
 	if len(rest) != 0 {
 		t.Errorf("Expected no trailing data, got %v bytes", len(rest))
 	}
-	if es, ps := fmt.Sprintf("&%+v", item.expected), fmt.Sprintf("%+v", parsed); es != ps {
+	if es, ps := "&"+repr(item.expected), repr(parsed); es != ps {
 		t.Errorf("Repr mismatch:\n exp: %v\n got: %v", es, ps)
 	}
 
 	// verify that it can be generated and serialization is reversible
-	generatedBytes, err := asn1.Marshal(item.expected)
-	if err != nil {
+	var e der.Encoder
+	if err := item.expected.(der.Marshaler).EncodeDER(&e, der.Tag{}); err != nil {
 		t.Fatalf("Failed to marshall message: %v", err.Error())
 	}
-	_, err = asn1.Unmarshal(generatedBytes, parsed)
+	_, err = der.UnmarshalDER(e.Bytes(), parsed)
 	if err != nil {
 		t.Fatalf("Failed to unmarshall message: %v", err.Error())
 	}
-	if es, ps := fmt.Sprintf("&%+v", item.expected), fmt.Sprintf("%+v", parsed); es != ps {
+	if es, ps := "&"+repr(item.expected), repr(parsed); es != ps {
+		t.Errorf("Repr mismatch:\n exp: %v\n got: %v", es, ps)
+	}
+}
+
+// berMessageTest verifies that message can be parsed by generated BER decoder, both as is,
+// and with constructed values re-encoded with indefinite lengths, which encoding/asn1 rejects.
+func berMessageTest(t *testing.T, item testCase) {
+	parsed := item.value.(der.Unmarshaler)
+	if _, err := der.UnmarshalDER(item.bytes, parsed); err != nil {
+		t.Fatalf("Failed to parse DER: %v", err.Error())
+	}
+	if es, ps := "&"+repr(item.expected), repr(parsed); es != ps {
+		t.Errorf("Repr mismatch:\n exp: %v\n got: %v", es, ps)
+	}
+
+	berBytes := indefiniteLengths(t, item.bytes)
+	if _, err := asn1.Unmarshal(berBytes, new(asn1.RawValue)); err == nil {
+		t.Errorf("Expected encoding/asn1 to reject indefinite lengths")
+	}
+	if _, err := der.UnmarshalDER(berBytes, parsed); err == nil {
+		t.Errorf("Expected strict decoder to reject indefinite lengths")
+	}
+	rest, err := der.UnmarshalBER(append(berBytes, 0xff), parsed)
+	if err != nil {
+		t.Fatalf("Failed to parse BER: %v", err.Error())
+	}
+	if len(rest) != 1 {
+		t.Errorf("Expected 1 byte of trailing data, got %v bytes", len(rest))
+	}
+	if es, ps := "&"+repr(item.expected), repr(parsed); es != ps {
 		t.Errorf("Repr mismatch:\n exp: %v\n got: %v", es, ps)
 	}
 }
 
+// indefiniteLengths re-encodes constructed values of DER encoding with indefinite lengths.
+func indefiniteLengths(t *testing.T, data []byte) []byte {
+	var res []byte
+	for len(data) > 0 {
+		var raw asn1.RawValue
+		rest, err := asn1.Unmarshal(data, &raw)
+		if err != nil {
+			t.Fatalf("Failed to parse element: %v", err.Error())
+		}
+		if raw.IsCompound {
+			identifierSize := 1
+			if raw.FullBytes[0]&0x1f == 0x1f {
+				for raw.FullBytes[identifierSize]&0x80 != 0 {
+					identifierSize++
+				}
+				identifierSize++
+			}
+			res = append(res, raw.FullBytes[:identifierSize]...)
+			res = append(res, 0x80)
+			res = append(res, indefiniteLengths(t, raw.Bytes)...)
+			res = append(res, 0x00, 0x00)
+		} else {
+			res = append(res, raw.FullBytes...)
+		}
+		data = rest
+	}
+	return res
+}
+
+// jerMessage is implemented by generated types with DER and JER methods.
+type jerMessage interface {
+	der.Unmarshaler
+	json.Marshaler
+	json.Unmarshaler
+	MarshalDER() ([]byte, error)
+}
+
+// jerMessageTest verifies that message decoded from DER survives a round trip through JER with encoding/json,
+// and has the same DER encoding afterwards. Returns JER encoding of the message.
+func jerMessageTest(t *testing.T, derBytes []byte, parsed jerMessage, decoded jerMessage) []byte {
+	if _, err := der.UnmarshalDER(derBytes, parsed); err != nil {
+		t.Fatalf("Failed to parse DER: %v", err.Error())
+	}
+	jerBytes, err := json.Marshal(parsed)
+	if err != nil {
+		t.Fatalf("Failed to marshal JER: %v", err.Error())
+	}
+	if err := json.Unmarshal(jerBytes, decoded); err != nil {
+		t.Fatalf("Failed to parse JER: %v", err.Error())
+	}
+	if es, ps := repr(parsed), repr(decoded); es != ps {
+		t.Errorf("Repr mismatch:\n exp: %v\n got: %v", es, ps)
+	}
+	generatedBytes, err := decoded.MarshalDER()
+	if err != nil {
+		t.Fatalf("Failed to marshal DER: %v", err.Error())
+	}
+	if !bytes.Equal(generatedBytes, derBytes) {
+		t.Errorf("DER encoding mismatch after JER round trip:\n exp: %x\n got: %x", derBytes, generatedBytes)
+	}
+	return jerBytes
+}
+
+// xerMessage is implemented by generated types with DER and XER methods.
+type xerMessage interface {
+	der.Unmarshaler
+	xer.Marshaler
+	xer.Unmarshaler
+	MarshalDER() ([]byte, error)
+	MarshalXER() ([]byte, error)
+}
+
+// xerMessageTest verifies that message decoded from DER survives a round trip through CXER,
+// and has the same DER encoding afterwards. Returns XER encoding of the message.
+func xerMessageTest(t *testing.T, derBytes []byte, typeName string, parsed xerMessage, decoded xerMessage) []byte {
+	if _, err := der.UnmarshalDER(derBytes, parsed); err != nil {
+		t.Fatalf("Failed to parse DER: %v", err.Error())
+	}
+	xerBytes, err := parsed.MarshalXER()
+	if err != nil {
+		t.Fatalf("Failed to marshal XER: %v", err.Error())
+	}
+	if err := xer.UnmarshalCanonical(xerBytes, typeName, decoded); err != nil {
+		t.Fatalf("Failed to parse XER: %v", err.Error())
+	}
+	if es, ps := repr(parsed), repr(decoded); es != ps {
+		t.Errorf("Repr mismatch:\n exp: %v\n got: %v", es, ps)
+	}
+	generatedBytes, err := decoded.MarshalDER()
+	if err != nil {
+		t.Fatalf("Failed to marshal DER: %v", err.Error())
+	}
+	if !bytes.Equal(generatedBytes, derBytes) {
+		t.Errorf("DER encoding mismatch after XER round trip:\n exp: %x\n got: %x", derBytes, generatedBytes)
+	}
+	return xerBytes
+}
+
 func TestKdcReq(t *testing.T) {
 	msgBytes := utils.ParseWiresharkHex(`
 0000   30 81 aa a1 03 02 01 05 a2 03 02 01 0a a3 0e 30
@@ -76,16 +213,80 @@ func TestKdcReq(t *testing.T) {
 		},
 		Req_body: KDC_REQ_BODY{
 			Kdc_options: asn1.BitString{Bytes: []byte{0x00, 0x00, 0x00, 0x10}, BitLength: 32},
-			Cname:       PrincipalName{1, []KerberosString{"chemikadze"}},
+			Cname:       &PrincipalName{1, []KerberosString{"chemikadze"}},
 			Realm:       "ATHENA.MIT.EDU",
-			Sname:       PrincipalName{2, []KerberosString{"krbtgt", "ATHENA.MIT.EDU"}},
+			Sname:       &PrincipalName{2, []KerberosString{"krbtgt", "ATHENA.MIT.EDU"}},
 			Till:        utils.ParseWiresharkTime("2018-01-03 06:04:07"),
 			Nonce:       1679932297,
 			Etype:       []Int32{18, 17, 16, 23, 25, 26},
 		},
 	}
 
-	messageTest(t, testCase{bytes: msgBytes, value: new(AS_REQ), expected: expected})
+	messageTest(t, testCase{bytes: msgBytes, value: new(KDC_REQ), expected: KDC_REQ(expected)})
+
+	kdcReqBytes, err := KDC_REQ(expected).MarshalDER()
+	if err != nil {
+		t.Fatalf("Failed to marshal KDC-REQ: %v", err)
+	}
+	if !bytes.Equal(kdcReqBytes, msgBytes) {
+		t.Errorf("KDC-REQ encoding mismatch:\n exp: %x\n got: %x", msgBytes, kdcReqBytes)
+	}
+	asReqBytes, err := expected.MarshalDER()
+	if err != nil {
+		t.Fatalf("Failed to marshal AS-REQ: %v", err)
+	}
+	if exp := append([]byte{0x6a, 0x81, 0xad}, msgBytes...); !bytes.Equal(asReqBytes, exp) {
+		t.Errorf("AS-REQ encoding mismatch:\n exp: %x\n got: %x", exp, asReqBytes)
+	}
+
+	berMessageTest(t, testCase{bytes: msgBytes, value: new(KDC_REQ), expected: KDC_REQ(expected)})
+	berMessageTest(t, testCase{bytes: asReqBytes, value: new(AS_REQ), expected: expected})
+
+	jerMessageTest(t, asReqBytes, new(AS_REQ), new(AS_REQ))
+	xerMessageTest(t, asReqBytes, "AS-REQ", new(AS_REQ), new(AS_REQ))
+
+	node := dynamicMessageTest(t, "AS-REQ", asReqBytes)
+	body, _ := node.Child("req-body")
+	if realm, _ := body.Child("realm"); realm.Type != "Realm" || realm.Value != "ATHENA.MIT.EDU" {
+		t.Errorf("Expected realm ATHENA.MIT.EDU, got %+v", realm)
+	}
+	var etypes []any
+	etype, _ := body.Child("etype")
+	for _, child := range etype.Children {
+		etypes = append(etypes, child.Value)
+	}
+	if exp := []any{int64(18), int64(17), int64(16), int64(23), int64(25), int64(26)}; fmt.Sprint(etypes) != fmt.Sprint(exp) {
+		t.Errorf("Expected etype %v, got %v", exp, etypes)
+	}
+
+	if err := expected.Validate(); err != nil {
+		t.Errorf("Expected AS-REQ to be valid, got %v", err)
+	}
+	expected.Req_body.Etype = []Int32{18, 17, 16, 1 << 31}
+	if err := expected.Validate(); err == nil || err.Error() != "Req_body.Etype[3]: value 2147483648 is not permitted by constraint" {
+		t.Errorf("Expected error about etype out of range, got %v", err)
+	}
+}
+
+// dynamicMessageTest decodes message with types parsed from rfc4120.asn1, without generated code.
+func dynamicMessageTest(t *testing.T, typeName string, data []byte) asn1go.Node {
+	f, err := os.Open("rfc4120.asn1")
+	if err != nil {
+		t.Fatalf("Failed to open module: %v", err)
+	}
+	defer f.Close()
+	module, err := asn1go.ParseStream(f)
+	if err != nil {
+		t.Fatalf("Failed to parse module: %v", err)
+	}
+	node, err := asn1go.Decode(module, typeName, data)
+	if err != nil {
+		t.Fatalf("Failed to decode %v: %v", typeName, err)
+	}
+	if node.Type != typeName || !bytes.Equal(node.Bytes, data) {
+		t.Errorf("Expected node of %v spanning the message, got %v spanning %x", typeName, node.Type, node.Bytes)
+	}
+	return node
 }
 
 func TestKrbError(t *testing.T) {
@@ -106,16 +307,46 @@ func TestKrbError(t *testing.T) {
 	expected := KRB_ERROR{
 		Pvno:       5,
 		Msg_type:   30,
-		Ctime:      utils.ParseWiresharkTime("2023-03-27 15:51:37"),
+		Ctime:      ptr(utils.ParseWiresharkTime("2023-03-27 15:51:37")),
 		Stime:      utils.ParseWiresharkTime("2018-01-02 06:04:07"),
 		Susec:      297128,
 		Error_code: 6,
-		Crealm:     "ATHENA.MIT.EDU",
-		Cname:      PrincipalName{1, []KerberosString{"chemikadze"}},
+		Crealm:     ptr[Realm]("ATHENA.MIT.EDU"),
+		Cname:      &PrincipalName{1, []KerberosString{"chemikadze"}},
 		Realm:      "ATHENA.MIT.EDU",
 		Sname:      PrincipalName{2, []KerberosString{"krbtgt", "ATHENA.MIT.EDU"}},
-		E_text:     "CLIENT_NOT_FOUND",
+		E_text:     ptr[KerberosString]("CLIENT_NOT_FOUND"),
 	}
 
-	messageTest(t, testCase{bytes: msgBytes, value: new(KRB_ERROR), expected: expected})
+	// encoding of KRB-ERROR is the SEQUENCE wrapped with its APPLICATION tag
+	messageTest(t, testCase{bytes: append([]byte{0x7e, 0x81, 0xb5}, msgBytes...), value: new(KRB_ERROR), expected: expected})
+
+	derBytes, err := expected.MarshalDER()
+	if err != nil {
+		t.Fatalf("Failed to marshal KRB-ERROR: %v", err)
+	}
+	if exp := append([]byte{0x7e, 0x81, 0xb5}, msgBytes...); !bytes.Equal(derBytes, exp) {
+		t.Errorf("KRB-ERROR encoding mismatch:\n exp: %x\n got: %x", exp, derBytes)
+	}
+
+	berMessageTest(t, testCase{bytes: derBytes, value: new(KRB_ERROR), expected: expected})
+
+	jerBytes := jerMessageTest(t, derBytes, new(KRB_ERROR), new(KRB_ERROR))
+	expectedJER := `{"pvno":5,"msg-type":30,"ctime":"20230327155137Z","stime":"20180102060407Z","susec":297128,` +
+		`"error-code":6,"crealm":"ATHENA.MIT.EDU","cname":{"name-type":1,"name-string":["chemikadze"]},` +
+		`"realm":"ATHENA.MIT.EDU","sname":{"name-type":2,"name-string":["krbtgt","ATHENA.MIT.EDU"]},` +
+		`"e-text":"CLIENT_NOT_FOUND"}`
+	if string(jerBytes) != expectedJER {
+		t.Errorf("JER encoding mismatch:\n exp: %s\n got: %s", expectedJER, jerBytes)
+	}
+
+	xerBytes := xerMessageTest(t, derBytes, "KRB-ERROR", new(KRB_ERROR), new(KRB_ERROR))
+	expectedXER := `<KRB-ERROR><pvno>5</pvno><msg-type>30</msg-type><ctime>20230327155137Z</ctime>` +
+		`<stime>20180102060407Z</stime><susec>297128</susec><error-code>6</error-code><crealm>ATHENA.MIT.EDU</crealm>` +
+		`<cname><name-type>1</name-type><name-string><KerberosString>chemikadze</KerberosString></name-string></cname>` +
+		`<realm>ATHENA.MIT.EDU</realm><sname><name-type>2</name-type><name-string><KerberosString>krbtgt</KerberosString>` +
+		`<KerberosString>ATHENA.MIT.EDU</KerberosString></name-string></sname><e-text>CLIENT_NOT_FOUND</e-text></KRB-ERROR>`
+	if string(xerBytes) != expectedXER {
+		t.Errorf("XER encoding mismatch:\n exp: %s\n got: %s", expectedXER, xerBytes)
+	}
 }
@@ -28,3 +28,12 @@ func TestX509Parsing(t *testing.T) {
 	}
 	t.Logf("%+v", cert)
 }
+
+func TestX509ObjectIdentifiers(t *testing.T) {
+	if !ValId_at_commonName.Equal(asn1.ObjectIdentifier{2, 5, 4, 3}) {
+		t.Errorf("Unexpected id-at-commonName: %v", ValId_at_commonName)
+	}
+	if !ValId_emailAddress.Equal(asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 1}) {
+		t.Errorf("Unexpected id-emailAddress: %v", ValId_emailAddress)
+	}
+}
@@ -0,0 +1,200 @@
+package examples
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chemikadze/asn1go/der"
+	"github.com/chemikadze/asn1go/xer"
+)
+
+//go:generate go run ../cmd/asn1go/main.go -der -xer -package examples xer.asn1 xer_generated.go
+
+var shipment = Shipment{
+	Id:          7,
+	Weight:      -0.125,
+	Fragile:     true,
+	Checks:      []bool{true},
+	Urgency:     ptr(UrgencyValHigh),
+	Marks:       asn1.BitString{Bytes: []byte{0x80}, BitLength: 2},
+	Digest:      []byte{0xde, 0xad},
+	Carrier:     asn1.ObjectIdentifier{1, 2, 840},
+	Shipped:     time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	Label:       "<a> & b",
+	Destination: DestinationWarehouse{Value: 12},
+	Parcels:     []Parcel{{Size: 3, Contents: ptr("tea")}, {Size: 1}},
+	Codes:       []int64{20, 3},
+	Insured:     ptr(true),
+}
+
+func TestXEREncoding(t *testing.T) {
+	expected := `<Shipment><id>7</id><weight>-1.25E-1</weight><fragile><true/></fragile><checks><true/></checks>` +
+		`<urgency><high/></urgency><marks>10</marks><digest>DEAD</digest><carrier>1.2.840</carrier>` +
+		`<shipped>20240102030405Z</shipped><label>&lt;a&gt; &amp; b</label>` +
+		`<destination><warehouse>12</warehouse></destination>` +
+		`<parcels><Parcel><size>1</size></Parcel><Parcel><size>3</size><contents>tea</contents></Parcel></parcels>` +
+		`<codes><INTEGER>20</INTEGER><INTEGER>3</INTEGER></codes><insured><true/></insured></Shipment>`
+	encoded, err := shipment.MarshalXER()
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	if string(encoded) != expected {
+		t.Errorf("Marshalled XML did not match expected:\n exp: %s\n got: %s", expected, encoded)
+	}
+	var decoded Shipment
+	if err := xer.UnmarshalCanonical([]byte(expected), "Shipment", &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+	// SET OF values are decoded in canonical order
+	decoded.Parcels[0], decoded.Parcels[1] = decoded.Parcels[1], decoded.Parcels[0]
+	if es, ps := repr(shipment), repr(decoded); es != ps {
+		t.Errorf("Repr mismatch:\n exp: %v\n got: %v", es, ps)
+	}
+}
+
+func TestXERValueNotation(t *testing.T) {
+	expected := `<Shipment><id>42</id><weight>2.5E0</weight><checks><true/><false/></checks><marks>101</marks>` +
+		`<digest>CAFE</digest><carrier>1.3.6.1</carrier><shipped>00010101000000Z</shipped><label>Fish &amp; chips<lf/></label>` +
+		`<destination><address><city>Oslo</city><street>Main</street></address></destination>` +
+		`<parcels><Parcel><size>1</size><contents>books</contents></Parcel><Parcel><size>2</size></Parcel></parcels>` +
+		`<codes><INTEGER>3</INTEGER><INTEGER>7</INTEGER></codes></Shipment>`
+	encoded, err := ValSample.MarshalXER()
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	if string(encoded) != expected {
+		t.Errorf("Marshalled XML did not match expected:\n exp: %s\n got: %s", expected, encoded)
+	}
+}
+
+func TestXERRoundTrip(t *testing.T) {
+	withWeight := func(v float64) Shipment {
+		res := shipment
+		res.Weight = v
+		return res
+	}
+	testCases := []struct {
+		name  string
+		value Shipment
+	}{
+		{name: "all components", value: shipment},
+		{name: "value notation", value: ValSample},
+		{name: "positive infinity", value: withWeight(math.Inf(1))},
+		{name: "negative infinity", value: withWeight(math.Inf(-1))},
+		{name: "not a number", value: withWeight(math.NaN())},
+		{name: "negative zero", value: withWeight(math.Copysign(0, -1))},
+		{name: "large number", value: withWeight(6.02214076e23)},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			derBytes, err := tc.value.MarshalDER()
+			if err != nil {
+				t.Fatalf("Failed to marshal DER: %v", err)
+			}
+			var fromDER, fromXER Shipment
+			if _, err := der.UnmarshalDER(derBytes, &fromDER); err != nil {
+				t.Fatalf("Failed to unmarshal DER: %v", err)
+			}
+			xerBytes, err := fromDER.MarshalXER()
+			if err != nil {
+				t.Fatalf("Failed to marshal XER: %v", err)
+			}
+			if err := xer.UnmarshalCanonical(xerBytes, "Shipment", &fromXER); err != nil {
+				t.Fatalf("Failed to unmarshal XER %s: %v", xerBytes, err)
+			}
+			generatedBytes, err := fromXER.MarshalDER()
+			if err != nil {
+				t.Fatalf("Failed to marshal DER: %v", err)
+			}
+			if !bytes.Equal(generatedBytes, derBytes) {
+				t.Errorf("DER encoding mismatch:\n exp: %x\n got: %x", derBytes, generatedBytes)
+			}
+		})
+	}
+}
+
+func TestXERBasicDecoding(t *testing.T) {
+	data := `<?xml version="1.0" encoding="UTF-8"?>
+<Shipment>
+	<!-- components can follow in any order, and values can be surrounded by whitespace -->
+	<label>&lt;a&gt; &amp; b</label>
+	<id> 7 </id>
+	<weight>-0.125</weight>
+	<fragile>true</fragile>
+	<checks><BOOLEAN><true/></BOOLEAN></checks>
+	<urgency>high</urgency>
+	<marks>1 0</marks>
+	<digest>de ad</digest>
+	<carrier>iso(1).member-body(2).840</carrier>
+	<shipped>20240102040405+0100</shipped>
+	<destination><warehouse>12</warehouse></destination>
+	<parcels>
+		<Parcel><contents>tea</contents><size>3</size></Parcel>
+		<Parcel><size>1</size></Parcel>
+	</parcels>
+	<codes><INTEGER>20</INTEGER><INTEGER>3</INTEGER></codes>
+	<insured><true/></insured>
+	<unknown>ignored extension addition</unknown>
+</Shipment>
+`
+	var decoded Shipment
+	if err := decoded.UnmarshalXER([]byte(data)); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+	decoded.Shipped = decoded.Shipped.UTC()
+	if es, ps := repr(shipment), repr(decoded); es != ps {
+		t.Errorf("Repr mismatch:\n exp: %v\n got: %v", es, ps)
+	}
+	if err := xer.UnmarshalCanonical([]byte(data), "Shipment", &decoded); err == nil {
+		t.Errorf("Expected error decoding non-canonical encoding")
+	}
+}
+
+func TestXERDecodingErrors(t *testing.T) {
+	valid := `<id>7</id><weight>0</weight><checks/><marks/><digest/><carrier>1.2</carrier>` +
+		`<shipped>20240102030405Z</shipped><label/><parcels/><codes/>`
+	testCases := []struct {
+		name string
+		data string
+	}{
+		{name: "invalid XML", data: `<Shipment>` + valid + `<destination><warehouse>1</warehouse></destination>`},
+		{name: "unexpected root element", data: `<Parcel>` + valid + `<destination><warehouse>1</warehouse></destination></Parcel>`},
+		{name: "missing mandatory component", data: `<Shipment>` + valid + `</Shipment>`},
+		{name: "unknown component of non-extensible type", data: `<Shipment>` + valid + `<destination><address><city/><street/><zip/></address></destination></Shipment>`},
+		{name: "choice with several alternatives", data: `<Shipment>` + valid + `<destination><warehouse>1</warehouse><warehouse>2</warehouse></destination></Shipment>`},
+		{name: "unknown enumeration identifier", data: `<Shipment>` + valid + `<urgency><lost/></urgency><destination><warehouse>1</warehouse></destination></Shipment>`},
+		{name: "character data in sequence", data: `<Shipment>` + valid + `text<destination><warehouse>1</warehouse></destination></Shipment>`},
+		{name: "invalid integer", data: `<Shipment>` + strings.Replace(valid, "<id>7</id>", "<id>7.0</id>", 1) + `<destination><warehouse>1</warehouse></destination></Shipment>`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var decoded Shipment
+			if err := decoded.UnmarshalXER([]byte(tc.data)); err == nil {
+				t.Errorf("Expected error, got %+v", decoded)
+			}
+		})
+	}
+	var decoded Shipment
+	if err := decoded.UnmarshalXER([]byte(`<Shipment>` + valid + `<destination><warehouse>1</warehouse></destination></Shipment>`)); err != nil {
+		t.Errorf("Failed to unmarshal valid message: %v", err)
+	}
+	if _, err := UnmarshalXERDestination([]byte(`<Destination><unknown/></Destination>`)); err != nil {
+		t.Errorf("Expected unknown alternative of extensible CHOICE to be accepted, got %v", err)
+	}
+}
+
+func TestEnumeratedMethods(t *testing.T) {
+	if s := UrgencyValNormal.String(); s != "normal" {
+		t.Errorf("Expected identifier normal, got %v", s)
+	}
+	if s := Urgency(7).String(); s != "Urgency(7)" {
+		t.Errorf("Expected unknown value to be formatted as Urgency(7), got %v", s)
+	}
+	if !UrgencyValHigh.IsValid() || Urgency(3).IsValid() {
+		t.Errorf("Expected only items of enumeration to be valid")
+	}
+}
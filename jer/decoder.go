@@ -0,0 +1,323 @@
+package jer
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chemikadze/asn1go/der"
+)
+
+// Decoder decodes a single JSON value. Decoders of members of JSON objects and elements of JSON arrays
+// are passed to callbacks of ReadObject, ReadChoice and ReadList.
+type Decoder struct {
+	data []byte
+}
+
+// NewDecoder returns decoder of JSON value in data.
+func NewDecoder(data []byte) *Decoder {
+	return &Decoder{data: bytes.TrimSpace(data)}
+}
+
+// unmarshal decodes JSON value with encoding/json.
+func (d *Decoder) unmarshal(v any, typeName string) error {
+	if err := json.Unmarshal(d.data, v); err != nil {
+		return SyntaxError{fmt.Sprintf("expected %v, got %.32s", typeName, d.data)}
+	}
+	return nil
+}
+
+// members returns members of JSON object in order of their appearance.
+func (d *Decoder) members() ([]string, []*Decoder, error) {
+	if !bytes.HasPrefix(d.data, []byte{'{'}) {
+		return nil, nil, SyntaxError{fmt.Sprintf("expected object, got %.32s", d.data)}
+	}
+	dec := json.NewDecoder(bytes.NewReader(d.data))
+	if _, err := dec.Token(); err != nil {
+		return nil, nil, SyntaxError{err.Error()}
+	}
+	var names []string
+	var values []*Decoder
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return nil, nil, SyntaxError{err.Error()}
+		}
+		name := token.(string) // keys of objects are always strings
+		for _, seen := range names {
+			if seen == name {
+				return nil, nil, SyntaxError{fmt.Sprintf("duplicate member %q", name)}
+			}
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, nil, SyntaxError{err.Error()}
+		}
+		names = append(names, name)
+		values = append(values, NewDecoder(value))
+	}
+	return names, values, nil
+}
+
+// ReadObject reads JSON object, which encodes value of SEQUENCE or SET type, calling fn for each of its members.
+func (d *Decoder) ReadObject(fn func(name string, d *Decoder) error) error {
+	names, values, err := d.members()
+	if err != nil {
+		return err
+	}
+	for i, name := range names {
+		if err := fn(name, values[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadChoice reads JSON object with a single member, which encodes value of CHOICE type.
+// Name of the member is identifier of the chosen alternative.
+func (d *Decoder) ReadChoice(fn func(name string, d *Decoder) error) error {
+	names, values, err := d.members()
+	if err != nil {
+		return err
+	}
+	if len(names) != 1 {
+		return SyntaxError{fmt.Sprintf("CHOICE value should have exactly one member, got %v", len(names))}
+	}
+	return fn(names[0], values[0])
+}
+
+// ReadList reads JSON array, which encodes value of SEQUENCE OF or SET OF type, calling fn for each of its elements.
+func (d *Decoder) ReadList(fn func(d *Decoder) error) error {
+	var elements []json.RawMessage
+	if err := d.unmarshal(&elements, "array"); err != nil {
+		return err
+	}
+	for _, element := range elements {
+		if err := fn(NewDecoder(element)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadBoolean reads BOOLEAN value.
+func (d *Decoder) ReadBoolean(v *bool) error {
+	return d.unmarshal(v, "boolean")
+}
+
+// number reads JSON number.
+func (d *Decoder) number() (string, error) {
+	if len(d.data) == 0 || (d.data[0] != '-' && (d.data[0] < '0' || d.data[0] > '9')) {
+		return "", SyntaxError{fmt.Sprintf("expected number, got %.32s", d.data)}
+	}
+	var n json.Number
+	if err := d.unmarshal(&n, "number"); err != nil {
+		return "", err
+	}
+	return n.String(), nil
+}
+
+// ReadInteger reads INTEGER value.
+func (d *Decoder) ReadInteger(v *int64) error {
+	n, err := d.number()
+	if err != nil {
+		return err
+	}
+	res, err := strconv.ParseInt(n, 10, 64)
+	if err != nil {
+		return SyntaxError{fmt.Sprintf("INTEGER value %v does not fit into 64 bits", n)}
+	}
+	*v = res
+	return nil
+}
+
+// ReadBigInteger reads INTEGER value.
+func (d *Decoder) ReadBigInteger(v **big.Int) error {
+	n, err := d.number()
+	if err != nil {
+		return err
+	}
+	res, ok := new(big.Int).SetString(n, 10)
+	if !ok {
+		return SyntaxError{fmt.Sprintf("invalid INTEGER value %v", n)}
+	}
+	*v = res
+	return nil
+}
+
+// ReadEnumerated reads ENUMERATED value encoded as its identifier.
+func (d *Decoder) ReadEnumerated(v *asn1.Enumerated, en Enumeration) error {
+	var name string
+	if err := d.unmarshal(&name, "string"); err != nil {
+		return err
+	}
+	for _, item := range en {
+		if item.Name == name {
+			*v = asn1.Enumerated(item.Value)
+			return nil
+		}
+	}
+	return SyntaxError{fmt.Sprintf("unknown enumeration identifier %q", name)}
+}
+
+// ReadReal reads REAL value, see Encoder.WriteReal.
+func (d *Decoder) ReadReal(v *float64) error {
+	var special string
+	if json.Unmarshal(d.data, &special) == nil {
+		switch special {
+		case "INF":
+			*v = math.Inf(1)
+		case "-INF":
+			*v = math.Inf(-1)
+		case "NaN":
+			*v = math.NaN()
+		case "-0":
+			*v = math.Copysign(0, -1)
+		default:
+			return SyntaxError{fmt.Sprintf("invalid REAL value %q", special)}
+		}
+		return nil
+	}
+	n, err := d.number()
+	if err != nil {
+		return err
+	}
+	res, err := strconv.ParseFloat(n, 64)
+	if err != nil {
+		return SyntaxError{fmt.Sprintf("invalid REAL value %v", n)}
+	}
+	*v = res
+	return nil
+}
+
+// ReadBitString reads BIT STRING value encoded as JSON object with hexadecimal value and length in bits.
+func (d *Decoder) ReadBitString(v *asn1.BitString) error {
+	var encoded struct {
+		Value  *string `json:"value"`
+		Length *int    `json:"length"`
+	}
+	if err := d.unmarshal(&encoded, "object"); err != nil {
+		return err
+	}
+	if encoded.Value == nil || encoded.Length == nil {
+		return SyntaxError{"BIT STRING value should have value and length"}
+	}
+	return d.bitString(v, *encoded.Value, *encoded.Length)
+}
+
+// ReadFixedBitString reads BIT STRING value of fixed size encoded as JSON string with hexadecimal value.
+func (d *Decoder) ReadFixedBitString(v *asn1.BitString, size int) error {
+	var s string
+	if err := d.unmarshal(&s, "string"); err != nil {
+		return err
+	}
+	return d.bitString(v, s, size)
+}
+
+func (d *Decoder) bitString(v *asn1.BitString, s string, length int) error {
+	data, err := decodeHex(s)
+	if err != nil {
+		return err
+	}
+	if length < 0 || len(data) != (length+7)/8 {
+		return SyntaxError{fmt.Sprintf("BIT STRING of %v bits should have %v octets, got %v", length, (length+7)/8, len(data))}
+	}
+	if length%8 != 0 {
+		data[len(data)-1] &^= 0xff >> (length % 8)
+	}
+	*v = asn1.BitString{Bytes: data, BitLength: length}
+	return nil
+}
+
+// ReadOctetString reads OCTET STRING value encoded as JSON string with hexadecimal value.
+func (d *Decoder) ReadOctetString(v *[]byte) error {
+	var s string
+	if err := d.unmarshal(&s, "string"); err != nil {
+		return err
+	}
+	data, err := decodeHex(s)
+	if err != nil {
+		return err
+	}
+	*v = data
+	return nil
+}
+
+// ReadString reads value of character string type.
+func (d *Decoder) ReadString(v *string) error {
+	return d.unmarshal(v, "string")
+}
+
+// ReadNull reads NULL value.
+func (d *Decoder) ReadNull() error {
+	if string(d.data) != "null" {
+		return SyntaxError{fmt.Sprintf("expected null, got %.32s", d.data)}
+	}
+	return nil
+}
+
+// ReadObjectIdentifier reads OBJECT IDENTIFIER value encoded as JSON string with dot-separated arcs.
+func (d *Decoder) ReadObjectIdentifier(v *asn1.ObjectIdentifier) error {
+	var s string
+	if err := d.unmarshal(&s, "string"); err != nil {
+		return err
+	}
+	arcs := strings.Split(s, ".")
+	if len(arcs) < 2 {
+		return SyntaxError{fmt.Sprintf("invalid object identifier %q", s)}
+	}
+	res := make(asn1.ObjectIdentifier, len(arcs))
+	for i, arc := range arcs {
+		n, err := strconv.Atoi(arc)
+		if err != nil || n < 0 || arc[0] == '+' {
+			return SyntaxError{fmt.Sprintf("invalid object identifier %q", s)}
+		}
+		res[i] = n
+	}
+	*v = res
+	return nil
+}
+
+// ReadGeneralizedTime reads GeneralizedTime value, which is accepted in any format valid in BER.
+func (d *Decoder) ReadGeneralizedTime(v *time.Time) error {
+	dec, err := d.derDecoder(der.TagGeneralizedTime)
+	if err != nil {
+		return err
+	}
+	return dec.ReadGeneralizedTime(der.TagGeneralizedTime, v)
+}
+
+// ReadUTCTime reads UTCTime value, which is accepted in any format valid in BER.
+func (d *Decoder) ReadUTCTime(v *time.Time) error {
+	dec, err := d.derDecoder(der.TagUTCTime)
+	if err != nil {
+		return err
+	}
+	return dec.ReadUTCTime(der.TagUTCTime, v)
+}
+
+// derDecoder returns decoder of DER package reading JSON string as character string value with the tag.
+func (d *Decoder) derDecoder(tag der.Tag) (*der.Decoder, error) {
+	var s string
+	if err := d.unmarshal(&s, "string"); err != nil {
+		return nil, err
+	}
+	var enc der.Encoder
+	enc.WriteString(tag, s)
+	return der.NewDecoder(enc.Bytes()), nil
+}
+
+func decodeHex(s string) ([]byte, error) {
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, SyntaxError{fmt.Sprintf("invalid hexadecimal string %q", s)}
+	}
+	return data, nil
+}
@@ -0,0 +1,223 @@
+package jer
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chemikadze/asn1go/der"
+)
+
+// Encoder appends JER encodings of values to a buffer. Zero value is ready to use.
+// Values of SEQUENCE, SET and CHOICE types are written as JSON objects, which are started by BeginObject,
+// and hold values preceded by WriteName.
+type Encoder struct {
+	buf []byte
+	// separate is set if the next value or name should be preceded by comma.
+	separate bool
+}
+
+// Bytes returns encoded data.
+func (e *Encoder) Bytes() []byte {
+	return e.buf
+}
+
+// Reset discards encoded data, so that encoder can be reused.
+func (e *Encoder) Reset() {
+	e.buf = e.buf[:0]
+	e.separate = false
+}
+
+// begin starts the next value.
+func (e *Encoder) begin() {
+	if e.separate {
+		e.buf = append(e.buf, ',')
+	}
+	e.separate = true
+}
+
+// BeginObject starts JSON object.
+func (e *Encoder) BeginObject() {
+	e.begin()
+	e.buf = append(e.buf, '{')
+	e.separate = false
+}
+
+// WriteName appends name of the next member of JSON object, e.g. identifier of SEQUENCE component.
+func (e *Encoder) WriteName(name string) {
+	e.begin()
+	e.buf = appendString(e.buf, name)
+	e.buf = append(e.buf, ':')
+	e.separate = false
+}
+
+// EndObject ends JSON object started by BeginObject.
+func (e *Encoder) EndObject() {
+	e.buf = append(e.buf, '}')
+	e.separate = true
+}
+
+// WriteList appends elements of SEQUENCE OF or SET OF value as JSON array, fn appends i-th element.
+func (e *Encoder) WriteList(n int, fn func(i int) error) error {
+	e.begin()
+	e.buf = append(e.buf, '[')
+	e.separate = false
+	for i := 0; i < n; i++ {
+		if err := fn(i); err != nil {
+			return err
+		}
+	}
+	e.buf = append(e.buf, ']')
+	e.separate = true
+	return nil
+}
+
+// WriteBoolean appends BOOLEAN value.
+func (e *Encoder) WriteBoolean(v bool) {
+	e.begin()
+	e.buf = strconv.AppendBool(e.buf, v)
+}
+
+// WriteInteger appends INTEGER value as JSON number.
+func (e *Encoder) WriteInteger(v int64) {
+	e.begin()
+	e.buf = strconv.AppendInt(e.buf, v, 10)
+}
+
+// WriteBigInteger appends INTEGER value as JSON number.
+func (e *Encoder) WriteBigInteger(v *big.Int) error {
+	if v == nil {
+		return fmt.Errorf("jer: INTEGER value is nil")
+	}
+	e.begin()
+	e.buf = v.Append(e.buf, 10)
+	return nil
+}
+
+// WriteEnumerated appends ENUMERATED value as JSON string holding its identifier.
+func (e *Encoder) WriteEnumerated(v asn1.Enumerated, en Enumeration) error {
+	for _, item := range en {
+		if item.Value == int64(v) {
+			e.WriteString(item.Name)
+			return nil
+		}
+	}
+	return fmt.Errorf("jer: unknown enumeration value %v", v)
+}
+
+// WriteReal appends REAL value as JSON number. Special values, which can not be represented by JSON numbers,
+// are encoded as JSON strings "INF", "-INF", "NaN" and "-0".
+func (e *Encoder) WriteReal(v float64) {
+	switch {
+	case math.IsInf(v, 1):
+		e.WriteString("INF")
+	case math.IsInf(v, -1):
+		e.WriteString("-INF")
+	case math.IsNaN(v):
+		e.WriteString("NaN")
+	case v == 0 && math.Signbit(v):
+		e.WriteString("-0")
+	default:
+		e.begin()
+		e.buf = strconv.AppendFloat(e.buf, v, 'g', -1, 64)
+	}
+}
+
+// WriteBitString appends BIT STRING value as JSON object with hexadecimal value and length in bits.
+func (e *Encoder) WriteBitString(v asn1.BitString) error {
+	if v.BitLength < 0 || len(v.Bytes) < (v.BitLength+7)/8 {
+		return fmt.Errorf("jer: invalid BIT STRING with %v bits in %v octets", v.BitLength, len(v.Bytes))
+	}
+	e.BeginObject()
+	e.WriteName("value")
+	e.WriteOctetString(v.Bytes[:(v.BitLength+7)/8])
+	e.WriteName("length")
+	e.WriteInteger(int64(v.BitLength))
+	e.EndObject()
+	return nil
+}
+
+// WriteFixedBitString appends BIT STRING value of fixed size as JSON string with hexadecimal value.
+func (e *Encoder) WriteFixedBitString(v asn1.BitString, size int) error {
+	if v.BitLength != size {
+		return fmt.Errorf("jer: size %v of BIT STRING does not match fixed size %v", v.BitLength, size)
+	}
+	if len(v.Bytes) < (size+7)/8 {
+		return fmt.Errorf("jer: invalid BIT STRING with %v bits in %v octets", size, len(v.Bytes))
+	}
+	e.WriteOctetString(v.Bytes[:(size+7)/8])
+	return nil
+}
+
+// WriteOctetString appends OCTET STRING value as JSON string with hexadecimal value.
+func (e *Encoder) WriteOctetString(v []byte) {
+	e.begin()
+	e.buf = append(e.buf, '"')
+	e.buf = append(e.buf, strings.ToUpper(hex.EncodeToString(v))...)
+	e.buf = append(e.buf, '"')
+}
+
+// WriteString appends value of character string type as JSON string.
+func (e *Encoder) WriteString(v string) {
+	e.begin()
+	e.buf = appendString(e.buf, v)
+}
+
+// WriteNull appends NULL value.
+func (e *Encoder) WriteNull() {
+	e.begin()
+	e.buf = append(e.buf, "null"...)
+}
+
+// WriteObjectIdentifier appends OBJECT IDENTIFIER value as JSON string with dot-separated arcs.
+func (e *Encoder) WriteObjectIdentifier(v asn1.ObjectIdentifier) error {
+	if len(v) < 2 {
+		return fmt.Errorf("jer: invalid object identifier %v", v)
+	}
+	e.WriteString(v.String())
+	return nil
+}
+
+// WriteGeneralizedTime appends GeneralizedTime value as JSON string in the same format as DER.
+func (e *Encoder) WriteGeneralizedTime(v time.Time) error {
+	var enc der.Encoder
+	if err := enc.WriteGeneralizedTime(der.TagGeneralizedTime, v); err != nil {
+		return err
+	}
+	return e.writeDERString(enc.Bytes())
+}
+
+// WriteUTCTime appends UTCTime value as JSON string in the same format as DER.
+func (e *Encoder) WriteUTCTime(v time.Time) error {
+	var enc der.Encoder
+	if err := enc.WriteUTCTime(der.TagUTCTime, v); err != nil {
+		return err
+	}
+	return e.writeDERString(enc.Bytes())
+}
+
+// writeDERString appends contents octets of DER encoding of a character string as JSON string.
+func (e *Encoder) writeDERString(encoding []byte) error {
+	var raw asn1.RawValue
+	if err := der.NewDecoder(encoding).ReadRawValue(&raw); err != nil {
+		return err
+	}
+	e.WriteString(string(raw.Bytes))
+	return nil
+}
+
+// appendString appends JSON string. Unlike encoding/json, HTML characters are not escaped.
+func appendString(buf []byte, s string) []byte {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s) // strings are always encoded
+	return append(buf, bytes.TrimSuffix(b.Bytes(), []byte{'\n'})...)
+}
@@ -0,0 +1,69 @@
+// Package jer implements JSON Encoding Rules of ASN.1, as defined in X.697.
+//
+// It is a runtime library of the code generated by asn1go with GEN_JER code generator type,
+// and is not intended to be used directly. Generated types implement json.Marshaler and json.Unmarshaler
+// with this package, so that they can be used with encoding/json.
+package jer
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// ErrAbsentValue is returned when value of CHOICE to encode is nil.
+var ErrAbsentValue = errors.New("jer: value of CHOICE is not set")
+
+// SyntaxError is returned when decoded data is not a valid JER encoding of the type.
+type SyntaxError struct {
+	Msg string
+}
+
+func (e SyntaxError) Error() string {
+	return "jer: syntax error: " + e.Msg
+}
+
+// Marshaler is implemented by generated types, which encode themselves without reflection.
+type Marshaler interface {
+	// EncodeJER appends JER encoding of the value to e.
+	EncodeJER(e *Encoder) error
+}
+
+// Unmarshaler is implemented by generated types, which decode themselves without reflection.
+type Unmarshaler interface {
+	// DecodeJER decodes the value of d.
+	DecodeJER(d *Decoder) error
+}
+
+// UnmarshalerFunc is an adapter to use function, e.g. generated function decoding CHOICE type, as Unmarshaler.
+type UnmarshalerFunc func(d *Decoder) error
+
+// DecodeJER calls f(d).
+func (f UnmarshalerFunc) DecodeJER(d *Decoder) error {
+	return f(d)
+}
+
+// Marshal returns JER encoding of the value.
+func Marshal(v Marshaler) ([]byte, error) {
+	var e Encoder
+	if err := v.EncodeJER(&e); err != nil {
+		return nil, err
+	}
+	return e.Bytes(), nil
+}
+
+// Unmarshal decodes JER encoding of the value from data, which should hold a single JSON value.
+func Unmarshal(data []byte, v Unmarshaler) error {
+	if !json.Valid(data) {
+		return SyntaxError{"invalid JSON"}
+	}
+	return v.DecodeJER(NewDecoder(data))
+}
+
+// Enumeration describes identifiers of ENUMERATED type, which are used as encodings of its values.
+type Enumeration []EnumerationItem
+
+// EnumerationItem is identifier of ENUMERATED type with its value.
+type EnumerationItem struct {
+	Name  string
+	Value int64
+}
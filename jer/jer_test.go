@@ -0,0 +1,304 @@
+package jer
+
+import (
+	"encoding/asn1"
+	"math"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEncoding(t *testing.T) {
+	enumeration := Enumeration{{Name: "low", Value: 0}, {Name: "high", Value: 200}}
+	testCases := []struct {
+		name     string
+		write    func(e *Encoder) error
+		read     func(d *Decoder) (any, error)
+		value    any
+		expected string
+	}{
+		{
+			name:     "boolean",
+			write:    func(e *Encoder) error { e.WriteBoolean(true); return nil },
+			read:     func(d *Decoder) (any, error) { var b bool; err := d.ReadBoolean(&b); return b, err },
+			value:    true,
+			expected: `true`,
+		},
+		{
+			name:     "integer",
+			write:    func(e *Encoder) error { e.WriteInteger(-42); return nil },
+			read:     func(d *Decoder) (any, error) { var i int64; err := d.ReadInteger(&i); return i, err },
+			value:    int64(-42),
+			expected: `-42`,
+		},
+		{
+			name: "big integer",
+			write: func(e *Encoder) error {
+				return e.WriteBigInteger(new(big.Int).Lsh(big.NewInt(1), 80))
+			},
+			read:     func(d *Decoder) (any, error) { var i *big.Int; err := d.ReadBigInteger(&i); return i, err },
+			value:    new(big.Int).Lsh(big.NewInt(1), 80),
+			expected: `1208925819614629174706176`,
+		},
+		{
+			name:  "enumerated",
+			write: func(e *Encoder) error { return e.WriteEnumerated(200, enumeration) },
+			read: func(d *Decoder) (any, error) {
+				var v asn1.Enumerated
+				err := d.ReadEnumerated(&v, enumeration)
+				return v, err
+			},
+			value:    asn1.Enumerated(200),
+			expected: `"high"`,
+		},
+		{
+			name:     "real",
+			write:    func(e *Encoder) error { e.WriteReal(-2.5e-10); return nil },
+			read:     func(d *Decoder) (any, error) { var f float64; err := d.ReadReal(&f); return f, err },
+			value:    -2.5e-10,
+			expected: `-2.5e-10`,
+		},
+		{
+			name:     "real infinity",
+			write:    func(e *Encoder) error { e.WriteReal(math.Inf(-1)); return nil },
+			read:     func(d *Decoder) (any, error) { var f float64; err := d.ReadReal(&f); return f, err },
+			value:    math.Inf(-1),
+			expected: `"-INF"`,
+		},
+		{
+			name: "bit string",
+			write: func(e *Encoder) error {
+				return e.WriteBitString(asn1.BitString{Bytes: []byte{0xa8, 0x00}, BitLength: 5})
+			},
+			read: func(d *Decoder) (any, error) {
+				var v asn1.BitString
+				err := d.ReadBitString(&v)
+				return v, err
+			},
+			value:    asn1.BitString{Bytes: []byte{0xa8}, BitLength: 5},
+			expected: `{"value":"A8","length":5}`,
+		},
+		{
+			name: "bit string of fixed size",
+			write: func(e *Encoder) error {
+				return e.WriteFixedBitString(asn1.BitString{Bytes: []byte{0x12, 0x30}, BitLength: 12}, 12)
+			},
+			read: func(d *Decoder) (any, error) {
+				var v asn1.BitString
+				err := d.ReadFixedBitString(&v, 12)
+				return v, err
+			},
+			value:    asn1.BitString{Bytes: []byte{0x12, 0x30}, BitLength: 12},
+			expected: `"1230"`,
+		},
+		{
+			name:     "octet string",
+			write:    func(e *Encoder) error { e.WriteOctetString([]byte{0x01, 0xab}); return nil },
+			read:     func(d *Decoder) (any, error) { var b []byte; err := d.ReadOctetString(&b); return b, err },
+			value:    []byte{0x01, 0xab},
+			expected: `"01AB"`,
+		},
+		{
+			name:     "string without escaping of HTML",
+			write:    func(e *Encoder) error { e.WriteString("<a & \"b\">\n"); return nil },
+			read:     func(d *Decoder) (any, error) { var s string; err := d.ReadString(&s); return s, err },
+			value:    "<a & \"b\">\n",
+			expected: `"<a & \"b\">\n"`,
+		},
+		{
+			name:     "null",
+			write:    func(e *Encoder) error { e.WriteNull(); return nil },
+			read:     func(d *Decoder) (any, error) { return nil, d.ReadNull() },
+			value:    nil,
+			expected: `null`,
+		},
+		{
+			name:  "object identifier",
+			write: func(e *Encoder) error { return e.WriteObjectIdentifier(asn1.ObjectIdentifier{2, 5, 4, 3}) },
+			read: func(d *Decoder) (any, error) {
+				var v asn1.ObjectIdentifier
+				err := d.ReadObjectIdentifier(&v)
+				return v, err
+			},
+			value:    asn1.ObjectIdentifier{2, 5, 4, 3},
+			expected: `"2.5.4.3"`,
+		},
+		{
+			name: "generalized time",
+			write: func(e *Encoder) error {
+				return e.WriteGeneralizedTime(time.Date(2024, 1, 2, 3, 4, 5, 500000000, time.UTC))
+			},
+			read: func(d *Decoder) (any, error) {
+				var v time.Time
+				err := d.ReadGeneralizedTime(&v)
+				return v, err
+			},
+			value:    time.Date(2024, 1, 2, 3, 4, 5, 500000000, time.UTC),
+			expected: `"20240102030405.5Z"`,
+		},
+		{
+			name: "object and list",
+			write: func(e *Encoder) error {
+				e.BeginObject()
+				e.WriteName("a")
+				e.WriteInteger(1)
+				e.WriteName("b")
+				err := e.WriteList(2, func(i int) error { e.WriteBoolean(i == 0); return nil })
+				e.EndObject()
+				return err
+			},
+			read: func(d *Decoder) (any, error) {
+				res := make(map[string]any)
+				err := d.ReadObject(func(name string, d *Decoder) error {
+					if name == "a" {
+						var i int64
+						err := d.ReadInteger(&i)
+						res[name] = i
+						return err
+					}
+					var items []bool
+					err := d.ReadList(func(d *Decoder) error {
+						var b bool
+						err := d.ReadBoolean(&b)
+						items = append(items, b)
+						return err
+					})
+					res[name] = items
+					return err
+				})
+				return res, err
+			},
+			value:    map[string]any{"a": int64(1), "b": []bool{true, false}},
+			expected: `{"a":1,"b":[true,false]}`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var e Encoder
+			if err := tc.write(&e); err != nil {
+				t.Fatalf("Failed to encode: %v", err)
+			}
+			if string(e.Bytes()) != tc.expected {
+				t.Errorf("Expected encoding %s, got %s", tc.expected, e.Bytes())
+			}
+			decoded, err := tc.read(NewDecoder([]byte(" " + tc.expected + "\n")))
+			if err != nil {
+				t.Fatalf("Failed to decode: %v", err)
+			}
+			if !reflect.DeepEqual(decoded, tc.value) {
+				t.Errorf("Expected decoded value %#v, got %#v", tc.value, decoded)
+			}
+		})
+	}
+}
+
+func TestEncoderErrors(t *testing.T) {
+	testCases := []struct {
+		name  string
+		write func(e *Encoder) error
+	}{
+		{
+			name:  "unknown enumeration value",
+			write: func(e *Encoder) error { return e.WriteEnumerated(3, Enumeration{{Name: "a", Value: 0}}) },
+		},
+		{
+			name: "size differs from fixed size",
+			write: func(e *Encoder) error {
+				return e.WriteFixedBitString(asn1.BitString{Bytes: []byte{0}, BitLength: 8}, 4)
+			},
+		},
+		{
+			name:  "bit string shorter than its length",
+			write: func(e *Encoder) error { return e.WriteBitString(asn1.BitString{Bytes: []byte{0}, BitLength: 9}) },
+		},
+		{
+			name:  "invalid object identifier",
+			write: func(e *Encoder) error { return e.WriteObjectIdentifier(asn1.ObjectIdentifier{1}) },
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var e Encoder
+			if err := tc.write(&e); err == nil {
+				t.Errorf("Expected error, got encoding %s", e.Bytes())
+			}
+		})
+	}
+}
+
+func TestDecoderErrors(t *testing.T) {
+	testCases := []struct {
+		name string
+		data string
+		read func(d *Decoder) error
+	}{
+		{
+			name: "fraction in integer",
+			data: `1.5`,
+			read: func(d *Decoder) error { var i int64; return d.ReadInteger(&i) },
+		},
+		{
+			name: "integer as string",
+			data: `"1"`,
+			read: func(d *Decoder) error { var i int64; return d.ReadInteger(&i) },
+		},
+		{
+			name: "integer out of 64 bits",
+			data: `9223372036854775808`,
+			read: func(d *Decoder) error { var i int64; return d.ReadInteger(&i) },
+		},
+		{
+			name: "unknown special real value",
+			data: `"Infinity"`,
+			read: func(d *Decoder) error { var f float64; return d.ReadReal(&f) },
+		},
+		{
+			name: "bit string without length",
+			data: `{"value":"FF"}`,
+			read: func(d *Decoder) error { var v asn1.BitString; return d.ReadBitString(&v) },
+		},
+		{
+			name: "bit string length mismatch",
+			data: `{"value":"FF","length":9}`,
+			read: func(d *Decoder) error { var v asn1.BitString; return d.ReadBitString(&v) },
+		},
+		{
+			name: "invalid hexadecimal string",
+			data: `"ABC"`,
+			read: func(d *Decoder) error { var b []byte; return d.ReadOctetString(&b) },
+		},
+		{
+			name: "invalid object identifier",
+			data: `"1.-2"`,
+			read: func(d *Decoder) error { var v asn1.ObjectIdentifier; return d.ReadObjectIdentifier(&v) },
+		},
+		{
+			name: "invalid time",
+			data: `"2024-01-02"`,
+			read: func(d *Decoder) error { var v time.Time; return d.ReadGeneralizedTime(&v) },
+		},
+		{
+			name: "duplicate member",
+			data: `{"a":1,"a":2}`,
+			read: func(d *Decoder) error { return d.ReadObject(func(string, *Decoder) error { return nil }) },
+		},
+		{
+			name: "choice without members",
+			data: `{}`,
+			read: func(d *Decoder) error { return d.ReadChoice(func(string, *Decoder) error { return nil }) },
+		},
+		{
+			name: "null instead of object",
+			data: `null`,
+			read: func(d *Decoder) error { return d.ReadObject(func(string, *Decoder) error { return nil }) },
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.read(NewDecoder([]byte(tc.data))); err == nil {
+				t.Errorf("Expected error decoding %s", tc.data)
+			}
+		})
+	}
+}
@@ -3,8 +3,11 @@ package asn1go
 import (
 	"bufio"
 	"bytes"
+	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
+	"math/big"
 	"strconv"
 	"unicode"
 	"unicode/utf8"
@@ -102,9 +105,11 @@ type ASN1Lexer struct {
 	bufReader *bufio.Reader
 	// err is used to store lexer or parser error.
 	err error
-	// result is where parsing result will be written by the parser.
-	result        *ModuleDefinition
+	// results is where parsed modules will be written by the parser.
+	results       []*ModuleDefinition
 	lastWasNumber bool
+	// lastWasAssignment is set after assignment token, where XMLTypedValue can start.
+	lastWasAssignment bool
 
 	// lineNo is 0-indexed line number used for error reporting.
 	lineNo int
@@ -116,6 +121,8 @@ type ASN1Lexer struct {
 func (lex *ASN1Lexer) Lex(lval *yySymType) int {
 	lastWasNumber := lex.lastWasNumber
 	lex.lastWasNumber = false
+	lastWasAssignment := lex.lastWasAssignment
+	lex.lastWasAssignment = false
 	for {
 		r, _, err := lex.readRune()
 		if err == io.EOF {
@@ -167,8 +174,16 @@ func (lex *ASN1Lexer) Lex(lval *yySymType) int {
 			lex.unreadRune()
 			lex.lastWasNumber = true
 			return lex.consumeNumber(lval)
+		} else if r == '"' {
+			return lex.consumeCString(lval)
+		} else if r == '\'' {
+			return lex.consumeBHString(lval)
+		} else if r == '<' && lastWasAssignment {
+			lex.unreadRune()
+			return lex.consumeXMLTypedValue(lval)
 		} else if r == ':' && lex.peekRunes(2) == ":=" {
 			lex.discard(2)
+			lex.lastWasAssignment = true
 			return ASSIGNMENT
 		} else if r == '.' && lex.peekRunes(2) == ".." {
 			lex.discard(2)
@@ -216,10 +231,6 @@ func (lex *ASN1Lexer) consumeSingleSymbol(r rune) int {
 		return COLON
 	case '=':
 		return EQUALS
-	case '"':
-		return QUOTATION_MARK
-	case '\'':
-		return APOSTROPHE
 	case ' ': // TODO at which context it can be parsed?
 		return SPACE
 	case ';':
@@ -390,6 +401,11 @@ func (lex *ASN1Lexer) consumeNumber(lval *yySymType) int {
 			}
 			repr := acc.String()
 			i, err := strconv.Atoi(repr)
+			if errors.Is(err, strconv.ErrRange) {
+				n, _ := new(big.Int).SetString(repr, 10)
+				lval.BigNumber = BigNumber{Int: n}
+				return BIGNUMBER
+			}
 			if err != nil {
 				lex.Error(fmt.Sprintf("Failed to parse number: %v", err.Error()))
 				return -1
@@ -406,9 +422,174 @@ func (lex *ASN1Lexer) consumeNumber(lval *yySymType) int {
 	}
 }
 
+// consumeCString reads cstring after opening quotation mark.
+// Paired quotation marks are unescaped, and newlines are removed together with surrounding whitespace.
+// See X.680, section 11.14.
+func (lex *ASN1Lexer) consumeCString(lval *yySymType) int {
+	acc := bytes.NewBufferString("")
+	for {
+		r, _, err := lex.readRune()
+		if err == io.EOF {
+			lex.Error("unterminated cstring")
+			return -1
+		}
+		if err != nil {
+			lex.Error(fmt.Sprintf("Failed to read: %v", err.Error()))
+			return -1
+		}
+		if r == '"' {
+			if lex.peekRune() == '"' {
+				lex.readRune()
+				acc.WriteRune(r)
+				continue
+			}
+			lval.cstring = acc.String()
+			return CSTRING
+		}
+		if isNewline(r) {
+			trimmed := bytes.TrimRightFunc(acc.Bytes(), isWhitespace)
+			acc.Truncate(len(trimmed))
+			for next, err := lex.peekRuneE(); err == nil && isWhitespace(next); next, err = lex.peekRuneE() {
+				lex.readRune()
+			}
+			continue
+		}
+		acc.WriteRune(r)
+	}
+}
+
+// consumeBHString reads bstring or hstring after opening apostrophe.
+// Whitespace inside of the string is ignored.
+// See X.680, sections 11.10 and 11.12.
+func (lex *ASN1Lexer) consumeBHString(lval *yySymType) int {
+	acc := bytes.NewBufferString("")
+	for {
+		r, _, err := lex.readRune()
+		if err == io.EOF {
+			lex.Error("unterminated bstring or hstring")
+			return -1
+		}
+		if err != nil {
+			lex.Error(fmt.Sprintf("Failed to read: %v", err.Error()))
+			return -1
+		}
+		if r == '\'' {
+			break
+		}
+		if isWhitespace(r) {
+			continue
+		}
+		acc.WriteRune(r)
+	}
+	content := acc.String()
+	r, _, err := lex.readRune()
+	if err != nil {
+		lex.Error(fmt.Sprintf("expected B or H after '%v'", content))
+		return -1
+	}
+	switch r {
+	case 'B':
+		for _, c := range content {
+			if c != '0' && c != '1' {
+				lex.Error(fmt.Sprintf("invalid character '%c' in bstring '%v'", c, content))
+				return -1
+			}
+		}
+		lval.bstring = content
+		return BSTRING
+	case 'H':
+		for _, c := range content {
+			if !(c >= '0' && c <= '9' || c >= 'A' && c <= 'F') {
+				lex.Error(fmt.Sprintf("invalid character '%c' in hstring '%v'", c, content))
+				return -1
+			}
+		}
+		lval.hstring = content
+		return HSTRING
+	default:
+		lex.Error(fmt.Sprintf("expected B or H after '%v', got %c", content, r))
+		return -1
+	}
+}
+
+// consumeXMLTypedValue reads XMLTypedValue, which is an XML element following assignment, e.g. <Type><a>1</a></Type>.
+// Character and entity references are replaced, and comments are skipped. See X.680, section 16.
+func (lex *ASN1Lexer) consumeXMLTypedValue(lval *yySymType) int {
+	dec := xml.NewDecoder(xmlReader{lex})
+	var stack []XMLValue
+	for {
+		token, err := dec.Token()
+		if err != nil {
+			lex.Error(fmt.Sprintf("invalid XML value: %v", err.Error()))
+			return -1
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			if len(t.Attr) > 0 {
+				lex.Error(fmt.Sprintf("unexpected attributes of XML element %v", t.Name.Local))
+				return -1
+			}
+			stack = append(stack, XMLValue{Name: t.Name.Local})
+		case xml.EndElement:
+			v := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if len(stack) == 0 {
+				lval.XMLValue = v
+				return XML_TYPED_VALUE
+			}
+			parent := &stack[len(stack)-1]
+			parent.Content = append(parent.Content, v)
+		case xml.CharData:
+			parent := &stack[len(stack)-1]
+			if n := len(parent.Content); n > 0 {
+				if text, ok := parent.Content[n-1].(XMLText); ok {
+					parent.Content[n-1] = text + XMLText(t)
+					continue
+				}
+			}
+			parent.Content = append(parent.Content, XMLText(t))
+		case xml.Comment:
+			continue
+		default:
+			lex.Error(fmt.Sprintf("invalid XML value: unexpected %T", t))
+			return -1
+		}
+	}
+}
+
+// xmlReader reads XML value for encoding/xml decoder byte by byte, so that it does not read past the end of value.
+type xmlReader struct {
+	lex *ASN1Lexer
+}
+
+// ReadByte implements io.ByteReader.
+func (r xmlReader) ReadByte() (byte, error) {
+	b, err := r.lex.bufReader.ReadByte()
+	if err == nil && isNewline(rune(b)) {
+		r.lex.lineNo += 1
+	}
+	return b, err
+}
+
+// Read implements io.Reader.
+func (r xmlReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	b, err := r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	p[0] = b
+	return 1, nil
+}
+
 // Error implements yyLexer, and is used by the parser to communicate errors.
+// The first error is kept, as errors of the lexer are followed by syntax errors reported by the parser.
 func (lex *ASN1Lexer) Error(e string) {
-	lex.err = fmt.Errorf("line %v: %v", lex.lineNo+1, e)
+	if lex.err == nil {
+		lex.err = fmt.Errorf("line %v: %v", lex.lineNo+1, e)
+	}
 }
 
 // isWhitespace returns true if the rune r is whitespace.
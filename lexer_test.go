@@ -2,6 +2,7 @@ package asn1go
 
 import (
 	"bufio"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -138,6 +139,17 @@ func TestNumber(t *testing.T) {
 	testNumber(t, "12345", Number(12345))
 }
 
+func TestBigNumber(t *testing.T) {
+	lexer := lexForString("18446744073709551616")
+	sym := &yySymType{}
+	if l := lexer.Lex(sym); l != BIGNUMBER {
+		t.Fatalf("Expected lexem BIGNUMBER (%v) got %v", BIGNUMBER, l)
+	}
+	if sym.BigNumber.String() != "18446744073709551616" {
+		t.Errorf("Expected number value 18446744073709551616 got %v", sym.BigNumber)
+	}
+}
+
 func TestAssignment(t *testing.T) {
 	testLexemType(t, "::=", ASSIGNMENT)
 }
@@ -158,6 +170,75 @@ func TestRightVersionBrackets(t *testing.T) {
 	testLexemType(t, "]]", RIGHT_VERSION_BRACKETS)
 }
 
+func cstr(t *yySymType) string {
+	return t.cstring
+}
+
+func bstr(t *yySymType) string {
+	return t.bstring
+}
+
+func hstr(t *yySymType) string {
+	return t.hstring
+}
+
+func TestCString(t *testing.T) {
+	testLexem(t, cstr, `"abc"`, CSTRING, "abc")
+	testLexem(t, cstr, `""`, CSTRING, "")
+	testLexem(t, cstr, `"a ""quoted"" word"`, CSTRING, `a "quoted" word`)
+	testLexem(t, cstr, `"a
+		   b  c  
+		d"`, CSTRING, "ab  cd")
+	testLexem(t, cstr, `"-- not a comment"`, CSTRING, "-- not a comment")
+	testError(t, `"abc`, "line 1: unterminated cstring")
+}
+
+func TestBString(t *testing.T) {
+	testLexem(t, bstr, "'0101'B", BSTRING, "0101")
+	testLexem(t, bstr, "''B", BSTRING, "")
+	testLexem(t, bstr, "'01 01\n  11'B", BSTRING, "010111")
+	testError(t, "'0121'B", "line 1: invalid character '2' in bstring '0121'")
+	testError(t, "'0101'", "line 1: expected B or H after '0101'")
+}
+
+func TestHString(t *testing.T) {
+	testLexem(t, hstr, "'0AFF'H", HSTRING, "0AFF")
+	testLexem(t, hstr, "'0A FF\n 12'H", HSTRING, "0AFF12")
+	testError(t, "'0aff'H", "line 1: invalid character 'a' in hstring '0aff'")
+	testError(t, "'0AFF'X", "line 1: expected B or H after '0AFF', got X")
+	testError(t, "'0AFF", "line 1: unterminated bstring or hstring")
+}
+
+func TestXMLTypedValue(t *testing.T) {
+	lex := lexForString("::= <Seq><a>1</a>\n<b/></Seq> next < x")
+	expectedTypes := []int{ASSIGNMENT, XML_TYPED_VALUE, VALUEIDENTIFIER, LESS}
+	var values []XMLValue
+	for _, expectedType := range expectedTypes {
+		symType := &yySymType{}
+		if gotType := lex.Lex(symType); gotType != expectedType {
+			t.Errorf("Expected %v token, got %v", expectedType, gotType)
+		}
+		values = append(values, symType.XMLValue)
+	}
+	if lex.err != nil {
+		t.Errorf("Expected nil error, got %v", lex.err)
+	}
+	expected := XMLValue{Name: "Seq", Content: []XMLContent{
+		XMLValue{Name: "a", Content: []XMLContent{XMLText("1")}}, XMLText("\n"), XMLValue{Name: "b"},
+	}}
+	if !reflect.DeepEqual(values[1], expected) {
+		t.Errorf("Expected %+v, got %+v", expected, values[1])
+	}
+	if lex.lineNo != 1 {
+		t.Errorf("Expected newline in XML value to be counted, got line %v", lex.lineNo)
+	}
+	lex = lexForString("::= <Seq>")
+	lex.Lex(&yySymType{})
+	if lex.Lex(&yySymType{}) != -1 || lex.err == nil {
+		t.Errorf("Expected error for unterminated XML value, got %v", lex.err)
+	}
+}
+
 func TestPeekRunes(t *testing.T) {
 	lexer := lexForString("aХc￥eЙ")
 	if v := lexer.peekRunes(1); v != "a" {
@@ -195,8 +276,6 @@ func TestSingleSymbolTokens(t *testing.T) {
 	testLexemType(t, "-", MINUS)
 	testLexemType(t, ":", COLON)
 	testLexemType(t, "=", EQUALS)
-	testLexemType(t, "\"", QUOTATION_MARK)
-	testLexemType(t, "'", APOSTROPHE)
 	//testLexemType(t, " ", SPACE)  // TODO
 	testLexemType(t, ";", SEMICOLON)
 	testLexemType(t, "@", AT)
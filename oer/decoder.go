@@ -0,0 +1,517 @@
+package oer
+
+import (
+	"encoding/asn1"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"time"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/chemikadze/asn1go/der"
+)
+
+// Decoder reads OER encoded values one by one.
+//
+// By default, Decoder accepts any valid BASIC-OER encoding. With Canonical set, it also rejects encodings
+// which are not canonical, e.g. non-minimal lengths and numbers, non-zero padding bits, or encoded DEFAULT values.
+// Decoders of open types inherit the mode.
+type Decoder struct {
+	// Canonical enables restrictions of canonical OER.
+	Canonical bool
+	data      []byte
+}
+
+// NewDecoder returns decoder reading values from data.
+func NewDecoder(data []byte) *Decoder {
+	return &Decoder{data: data}
+}
+
+// Rest returns data which was not read yet.
+func (d *Decoder) Rest() []byte {
+	return d.data
+}
+
+// readOctets reads n octets.
+func (d *Decoder) readOctets(n int) ([]byte, error) {
+	if n < 0 || n > len(d.data) {
+		return nil, SyntaxError{fmt.Sprintf("data truncated, %v octets expected, got %v", n, len(d.data))}
+	}
+	res := d.data[:n:n]
+	d.data = d.data[n:]
+	return res, nil
+}
+
+// readOctet reads a single octet.
+func (d *Decoder) readOctet() (byte, error) {
+	data, err := d.readOctets(1)
+	if err != nil {
+		return 0, err
+	}
+	return data[0], nil
+}
+
+// ReadBoolean reads BOOLEAN value. Any non-zero octet is TRUE, but canonical encoding of TRUE is 0xFF.
+func (d *Decoder) ReadBoolean(v *bool) error {
+	b, err := d.readOctet()
+	if err != nil {
+		return err
+	}
+	if d.Canonical && b != 0x00 && b != 0xff {
+		return SyntaxError{fmt.Sprintf("non-canonical BOOLEAN value %#x", b)}
+	}
+	*v = b != 0
+	return nil
+}
+
+// ReadInteger reads INTEGER value constrained by c, see Encoder.WriteInteger.
+func (d *Decoder) ReadInteger(v *int64, c Constraint) error {
+	size, signed := c.integerSize()
+	var contents []byte
+	var err error
+	if size > 0 {
+		contents, err = d.readOctets(size)
+	} else {
+		contents, err = d.readNumber(signed)
+	}
+	if err != nil {
+		return err
+	}
+	if len(contents) > 8 || (!signed && len(contents) == 8 && contents[0]&0x80 != 0) {
+		return SyntaxError{"INTEGER value does not fit into 64 bits"}
+	}
+	res := decodeUnsigned(contents)
+	if signed && len(contents) < 8 && contents[0]&0x80 != 0 {
+		res -= 1 << (8 * len(contents))
+	}
+	if !c.contains(int64(res)) {
+		return SyntaxError{fmt.Sprintf("value %v does not satisfy constraint %v", int64(res), c)}
+	}
+	*v = int64(res)
+	return nil
+}
+
+// ReadBigInteger reads INTEGER value of arbitrary size constrained by c, see Encoder.WriteInteger.
+func (d *Decoder) ReadBigInteger(v **big.Int, c Constraint) error {
+	size, signed := c.integerSize()
+	var contents []byte
+	var err error
+	if size > 0 {
+		contents, err = d.readOctets(size)
+	} else {
+		contents, err = d.readNumber(signed)
+	}
+	if err != nil {
+		return err
+	}
+	res := new(big.Int).SetBytes(contents)
+	if signed && contents[0]&0x80 != 0 {
+		res.Sub(res, new(big.Int).Lsh(big.NewInt(1), uint(8*len(contents))))
+	}
+	if (c.HasLower && res.Cmp(big.NewInt(c.Lower)) < 0) || (c.HasUpper && res.Cmp(big.NewInt(c.Upper)) > 0) {
+		return SyntaxError{fmt.Sprintf("value %v does not satisfy constraint %v", res, c)}
+	}
+	*v = res
+	return nil
+}
+
+// readNumber reads contents of a number preceded by length determinant.
+// In canonical mode, numbers should be encoded in minimal number of octets.
+func (d *Decoder) readNumber(signed bool) ([]byte, error) {
+	n, err := d.readLength()
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, SyntaxError{"number has no contents octets"}
+	}
+	contents, err := d.readOctets(n)
+	if err != nil {
+		return nil, err
+	}
+	if d.Canonical && !minimal(contents, signed) {
+		return nil, SyntaxError{"number is not encoded in minimal number of octets"}
+	}
+	return contents, nil
+}
+
+// minimal returns true if number is encoded in minimal number of octets.
+func minimal(contents []byte, signed bool) bool {
+	if len(contents) < 2 {
+		return true
+	}
+	if !signed {
+		return contents[0] != 0
+	}
+	return !(contents[0] == 0x00 && contents[1]&0x80 == 0) && !(contents[0] == 0xff && contents[1]&0x80 != 0)
+}
+
+// decodeUnsigned returns big-endian number in up to 8 octets.
+func decodeUnsigned(contents []byte) uint64 {
+	var res uint64
+	for _, b := range contents {
+		res = res<<8 | uint64(b)
+	}
+	return res
+}
+
+// ReadEnumerated reads ENUMERATED value. Unknown values are accepted only if enumeration is extensible.
+func (d *Decoder) ReadEnumerated(v *asn1.Enumerated, en Enumeration) error {
+	first, err := d.readOctet()
+	if err != nil {
+		return err
+	}
+	var res int64
+	if first&0x80 == 0 {
+		res = int64(first)
+	} else {
+		n := int(first & 0x7f)
+		if n == 0 || n > 8 {
+			return SyntaxError{fmt.Sprintf("invalid length %v of ENUMERATED value", n)}
+		}
+		contents, err := d.readOctets(n)
+		if err != nil {
+			return err
+		}
+		res = int64(decodeUnsigned(contents))
+		if n < 8 && contents[0]&0x80 != 0 {
+			res -= 1 << (8 * n)
+		}
+		if d.Canonical && (!minimal(contents, true) || (res >= 0 && res <= 127)) {
+			return SyntaxError{"ENUMERATED value is not encoded in minimal number of octets"}
+		}
+	}
+	if !en.Extensible && !en.contains(res) {
+		return SyntaxError{fmt.Sprintf("unknown enumeration value %v", res)}
+	}
+	*v = asn1.Enumerated(res)
+	return nil
+}
+
+// ReadReal reads REAL value. In canonical mode, value should be encoded as in DER.
+func (d *Decoder) ReadReal(v *float64) error {
+	contents, err := d.readOctetsWithLength()
+	if err != nil {
+		return err
+	}
+	return d.derDecoder(der.TagReal, contents).ReadReal(der.TagReal, v)
+}
+
+// derDecoder returns decoder of DER package reading primitive value with the contents octets.
+func (d *Decoder) derDecoder(tag der.Tag, contents []byte) *der.Decoder {
+	var enc der.Encoder
+	enc.WriteRawValue(asn1.RawValue{Class: int(tag.Class), Tag: tag.Number, Bytes: contents})
+	res := der.NewDecoder(enc.Bytes())
+	res.Strict = d.Canonical
+	return res
+}
+
+// readOctetsWithLength reads octets preceded by length determinant.
+func (d *Decoder) readOctetsWithLength() ([]byte, error) {
+	n, err := d.readLength()
+	if err != nil {
+		return nil, err
+	}
+	return d.readOctets(n)
+}
+
+// ReadBitString reads BIT STRING value with number of bits constrained by size, see Encoder.WriteBitString.
+func (d *Decoder) ReadBitString(v *asn1.BitString, size Constraint) error {
+	var res asn1.BitString
+	if size.fixed() {
+		data, err := d.readOctets(int((size.Lower + 7) / 8))
+		if err != nil {
+			return err
+		}
+		res = asn1.BitString{Bytes: data, BitLength: int(size.Lower)}
+	} else {
+		data, err := d.readOctetsWithLength()
+		if err != nil {
+			return err
+		}
+		if len(data) == 0 || data[0] > 7 || (len(data) == 1 && data[0] != 0) {
+			return SyntaxError{"invalid number of padding bits of BIT STRING"}
+		}
+		res = asn1.BitString{Bytes: data[1:], BitLength: 8*(len(data)-1) - int(data[0])}
+	}
+	if n := len(res.Bytes); d.Canonical && n > 0 && res.Bytes[n-1]&^(0xff<<(8*n-res.BitLength)) != 0 {
+		return SyntaxError{"padding bits of BIT STRING are not zero"}
+	}
+	if !size.contains(int64(res.BitLength)) {
+		return SyntaxError{fmt.Sprintf("size %v does not satisfy constraint %v", res.BitLength, size)}
+	}
+	*v = res
+	return nil
+}
+
+// ReadNamedBitString reads BIT STRING value of type with named bits. In canonical mode,
+// values should not have trailing zero bits, unless they are required by size constraint.
+func (d *Decoder) ReadNamedBitString(v *asn1.BitString, size Constraint) error {
+	if err := d.ReadBitString(v, size); err != nil {
+		return err
+	}
+	if d.Canonical && v.BitLength > 0 && v.At(v.BitLength-1) == 0 && (!size.HasLower || int64(v.BitLength) > size.Lower) {
+		return SyntaxError{"BIT STRING with named bits has trailing zero bits"}
+	}
+	return nil
+}
+
+// ReadOctetString reads OCTET STRING value with number of octets constrained by size.
+func (d *Decoder) ReadOctetString(v *[]byte, size Constraint) error {
+	var data []byte
+	var err error
+	if size.fixed() {
+		data, err = d.readOctets(int(size.Lower))
+	} else {
+		data, err = d.readOctetsWithLength()
+	}
+	if err != nil {
+		return err
+	}
+	if !size.contains(int64(len(data))) {
+		return SyntaxError{fmt.Sprintf("size %v does not satisfy constraint %v", len(data), size)}
+	}
+	*v = data
+	return nil
+}
+
+// ReadString reads value of character string type which encodes characters in single octets,
+// see Encoder.WriteString.
+func (d *Decoder) ReadString(v *string, size Constraint) error {
+	var data []byte
+	if err := d.ReadOctetString(&data, size); err != nil {
+		return err
+	}
+	*v = string(data)
+	return nil
+}
+
+// ReadBMPString reads BMPString value, which encodes characters in two octets.
+func (d *Decoder) ReadBMPString(v *string, size Constraint) error {
+	data, err := d.readCharacters(2, size)
+	if err != nil {
+		return err
+	}
+	units := make([]uint16, 0, len(data)/2)
+	for i := 0; i < len(data); i += 2 {
+		units = append(units, binary.BigEndian.Uint16(data[i:]))
+	}
+	*v = string(utf16.Decode(units))
+	return nil
+}
+
+// ReadUniversalString reads UniversalString value, which encodes characters in four octets.
+func (d *Decoder) ReadUniversalString(v *string, size Constraint) error {
+	data, err := d.readCharacters(4, size)
+	if err != nil {
+		return err
+	}
+	runes := make([]rune, 0, len(data)/4)
+	for i := 0; i < len(data); i += 4 {
+		r := rune(binary.BigEndian.Uint32(data[i:]))
+		if !utf8.ValidRune(r) {
+			return SyntaxError{fmt.Sprintf("invalid character %#x of UniversalString", uint32(r))}
+		}
+		runes = append(runes, r)
+	}
+	*v = string(runes)
+	return nil
+}
+
+// readCharacters reads encoded characters of known-multiplier character string, where each character
+// is encoded in width octets.
+func (d *Decoder) readCharacters(width int, size Constraint) ([]byte, error) {
+	var data []byte
+	var err error
+	if size.fixed() {
+		data, err = d.readOctets(width * int(size.Lower))
+	} else {
+		data, err = d.readOctetsWithLength()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(data)%width != 0 {
+		return nil, SyntaxError{fmt.Sprintf("length %v is not a multiple of character size %v", len(data), width)}
+	}
+	if n := len(data) / width; !size.contains(int64(n)) {
+		return nil, SyntaxError{fmt.Sprintf("size %v does not satisfy constraint %v", n, size)}
+	}
+	return data, nil
+}
+
+// ReadObjectIdentifier reads OBJECT IDENTIFIER value.
+func (d *Decoder) ReadObjectIdentifier(v *asn1.ObjectIdentifier) error {
+	contents, err := d.readOctetsWithLength()
+	if err != nil {
+		return err
+	}
+	return d.derDecoder(der.TagObjectIdentifier, contents).ReadObjectIdentifier(der.TagObjectIdentifier, v)
+}
+
+// ReadGeneralizedTime reads GeneralizedTime value. Any format allowed by BER is accepted,
+// unless decoder is in canonical mode.
+func (d *Decoder) ReadGeneralizedTime(v *time.Time) error {
+	contents, err := d.readOctetsWithLength()
+	if err != nil {
+		return err
+	}
+	return d.derDecoder(der.TagGeneralizedTime, contents).ReadGeneralizedTime(der.TagGeneralizedTime, v)
+}
+
+// ReadUTCTime reads UTCTime value. Any format allowed by BER is accepted, unless decoder is in canonical mode.
+func (d *Decoder) ReadUTCTime(v *time.Time) error {
+	contents, err := d.readOctetsWithLength()
+	if err != nil {
+		return err
+	}
+	return d.derDecoder(der.TagUTCTime, contents).ReadUTCTime(der.TagUTCTime, v)
+}
+
+// ReadTag reads tag of CHOICE alternative, see Encoder.WriteTag.
+func (d *Decoder) ReadTag() (der.Tag, error) {
+	first, err := d.readOctet()
+	if err != nil {
+		return der.Tag{}, err
+	}
+	tag := der.Tag{Class: der.Class(first >> 6), Number: int(first & 0x3f)}
+	if tag.Number < 63 {
+		return tag, nil
+	}
+	tag.Number = 0
+	for i := 0; ; i++ {
+		b, err := d.readOctet()
+		if err != nil {
+			return der.Tag{}, err
+		}
+		if i == 0 && b == 0x80 {
+			return der.Tag{}, SyntaxError{"tag number is not encoded in minimal number of octets"}
+		}
+		if tag.Number >= 1<<24 {
+			return der.Tag{}, SyntaxError{"tag number is too large"}
+		}
+		tag.Number = tag.Number<<7 | int(b&0x7f)
+		if b&0x80 == 0 {
+			break
+		}
+	}
+	if tag.Number < 63 {
+		return der.Tag{}, SyntaxError{fmt.Sprintf("tag number %v should be encoded in the first octet", tag.Number)}
+	}
+	return tag, nil
+}
+
+// ReadBitmap reads presence bitmap of n bits, see Encoder.WriteBitmap.
+func (d *Decoder) ReadBitmap(n int) ([]bool, error) {
+	data, err := d.readOctets((n + 7) / 8)
+	if err != nil {
+		return nil, err
+	}
+	return d.bits(data, n)
+}
+
+// bits returns first n bits of data. In canonical mode, remaining bits should be zero.
+func (d *Decoder) bits(data []byte, n int) ([]bool, error) {
+	res := make([]bool, 8*len(data))
+	for i := range res {
+		res[i] = data[i/8]&(0x80>>(i%8)) != 0
+	}
+	for _, padding := range res[n:] {
+		if padding && d.Canonical {
+			return nil, SyntaxError{"padding bits of bitmap are not zero"}
+		}
+	}
+	return res[:n], nil
+}
+
+// ReadExtensionBitmap reads presence bitmap of extension additions, see Encoder.WriteExtensionBitmap.
+func (d *Decoder) ReadExtensionBitmap() ([]bool, error) {
+	data, err := d.readOctetsWithLength()
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 2 || data[0] > 7 {
+		return nil, SyntaxError{"invalid extension bitmap"}
+	}
+	return d.bits(data[1:], 8*(len(data)-1)-int(data[0]))
+}
+
+// ReadOpenType reads encoding preceded by its length with fn, see Encoder.WriteOpenType.
+// In canonical mode, fn should read complete encoding.
+func (d *Decoder) ReadOpenType(fn func(d *Decoder) error) error {
+	data, err := d.readOctetsWithLength()
+	if err != nil {
+		return err
+	}
+	inner := &Decoder{Canonical: d.Canonical, data: data}
+	if err := fn(inner); err != nil {
+		return err
+	}
+	if d.Canonical && len(inner.data) > 0 {
+		return SyntaxError{fmt.Sprintf("open type has %v octets of trailing data", len(inner.data))}
+	}
+	return nil
+}
+
+// SkipOpenType skips encoding preceded by its length, e.g. value of unknown extension addition.
+func (d *Decoder) SkipOpenType() error {
+	_, err := d.readOctetsWithLength()
+	return err
+}
+
+// ReadList reads elements of SEQUENCE OF or SET OF value with fn, preceded by their quantity.
+func (d *Decoder) ReadList(fn func() error) error {
+	contents, err := d.readNumber(false)
+	if err != nil {
+		return err
+	}
+	if len(contents) > 8 {
+		return SyntaxError{"quantity does not fit into 64 bits"}
+	}
+	n := decodeUnsigned(contents)
+	// every element is encoded in at least one octet, except for empty types, e.g. NULL
+	if n > uint64(len(d.data)) && len(d.data) > 0 {
+		return SyntaxError{fmt.Sprintf("quantity %v exceeds length of remaining data", n)}
+	}
+	for i := uint64(0); i < n; i++ {
+		if err := fn(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readLength reads length determinant, see Encoder.writeLength.
+// In canonical mode, lengths should be encoded in minimal number of octets.
+func (d *Decoder) readLength() (int, error) {
+	first, err := d.readOctet()
+	if err != nil {
+		return 0, err
+	}
+	if first&0x80 == 0 {
+		return int(first), nil
+	}
+	n := int(first & 0x7f)
+	if n == 0 {
+		return 0, SyntaxError{"invalid length determinant"}
+	}
+	contents, err := d.readOctets(n)
+	if err != nil {
+		return 0, err
+	}
+	if d.Canonical && (!minimal(contents, false) || decodeUnsigned(contents) <= 127) {
+		return 0, SyntaxError{"length is not encoded in minimal number of octets"}
+	}
+	for len(contents) > 0 && contents[0] == 0 {
+		contents = contents[1:]
+	}
+	if len(contents) > 4 {
+		return 0, SyntaxError{"length does not fit into 32 bits"}
+	}
+	length := decodeUnsigned(contents)
+	if length > uint64(len(d.data)) {
+		return 0, SyntaxError{fmt.Sprintf("data truncated, %v octets expected, got %v", length, len(d.data))}
+	}
+	return int(length), nil
+}
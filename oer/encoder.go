@@ -0,0 +1,385 @@
+package oer
+
+import (
+	"encoding/asn1"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"time"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/chemikadze/asn1go/der"
+)
+
+// Encoder appends OER encodings of values to a buffer. Zero value is ready to use.
+type Encoder struct {
+	buf []byte
+}
+
+// Bytes returns encoded data.
+func (e *Encoder) Bytes() []byte {
+	return e.buf
+}
+
+// Reset discards encoded data, so that encoder can be reused.
+func (e *Encoder) Reset() {
+	e.buf = e.buf[:0]
+}
+
+// WriteBoolean appends BOOLEAN value.
+func (e *Encoder) WriteBoolean(v bool) {
+	if v {
+		e.buf = append(e.buf, 0xff)
+	} else {
+		e.buf = append(e.buf, 0x00)
+	}
+}
+
+// WriteInteger appends INTEGER value constrained by c. Values with both bounds fitting into 64 bits are encoded
+// as fixed-size numbers, and other values are encoded as numbers of minimal size preceded by length determinant.
+func (e *Encoder) WriteInteger(v int64, c Constraint) error {
+	if !c.contains(v) {
+		return fmt.Errorf("oer: value %v does not satisfy constraint %v", v, c)
+	}
+	size, signed := c.integerSize()
+	switch {
+	case size > 0:
+		e.buf = appendFixed(e.buf, uint64(v), size)
+	case signed:
+		contents := appendSigned(nil, v)
+		e.writeLength(len(contents))
+		e.buf = append(e.buf, contents...)
+	default:
+		contents := appendUnsigned(nil, uint64(v))
+		e.writeLength(len(contents))
+		e.buf = append(e.buf, contents...)
+	}
+	return nil
+}
+
+// WriteBigInteger appends INTEGER value of arbitrary size constrained by c, see WriteInteger.
+func (e *Encoder) WriteBigInteger(v *big.Int, c Constraint) error {
+	if v == nil {
+		return fmt.Errorf("oer: INTEGER value is nil")
+	}
+	if size, _ := c.integerSize(); size > 0 || v.IsInt64() {
+		if !v.IsInt64() {
+			return fmt.Errorf("oer: value %v does not satisfy constraint %v", v, c)
+		}
+		return e.WriteInteger(v.Int64(), c)
+	}
+	if (c.HasLower && v.Cmp(big.NewInt(c.Lower)) < 0) || (c.HasUpper && v.Cmp(big.NewInt(c.Upper)) > 0) {
+		return fmt.Errorf("oer: value %v does not satisfy constraint %v", v, c)
+	}
+	var contents []byte
+	if _, signed := c.integerSize(); signed {
+		contents = appendBigSigned(nil, v)
+	} else {
+		contents = v.Bytes()
+	}
+	e.writeLength(len(contents))
+	e.buf = append(e.buf, contents...)
+	return nil
+}
+
+// WriteEnumerated appends ENUMERATED value. Values from 0 to 127 are encoded in a single octet,
+// and other values are encoded as numbers of minimal size preceded by their length.
+func (e *Encoder) WriteEnumerated(v asn1.Enumerated, en Enumeration) error {
+	if !en.contains(int64(v)) {
+		return fmt.Errorf("oer: unknown enumeration value %v", v)
+	}
+	if v >= 0 && v <= 127 {
+		e.buf = append(e.buf, byte(v))
+		return nil
+	}
+	contents := appendSigned(nil, int64(v))
+	e.buf = append(e.buf, 0x80|byte(len(contents)))
+	e.buf = append(e.buf, contents...)
+	return nil
+}
+
+// WriteReal appends REAL value as contents octets of its DER encoding preceded by length determinant.
+func (e *Encoder) WriteReal(v float64) error {
+	var enc der.Encoder
+	enc.WriteReal(der.TagReal, v)
+	return e.writeDERContents(enc.Bytes())
+}
+
+// writeDERContents appends contents octets of DER encoding of a primitive value preceded by length determinant.
+func (e *Encoder) writeDERContents(encoding []byte) error {
+	var raw asn1.RawValue
+	if err := der.NewDecoder(encoding).ReadRawValue(&raw); err != nil {
+		return err
+	}
+	e.writeOctetsWithLength(raw.Bytes)
+	return nil
+}
+
+// writeOctetsWithLength appends octets preceded by length determinant.
+func (e *Encoder) writeOctetsWithLength(data []byte) {
+	e.writeLength(len(data))
+	e.buf = append(e.buf, data...)
+}
+
+// WriteBitString appends BIT STRING value with number of bits constrained by size. Values of fixed size
+// are encoded as bits padded to octet boundary, and other values are preceded by length determinant
+// and number of padding bits.
+func (e *Encoder) WriteBitString(v asn1.BitString, size Constraint) error {
+	if !size.contains(int64(v.BitLength)) {
+		return fmt.Errorf("oer: size %v does not satisfy constraint %v", v.BitLength, size)
+	}
+	n := (v.BitLength + 7) / 8
+	if len(v.Bytes) < n {
+		return fmt.Errorf("oer: BIT STRING has %v bits, but only %v bytes", v.BitLength, len(v.Bytes))
+	}
+	data := make([]byte, n)
+	copy(data, v.Bytes)
+	if padding := 8*n - v.BitLength; padding > 0 {
+		data[n-1] &= 0xff << padding
+	}
+	if size.fixed() {
+		e.buf = append(e.buf, data...)
+		return nil
+	}
+	e.writeLength(n + 1)
+	e.buf = append(e.buf, byte(8*n-v.BitLength))
+	e.buf = append(e.buf, data...)
+	return nil
+}
+
+// WriteNamedBitString appends BIT STRING value of type with named bits. Trailing zero bits are removed
+// from values which are not of fixed size, and values of fixed size are padded with zero bits.
+func (e *Encoder) WriteNamedBitString(v asn1.BitString, size Constraint) error {
+	for v.BitLength > 0 && v.At(v.BitLength-1) == 0 {
+		v.BitLength--
+	}
+	if size.HasLower && int64(v.BitLength) < size.Lower {
+		bytes := make([]byte, (size.Lower+7)/8)
+		copy(bytes, v.Bytes[:(v.BitLength+7)/8])
+		v = asn1.BitString{Bytes: bytes, BitLength: int(size.Lower)}
+	}
+	return e.WriteBitString(v, size)
+}
+
+// WriteOctetString appends OCTET STRING value with number of octets constrained by size.
+// Values of fixed size are encoded without length determinant.
+func (e *Encoder) WriteOctetString(v []byte, size Constraint) error {
+	if !size.contains(int64(len(v))) {
+		return fmt.Errorf("oer: size %v does not satisfy constraint %v", len(v), size)
+	}
+	if size.fixed() {
+		e.buf = append(e.buf, v...)
+		return nil
+	}
+	e.writeOctetsWithLength(v)
+	return nil
+}
+
+// WriteString appends value of character string type which encodes characters in single octets,
+// e.g. IA5String or UTF8String. Number of characters is constrained by size, which is OER-visible
+// for known-multiplier character string types only. Values of fixed size are encoded without length determinant.
+func (e *Encoder) WriteString(v string, size Constraint) error {
+	return e.WriteOctetString([]byte(v), size)
+}
+
+// WriteBMPString appends BMPString value, which encodes characters in two octets.
+func (e *Encoder) WriteBMPString(v string, size Constraint) error {
+	units := utf16.Encode([]rune(v))
+	data := make([]byte, 0, 2*len(units))
+	for _, r := range units {
+		if utf16.IsSurrogate(rune(r)) {
+			return fmt.Errorf("oer: character %q can not be encoded in BMPString", v)
+		}
+		data = binary.BigEndian.AppendUint16(data, r)
+	}
+	return e.writeCharacters(data, len(units), size)
+}
+
+// WriteUniversalString appends UniversalString value, which encodes characters in four octets.
+func (e *Encoder) WriteUniversalString(v string, size Constraint) error {
+	data := make([]byte, 0, 4*utf8.RuneCountInString(v))
+	for _, r := range v {
+		data = binary.BigEndian.AppendUint32(data, uint32(r))
+	}
+	return e.writeCharacters(data, len(data)/4, size)
+}
+
+// writeCharacters appends encoded characters of known-multiplier character string.
+func (e *Encoder) writeCharacters(data []byte, n int, size Constraint) error {
+	if !size.contains(int64(n)) {
+		return fmt.Errorf("oer: size %v does not satisfy constraint %v", n, size)
+	}
+	if size.fixed() {
+		e.buf = append(e.buf, data...)
+		return nil
+	}
+	e.writeOctetsWithLength(data)
+	return nil
+}
+
+// WriteObjectIdentifier appends OBJECT IDENTIFIER value as contents octets of its DER encoding preceded
+// by length determinant.
+func (e *Encoder) WriteObjectIdentifier(v asn1.ObjectIdentifier) error {
+	var enc der.Encoder
+	if err := enc.WriteObjectIdentifier(der.TagObjectIdentifier, v); err != nil {
+		return err
+	}
+	return e.writeDERContents(enc.Bytes())
+}
+
+// WriteGeneralizedTime appends GeneralizedTime value, which is encoded as VisibleString in the same format as DER.
+func (e *Encoder) WriteGeneralizedTime(v time.Time) error {
+	var enc der.Encoder
+	if err := enc.WriteGeneralizedTime(der.TagGeneralizedTime, v); err != nil {
+		return err
+	}
+	return e.writeDERContents(enc.Bytes())
+}
+
+// WriteUTCTime appends UTCTime value, which is encoded as VisibleString in the same format as DER.
+func (e *Encoder) WriteUTCTime(v time.Time) error {
+	var enc der.Encoder
+	if err := enc.WriteUTCTime(der.TagUTCTime, v); err != nil {
+		return err
+	}
+	return e.writeDERContents(enc.Bytes())
+}
+
+// WriteTag appends tag of CHOICE alternative. Class of the tag is encoded in two most significant bits
+// of the first octet, followed by tag number.
+func (e *Encoder) WriteTag(tag der.Tag) {
+	class := byte(tag.Class) << 6
+	if tag.Number < 63 {
+		e.buf = append(e.buf, class|byte(tag.Number))
+		return
+	}
+	e.buf = append(e.buf, class|0x3f)
+	e.buf = appendBase128(e.buf, uint64(tag.Number))
+}
+
+// WriteBitmap appends presence bitmap of SEQUENCE or SET components, preceded by the extension bit
+// if type is extensible. Bits are padded with zeros to octet boundary.
+func (e *Encoder) WriteBitmap(bits ...bool) {
+	e.buf = appendBits(e.buf, bits)
+}
+
+// WriteExtensionBitmap appends presence bitmap of extension additions, which is encoded as BIT STRING
+// preceded by length determinant.
+func (e *Encoder) WriteExtensionBitmap(bits []bool) {
+	n := (len(bits) + 7) / 8
+	e.writeLength(n + 1)
+	e.buf = append(e.buf, byte(8*n-len(bits)))
+	e.buf = appendBits(e.buf, bits)
+}
+
+// WriteOpenType appends encoding written by fn preceded by its length. Open types hold values
+// of extension additions and of extension alternatives of CHOICE.
+func (e *Encoder) WriteOpenType(fn func(e *Encoder) error) error {
+	var inner Encoder
+	if err := fn(&inner); err != nil {
+		return err
+	}
+	e.writeOctetsWithLength(inner.buf)
+	return nil
+}
+
+// WriteList appends n elements of SEQUENCE OF or SET OF value written by fn, preceded by their quantity.
+// Size constraints of SEQUENCE OF and SET OF types are not OER-visible.
+func (e *Encoder) WriteList(n int, fn func(i int) error) error {
+	quantity := appendUnsigned(nil, uint64(n))
+	e.writeLength(len(quantity))
+	e.buf = append(e.buf, quantity...)
+	for i := 0; i < n; i++ {
+		if err := fn(i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeLength appends length determinant. Lengths up to 127 are encoded in a single octet,
+// and other lengths are encoded as numbers of minimal size preceded by their length.
+func (e *Encoder) writeLength(n int) {
+	if n <= 127 {
+		e.buf = append(e.buf, byte(n))
+		return
+	}
+	contents := appendUnsigned(nil, uint64(n))
+	e.buf = append(e.buf, 0x80|byte(len(contents)))
+	e.buf = append(e.buf, contents...)
+}
+
+// appendFixed appends two's complement representation of v in size octets.
+func appendFixed(dst []byte, v uint64, size int) []byte {
+	for i := size - 1; i >= 0; i-- {
+		dst = append(dst, byte(v>>(8*i)))
+	}
+	return dst
+}
+
+// appendUnsigned appends v in minimal number of octets, which is at least one.
+func appendUnsigned(dst []byte, v uint64) []byte {
+	size := 1
+	for size < 8 && v >= 1<<(8*size) {
+		size++
+	}
+	return appendFixed(dst, v, size)
+}
+
+// appendSigned appends two's complement representation of v in minimal number of octets.
+func appendSigned(dst []byte, v int64) []byte {
+	size := 1
+	for size < 8 && (v < -1<<(8*size-1) || v >= 1<<(8*size-1)) {
+		size++
+	}
+	return appendFixed(dst, uint64(v), size)
+}
+
+// appendBigSigned appends two's complement representation of v in minimal number of octets.
+func appendBigSigned(dst []byte, v *big.Int) []byte {
+	if v.Sign() >= 0 {
+		contents := v.Bytes()
+		if len(contents) == 0 || contents[0]&0x80 != 0 {
+			dst = append(dst, 0x00)
+		}
+		return append(dst, contents...)
+	}
+	// two's complement of negative value is bitwise complement of its absolute value minus one
+	abs := new(big.Int).Neg(v)
+	abs.Sub(abs, big.NewInt(1))
+	contents := abs.Bytes()
+	if len(contents) == 0 || contents[0]&0x80 != 0 {
+		dst = append(dst, 0xff)
+	}
+	for _, b := range contents {
+		dst = append(dst, ^b)
+	}
+	return dst
+}
+
+// appendBase128 appends v as base-128 number, where all octets except the last have the highest bit set.
+func appendBase128(dst []byte, v uint64) []byte {
+	var tmp [10]byte
+	i := len(tmp) - 1
+	tmp[i] = byte(v & 0x7f)
+	for v >>= 7; v > 0; v >>= 7 {
+		i--
+		tmp[i] = byte(v&0x7f) | 0x80
+	}
+	return append(dst, tmp[i:]...)
+}
+
+// appendBits appends bits starting from the most significant bit of each octet, padded with zeros.
+func appendBits(dst []byte, bits []bool) []byte {
+	for i := 0; i < len(bits); i += 8 {
+		var b byte
+		for j := 0; j < 8 && i+j < len(bits); j++ {
+			if bits[i+j] {
+				b |= 0x80 >> j
+			}
+		}
+		dst = append(dst, b)
+	}
+	return dst
+}
@@ -0,0 +1,147 @@
+// Package oer implements Octet Encoding Rules of ASN.1, as defined in X.696.
+//
+// It is a runtime library of the code generated by asn1go with GEN_OER code generator type,
+// and is not intended to be used directly. Encoder always produces canonical encodings (COER),
+// which are also valid BASIC-OER encodings. Decoder accepts any BASIC-OER encoding, or, in canonical mode,
+// only canonical ones, which is required to verify signatures over decoded values.
+// OER-visible constraints of generated types are passed to encoder and decoder methods as Constraint values.
+package oer
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+)
+
+// ErrAbsentValue is returned when value of CHOICE to encode is nil.
+var ErrAbsentValue = errors.New("oer: value of CHOICE is not set")
+
+// SyntaxError is returned when decoded data is not a valid OER encoding, or, in canonical mode,
+// is not a canonical encoding.
+type SyntaxError struct {
+	Msg string
+}
+
+func (e SyntaxError) Error() string {
+	return "oer: syntax error: " + e.Msg
+}
+
+// Marshaler is implemented by generated types, which encode themselves without reflection.
+type Marshaler interface {
+	// EncodeOER appends OER encoding of the value to e.
+	EncodeOER(e *Encoder) error
+}
+
+// Unmarshaler is implemented by generated types, which decode themselves without reflection.
+type Unmarshaler interface {
+	// DecodeOER decodes the next value of d.
+	DecodeOER(d *Decoder) error
+}
+
+// Marshal returns canonical OER encoding of the value.
+func Marshal(v Marshaler) ([]byte, error) {
+	var e Encoder
+	if err := v.EncodeOER(&e); err != nil {
+		return nil, err
+	}
+	return e.Bytes(), nil
+}
+
+// Unmarshal decodes BASIC-OER encoding of the value from data, and returns remaining data.
+func Unmarshal(data []byte, v Unmarshaler) ([]byte, error) {
+	return unmarshal(NewDecoder(data), v)
+}
+
+// UnmarshalCanonical is same as Unmarshal, but rejects encodings which are not canonical.
+func UnmarshalCanonical(data []byte, v Unmarshaler) ([]byte, error) {
+	d := NewDecoder(data)
+	d.Canonical = true
+	return unmarshal(d, v)
+}
+
+func unmarshal(d *Decoder, v Unmarshaler) ([]byte, error) {
+	if err := v.DecodeOER(d); err != nil {
+		return nil, err
+	}
+	return d.Rest(), nil
+}
+
+// Constraint is OER-visible constraint of INTEGER value, or of number of items in BIT STRING,
+// OCTET STRING or known-multiplier character string value. Zero value means that value is not constrained.
+// Constraints with extension marker are not OER-visible.
+type Constraint struct {
+	// Lower is the lower bound, if HasLower is set.
+	Lower int64
+	// Upper is the upper bound, if HasUpper is set.
+	Upper    int64
+	HasLower bool
+	HasUpper bool
+}
+
+// contains returns true if value is within bounds of the constraint.
+func (c Constraint) contains(v int64) bool {
+	return (!c.HasLower || v >= c.Lower) && (!c.HasUpper || v <= c.Upper)
+}
+
+// fixed returns true if constraint allows single value only. Values of fixed size are encoded
+// without length determinant.
+func (c Constraint) fixed() bool {
+	return c.HasLower && c.HasUpper && c.Lower == c.Upper
+}
+
+// String returns constraint in ASN.1 notation, e.g. (0..MAX).
+func (c Constraint) String() string {
+	lower, upper := "MIN", "MAX"
+	if c.HasLower {
+		lower = strconv.FormatInt(c.Lower, 10)
+	}
+	if c.HasUpper {
+		upper = strconv.FormatInt(c.Upper, 10)
+	}
+	if c.fixed() {
+		return "(" + lower + ")"
+	}
+	return "(" + lower + ".." + upper + ")"
+}
+
+// integerSize returns number of octets of fixed-size encoding of INTEGER values with the constraint,
+// and whether they are signed. Zero size means that values are encoded with length determinant.
+func (c Constraint) integerSize() (size int, signed bool) {
+	switch {
+	case !c.HasLower:
+		return 0, true
+	case c.Lower >= 0 && !c.HasUpper:
+		return 0, false
+	case c.Lower >= 0:
+		for _, size := range []int{1, 2, 4} {
+			if c.Upper < 1<<(8*size) {
+				return size, false
+			}
+		}
+		return 8, false
+	case !c.HasUpper:
+		return 0, true
+	default:
+		for _, size := range []int{1, 2, 4} {
+			limit := int64(1) << (8*size - 1)
+			if c.Lower >= -limit && c.Upper < limit {
+				return size, true
+			}
+		}
+		return 8, true
+	}
+}
+
+// Enumeration describes values of ENUMERATED type.
+type Enumeration struct {
+	// Values are values of root enumeration and additional enumeration.
+	Values []int64
+	// Extensible is set if type has extension marker, in which case values of unknown
+	// additions are decoded as is.
+	Extensible bool
+}
+
+// contains returns true if v is a known value of the enumeration.
+func (en Enumeration) contains(v int64) bool {
+	return slices.Contains(en.Values, v)
+}
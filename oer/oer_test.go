@@ -0,0 +1,575 @@
+package oer
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"math"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/chemikadze/asn1go/der"
+)
+
+func bounds(lower, upper int64) Constraint {
+	return Constraint{Lower: lower, Upper: upper, HasLower: true, HasUpper: true}
+}
+
+func readInteger(c Constraint) func(d *Decoder) (any, error) {
+	return func(d *Decoder) (any, error) {
+		var i int64
+		err := d.ReadInteger(&i, c)
+		return i, err
+	}
+}
+
+func TestEncoding(t *testing.T) {
+	enumeration := Enumeration{Values: []int64{-1, 5, 200}}
+	long := bytes.Repeat([]byte{0xab}, 200)
+	testCases := []struct {
+		name     string
+		write    func(e *Encoder) error
+		read     func(d *Decoder) (any, error)
+		value    any
+		expected []byte
+	}{
+		{
+			name:     "boolean",
+			write:    func(e *Encoder) error { e.WriteBoolean(true); return nil },
+			read:     func(d *Decoder) (any, error) { var b bool; err := d.ReadBoolean(&b); return b, err },
+			value:    true,
+			expected: []byte{0xff},
+		},
+		{
+			name:     "unsigned integer in one octet",
+			write:    func(e *Encoder) error { return e.WriteInteger(200, bounds(0, 255)) },
+			read:     readInteger(bounds(0, 255)),
+			value:    int64(200),
+			expected: []byte{0xc8},
+		},
+		{
+			name:     "unsigned integer in two octets",
+			write:    func(e *Encoder) error { return e.WriteInteger(256, bounds(1, 65535)) },
+			read:     readInteger(bounds(1, 65535)),
+			value:    int64(256),
+			expected: []byte{0x01, 0x00},
+		},
+		{
+			name:     "unsigned integer in four octets",
+			write:    func(e *Encoder) error { return e.WriteInteger(1, bounds(0, math.MaxUint32)) },
+			read:     readInteger(bounds(0, math.MaxUint32)),
+			value:    int64(1),
+			expected: []byte{0x00, 0x00, 0x00, 0x01},
+		},
+		{
+			name:     "unsigned integer in eight octets",
+			write:    func(e *Encoder) error { return e.WriteInteger(math.MaxInt64, bounds(0, math.MaxInt64)) },
+			read:     readInteger(bounds(0, math.MaxInt64)),
+			value:    int64(math.MaxInt64),
+			expected: []byte{0x7f, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+		},
+		{
+			name:     "signed integer in one octet",
+			write:    func(e *Encoder) error { return e.WriteInteger(-1, bounds(-128, 127)) },
+			read:     readInteger(bounds(-128, 127)),
+			value:    int64(-1),
+			expected: []byte{0xff},
+		},
+		{
+			name:     "signed integer in two octets",
+			write:    func(e *Encoder) error { return e.WriteInteger(-1000, bounds(-1000, 1000)) },
+			read:     readInteger(bounds(-1000, 1000)),
+			value:    int64(-1000),
+			expected: []byte{0xfc, 0x18},
+		},
+		{
+			name:     "non-negative integer with length",
+			write:    func(e *Encoder) error { return e.WriteInteger(256, Constraint{Lower: 0, HasLower: true}) },
+			read:     readInteger(Constraint{Lower: 0, HasLower: true}),
+			value:    int64(256),
+			expected: []byte{0x02, 0x01, 0x00},
+		},
+		{
+			name:     "unsigned integer with length is not padded",
+			write:    func(e *Encoder) error { return e.WriteInteger(128, Constraint{Lower: 0, HasLower: true}) },
+			read:     readInteger(Constraint{Lower: 0, HasLower: true}),
+			value:    int64(128),
+			expected: []byte{0x01, 0x80},
+		},
+		{
+			name:     "unconstrained integer",
+			write:    func(e *Encoder) error { return e.WriteInteger(-129, Constraint{}) },
+			read:     readInteger(Constraint{}),
+			value:    int64(-129),
+			expected: []byte{0x02, 0xff, 0x7f},
+		},
+		{
+			name:     "signed integer with upper bound only",
+			write:    func(e *Encoder) error { return e.WriteInteger(128, Constraint{Upper: 1000, HasUpper: true}) },
+			read:     readInteger(Constraint{Upper: 1000, HasUpper: true}),
+			value:    int64(128),
+			expected: []byte{0x02, 0x00, 0x80},
+		},
+		{
+			name: "big integer",
+			write: func(e *Encoder) error {
+				return e.WriteBigInteger(new(big.Int).Lsh(big.NewInt(1), 64), Constraint{})
+			},
+			read: func(d *Decoder) (any, error) {
+				var i *big.Int
+				err := d.ReadBigInteger(&i, Constraint{})
+				return i.String(), err
+			},
+			value:    "18446744073709551616",
+			expected: []byte{0x09, 0x01, 0, 0, 0, 0, 0, 0, 0, 0},
+		},
+		{
+			name: "negative big integer",
+			write: func(e *Encoder) error {
+				return e.WriteBigInteger(new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), 64)), Constraint{})
+			},
+			read: func(d *Decoder) (any, error) {
+				var i *big.Int
+				err := d.ReadBigInteger(&i, Constraint{})
+				return i.String(), err
+			},
+			value:    "-18446744073709551616",
+			expected: []byte{0x09, 0xff, 0, 0, 0, 0, 0, 0, 0, 0},
+		},
+		{
+			name:  "enumerated in one octet",
+			write: func(e *Encoder) error { return e.WriteEnumerated(5, enumeration) },
+			read: func(d *Decoder) (any, error) {
+				var v asn1.Enumerated
+				err := d.ReadEnumerated(&v, enumeration)
+				return v, err
+			},
+			value:    asn1.Enumerated(5),
+			expected: []byte{0x05},
+		},
+		{
+			name:  "enumerated with length",
+			write: func(e *Encoder) error { return e.WriteEnumerated(200, enumeration) },
+			read: func(d *Decoder) (any, error) {
+				var v asn1.Enumerated
+				err := d.ReadEnumerated(&v, enumeration)
+				return v, err
+			},
+			value:    asn1.Enumerated(200),
+			expected: []byte{0x82, 0x00, 0xc8},
+		},
+		{
+			name:  "negative enumerated",
+			write: func(e *Encoder) error { return e.WriteEnumerated(-1, enumeration) },
+			read: func(d *Decoder) (any, error) {
+				var v asn1.Enumerated
+				err := d.ReadEnumerated(&v, enumeration)
+				return v, err
+			},
+			value:    asn1.Enumerated(-1),
+			expected: []byte{0x81, 0xff},
+		},
+		{
+			name:  "real",
+			write: func(e *Encoder) error { return e.WriteReal(0.5) },
+			read: func(d *Decoder) (any, error) {
+				var v float64
+				err := d.ReadReal(&v)
+				return v, err
+			},
+			value:    0.5,
+			expected: []byte{0x03, 0x80, 0xff, 0x01},
+		},
+		{
+			name: "bit string of fixed size",
+			write: func(e *Encoder) error {
+				return e.WriteBitString(asn1.BitString{Bytes: []byte{0xab, 0xcf}, BitLength: 12}, bounds(12, 12))
+			},
+			read: func(d *Decoder) (any, error) {
+				var v asn1.BitString
+				err := d.ReadBitString(&v, bounds(12, 12))
+				return v, err
+			},
+			value:    asn1.BitString{Bytes: []byte{0xab, 0xc0}, BitLength: 12},
+			expected: []byte{0xab, 0xc0},
+		},
+		{
+			name: "bit string with length",
+			write: func(e *Encoder) error {
+				return e.WriteBitString(asn1.BitString{Bytes: []byte{0xa0}, BitLength: 3}, bounds(0, 8))
+			},
+			read: func(d *Decoder) (any, error) {
+				var v asn1.BitString
+				err := d.ReadBitString(&v, bounds(0, 8))
+				return v, err
+			},
+			value:    asn1.BitString{Bytes: []byte{0xa0}, BitLength: 3},
+			expected: []byte{0x02, 0x05, 0xa0},
+		},
+		{
+			name: "empty bit string",
+			write: func(e *Encoder) error {
+				return e.WriteBitString(asn1.BitString{}, Constraint{})
+			},
+			read: func(d *Decoder) (any, error) {
+				var v asn1.BitString
+				err := d.ReadBitString(&v, Constraint{})
+				return v, err
+			},
+			value:    asn1.BitString{Bytes: []byte{}},
+			expected: []byte{0x01, 0x00},
+		},
+		{
+			name: "named bit string without trailing zeros",
+			write: func(e *Encoder) error {
+				return e.WriteNamedBitString(asn1.BitString{Bytes: []byte{0x40, 0x00}, BitLength: 16}, Constraint{})
+			},
+			read: func(d *Decoder) (any, error) {
+				var v asn1.BitString
+				err := d.ReadNamedBitString(&v, Constraint{})
+				return v, err
+			},
+			value:    asn1.BitString{Bytes: []byte{0x40}, BitLength: 2},
+			expected: []byte{0x02, 0x06, 0x40},
+		},
+		{
+			name:  "octet string of fixed size",
+			write: func(e *Encoder) error { return e.WriteOctetString([]byte("abc"), bounds(3, 3)) },
+			read: func(d *Decoder) (any, error) {
+				var v []byte
+				err := d.ReadOctetString(&v, bounds(3, 3))
+				return v, err
+			},
+			value:    []byte("abc"),
+			expected: []byte("abc"),
+		},
+		{
+			name:  "octet string with long length",
+			write: func(e *Encoder) error { return e.WriteOctetString(long, Constraint{}) },
+			read: func(d *Decoder) (any, error) {
+				var v []byte
+				err := d.ReadOctetString(&v, Constraint{})
+				return v, err
+			},
+			value:    long,
+			expected: append([]byte{0x81, 0xc8}, long...),
+		},
+		{
+			name:  "character string",
+			write: func(e *Encoder) error { return e.WriteString("abc", bounds(1, 4)) },
+			read: func(d *Decoder) (any, error) {
+				var v string
+				err := d.ReadString(&v, bounds(1, 4))
+				return v, err
+			},
+			value:    "abc",
+			expected: []byte{0x03, 'a', 'b', 'c'},
+		},
+		{
+			name:  "BMPString of fixed size",
+			write: func(e *Encoder) error { return e.WriteBMPString("Aé", bounds(2, 2)) },
+			read: func(d *Decoder) (any, error) {
+				var v string
+				err := d.ReadBMPString(&v, bounds(2, 2))
+				return v, err
+			},
+			value:    "Aé",
+			expected: []byte{0x00, 0x41, 0x00, 0xe9},
+		},
+		{
+			name:  "UniversalString",
+			write: func(e *Encoder) error { return e.WriteUniversalString("A", Constraint{}) },
+			read: func(d *Decoder) (any, error) {
+				var v string
+				err := d.ReadUniversalString(&v, Constraint{})
+				return v, err
+			},
+			value:    "A",
+			expected: []byte{0x04, 0x00, 0x00, 0x00, 0x41},
+		},
+		{
+			name:  "object identifier",
+			write: func(e *Encoder) error { return e.WriteObjectIdentifier(asn1.ObjectIdentifier{1, 2, 840}) },
+			read: func(d *Decoder) (any, error) {
+				var v asn1.ObjectIdentifier
+				err := d.ReadObjectIdentifier(&v)
+				return v, err
+			},
+			value:    asn1.ObjectIdentifier{1, 2, 840},
+			expected: []byte{0x03, 0x2a, 0x86, 0x48},
+		},
+		{
+			name: "generalized time",
+			write: func(e *Encoder) error {
+				return e.WriteGeneralizedTime(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+			},
+			read: func(d *Decoder) (any, error) {
+				var v time.Time
+				err := d.ReadGeneralizedTime(&v)
+				return v, err
+			},
+			value:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			expected: append([]byte{0x0f}, "20240102030405Z"...),
+		},
+		{
+			name:     "context-specific tag",
+			write:    func(e *Encoder) error { e.WriteTag(der.Tag{Class: der.ClassContextSpecific, Number: 3}); return nil },
+			read:     func(d *Decoder) (any, error) { return d.ReadTag() },
+			value:    der.Tag{Class: der.ClassContextSpecific, Number: 3},
+			expected: []byte{0x83},
+		},
+		{
+			name:     "tag with large number",
+			write:    func(e *Encoder) error { e.WriteTag(der.Tag{Class: der.ClassApplication, Number: 200}); return nil },
+			read:     func(d *Decoder) (any, error) { return d.ReadTag() },
+			value:    der.Tag{Class: der.ClassApplication, Number: 200},
+			expected: []byte{0x7f, 0x81, 0x48},
+		},
+		{
+			name:     "bitmap",
+			write:    func(e *Encoder) error { e.WriteBitmap(true, false, true); return nil },
+			read:     func(d *Decoder) (any, error) { return d.ReadBitmap(3) },
+			value:    []bool{true, false, true},
+			expected: []byte{0xa0},
+		},
+		{
+			name:     "extension bitmap",
+			write:    func(e *Encoder) error { e.WriteExtensionBitmap([]bool{true, false}); return nil },
+			read:     func(d *Decoder) (any, error) { return d.ReadExtensionBitmap() },
+			value:    []bool{true, false},
+			expected: []byte{0x02, 0x06, 0x80},
+		},
+		{
+			name: "open type",
+			write: func(e *Encoder) error {
+				return e.WriteOpenType(func(e *Encoder) error { return e.WriteInteger(7, Constraint{}) })
+			},
+			read: func(d *Decoder) (any, error) {
+				var i int64
+				err := d.ReadOpenType(func(d *Decoder) error { return d.ReadInteger(&i, Constraint{}) })
+				return i, err
+			},
+			value:    int64(7),
+			expected: []byte{0x02, 0x01, 0x07},
+		},
+		{
+			name: "list",
+			write: func(e *Encoder) error {
+				items := []bool{true, false}
+				return e.WriteList(len(items), func(i int) error { e.WriteBoolean(items[i]); return nil })
+			},
+			read: func(d *Decoder) (any, error) {
+				var items []bool
+				err := d.ReadList(func() error {
+					var b bool
+					err := d.ReadBoolean(&b)
+					items = append(items, b)
+					return err
+				})
+				return items, err
+			},
+			value:    []bool{true, false},
+			expected: []byte{0x01, 0x02, 0xff, 0x00},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var e Encoder
+			if err := tc.write(&e); err != nil {
+				t.Fatalf("Failed to encode: %v", err)
+			}
+			if !bytes.Equal(e.Bytes(), tc.expected) {
+				t.Errorf("Expected encoding %x, got %x", tc.expected, e.Bytes())
+			}
+			for _, canonical := range []bool{false, true} {
+				d := NewDecoder(tc.expected)
+				d.Canonical = canonical
+				decoded, err := tc.read(d)
+				if err != nil {
+					t.Fatalf("Failed to decode with canonical=%v: %v", canonical, err)
+				}
+				if !reflect.DeepEqual(decoded, tc.value) {
+					t.Errorf("Expected decoded value %#v, got %#v", tc.value, decoded)
+				}
+				if len(d.Rest()) != 0 {
+					t.Errorf("Expected no trailing data, got %x", d.Rest())
+				}
+			}
+		})
+	}
+}
+
+func TestEncoderErrors(t *testing.T) {
+	testCases := []struct {
+		name  string
+		write func(e *Encoder) error
+	}{
+		{
+			name:  "integer out of range",
+			write: func(e *Encoder) error { return e.WriteInteger(256, bounds(0, 255)) },
+		},
+		{
+			name:  "negative value of non-negative integer",
+			write: func(e *Encoder) error { return e.WriteInteger(-1, Constraint{Lower: 0, HasLower: true}) },
+		},
+		{
+			name:  "unknown enumeration value",
+			write: func(e *Encoder) error { return e.WriteEnumerated(3, Enumeration{Values: []int64{0, 1}}) },
+		},
+		{
+			name:  "size out of range",
+			write: func(e *Encoder) error { return e.WriteOctetString([]byte("ab"), bounds(3, 3)) },
+		},
+		{
+			name:  "character out of BMP",
+			write: func(e *Encoder) error { return e.WriteBMPString("\U0001F600", Constraint{}) },
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var e Encoder
+			if err := tc.write(&e); err == nil {
+				t.Errorf("Expected error, got encoding %x", e.Bytes())
+			}
+		})
+	}
+}
+
+func TestDecoderErrors(t *testing.T) {
+	testCases := []struct {
+		name string
+		data []byte
+		read func(d *Decoder) error
+		// nonCanonical is set if data is valid BASIC-OER encoding, which is not canonical.
+		nonCanonical bool
+	}{
+		{
+			name:         "boolean other than 0xff",
+			data:         []byte{0x01},
+			read:         func(d *Decoder) error { var b bool; return d.ReadBoolean(&b) },
+			nonCanonical: true,
+		},
+		{
+			name: "non-minimal length",
+			data: []byte{0x81, 0x03, 'a', 'b', 'c'},
+			read: func(d *Decoder) error {
+				var b []byte
+				return d.ReadOctetString(&b, Constraint{})
+			},
+			nonCanonical: true,
+		},
+		{
+			name:         "non-minimal integer",
+			data:         []byte{0x02, 0x00, 0x05},
+			read:         func(d *Decoder) error { var i int64; return d.ReadInteger(&i, Constraint{}) },
+			nonCanonical: true,
+		},
+		{
+			name: "enumerated in long form",
+			data: []byte{0x81, 0x05},
+			read: func(d *Decoder) error {
+				var v asn1.Enumerated
+				return d.ReadEnumerated(&v, Enumeration{Values: []int64{5}})
+			},
+			nonCanonical: true,
+		},
+		{
+			name: "non-zero padding bits of bit string",
+			data: []byte{0x02, 0x05, 0xa1},
+			read: func(d *Decoder) error {
+				var v asn1.BitString
+				return d.ReadBitString(&v, Constraint{})
+			},
+			nonCanonical: true,
+		},
+		{
+			name: "named bit string with trailing zeros",
+			data: []byte{0x02, 0x00, 0x40},
+			read: func(d *Decoder) error {
+				var v asn1.BitString
+				return d.ReadNamedBitString(&v, Constraint{})
+			},
+			nonCanonical: true,
+		},
+		{
+			name:         "non-zero padding bits of bitmap",
+			data:         []byte{0xa1},
+			read:         func(d *Decoder) error { _, err := d.ReadBitmap(3); return err },
+			nonCanonical: true,
+		},
+		{
+			name: "trailing data of open type",
+			data: []byte{0x02, 0xff, 0x00},
+			read: func(d *Decoder) error {
+				return d.ReadOpenType(func(d *Decoder) error { var b bool; return d.ReadBoolean(&b) })
+			},
+			nonCanonical: true,
+		},
+		{
+			name: "time in format not allowed by DER",
+			data: append([]byte{0x13}, "20240102030405+0100"...),
+			read: func(d *Decoder) error {
+				var v time.Time
+				return d.ReadGeneralizedTime(&v)
+			},
+			nonCanonical: true,
+		},
+		{
+			name: "integer out of range",
+			data: []byte{0xc8},
+			read: func(d *Decoder) error { var i int64; return d.ReadInteger(&i, bounds(0, 7)) },
+		},
+		{
+			name: "truncated integer",
+			data: []byte{0x02, 0x01},
+			read: func(d *Decoder) error { var i int64; return d.ReadInteger(&i, Constraint{}) },
+		},
+		{
+			name: "unknown value of non-extensible enumeration",
+			data: []byte{0x03},
+			read: func(d *Decoder) error {
+				var v asn1.Enumerated
+				return d.ReadEnumerated(&v, Enumeration{Values: []int64{0, 1}})
+			},
+		},
+		{
+			name: "size out of range",
+			data: []byte{0x03, 'a', 'b', 'c'},
+			read: func(d *Decoder) error {
+				var b []byte
+				return d.ReadOctetString(&b, Constraint{Lower: 4, HasLower: true})
+			},
+		},
+		{
+			name: "invalid number of padding bits",
+			data: []byte{0x02, 0x08, 0x00},
+			read: func(d *Decoder) error {
+				var v asn1.BitString
+				return d.ReadBitString(&v, Constraint{})
+			},
+		},
+		{
+			name: "quantity exceeding length of data",
+			data: []byte{0x01, 0x05, 0xff},
+			read: func(d *Decoder) error {
+				return d.ReadList(func() error { var b bool; return d.ReadBoolean(&b) })
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.read(NewDecoder(tc.data))
+			if tc.nonCanonical && err != nil {
+				t.Errorf("Failed to decode BASIC-OER encoding: %v", err)
+			} else if !tc.nonCanonical && err == nil {
+				t.Errorf("Expected error")
+			}
+			d := NewDecoder(tc.data)
+			d.Canonical = true
+			if err := tc.read(d); err == nil {
+				t.Errorf("Expected error in canonical mode")
+			}
+		})
+	}
+}
@@ -2,10 +2,14 @@ package asn1go
 
 import (
 	"bufio"
+	"fmt"
 	"io"
 	"math"
 	"os"
+	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 func init() {
@@ -18,14 +22,27 @@ func ParseString(str string) (*ModuleDefinition, error) {
 }
 
 // ParseStream reads text of ASN.1 definitions from provided reader and parses it into ASN.1 AST.
+// Returns error if input contains more than one module, use ParseModules to parse such inputs.
 func ParseStream(reader io.Reader) (*ModuleDefinition, error) {
+	modules, err := ParseModules(reader)
+	if err != nil {
+		return nil, err
+	}
+	if len(modules) != 1 {
+		return nil, fmt.Errorf("expected single module in the input, got %v", len(modules))
+	}
+	return modules[0], nil
+}
+
+// ParseModules reads text of ASN.1 definitions from provided reader and parses all modules defined in it.
+func ParseModules(reader io.Reader) ([]*ModuleDefinition, error) {
 	lex := &ASN1Lexer{}
 	lex.bufReader = bufio.NewReader(reader)
 	yyParse(lex)
 	if lex.err != nil {
 		return nil, lex.err
 	}
-	return lex.result, nil
+	return lex.results, nil
 }
 
 // ParseFile parses ASN.1 definition file into ASN.1 AST.
@@ -38,6 +55,56 @@ func ParseFile(name string) (*ModuleDefinition, error) {
 	return ParseStream(file)
 }
 
+// ParseModulesFile parses all modules from ASN.1 definition file into ASN.1 AST.
+func ParseModulesFile(name string) ([]*ModuleDefinition, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ParseModules(file)
+}
+
+// parseBString converts content of bstring into BitStringValue.
+// Lexer guarantees that only '0' and '1' characters are present.
+func parseBString(bstring string) BitStringValue {
+	val := BitStringValue{Bytes: make([]byte, (len(bstring)+7)/8), BitLength: len(bstring)}
+	for i, c := range bstring {
+		if c == '1' {
+			val.Bytes[i/8] |= 0x80 >> uint(i%8)
+		}
+	}
+	return val
+}
+
+// parseHString converts content of hstring into BitStringValue.
+// Lexer guarantees that only hexadecimal digits are present.
+func parseHString(hstring string) BitStringValue {
+	val := BitStringValue{Bytes: make([]byte, (len(hstring)+1)/2), BitLength: len(hstring) * 4}
+	for i, c := range hstring {
+		digit, _ := strconv.ParseUint(string(c), 16, 8)
+		if i%2 == 0 {
+			digit <<= 4
+		}
+		val.Bytes[i/2] |= byte(digit)
+	}
+	return val
+}
+
+// valueOfType converts BracedValue to the notation of governing type t, if t is a built-in type.
+// Values of referenced types, and values which are not valid for t, are kept as they are written,
+// and are converted or reported by code generator, see BracedValue.As.
+func valueOfType(t Type, v Value) Value {
+	braced, ok := v.(BracedValue)
+	if !ok {
+		return v
+	}
+	if converted, ok := braced.As(t); ok {
+		return converted
+	}
+	return v
+}
+
 func parseRealNumber(integer Number, fraction Number, exponent Number) Real {
 	value := float64(integer)
 	if fraction != 0 {
@@ -49,3 +116,39 @@ func parseRealNumber(integer Number, fraction Number, exponent Number) Real {
 	}
 	return Real(value)
 }
+
+// xmlTypeNames are names of built-in types used as names of XMLTypedValue elements, see X.680, section 12.36.
+// Names of structured types are not included, as values of such types can be interpreted only with
+// definitions of their components.
+var xmlTypeNames = map[string]Type{
+	"BIT_STRING":        BitStringType{},
+	"BOOLEAN":           BooleanType{},
+	"INTEGER":           IntegerType{},
+	"NULL":              NullType{},
+	"OBJECT_IDENTIFIER": ObjectIdentifierType{},
+	"OCTET_STRING":      OctetStringType{},
+	"REAL":              RealType{},
+}
+
+// xmlValueType returns type of XMLTypedValue element with given name, which is either a name
+// of built-in type, or a type reference.
+func xmlValueType(yylex yyLexer, name string) Type {
+	if t, ok := xmlTypeNames[name]; ok {
+		return t
+	}
+	if name == GeneralizedTimeName || name == UTCTimeName {
+		return TypeReference(name)
+	}
+	if code, ok := reservedWords[name]; ok {
+		if _, ok := restrictedStringTags[code]; ok {
+			return RestrictedStringType{LexType: code}
+		}
+		yylex.Error(fmt.Sprintf("XML value of %v type should be named by type reference", name))
+		return nil
+	}
+	if r, _ := utf8.DecodeRuneInString(name); !unicode.IsUpper(r) || strings.Contains(name, "_") {
+		yylex.Error(fmt.Sprintf("XML value should be named by type reference, got %v", name))
+		return nil
+	}
+	return TypeReference(name)
+}
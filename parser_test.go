@@ -3,6 +3,7 @@ package asn1go
 import (
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -30,6 +31,36 @@ func TestParseMinimalModule(t *testing.T) {
 	}
 }
 
+func TestParseModules(t *testing.T) {
+	content := `
+	First DEFINITIONS ::= BEGIN
+		A ::= INTEGER
+	END
+
+	Second DEFINITIONS IMPLICIT TAGS ::= BEGIN
+		IMPORTS A FROM First;
+		B ::= SEQUENCE OF A
+	END
+	`
+	modules, err := ParseModules(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("Expected nil error, got %v", err.Error())
+	}
+	var names []string
+	for _, module := range modules {
+		names = append(names, module.ModuleIdentifier.Reference)
+	}
+	if diff := cmp.Diff([]string{"First", "Second"}, names); diff != "" {
+		t.Errorf("Module names did not match expected, diff (-want, +got):\n%v", diff)
+	}
+	if modules[1].TagDefault != TAGS_IMPLICIT {
+		t.Errorf("Expected second module to have IMPLICIT TAGS")
+	}
+	if _, err := ParseString(content); err == nil {
+		t.Errorf("Expected ParseString to fail on input with several modules")
+	}
+}
+
 func TestParseImports(t *testing.T) {
 	content := `
 	RFC1157-SNMP DEFINITIONS ::= BEGIN
@@ -235,6 +266,108 @@ func TestTypeTypeConstraint(t *testing.T) {
 	}
 }
 
+func TestIncludesTypeConstraint(t *testing.T) {
+	content := `
+	TestSpec DEFINITIONS ::= BEGIN
+		Small ::= INTEGER (0..7)
+		Smaller ::= INTEGER (INCLUDES Small EXCEPT 0)
+	END
+	`
+	r := testNotFails(t, content)
+	parsedAssignment := r.ModuleBody.AssignmentList.GetType("Smaller")
+	if parsedAssignment == nil {
+		t.Fatal("Expected Smaller in assignments")
+	}
+	elements := parsedAssignment.Type.(ConstraintedType).Constraint.ConstraintSpec.(SubtypeConstraint)[0].(Unions)[0][0]
+	if included, ok := elements.Elements.(TypeConstraint); !ok || included.Type != TypeReference("Small") {
+		t.Errorf("Expected type constraint of Small, got %v", elements.Elements)
+	}
+	if elements.Exclusions.Elements != (SingleValue{Number(0)}) {
+		t.Errorf("Expected exclusion of 0, got %v", elements.Exclusions.Elements)
+	}
+}
+
+func TestStringAndContentsConstraints(t *testing.T) {
+	content := `
+	TestSpec DEFINITIONS ::= BEGIN
+		Digits ::= IA5String (FROM ("0".."9"))
+		Word ::= UTF8String (PATTERN "[a-z]+")
+		Wrapped ::= OCTET STRING (CONTAINING Word)
+		Encoded ::= OCTET STRING (CONTAINING Word ENCODED BY { 1 2 3 })
+	END
+	`
+	r := testNotFails(t, content)
+	elements := func(name string) Elements {
+		return r.ModuleBody.AssignmentList.GetType(name).Type.(ConstraintedType).Constraint.ConstraintSpec.(SubtypeConstraint)[0].(Unions)[0][0].Elements
+	}
+	expectedAlphabet := PermittedAlphabet{Constraint{ConstraintSpec: SubtypeConstraint{
+		Unions{Intersections{{Elements: ValueRange{
+			LowerEndpoint: RangeEndpoint{Value: CharacterStringValue{CString("0")}},
+			UpperEndpoint: RangeEndpoint{Value: CharacterStringValue{CString("9")}},
+		}}}},
+	}}}
+	if diff := cmp.Diff(expectedAlphabet, elements("Digits")); diff != "" {
+		t.Errorf("Unexpected permitted alphabet (-want +got):\n%v", diff)
+	}
+	if diff := cmp.Diff(PatternConstraint{CharacterStringValue{CString("[a-z]+")}}, elements("Word")); diff != "" {
+		t.Errorf("Unexpected pattern (-want +got):\n%v", diff)
+	}
+	contents := r.ModuleBody.AssignmentList.GetType("Wrapped").Type.(ConstraintedType).Constraint.ConstraintSpec
+	if diff := cmp.Diff(ContentsConstraint{Type: TypeReference("Word")}, contents); diff != "" {
+		t.Errorf("Unexpected contents constraint (-want +got):\n%v", diff)
+	}
+	encoded := r.ModuleBody.AssignmentList.GetType("Encoded").Type.(ConstraintedType).Constraint.ConstraintSpec.(ContentsConstraint)
+	if encoded.Type != TypeReference("Word") || encoded.EncodedBy == nil {
+		t.Errorf("Expected contents constraint with encoding, got %#v", encoded)
+	}
+}
+
+func TestInnerTypeConstraint(t *testing.T) {
+	content := `
+	TestSpec DEFINITIONS ::= BEGIN
+		Full ::= Msg (WITH COMPONENTS { a (0..7) PRESENT, b ABSENT, c OPTIONAL, d })
+		Partial ::= Msg (WITH COMPONENTS { ..., a (1) })
+		Elements ::= Msgs (WITH COMPONENT (WITH COMPONENTS { ..., b PRESENT }))
+	END
+	`
+	r := testNotFails(t, content)
+	elements := func(name string) Elements {
+		return r.ModuleBody.AssignmentList.GetType(name).Type.(ConstraintedType).Constraint.ConstraintSpec.(SubtypeConstraint)[0].(Unions)[0][0].Elements
+	}
+	small := SingleElementConstraint(ValueRange{LowerEndpoint: RangeEndpoint{Value: Number(0)}, UpperEndpoint: RangeEndpoint{Value: Number(7)}})
+	one := SingleElementConstraint(SingleValue{Number(1)})
+	presentB := SingleElementConstraint(InnerTypeConstraint{Components: []NamedConstraint{{Identifier: "b", Presence: PRESENCE_PRESENT}}, Partial: true})
+	testCases := []struct {
+		name     string
+		expected InnerTypeConstraint
+	}{
+		{
+			name: "Full",
+			expected: InnerTypeConstraint{Components: []NamedConstraint{
+				{Identifier: "a", Constraint: &small, Presence: PRESENCE_PRESENT},
+				{Identifier: "b", Presence: PRESENCE_ABSENT},
+				{Identifier: "c", Presence: PRESENCE_OPTIONAL},
+				{Identifier: "d"},
+			}},
+		},
+		{
+			name:     "Partial",
+			expected: InnerTypeConstraint{Components: []NamedConstraint{{Identifier: "a", Constraint: &one}}, Partial: true},
+		},
+		{
+			name:     "Elements",
+			expected: InnerTypeConstraint{Component: &presentB},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.expected, elements(tc.name)); diff != "" {
+				t.Errorf("Unexpected constraint (-want +got):\n%v", diff)
+			}
+		})
+	}
+}
+
 func TestSequenceWithTagsAndSequenceOf(t *testing.T) {
 	content := `
 	KerberosV5Spec2 DEFINITIONS ::= BEGIN
@@ -308,6 +441,44 @@ func TestBitStringWithSizeConstraint(t *testing.T) {
 	}
 }
 
+func TestExtensibleConstraint(t *testing.T) {
+	content := `
+	TestSpec DEFINITIONS ::= BEGIN
+		Closed ::= INTEGER (0..10)
+		Open ::= INTEGER (0..10, ...)
+		Extended ::= INTEGER (0..10, ..., 20)
+	END
+	`
+	r := testNotFails(t, content)
+	rootRange := Unions{Intersections{IntersectionElements{Elements: ValueRange{
+		LowerEndpoint: RangeEndpoint{Value: Number(0)},
+		UpperEndpoint: RangeEndpoint{Value: Number(10)},
+	}}}}
+	testCases := []struct {
+		name     string
+		expected SubtypeConstraint
+	}{
+		{name: "Closed", expected: SubtypeConstraint{rootRange}},
+		{name: "Open", expected: SubtypeConstraint{rootRange, Unions{}}},
+		{name: "Extended", expected: SubtypeConstraint{rootRange, Unions{Intersections{IntersectionElements{Elements: SingleValue{Number(20)}}}}}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			parsedAssignment := r.ModuleBody.AssignmentList.GetType(tc.name)
+			if parsedAssignment == nil {
+				t.Fatalf("Expected %v in assignments", tc.name)
+			}
+			parsed := parsedAssignment.Type.(ConstraintedType).Constraint.ConstraintSpec.(SubtypeConstraint)
+			if diff := cmp.Diff(tc.expected, parsed); diff != "" {
+				t.Errorf("Constraint did not match expected, diff (-want, +got):\n%v", diff)
+			}
+			if parsed.Extensible() != (tc.name != "Closed") {
+				t.Errorf("Unexpected extensibility of %v", parsed)
+			}
+		})
+	}
+}
+
 func TestConstrainedSequence(t *testing.T) {
 	content := `
 	TestSpec DEFINITIONS ::= BEGIN
@@ -412,6 +583,7 @@ func TestChoiceTypeExtension(t *testing.T) {
 		ExtensionTypes: []ChoiceExtension{
 			NamedType{Identifier("extra-choice"), TypeReference("Extra-Type")},
 		},
+		Extensible: true,
 	}
 	r := testNotFails(t, content)
 	parsedAssignment := r.ModuleBody.AssignmentList.GetType("PDUs")
@@ -434,6 +606,7 @@ func TestRealValues(t *testing.T) {
 		minusReal REAL ::= -1.234
 		plusExp REAL ::= 1.234e3
 		minusExp REAL ::= 1234e-3
+		intReal REAL ::= 5
 	END
 	`
 	expectedDecls := AssignmentList{
@@ -443,6 +616,7 @@ func TestRealValues(t *testing.T) {
 		ValueAssignment{ValueReference("minusReal"), RealType{}, Real(-1.234)},
 		ValueAssignment{ValueReference("plusExp"), RealType{}, Real(1234.0)},
 		ValueAssignment{ValueReference("minusExp"), RealType{}, Real(1.234)},
+		ValueAssignment{ValueReference("intReal"), RealType{}, Number(5)},
 	}
 	r := testNotFails(t, content)
 	// quick and dirty
@@ -451,6 +625,33 @@ func TestRealValues(t *testing.T) {
 	}
 }
 
+func TestBigIntegerValues(t *testing.T) {
+	content := `
+	TestSpec DEFINITIONS ::= BEGIN
+		plusBig INTEGER ::= 18446744073709551616
+		minusBig INTEGER ::= -18446744073709551616
+		minInt64 INTEGER ::= -9223372036854775808
+		Wide ::= INTEGER (0..18446744073709551615)
+	END
+	`
+	expectedDecls := AssignmentList{
+		ValueAssignment{ValueReference("plusBig"), IntegerType{}, BigNumber{Int: bigInt(t, "18446744073709551616")}},
+		ValueAssignment{ValueReference("minusBig"), IntegerType{}, BigNumber{Int: bigInt(t, "-18446744073709551616")}},
+		ValueAssignment{ValueReference("minInt64"), IntegerType{}, Number(-9223372036854775808)},
+		TypeAssignment{TypeReference: "Wide", Type: ConstraintedType{
+			Type: IntegerType{},
+			Constraint: SingleElementConstraint(ValueRange{
+				LowerEndpoint: RangeEndpoint{Value: Number(0)},
+				UpperEndpoint: RangeEndpoint{Value: BigNumber{Int: bigInt(t, "18446744073709551615")}},
+			}),
+		}},
+	}
+	r := testNotFails(t, content)
+	if diff := cmp.Diff(expectedDecls, r.ModuleBody.AssignmentList, bigIntComparer); diff != "" {
+		t.Errorf("Values did not match expected, diff (-want, +got):\n%v", diff)
+	}
+}
+
 func TestBooleanValues(t *testing.T) {
 	content := `
 	TestSpec DEFINITIONS ::= BEGIN
@@ -469,6 +670,169 @@ func TestBooleanValues(t *testing.T) {
 	}
 }
 
+func TestStringValues(t *testing.T) {
+	content := `
+	TestSpec DEFINITIONS ::= BEGIN
+		bits BIT STRING ::= '0101 1'B
+		hex BIT STRING ::= '0AF'H
+		named BIT STRING ::= { flagA, flagC }
+		noBits BIT STRING ::= { }
+		str IA5String ::= "some ""quoted"" text"
+		quadruple UTF8String ::= { 0, 0, 4, 16 }
+		tuple IA5String ::= { 4, 1 }
+		list IA5String ::= { "ab", { 0, 0, 0, 99 }, cr }
+		refList IA5String ::= { cr, lf, "text" }
+	END
+	`
+	expectedDecls := AssignmentList{
+		ValueAssignment{ValueReference("bits"), BitStringType{}, BitStringValue{Bytes: []byte{0x58}, BitLength: 5}},
+		ValueAssignment{ValueReference("hex"), BitStringType{}, BitStringValue{Bytes: []byte{0x0A, 0xF0}, BitLength: 12}},
+		ValueAssignment{ValueReference("named"), BitStringType{}, BitStringValue{NamedBits: []Identifier{"flagA", "flagC"}}},
+		ValueAssignment{ValueReference("noBits"), BitStringType{}, BitStringValue{NamedBits: []Identifier{}}},
+		ValueAssignment{ValueReference("str"), RestrictedStringType{IA5String}, CharacterStringValue{CString(`some "quoted" text`)}},
+		ValueAssignment{ValueReference("quadruple"), RestrictedStringType{UTF8String}, CharacterStringValue{Quadruple{Row: 4, Cell: 16}}},
+		ValueAssignment{ValueReference("tuple"), RestrictedStringType{IA5String}, CharacterStringValue{Tuple{TableColumn: 4, TableRow: 1}}},
+		ValueAssignment{ValueReference("list"), RestrictedStringType{IA5String}, CharacterStringValue{
+			CString("ab"), Quadruple{Cell: 99}, DefinedValue{ValueName: "cr"},
+		}},
+		ValueAssignment{ValueReference("refList"), RestrictedStringType{IA5String}, CharacterStringValue{
+			DefinedValue{ValueName: "cr"}, DefinedValue{ValueName: "lf"}, CString("text"),
+		}},
+	}
+	r := testNotFails(t, content)
+	if diff := cmp.Diff(expectedDecls, r.ModuleBody.AssignmentList); diff != "" {
+		t.Errorf("Assignments did not match expected, diff (-want, +got):\n%v", diff)
+	}
+}
+
+func TestCharacterStringValue(t *testing.T) {
+	val := CharacterStringValue{CString("ab"), Quadruple{Row: 4, Cell: 16}, Tuple{TableColumn: 4, TableRow: 1}}
+	if s, ok := val.StringValue(); !ok || s != "abАA" {
+		t.Errorf("Expected 'abАA', got '%v' (%v)", s, ok)
+	}
+	if _, ok := append(val, DefinedValue{ValueName: "cr"}).StringValue(); ok {
+		t.Errorf("Expected value with references to be not computable")
+	}
+}
+
+func TestStructuredValues(t *testing.T) {
+	content := `
+	TestSpec DEFINITIONS ::= BEGIN
+		nothing NULL ::= NULL
+		choice Choice ::= alt1 : TRUE
+		empty SEQUENCE OF INTEGER ::= {}
+		seq SEQUENCE { name IA5String, inner Inner, count INTEGER } ::= { name "abc", inner { id 1, flag FALSE }, count -1 }
+		seqOf SET OF INTEGER ::= { 1, 2, 3 }
+		named SEQUENCE OF INTEGER ::= { a 1, b 2 }
+		nestedSeqOf SeqOf ::= { { a 1, b 2 }, { a 3, b 4 } }
+	END`
+	seqType := SequenceType{Components: ComponentTypeList{
+		NamedComponentType{NamedType: NamedType{Identifier: "name", Type: RestrictedStringType{IA5String}}},
+		NamedComponentType{NamedType: NamedType{Identifier: "inner", Type: TypeReference("Inner")}},
+		NamedComponentType{NamedType: NamedType{Identifier: "count", Type: IntegerType{}}},
+	}}
+	expectedDecls := AssignmentList{
+		ValueAssignment{ValueReference: "nothing", Type: NullType{}, Value: NullValue{}},
+		ValueAssignment{ValueReference: "choice", Type: TypeReference("Choice"), Value: ChoiceValue{Identifier: "alt1", Value: Boolean(true)}},
+		ValueAssignment{ValueReference: "empty", Type: SequenceOfType{IntegerType{}}, Value: SequenceOfValue{}},
+		ValueAssignment{ValueReference: "seq", Type: seqType, Value: SequenceValue{
+			{Identifier: "name", Value: CharacterStringValue{CString("abc")}},
+			{Identifier: "inner", Value: BracedValue{
+				{IdentifiedIntegerValue{Name: "id"}, Number(1)},
+				{IdentifiedIntegerValue{Name: "flag"}, Boolean(false)},
+			}},
+			{Identifier: "count", Value: Number(-1)},
+		}},
+		ValueAssignment{ValueReference: "seqOf", Type: SetOfType{IntegerType{}}, Value: SequenceOfValue{Number(1), Number(2), Number(3)}},
+		ValueAssignment{ValueReference: "named", Type: SequenceOfType{IntegerType{}}, Value: SequenceOfValue{Number(1), Number(2)}},
+		ValueAssignment{ValueReference: "nestedSeqOf", Type: TypeReference("SeqOf"), Value: BracedValue{
+			{BracedValue{{IdentifiedIntegerValue{Name: "a"}, Number(1)}, {IdentifiedIntegerValue{Name: "b"}, Number(2)}}},
+			{BracedValue{{IdentifiedIntegerValue{Name: "a"}, Number(3)}, {IdentifiedIntegerValue{Name: "b"}, Number(4)}}},
+		}},
+	}
+	r := testNotFails(t, content)
+	if diff := cmp.Diff(expectedDecls, r.ModuleBody.AssignmentList, cmp.AllowUnexported(IdentifiedIntegerValue{})); diff != "" {
+		t.Errorf("ModuleName did not match expected, diff (-want, +got):\n%v", diff)
+	}
+}
+
+func TestBracedValueAs(t *testing.T) {
+	content := `
+	TestSpec DEFINITIONS ::= BEGIN
+		empty Value ::= {}
+		named Value ::= { a 1 }
+		names Value ::= { a, b }
+		numbers Value ::= { 1, 2 }
+		chars Value ::= { "ab", { 0, 0, 0, 99 }, cr }
+		oid Value ::= { iso(1) 2 a }
+	END`
+	r := testNotFails(t, content)
+	bits := BitStringType{}
+	oid := ObjectIdentifierType{}
+	str := RestrictedStringType{IA5String}
+	seq := SequenceType{}
+	list := SequenceOfType{IntegerType{}}
+	testCases := []struct {
+		value    string
+		t        Type
+		expected Value
+	}{
+		{value: "empty", t: bits, expected: BitStringValue{NamedBits: []Identifier{}}},
+		{value: "empty", t: seq, expected: SequenceValue{}},
+		{value: "empty", t: list, expected: SequenceOfValue{}},
+		{value: "empty", t: oid},
+		{value: "empty", t: str},
+		{value: "named", t: oid, expected: ObjectIdentifierValue{{Reference: &DefinedValue{ValueName: "a"}}, {ID: 1}}},
+		{value: "named", t: seq, expected: SequenceValue{{Identifier: "a", Value: Number(1)}}},
+		{value: "named", t: list, expected: SequenceOfValue{Number(1)}},
+		{value: "named", t: bits},
+		{value: "names", t: bits, expected: BitStringValue{NamedBits: []Identifier{"a", "b"}}},
+		{value: "names", t: list, expected: SequenceOfValue{IdentifiedIntegerValue{Name: "a"}, IdentifiedIntegerValue{Name: "b"}}},
+		{value: "names", t: str, expected: CharacterStringValue{DefinedValue{ValueName: "a"}, DefinedValue{ValueName: "b"}}},
+		{value: "names", t: seq},
+		{value: "chars", t: oid},
+		{value: "numbers", t: str, expected: CharacterStringValue{Tuple{TableColumn: 1, TableRow: 2}}},
+		{value: "numbers", t: list, expected: SequenceOfValue{Number(1), Number(2)}},
+		{value: "numbers", t: bits},
+		{value: "numbers", t: IntegerType{}},
+		{value: "chars", t: TaggedType{Type: str}, expected: CharacterStringValue{CString("ab"), Quadruple{Cell: 99}, DefinedValue{ValueName: "cr"}}},
+		{value: "oid", t: oid, expected: ObjectIdentifierValue{{Name: "iso", ID: 1}, {ID: 2}, {Reference: &DefinedValue{ValueName: "a"}}}},
+		{value: "oid", t: seq},
+		{value: "oid", t: list},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%v as %T", tc.value, tc.t), func(t *testing.T) {
+			braced := r.ModuleBody.AssignmentList.GetValue(tc.value).Value.(BracedValue)
+			got, ok := braced.As(tc.t)
+			if ok != (tc.expected != nil) {
+				t.Fatalf("Expected conversion to succeed: %v, got %v", tc.expected != nil, got)
+			}
+			if diff := cmp.Diff(tc.expected, got, cmp.AllowUnexported(IdentifiedIntegerValue{})); diff != "" {
+				t.Errorf("Unexpected value (-want +got):\n%v", diff)
+			}
+		})
+	}
+}
+
+func TestReferencedValue(t *testing.T) {
+	content := `
+	TestSpec DEFINITIONS ::= BEGIN
+		a INTEGER ::= Other.b
+		seq Seq ::= { x Other.c, y 1 }
+	END`
+	expectedDecls := AssignmentList{
+		ValueAssignment{ValueReference: "a", Type: IntegerType{}, Value: DefinedValue{ModuleName: "Other", ValueName: "b"}},
+		ValueAssignment{ValueReference: "seq", Type: TypeReference("Seq"), Value: BracedValue{
+			{IdentifiedIntegerValue{Name: "x"}, DefinedValue{ModuleName: "Other", ValueName: "c"}},
+			{IdentifiedIntegerValue{Name: "y"}, Number(1)},
+		}},
+	}
+	r := testNotFails(t, content)
+	if diff := cmp.Diff(expectedDecls, r.ModuleBody.AssignmentList, cmp.AllowUnexported(IdentifiedIntegerValue{})); diff != "" {
+		t.Errorf("ModuleName did not match expected, diff (-want, +got):\n%v", diff)
+	}
+}
+
 func TestAnyType(t *testing.T) {
 	content := `
 	TestSpec DEFINITIONS ::= BEGIN
@@ -523,6 +887,23 @@ func TestSequenceSyntax(t *testing.T) {
 				TypeAssignment{TypeReference: "Sequence", Type: SequenceType{}},
 			},
 		},
+		{
+			name: "sequence with default values",
+			content: `
+			TestSpec DEFINITIONS ::= BEGIN
+				Sequence ::= SEQUENCE {
+					flag BOOLEAN DEFAULT TRUE,
+					size INTEGER DEFAULT 5
+				}
+			END
+			`,
+			expected: AssignmentList{
+				TypeAssignment{TypeReference: "Sequence", Type: SequenceType{Components: ComponentTypeList{
+					NamedComponentType{NamedType: NamedType{Identifier: "flag", Type: BooleanType{}}, Default: ptr[Value](Boolean(true))},
+					NamedComponentType{NamedType: NamedType{Identifier: "size", Type: IntegerType{}}, Default: ptr[Value](Number(5))},
+				}}},
+			},
+		},
 		{
 			name: "simple sequence",
 			content: `
@@ -566,25 +947,27 @@ func TestSequenceSyntax(t *testing.T) {
 			END
 			`,
 			expected: AssignmentList{
-				TypeAssignment{TypeReference: "SequenceNoFields", Type: SequenceType{}},
-				TypeAssignment{TypeReference: "SequenceEmptyAdditionsNoMarker", Type: SequenceType{Components: ComponentTypeList{
-					NamedComponentType{NamedType: NamedType{Identifier: "field1", Type: BooleanType{}}},
-				}}},
+				TypeAssignment{TypeReference: "SequenceNoFields", Type: SequenceType{Extensible: true}},
+				TypeAssignment{TypeReference: "SequenceEmptyAdditionsNoMarker", Type: SequenceType{
+					Components: ComponentTypeList{
+						NamedComponentType{NamedType: NamedType{Identifier: "field1", Type: BooleanType{}}},
+					},
+					Extensible: true,
+				}},
 				TypeAssignment{TypeReference: "SequenceWithExtensions", Type: SequenceType{
 					Components: ComponentTypeList{
-						// TODO: extensions should be exposed to AST
 						NamedComponentType{NamedType: NamedType{Identifier: "field1", Type: BooleanType{}}},
 					},
 					ExtensionAdditions: ExtensionAdditions{
 						NamedComponentType{NamedType: NamedType{Identifier: "addition1", Type: BooleanType{}}},
 						NamedComponentType{NamedType: NamedType{Identifier: "addition2", Type: BooleanType{}}},
 					},
+					Extensible: true,
 				}},
 			},
 		},
 		{
-			name:       "sequence with two component type lists",
-			skipReason: "Current ComponentTypeLists contains ambiguities and conflicts",
+			name: "sequence with two component type lists",
 			content: `
 			TestSpec DEFINITIONS ::= BEGIN
 				SequenceWithExtensions ::= SEQUENCE {
@@ -594,18 +977,66 @@ func TestSequenceSyntax(t *testing.T) {
 					addition2 BOOLEAN,
 					...,
 					field2 BOOLEAN
+                }
+				SequenceWithEndMarker ::= SEQUENCE {
+					field1 BOOLEAN,
+					...,
+					addition1 BOOLEAN,
+					...
                 }
 			END
 			`,
 			expected: AssignmentList{
-				TypeAssignment{TypeReference: "SequenceNoFields", Type: SequenceType{}},
-				TypeAssignment{TypeReference: "SequenceEmptyAdditionsNoMarker", Type: SequenceType{Components: ComponentTypeList{
-					NamedComponentType{NamedType: NamedType{Identifier: "field1", Type: BooleanType{}}},
-				}}},
-				TypeAssignment{TypeReference: "SequenceWithExtensions", Type: SequenceType{Components: ComponentTypeList{
-					// TODO: extensions should be exposed to AST
-					NamedComponentType{NamedType: NamedType{Identifier: "field1", Type: BooleanType{}}},
-				}}},
+				TypeAssignment{TypeReference: "SequenceWithExtensions", Type: SequenceType{
+					Components: ComponentTypeList{
+						NamedComponentType{NamedType: NamedType{Identifier: "field1", Type: BooleanType{}}},
+						NamedComponentType{NamedType: NamedType{Identifier: "field2", Type: BooleanType{}}},
+					},
+					ExtensionAdditions: ExtensionAdditions{
+						NamedComponentType{NamedType: NamedType{Identifier: "addition1", Type: BooleanType{}}},
+						NamedComponentType{NamedType: NamedType{Identifier: "addition2", Type: BooleanType{}}},
+					},
+					Extensible: true,
+				}},
+				TypeAssignment{TypeReference: "SequenceWithEndMarker", Type: SequenceType{
+					Components: ComponentTypeList{
+						NamedComponentType{NamedType: NamedType{Identifier: "field1", Type: BooleanType{}}},
+					},
+					ExtensionAdditions: ExtensionAdditions{
+						NamedComponentType{NamedType: NamedType{Identifier: "addition1", Type: BooleanType{}}},
+					},
+					Extensible: true,
+				}},
+			},
+		},
+		{
+			name: "sequence with extension addition groups",
+			content: `
+			TestSpec DEFINITIONS ::= BEGIN
+				Sequence ::= SEQUENCE {
+					field1 BOOLEAN,
+					...,
+					[[ 2: addition1 BOOLEAN, addition2 BOOLEAN OPTIONAL ]],
+					[[ addition3 BOOLEAN ]]
+                }
+			END
+			`,
+			expected: AssignmentList{
+				TypeAssignment{TypeReference: "Sequence", Type: SequenceType{
+					Components: ComponentTypeList{
+						NamedComponentType{NamedType: NamedType{Identifier: "field1", Type: BooleanType{}}},
+					},
+					ExtensionAdditions: ExtensionAdditions{
+						ExtensionAdditionGroup{Version: 2, Components: ComponentTypeList{
+							NamedComponentType{NamedType: NamedType{Identifier: "addition1", Type: BooleanType{}}},
+							NamedComponentType{NamedType: NamedType{Identifier: "addition2", Type: BooleanType{}}, IsOptional: true},
+						}},
+						ExtensionAdditionGroup{Components: ComponentTypeList{
+							NamedComponentType{NamedType: NamedType{Identifier: "addition3", Type: BooleanType{}}},
+						}},
+					},
+					Extensible: true,
+				}},
 			},
 		},
 	}
@@ -665,13 +1096,14 @@ func TestChoiceSyntax(t *testing.T) {
 			END
 			`,
 			expected: AssignmentList{
-				TypeAssignment{TypeReference: "Choice", Type: ChoiceType{ExtensionTypes: []ChoiceExtension{}}},
+				TypeAssignment{TypeReference: "Choice", Type: ChoiceType{ExtensionTypes: []ChoiceExtension{}, Extensible: true}},
 				TypeAssignment{TypeReference: "Choice2", Type: ChoiceType{
 					AlternativeTypeList: []NamedType{
 						{Identifier: "alt1", Type: BooleanType{}},
 						{Identifier: "alt2", Type: BooleanType{}},
 					},
-					ExtensionTypes: []ChoiceExtension{}},
+					ExtensionTypes: []ChoiceExtension{},
+					Extensible:     true},
 				},
 				TypeAssignment{TypeReference: "Choice3", Type: ChoiceType{
 					AlternativeTypeList: []NamedType{
@@ -680,7 +1112,37 @@ func TestChoiceSyntax(t *testing.T) {
 					ExtensionTypes: []ChoiceExtension{
 						NamedType{Identifier: "ext2", Type: BooleanType{}},
 						NamedType{Identifier: "ext3", Type: BooleanType{}},
-					}},
+					},
+					Extensible: true},
+				},
+			},
+		},
+		{
+			name: "choice extension groups and end marker",
+			content: `
+			TestSpec DEFINITIONS ::= BEGIN
+				Choice ::= CHOICE {
+					alt1 BOOLEAN,
+					...,
+					[[ 3: ext1 BOOLEAN, ext2 INTEGER ]],
+					ext3 BOOLEAN,
+					...
+				}
+			END
+			`,
+			expected: AssignmentList{
+				TypeAssignment{TypeReference: "Choice", Type: ChoiceType{
+					AlternativeTypeList: []NamedType{
+						{Identifier: "alt1", Type: BooleanType{}},
+					},
+					ExtensionTypes: []ChoiceExtension{
+						ExtensionAdditionAlternativesGroup{Version: 3, Alternatives: []NamedType{
+							{Identifier: "ext1", Type: BooleanType{}},
+							{Identifier: "ext2", Type: IntegerType{}},
+						}},
+						NamedType{Identifier: "ext3", Type: BooleanType{}},
+					},
+					Extensible: true},
 				},
 			},
 		},
@@ -743,8 +1205,7 @@ func TestEnumerationSyntax(t *testing.T) {
 			},
 		},
 		{
-			name:       "enumeration with extensibility",
-			skipReason: "Conflict in yacc rules",
+			name: "enumeration with extensibility",
 			content: `
 			TestSpec DEFINITIONS ::= BEGIN
 				Enum1 ::= ENUMERATED {
@@ -756,10 +1217,23 @@ func TestEnumerationSyntax(t *testing.T) {
 			END
 			`,
 			expected: AssignmentList{
-				TypeAssignment{TypeReference: "Enum1", Type: EnumeratedType{RootEnumeration: []EnumerationItem{
-					Identifier("anon1"),
-					Identifier("anon2"),
-				}}},
+				TypeAssignment{TypeReference: "Enum1", Type: EnumeratedType{
+					RootEnumeration: []EnumerationItem{
+						Identifier("anon1"),
+						Identifier("anon2"),
+					},
+					Extensible: true,
+				}},
+				TypeAssignment{TypeReference: "Enum2", Type: EnumeratedType{
+					RootEnumeration: []EnumerationItem{
+						Identifier("anon1"),
+						Identifier("anon2"),
+					},
+					AdditionalEnumeration: []EnumerationItem{
+						Identifier("anon3"),
+					},
+					Extensible: true,
+				}},
 			},
 		},
 	}
@@ -831,3 +1305,63 @@ func TestIntegerSyntax(t *testing.T) {
 		})
 	}
 }
+
+// ptr returns pointer to a copy of v.
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestXMLValues(t *testing.T) {
+	content := `
+	TestSpec DEFINITIONS ::= BEGIN
+		num ::= <INTEGER>-5</INTEGER>
+		empty ::= <NULL/>
+		str ::= <IA5String>a<lf/> b</IA5String>
+		seq ::= <Seq>
+			<!-- comment -->
+			<name>abc</name>
+			<flag><true/></flag>
+		</Seq>
+		next INTEGER ::= 1
+	END`
+	expectedDecls := AssignmentList{
+		ValueAssignment{ValueReference: "num", Type: IntegerType{}, Value: XMLValue{Name: "INTEGER", Content: []XMLContent{XMLText("-5")}}},
+		ValueAssignment{ValueReference: "empty", Type: NullType{}, Value: XMLValue{Name: "NULL"}},
+		ValueAssignment{ValueReference: "str", Type: RestrictedStringType{IA5String}, Value: XMLValue{Name: "IA5String", Content: []XMLContent{
+			XMLText("a"), XMLValue{Name: "lf"}, XMLText(" b"),
+		}}},
+		ValueAssignment{ValueReference: "seq", Type: TypeReference("Seq"), Value: XMLValue{Name: "Seq", Content: []XMLContent{
+			XMLText("\n\t\t\t\n\t\t\t"),
+			XMLValue{Name: "name", Content: []XMLContent{XMLText("abc")}},
+			XMLText("\n\t\t\t"),
+			XMLValue{Name: "flag", Content: []XMLContent{XMLValue{Name: "true"}}},
+			XMLText("\n\t\t"),
+		}}},
+		ValueAssignment{ValueReference: "next", Type: IntegerType{}, Value: Number(1)},
+	}
+	r := testNotFails(t, content)
+	if diff := cmp.Diff(expectedDecls, r.ModuleBody.AssignmentList); diff != "" {
+		t.Errorf("Assignments did not match expected, diff (-want, +got):\n%v", diff)
+	}
+}
+
+func TestXMLValueErrors(t *testing.T) {
+	testCases := []struct {
+		name    string
+		value   string
+		message string
+	}{
+		{name: "built-in type without XML name", value: "<SEQUENCE/>", message: "should be named by type reference"},
+		{name: "identifier instead of type", value: "<seq/>", message: "should be named by type reference"},
+		{name: "attributes", value: `<Seq a="1"/>`, message: "attributes"},
+		{name: "mismatched tags", value: "<Seq></Other>", message: "invalid XML value"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseString("TestSpec DEFINITIONS ::= BEGIN\n value ::= " + tc.value + "\nEND")
+			if err == nil || !strings.Contains(err.Error(), tc.message) {
+				t.Errorf("Expected error containing %q, got %v", tc.message, err)
+			}
+		})
+	}
+}
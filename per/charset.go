@@ -0,0 +1,119 @@
+package per
+
+// Charset is an alphabet of known-multiplier character string type, see X.691, section 30.
+// Characters are encoded with fixed number of bits, either as their values, or as their indices
+// in the alphabet if values do not fit into that number of bits.
+type Charset struct {
+	// ranges are ranges of permitted characters in ascending order.
+	ranges []charRange
+}
+
+type charRange struct {
+	first, last uint32
+}
+
+// Alphabets of known-multiplier character string types, see X.680, section 41.
+var (
+	IA5String       = Charset{ranges: []charRange{{0, 0x7f}}}
+	VisibleString   = Charset{ranges: []charRange{{0x20, 0x7e}}}
+	NumericString   = Charset{ranges: []charRange{{' ', ' '}, {'0', '9'}}}
+	PrintableString = Charset{ranges: []charRange{
+		{' ', ' '}, {'\'', ')'}, {'+', ':'}, {'=', '='}, {'?', '?'}, {'A', 'Z'}, {'a', 'z'},
+	}}
+	BMPString       = Charset{ranges: []charRange{{0, 0xffff}}}
+	UniversalString = Charset{ranges: []charRange{{0, 0xffffffff}}}
+)
+
+// Restrict returns alphabet of characters of c which are in one of the ranges of first and last characters,
+// i.e. effective alphabet of the type with PER-visible permitted alphabet constraint, see X.691, section 30.1.
+// Ranges must be in ascending order and must not overlap.
+func (c Charset) Restrict(ranges ...[2]rune) Charset {
+	var res Charset
+	for _, r := range c.ranges {
+		for _, p := range ranges {
+			if p[1] < 0 {
+				continue
+			}
+			first, last := max(r.first, uint32(max(p[0], 0))), min(r.last, uint32(p[1]))
+			if first <= last {
+				res.ranges = append(res.ranges, charRange{first, last})
+			}
+		}
+	}
+	return res
+}
+
+// size returns number of characters in the alphabet.
+func (c Charset) size() uint64 {
+	var n uint64
+	for _, r := range c.ranges {
+		n += uint64(r.last-r.first) + 1
+	}
+	return n
+}
+
+// bits returns number of bits used to encode a character, see X.691, section 30.5.2.
+func (c Charset) bits(aligned bool) int {
+	b := bitLength(c.size() - 1)
+	if aligned {
+		// round up to power of 2
+		for p := 1; ; p *= 2 {
+			if p >= b {
+				return p
+			}
+		}
+	}
+	return b
+}
+
+// indexed returns true if characters are encoded as their indices in the alphabet,
+// rather than as their values, see X.691, section 30.5.4.
+func (c Charset) indexed(bits int) bool {
+	if len(c.ranges) == 0 {
+		return false
+	}
+	return uint64(c.ranges[len(c.ranges)-1].last) >= 1<<bits
+}
+
+// encode returns encoding of the character. Returns false if character is not in the alphabet.
+func (c Charset) encode(ch rune, bits int) (uint64, bool) {
+	if ch < 0 {
+		return 0, false
+	}
+	v := uint32(ch)
+	var index uint64
+	for _, r := range c.ranges {
+		if v < r.first {
+			break
+		}
+		if v <= r.last {
+			if c.indexed(bits) {
+				return index + uint64(v-r.first), true
+			}
+			return uint64(v), true
+		}
+		index += uint64(r.last-r.first) + 1
+	}
+	return 0, false
+}
+
+// decode returns character by its encoding. Returns false if encoding does not correspond to
+// a character of the alphabet.
+func (c Charset) decode(v uint64, bits int) (rune, bool) {
+	if !c.indexed(bits) {
+		for _, r := range c.ranges {
+			if v >= uint64(r.first) && v <= uint64(r.last) {
+				return rune(v), true
+			}
+		}
+		return 0, false
+	}
+	for _, r := range c.ranges {
+		n := uint64(r.last-r.first) + 1
+		if v < n {
+			return rune(uint64(r.first) + v), true
+		}
+		v -= n
+	}
+	return 0, false
+}
@@ -0,0 +1,569 @@
+package per
+
+import (
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/chemikadze/asn1go/der"
+)
+
+// Decoder reads values from PER encoding.
+type Decoder struct {
+	aligned bool
+	data    []byte
+	// pos is position of the next bit to read.
+	pos int
+}
+
+// NewDecoder returns decoder reading PER encoding of the variant from data.
+func NewDecoder(data []byte, variant Variant) *Decoder {
+	return &Decoder{aligned: variant == Aligned, data: data}
+}
+
+// variant returns PER variant of the decoder.
+func (d *Decoder) variant() Variant {
+	if d.aligned {
+		return Aligned
+	}
+	return Unaligned
+}
+
+// errTruncated is returned when encoding ends before the value.
+var errTruncated = SyntaxError{"unexpected end of data"}
+
+// readBits reads n bits, most significant first. Number of bits should not exceed 64.
+func (d *Decoder) readBits(n int) (uint64, error) {
+	if n > 8*len(d.data)-d.pos {
+		return 0, errTruncated
+	}
+	var v uint64
+	for n > 0 {
+		free := 8 - d.pos%8
+		k := min(n, free)
+		chunk := uint64(d.data[d.pos/8]>>(free-k)) & (1<<k - 1)
+		v = v<<k | chunk
+		d.pos += k
+		n -= k
+	}
+	return v, nil
+}
+
+// align skips padding bits up to octet boundary in ALIGNED variant.
+func (d *Decoder) align() {
+	if d.aligned {
+		d.pos = (d.pos + 7) &^ 7
+	}
+}
+
+// readOctets reads n octets, which are octet-aligned only if encoding is.
+func (d *Decoder) readOctets(n int) ([]byte, error) {
+	if n > (8*len(d.data)-d.pos)/8 {
+		return nil, errTruncated
+	}
+	res := make([]byte, n)
+	if d.pos%8 == 0 {
+		copy(res, d.data[d.pos/8:])
+		d.pos += 8 * n
+		return res, nil
+	}
+	for i := range res {
+		b, _ := d.readBits(8)
+		res[i] = byte(b)
+	}
+	return res, nil
+}
+
+// ReadBit reads single bit, e.g. extension bit, or presence bit of OPTIONAL component.
+func (d *Decoder) ReadBit() (bool, error) {
+	v, err := d.readBits(1)
+	return v == 1, err
+}
+
+// ReadBitmap reads n presence bits of OPTIONAL and DEFAULT components, see X.691, section 19.2.
+func (d *Decoder) ReadBitmap(n int) ([]bool, error) {
+	res := make([]bool, n)
+	for i := range res {
+		var err error
+		if res[i], err = d.ReadBit(); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// ReadBoolean reads BOOLEAN value.
+func (d *Decoder) ReadBoolean(v *bool) error {
+	var err error
+	*v, err = d.ReadBit()
+	return err
+}
+
+// readConstrained reads constrained whole number, which is offset from the lower bound of the range.
+func (d *Decoder) readConstrained(span uint64) (uint64, error) {
+	var v uint64
+	var err error
+	switch {
+	case span == 0:
+	case !d.aligned || span < 255:
+		v, err = d.readBits(bitLength(span))
+	case span == 255:
+		d.align()
+		v, err = d.readBits(8)
+	case span < 1<<16:
+		d.align()
+		v, err = d.readBits(16)
+	default:
+		var n uint64
+		if n, err = d.readConstrained(uint64(octetLength(span) - 1)); err != nil {
+			return 0, err
+		}
+		d.align()
+		v, err = d.readBits(8 * int(n+1))
+	}
+	if err == nil && v > span {
+		err = SyntaxError{fmt.Sprintf("constrained whole number %v is out of range", v)}
+	}
+	return v, err
+}
+
+// readNormallySmall reads normally small non-negative whole number.
+func (d *Decoder) readNormallySmall() (uint64, error) {
+	large, err := d.ReadBit()
+	if err != nil {
+		return 0, err
+	}
+	if !large {
+		return d.readBits(6)
+	}
+	return d.readSemiConstrained()
+}
+
+// readNumberOctets reads length of whole number in octets.
+func (d *Decoder) readNumberOctets() (int, error) {
+	n, fragmented, err := d.readLength(Constraint{})
+	switch {
+	case err != nil:
+		return 0, err
+	case fragmented || n == 0 || n > 8:
+		return 0, SyntaxError{fmt.Sprintf("unsupported length of whole number: %v octets", n)}
+	default:
+		return n, nil
+	}
+}
+
+// readSemiConstrained reads semi-constrained whole number, which is offset from the lower bound.
+func (d *Decoder) readSemiConstrained() (uint64, error) {
+	n, err := d.readNumberOctets()
+	if err != nil {
+		return 0, err
+	}
+	return d.readBits(8 * n)
+}
+
+// readUnconstrained reads unconstrained whole number.
+func (d *Decoder) readUnconstrained() (int64, error) {
+	n, err := d.readNumberOctets()
+	if err != nil {
+		return 0, err
+	}
+	v, err := d.readBits(8 * n)
+	if err != nil {
+		return 0, err
+	}
+	// sign extension
+	shift := 64 - 8*n
+	return int64(v<<shift) >> shift, nil
+}
+
+// readLength reads length determinant, see Encoder.writeLength.
+func (d *Decoder) readLength(c Constraint) (int, bool, error) {
+	if c = c.sizeConstraint(); c.bounded() {
+		v, err := d.readConstrained(c.span())
+		return int(c.Lower + int64(v)), false, err
+	}
+	d.align()
+	first, err := d.readBits(8)
+	if err != nil {
+		return 0, false, err
+	}
+	switch {
+	case first&0x80 == 0:
+		return int(first), false, nil
+	case first&0x40 == 0:
+		second, err := d.readBits(8)
+		return int(first&0x3f)<<8 | int(second), false, err
+	case first&0x3f >= 1 && first&0x3f <= 4:
+		return int(first&0x3f) * fragmentSize, true, nil
+	default:
+		return 0, false, SyntaxError{fmt.Sprintf("invalid length determinant %#x", first)}
+	}
+}
+
+// readNormallySmallLength reads normally small length.
+func (d *Decoder) readNormallySmallLength() (int, error) {
+	large, err := d.ReadBit()
+	if err != nil {
+		return 0, err
+	}
+	if !large {
+		n, err := d.readBits(6)
+		return int(n) + 1, err
+	}
+	n, fragmented, err := d.readLength(Constraint{})
+	if err == nil && (fragmented || n == 0) {
+		err = SyntaxError{"invalid normally small length"}
+	}
+	return n, err
+}
+
+// effectiveSize reads extension bit of size constraint if it is extensible, and returns
+// constraint which applies to the encoding of size.
+func (d *Decoder) effectiveSize(c Constraint) (Constraint, error) {
+	if !c.Extensible {
+		return c, nil
+	}
+	extended, err := d.ReadBit()
+	if extended {
+		return Constraint{}, err
+	}
+	return c, err
+}
+
+// readItems reads length determinant and items of the value of size constrained by c.
+// Function read is called for every fragment of items with their number.
+func (d *Decoder) readItems(c Constraint, l layout, read func(n int) error) error {
+	c, err := d.effectiveSize(c)
+	if err != nil {
+		return err
+	}
+	if fixedSize(c) {
+		if c.Upper*int64(l.bits) > 16 {
+			d.align()
+		}
+		return read(int(c.Upper))
+	}
+	total := 0
+	for {
+		n, fragmented, err := d.readLength(c)
+		if err != nil {
+			return err
+		}
+		if l.alignedItems(n, c) {
+			d.align()
+		}
+		if l.bits > 0 && n > 8*len(d.data) {
+			return errTruncated
+		}
+		if err := read(n); err != nil {
+			return err
+		}
+		total += n
+		if !fragmented {
+			break
+		}
+	}
+	if !c.contains(int64(total)) {
+		return SyntaxError{fmt.Sprintf("size %v is not in range %v", total, c)}
+	}
+	return nil
+}
+
+// ReadInteger reads INTEGER value.
+func (d *Decoder) ReadInteger(v *int64, c Constraint) error {
+	if c.Extensible {
+		extended, err := d.ReadBit()
+		if err != nil {
+			return err
+		}
+		if extended {
+			*v, err = d.readUnconstrained()
+			return err
+		}
+	}
+	switch {
+	case c.bounded():
+		offset, err := d.readConstrained(c.span())
+		if err != nil {
+			return err
+		}
+		*v = int64(uint64(c.Lower) + offset)
+	case c.HasLower:
+		offset, err := d.readSemiConstrained()
+		if err != nil {
+			return err
+		}
+		res := int64(uint64(c.Lower) + offset)
+		if res < c.Lower {
+			return SyntaxError{"INTEGER value does not fit into int64"}
+		}
+		*v = res
+	default:
+		var err error
+		if *v, err = d.readUnconstrained(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadBigInteger reads INTEGER value represented as big.Int.
+func (d *Decoder) ReadBigInteger(v **big.Int, c Constraint) error {
+	extended := false
+	if c.Extensible {
+		var err error
+		if extended, err = d.ReadBit(); err != nil {
+			return err
+		}
+	}
+	if !extended && c.bounded() {
+		var res int64
+		if err := d.ReadInteger(&res, Constraint{Lower: c.Lower, Upper: c.Upper, HasLower: true, HasUpper: true}); err != nil {
+			return err
+		}
+		*v = big.NewInt(res)
+		return nil
+	}
+	contents, err := d.readOctetsWithLength(Constraint{Lower: 1, HasLower: true})
+	if err != nil {
+		return err
+	}
+	res := new(big.Int)
+	if !extended && c.HasLower {
+		res.SetBytes(contents).Add(res, big.NewInt(c.Lower))
+	} else {
+		res.SetBytes(contents)
+		if contents[0]&0x80 != 0 {
+			res.Sub(res, new(big.Int).Lsh(big.NewInt(1), uint(8*len(contents))))
+		}
+	}
+	*v = res
+	return nil
+}
+
+// ReadEnumerated reads ENUMERATED value.
+// Values of unknown extension additions can not be represented, and are reported as error.
+func (d *Decoder) ReadEnumerated(v *asn1.Enumerated, en Enumeration) error {
+	if en.Extensible {
+		extended, err := d.ReadBit()
+		if err != nil {
+			return err
+		}
+		if extended {
+			i, err := d.readNormallySmall()
+			if err != nil {
+				return err
+			}
+			if i >= uint64(len(en.Additions)) {
+				return SyntaxError{fmt.Sprintf("unknown enumeration addition %v", i)}
+			}
+			*v = asn1.Enumerated(en.Additions[i])
+			return nil
+		}
+	}
+	if len(en.Root) == 0 {
+		return SyntaxError{"enumeration has no root values"}
+	}
+	i, err := d.readConstrained(uint64(len(en.Root) - 1))
+	if err != nil {
+		return err
+	}
+	*v = asn1.Enumerated(en.Root[i])
+	return nil
+}
+
+// ReadReal reads REAL value.
+func (d *Decoder) ReadReal(v *float64) error {
+	contents, err := d.readOctetsWithLength(Constraint{})
+	if err != nil {
+		return err
+	}
+	return der.NewDecoder(derElement(der.TagReal, contents)).ReadReal(der.TagReal, v)
+}
+
+// derElement returns DER encoding of primitive value with the contents octets.
+func derElement(tag der.Tag, contents []byte) []byte {
+	var enc der.Encoder
+	enc.WriteRawValue(asn1.RawValue{Class: int(tag.Class), Tag: tag.Number, Bytes: contents})
+	return enc.Bytes()
+}
+
+// readOctetsWithLength reads octets preceded by length determinant.
+func (d *Decoder) readOctetsWithLength(c Constraint) ([]byte, error) {
+	var res []byte
+	err := d.readItems(c, layout{bits: 8}, func(n int) error {
+		data, err := d.readOctets(n)
+		res = append(res, data...)
+		return err
+	})
+	return res, err
+}
+
+// ReadBitString reads BIT STRING value with number of bits constrained by size.
+func (d *Decoder) ReadBitString(v *asn1.BitString, size Constraint) error {
+	var res asn1.BitString
+	err := d.readItems(size, layout{bits: 1}, func(n int) error {
+		if n > 8*len(d.data)-d.pos {
+			return errTruncated
+		}
+		res.Bytes = append(res.Bytes, make([]byte, (res.BitLength+n+7)/8-len(res.Bytes))...)
+		for i := res.BitLength; i < res.BitLength+n; i++ {
+			bit, _ := d.readBits(1)
+			res.Bytes[i/8] |= byte(bit) << (7 - i%8)
+		}
+		res.BitLength += n
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	*v = res
+	return nil
+}
+
+// ReadNamedBitString reads value of BIT STRING type with named bits.
+func (d *Decoder) ReadNamedBitString(v *asn1.BitString, size Constraint) error {
+	return d.ReadBitString(v, size)
+}
+
+// ReadOctetString reads OCTET STRING value with number of octets constrained by size.
+func (d *Decoder) ReadOctetString(v *[]byte, size Constraint) error {
+	res, err := d.readOctetsWithLength(size)
+	if err != nil {
+		return err
+	}
+	*v = res
+	return nil
+}
+
+// ReadString reads value of known-multiplier character string type with characters of the alphabet cs,
+// and number of characters constrained by size.
+func (d *Decoder) ReadString(v *string, cs Charset, size Constraint) error {
+	bits := cs.bits(d.aligned)
+	var res strings.Builder
+	err := d.readItems(size, layout{bits: bits, packed: true}, func(n int) error {
+		for range n {
+			ch, err := d.readBits(bits)
+			if err != nil {
+				return err
+			}
+			r, ok := cs.decode(ch, bits)
+			if !ok || !utf8.ValidRune(r) {
+				return SyntaxError{fmt.Sprintf("invalid character %#x", ch)}
+			}
+			res.WriteRune(r)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	*v = res.String()
+	return nil
+}
+
+// ReadUTF8String reads value of character string type which is not a known-multiplier type.
+func (d *Decoder) ReadUTF8String(v *string) error {
+	res, err := d.readOctetsWithLength(Constraint{})
+	if err != nil {
+		return err
+	}
+	*v = string(res)
+	return nil
+}
+
+// ReadObjectIdentifier reads OBJECT IDENTIFIER value.
+func (d *Decoder) ReadObjectIdentifier(v *asn1.ObjectIdentifier) error {
+	contents, err := d.readOctetsWithLength(Constraint{})
+	if err != nil {
+		return err
+	}
+	return der.NewDecoder(derElement(der.TagObjectIdentifier, contents)).ReadObjectIdentifier(der.TagObjectIdentifier, v)
+}
+
+// ReadGeneralizedTime reads GeneralizedTime value. Any format allowed by BER is accepted.
+func (d *Decoder) ReadGeneralizedTime(v *time.Time) error {
+	var s string
+	if err := d.ReadString(&s, VisibleString, Constraint{}); err != nil {
+		return err
+	}
+	return der.NewDecoder(derElement(der.TagGeneralizedTime, []byte(s))).ReadGeneralizedTime(der.TagGeneralizedTime, v)
+}
+
+// ReadUTCTime reads UTCTime value. Any format allowed by BER is accepted.
+func (d *Decoder) ReadUTCTime(v *time.Time) error {
+	var s string
+	if err := d.ReadString(&s, VisibleString, Constraint{}); err != nil {
+		return err
+	}
+	return der.NewDecoder(derElement(der.TagUTCTime, []byte(s))).ReadUTCTime(der.TagUTCTime, v)
+}
+
+// ReadChoiceIndex reads index of CHOICE alternative, see Encoder.WriteChoiceIndex.
+// Index of unknown extension addition is returned as is, and its value should be skipped with SkipOpenType.
+func (d *Decoder) ReadChoiceIndex(root int, extensible bool) (int, error) {
+	if extensible {
+		extended, err := d.ReadBit()
+		if err != nil {
+			return 0, err
+		}
+		if extended {
+			i, err := d.readNormallySmall()
+			if err != nil {
+				return 0, err
+			}
+			if i > fragmentSize {
+				return 0, SyntaxError{fmt.Sprintf("CHOICE index %v is too large", i)}
+			}
+			return root + int(i), nil
+		}
+	}
+	if root == 0 {
+		return 0, SyntaxError{"CHOICE has no root alternatives"}
+	}
+	i, err := d.readConstrained(uint64(root - 1))
+	return int(i), err
+}
+
+// ReadOpenType reads value of open type with decode, which is called with decoder of its encoding.
+func (d *Decoder) ReadOpenType(decode func(d *Decoder) error) error {
+	data, err := d.readOctetsWithLength(Constraint{})
+	if err != nil {
+		return err
+	}
+	return decode(NewDecoder(data, d.variant()))
+}
+
+// SkipOpenType skips value of open type, e.g. unknown extension addition.
+func (d *Decoder) SkipOpenType() error {
+	_, err := d.readOctetsWithLength(Constraint{})
+	return err
+}
+
+// ReadExtensionBitmap reads presence bitmap of extension additions of SEQUENCE or SET value.
+func (d *Decoder) ReadExtensionBitmap() ([]bool, error) {
+	n, err := d.readNormallySmallLength()
+	if err != nil {
+		return nil, err
+	}
+	if n > 8*len(d.data)-d.pos {
+		return nil, errTruncated
+	}
+	return d.ReadBitmap(n)
+}
+
+// ReadList reads SEQUENCE OF or SET OF value with number of elements constrained by size.
+// Function item is called to decode every element.
+func (d *Decoder) ReadList(size Constraint, item func() error) error {
+	return d.readItems(size, layout{}, func(n int) error {
+		for range n {
+			if err := item(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
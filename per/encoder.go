@@ -0,0 +1,529 @@
+package per
+
+import (
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+	"slices"
+	"time"
+
+	"github.com/chemikadze/asn1go/der"
+)
+
+// Encoder accumulates PER encoding of values.
+type Encoder struct {
+	aligned bool
+	buf     []byte
+	// free is number of unused bits of the last octet of buf.
+	free int
+}
+
+// NewEncoder returns an empty encoder of the PER variant.
+func NewEncoder(variant Variant) *Encoder {
+	return &Encoder{aligned: variant == Aligned}
+}
+
+// variant returns PER variant of the encoder.
+func (e *Encoder) variant() Variant {
+	if e.aligned {
+		return Aligned
+	}
+	return Unaligned
+}
+
+// Bytes returns encoding accumulated so far, padded with zero bits to octet boundary.
+// Empty encoding is replaced with single zero octet, see X.691, section 11.1.3.
+func (e *Encoder) Bytes() []byte {
+	if len(e.buf) == 0 {
+		return []byte{0}
+	}
+	return e.buf
+}
+
+// writeBits appends n least significant bits of v, most significant first.
+func (e *Encoder) writeBits(v uint64, n int) {
+	for n > 0 {
+		if e.free == 0 {
+			e.buf = append(e.buf, 0)
+			e.free = 8
+		}
+		k := min(n, e.free)
+		chunk := (v >> (n - k)) & (1<<k - 1)
+		e.buf[len(e.buf)-1] |= byte(chunk << (e.free - k))
+		e.free -= k
+		n -= k
+	}
+}
+
+// align pads encoding to octet boundary in ALIGNED variant.
+func (e *Encoder) align() {
+	if e.aligned {
+		e.free = 0
+	}
+}
+
+// writeOctets appends octets, which are octet-aligned only if encoding is.
+func (e *Encoder) writeOctets(data []byte) {
+	if e.free == 0 {
+		e.buf = append(e.buf, data...)
+		return
+	}
+	for _, b := range data {
+		e.writeBits(uint64(b), 8)
+	}
+}
+
+// WriteBit appends single bit, e.g. extension bit, or presence bit of OPTIONAL component.
+func (e *Encoder) WriteBit(v bool) {
+	if v {
+		e.writeBits(1, 1)
+	} else {
+		e.writeBits(0, 1)
+	}
+}
+
+// WriteBoolean appends BOOLEAN value, see X.691, section 12.
+func (e *Encoder) WriteBoolean(v bool) {
+	e.WriteBit(v)
+}
+
+// writeConstrained appends constrained whole number v, which is offset from the lower bound of the range,
+// see X.691, section 11.5.
+func (e *Encoder) writeConstrained(v uint64, span uint64) {
+	switch {
+	case span == 0:
+	case !e.aligned || span < 255:
+		e.writeBits(v, bitLength(span))
+	case span == 255:
+		e.align()
+		e.writeBits(v, 8)
+	case span < 1<<16:
+		e.align()
+		e.writeBits(v, 16)
+	default:
+		// indefinite length case, length is constrained by number of octets needed for the range
+		n := octetLength(v)
+		e.writeConstrained(uint64(n-1), uint64(octetLength(span)-1))
+		e.align()
+		e.writeBits(v, 8*n)
+	}
+}
+
+// writeNormallySmall appends normally small non-negative whole number, see X.691, section 11.6.
+func (e *Encoder) writeNormallySmall(v uint64) {
+	if v < 64 {
+		e.writeBits(v, 7)
+		return
+	}
+	e.writeBits(1, 1)
+	e.writeSemiConstrained(v)
+}
+
+// writeSemiConstrained appends semi-constrained whole number v, which is offset from the lower bound,
+// see X.691, section 11.7.
+func (e *Encoder) writeSemiConstrained(v uint64) {
+	n := octetLength(v)
+	e.writeLength(n, Constraint{})
+	e.writeBits(v, 8*n)
+}
+
+// writeUnconstrained appends unconstrained whole number, see X.691, section 11.8.
+func (e *Encoder) writeUnconstrained(v int64) {
+	contents := appendSigned(nil, v)
+	e.writeLength(len(contents), Constraint{})
+	e.writeOctets(contents)
+}
+
+// writeLength appends length determinant of n items, see X.691, section 11.9. Returns number of items
+// which should follow it, and true if encoding is fragmented, in which case remaining items should be
+// preceded by another length determinant.
+func (e *Encoder) writeLength(n int, c Constraint) (int, bool) {
+	if c = c.sizeConstraint(); c.bounded() {
+		e.writeConstrained(uint64(int64(n)-c.Lower), c.span())
+		return n, false
+	}
+	e.align()
+	switch {
+	case n < 128:
+		e.writeBits(uint64(n), 8)
+		return n, false
+	case n < fragmentSize:
+		e.writeBits(0x8000|uint64(n), 16)
+		return n, false
+	default:
+		m := min(n/fragmentSize, 4)
+		e.writeBits(0xc0|uint64(m), 8)
+		return m * fragmentSize, true
+	}
+}
+
+// writeNormallySmallLength appends normally small length, which is at least 1, see X.691, section 11.9.3.4.
+func (e *Encoder) writeNormallySmallLength(n int) {
+	if n <= 64 {
+		e.writeBits(uint64(n-1), 7)
+		return
+	}
+	e.writeBits(1, 1)
+	e.writeLength(n, Constraint{})
+}
+
+// layout describes how items of BIT STRING, OCTET STRING, character string, SEQUENCE OF or SET OF
+// value are placed after length determinant.
+type layout struct {
+	// bits is size of an item in bits, or zero if items have variable size.
+	bits int
+	// packed is set if items which fit into 16 bits are not octet-aligned, as in known-multiplier
+	// character strings, see X.691, section 30.5.7.
+	packed bool
+}
+
+// alignedItems returns true if n items of the value of size constrained by c are octet-aligned
+// in ALIGNED variant. Values with fixed size are handled separately.
+func (l layout) alignedItems(n int, c Constraint) bool {
+	if l.bits == 0 {
+		return false
+	}
+	if l.packed {
+		return n > 0 && (!c.HasUpper || c.Upper*int64(l.bits) > 16)
+	}
+	return true
+}
+
+// fixedSize returns true if value of size constrained by c is encoded without length determinant.
+// Items of such values are octet-aligned if they do not fit into 16 bits, see X.691, section 16.10.
+func fixedSize(c Constraint) bool {
+	return c.fixed() && c.Upper < lengthLimit
+}
+
+// effectiveSize appends extension bit of size constraint if it is extensible, and returns
+// constraint which applies to the encoding of size n.
+func (e *Encoder) effectiveSize(n int, c Constraint) (Constraint, error) {
+	switch {
+	case c.Extensible:
+		extended := !c.contains(int64(n))
+		e.WriteBit(extended)
+		if extended {
+			return Constraint{}, nil
+		}
+		return c, nil
+	case !c.contains(int64(n)):
+		return c, fmt.Errorf("per: size %v is not in range %v", n, c)
+	default:
+		return c, nil
+	}
+}
+
+// writeItems appends length determinant and n items of the value of size constrained by c.
+// Function write is called for every fragment of items.
+func (e *Encoder) writeItems(n int, c Constraint, l layout, write func(from, to int) error) error {
+	c, err := e.effectiveSize(n, c)
+	if err != nil {
+		return err
+	}
+	if fixedSize(c) {
+		if c.Upper*int64(l.bits) > 16 {
+			e.align()
+		}
+		return write(0, n)
+	}
+	for from := 0; ; {
+		k, fragmented := e.writeLength(n-from, c)
+		if l.alignedItems(n, c) {
+			e.align()
+		}
+		if err := write(from, from+k); err != nil {
+			return err
+		}
+		from += k
+		if !fragmented {
+			return nil
+		}
+	}
+}
+
+// WriteInteger appends INTEGER value, see X.691, section 13.
+func (e *Encoder) WriteInteger(v int64, c Constraint) error {
+	switch {
+	case c.Extensible:
+		extended := !c.contains(v)
+		e.WriteBit(extended)
+		if extended {
+			e.writeUnconstrained(v)
+			return nil
+		}
+	case !c.contains(v):
+		return fmt.Errorf("per: value %v is not in range %v", v, c)
+	}
+	switch {
+	case c.bounded():
+		e.writeConstrained(uint64(v)-uint64(c.Lower), c.span())
+	case c.HasLower:
+		e.writeSemiConstrained(uint64(v) - uint64(c.Lower))
+	default:
+		e.writeUnconstrained(v)
+	}
+	return nil
+}
+
+// WriteBigInteger appends INTEGER value represented as big.Int, see X.691, section 13.
+func (e *Encoder) WriteBigInteger(v *big.Int, c Constraint) error {
+	if v == nil {
+		return fmt.Errorf("per: INTEGER value is nil")
+	}
+	if v.IsInt64() {
+		return e.WriteInteger(v.Int64(), c)
+	}
+	// bounds are int64, so the value can only be in the root of constraints without one of the bounds
+	inRoot := (v.Sign() > 0 && !c.HasUpper) || (v.Sign() < 0 && !c.HasLower)
+	switch {
+	case c.Extensible:
+		e.WriteBit(!inRoot)
+	case !inRoot:
+		return fmt.Errorf("per: value %v is not in range %v", v, c)
+	}
+	var contents []byte
+	if inRoot && c.HasLower {
+		contents = new(big.Int).Sub(v, big.NewInt(c.Lower)).Bytes()
+	} else {
+		contents = appendBigSigned(nil, v)
+	}
+	e.writeLength(len(contents), Constraint{})
+	e.writeOctets(contents)
+	return nil
+}
+
+// Enumeration describes values of ENUMERATED type.
+type Enumeration struct {
+	// Root holds values of root enumeration in ascending order.
+	Root []int64
+	// Additions holds values of additional enumeration in order of their definition.
+	Additions []int64
+	// Extensible is set if type has extension marker.
+	Extensible bool
+}
+
+// WriteEnumerated appends ENUMERATED value as its index in the enumeration, see X.691, section 14.
+func (e *Encoder) WriteEnumerated(v asn1.Enumerated, en Enumeration) error {
+	if i := slices.Index(en.Root, int64(v)); i >= 0 {
+		if en.Extensible {
+			e.WriteBit(false)
+		}
+		e.writeConstrained(uint64(i), uint64(len(en.Root)-1))
+		return nil
+	}
+	if i := slices.Index(en.Additions, int64(v)); i >= 0 && en.Extensible {
+		e.WriteBit(true)
+		e.writeNormallySmall(uint64(i))
+		return nil
+	}
+	return fmt.Errorf("per: value %v is not in enumeration", v)
+}
+
+// WriteReal appends REAL value as its length and contents octets of DER encoding, see X.691, section 15.
+func (e *Encoder) WriteReal(v float64) error {
+	var enc der.Encoder
+	enc.WriteReal(der.TagReal, v)
+	return e.writeDERContents(enc.Bytes())
+}
+
+// writeDERContents appends length and contents octets of DER encoding of a primitive value.
+func (e *Encoder) writeDERContents(encoding []byte) error {
+	var raw asn1.RawValue
+	if err := der.NewDecoder(encoding).ReadRawValue(&raw); err != nil {
+		return err
+	}
+	return e.writeOctetsWithLength(raw.Bytes, Constraint{})
+}
+
+// writeOctetsWithLength appends octets preceded by length determinant.
+func (e *Encoder) writeOctetsWithLength(data []byte, c Constraint) error {
+	return e.writeItems(len(data), c, layout{bits: 8}, func(from, to int) error {
+		e.writeOctets(data[from:to])
+		return nil
+	})
+}
+
+// WriteBitString appends BIT STRING value with number of bits constrained by size, see X.691, section 16.
+func (e *Encoder) WriteBitString(v asn1.BitString, size Constraint) error {
+	return e.writeItems(v.BitLength, size, layout{bits: 1}, func(from, to int) error {
+		for i := from; i < to; {
+			if i%8 == 0 && to-i >= 8 {
+				e.writeBits(uint64(v.Bytes[i/8]), 8)
+				i += 8
+				continue
+			}
+			e.writeBits(uint64(v.At(i)), 1)
+			i++
+		}
+		return nil
+	})
+}
+
+// WriteNamedBitString appends value of BIT STRING type with named bits. Trailing zero bits are not encoded,
+// unless they are needed to satisfy lower bound of the size, see X.691, section 16.3.
+func (e *Encoder) WriteNamedBitString(v asn1.BitString, size Constraint) error {
+	for v.BitLength > 0 && v.At(v.BitLength-1) == 0 {
+		v.BitLength--
+	}
+	if size.HasLower && int64(v.BitLength) < size.Lower {
+		n := int(size.Lower)
+		padded := make([]byte, (n+7)/8)
+		copy(padded, v.Bytes[:(v.BitLength+7)/8])
+		v = asn1.BitString{Bytes: padded, BitLength: n}
+	}
+	return e.WriteBitString(v, size)
+}
+
+// WriteOctetString appends OCTET STRING value with number of octets constrained by size,
+// see X.691, section 17.
+func (e *Encoder) WriteOctetString(v []byte, size Constraint) error {
+	return e.writeOctetsWithLength(v, size)
+}
+
+// WriteString appends value of known-multiplier character string type with characters of the alphabet cs,
+// and number of characters constrained by size, see X.691, section 30.5.
+func (e *Encoder) WriteString(v string, cs Charset, size Constraint) error {
+	bits := cs.bits(e.aligned)
+	chars := make([]uint64, 0, len(v))
+	for _, r := range v {
+		ch, ok := cs.encode(r, bits)
+		if !ok {
+			return fmt.Errorf("per: character %q is not permitted in %q", r, v)
+		}
+		chars = append(chars, ch)
+	}
+	return e.writeItems(len(chars), size, layout{bits: bits, packed: true}, func(from, to int) error {
+		for _, ch := range chars[from:to] {
+			e.writeBits(ch, bits)
+		}
+		return nil
+	})
+}
+
+// WriteUTF8String appends value of character string type which is not a known-multiplier type,
+// e.g. UTF8String or GeneralString, as its octets preceded by length, see X.691, section 30.6.
+func (e *Encoder) WriteUTF8String(v string) error {
+	return e.writeOctetsWithLength([]byte(v), Constraint{})
+}
+
+// WriteObjectIdentifier appends OBJECT IDENTIFIER value as its length and contents octets of DER encoding,
+// see X.691, section 24.
+func (e *Encoder) WriteObjectIdentifier(v asn1.ObjectIdentifier) error {
+	var enc der.Encoder
+	if err := enc.WriteObjectIdentifier(der.TagObjectIdentifier, v); err != nil {
+		return err
+	}
+	return e.writeDERContents(enc.Bytes())
+}
+
+// WriteGeneralizedTime appends GeneralizedTime value, which is encoded as VisibleString
+// in the same format as DER, see X.691, section 32.
+func (e *Encoder) WriteGeneralizedTime(v time.Time) error {
+	var enc der.Encoder
+	if err := enc.WriteGeneralizedTime(der.TagGeneralizedTime, v); err != nil {
+		return err
+	}
+	return e.writeTimeString(enc.Bytes())
+}
+
+// WriteUTCTime appends UTCTime value, which is encoded as VisibleString in the same format as DER,
+// see X.691, section 32.
+func (e *Encoder) WriteUTCTime(v time.Time) error {
+	var enc der.Encoder
+	if err := enc.WriteUTCTime(der.TagUTCTime, v); err != nil {
+		return err
+	}
+	return e.writeTimeString(enc.Bytes())
+}
+
+// writeTimeString appends contents of DER encoding of time value as VisibleString.
+func (e *Encoder) writeTimeString(encoding []byte) error {
+	var raw asn1.RawValue
+	if err := der.NewDecoder(encoding).ReadRawValue(&raw); err != nil {
+		return err
+	}
+	return e.WriteString(string(raw.Bytes), VisibleString, Constraint{})
+}
+
+// WriteChoiceIndex appends index of CHOICE alternative, see X.691, section 23.
+// Alternatives of extension root have indices below root, and extension additions follow them.
+// Values of extension additions should be encoded as open types.
+func (e *Encoder) WriteChoiceIndex(index int, root int, extensible bool) error {
+	if index < root {
+		if extensible {
+			e.WriteBit(false)
+		}
+		e.writeConstrained(uint64(index), uint64(root-1))
+		return nil
+	}
+	if !extensible {
+		return fmt.Errorf("per: CHOICE index %v is out of range", index)
+	}
+	e.WriteBit(true)
+	e.writeNormallySmall(uint64(index - root))
+	return nil
+}
+
+// WriteOpenType appends value encoded by encode as open type, i.e. as complete encoding
+// preceded by its length, see X.691, section 11.2.
+func (e *Encoder) WriteOpenType(encode func(e *Encoder) error) error {
+	inner := NewEncoder(e.variant())
+	if err := encode(inner); err != nil {
+		return err
+	}
+	return e.writeOctetsWithLength(inner.Bytes(), Constraint{})
+}
+
+// WriteExtensionBitmap appends presence bitmap of extension additions of SEQUENCE or SET value,
+// see X.691, section 19.7. Present additions should follow it encoded as open types.
+func (e *Encoder) WriteExtensionBitmap(present []bool) {
+	e.writeNormallySmallLength(len(present))
+	for _, p := range present {
+		e.WriteBit(p)
+	}
+}
+
+// WriteList appends SEQUENCE OF or SET OF value of n elements, with number of elements
+// constrained by size, see X.691, section 20. Function item is called to encode every element.
+func (e *Encoder) WriteList(n int, size Constraint, item func(i int) error) error {
+	return e.writeItems(n, size, layout{}, func(from, to int) error {
+		for i := from; i < to; i++ {
+			if err := item(i); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// appendSigned appends minimal two's complement encoding of v.
+func appendSigned(dst []byte, v int64) []byte {
+	n := 1
+	for u := v; u > 127 || u < -128; u >>= 8 {
+		n++
+	}
+	for i := n - 1; i >= 0; i-- {
+		dst = append(dst, byte(v>>(8*i)))
+	}
+	return dst
+}
+
+// appendBigSigned appends minimal two's complement encoding of v.
+func appendBigSigned(dst []byte, v *big.Int) []byte {
+	if v.Sign() >= 0 {
+		b := v.Bytes()
+		if len(b) == 0 || b[0]&0x80 != 0 {
+			dst = append(dst, 0)
+		}
+		return append(dst, b...)
+	}
+	// two's complement of negative number is complement of its absolute value minus one
+	b := new(big.Int).Sub(new(big.Int).Neg(v), big.NewInt(1)).Bytes()
+	for i := range b {
+		b[i] = ^b[i]
+	}
+	if len(b) == 0 || b[0]&0x80 == 0 {
+		dst = append(dst, 0xff)
+	}
+	return append(dst, b...)
+}
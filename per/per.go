@@ -0,0 +1,151 @@
+// Package per implements Packed Encoding Rules of ASN.1, as defined in X.691.
+//
+// It is a runtime library of the code generated by asn1go with GEN_PER code generator type,
+// and is not intended to be used directly. Both ALIGNED and UNALIGNED variants of BASIC-PER are supported.
+// PER-visible constraints of generated types are passed to encoder and decoder methods as Constraint values.
+package per
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"strconv"
+)
+
+// Variant is a variant of PER.
+type Variant int
+
+const (
+	// Aligned is ALIGNED variant of PER, which pads some fields to octet boundary.
+	Aligned Variant = iota
+	// Unaligned is UNALIGNED variant of PER, which encodes fields with minimal number of bits.
+	Unaligned
+)
+
+// String returns name of the variant.
+func (v Variant) String() string {
+	switch v {
+	case Aligned:
+		return "ALIGNED"
+	case Unaligned:
+		return "UNALIGNED"
+	default:
+		return fmt.Sprintf("Variant(%d)", int(v))
+	}
+}
+
+// ErrAbsentValue is returned when value of CHOICE to encode is nil.
+var ErrAbsentValue = errors.New("per: value of CHOICE is not set")
+
+// SyntaxError is returned when decoded data is not a valid PER encoding.
+type SyntaxError struct {
+	Msg string
+}
+
+func (e SyntaxError) Error() string {
+	return "per: syntax error: " + e.Msg
+}
+
+// Marshaler is implemented by generated types, which encode themselves without reflection.
+type Marshaler interface {
+	// EncodePER appends PER encoding of the value to e.
+	EncodePER(e *Encoder) error
+}
+
+// Unmarshaler is implemented by generated types, which decode themselves without reflection.
+type Unmarshaler interface {
+	// DecodePER decodes the value from d.
+	DecodePER(d *Decoder) error
+}
+
+// Marshal returns complete PER encoding of the value, see X.691, section 11.1.
+func Marshal(v Marshaler, variant Variant) ([]byte, error) {
+	e := NewEncoder(variant)
+	if err := v.EncodePER(e); err != nil {
+		return nil, err
+	}
+	return e.Bytes(), nil
+}
+
+// Unmarshal decodes complete PER encoding of the value. Padding bits of the last octet are ignored.
+func Unmarshal(data []byte, v Unmarshaler, variant Variant) error {
+	return v.DecodePER(NewDecoder(data, variant))
+}
+
+// Constraint is PER-visible constraint of INTEGER value, or of number of items in BIT STRING,
+// OCTET STRING, character string, SEQUENCE OF or SET OF value. Zero value means that value is not constrained.
+type Constraint struct {
+	// Lower is the lower bound, if HasLower is set.
+	Lower int64
+	// Upper is the upper bound, if HasUpper is set.
+	Upper    int64
+	HasLower bool
+	HasUpper bool
+	// Extensible is set if constraint has extension marker, in which case values outside of bounds
+	// can be encoded.
+	Extensible bool
+}
+
+// contains returns true if value is within bounds of the constraint.
+func (c Constraint) contains(v int64) bool {
+	return (!c.HasLower || v >= c.Lower) && (!c.HasUpper || v <= c.Upper)
+}
+
+// bounded returns true if both bounds are set.
+func (c Constraint) bounded() bool {
+	return c.HasLower && c.HasUpper
+}
+
+// fixed returns true if constraint allows single value only.
+func (c Constraint) fixed() bool {
+	return c.bounded() && c.Lower == c.Upper
+}
+
+// span returns difference between the upper and the lower bound, which is range of values minus one.
+func (c Constraint) span() uint64 {
+	return uint64(c.Upper - c.Lower)
+}
+
+// lengthLimit is upper bound of length determinants encoded as constrained whole numbers, see X.691, section 11.9.
+const lengthLimit = 64 << 10
+
+// fragmentSize is number of items in a fragment of encoding with large length, see X.691, section 11.9.3.8.
+const fragmentSize = 16 << 10
+
+// sizeConstraint returns constraint of length determinant. Lengths with upper bound of 64K or larger
+// are encoded as if they were not constrained.
+func (c Constraint) sizeConstraint() Constraint {
+	if !c.HasUpper || c.Upper >= lengthLimit {
+		return Constraint{}
+	}
+	return Constraint{Lower: max(c.Lower, 0), Upper: c.Upper, HasLower: true, HasUpper: true}
+}
+
+// bitLength returns number of bits needed to encode values from 0 to v.
+func bitLength(v uint64) int {
+	return bits.Len64(v)
+}
+
+// octetLength returns number of octets needed to encode values from 0 to v, which is at least 1.
+func octetLength(v uint64) int {
+	return max(1, (bits.Len64(v)+7)/8)
+}
+
+// String returns constraint in ASN.1 notation, e.g. (0..MAX, ...).
+func (c Constraint) String() string {
+	lower, upper := "MIN", "MAX"
+	if c.HasLower {
+		lower = strconv.FormatInt(c.Lower, 10)
+	}
+	if c.HasUpper {
+		upper = strconv.FormatInt(c.Upper, 10)
+	}
+	res := lower + ".." + upper
+	if c.fixed() {
+		res = lower
+	}
+	if c.Extensible {
+		res += ", ..."
+	}
+	return "(" + res + ")"
+}
@@ -0,0 +1,447 @@
+package per
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func bounds(lower, upper int64) Constraint {
+	return Constraint{Lower: lower, Upper: upper, HasLower: true, HasUpper: true}
+}
+
+func TestEncoding(t *testing.T) {
+	largeOctets := bytes.Repeat([]byte{0xab}, fragmentSize+1)
+	testCases := []struct {
+		name  string
+		write func(e *Encoder) error
+		read  func(d *Decoder) (any, error)
+		value any
+		// aligned and unaligned are expected encodings, unaligned is the same as aligned if not set.
+		aligned   []byte
+		unaligned []byte
+	}{
+		{
+			name:    "boolean",
+			write:   func(e *Encoder) error { e.WriteBoolean(true); return nil },
+			read:    func(d *Decoder) (any, error) { var b bool; err := d.ReadBoolean(&b); return b, err },
+			value:   true,
+			aligned: []byte{0x80},
+		},
+		{
+			name:    "constrained integer in bit-field",
+			write:   func(e *Encoder) error { return e.WriteInteger(5, bounds(0, 7)) },
+			read:    func(d *Decoder) (any, error) { var i int64; err := d.ReadInteger(&i, bounds(0, 7)); return i, err },
+			value:   int64(5),
+			aligned: []byte{0xa0},
+		},
+		{
+			name: "constrained integer with range of 256",
+			write: func(e *Encoder) error {
+				e.WriteBit(true)
+				return e.WriteInteger(200, bounds(0, 255))
+			},
+			read: func(d *Decoder) (any, error) {
+				d.ReadBit()
+				var i int64
+				err := d.ReadInteger(&i, bounds(0, 255))
+				return i, err
+			},
+			value:     int64(200),
+			aligned:   []byte{0x80, 0xc8},
+			unaligned: []byte{0xe4, 0x00},
+		},
+		{
+			name:      "constrained integer in two octets",
+			write:     func(e *Encoder) error { return e.WriteInteger(500, bounds(0, 1000)) },
+			read:      func(d *Decoder) (any, error) { var i int64; err := d.ReadInteger(&i, bounds(0, 1000)); return i, err },
+			value:     int64(500),
+			aligned:   []byte{0x01, 0xf4},
+			unaligned: []byte{0x7d, 0x00},
+		},
+		{
+			name:  "constrained integer with large range",
+			write: func(e *Encoder) error { return e.WriteInteger(256, bounds(0, 1<<32-1)) },
+			read: func(d *Decoder) (any, error) {
+				var i int64
+				err := d.ReadInteger(&i, bounds(0, 1<<32-1))
+				return i, err
+			},
+			value:     int64(256),
+			aligned:   []byte{0x40, 0x01, 0x00},
+			unaligned: []byte{0x00, 0x00, 0x01, 0x00},
+		},
+		{
+			name:  "semi-constrained integer",
+			write: func(e *Encoder) error { return e.WriteInteger(256, Constraint{Lower: 1, HasLower: true}) },
+			read: func(d *Decoder) (any, error) {
+				var i int64
+				err := d.ReadInteger(&i, Constraint{Lower: 1, HasLower: true})
+				return i, err
+			},
+			value:   int64(256),
+			aligned: []byte{0x01, 0xff},
+		},
+		{
+			name:    "unconstrained integer",
+			write:   func(e *Encoder) error { return e.WriteInteger(-129, Constraint{}) },
+			read:    func(d *Decoder) (any, error) { var i int64; err := d.ReadInteger(&i, Constraint{}); return i, err },
+			value:   int64(-129),
+			aligned: []byte{0x02, 0xff, 0x7f},
+		},
+		{
+			name: "integer in extension root",
+			write: func(e *Encoder) error {
+				return e.WriteInteger(3, Constraint{Upper: 7, HasLower: true, HasUpper: true, Extensible: true})
+			},
+			read: func(d *Decoder) (any, error) {
+				var i int64
+				err := d.ReadInteger(&i, Constraint{Upper: 7, HasLower: true, HasUpper: true, Extensible: true})
+				return i, err
+			},
+			value:   int64(3),
+			aligned: []byte{0x30},
+		},
+		{
+			name: "integer outside of extension root",
+			write: func(e *Encoder) error {
+				return e.WriteInteger(8, Constraint{Upper: 7, HasLower: true, HasUpper: true, Extensible: true})
+			},
+			read: func(d *Decoder) (any, error) {
+				var i int64
+				err := d.ReadInteger(&i, Constraint{Upper: 7, HasLower: true, HasUpper: true, Extensible: true})
+				return i, err
+			},
+			value:     int64(8),
+			aligned:   []byte{0x80, 0x01, 0x08},
+			unaligned: []byte{0x80, 0x84, 0x00},
+		},
+		{
+			name:  "big integer",
+			write: func(e *Encoder) error { return e.WriteBigInteger(new(big.Int).Lsh(big.NewInt(1), 64), Constraint{}) },
+			read: func(d *Decoder) (any, error) {
+				var i *big.Int
+				err := d.ReadBigInteger(&i, Constraint{})
+				return i.String(), err
+			},
+			value:   "18446744073709551616",
+			aligned: []byte{0x09, 0x01, 0, 0, 0, 0, 0, 0, 0, 0},
+		},
+		{
+			name:    "empty encoding",
+			write:   func(e *Encoder) error { return e.WriteInteger(5, bounds(5, 5)) },
+			read:    func(d *Decoder) (any, error) { var i int64; err := d.ReadInteger(&i, bounds(5, 5)); return i, err },
+			value:   int64(5),
+			aligned: []byte{0x00},
+		},
+		{
+			name:  "enumerated",
+			write: func(e *Encoder) error { return e.WriteEnumerated(2, Enumeration{Root: []int64{0, 1, 2}}) },
+			read: func(d *Decoder) (any, error) {
+				var v asn1.Enumerated
+				err := d.ReadEnumerated(&v, Enumeration{Root: []int64{0, 1, 2}})
+				return v, err
+			},
+			value:   asn1.Enumerated(2),
+			aligned: []byte{0x80},
+		},
+		{
+			name: "enumeration addition",
+			write: func(e *Encoder) error {
+				return e.WriteEnumerated(5, Enumeration{Root: []int64{0, 1}, Additions: []int64{5}, Extensible: true})
+			},
+			read: func(d *Decoder) (any, error) {
+				var v asn1.Enumerated
+				err := d.ReadEnumerated(&v, Enumeration{Root: []int64{0, 1}, Additions: []int64{5}, Extensible: true})
+				return v, err
+			},
+			value:   asn1.Enumerated(5),
+			aligned: []byte{0x80},
+		},
+		{
+			name:    "fixed size octet string",
+			write:   func(e *Encoder) error { return e.WriteOctetString([]byte("abc"), bounds(3, 3)) },
+			read:    func(d *Decoder) (any, error) { var b []byte; err := d.ReadOctetString(&b, bounds(3, 3)); return b, err },
+			value:   []byte("abc"),
+			aligned: []byte("abc"),
+		},
+		{
+			name: "fixed size octet string in two octets is not aligned",
+			write: func(e *Encoder) error {
+				e.WriteBit(true)
+				return e.WriteOctetString([]byte{0x12, 0x34}, bounds(2, 2))
+			},
+			read: func(d *Decoder) (any, error) {
+				d.ReadBit()
+				var b []byte
+				err := d.ReadOctetString(&b, bounds(2, 2))
+				return b, err
+			},
+			value:   []byte{0x12, 0x34},
+			aligned: []byte{0x89, 0x1a, 0x00},
+		},
+		{
+			name: "unconstrained octet string",
+			write: func(e *Encoder) error {
+				e.WriteBit(true)
+				return e.WriteOctetString([]byte("ab"), Constraint{})
+			},
+			read: func(d *Decoder) (any, error) {
+				d.ReadBit()
+				var b []byte
+				err := d.ReadOctetString(&b, Constraint{})
+				return b, err
+			},
+			value:     []byte("ab"),
+			aligned:   []byte{0x80, 0x02, 'a', 'b'},
+			unaligned: []byte{0x81, 0x30, 0xb1, 0x00},
+		},
+		{
+			name:    "octet string with two octet length",
+			write:   func(e *Encoder) error { return e.WriteOctetString(largeOctets[:200], Constraint{}) },
+			read:    func(d *Decoder) (any, error) { var b []byte; err := d.ReadOctetString(&b, Constraint{}); return b, err },
+			value:   largeOctets[:200],
+			aligned: append([]byte{0x80, 0xc8}, largeOctets[:200]...),
+		},
+		{
+			name:    "fragmented octet string",
+			write:   func(e *Encoder) error { return e.WriteOctetString(largeOctets, Constraint{}) },
+			read:    func(d *Decoder) (any, error) { var b []byte; err := d.ReadOctetString(&b, Constraint{}); return b, err },
+			value:   largeOctets,
+			aligned: append(append(append([]byte{0xc1}, largeOctets[:fragmentSize]...), 0x01), largeOctets[fragmentSize:]...),
+		},
+		{
+			name: "bit string with constrained length",
+			write: func(e *Encoder) error {
+				return e.WriteBitString(asn1.BitString{Bytes: []byte{0xa0}, BitLength: 3}, bounds(0, 7))
+			},
+			read: func(d *Decoder) (any, error) {
+				var b asn1.BitString
+				err := d.ReadBitString(&b, bounds(0, 7))
+				return b, err
+			},
+			value:     asn1.BitString{Bytes: []byte{0xa0}, BitLength: 3},
+			aligned:   []byte{0x60, 0xa0},
+			unaligned: []byte{0x74},
+		},
+		{
+			name:  "visible string",
+			write: func(e *Encoder) error { return e.WriteString("Jo", VisibleString, Constraint{}) },
+			read: func(d *Decoder) (any, error) {
+				var s string
+				err := d.ReadString(&s, VisibleString, Constraint{})
+				return s, err
+			},
+			value:     "Jo",
+			aligned:   []byte{0x02, 'J', 'o'},
+			unaligned: []byte{0x02, 0x95, 0xbc},
+		},
+		{
+			name: "numeric string with indexed characters",
+			write: func(e *Encoder) error {
+				e.WriteBit(true)
+				return e.WriteString("123", NumericString, bounds(3, 3))
+			},
+			read: func(d *Decoder) (any, error) {
+				d.ReadBit()
+				var s string
+				err := d.ReadString(&s, NumericString, bounds(3, 3))
+				return s, err
+			},
+			value:   "123",
+			aligned: []byte{0x91, 0xa0},
+		},
+		{
+			name: "restricted alphabet",
+			write: func(e *Encoder) error {
+				return e.WriteString("123", IA5String.Restrict([2]rune{'0', '9'}), bounds(3, 3))
+			},
+			read: func(d *Decoder) (any, error) {
+				var s string
+				err := d.ReadString(&s, IA5String.Restrict([2]rune{'0', '9'}), bounds(3, 3))
+				return s, err
+			},
+			value:   "123",
+			aligned: []byte{0x12, 0x30},
+		},
+		{
+			name: "object identifier",
+			write: func(e *Encoder) error {
+				return e.WriteObjectIdentifier(asn1.ObjectIdentifier{1, 2, 840, 113549})
+			},
+			read: func(d *Decoder) (any, error) {
+				var oid asn1.ObjectIdentifier
+				err := d.ReadObjectIdentifier(&oid)
+				return oid, err
+			},
+			value:   asn1.ObjectIdentifier{1, 2, 840, 113549},
+			aligned: []byte{0x06, 0x2a, 0x86, 0x48, 0x86, 0xf7, 0x0d},
+		},
+		{
+			name:    "choice index",
+			write:   func(e *Encoder) error { return e.WriteChoiceIndex(1, 3, false) },
+			read:    func(d *Decoder) (any, error) { return d.ReadChoiceIndex(3, false) },
+			value:   1,
+			aligned: []byte{0x40},
+		},
+		{
+			name:    "choice index of extension addition",
+			write:   func(e *Encoder) error { return e.WriteChoiceIndex(2, 2, true) },
+			read:    func(d *Decoder) (any, error) { return d.ReadChoiceIndex(2, true) },
+			value:   2,
+			aligned: []byte{0x80},
+		},
+		{
+			name:    "extension bitmap",
+			write:   func(e *Encoder) error { e.WriteExtensionBitmap([]bool{true, false}); return nil },
+			read:    func(d *Decoder) (any, error) { return d.ReadExtensionBitmap() },
+			value:   []bool{true, false},
+			aligned: []byte{0x03, 0x00},
+		},
+		{
+			name: "open type",
+			write: func(e *Encoder) error {
+				return e.WriteOpenType(func(e *Encoder) error { return e.WriteInteger(7, bounds(0, 255)) })
+			},
+			read: func(d *Decoder) (any, error) {
+				var i int64
+				err := d.ReadOpenType(func(d *Decoder) error { return d.ReadInteger(&i, bounds(0, 255)) })
+				return i, err
+			},
+			value:   int64(7),
+			aligned: []byte{0x01, 0x07},
+		},
+		{
+			name: "list with constrained size",
+			write: func(e *Encoder) error {
+				items := []bool{true, false}
+				return e.WriteList(len(items), bounds(1, 4), func(i int) error { e.WriteBoolean(items[i]); return nil })
+			},
+			read: func(d *Decoder) (any, error) {
+				var items []bool
+				err := d.ReadList(bounds(1, 4), func() error {
+					var b bool
+					err := d.ReadBoolean(&b)
+					items = append(items, b)
+					return err
+				})
+				return items, err
+			},
+			value:   []bool{true, false},
+			aligned: []byte{0x60},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			expected := map[Variant][]byte{Aligned: tc.aligned, Unaligned: tc.unaligned}
+			if tc.unaligned == nil {
+				expected[Unaligned] = tc.aligned
+			}
+			for _, variant := range []Variant{Aligned, Unaligned} {
+				e := NewEncoder(variant)
+				if err := tc.write(e); err != nil {
+					t.Fatalf("%v: failed to encode: %v", variant, err)
+				}
+				if !bytes.Equal(e.Bytes(), expected[variant]) {
+					t.Errorf("%v: expected encoding %x, got %x", variant, expected[variant], e.Bytes())
+				}
+				decoded, err := tc.read(NewDecoder(expected[variant], variant))
+				if err != nil {
+					t.Fatalf("%v: failed to decode: %v", variant, err)
+				}
+				if !reflect.DeepEqual(decoded, tc.value) {
+					t.Errorf("%v: expected decoded value %#v, got %#v", variant, tc.value, decoded)
+				}
+			}
+		})
+	}
+}
+
+func TestEncoderErrors(t *testing.T) {
+	testCases := []struct {
+		name  string
+		write func(e *Encoder) error
+	}{
+		{
+			name:  "integer out of range",
+			write: func(e *Encoder) error { return e.WriteInteger(8, bounds(0, 7)) },
+		},
+		{
+			name:  "size out of range",
+			write: func(e *Encoder) error { return e.WriteOctetString([]byte("abc"), bounds(0, 2)) },
+		},
+		{
+			name:  "character not in alphabet",
+			write: func(e *Encoder) error { return e.WriteString("12a", NumericString, Constraint{}) },
+		},
+		{
+			name:  "unknown enumeration value",
+			write: func(e *Encoder) error { return e.WriteEnumerated(3, Enumeration{Root: []int64{0, 1}}) },
+		},
+		{
+			name:  "choice addition without extension marker",
+			write: func(e *Encoder) error { return e.WriteChoiceIndex(2, 2, false) },
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.write(NewEncoder(Aligned)); err == nil {
+				t.Errorf("Expected error")
+			}
+		})
+	}
+}
+
+func TestDecoderErrors(t *testing.T) {
+	testCases := []struct {
+		name string
+		data []byte
+		read func(d *Decoder) error
+	}{
+		{
+			name: "constrained integer out of range",
+			data: []byte{0xe0},
+			read: func(d *Decoder) error { var i int64; return d.ReadInteger(&i, bounds(0, 4)) },
+		},
+		{
+			name: "truncated integer",
+			data: []byte{0x02, 0x01},
+			read: func(d *Decoder) error { var i int64; return d.ReadInteger(&i, Constraint{}) },
+		},
+		{
+			name: "truncated octet string",
+			data: []byte{0x03, 'a', 'b'},
+			read: func(d *Decoder) error { var b []byte; return d.ReadOctetString(&b, Constraint{}) },
+		},
+		{
+			name: "size out of range",
+			data: []byte{0x03, 'a', 'b', 'c'},
+			read: func(d *Decoder) error {
+				var b []byte
+				return d.ReadOctetString(&b, Constraint{Lower: 4, HasLower: true})
+			},
+		},
+		{
+			name: "character not in alphabet",
+			data: []byte{0xf0},
+			read: func(d *Decoder) error { var s string; return d.ReadString(&s, NumericString, bounds(1, 1)) },
+		},
+		{
+			name: "unknown enumeration addition",
+			data: []byte{0x81},
+			read: func(d *Decoder) error {
+				var v asn1.Enumerated
+				return d.ReadEnumerated(&v, Enumeration{Root: []int64{0}, Extensible: true})
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.read(NewDecoder(tc.data, Aligned)); err == nil {
+				t.Errorf("Expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,293 @@
+package asn1go
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ModuleFileExtensions are extensions of files which are considered to be ASN.1 modules
+// when modules are looked up in the search path.
+var ModuleFileExtensions = []string{".asn1", ".asn"}
+
+// ModuleRegistry holds parsed modules, and locates modules imported by them.
+//
+// Modules can be added explicitly, or loaded from directories of the search path on demand.
+// Files in search path are looked up by module name first, e.g. PKIX1Explicit88.asn1,
+// and if module is not found this way, all files in search path are parsed.
+//
+// Files in search path which can not be parsed are skipped, and modules registered explicitly are preferred
+// over the ones with the same name found in search path. Lookup of a module which is defined
+// in several files of search path fails, as it is ambiguous.
+type ModuleRegistry struct {
+	searchPath []string
+	modules    []*ModuleDefinition
+	// loadedFiles holds absolute names of files already loaded into registry.
+	loadedFiles map[string]bool
+	// searchPathFiles holds names of search path files where modules loaded from search path are defined.
+	searchPathFiles map[string][]string
+	// failedFiles holds errors of search path files which could not be parsed.
+	failedFiles []error
+	// scanned is set after all files in search path are loaded.
+	scanned bool
+}
+
+// NewModuleRegistry creates a new registry, which looks up modules in provided directories.
+func NewModuleRegistry(searchPath ...string) *ModuleRegistry {
+	return &ModuleRegistry{
+		searchPath:      searchPath,
+		loadedFiles:     make(map[string]bool),
+		searchPathFiles: make(map[string][]string),
+	}
+}
+
+// Add registers modules.
+// Returns error if module with the same name is already registered.
+func (r *ModuleRegistry) Add(modules ...*ModuleDefinition) error {
+	for _, module := range modules {
+		if existing := r.get(module.ModuleIdentifier.Reference); existing != nil {
+			return fmt.Errorf("module %v is already registered", module.ModuleIdentifier.Reference)
+		}
+		r.modules = append(r.modules, module)
+	}
+	return nil
+}
+
+// LoadFile parses all modules defined in the file and registers them.
+// Returns modules defined in the file. Files that were already loaded are not parsed again.
+func (r *ModuleRegistry) LoadFile(name string) ([]*ModuleDefinition, error) {
+	absName, err := filepath.Abs(name)
+	if err != nil {
+		return nil, err
+	}
+	if r.loadedFiles[absName] {
+		return nil, nil
+	}
+	modules, err := ParseModulesFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %v: %w", name, err)
+	}
+	r.loadedFiles[absName] = true
+	if err := r.Add(modules...); err != nil {
+		return nil, fmt.Errorf("failed to load %v: %w", name, err)
+	}
+	return modules, nil
+}
+
+// Modules returns all registered modules.
+func (r *ModuleRegistry) Modules() []*ModuleDefinition {
+	return r.modules
+}
+
+// Lookup finds module by reference, loading it from search path if needed.
+// If reference has assigned identifier, module is looked up by its DefinitiveIdentifier first, and by name otherwise.
+// Returns error if module is not found, or if it is defined in several files of search path.
+func (r *ModuleRegistry) Lookup(ref GlobalModuleReference) (*ModuleDefinition, error) {
+	if module := r.find(ref); module != nil {
+		return r.unambiguous(module)
+	}
+	for _, dir := range r.searchPath {
+		for _, ext := range ModuleFileExtensions {
+			name := filepath.Join(dir, ref.Reference+ext)
+			if _, err := os.Stat(name); err != nil {
+				continue
+			}
+			r.loadSearchPathFile(name)
+		}
+	}
+	if module := r.find(ref); module != nil {
+		return r.unambiguous(module)
+	}
+	if err := r.scanSearchPath(); err != nil {
+		return nil, err
+	}
+	if module := r.find(ref); module != nil {
+		return r.unambiguous(module)
+	}
+	if len(r.failedFiles) > 0 {
+		return nil, fmt.Errorf("module %v is not found, some files in search path were skipped: %w", ref.Reference, errors.Join(r.failedFiles...))
+	}
+	return nil, fmt.Errorf("module %v is not found", ref.Reference)
+}
+
+// Dependencies returns modules imported by the module, directly or transitively, in the order they are imported.
+// Module itself is not included. Import cycles are allowed, every module is returned only once.
+// Returns error if imported module is not found, or if imported symbol is not defined in it.
+func (r *ModuleRegistry) Dependencies(module *ModuleDefinition) ([]*ModuleDefinition, error) {
+	var res []*ModuleDefinition
+	visited := []string{module.ModuleIdentifier.Reference}
+	queue := []*ModuleDefinition{module}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, imported := range current.ModuleBody.Imports {
+			dependency, err := r.Lookup(imported.Module)
+			if err != nil {
+				return nil, fmt.Errorf("module %v: %w", current.ModuleIdentifier.Reference, err)
+			}
+			for _, symbol := range imported.SymbolList {
+				if err := checkSymbolDefined(dependency, symbol); err != nil {
+					return nil, fmt.Errorf("module %v: %w", current.ModuleIdentifier.Reference, err)
+				}
+			}
+			if !slices.Contains(visited, dependency.ModuleIdentifier.Reference) {
+				visited = append(visited, dependency.ModuleIdentifier.Reference)
+				queue = append(queue, dependency)
+				res = append(res, dependency)
+			}
+		}
+	}
+	return res, nil
+}
+
+func (r *ModuleRegistry) get(name string) *ModuleDefinition {
+	for _, module := range r.modules {
+		if module.ModuleIdentifier.Reference == name {
+			return module
+		}
+	}
+	return nil
+}
+
+// find looks up registered module by reference.
+func (r *ModuleRegistry) find(ref GlobalModuleReference) *ModuleDefinition {
+	if oid, ok := ref.AssignedIdentifier.(ObjectIdentifierValue); ok {
+		if arcs, ok := literalObjectIdentifierArcs(oid); ok {
+			for _, module := range r.modules {
+				if moduleArcs, ok := module.ModuleIdentifier.DefinitiveIdentifier.Arcs(); ok && slices.Equal(arcs, moduleArcs) {
+					return module
+				}
+			}
+		}
+	}
+	return r.get(ref.Reference)
+}
+
+// unambiguous returns error if module was loaded from search path, and is defined there in several files.
+func (r *ModuleRegistry) unambiguous(module *ModuleDefinition) (*ModuleDefinition, error) {
+	if files := r.searchPathFiles[module.ModuleIdentifier.Reference]; len(files) > 1 {
+		return nil, fmt.Errorf("module %v is defined in several files: %v", module.ModuleIdentifier.Reference, strings.Join(files, ", "))
+	}
+	return module, nil
+}
+
+// loadSearchPathFile parses all modules defined in the file of search path and registers them.
+// Unlike LoadFile, it records files which can not be parsed instead of failing, and does not register
+// modules which are already registered, but records the file where they are defined.
+func (r *ModuleRegistry) loadSearchPathFile(name string) {
+	absName, err := filepath.Abs(name)
+	if err != nil {
+		r.failedFiles = append(r.failedFiles, err)
+		return
+	}
+	if r.loadedFiles[absName] {
+		return
+	}
+	r.loadedFiles[absName] = true
+	modules, err := ParseModulesFile(name)
+	if err != nil {
+		r.failedFiles = append(r.failedFiles, fmt.Errorf("failed to parse %v: %w", name, err))
+		return
+	}
+	for _, module := range modules {
+		moduleName := module.ModuleIdentifier.Reference
+		if r.get(moduleName) == nil {
+			r.modules = append(r.modules, module)
+		} else if _, ok := r.searchPathFiles[moduleName]; !ok {
+			continue // modules registered explicitly are preferred
+		}
+		r.searchPathFiles[moduleName] = append(r.searchPathFiles[moduleName], name)
+	}
+}
+
+// scanSearchPath loads all module files from the search path.
+func (r *ModuleRegistry) scanSearchPath() error {
+	if r.scanned {
+		return nil
+	}
+	r.scanned = true
+	for _, dir := range r.searchPath {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || !slices.Contains(ModuleFileExtensions, strings.ToLower(filepath.Ext(entry.Name()))) {
+				continue
+			}
+			r.loadSearchPathFile(filepath.Join(dir, entry.Name()))
+		}
+	}
+	return nil
+}
+
+// checkSymbolDefined returns error if imported symbol is neither defined nor imported in the module.
+func checkSymbolDefined(module *ModuleDefinition, symbol Symbol) error {
+	var name string
+	switch s := symbol.(type) {
+	case TypeReference:
+		name = s.Name()
+	case ValueReference:
+		name = s.Name()
+	default:
+		return nil
+	}
+	if module.ModuleBody.AssignmentList.Get(name) != nil {
+		return nil
+	}
+	for _, imported := range module.ModuleBody.Imports {
+		if slices.Contains(imported.SymbolList, symbol) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%v is not defined in module %v", name, module.ModuleIdentifier.Reference)
+}
+
+// Arcs returns numeric arcs of the identifier.
+// Returns false if identifier is empty.
+func (d DefinitiveIdentifier) Arcs() ([]int, bool) {
+	if len(d) == 0 {
+		return nil, false
+	}
+	arcs := make([]int, 0, len(d))
+	for _, component := range d {
+		arcs = append(arcs, nameOrNumberArc(arcs, component.Name, component.Id))
+	}
+	return arcs, true
+}
+
+// literalObjectIdentifierArcs converts OBJECT IDENTIFIER value to arcs without resolving value references.
+// Returns false if value has references other than well-known arc names.
+func literalObjectIdentifierArcs(oid ObjectIdentifierValue) ([]int, bool) {
+	arcs := make([]int, 0, len(oid))
+	for _, elem := range oid {
+		switch {
+		case elem.Reference == nil:
+			arcs = append(arcs, nameOrNumberArc(arcs, elem.Name, elem.ID))
+		case elem.Name == "" && elem.Reference.ModuleName == "":
+			arc, ok := wellKnownArc(arcs, elem.Reference.ValueName.Name())
+			if !ok {
+				return nil, false
+			}
+			arcs = append(arcs, arc)
+		default:
+			return nil, false
+		}
+	}
+	return arcs, true
+}
+
+// nameOrNumberArc returns number of the arc specified in NameForm, NumberForm or NameAndNumberForm.
+// Parser does not distinguish NameForm from NameAndNumberForm with zero number, so name is looked up
+// in well-known arc names if number is zero.
+func nameOrNumberArc(arcs []int, name string, id int) int {
+	if name != "" && id == 0 {
+		if arc, ok := wellKnownArc(arcs, name); ok {
+			return arc
+		}
+	}
+	return id
+}
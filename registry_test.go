@@ -0,0 +1,161 @@
+package asn1go
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func writeModuleFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %v: %v", name, err)
+		}
+	}
+	return dir
+}
+
+func moduleNames(modules []*ModuleDefinition) []string {
+	names := make([]string, 0, len(modules))
+	for _, module := range modules {
+		names = append(names, module.ModuleIdentifier.Reference)
+	}
+	return names
+}
+
+func TestRegistryLookup(t *testing.T) {
+	dir := writeModuleFiles(t, map[string]string{
+		"Named.asn1": `Named DEFINITIONS ::= BEGIN A ::= INTEGER END`,
+		"other.asn": `
+			Scanned DEFINITIONS ::= BEGIN B ::= INTEGER END
+			WithOid { iso(1) member-body(2) 42 } DEFINITIONS ::= BEGIN C ::= INTEGER END`,
+		"ignored.txt": `not a module`,
+	})
+	testCases := []struct {
+		name     string
+		ref      GlobalModuleReference
+		expected string
+	}{
+		{name: "by file name", ref: GlobalModuleReference{Reference: "Named"}, expected: "Named"},
+		{name: "by scanning search path", ref: GlobalModuleReference{Reference: "Scanned"}, expected: "Scanned"},
+		{
+			name: "by definitive identifier",
+			ref: GlobalModuleReference{Reference: "OtherName", AssignedIdentifier: ObjectIdentifierValue{
+				{Name: "iso"}, {Name: "member-body", ID: 2}, {ID: 42},
+			}},
+			expected: "WithOid",
+		},
+	}
+	registry := NewModuleRegistry(dir)
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			module, err := registry.Lookup(tc.ref)
+			if err != nil {
+				t.Fatalf("Expected nil error, got %v", err)
+			}
+			if got := module.ModuleIdentifier.Reference; got != tc.expected {
+				t.Errorf("Expected module %v, got %v", tc.expected, got)
+			}
+		})
+	}
+	if _, err := registry.Lookup(GlobalModuleReference{Reference: "Missing"}); err == nil || err.Error() != "module Missing is not found" {
+		t.Errorf("Expected module to be not found, got %v", err)
+	}
+}
+
+func TestRegistryDependencies(t *testing.T) {
+	dir := writeModuleFiles(t, map[string]string{
+		"A.asn1": `A DEFINITIONS ::= BEGIN
+			IMPORTS TB FROM B;
+			TA ::= SEQUENCE OF TB
+		END`,
+		"B.asn1": `B DEFINITIONS ::= BEGIN
+			IMPORTS TC, vc FROM C;
+			TB ::= TC
+		END`,
+		"C.asn1": `C DEFINITIONS ::= BEGIN
+			IMPORTS TA FROM A;
+			TC ::= INTEGER
+			vc INTEGER ::= 1
+		END`,
+		"Broken.asn1": `Broken DEFINITIONS ::= BEGIN
+			IMPORTS TX FROM C;
+			T ::= TX
+		END`,
+	})
+	registry := NewModuleRegistry(dir)
+	module, err := registry.Lookup(GlobalModuleReference{Reference: "A"})
+	if err != nil {
+		t.Fatalf("Expected nil error, got %v", err)
+	}
+	dependencies, err := registry.Dependencies(module)
+	if err != nil {
+		t.Fatalf("Expected nil error, got %v", err)
+	}
+	if diff := cmp.Diff([]string{"B", "C"}, moduleNames(dependencies)); diff != "" {
+		t.Errorf("Dependencies did not match expected, diff (-want, +got):\n%v", diff)
+	}
+
+	broken, err := registry.Lookup(GlobalModuleReference{Reference: "Broken"})
+	if err != nil {
+		t.Fatalf("Expected nil error, got %v", err)
+	}
+	if _, err := registry.Dependencies(broken); err == nil || !strings.Contains(err.Error(), "TX is not defined in module C") {
+		t.Errorf("Expected error about undefined symbol, got %v", err)
+	}
+}
+
+func TestRegistryDuplicateModules(t *testing.T) {
+	dir := writeModuleFiles(t, map[string]string{
+		"first.asn1":  `Dup DEFINITIONS ::= BEGIN A ::= INTEGER END`,
+		"second.asn1": `Dup DEFINITIONS ::= BEGIN B ::= INTEGER END`,
+	})
+	registry := NewModuleRegistry()
+	if _, err := registry.LoadFile(filepath.Join(dir, "first.asn1")); err != nil {
+		t.Fatalf("Expected nil error, got %v", err)
+	}
+	if modules, err := registry.LoadFile(filepath.Join(dir, "first.asn1")); err != nil || len(modules) != 0 {
+		t.Errorf("Expected file to be loaded only once, got %v, %v", moduleNames(modules), err)
+	}
+	if _, err := registry.LoadFile(filepath.Join(dir, "second.asn1")); err == nil || !strings.Contains(err.Error(), "module Dup is already registered") {
+		t.Errorf("Expected duplicate module error, got %v", err)
+	}
+}
+
+func TestRegistrySearchPathErrors(t *testing.T) {
+	dir := writeModuleFiles(t, map[string]string{
+		"Input.asn1":  `Input DEFINITIONS ::= BEGIN IMPORTS B FROM Wanted; A ::= B END`,
+		"Wanted.asn1": `Wanted DEFINITIONS ::= BEGIN B ::= INTEGER END`,
+		"broken.asn1": `Broken DEFINITIONS ::= BEGIN`,
+		"first.asn1":  `Dup DEFINITIONS ::= BEGIN A ::= INTEGER END`,
+		"second.asn1": `Dup DEFINITIONS ::= BEGIN B ::= INTEGER END`,
+		"copy.asn1":   `Input DEFINITIONS ::= BEGIN A ::= INTEGER END`,
+	})
+	registry := NewModuleRegistry(dir)
+	input, err := registry.LoadFile(filepath.Join(dir, "Input.asn1"))
+	if err != nil {
+		t.Fatalf("Expected nil error, got %v", err)
+	}
+	for _, name := range []string{"Wanted", "Input"} {
+		module, err := registry.Lookup(GlobalModuleReference{Reference: name})
+		if err != nil {
+			t.Fatalf("Expected nil error for %v, got %v", name, err)
+		}
+		if got := module.ModuleIdentifier.Reference; got != name {
+			t.Errorf("Expected module %v, got %v", name, got)
+		}
+	}
+	if _, err := registry.Lookup(GlobalModuleReference{Reference: "Scanned"}); err == nil || !strings.Contains(err.Error(), "broken.asn1") {
+		t.Errorf("Expected not found error to mention skipped file, got %v", err)
+	}
+	if module, err := registry.Lookup(GlobalModuleReference{Reference: "Input"}); err != nil || module != input[0] {
+		t.Errorf("Expected explicitly loaded module to be preferred, got %v", err)
+	}
+	if _, err := registry.Lookup(GlobalModuleReference{Reference: "Dup"}); err == nil || !strings.Contains(err.Error(), "module Dup is defined in several files") {
+		t.Errorf("Expected ambiguous module error, got %v", err)
+	}
+}
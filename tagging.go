@@ -0,0 +1,242 @@
+package asn1go
+
+import "slices"
+
+// applyAutomaticTagging returns module body with automatic tagging transformation applied to all
+// SEQUENCE, SET and CHOICE types in it, as required for modules with AUTOMATIC TAGS default.
+// See X.680, sections 25.3, 27.3 and 29.3.
+//
+// Generated tags do not specify tag type, so they are implicit unless tagged type is an untagged CHOICE or open type.
+func applyAutomaticTagging(body ModuleBody) ModuleBody {
+	assignments := make(AssignmentList, 0, len(body.AssignmentList))
+	for _, assignment := range body.AssignmentList {
+		switch a := assignment.(type) {
+		case TypeAssignment:
+			a.Type = automaticallyTagged(a.Type)
+			assignments = append(assignments, a)
+		case ValueAssignment:
+			a.Type = automaticallyTagged(a.Type)
+			assignments = append(assignments, a)
+		default:
+			assignments = append(assignments, a)
+		}
+	}
+	body.AssignmentList = assignments
+	return body
+}
+
+// automaticallyTagged applies automatic tagging to t and all types nested in it.
+func automaticallyTagged(t Type) Type {
+	switch tt := t.(type) {
+	case SequenceType:
+		tt.Components, tt.ExtensionAdditions = automaticallyTaggedComponents(tt.Components, tt.ExtensionAdditions)
+		return tt
+	case SetType:
+		tt.Components, tt.ExtensionAdditions = automaticallyTaggedComponents(tt.Components, tt.ExtensionAdditions)
+		return tt
+	case ChoiceType:
+		return automaticallyTaggedChoice(tt)
+	case SequenceOfType:
+		tt.Type = automaticallyTagged(tt.Type)
+		return tt
+	case SetOfType:
+		tt.Type = automaticallyTagged(tt.Type)
+		return tt
+	case NamedType:
+		tt.Type = automaticallyTagged(tt.Type)
+		return tt
+	case TaggedType:
+		tt.Type = automaticallyTagged(tt.Type)
+		return tt
+	case ConstraintedType:
+		tt.Type = automaticallyTagged(tt.Type)
+		return tt
+	default:
+		return t
+	}
+}
+
+// automaticallyTaggedComponents applies automatic tagging to components of SEQUENCE or SET.
+//
+// Components are tagged only if none of them is tagged already. Root components are numbered first,
+// followed by extension additions in the order of definition. Components included by COMPONENTS OF
+// are neither tagged nor numbered, see X.680, section 25.3.
+func automaticallyTaggedComponents(components ComponentTypeList, additions ExtensionAdditions) (ComponentTypeList, ExtensionAdditions) {
+	enabled := true
+	for _, component := range slices.Concat(components, additions.Components()) {
+		if named, ok := component.(NamedComponentType); ok && isTaggedType(named.NamedType.Type) {
+			enabled = false
+		}
+	}
+	number := 0
+	tagComponents := func(list ComponentTypeList) ComponentTypeList {
+		res := make(ComponentTypeList, 0, len(list))
+		for _, component := range list {
+			if named, ok := component.(NamedComponentType); ok {
+				named.NamedType.Type = automaticallyTagged(named.NamedType.Type)
+				if enabled {
+					named.NamedType.Type = automaticTag(number, named.NamedType.Type)
+					number++
+				}
+				component = named
+			}
+			res = append(res, component)
+		}
+		return res
+	}
+	components = tagComponents(components)
+	var taggedAdditions ExtensionAdditions
+	for _, addition := range additions {
+		switch a := addition.(type) {
+		case ComponentType:
+			taggedAdditions = append(taggedAdditions, tagComponents(ComponentTypeList{a})[0])
+		case ExtensionAdditionGroup:
+			a.Components = tagComponents(a.Components)
+			taggedAdditions = append(taggedAdditions, a)
+		default:
+			taggedAdditions = append(taggedAdditions, a)
+		}
+	}
+	return components, taggedAdditions
+}
+
+// automaticallyTaggedChoice applies automatic tagging to alternatives of CHOICE.
+//
+// Alternatives are tagged only if none of them is tagged already. Root alternatives are numbered first,
+// followed by extension additions in the order of definition. See X.680, section 29.3.
+func automaticallyTaggedChoice(t ChoiceType) ChoiceType {
+	enabled := true
+	for _, alternative := range t.Alternatives() {
+		if isTaggedType(alternative.Type) {
+			enabled = false
+		}
+	}
+	number := 0
+	tagAlternatives := func(list []NamedType) []NamedType {
+		res := make([]NamedType, 0, len(list))
+		for _, alternative := range list {
+			alternative.Type = automaticallyTagged(alternative.Type)
+			if enabled {
+				alternative.Type = automaticTag(number, alternative.Type)
+				number++
+			}
+			res = append(res, alternative)
+		}
+		return res
+	}
+	res := ChoiceType{AlternativeTypeList: tagAlternatives(t.AlternativeTypeList), Extensible: t.Extensible}
+	for _, ext := range t.ExtensionTypes {
+		switch e := ext.(type) {
+		case NamedType:
+			res.ExtensionTypes = append(res.ExtensionTypes, tagAlternatives([]NamedType{e})[0])
+		case ExtensionAdditionAlternativesGroup:
+			e.Alternatives = tagAlternatives(e.Alternatives)
+			res.ExtensionTypes = append(res.ExtensionTypes, e)
+		default:
+			res.ExtensionTypes = append(res.ExtensionTypes, e)
+		}
+	}
+	return res
+}
+
+// automaticTag returns t tagged with context-specific tag number.
+func automaticTag(number int, t Type) Type {
+	return TaggedType{
+		Tag:  Tag{Class: CLASS_CONTEXT_SPECIFIC, ClassNumber: Number(number)},
+		Type: t,
+	}
+}
+
+// isTaggedType returns true if type is tagged in its definition, ignoring constraints.
+// Tags of referenced types are not taken into account.
+func isTaggedType(t Type) bool {
+	for {
+		switch tt := t.(type) {
+		case TaggedType:
+			return true
+		case ConstraintedType:
+			t = tt.Type
+		default:
+			return false
+		}
+	}
+}
+
+// Numbers of UNIVERSAL tags of built-in types, see X.680, section 8.6.
+const (
+	tagBoolean          = 1
+	tagInteger          = 2
+	tagBitString        = 3
+	tagOctetString      = 4
+	tagNull             = 5
+	tagObjectIdentifier = 6
+	tagReal             = 9
+	tagEnumerated       = 10
+	tagUTF8String       = 12
+	tagSequence         = 16
+	tagSet              = 17
+	tagNumericString    = 18
+	tagPrintableString  = 19
+	tagTeletexString    = 20
+	tagVideotexString   = 21
+	tagIA5String        = 22
+	tagUTCTime          = 23
+	tagGeneralizedTime  = 24
+	tagGraphicString    = 25
+	tagVisibleString    = 26
+	tagGeneralString    = 27
+	tagUniversalString  = 28
+	tagCharacterString  = 29
+	tagBMPString        = 30
+)
+
+// restrictedStringTags maps lexem types of restricted string types to their UNIVERSAL tag numbers.
+var restrictedStringTags = map[int]int{
+	BMPString:       tagBMPString,
+	GeneralString:   tagGeneralString,
+	GraphicString:   tagGraphicString,
+	IA5String:       tagIA5String,
+	ISO646String:    tagVisibleString,
+	NumericString:   tagNumericString,
+	PrintableString: tagPrintableString,
+	TeletexString:   tagTeletexString,
+	T61String:       tagTeletexString,
+	UniversalString: tagUniversalString,
+	UTF8String:      tagUTF8String,
+	VideotexString:  tagVideotexString,
+	VisibleString:   tagVisibleString,
+}
+
+// universalTag returns number of UNIVERSAL tag of built-in type.
+// Returns false for types which do not have a tag of their own, e.g. CHOICE, ANY, type references and tagged types.
+func universalTag(t Type) (int, bool) {
+	switch tt := t.(type) {
+	case BooleanType:
+		return tagBoolean, true
+	case IntegerType:
+		return tagInteger, true
+	case BitStringType:
+		return tagBitString, true
+	case OctetStringType:
+		return tagOctetString, true
+	case NullType:
+		return tagNull, true
+	case ObjectIdentifierType:
+		return tagObjectIdentifier, true
+	case RealType:
+		return tagReal, true
+	case EnumeratedType:
+		return tagEnumerated, true
+	case SequenceType, SequenceOfType:
+		return tagSequence, true
+	case SetType, SetOfType:
+		return tagSet, true
+	case CharacterStringType:
+		return tagCharacterString, true
+	case RestrictedStringType:
+		tag, ok := restrictedStringTags[tt.LexType]
+		return tag, ok
+	default:
+		return 0, false
+	}
+}
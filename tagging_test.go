@@ -0,0 +1,61 @@
+package asn1go
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAutomaticallyTaggedChoice(t *testing.T) {
+	m := parseModule(t, `
+	TestSpec DEFINITIONS AUTOMATIC TAGS ::= BEGIN
+		Choice ::= CHOICE {
+			a INTEGER,
+			b SEQUENCE { x BOOLEAN },
+			...,
+			[[ c INTEGER, d INTEGER ]],
+			e INTEGER,
+			...
+		}
+		Tagged ::= CHOICE {
+			a INTEGER,
+			...,
+			b [APPLICATION 1] INTEGER
+		}
+	END
+	`)
+	tag := func(number int, t Type) Type {
+		return TaggedType{Tag: Tag{Class: CLASS_CONTEXT_SPECIFIC, ClassNumber: Number(number)}, Type: t}
+	}
+	expected := AssignmentList{
+		TypeAssignment{TypeReference: "Choice", Type: ChoiceType{
+			AlternativeTypeList: []NamedType{
+				{Identifier: "a", Type: tag(0, IntegerType{})},
+				{Identifier: "b", Type: tag(1, SequenceType{Components: ComponentTypeList{
+					NamedComponentType{NamedType: NamedType{Identifier: "x", Type: tag(0, BooleanType{})}},
+				}})},
+			},
+			ExtensionTypes: []ChoiceExtension{
+				ExtensionAdditionAlternativesGroup{Alternatives: []NamedType{
+					{Identifier: "c", Type: tag(2, IntegerType{})},
+					{Identifier: "d", Type: tag(3, IntegerType{})},
+				}},
+				NamedType{Identifier: "e", Type: tag(4, IntegerType{})},
+			},
+			Extensible: true,
+		}},
+		TypeAssignment{TypeReference: "Tagged", Type: ChoiceType{
+			AlternativeTypeList: []NamedType{
+				{Identifier: "a", Type: IntegerType{}},
+			},
+			ExtensionTypes: []ChoiceExtension{
+				NamedType{Identifier: "b", Type: TaggedType{Tag: Tag{Class: CLASS_APPLICATION, ClassNumber: Number(1)}, Type: IntegerType{}}},
+			},
+			Extensible: true,
+		}},
+	}
+	got := applyAutomaticTagging(m.ModuleBody).AssignmentList
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("Tagged types did not match expected, diff (-want, +got):\n%v", diff)
+	}
+}
@@ -0,0 +1,395 @@
+package xer
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chemikadze/asn1go/der"
+)
+
+// element is XML element of decoded document.
+type element struct {
+	name string
+	// content holds character data as strings, and child elements as *element values.
+	content []any
+}
+
+// parse reads XML document holding a single element.
+func parse(data []byte) (*element, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	var root *element
+	var stack []*element
+	for {
+		token, err := dec.Token()
+		if err == io.EOF && root != nil {
+			return root, nil
+		}
+		if err != nil {
+			return nil, SyntaxError{err.Error()}
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			if root != nil && len(stack) == 0 {
+				return nil, SyntaxError{"unexpected element " + t.Name.Local + " after the value"}
+			}
+			e := &element{name: t.Name.Local}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.content = append(parent.content, e)
+			} else {
+				root = e
+			}
+			stack = append(stack, e)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) == 0 {
+				if len(bytes.TrimSpace(t)) > 0 {
+					return nil, SyntaxError{"unexpected character data outside of the value"}
+				}
+				continue
+			}
+			parent := stack[len(stack)-1]
+			if n := len(parent.content); n > 0 {
+				if text, ok := parent.content[n-1].(string); ok {
+					parent.content[n-1] = text + string(t)
+					continue
+				}
+			}
+			parent.content = append(parent.content, string(t))
+		case xml.Comment, xml.ProcInst:
+			continue
+		default:
+			return nil, SyntaxError{fmt.Sprintf("unexpected %T", t)}
+		}
+	}
+}
+
+// Decoder decodes a single value from content of XML element. Decoders of child elements
+// are passed to callbacks of ReadElements, ReadChoice, ReadList and ReadValueList.
+type Decoder struct {
+	content []any
+}
+
+// elements returns child elements, ignoring whitespace between them.
+func (d *Decoder) elements() ([]*element, error) {
+	var res []*element
+	for _, c := range d.content {
+		switch c := c.(type) {
+		case *element:
+			res = append(res, c)
+		case string:
+			if strings.TrimSpace(c) != "" {
+				return nil, SyntaxError{fmt.Sprintf("unexpected character data %.32q", c)}
+			}
+		}
+	}
+	return res, nil
+}
+
+// text returns character data with surrounding whitespace removed.
+func (d *Decoder) text(typeName string) (string, error) {
+	var sb strings.Builder
+	for _, c := range d.content {
+		switch c := c.(type) {
+		case *element:
+			return "", SyntaxError{fmt.Sprintf("unexpected element %v in %v value", c.name, typeName)}
+		case string:
+			sb.WriteString(c)
+		}
+	}
+	return strings.TrimSpace(sb.String()), nil
+}
+
+// name returns identifier of the value, which is either the name of the only empty child element, e.g. <true/>,
+// or character data.
+func (d *Decoder) name(typeName string) (string, error) {
+	elements, err := d.elements()
+	if err == nil && len(elements) == 1 && len(elements[0].content) == 0 {
+		return elements[0].name, nil
+	}
+	return d.text(typeName)
+}
+
+// ReadElements reads child elements, which encode components of SEQUENCE or SET value,
+// calling fn with name of each element and decoder of its content.
+func (d *Decoder) ReadElements(fn func(name string, d *Decoder) error) error {
+	elements, err := d.elements()
+	if err != nil {
+		return err
+	}
+	for _, e := range elements {
+		if err := fn(e.name, &Decoder{content: e.content}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadChoice reads a single child element, which encodes value of CHOICE type.
+// Name of the element is identifier of the chosen alternative.
+func (d *Decoder) ReadChoice(fn func(name string, d *Decoder) error) error {
+	elements, err := d.elements()
+	if err != nil {
+		return err
+	}
+	if len(elements) != 1 {
+		return SyntaxError{fmt.Sprintf("CHOICE value should have exactly one element, got %v", len(elements))}
+	}
+	return fn(elements[0].name, &Decoder{content: elements[0].content})
+}
+
+// ReadList reads elements of SEQUENCE OF or SET OF value, which are enclosed into elements named by their type,
+// calling fn with decoder of content of each element.
+func (d *Decoder) ReadList(fn func(d *Decoder) error) error {
+	return d.ReadElements(func(_ string, d *Decoder) error {
+		return fn(d)
+	})
+}
+
+// ReadValueList reads elements of SEQUENCE OF or SET OF value of BOOLEAN, ENUMERATED or CHOICE type,
+// which are either not enclosed into elements, or enclosed into elements named typeName,
+// calling fn with decoder of each element.
+func (d *Decoder) ReadValueList(typeName string, fn func(d *Decoder) error) error {
+	elements, err := d.elements()
+	if err != nil {
+		return err
+	}
+	for _, e := range elements {
+		content := []any{e}
+		if e.name == typeName {
+			content = e.content
+		}
+		if err := fn(&Decoder{content: content}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadBoolean reads BOOLEAN value encoded as empty element <true/> or <false/>, or as text true or false.
+func (d *Decoder) ReadBoolean(v *bool) error {
+	name, err := d.name("BOOLEAN")
+	if err != nil {
+		return err
+	}
+	switch name {
+	case "true":
+		*v = true
+	case "false":
+		*v = false
+	default:
+		return SyntaxError{fmt.Sprintf("invalid BOOLEAN value %.32q", name)}
+	}
+	return nil
+}
+
+// number returns text of INTEGER value.
+func (d *Decoder) number() (string, error) {
+	n, err := d.text("INTEGER")
+	if err != nil {
+		return "", err
+	}
+	if digits := strings.TrimPrefix(n, "-"); digits == "" || strings.Trim(digits, "0123456789") != "" {
+		return "", SyntaxError{fmt.Sprintf("invalid INTEGER value %.32q", n)}
+	}
+	return n, nil
+}
+
+// ReadInteger reads INTEGER value.
+func (d *Decoder) ReadInteger(v *int64) error {
+	n, err := d.number()
+	if err != nil {
+		return err
+	}
+	res, err := strconv.ParseInt(n, 10, 64)
+	if err != nil {
+		return SyntaxError{fmt.Sprintf("INTEGER value %v does not fit into 64 bits", n)}
+	}
+	*v = res
+	return nil
+}
+
+// ReadBigInteger reads INTEGER value.
+func (d *Decoder) ReadBigInteger(v **big.Int) error {
+	n, err := d.number()
+	if err != nil {
+		return err
+	}
+	res, _ := new(big.Int).SetString(n, 10) // number is validated
+	*v = res
+	return nil
+}
+
+// ReadEnumerated reads ENUMERATED value encoded as empty element named by its identifier, or as text identifier.
+func (d *Decoder) ReadEnumerated(v *asn1.Enumerated, en Enumeration) error {
+	name, err := d.name("ENUMERATED")
+	if err != nil {
+		return err
+	}
+	for _, item := range en {
+		if item.Name == name {
+			*v = asn1.Enumerated(item.Value)
+			return nil
+		}
+	}
+	return SyntaxError{fmt.Sprintf("unknown enumeration identifier %.32q", name)}
+}
+
+// ReadReal reads REAL value in any of decimal forms, or special value encoded as empty element
+// or as text INF, -INF or NaN.
+func (d *Decoder) ReadReal(v *float64) error {
+	s, err := d.name("REAL")
+	if err != nil {
+		return err
+	}
+	switch s {
+	case "PLUS-INFINITY", "INF":
+		*v = math.Inf(1)
+		return nil
+	case "MINUS-INFINITY", "-INF":
+		*v = math.Inf(-1)
+		return nil
+	case "NOT-A-NUMBER", "NaN":
+		*v = math.NaN()
+		return nil
+	}
+	res, err := strconv.ParseFloat(s, 64)
+	if err != nil || s == "" || strings.Trim(s, "0123456789.eE+-") != "" {
+		return SyntaxError{fmt.Sprintf("invalid REAL value %.32q", s)}
+	}
+	*v = res
+	return nil
+}
+
+// ReadBitString reads BIT STRING value encoded as a string of 0 and 1 characters, which can be separated by whitespace.
+func (d *Decoder) ReadBitString(v *asn1.BitString) error {
+	s, err := d.text("BIT STRING")
+	if err != nil {
+		return err
+	}
+	bits := strings.Join(strings.Fields(s), "")
+	res := asn1.BitString{Bytes: make([]byte, (len(bits)+7)/8), BitLength: len(bits)}
+	for i, c := range bits {
+		switch c {
+		case '1':
+			res.Bytes[i/8] |= 0x80 >> (i % 8)
+		case '0':
+		default:
+			return SyntaxError{fmt.Sprintf("invalid BIT STRING value %.32q", s)}
+		}
+	}
+	*v = res
+	return nil
+}
+
+// ReadOctetString reads OCTET STRING value encoded as hexadecimal string, which can be separated by whitespace.
+func (d *Decoder) ReadOctetString(v *[]byte) error {
+	s, err := d.text("OCTET STRING")
+	if err != nil {
+		return err
+	}
+	res, err := hex.DecodeString(strings.Join(strings.Fields(s), ""))
+	if err != nil {
+		return SyntaxError{fmt.Sprintf("invalid hexadecimal string %.32q", s)}
+	}
+	*v = res
+	return nil
+}
+
+// ReadString reads value of character string type, where control characters can be encoded as empty elements.
+// Unlike values of other types, whitespace is preserved.
+func (d *Decoder) ReadString(v *string) error {
+	var sb strings.Builder
+	for _, c := range d.content {
+		switch c := c.(type) {
+		case *element:
+			code := slices.Index(controlCharacters, c.name)
+			if code < 0 || len(c.content) > 0 {
+				return SyntaxError{fmt.Sprintf("unexpected element %v in character string value", c.name)}
+			}
+			sb.WriteByte(byte(code))
+		case string:
+			sb.WriteString(c)
+		}
+	}
+	*v = sb.String()
+	return nil
+}
+
+// ReadNull reads NULL value, which has no content.
+func (d *Decoder) ReadNull() error {
+	s, err := d.text("NULL")
+	if err != nil {
+		return err
+	}
+	if s != "" {
+		return SyntaxError{fmt.Sprintf("unexpected content of NULL value %.32q", s)}
+	}
+	return nil
+}
+
+// ReadObjectIdentifier reads OBJECT IDENTIFIER value encoded as dot-separated arcs.
+// Arcs in name and number form, e.g. iso(1), are accepted as well.
+func (d *Decoder) ReadObjectIdentifier(v *asn1.ObjectIdentifier) error {
+	s, err := d.text("OBJECT IDENTIFIER")
+	if err != nil {
+		return err
+	}
+	arcs := strings.Split(strings.Join(strings.Fields(s), ""), ".")
+	if len(arcs) < 2 {
+		return SyntaxError{fmt.Sprintf("invalid object identifier %.32q", s)}
+	}
+	res := make(asn1.ObjectIdentifier, len(arcs))
+	for i, arc := range arcs {
+		if _, number, ok := strings.Cut(arc, "("); ok && strings.HasSuffix(number, ")") {
+			arc = strings.TrimSuffix(number, ")")
+		}
+		n, err := strconv.Atoi(arc)
+		if err != nil || n < 0 || arc[0] == '+' {
+			return SyntaxError{fmt.Sprintf("invalid object identifier %.32q", s)}
+		}
+		res[i] = n
+	}
+	*v = res
+	return nil
+}
+
+// ReadGeneralizedTime reads GeneralizedTime value, which is accepted in any format valid in BER.
+func (d *Decoder) ReadGeneralizedTime(v *time.Time) error {
+	dec, err := d.derDecoder(der.TagGeneralizedTime)
+	if err != nil {
+		return err
+	}
+	return dec.ReadGeneralizedTime(der.TagGeneralizedTime, v)
+}
+
+// ReadUTCTime reads UTCTime value, which is accepted in any format valid in BER.
+func (d *Decoder) ReadUTCTime(v *time.Time) error {
+	dec, err := d.derDecoder(der.TagUTCTime)
+	if err != nil {
+		return err
+	}
+	return dec.ReadUTCTime(der.TagUTCTime, v)
+}
+
+// derDecoder returns decoder of DER package reading text as character string value with the tag.
+func (d *Decoder) derDecoder(tag der.Tag) (*der.Decoder, error) {
+	s, err := d.text("time")
+	if err != nil {
+		return nil, err
+	}
+	var enc der.Encoder
+	enc.WriteString(tag, s)
+	return der.NewDecoder(enc.Bytes()), nil
+}
@@ -0,0 +1,252 @@
+package xer
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chemikadze/asn1go/der"
+)
+
+// Encoder appends CXER encodings of values to a buffer. Zero value is ready to use.
+// Values of SEQUENCE, SET and CHOICE types are written as XML elements, which are started by BeginElement,
+// and ended by EndElement. Elements without content are written as empty element tags, e.g. <a/>.
+type Encoder struct {
+	buf []byte
+	// open is set if start tag of the last element is not closed yet.
+	open bool
+}
+
+// Bytes returns encoded data.
+func (e *Encoder) Bytes() []byte {
+	return e.buf
+}
+
+// Reset discards encoded data, so that encoder can be reused.
+func (e *Encoder) Reset() {
+	e.buf = e.buf[:0]
+	e.open = false
+}
+
+// closeStart closes start tag of the last element, as it has content.
+func (e *Encoder) closeStart() {
+	if e.open {
+		e.buf = append(e.buf, '>')
+		e.open = false
+	}
+}
+
+// BeginElement starts XML element with given name, e.g. identifier of SEQUENCE component.
+func (e *Encoder) BeginElement(name string) {
+	e.closeStart()
+	e.buf = append(e.buf, '<')
+	e.buf = append(e.buf, name...)
+	e.open = true
+}
+
+// EndElement ends XML element started by BeginElement.
+func (e *Encoder) EndElement(name string) {
+	if e.open {
+		e.buf = append(e.buf, "/>"...)
+		e.open = false
+		return
+	}
+	e.buf = append(e.buf, "</"...)
+	e.buf = append(e.buf, name...)
+	e.buf = append(e.buf, '>')
+}
+
+// writeEmptyElement appends XML element without content, e.g. <true/>.
+func (e *Encoder) writeEmptyElement(name string) {
+	e.BeginElement(name)
+	e.EndElement(name)
+}
+
+// writeText appends character data, which should not need escaping.
+func (e *Encoder) writeText(s string) {
+	if s == "" {
+		return
+	}
+	e.closeStart()
+	e.buf = append(e.buf, s...)
+}
+
+// WriteList appends elements of SEQUENCE OF value, fn appends i-th element.
+func (e *Encoder) WriteList(n int, fn func(i int) error) error {
+	for i := 0; i < n; i++ {
+		if err := fn(i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteSetOf appends elements of SET OF value, fn appends i-th element.
+// Encodings of elements are sorted in ascending order, as required by CXER.
+func (e *Encoder) WriteSetOf(n int, fn func(i int) error) error {
+	if n == 0 {
+		return nil
+	}
+	e.closeStart()
+	start := len(e.buf)
+	bounds := make([]int, 0, n+1)
+	bounds = append(bounds, start)
+	for i := 0; i < n; i++ {
+		if err := fn(i); err != nil {
+			return err
+		}
+		e.closeStart()
+		bounds = append(bounds, len(e.buf))
+	}
+	elements := make([][]byte, 0, n)
+	for i := 0; i < n; i++ {
+		elements = append(elements, slices.Clone(e.buf[bounds[i]:bounds[i+1]]))
+	}
+	slices.SortFunc(elements, bytes.Compare)
+	e.buf = e.buf[:start]
+	for _, element := range elements {
+		e.buf = append(e.buf, element...)
+	}
+	return nil
+}
+
+// WriteBoolean appends BOOLEAN value as empty element <true/> or <false/>.
+func (e *Encoder) WriteBoolean(v bool) {
+	if v {
+		e.writeEmptyElement("true")
+	} else {
+		e.writeEmptyElement("false")
+	}
+}
+
+// WriteInteger appends INTEGER value in decimal form.
+func (e *Encoder) WriteInteger(v int64) {
+	e.writeText(strconv.FormatInt(v, 10))
+}
+
+// WriteBigInteger appends INTEGER value in decimal form.
+func (e *Encoder) WriteBigInteger(v *big.Int) error {
+	if v == nil {
+		return fmt.Errorf("xer: INTEGER value is nil")
+	}
+	e.writeText(v.String())
+	return nil
+}
+
+// WriteEnumerated appends ENUMERATED value as empty element named by its identifier.
+func (e *Encoder) WriteEnumerated(v asn1.Enumerated, en Enumeration) error {
+	for _, item := range en {
+		if item.Value == int64(v) {
+			e.writeEmptyElement(item.Name)
+			return nil
+		}
+	}
+	return fmt.Errorf("xer: unknown enumeration value %v", v)
+}
+
+// WriteReal appends REAL value. Zero is encoded as 0 or -0, and other numbers are encoded in scientific notation
+// with the shortest mantissa, which has no trailing zeros, and exponent without plus sign and leading zeros,
+// e.g. 1.5E1. Infinities and NaN are encoded as empty elements <PLUS-INFINITY/>, <MINUS-INFINITY/>
+// and <NOT-A-NUMBER/>.
+func (e *Encoder) WriteReal(v float64) {
+	switch {
+	case math.IsInf(v, 1):
+		e.writeEmptyElement("PLUS-INFINITY")
+	case math.IsInf(v, -1):
+		e.writeEmptyElement("MINUS-INFINITY")
+	case math.IsNaN(v):
+		e.writeEmptyElement("NOT-A-NUMBER")
+	case v == 0 && math.Signbit(v):
+		e.writeText("-0")
+	case v == 0:
+		e.writeText("0")
+	default:
+		mantissa, exponent, _ := strings.Cut(strconv.FormatFloat(v, 'E', -1, 64), "E")
+		exp, _ := strconv.Atoi(exponent) // exponent is always a valid number
+		e.writeText(mantissa + "E" + strconv.Itoa(exp))
+	}
+}
+
+// WriteBitString appends BIT STRING value as a string of 0 and 1 characters.
+func (e *Encoder) WriteBitString(v asn1.BitString) error {
+	if v.BitLength < 0 || len(v.Bytes) < (v.BitLength+7)/8 {
+		return fmt.Errorf("xer: invalid BIT STRING with %v bits in %v octets", v.BitLength, len(v.Bytes))
+	}
+	bits := make([]byte, v.BitLength)
+	for i := range bits {
+		bits[i] = '0' + byte(v.At(i))
+	}
+	e.writeText(string(bits))
+	return nil
+}
+
+// WriteOctetString appends OCTET STRING value as hexadecimal string with upper case digits.
+func (e *Encoder) WriteOctetString(v []byte) {
+	e.writeText(strings.ToUpper(hex.EncodeToString(v)))
+}
+
+// WriteString appends value of character string type. Characters &, < and > are escaped with entity references,
+// and control characters are encoded as empty elements, e.g. <lf/>.
+func (e *Encoder) WriteString(v string) {
+	for _, r := range v {
+		switch {
+		case r == '&':
+			e.writeText("&amp;")
+		case r == '<':
+			e.writeText("&lt;")
+		case r == '>':
+			e.writeText("&gt;")
+		case r < rune(len(controlCharacters)):
+			e.writeEmptyElement(controlCharacters[r])
+		default:
+			e.writeText(string(r))
+		}
+	}
+}
+
+// WriteNull appends NULL value, which has no content.
+func (e *Encoder) WriteNull() {}
+
+// WriteObjectIdentifier appends OBJECT IDENTIFIER value as dot-separated arcs.
+func (e *Encoder) WriteObjectIdentifier(v asn1.ObjectIdentifier) error {
+	if len(v) < 2 {
+		return fmt.Errorf("xer: invalid object identifier %v", v)
+	}
+	e.writeText(v.String())
+	return nil
+}
+
+// WriteGeneralizedTime appends GeneralizedTime value in the same format as DER.
+func (e *Encoder) WriteGeneralizedTime(v time.Time) error {
+	var enc der.Encoder
+	if err := enc.WriteGeneralizedTime(der.TagGeneralizedTime, v); err != nil {
+		return err
+	}
+	return e.writeDERString(enc.Bytes())
+}
+
+// WriteUTCTime appends UTCTime value in the same format as DER.
+func (e *Encoder) WriteUTCTime(v time.Time) error {
+	var enc der.Encoder
+	if err := enc.WriteUTCTime(der.TagUTCTime, v); err != nil {
+		return err
+	}
+	return e.writeDERString(enc.Bytes())
+}
+
+// writeDERString appends contents octets of DER encoding of a character string.
+func (e *Encoder) writeDERString(encoding []byte) error {
+	var raw asn1.RawValue
+	if err := der.NewDecoder(encoding).ReadRawValue(&raw); err != nil {
+		return err
+	}
+	e.WriteString(string(raw.Bytes))
+	return nil
+}
@@ -0,0 +1,104 @@
+// Package xer implements XML Encoding Rules of ASN.1, as defined in X.693.
+//
+// It is a runtime library of the code generated by asn1go with GEN_XER code generator type,
+// and is not intended to be used directly. Encoder always produces canonical encodings (CXER),
+// which are also valid BASIC-XER encodings. Decoder accepts any BASIC-XER encoding,
+// and UnmarshalCanonical additionally verifies that the encoding is canonical.
+package xer
+
+import (
+	"bytes"
+	"errors"
+)
+
+// ErrAbsentValue is returned when value of CHOICE to encode is nil.
+var ErrAbsentValue = errors.New("xer: value of CHOICE is not set")
+
+// SyntaxError is returned when decoded data is not a valid XER encoding of the type,
+// or, with UnmarshalCanonical, is not a canonical encoding.
+type SyntaxError struct {
+	Msg string
+}
+
+func (e SyntaxError) Error() string {
+	return "xer: syntax error: " + e.Msg
+}
+
+// Marshaler is implemented by generated types, which encode themselves without reflection.
+type Marshaler interface {
+	// EncodeXER appends XER encoding of the value to e, which is the content of the element holding the value.
+	EncodeXER(e *Encoder) error
+}
+
+// Unmarshaler is implemented by generated types, which decode themselves without reflection.
+type Unmarshaler interface {
+	// DecodeXER decodes the value from content of the element of d.
+	DecodeXER(d *Decoder) error
+}
+
+// UnmarshalerFunc is an adapter to use function, e.g. generated function decoding CHOICE type, as Unmarshaler.
+type UnmarshalerFunc func(d *Decoder) error
+
+// DecodeXER calls f(d).
+func (f UnmarshalerFunc) DecodeXER(d *Decoder) error {
+	return f(d)
+}
+
+// Marshal returns CXER encoding of the value as XML element with given name, which is the name of its type.
+func Marshal(name string, v Marshaler) ([]byte, error) {
+	var e Encoder
+	e.BeginElement(name)
+	if err := v.EncodeXER(&e); err != nil {
+		return nil, err
+	}
+	e.EndElement(name)
+	return e.Bytes(), nil
+}
+
+// Unmarshal decodes BASIC-XER encoding of the value from data, which should hold a single XML element
+// with given name, which is the name of its type. XML declaration and comments are ignored.
+func Unmarshal(data []byte, name string, v Unmarshaler) error {
+	root, err := parse(data)
+	if err != nil {
+		return err
+	}
+	if root.name != name {
+		return SyntaxError{"expected element " + name + ", got " + root.name}
+	}
+	return v.DecodeXER(&Decoder{content: root.content})
+}
+
+// UnmarshalCanonical is same as Unmarshal, but rejects encodings which are not canonical.
+// Values with unknown extension additions are rejected as well, as their encodings can not be verified.
+func UnmarshalCanonical(data []byte, name string, v interface {
+	Marshaler
+	Unmarshaler
+}) error {
+	if err := Unmarshal(data, name, v); err != nil {
+		return err
+	}
+	canonical, err := Marshal(name, v)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(data, canonical) {
+		return SyntaxError{"encoding is not canonical"}
+	}
+	return nil
+}
+
+// Enumeration describes identifiers of ENUMERATED type, which are used as encodings of its values.
+type Enumeration []EnumerationItem
+
+// EnumerationItem is identifier of ENUMERATED type with its value.
+type EnumerationItem struct {
+	Name  string
+	Value int64
+}
+
+// controlCharacters are names of empty elements, which represent control characters in character strings.
+// Index of the name is the code of the character. See X.680, section 12.15.9.
+var controlCharacters = []string{
+	"nul", "soh", "stx", "etx", "eot", "enq", "ack", "bel", "bs", "ht", "lf", "vt", "ff", "cr", "so", "si",
+	"dle", "dc1", "dc2", "dc3", "dc4", "nak", "syn", "etb", "can", "em", "sub", "esc", "is4", "is3", "is2", "is1",
+}
@@ -0,0 +1,415 @@
+package xer
+
+import (
+	"encoding/asn1"
+	"math"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEncoding(t *testing.T) {
+	enumeration := Enumeration{{Name: "low", Value: 0}, {Name: "high", Value: 200}}
+	testCases := []struct {
+		name     string
+		write    func(e *Encoder) error
+		read     func(d *Decoder) (any, error)
+		value    any
+		expected string
+	}{
+		{
+			name:     "boolean",
+			write:    func(e *Encoder) error { e.WriteBoolean(true); return nil },
+			read:     func(d *Decoder) (any, error) { var b bool; err := d.ReadBoolean(&b); return b, err },
+			value:    true,
+			expected: `<v><true/></v>`,
+		},
+		{
+			name:     "integer",
+			write:    func(e *Encoder) error { e.WriteInteger(-42); return nil },
+			read:     func(d *Decoder) (any, error) { var i int64; err := d.ReadInteger(&i); return i, err },
+			value:    int64(-42),
+			expected: `<v>-42</v>`,
+		},
+		{
+			name: "big integer",
+			write: func(e *Encoder) error {
+				return e.WriteBigInteger(new(big.Int).Lsh(big.NewInt(1), 80))
+			},
+			read:     func(d *Decoder) (any, error) { var i *big.Int; err := d.ReadBigInteger(&i); return i, err },
+			value:    new(big.Int).Lsh(big.NewInt(1), 80),
+			expected: `<v>1208925819614629174706176</v>`,
+		},
+		{
+			name:  "enumerated",
+			write: func(e *Encoder) error { return e.WriteEnumerated(200, enumeration) },
+			read: func(d *Decoder) (any, error) {
+				var v asn1.Enumerated
+				err := d.ReadEnumerated(&v, enumeration)
+				return v, err
+			},
+			value:    asn1.Enumerated(200),
+			expected: `<v><high/></v>`,
+		},
+		{
+			name:     "real",
+			write:    func(e *Encoder) error { e.WriteReal(-2.5e-10); return nil },
+			read:     func(d *Decoder) (any, error) { var f float64; err := d.ReadReal(&f); return f, err },
+			value:    -2.5e-10,
+			expected: `<v>-2.5E-10</v>`,
+		},
+		{
+			name:     "real integer",
+			write:    func(e *Encoder) error { e.WriteReal(100); return nil },
+			read:     func(d *Decoder) (any, error) { var f float64; err := d.ReadReal(&f); return f, err },
+			value:    100.0,
+			expected: `<v>1E2</v>`,
+		},
+		{
+			name:     "real zero",
+			write:    func(e *Encoder) error { e.WriteReal(0); return nil },
+			read:     func(d *Decoder) (any, error) { var f float64; err := d.ReadReal(&f); return f, err },
+			value:    0.0,
+			expected: `<v>0</v>`,
+		},
+		{
+			name:     "real infinity",
+			write:    func(e *Encoder) error { e.WriteReal(math.Inf(-1)); return nil },
+			read:     func(d *Decoder) (any, error) { var f float64; err := d.ReadReal(&f); return f, err },
+			value:    math.Inf(-1),
+			expected: `<v><MINUS-INFINITY/></v>`,
+		},
+		{
+			name: "bit string",
+			write: func(e *Encoder) error {
+				return e.WriteBitString(asn1.BitString{Bytes: []byte{0xa8, 0x00}, BitLength: 5})
+			},
+			read: func(d *Decoder) (any, error) {
+				var v asn1.BitString
+				err := d.ReadBitString(&v)
+				return v, err
+			},
+			value:    asn1.BitString{Bytes: []byte{0xa8}, BitLength: 5},
+			expected: `<v>10101</v>`,
+		},
+		{
+			name:     "octet string",
+			write:    func(e *Encoder) error { e.WriteOctetString([]byte{0x01, 0xab}); return nil },
+			read:     func(d *Decoder) (any, error) { var b []byte; err := d.ReadOctetString(&b); return b, err },
+			value:    []byte{0x01, 0xab},
+			expected: `<v>01AB</v>`,
+		},
+		{
+			name:     "string with escaped characters",
+			write:    func(e *Encoder) error { e.WriteString("<a & \"b\">\n "); return nil },
+			read:     func(d *Decoder) (any, error) { var s string; err := d.ReadString(&s); return s, err },
+			value:    "<a & \"b\">\n ",
+			expected: `<v>&lt;a &amp; "b"&gt;<lf/> </v>`,
+		},
+		{
+			name:     "empty string",
+			write:    func(e *Encoder) error { e.WriteString(""); return nil },
+			read:     func(d *Decoder) (any, error) { var s string; err := d.ReadString(&s); return s, err },
+			value:    "",
+			expected: `<v/>`,
+		},
+		{
+			name:     "null",
+			write:    func(e *Encoder) error { e.WriteNull(); return nil },
+			read:     func(d *Decoder) (any, error) { return nil, d.ReadNull() },
+			value:    nil,
+			expected: `<v/>`,
+		},
+		{
+			name:  "object identifier",
+			write: func(e *Encoder) error { return e.WriteObjectIdentifier(asn1.ObjectIdentifier{2, 5, 4, 3}) },
+			read: func(d *Decoder) (any, error) {
+				var v asn1.ObjectIdentifier
+				err := d.ReadObjectIdentifier(&v)
+				return v, err
+			},
+			value:    asn1.ObjectIdentifier{2, 5, 4, 3},
+			expected: `<v>2.5.4.3</v>`,
+		},
+		{
+			name: "generalized time",
+			write: func(e *Encoder) error {
+				return e.WriteGeneralizedTime(time.Date(2024, 1, 2, 3, 4, 5, 500000000, time.UTC))
+			},
+			read: func(d *Decoder) (any, error) {
+				var v time.Time
+				err := d.ReadGeneralizedTime(&v)
+				return v, err
+			},
+			value:    time.Date(2024, 1, 2, 3, 4, 5, 500000000, time.UTC),
+			expected: `<v>20240102030405.5Z</v>`,
+		},
+		{
+			name:  "empty set of",
+			write: func(e *Encoder) error { return e.WriteSetOf(0, func(int) error { return nil }) },
+			read: func(d *Decoder) (any, error) {
+				var items []int64
+				err := d.ReadList(func(*Decoder) error { items = append(items, 0); return nil })
+				return items, err
+			},
+			value:    []int64(nil),
+			expected: `<v/>`,
+		},
+		{
+			name: "elements and lists",
+			write: func(e *Encoder) error {
+				e.BeginElement("a")
+				e.WriteInteger(1)
+				e.EndElement("a")
+				e.BeginElement("b")
+				err := e.WriteList(2, func(i int) error { e.WriteBoolean(i == 0); return nil })
+				e.EndElement("b")
+				if err != nil {
+					return err
+				}
+				e.BeginElement("c")
+				err = e.WriteSetOf(3, func(i int) error {
+					e.BeginElement("INTEGER")
+					e.WriteInteger([]int64{3, 1, 2}[i])
+					e.EndElement("INTEGER")
+					return nil
+				})
+				e.EndElement("c")
+				return err
+			},
+			read: func(d *Decoder) (any, error) {
+				res := make(map[string]any)
+				err := d.ReadElements(func(name string, d *Decoder) error {
+					switch name {
+					case "a":
+						var i int64
+						err := d.ReadInteger(&i)
+						res[name] = i
+						return err
+					case "b":
+						var items []bool
+						err := d.ReadValueList("BOOLEAN", func(d *Decoder) error {
+							var b bool
+							err := d.ReadBoolean(&b)
+							items = append(items, b)
+							return err
+						})
+						res[name] = items
+						return err
+					default:
+						var items []int64
+						err := d.ReadList(func(d *Decoder) error {
+							var i int64
+							err := d.ReadInteger(&i)
+							items = append(items, i)
+							return err
+						})
+						res[name] = items
+						return err
+					}
+				})
+				return res, err
+			},
+			value:    map[string]any{"a": int64(1), "b": []bool{true, false}, "c": []int64{1, 2, 3}},
+			expected: `<v><a>1</a><b><true/><false/></b><c><INTEGER>1</INTEGER><INTEGER>2</INTEGER><INTEGER>3</INTEGER></c></v>`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var e Encoder
+			e.BeginElement("v")
+			if err := tc.write(&e); err != nil {
+				t.Fatalf("Failed to encode: %v", err)
+			}
+			e.EndElement("v")
+			if string(e.Bytes()) != tc.expected {
+				t.Errorf("Expected encoding %s, got %s", tc.expected, e.Bytes())
+			}
+			var decoded any
+			err := Unmarshal([]byte("<?xml version=\"1.0\"?>\n"+tc.expected+"\n"), "v", UnmarshalerFunc(func(d *Decoder) error {
+				var err error
+				decoded, err = tc.read(d)
+				return err
+			}))
+			if err != nil {
+				t.Fatalf("Failed to decode: %v", err)
+			}
+			if !reflect.DeepEqual(decoded, tc.value) {
+				t.Errorf("Expected decoded value %#v, got %#v", tc.value, decoded)
+			}
+		})
+	}
+}
+
+func TestBasicDecoding(t *testing.T) {
+	var v struct {
+		b    bool
+		i    int64
+		r    float64
+		bits asn1.BitString
+		oid  asn1.ObjectIdentifier
+		data []byte
+		list []bool
+	}
+	data := `<v>
+		<b> true </b>
+		<!-- comment -->
+		<i> 7 </i>
+		<r> 1.5e1 </r>
+		<bits> 101 0 </bits>
+		<oid>iso(1). 2 .840</oid>
+		<data>0a bc</data>
+		<list><BOOLEAN><true/></BOOLEAN> <false/></list>
+	</v>`
+	err := Unmarshal([]byte(data), "v", UnmarshalerFunc(func(d *Decoder) error {
+		return d.ReadElements(func(name string, d *Decoder) error {
+			switch name {
+			case "b":
+				return d.ReadBoolean(&v.b)
+			case "i":
+				return d.ReadInteger(&v.i)
+			case "r":
+				return d.ReadReal(&v.r)
+			case "bits":
+				return d.ReadBitString(&v.bits)
+			case "oid":
+				return d.ReadObjectIdentifier(&v.oid)
+			case "data":
+				return d.ReadOctetString(&v.data)
+			default:
+				return d.ReadValueList("BOOLEAN", func(d *Decoder) error {
+					var b bool
+					err := d.ReadBoolean(&b)
+					v.list = append(v.list, b)
+					return err
+				})
+			}
+		})
+	}))
+	if err != nil {
+		t.Fatalf("Failed to decode: %v", err)
+	}
+	if !v.b || v.i != 7 || v.r != 15 || v.bits.BitLength != 4 || v.bits.Bytes[0] != 0xa0 || !v.oid.Equal(asn1.ObjectIdentifier{1, 2, 840}) || !reflect.DeepEqual(v.data, []byte{0x0a, 0xbc}) || !reflect.DeepEqual(v.list, []bool{true, false}) {
+		t.Errorf("Unexpected decoded value %+v", v)
+	}
+}
+
+func TestEncoderErrors(t *testing.T) {
+	testCases := []struct {
+		name  string
+		write func(e *Encoder) error
+	}{
+		{
+			name:  "unknown enumeration value",
+			write: func(e *Encoder) error { return e.WriteEnumerated(3, Enumeration{{Name: "a", Value: 0}}) },
+		},
+		{
+			name:  "bit string shorter than its length",
+			write: func(e *Encoder) error { return e.WriteBitString(asn1.BitString{Bytes: []byte{0}, BitLength: 9}) },
+		},
+		{
+			name:  "invalid object identifier",
+			write: func(e *Encoder) error { return e.WriteObjectIdentifier(asn1.ObjectIdentifier{1}) },
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var e Encoder
+			if err := tc.write(&e); err == nil {
+				t.Errorf("Expected error, got encoding %s", e.Bytes())
+			}
+		})
+	}
+}
+
+func TestDecoderErrors(t *testing.T) {
+	testCases := []struct {
+		name string
+		data string
+		read func(d *Decoder) error
+	}{
+		{
+			name: "fraction in integer",
+			data: `<v>1.5</v>`,
+			read: func(d *Decoder) error { var i int64; return d.ReadInteger(&i) },
+		},
+		{
+			name: "plus sign in integer",
+			data: `<v>+1</v>`,
+			read: func(d *Decoder) error { var i int64; return d.ReadInteger(&i) },
+		},
+		{
+			name: "integer out of 64 bits",
+			data: `<v>9223372036854775808</v>`,
+			read: func(d *Decoder) error { var i int64; return d.ReadInteger(&i) },
+		},
+		{
+			name: "unknown boolean value",
+			data: `<v><yes/></v>`,
+			read: func(d *Decoder) error { var b bool; return d.ReadBoolean(&b) },
+		},
+		{
+			name: "hexadecimal real",
+			data: `<v>0x1p-2</v>`,
+			read: func(d *Decoder) error { var f float64; return d.ReadReal(&f) },
+		},
+		{
+			name: "invalid bit",
+			data: `<v>0120</v>`,
+			read: func(d *Decoder) error { var v asn1.BitString; return d.ReadBitString(&v) },
+		},
+		{
+			name: "invalid hexadecimal string",
+			data: `<v>ABC</v>`,
+			read: func(d *Decoder) error { var b []byte; return d.ReadOctetString(&b) },
+		},
+		{
+			name: "unknown element in string",
+			data: `<v>a<b/></v>`,
+			read: func(d *Decoder) error { var s string; return d.ReadString(&s) },
+		},
+		{
+			name: "content of null",
+			data: `<v>0</v>`,
+			read: func(d *Decoder) error { return d.ReadNull() },
+		},
+		{
+			name: "invalid time",
+			data: `<v>2024-01-02</v>`,
+			read: func(d *Decoder) error { var v time.Time; return d.ReadGeneralizedTime(&v) },
+		},
+		{
+			name: "character data between elements",
+			data: `<v><a/>b</v>`,
+			read: func(d *Decoder) error { return d.ReadElements(func(string, *Decoder) error { return nil }) },
+		},
+		{
+			name: "choice without elements",
+			data: `<v></v>`,
+			read: func(d *Decoder) error { return d.ReadChoice(func(string, *Decoder) error { return nil }) },
+		},
+		{
+			name: "unexpected root element",
+			data: `<w/>`,
+			read: func(d *Decoder) error { return nil },
+		},
+		{
+			name: "element after the value",
+			data: `<v/><v/>`,
+			read: func(d *Decoder) error { return nil },
+		},
+		{
+			name: "malformed document",
+			data: `<v><a></v>`,
+			read: func(d *Decoder) error { return nil },
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := Unmarshal([]byte(tc.data), "v", UnmarshalerFunc(tc.read)); err == nil {
+				t.Errorf("Expected error decoding %s", tc.data)
+			}
+		})
+	}
+}
@@ -16,60 +16,74 @@ type yySymType struct {
 	yys        int
 	name       string
 	numberRepr string
-
-	Number                            Number
-	Real                              Real
-	TagDefault                        int
-	ExtensionDefault                  bool
-	ModuleIdentifier                  ModuleIdentifier
-	DefinitiveObjIdComponent          DefinitiveObjIdComponent
-	DefinitiveObjIdComponentList      []DefinitiveObjIdComponent
-	DefinitiveIdentifier              DefinitiveIdentifier
-	Type                              Type
-	ObjectIdElement                   ObjectIdElement
-	DefinedValue                      DefinedValue
-	ObjectIdentifierValue             ObjectIdentifierValue
-	Value                             Value
-	Assignment                        Assignment
-	AssignmentList                    AssignmentList
-	ModuleBody                        ModuleBody
-	ValueReference                    ValueReference
-	TypeReference                     TypeReference
-	Constraint                        Constraint
-	ConstraintSpec                    ConstraintSpec
-	ElementSetSpec                    ElementSetSpec
-	Unions                            Unions
-	Intersections                     Intersections
-	IntersectionElements              IntersectionElements
-	Exclusions                        Exclusions
-	Elements                          Elements
-	SubtypeConstraint                 SubtypeConstraint
-	RangeEndpoint                     RangeEndpoint
-	NamedType                         NamedType
-	ComponentType                     ComponentType
-	ComponentTypeLists                ComponentTypeLists
-	ComponentTypeList                 ComponentTypeList
-	SequenceType                      SequenceType
-	Tag                               Tag
-	Class                             int
-	SequenceOfType                    SequenceOfType
-	NamedBitList                      []NamedBit
-	NamedBit                          NamedBit
-	Imports                           []SymbolsFromModule
-	SymbolsFromModule                 SymbolsFromModule
-	SymbolList                        []Symbol
-	Symbol                            Symbol
-	GlobalModuleReference             GlobalModuleReference
-	AlternativeTypeList               []NamedType
-	ChoiceType                        ChoiceType
-	ExtensionAdditionAlternative      ChoiceExtension
-	ExtensionAdditionAlternativesList []ChoiceExtension
-	ExtensionAdditions                []ExtensionAddition
-	NamedNumberList                   []NamedNumber
-	NamedNumber                       NamedNumber
-	EnumeratedType                    EnumeratedType
-	Enumeration                       []EnumerationItem
-	EnumerationItem                   EnumerationItem
+	bstring    string
+	hstring    string
+	cstring    string
+
+	Number                             Number
+	BigNumber                          BigNumber
+	Real                               Real
+	TagDefault                         int
+	ExtensionDefault                   bool
+	ModuleIdentifier                   ModuleIdentifier
+	DefinitiveObjIdComponent           DefinitiveObjIdComponent
+	DefinitiveObjIdComponentList       []DefinitiveObjIdComponent
+	DefinitiveIdentifier               DefinitiveIdentifier
+	Type                               Type
+	ObjectIdElement                    ObjectIdElement
+	DefinedValue                       DefinedValue
+	ObjectIdentifierValue              ObjectIdentifierValue
+	Value                              Value
+	Assignment                         Assignment
+	AssignmentList                     AssignmentList
+	ModuleBody                         ModuleBody
+	ValueReference                     ValueReference
+	TypeReference                      TypeReference
+	Constraint                         Constraint
+	ConstraintSpec                     ConstraintSpec
+	ElementSetSpec                     ElementSetSpec
+	Unions                             Unions
+	Intersections                      Intersections
+	IntersectionElements               IntersectionElements
+	Exclusions                         Exclusions
+	Elements                           Elements
+	InnerTypeConstraint                InnerTypeConstraint
+	NamedConstraint                    NamedConstraint
+	NamedConstraints                   []NamedConstraint
+	ValueConstraint                    *Constraint
+	Presence                           int
+	SubtypeConstraint                  SubtypeConstraint
+	RangeEndpoint                      RangeEndpoint
+	NamedType                          NamedType
+	ComponentType                      ComponentType
+	ComponentTypeLists                 ComponentTypeLists
+	ComponentTypeList                  ComponentTypeList
+	SequenceType                       SequenceType
+	Tag                                Tag
+	Class                              int
+	SequenceOfType                     SequenceOfType
+	NamedBitList                       []NamedBit
+	NamedBit                           NamedBit
+	Imports                            []SymbolsFromModule
+	SymbolsFromModule                  SymbolsFromModule
+	SymbolList                         []Symbol
+	Symbol                             Symbol
+	GlobalModuleReference              GlobalModuleReference
+	AlternativeTypeList                []NamedType
+	ChoiceType                         ChoiceType
+	ExtensionAdditionAlternative       ChoiceExtension
+	ExtensionAdditionAlternativesList  []ChoiceExtension
+	ExtensionAdditions                 []ExtensionAddition
+	ExtensionAdditionGroup             ExtensionAdditionGroup
+	ExtensionAdditionAlternativesGroup ExtensionAdditionAlternativesGroup
+	NamedNumberList                    []NamedNumber
+	NamedNumber                        NamedNumber
+	EnumeratedType                     EnumeratedType
+	Enumeration                        []EnumerationItem
+	EnumerationItem                    EnumerationItem
+	BracedValue                        BracedValue
+	BracedComponent                    BracedComponent
+	XMLValue                           XMLValue
 }
 
 const WHITESPACE = 57346
@@ -77,126 +91,120 @@ const NEWLINE = 57347
 const TYPEORMODULEREFERENCE = 57348
 const VALUEIDENTIFIER = 57349
 const NUMBER = 57350
-const BSTRING = 57351
-const XMLBSTRING = 57352
+const BIGNUMBER = 57351
+const BSTRING = 57352
 const HSTRING = 57353
-const XMLHSTRING = 57354
-const CSTRING = 57355
-const XMLCSTRING = 57356
-const ASSIGNMENT = 57357
-const RANGE_SEPARATOR = 57358
-const ELLIPSIS = 57359
-const LEFT_VERSION_BRACKETS = 57360
-const RIGHT_VERSION_BRACKETS = 57361
-const XML_END_TAG_START = 57362
-const XML_SINGLE_START_END = 57363
-const XML_BOOLEAN_TRUE = 57364
-const XML_BOOLEAN_FALSE = 57365
-const XMLASN1TYPENAME = 57366
-const EXPONENT = 57367
-const OPEN_CURLY = 57368
-const CLOSE_CURLY = 57369
-const OPEN_ROUND = 57370
-const CLOSE_ROUND = 57371
-const OPEN_SQUARE = 57372
-const CLOSE_SQUARE = 57373
-const LESS = 57374
-const GREATER = 57375
-const COMMA = 57376
-const DOT = 57377
-const MINUS = 57378
-const COLON = 57379
-const EQUALS = 57380
-const QUOTATION_MARK = 57381
-const APOSTROPHE = 57382
-const SPACE = 57383
-const SEMICOLON = 57384
-const AT = 57385
-const PIPE = 57386
-const EXCLAMATION = 57387
-const CARET = 57388
-const ABSENT = 57389
-const ABSTRACT_SYNTAX = 57390
-const ALL = 57391
-const APPLICATION = 57392
-const AUTOMATIC = 57393
-const BEGIN = 57394
-const BIT = 57395
-const BMPString = 57396
-const BOOLEAN = 57397
-const BY = 57398
-const CHARACTER = 57399
-const CHOICE = 57400
-const CLASS = 57401
-const COMPONENT = 57402
-const COMPONENTS = 57403
-const CONSTRAINED = 57404
-const CONTAINING = 57405
-const DEFAULT = 57406
-const DEFINITIONS = 57407
-const EMBEDDED = 57408
-const ENCODED = 57409
-const END = 57410
-const ENUMERATED = 57411
-const EXCEPT = 57412
-const EXPLICIT = 57413
-const EXPORTS = 57414
-const EXTENSIBILITY = 57415
-const EXTERNAL = 57416
-const FALSE = 57417
-const FROM = 57418
-const GeneralString = 57419
-const GeneralizedTime = 57420
-const GraphicString = 57421
-const IA5String = 57422
-const IDENTIFIER = 57423
-const IMPLICIT = 57424
-const IMPLIED = 57425
-const IMPORTS = 57426
-const INCLUDES = 57427
-const INSTANCE = 57428
-const INTEGER = 57429
-const INTERSECTION = 57430
-const ISO646String = 57431
-const MAX = 57432
-const MIN = 57433
-const MINUS_INFINITY = 57434
-const NULL = 57435
-const NumericString = 57436
-const OBJECT = 57437
-const OCTET = 57438
-const OF = 57439
-const OPTIONAL = 57440
-const ObjectDescriptor = 57441
-const PATTERN = 57442
-const PDV = 57443
-const PLUS_INFINITY = 57444
-const PRESENT = 57445
-const PRIVATE = 57446
-const PrintableString = 57447
-const REAL = 57448
-const RELATIVE_OID = 57449
-const SEQUENCE = 57450
-const SET = 57451
-const SIZE = 57452
-const STRING = 57453
-const SYNTAX = 57454
-const T61String = 57455
-const TAGS = 57456
-const TRUE = 57457
-const TYPE_IDENTIFIER = 57458
-const TeletexString = 57459
-const UNION = 57460
-const UNIQUE = 57461
-const UNIVERSAL = 57462
-const UTCTime = 57463
-const UTF8String = 57464
-const UniversalString = 57465
-const VideotexString = 57466
-const VisibleString = 57467
-const WITH = 57468
-const ANY = 57469
-const DEFINED = 57470
+const CSTRING = 57354
+const ASSIGNMENT = 57355
+const RANGE_SEPARATOR = 57356
+const ELLIPSIS = 57357
+const LEFT_VERSION_BRACKETS = 57358
+const RIGHT_VERSION_BRACKETS = 57359
+const XML_TYPED_VALUE = 57360
+const EXPONENT = 57361
+const OPEN_CURLY = 57362
+const CLOSE_CURLY = 57363
+const OPEN_ROUND = 57364
+const CLOSE_ROUND = 57365
+const OPEN_SQUARE = 57366
+const CLOSE_SQUARE = 57367
+const LESS = 57368
+const GREATER = 57369
+const COMMA = 57370
+const DOT = 57371
+const MINUS = 57372
+const COLON = 57373
+const EQUALS = 57374
+const QUOTATION_MARK = 57375
+const APOSTROPHE = 57376
+const SPACE = 57377
+const SEMICOLON = 57378
+const AT = 57379
+const PIPE = 57380
+const EXCLAMATION = 57381
+const CARET = 57382
+const ABSENT = 57383
+const ABSTRACT_SYNTAX = 57384
+const ALL = 57385
+const APPLICATION = 57386
+const AUTOMATIC = 57387
+const BEGIN = 57388
+const BIT = 57389
+const BMPString = 57390
+const BOOLEAN = 57391
+const BY = 57392
+const CHARACTER = 57393
+const CHOICE = 57394
+const CLASS = 57395
+const COMPONENT = 57396
+const COMPONENTS = 57397
+const CONSTRAINED = 57398
+const CONTAINING = 57399
+const DEFAULT = 57400
+const DEFINITIONS = 57401
+const EMBEDDED = 57402
+const ENCODED = 57403
+const END = 57404
+const ENUMERATED = 57405
+const EXCEPT = 57406
+const EXPLICIT = 57407
+const EXPORTS = 57408
+const EXTENSIBILITY = 57409
+const EXTERNAL = 57410
+const FALSE = 57411
+const FROM = 57412
+const GeneralString = 57413
+const GeneralizedTime = 57414
+const GraphicString = 57415
+const IA5String = 57416
+const IDENTIFIER = 57417
+const IMPLICIT = 57418
+const IMPLIED = 57419
+const IMPORTS = 57420
+const INCLUDES = 57421
+const INSTANCE = 57422
+const INTEGER = 57423
+const INTERSECTION = 57424
+const ISO646String = 57425
+const MAX = 57426
+const MIN = 57427
+const MINUS_INFINITY = 57428
+const NULL = 57429
+const NumericString = 57430
+const OBJECT = 57431
+const OCTET = 57432
+const OF = 57433
+const OPTIONAL = 57434
+const ObjectDescriptor = 57435
+const PATTERN = 57436
+const PDV = 57437
+const PLUS_INFINITY = 57438
+const PRESENT = 57439
+const PRIVATE = 57440
+const PrintableString = 57441
+const REAL = 57442
+const RELATIVE_OID = 57443
+const SEQUENCE = 57444
+const SET = 57445
+const SIZE = 57446
+const STRING = 57447
+const SYNTAX = 57448
+const T61String = 57449
+const TAGS = 57450
+const TRUE = 57451
+const TYPE_IDENTIFIER = 57452
+const TeletexString = 57453
+const UNION = 57454
+const UNIQUE = 57455
+const UNIVERSAL = 57456
+const UTCTime = 57457
+const UTF8String = 57458
+const UniversalString = 57459
+const VideotexString = 57460
+const VisibleString = 57461
+const WITH = 57462
+const ANY = 57463
+const DEFINED = 57464
 
 var yyToknames = [...]string{
 	"$end",
@@ -207,22 +215,16 @@ var yyToknames = [...]string{
 	"TYPEORMODULEREFERENCE",
 	"VALUEIDENTIFIER",
 	"NUMBER",
+	"BIGNUMBER",
 	"BSTRING",
-	"XMLBSTRING",
 	"HSTRING",
-	"XMLHSTRING",
 	"CSTRING",
-	"XMLCSTRING",
 	"ASSIGNMENT",
 	"RANGE_SEPARATOR",
 	"ELLIPSIS",
 	"LEFT_VERSION_BRACKETS",
 	"RIGHT_VERSION_BRACKETS",
-	"XML_END_TAG_START",
-	"XML_SINGLE_START_END",
-	"XML_BOOLEAN_TRUE",
-	"XML_BOOLEAN_FALSE",
-	"XMLASN1TYPENAME",
+	"XML_TYPED_VALUE",
 	"EXPONENT",
 	"OPEN_CURLY",
 	"CLOSE_CURLY",
@@ -335,375 +337,436 @@ const yyEofCode = 1
 const yyErrCode = 2
 const yyInitialStackSize = 16
 
-//line asn1.y:1073
+//line asn1.y:1219
 
 //line yacctab:1
 var yyExca = [...]int16{
 	-1, 1,
 	1, -1,
 	-2, 0,
-	-1, 31,
-	68, 24,
-	-2, 27,
-	-1, 47,
-	35, 3,
-	-2, 2,
-	-1, 178,
-	44, 239,
-	118, 239,
-	-2, 235,
-	-1, 180,
-	46, 242,
-	88, 242,
-	-2, 237,
-	-1, 184,
-	70, 245,
-	-2, 243,
-	-1, 193,
-	16, 264,
-	32, 264,
-	-2, 258,
-	-1, 252,
-	28, 6,
+	-1, 33,
+	62, 26,
+	-2, 29,
+	-1, 49,
+	29, 5,
 	-2, 4,
-	-1, 329,
-	46, 242,
-	88, 242,
-	-2, 238,
-	-1, 421,
-	34, 138,
-	-2, 132,
+	-1, 199,
+	38, 269,
+	112, 269,
+	-2, 265,
+	-1, 201,
+	40, 272,
+	82, 272,
+	-2, 267,
+	-1, 205,
+	64, 275,
+	-2, 273,
+	-1, 217,
+	14, 298,
+	26, 298,
+	-2, 291,
+	-1, 350,
+	22, 8,
+	-2, 6,
+	-1, 374,
+	40, 272,
+	82, 272,
+	-2, 268,
 }
 
 const yyPrivate = 57344
 
-const yyLast = 920
+const yyLast = 1339
 
 var yyAct = [...]int16{
-	219, 205, 408, 218, 374, 208, 350, 220, 127, 17,
-	193, 177, 259, 317, 313, 17, 281, 277, 238, 291,
-	333, 242, 250, 210, 202, 262, 163, 184, 182, 180,
-	246, 159, 170, 217, 241, 3, 144, 19, 168, 265,
-	151, 24, 23, 22, 148, 134, 19, 168, 129, 296,
-	416, 297, 140, 233, 128, 232, 165, 46, 225, 54,
-	46, 224, 334, 38, 29, 165, 169, 19, 168, 268,
-	45, 54, 133, 45, 21, 169, 190, 62, 35, 46,
-	263, 36, 5, 295, 46, 19, 165, 11, 135, 234,
-	128, 260, 45, 19, 152, 204, 169, 45, 42, 31,
-	121, 417, 429, 19, 411, 162, 415, 9, 137, 142,
-	150, 269, 58, 266, 162, 61, 195, 60, 10, 123,
-	336, 430, 172, 141, 273, 274, 310, 318, 164, 336,
-	164, 172, 171, 211, 214, 162, 139, 155, 203, 221,
-	50, 171, 422, 19, 223, 161, 103, 221, 226, 231,
-	47, 48, 172, 204, 161, 319, 213, 221, 154, 136,
-	216, 124, 171, 227, 239, 228, 343, 253, 19, 245,
-	122, 65, 139, 344, 229, 161, 138, 143, 204, 59,
-	247, 125, 44, 128, 53, 44, 244, 289, 215, 164,
-	63, 420, 370, 40, 290, 431, 53, 221, 256, 271,
-	406, 278, 258, 403, 44, 4, 48, 325, 255, 44,
-	430, 270, 292, 395, 394, 41, 387, 283, 382, 145,
-	282, 372, 221, 294, 287, 286, 280, 261, 62, 275,
-	400, 309, 299, 301, 399, 326, 308, 388, 385, 384,
-	305, 307, 331, 320, 126, 32, 128, 345, 302, 253,
-	253, 315, 288, 222, 27, 425, 15, 128, 230, 393,
-	4, 48, 365, 235, 236, 164, 164, 363, 357, 164,
-	311, 303, 293, 285, 164, 323, 279, 25, 312, 314,
-	165, 342, 338, 335, 30, 199, 132, 322, 347, 211,
-	351, 328, 214, 353, 329, 360, 330, 131, 164, 130,
-	7, 348, 368, 355, 421, 346, 19, 361, 19, 358,
-	352, 327, 272, 64, 356, 354, 375, 28, 204, 359,
-	249, 12, 284, 4, 48, 240, 19, 18, 14, 424,
-	367, 371, 364, 369, 14, 26, 164, 316, 366, 257,
-	18, 298, 300, 19, 377, 335, 278, 47, 48, 304,
-	306, 4, 48, 254, 48, 373, 4, 252, 254, 4,
-	380, 321, 378, 381, 379, 383, 55, 48, 414, 397,
-	396, 376, 164, 341, 164, 340, 339, 324, 337, 267,
-	264, 390, 389, 423, 392, 410, 398, 405, 409, 402,
-	351, 55, 19, 168, 39, 34, 377, 377, 1, 412,
-	413, 401, 209, 418, 207, 211, 404, 206, 212, 419,
-	407, 165, 386, 187, 362, 120, 349, 201, 200, 72,
-	153, 169, 243, 409, 426, 427, 43, 428, 57, 56,
-	37, 360, 276, 69, 179, 77, 86, 149, 88, 106,
-	89, 237, 119, 92, 102, 84, 82, 81, 80, 79,
-	67, 85, 91, 90, 93, 71, 197, 332, 194, 192,
-	162, 191, 107, 104, 108, 109, 186, 189, 188, 185,
-	183, 181, 94, 178, 110, 391, 198, 172, 95, 111,
-	96, 97, 176, 175, 174, 173, 87, 171, 68, 33,
-	112, 98, 49, 99, 100, 139, 51, 52, 114, 167,
-	161, 166, 113, 55, 19, 168, 105, 116, 115, 117,
-	118, 196, 101, 157, 160, 78, 158, 156, 248, 251,
-	83, 73, 75, 165, 66, 187, 70, 120, 74, 76,
-	6, 16, 13, 169, 2, 8, 20, 0, 0, 0,
+	234, 247, 455, 248, 246, 237, 236, 230, 422, 131,
+	19, 393, 198, 360, 163, 284, 19, 316, 283, 289,
+	312, 266, 378, 348, 282, 344, 270, 217, 285, 238,
+	324, 161, 172, 4, 4, 231, 203, 294, 201, 149,
+	187, 156, 245, 179, 205, 26, 25, 410, 24, 297,
+	153, 213, 139, 134, 464, 330, 329, 145, 140, 132,
+	132, 261, 260, 253, 48, 252, 57, 48, 40, 13,
+	47, 31, 23, 47, 138, 37, 295, 44, 57, 38,
+	7, 420, 300, 65, 21, 293, 21, 48, 132, 11,
+	328, 61, 48, 47, 233, 157, 52, 262, 47, 33,
+	12, 21, 307, 308, 290, 465, 125, 49, 50, 233,
+	463, 155, 64, 142, 147, 255, 5, 21, 186, 180,
+	173, 174, 185, 298, 301, 127, 63, 371, 146, 141,
+	184, 178, 249, 178, 249, 467, 379, 239, 242, 232,
+	181, 144, 144, 128, 42, 251, 160, 225, 66, 249,
+	259, 62, 126, 21, 254, 143, 148, 132, 361, 107,
+	159, 430, 473, 276, 224, 17, 417, 241, 288, 5,
+	21, 186, 180, 173, 174, 185, 244, 358, 287, 176,
+	362, 256, 343, 184, 280, 286, 276, 269, 257, 43,
+	21, 275, 269, 181, 381, 32, 189, 46, 233, 56,
+	46, 249, 267, 388, 243, 482, 188, 273, 480, 178,
+	389, 56, 5, 50, 368, 475, 480, 477, 469, 175,
+	46, 303, 279, 225, 178, 46, 476, 459, 355, 313,
+	306, 457, 176, 394, 322, 356, 369, 448, 249, 309,
+	224, 323, 447, 419, 302, 325, 327, 320, 315, 189,
+	171, 291, 65, 317, 310, 342, 332, 334, 453, 188,
+	452, 440, 437, 341, 338, 340, 436, 376, 363, 130,
+	34, 132, 175, 358, 390, 351, 321, 178, 132, 5,
+	50, 29, 474, 286, 446, 439, 408, 335, 406, 400,
+	336, 326, 354, 274, 178, 319, 178, 178, 314, 27,
+	178, 357, 353, 349, 387, 228, 178, 269, 137, 372,
+	225, 225, 136, 135, 225, 9, 415, 383, 221, 392,
+	225, 239, 345, 269, 242, 133, 396, 224, 224, 403,
+	178, 224, 347, 380, 365, 374, 375, 224, 366, 14,
+	373, 21, 16, 401, 21, 404, 351, 351, 16, 430,
+	434, 391, 423, 399, 269, 28, 21, 286, 21, 370,
+	305, 67, 21, 402, 395, 397, 233, 30, 456, 398,
+	407, 409, 411, 418, 349, 349, 68, 416, 269, 269,
+	178, 412, 413, 5, 50, 268, 129, 359, 425, 20,
+	313, 269, 277, 278, 225, 5, 50, 352, 433, 5,
+	350, 352, 421, 21, 20, 50, 414, 380, 5, 429,
+	426, 224, 427, 286, 364, 435, 49, 50, 178, 299,
+	178, 178, 2, 269, 6, 150, 58, 50, 296, 441,
+	41, 357, 444, 442, 225, 451, 445, 36, 428, 1,
+	281, 454, 170, 162, 167, 177, 166, 164, 425, 425,
+	458, 224, 235, 240, 432, 472, 460, 461, 239, 232,
+	250, 471, 470, 466, 468, 258, 438, 431, 229, 76,
+	263, 264, 158, 271, 478, 45, 479, 60, 239, 59,
+	392, 39, 403, 396, 481, 311, 5, 21, 186, 180,
+	173, 174, 185, 73, 81, 90, 154, 265, 106, 88,
+	184, 86, 85, 84, 83, 71, 89, 95, 94, 75,
+	181, 226, 377, 219, 462, 292, 450, 449, 49, 21,
+	186, 180, 173, 174, 185, 424, 386, 385, 384, 215,
+	216, 212, 184, 210, 208, 214, 124, 304, 207, 211,
+	209, 206, 181, 204, 202, 199, 443, 195, 193, 176,
+	191, 194, 192, 318, 190, 200, 382, 91, 72, 92,
+	110, 93, 35, 123, 96, 51, 189, 171, 53, 196,
+	55, 331, 333, 197, 54, 97, 188, 183, 182, 337,
+	339, 176, 220, 111, 108, 112, 113, 165, 169, 175,
+	82, 218, 168, 98, 272, 114, 346, 227, 189, 99,
+	115, 100, 101, 87, 77, 79, 223, 70, 188, 367,
+	74, 116, 102, 78, 103, 104, 144, 80, 8, 118,
+	18, 175, 15, 117, 3, 10, 22, 109, 120, 119,
+	121, 122, 222, 105, 49, 21, 186, 180, 173, 174,
+	185, 0, 0, 0, 0, 0, 0, 0, 184, 405,
+	208, 0, 124, 0, 0, 0, 0, 0, 181, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 200, 0, 0, 0, 92, 110, 93, 0, 123,
+	96, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 97, 0, 0, 0, 0, 0, 176, 220, 111,
+	108, 112, 113, 0, 0, 0, 0, 218, 0, 98,
+	0, 114, 0, 227, 189, 99, 115, 100, 101, 0,
+	0, 0, 223, 0, 188, 0, 0, 116, 102, 0,
+	103, 104, 144, 0, 0, 118, 0, 175, 0, 117,
+	0, 0, 0, 109, 120, 119, 121, 122, 222, 105,
+	49, 21, 186, 180, 173, 174, 185, 0, 0, 0,
+	0, 0, 0, 0, 184, 0, 208, 0, 124, 0,
+	0, 0, 0, 0, 181, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 92, 110, 93, 0, 123, 96, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 97, 0, 0,
+	0, 0, 0, 176, 220, 111, 108, 112, 113, 0,
+	0, 0, 0, 218, 0, 98, 0, 114, 0, 227,
+	189, 99, 115, 100, 101, 0, 0, 0, 223, 0,
+	188, 0, 0, 116, 102, 0, 103, 104, 144, 0,
+	0, 118, 0, 175, 0, 117, 49, 50, 368, 109,
+	120, 119, 121, 122, 222, 105, 0, 0, 0, 0,
+	0, 0, 0, 0, 124, 0, 0, 0, 0, 0,
+	369, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 92, 110, 93,
+	0, 123, 96, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 97, 0, 0, 0, 58, 0, 0,
+	0, 111, 108, 112, 113, 0, 0, 0, 0, 0,
+	0, 98, 0, 114, 0, 124, 0, 99, 115, 100,
+	101, 0, 0, 0, 0, 0, 0, 0, 0, 116,
+	102, 0, 103, 104, 0, 0, 0, 118, 92, 110,
+	93, 117, 123, 96, 0, 109, 120, 119, 121, 122,
+	0, 105, 0, 0, 97, 0, 152, 0, 58, 21,
+	0, 0, 111, 108, 112, 113, 0, 151, 0, 0,
+	0, 0, 98, 0, 114, 0, 124, 0, 99, 115,
+	100, 101, 0, 0, 0, 0, 0, 0, 0, 0,
+	116, 102, 0, 103, 104, 0, 0, 0, 118, 92,
+	110, 93, 117, 123, 96, 0, 109, 120, 119, 121,
+	122, 0, 105, 0, 0, 97, 0, 0, 0, 0,
+	0, 0, 0, 111, 108, 112, 113, 0, 0, 0,
+	0, 0, 0, 98, 0, 114, 0, 0, 58, 99,
+	115, 100, 101, 0, 0, 69, 0, 0, 0, 0,
+	0, 116, 102, 0, 103, 104, 124, 0, 0, 118,
+	0, 0, 0, 117, 0, 0, 0, 109, 120, 119,
+	121, 122, 0, 105, 0, 0, 0, 0, 0, 92,
+	110, 93, 0, 123, 96, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 97, 0, 0, 0, 58,
+	0, 0, 0, 111, 108, 112, 113, 0, 0, 0,
+	0, 0, 0, 98, 0, 114, 0, 124, 0, 99,
+	115, 100, 101, 0, 0, 0, 0, 0, 0, 0,
+	0, 116, 102, 0, 103, 104, 0, 0, 0, 118,
+	92, 110, 93, 117, 123, 96, 0, 109, 120, 119,
+	121, 122, 0, 105, 0, 0, 97, 0, 0, 0,
+	0, 0, 0, 0, 111, 108, 112, 113, 0, 0,
+	0, 0, 0, 0, 98, 0, 114, 0, 0, 0,
+	99, 115, 100, 101, 0, 0, 0, 0, 0, 0,
+	0, 0, 116, 102, 0, 103, 104, 0, 0, 0,
+	118, 0, 0, 0, 117, 0, 0, 0, 109, 120,
+	119, 121, 122, 0, 105, 5, 21, 186, 180, 173,
+	174, 185, 0, 0, 0, 0, 0, 0, 0, 184,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 181,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	88, 106, 89, 0, 119, 92, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 93, 0, 0, 0,
-	0, 0, 162, 0, 107, 104, 108, 109, 0, 0,
-	0, 0, 0, 0, 94, 0, 110, 0, 198, 172,
-	95, 111, 96, 97, 0, 0, 0, 0, 0, 171,
-	0, 0, 112, 98, 0, 99, 100, 139, 0, 0,
-	114, 0, 161, 0, 113, 47, 48, 325, 105, 116,
-	115, 117, 118, 196, 101, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 0, 120,
-	0, 0, 0, 0, 0, 326, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 88, 106, 89, 0, 119, 92, 0, 0,
-	0, 0, 0, 0, 0, 0, 55, 0, 93, 0,
-	0, 0, 0, 0, 0, 0, 107, 104, 108, 109,
-	0, 0, 0, 0, 0, 0, 94, 0, 110, 0,
-	120, 0, 95, 111, 96, 97, 0, 0, 0, 0,
-	0, 0, 0, 0, 112, 98, 0, 99, 100, 0,
-	0, 0, 114, 88, 106, 89, 113, 119, 92, 0,
-	105, 116, 115, 117, 118, 0, 101, 55, 19, 93,
-	0, 147, 0, 0, 0, 0, 0, 107, 104, 108,
-	109, 0, 146, 0, 0, 0, 0, 94, 0, 110,
-	0, 120, 0, 95, 111, 96, 97, 0, 0, 0,
-	0, 0, 0, 0, 0, 112, 98, 0, 99, 100,
-	0, 0, 0, 114, 88, 106, 89, 113, 119, 92,
-	0, 105, 116, 115, 117, 118, 0, 101, 55, 0,
-	93, 0, 0, 0, 0, 0, 0, 0, 107, 104,
-	108, 109, 0, 0, 0, 0, 0, 0, 94, 0,
-	110, 0, 120, 0, 95, 111, 96, 97, 0, 0,
-	0, 0, 0, 0, 0, 0, 112, 98, 0, 99,
-	100, 0, 0, 0, 114, 88, 106, 89, 113, 119,
-	92, 0, 105, 116, 115, 117, 118, 0, 101, 0,
-	0, 93, 0, 0, 0, 0, 0, 0, 0, 107,
-	104, 108, 109, 0, 0, 0, 0, 0, 0, 94,
-	0, 110, 0, 0, 0, 95, 111, 96, 97, 0,
-	0, 0, 0, 0, 0, 0, 0, 112, 98, 0,
-	99, 100, 0, 0, 0, 114, 0, 0, 0, 113,
-	0, 0, 0, 105, 116, 115, 117, 118, 0, 101,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 176, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 381, 0, 189, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 188, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 175,
 }
 
 var yyPact = [...]int16{
-	353, -1000, 17, 274, -1000, 36, -1000, 319, 1, -71,
-	-72, -73, 250, 319, -1000, -1000, -1000, 226, -1000, -1000,
-	302, -19, -1000, -1000, -1000, -1000, -1000, 332, 47, -1000,
-	216, 6, -1000, 13, -21, 144, -1000, 360, 341, 75,
-	73, 194, -1000, -1000, -1000, -1000, -1000, -1000, -1000, 360,
-	-1000, -1000, -1000, 298, 792, -1000, 58, 341, -1000, 43,
-	-1000, -1000, 341, -1000, 792, 229, -1000, -1000, -1000, -1000,
+	402, 402, -1000, 21, 295, -1000, -1000, 24, -1000, 396,
+	5, -60, -62, -63, 278, 396, -1000, -1000, -1000, 259,
+	-1000, -1000, 354, -6, -1000, -1000, -1000, -1000, -1000, 381,
+	53, -1000, 247, 9, -1000, 17, -10, 101, -1000, 420,
+	410, 90, 76, 224, -1000, -1000, -1000, -1000, -1000, -1000,
+	-1000, 420, -1000, -1000, -1000, -1000, 348, 1052, -1000, 70,
+	410, -1000, 55, -1000, -1000, 410, -1000, 1113, 256, 307,
+	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
+	-1000, -1000, -52, -1000, -1000, -1000, 293, 292, 288, -1000,
+	-1, -53, -1000, 38, 37, -83, 911, -1000, -1000, -1000,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -63, -1000,
-	-1000, -1000, 273, 271, 260, -1000, -9, -66, -1000, 62,
-	26, -92, 670, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -67,
-	-10, -1000, -1000, 353, -1000, 218, 60, -1000, 385, 259,
-	301, 336, 336, -1000, -1000, 161, 731, -36, -39, 218,
-	136, 731, -42, -44, 33, 218, 792, 792, -1000, 317,
-	-1000, -1000, -1000, -1000, 254, -1000, -1000, -1000, -1000, -1000,
-	-1000, -1000, -1000, -1000, -1000, 350, -1000, -1000, 173, 331,
-	-1000, -1000, -1000, 46, -1000, -1000, 193, -1000, -1000, 10,
-	-1000, -5, -1000, 23, -1000, 10, -1000, 385, -1000, -1000,
-	-1000, -1000, -1000, -1000, 296, 218, 64, 197, -1000, 336,
-	249, 192, 186, -1000, 46, 792, 246, 191, -1000, 190,
-	-1000, 224, 160, -1000, 224, -1000, 178, 245, 189, -1000,
-	-15, -46, 218, -1000, 731, 731, -1000, -1000, 178, 244,
-	218, -1000, 731, 731, 336, 218, 218, 200, -1000, -1000,
-	-1000, 91, -1000, -1000, -1000, -1000, 243, 350, 350, -1000,
-	-1000, -1000, -1000, 223, -1000, 329, 119, 173, -1000, 214,
-	609, 294, -1000, 497, 497, -1000, -1000, 497, -1000, -1000,
-	-1000, 213, 30, 218, 255, -1000, 139, -1000, 219, -1000,
-	301, 178, 336, -1000, 218, -1000, 293, 336, 199, -1000,
-	336, 241, 292, -1000, 78, -1000, 60, 792, 218, -1000,
-	218, -1000, 240, -1000, 218, -1000, 218, -1000, -1000, -1000,
-	347, -1000, 235, -1000, -1000, 345, 277, -1000, -1000, 325,
-	-1000, -1000, -1000, -1000, 155, -1000, 323, 187, -1000, -1000,
-	-1000, -1000, -1000, -1000, 39, -1000, -1000, -1000, -1000, -1000,
-	-1000, -1000, 299, -1000, 336, 317, 186, -1000, -1000, 184,
-	-1000, -1000, 46, -1000, 210, 209, -1000, -1000, -1000, 182,
-	-1000, -1000, 218, -1000, -1000, -1000, 208, -1000, 119, -1000,
-	60, -1000, 385, -1000, 232, 180, 179, 218, -1000, 205,
-	201, 178, 336, 169, -1000, -1000, 166, 86, -1000, -1000,
-	-1000, -1000, -1000, -1000, 336, 336, -1000, 3, -1000, -1000,
-	-1000, -1000, -1000, 336, -1000, 157, 287, 108, -1000, -1000,
-	-1000, 321, 228, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	96, -1000, 86, 96, 65, -1000, 87, -1000, 176, -1000,
-	96, -1000,
+	-1000, -1000, -1000, -55, -3, -1000, -1000, 402, -1000, 249,
+	480, -1000, 512, -1000, 285, 351, 355, 355, -1000, -1000,
+	183, 972, -26, -28, 249, 94, 972, -29, -30, 47,
+	249, 1113, 1113, -1000, 377, -1000, -1000, -1000, -1000, 273,
+	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
+	-1000, -1000, 162, -1000, -1000, -1000, -1000, -1000, 132, -1000,
+	-1000, 384, -1000, -1000, 163, -1000, 149, -1000, -1000, -1000,
+	65, -1000, -1000, -1000, -1000, 223, 1113, 35, -1000, -1000,
+	12, -1000, 11, -1000, 42, -1000, 12, -1000, 628, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, 1113, 346,
+	249, 249, 48, 480, -1000, -1000, 228, -1000, 355, 277,
+	220, -1000, -1000, 65, 1113, 274, 219, -1000, -1000, 254,
+	213, -1000, 254, -1000, 217, 270, 218, -1000, -2, -36,
+	249, -1000, 972, 972, -1000, -1000, 217, 269, 249, -1000,
+	972, 972, 355, 249, 249, 230, -1000, -1000, -1000, 153,
+	-1000, -1000, -1000, -1000, 393, 398, 480, 149, -1000, -1000,
+	-1000, 207, 480, -1000, -1000, -1000, 155, 379, 150, 245,
+	850, 344, 66, 480, -1000, 744, 744, -1000, -1000, 744,
+	-1000, -1000, -1000, 244, 249, 110, -1000, 249, 284, -1000,
+	-1000, 182, -1000, 252, -1000, 351, 205, -1000, 249, -1000,
+	349, 206, -1000, 355, 268, 328, -1000, 79, -1000, 480,
+	1113, 249, -1000, 249, -1000, 267, -1000, 249, -1000, 249,
+	-1000, -1000, -1000, 398, 265, 393, 393, -1000, -1000, -1000,
+	-1000, 251, -1000, -1000, -1000, -1000, 480, -1000, 389, 297,
+	-1000, -1000, 369, -1000, -1000, -1000, -1000, 135, -1000, 365,
+	215, 31, -1000, -1000, -1000, -1000, -1000, -1000, -1000, 1229,
+	-1000, -1000, -1000, -1000, -1000, -1000, -1000, 337, -1000, 355,
+	377, -1000, -1000, -1000, 334, 65, -1000, 243, 239, -1000,
+	-1000, -1000, -1000, -1000, -1000, 249, -1000, -1000, -1000, 264,
+	-1000, -1000, 480, 238, -1000, 150, -1000, 480, -1000, 628,
+	480, -1000, 263, 214, 209, 249, -1000, 237, 235, 205,
+	-1000, -1000, -1000, -1000, 360, 203, -1000, -1000, 199, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, -1000, 355, 355, -1000,
+	13, -1000, -1000, -1000, -1000, 355, 104, 355, 190, 146,
+	261, -1000, -1000, -1000, -1000, -1000, 198, -1000, 189, 77,
+	-1000, -1000, -1000, 360, -1000, -1000, 355, 355, 180, 77,
+	77, 188, -1000,
 }
 
 var yyPgo = [...]int16{
-	0, 32, 13, 34, 146, 1, 536, 535, 534, 532,
-	256, 321, 531, 530, 320, 14, 529, 528, 526, 524,
-	116, 522, 521, 520, 7, 22, 519, 518, 30, 31,
-	517, 10, 516, 515, 514, 513, 501, 499, 26, 18,
-	140, 497, 496, 492, 489, 21, 488, 486, 8, 485,
-	484, 483, 482, 475, 11, 473, 471, 29, 470, 28,
-	25, 27, 469, 468, 467, 466, 461, 459, 76, 458,
-	457, 456, 20, 455, 453, 452, 451, 450, 449, 448,
-	447, 446, 0, 3, 446, 33, 445, 444, 441, 437,
-	436, 435, 433, 432, 17, 430, 429, 428, 112, 179,
-	98, 426, 422, 420, 419, 418, 417, 417, 6, 16,
-	416, 412, 410, 2, 23, 408, 407, 5, 404, 403,
-	402, 398, 395, 394, 12, 24, 19, 387, 385, 383,
-	383, 380, 379, 378, 376, 375, 373, 4, 371, 370,
-	369, 368, 361,
+	0, 40, 13, 32, 159, 0, 626, 625, 624, 622,
+	165, 339, 620, 618, 332, 47, 617, 613, 610, 607,
+	318, 605, 604, 603, 3, 23, 28, 596, 25, 594,
+	31, 15, 14, 592, 590, 588, 587, 578, 577, 43,
+	21, 96, 574, 570, 568, 565, 562, 26, 558, 557,
+	9, 556, 554, 552, 551, 550, 548, 547, 546, 12,
+	545, 544, 38, 543, 36, 37, 44, 541, 540, 539,
+	538, 535, 533, 531, 530, 529, 528, 527, 526, 8,
+	525, 517, 516, 514, 51, 513, 512, 511, 22, 509,
+	508, 507, 506, 505, 504, 503, 502, 501, 1, 4,
+	501, 42, 499, 498, 497, 496, 495, 494, 493, 485,
+	20, 481, 479, 477, 91, 151, 77, 475, 473, 472,
+	469, 468, 7, 468, 467, 17, 466, 462, 455, 454,
+	2, 29, 453, 452, 6, 5, 447, 446, 445, 444,
+	443, 442, 18, 27, 440, 24, 439, 422, 437, 430,
+	19, 35, 30, 11, 428, 419, 414,
 }
 
 var yyR1 = [...]uint8{
-	0, 121, 4, 3, 45, 39, 5, 8, 13, 13,
-	11, 11, 9, 9, 9, 10, 12, 7, 7, 7,
-	7, 6, 6, 44, 44, 122, 122, 122, 123, 123,
-	95, 95, 96, 96, 97, 97, 98, 103, 102, 102,
-	102, 99, 99, 100, 101, 101, 101, 43, 43, 40,
-	40, 76, 15, 15, 42, 41, 20, 20, 20, 19,
-	19, 19, 19, 19, 19, 19, 19, 19, 19, 19,
-	19, 19, 19, 19, 19, 77, 77, 24, 31, 30,
-	30, 30, 30, 18, 35, 35, 17, 17, 115, 115,
-	114, 114, 38, 38, 32, 32, 22, 116, 116, 116,
-	118, 119, 117, 117, 120, 120, 33, 34, 34, 36,
-	36, 37, 37, 1, 1, 1, 1, 2, 2, 92,
-	92, 93, 93, 94, 94, 91, 21, 78, 78, 78,
-	125, 125, 126, 126, 85, 85, 85, 84, 127, 111,
-	111, 112, 112, 113, 113, 128, 129, 129, 83, 83,
-	82, 82, 82, 82, 80, 80, 80, 81, 81, 23,
-	23, 104, 105, 105, 105, 107, 109, 109, 110, 110,
-	108, 130, 106, 106, 86, 86, 86, 87, 88, 88,
-	89, 89, 89, 89, 79, 79, 16, 29, 29, 28,
-	28, 27, 27, 27, 27, 25, 25, 26, 14, 73,
-	73, 74, 74, 74, 74, 74, 74, 74, 74, 74,
-	74, 74, 74, 74, 75, 90, 90, 46, 46, 47,
-	47, 47, 47, 47, 47, 47, 47, 48, 49, 50,
-	51, 51, 51, 52, 53, 54, 54, 55, 55, 56,
-	57, 57, 58, 59, 59, 62, 60, 131, 131, 132,
-	132, 61, 61, 65, 65, 65, 65, 65, 63, 64,
-	69, 69, 70, 70, 71, 71, 72, 72, 68, 66,
-	67, 67, 133, 134, 134, 135, 136, 137, 137, 138,
-	139, 140, 140, 141, 141, 141, 141, 124, 124, 142,
-	142, 142,
+	0, 146, 146, 147, 4, 3, 47, 40, 5, 8,
+	13, 13, 11, 11, 9, 9, 9, 10, 12, 7,
+	7, 7, 7, 6, 6, 46, 46, 148, 148, 148,
+	149, 149, 111, 111, 112, 112, 113, 113, 114, 119,
+	118, 118, 118, 115, 115, 116, 117, 117, 117, 45,
+	45, 41, 41, 41, 92, 15, 15, 44, 42, 43,
+	20, 20, 20, 19, 19, 19, 19, 19, 19, 19,
+	19, 19, 19, 19, 19, 19, 19, 19, 19, 93,
+	93, 24, 31, 31, 31, 30, 30, 30, 30, 30,
+	30, 30, 141, 141, 144, 144, 145, 145, 142, 142,
+	143, 143, 32, 18, 36, 36, 17, 17, 132, 132,
+	131, 131, 39, 39, 33, 33, 33, 33, 22, 133,
+	133, 133, 134, 134, 135, 135, 34, 35, 35, 37,
+	37, 38, 38, 1, 1, 1, 2, 2, 108, 108,
+	109, 109, 110, 110, 136, 136, 107, 21, 140, 94,
+	94, 94, 151, 151, 152, 152, 101, 101, 101, 101,
+	100, 153, 126, 126, 127, 127, 128, 130, 130, 99,
+	99, 98, 98, 98, 98, 96, 96, 96, 97, 97,
+	23, 23, 120, 121, 121, 121, 121, 121, 123, 125,
+	125, 124, 124, 129, 122, 122, 139, 102, 102, 102,
+	103, 104, 104, 105, 105, 105, 105, 95, 95, 16,
+	29, 29, 28, 28, 27, 27, 27, 27, 25, 25,
+	26, 14, 89, 89, 90, 90, 90, 90, 90, 90,
+	90, 90, 90, 90, 90, 90, 90, 138, 137, 91,
+	106, 106, 48, 48, 49, 49, 49, 49, 49, 49,
+	49, 49, 50, 52, 52, 53, 54, 54, 54, 55,
+	56, 56, 56, 57, 58, 59, 59, 60, 60, 61,
+	62, 62, 63, 64, 64, 67, 65, 154, 154, 155,
+	155, 66, 66, 70, 70, 70, 70, 70, 70, 70,
+	70, 68, 72, 69, 85, 85, 86, 86, 87, 87,
+	88, 88, 84, 73, 71, 75, 75, 51, 76, 76,
+	77, 78, 79, 79, 80, 81, 82, 82, 83, 83,
+	83, 83, 74, 150, 150, 156, 156, 156,
 }
 
 var yyR2 = [...]int8{
-	0, 8, 1, 1, 1, 1, 1, 2, 3, 0,
-	1, 2, 1, 1, 1, 1, 4, 2, 2, 2,
-	0, 2, 0, 3, 0, 3, 3, 0, 1, 0,
-	3, 0, 1, 0, 1, 2, 3, 2, 1, 1,
-	0, 1, 3, 1, 1, 1, 1, 1, 2, 1,
-	1, 1, 3, 1, 3, 4, 1, 1, 1, 1,
+	0, 1, 2, 8, 1, 1, 1, 1, 1, 2,
+	3, 0, 1, 2, 1, 1, 1, 1, 4, 2,
+	2, 2, 0, 2, 0, 3, 0, 3, 3, 0,
+	1, 0, 3, 0, 1, 0, 1, 2, 3, 2,
+	1, 1, 0, 1, 3, 1, 1, 1, 1, 1,
+	2, 1, 1, 1, 1, 3, 1, 3, 4, 3,
 	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
-	1, 1, 1, 1, 1, 1, 1, 2, 1, 1,
-	1, 1, 1, 1, 1, 1, 1, 4, 1, 3,
-	4, 4, 1, 2, 1, 1, 4, 1, 4, 6,
-	1, 1, 1, 3, 1, 1, 1, 1, 1, 1,
-	2, 1, 1, 1, 3, 5, 3, 1, 2, 2,
-	5, 1, 3, 4, 4, 2, 1, 3, 5, 4,
-	1, 2, 2, 0, 1, 5, 7, 1, 2, 2,
-	0, 1, 3, 1, 1, 4, 0, 2, 1, 3,
-	1, 2, 3, 3, 3, 5, 4, 3, 3, 1,
-	4, 4, 5, 1, 3, 1, 2, 0, 1, 3,
-	1, 4, 1, 3, 2, 3, 3, 4, 1, 1,
-	1, 1, 1, 0, 3, 3, 2, 3, 4, 1,
-	2, 1, 1, 1, 1, 1, 1, 4, 1, 1,
 	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
-	1, 1, 1, 1, 2, 1, 1, 2, 1, 4,
-	4, 4, 4, 4, 4, 4, 4, 4, 1, 1,
+	1, 2, 1, 1, 1, 1, 1, 1, 1, 1,
+	1, 1, 2, 3, 1, 3, 1, 2, 1, 1,
+	1, 1, 3, 1, 1, 1, 1, 4, 1, 3,
+	4, 4, 1, 2, 1, 1, 2, 1, 4, 1,
+	4, 6, 1, 3, 1, 1, 1, 1, 1, 1,
+	2, 1, 1, 3, 5, 3, 1, 2, 2, 5,
+	1, 3, 4, 4, 1, 1, 2, 1, 1, 3,
+	5, 4, 1, 2, 2, 0, 1, 4, 5, 7,
+	1, 2, 3, 0, 1, 1, 4, 0, 2, 1,
+	3, 1, 2, 3, 3, 3, 5, 4, 3, 3,
+	1, 4, 4, 4, 5, 1, 2, 3, 1, 3,
+	0, 1, 1, 4, 1, 3, 3, 2, 3, 3,
+	4, 1, 1, 1, 1, 1, 0, 3, 3, 2,
+	3, 4, 1, 2, 1, 1, 1, 1, 1, 1,
+	4, 1, 1, 1, 1, 1, 1, 1, 1, 1,
+	1, 1, 1, 1, 1, 1, 1, 1, 1, 2,
+	1, 1, 2, 1, 4, 4, 4, 4, 4, 4,
+	4, 4, 4, 1, 1, 1, 2, 3, 5, 1,
 	1, 3, 5, 1, 1, 1, 2, 1, 3, 1,
 	1, 3, 1, 1, 2, 1, 2, 1, 1, 1,
-	1, 1, 3, 1, 1, 1, 1, 1, 1, 3,
-	1, 2, 1, 2, 1, 1, 1, 1, 2, 1,
-	3, 3, 1, 1, 1, 3, 5, 1, 3, 2,
-	2, 1, 0, 1, 1, 1, 0, 2, 0, 1,
-	1, 3,
+	1, 1, 3, 1, 1, 1, 1, 1, 1, 1,
+	1, 1, 2, 3, 1, 2, 1, 2, 1, 1,
+	1, 1, 2, 2, 1, 3, 3, 1, 1, 1,
+	3, 5, 1, 3, 2, 2, 1, 0, 1, 1,
+	1, 0, 2, 2, 0, 1, 1, 3,
 }
 
 var yyChk = [...]int16{
-	-1000, -121, -8, -3, 6, 65, -13, 26, -7, 71,
-	82, 51, -11, -9, -14, -10, -12, -5, 8, 7,
-	-6, 73, 114, 114, 114, 27, -11, 28, 15, 83,
-	-10, 52, 29, -44, -122, 72, 68, -95, 84, -123,
-	49, -99, -100, -101, -4, -3, -45, 6, 7, -43,
-	-40, -42, -41, -4, -45, 6, -96, -97, -98, -99,
-	42, 42, 34, -40, 15, -20, -19, -77, -46, -92,
-	-18, -73, -104, -22, -17, -21, -16, -91, -33, -78,
-	-79, -80, -81, -23, -86, -76, -90, -47, 53, 55,
-	-74, -75, 58, 69, 87, 93, 95, 96, 106, 108,
-	109, 127, -87, -4, 78, 121, 54, 77, 79, 80,
-	89, 94, 105, 117, 113, 123, 122, 124, 125, 57,
-	30, 42, -98, 76, -100, -20, 15, -48, 28, 111,
-	26, 26, 26, 81, 111, 26, 97, -48, -68, 110,
-	26, 97, -48, -68, 128, -20, 82, 71, 111, -89,
-	120, 50, 104, -103, -3, -31, -30, -35, -32, -29,
-	-34, 115, 75, -38, -5, 26, -36, -37, 8, 36,
-	-1, 102, 92, -49, -50, -51, -52, -54, -55, 49,
-	-57, -56, -59, -58, -61, -62, -65, 28, -63, -64,
-	-68, -66, -67, -31, -69, -20, 126, -71, 91, 26,
-	-105, -106, -125, -24, 17, -5, -116, -118, -117, -120,
-	-114, -5, -115, -114, -5, 27, -125, -85, -83, -82,
-	-24, 61, -20, -24, 97, 97, -48, 27, -125, -85,
-	-20, -24, 97, 97, 56, -20, -20, -88, -39, -15,
-	8, -3, -45, -102, -29, -15, -28, -15, -27, -14,
-	-25, -26, 7, -5, 8, 35, 25, 8, -1, -124,
-	45, 34, -60, 70, -131, 44, 118, -132, 46, 88,
-	-60, -54, 16, 60, 61, 32, -93, -94, -5, 27,
-	34, -109, 34, -124, -20, 27, 34, 34, 28, 27,
-	34, -126, 34, 27, 34, 98, 64, 97, -20, -24,
-	-20, -24, -126, 27, -20, -24, -20, -24, -5, 31,
-	35, 27, -28, -15, -28, 28, 8, -2, 8, 36,
-	29, -142, -38, -15, -20, 8, 36, 17, -61, -57,
-	-59, 29, -70, -72, 32, -31, 90, -133, -48, -134,
-	-135, -136, 26, 27, 34, 28, -125, -24, -126, -110,
-	-108, -24, 17, -117, -38, -15, -114, 27, 17, -125,
-	-82, -31, -20, 27, -45, 27, -25, -15, 25, 8,
-	37, 8, 34, -72, -137, 17, -138, -5, -94, -39,
-	-15, -109, 34, -124, 29, 29, -111, 34, 29, -2,
-	-31, -53, -54, 27, 34, 34, -139, -140, -48, 29,
-	29, -126, -108, 34, -126, -127, 34, -112, -113, -82,
-	-128, 18, -137, -137, -141, 103, 47, 98, -119, -117,
-	34, 17, 34, -129, 8, 27, -83, -113, -83, 37,
-	34, 19,
+	-1000, -146, -147, -8, -3, 6, -147, 59, -13, 20,
+	-7, 65, 76, 45, -11, -9, -14, -10, -12, -5,
+	8, 7, -6, 67, 108, 108, 108, 21, -11, 22,
+	13, 77, -10, 46, 23, -46, -148, 66, 62, -111,
+	78, -149, 43, -115, -116, -117, -4, -3, -47, 6,
+	7, -45, -41, -44, -42, -43, -4, -47, 6, -112,
+	-113, -114, -115, 36, 36, 28, -41, 13, -20, 13,
+	-19, -93, -48, -108, -18, -89, -120, -22, -17, -21,
+	-16, -107, -34, -94, -95, -96, -97, -23, -102, -92,
+	-106, -49, 47, 49, -90, -91, 52, 63, 81, 87,
+	89, 90, 100, 102, 103, 121, -103, -4, 72, 115,
+	48, 71, 73, 74, 83, 88, 99, 111, 107, 117,
+	116, 118, 119, 51, 24, 36, -114, 70, -116, -20,
+	13, -50, 22, 18, 105, 20, 20, 20, 75, 105,
+	20, 91, -50, -84, 104, 20, 91, -50, -84, 122,
+	-20, 76, 65, 105, -105, 114, 44, 98, -119, -3,
+	-31, -30, -140, -32, -136, -36, -137, -139, -33, -35,
+	-141, 87, -3, 10, 11, 109, 69, -138, -5, -39,
+	9, 30, -37, -38, 20, 12, 8, -1, 96, 86,
+	-52, -55, -53, -56, -54, -57, 57, 61, -59, -60,
+	43, -62, -61, -64, -63, -66, -67, -70, 22, -68,
+	-72, -69, -73, -84, -71, -75, -74, -143, 79, -85,
+	70, -20, 120, 94, -30, -32, -87, 85, 20, -121,
+	-122, -151, -24, 15, -5, -133, -134, -135, -131, -5,
+	-132, -131, -5, 21, -151, -101, -99, -98, -24, 55,
+	-20, -24, 91, 91, -50, 21, -151, -101, -20, -24,
+	91, 91, 50, -20, -20, -104, -40, -15, 8, -3,
+	-47, -118, -29, -15, 20, 29, 31, 8, 9, -1,
+	21, -144, -145, -142, -31, -26, -5, 29, 19, -150,
+	39, 28, -20, 50, -65, 64, -154, 38, 112, -155,
+	40, 82, -65, -59, -20, 14, -50, 54, 55, -31,
+	26, -109, -110, -5, 21, 28, -125, -150, -20, 21,
+	28, 22, 21, 28, -152, 28, 21, 28, 92, 58,
+	91, -20, -24, -20, -24, -152, 21, -20, -24, -20,
+	-24, -5, 25, 29, -28, -15, -27, -14, -25, -26,
+	7, -5, 8, -47, -31, 21, 28, -142, 22, 8,
+	-2, 8, 30, 23, -156, -39, -15, -20, 8, 30,
+	15, 61, -31, -66, -62, -64, 23, -86, -88, 26,
+	-143, 84, -51, -50, -76, -77, -78, 20, 21, 28,
+	22, -151, -24, -153, 28, 15, -135, -39, -15, -131,
+	21, 15, -151, -98, -31, -20, 21, -47, 21, -28,
+	-15, -28, -145, -25, -15, 19, 8, 31, 8, 28,
+	50, -88, -79, 15, -80, -5, -110, -40, -15, -125,
+	15, -124, -129, -24, 16, -150, 23, 23, -126, 21,
+	23, -2, -31, -58, -59, -31, 21, 28, 28, -81,
+	-82, -50, 23, 23, -153, -130, 8, 28, -153, 28,
+	-79, -79, -83, 97, 41, 92, -122, 31, -134, 28,
+	-127, -98, -128, 16, 21, 17, 28, 28, -99, -130,
+	28, -99, 17,
 }
 
 var yyDef = [...]int16{
-	0, -2, 0, 9, 3, 20, 7, 0, 22, 0,
-	0, 0, 0, 10, 12, 13, 14, 198, 15, 6,
-	0, 0, 17, 18, 19, 8, 11, 0, 0, 21,
-	0, -2, 16, 0, 31, 29, 1, 0, 33, 0,
-	0, 28, 41, 43, 44, 45, 46, -2, 4, 23,
-	47, 49, 50, 0, 0, 2, 0, 32, 34, 0,
-	25, 26, 0, 48, 0, 0, 56, 57, 58, 59,
+	0, -2, 1, 0, 11, 5, 2, 22, 9, 0,
+	24, 0, 0, 0, 0, 12, 14, 15, 16, 221,
+	17, 8, 0, 0, 19, 20, 21, 10, 13, 0,
+	0, 23, 0, -2, 18, 0, 33, 31, 3, 0,
+	35, 0, 0, 30, 43, 45, 46, 47, 48, -2,
+	6, 25, 49, 51, 52, 53, 0, 0, 4, 0,
+	34, 36, 0, 27, 28, 0, 50, 0, 0, 0,
 	60, 61, 62, 63, 64, 65, 66, 67, 68, 69,
-	70, 71, 72, 73, 74, 75, 76, 218, 0, 83,
-	199, 200, 0, 0, 86, 126, 0, 0, 106, 0,
-	0, 159, 0, 51, 215, 216, 201, 202, 203, 204,
-	205, 206, 207, 208, 209, 210, 211, 212, 213, 0,
-	183, 30, 35, 0, 42, 54, 0, 217, 0, 119,
-	0, 0, 0, 186, 125, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 174, 0, 0, 214, 0,
-	180, 181, 182, 36, 40, 55, 78, 79, 80, 81,
-	82, 84, 85, 94, 95, 0, 107, 108, 92, 0,
-	109, 111, 112, 288, 228, 229, 230, 233, -2, 0,
-	-2, 0, 240, 0, -2, 0, 251, 0, 253, 254,
-	255, 256, 257, -2, 0, 269, 0, 260, 265, 0,
-	0, 163, 167, 172, 130, 0, 0, 97, 100, 102,
-	104, 105, 0, 88, 0, 127, 133, 0, 134, 148,
-	150, 0, 184, 185, 0, 0, 268, 154, 133, 0,
-	157, 158, 0, 0, 0, 175, 176, 0, 178, 179,
-	5, 0, 53, 37, 38, 39, 0, 194, 189, 191,
-	192, 193, -2, 198, 195, 0, 0, 93, 110, 0,
-	0, 0, 236, 0, 0, 247, 248, 0, 249, 250,
-	244, 0, 0, 0, 0, 261, 0, 121, 0, 161,
-	0, 133, 0, 131, 77, 96, 0, 0, 0, 87,
-	0, 0, 0, 129, 0, 151, 0, 0, 221, 225,
-	222, 226, 0, 156, 219, 223, 220, 224, 160, 177,
-	0, 187, 0, 194, 190, 0, 114, 116, 117, 0,
-	227, 287, 289, 290, 0, 92, 0, 231, 246, -2,
-	241, 252, 259, 262, 0, 266, 267, 270, 272, 271,
-	273, 274, 0, 120, 0, 0, 167, 173, 164, 166,
-	168, 170, 288, 103, 0, 0, 89, 128, 132, 140,
-	149, 152, 153, 155, 52, 188, 0, 196, 0, 118,
-	0, 93, 0, 263, 0, 0, 277, 282, 122, 0,
-	0, 133, 0, 98, 90, 91, 133, 0, 197, 115,
-	291, 232, 234, 275, 0, 0, 279, 286, 281, 123,
-	124, 162, 169, 0, 135, 0, 0, 139, 141, 143,
-	144, 146, 0, 278, 280, 283, 284, 285, 99, 101,
-	0, -2, 0, 0, 0, 276, 136, 142, 0, 147,
-	0, 145,
+	70, 71, 72, 73, 74, 75, 76, 77, 78, 79,
+	80, 243, 0, 103, 222, 223, 0, 0, 106, 147,
+	0, 0, 126, 0, 0, 180, 0, 54, 240, 241,
+	224, 225, 226, 227, 228, 229, 230, 231, 232, 233,
+	234, 235, 236, 0, 206, 32, 37, 0, 44, 57,
+	0, 242, 0, 59, 138, 0, 0, 0, 209, 146,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	197, 0, 0, 239, 0, 203, 204, 205, 38, 42,
+	58, 82, 83, 84, 85, 86, 87, 88, 89, 90,
+	91, 148, 0, 144, 145, 104, 105, 238, 117, 114,
+	115, 0, 127, 128, 0, 237, 112, 129, 131, 132,
+	324, 253, 254, 259, 255, 260, 0, 0, 263, -2,
+	0, -2, 0, 270, 0, -2, 0, 281, 0, 283,
+	284, 285, 286, 287, 288, 289, 290, -2, 0, 0,
+	0, 304, 0, 0, 100, 101, 294, 299, 0, 0,
+	185, 190, 194, 152, 0, 0, 119, 122, 124, 125,
+	0, 108, 0, 149, 155, 0, 156, 169, 171, 0,
+	207, 208, 0, 0, 302, 175, 155, 0, 178, 179,
+	0, 0, 0, 198, 199, 0, 201, 202, 7, 0,
+	56, 39, 40, 41, 0, 0, 0, 113, 116, 130,
+	92, 0, 94, 96, 98, 99, 117, 0, 0, 0,
+	0, 0, 256, 0, 266, 0, 0, 277, 278, 0,
+	279, 280, 274, 0, 292, 0, 303, 0, 0, 322,
+	295, 0, 140, 0, 182, 0, 186, 153, 81, 118,
+	0, 0, 107, 0, 0, 0, 151, 0, 172, 0,
+	0, 246, 250, 247, 251, 0, 177, 244, 248, 245,
+	249, 181, 200, 0, 0, 217, 212, 214, 215, 216,
+	-2, 221, 218, 102, 196, 93, 0, 97, 0, 133,
+	135, 136, 0, 252, 323, 325, 326, 0, 112, 0,
+	261, 0, 257, 276, -2, 271, 282, 293, 296, 0,
+	300, 301, 305, 307, 306, 308, 309, 0, 139, 0,
+	0, 190, 195, 187, 0, 324, 123, 0, 0, 109,
+	150, 154, 163, 170, 173, 174, 176, 55, 210, 0,
+	217, 213, 95, 0, 219, 0, 137, 0, 113, 0,
+	0, 297, 0, 0, 312, 317, 141, 0, 0, 183,
+	161, 189, 191, 192, 167, 120, 110, 111, 157, 211,
+	220, 134, 327, 262, 264, 258, 310, 0, 0, 314,
+	321, 316, 142, 143, 184, 0, 0, 0, 158, 0,
+	0, 313, 315, 318, 319, 320, 0, 168, 121, 0,
+	162, 164, 165, 167, 311, 193, 0, 0, 159, 0,
+	0, 0, 166,
 }
 
 var yyTok1 = [...]int8{
 	1,
 }
 
-var yyTok2 = [...]uint8{
+var yyTok2 = [...]int8{
 	2, 3, 4, 5, 6, 7, 8, 9, 10, 11,
 	12, 13, 14, 15, 16, 17, 18, 19, 20, 21,
 	22, 23, 24, 25, 26, 27, 28, 29, 30, 31,
@@ -716,7 +779,7 @@ var yyTok2 = [...]uint8{
 	92, 93, 94, 95, 96, 97, 98, 99, 100, 101,
 	102, 103, 104, 105, 106, 107, 108, 109, 110, 111,
 	112, 113, 114, 115, 116, 117, 118, 119, 120, 121,
-	122, 123, 124, 125, 126, 127, 128,
+	122,
 }
 
 var yyTok3 = [...]int8{
@@ -1060,1183 +1123,1404 @@ yydefault:
 	// dummy call; replaced with literal code
 	switch yynt {
 
-	case 1:
+	case 3:
 		yyDollar = yyS[yypt-8 : yypt+1]
-//line asn1.y:345
+//line asn1.y:380
 		{
-			yylex.(*ASN1Lexer).result = &ModuleDefinition{ModuleIdentifier: yyDollar[1].ModuleIdentifier, TagDefault: yyDollar[3].TagDefault, ExtensibilityImplied: yyDollar[4].ExtensionDefault, ModuleBody: yyDollar[7].ModuleBody}
+			lex := yylex.(*ASN1Lexer)
+			lex.results = append(lex.results, &ModuleDefinition{ModuleIdentifier: yyDollar[1].ModuleIdentifier, TagDefault: yyDollar[3].TagDefault, ExtensibilityImplied: yyDollar[4].ExtensionDefault, ModuleBody: yyDollar[7].ModuleBody})
 		}
-	case 2:
+	case 4:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:348
+//line asn1.y:386
 		{
 			yyVAL.TypeReference = TypeReference(yyDollar[1].name)
 		}
-	case 4:
+	case 6:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:353
+//line asn1.y:391
 		{
 			yyVAL.ValueReference = ValueReference(yyDollar[1].name)
 		}
-	case 7:
+	case 9:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line asn1.y:364
+//line asn1.y:402
 		{
 			yyVAL.ModuleIdentifier = ModuleIdentifier{Reference: yyDollar[1].name, DefinitiveIdentifier: yyDollar[2].DefinitiveIdentifier}
 		}
-	case 8:
+	case 10:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line asn1.y:367
+//line asn1.y:405
 		{
 			yyVAL.DefinitiveIdentifier = DefinitiveIdentifier(yyDollar[2].DefinitiveObjIdComponentList)
 		}
-	case 9:
+	case 11:
 		yyDollar = yyS[yypt-0 : yypt+1]
-//line asn1.y:368
+//line asn1.y:406
 		{
 			yyVAL.DefinitiveIdentifier = DefinitiveIdentifier(make([]DefinitiveObjIdComponent, 0))
 		}
-	case 10:
+	case 12:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:371
+//line asn1.y:409
 		{
 			yyVAL.DefinitiveObjIdComponentList = append(make([]DefinitiveObjIdComponent, 0), yyDollar[1].DefinitiveObjIdComponent)
 		}
-	case 11:
+	case 13:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line asn1.y:372
+//line asn1.y:410
 		{
 			yyVAL.DefinitiveObjIdComponentList = append(append(make([]DefinitiveObjIdComponent, 0), yyDollar[1].DefinitiveObjIdComponent), yyDollar[2].DefinitiveObjIdComponentList...)
 		}
-	case 12:
+	case 14:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:375
+//line asn1.y:413
 		{
 			yyVAL.DefinitiveObjIdComponent = DefinitiveObjIdComponent{Name: yyDollar[1].name}
 		}
-	case 13:
+	case 15:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:376
+//line asn1.y:414
 		{
 			yyVAL.DefinitiveObjIdComponent = DefinitiveObjIdComponent{Id: yyDollar[1].Number.IntValue()}
 		}
-	case 14:
+	case 16:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:377
+//line asn1.y:415
 		{
 			yyVAL.DefinitiveObjIdComponent = yyDollar[1].DefinitiveObjIdComponent
 		}
-	case 15:
+	case 17:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:380
+//line asn1.y:418
 		{
 			yyVAL.Number = yyDollar[1].Number
 		}
-	case 16:
+	case 18:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line asn1.y:384
+//line asn1.y:422
 		{
 			yyVAL.DefinitiveObjIdComponent = DefinitiveObjIdComponent{Name: yyDollar[1].name, Id: yyDollar[3].Number.IntValue()}
 		}
-	case 17:
+	case 19:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line asn1.y:387
+//line asn1.y:425
 		{
 			yyVAL.TagDefault = TAGS_EXPLICIT
 		}
-	case 18:
+	case 20:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line asn1.y:388
+//line asn1.y:426
 		{
 			yyVAL.TagDefault = TAGS_IMPLICIT
 		}
-	case 19:
+	case 21:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line asn1.y:389
+//line asn1.y:427
 		{
 			yyVAL.TagDefault = TAGS_AUTOMATIC
 		}
-	case 20:
+	case 22:
 		yyDollar = yyS[yypt-0 : yypt+1]
-//line asn1.y:390
+//line asn1.y:428
 		{
 			yyVAL.TagDefault = TAGS_EXPLICIT
 		}
-	case 21:
+	case 23:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line asn1.y:393
+//line asn1.y:431
 		{
 			yyVAL.ExtensionDefault = true
 		}
-	case 22:
+	case 24:
 		yyDollar = yyS[yypt-0 : yypt+1]
-//line asn1.y:394
+//line asn1.y:432
 		{
 			yyVAL.ExtensionDefault = false
 		}
-	case 23:
+	case 25:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line asn1.y:397
+//line asn1.y:435
 		{
 			yyVAL.ModuleBody = ModuleBody{Imports: yyDollar[2].Imports, AssignmentList: yyDollar[3].AssignmentList}
 		}
-	case 24:
+	case 26:
 		yyDollar = yyS[yypt-0 : yypt+1]
-//line asn1.y:398
+//line asn1.y:436
 		{
 			yyVAL.ModuleBody = ModuleBody{}
 		}
-	case 30:
+	case 32:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line asn1.y:411
+//line asn1.y:449
 		{
 			yyVAL.Imports = yyDollar[2].Imports
 		}
-	case 31:
+	case 33:
 		yyDollar = yyS[yypt-0 : yypt+1]
-//line asn1.y:412
+//line asn1.y:450
 		{
 			yyVAL.Imports = make([]SymbolsFromModule, 0)
 		}
-	case 32:
+	case 34:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:415
+//line asn1.y:453
 		{
 			yyVAL.Imports = yyDollar[1].Imports
 		}
-	case 33:
+	case 35:
 		yyDollar = yyS[yypt-0 : yypt+1]
-//line asn1.y:416
+//line asn1.y:454
 		{
 			yyVAL.Imports = make([]SymbolsFromModule, 0)
 		}
-	case 34:
+	case 36:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:419
+//line asn1.y:457
 		{
 			yyVAL.Imports = append(make([]SymbolsFromModule, 0), yyDollar[1].SymbolsFromModule)
 		}
-	case 35:
+	case 37:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line asn1.y:420
+//line asn1.y:458
 		{
 			yyVAL.Imports = append(yyDollar[1].Imports, yyDollar[2].SymbolsFromModule)
 		}
-	case 36:
+	case 38:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line asn1.y:423
+//line asn1.y:461
 		{
 			yyVAL.SymbolsFromModule = SymbolsFromModule{yyDollar[1].SymbolList, yyDollar[3].GlobalModuleReference}
 		}
-	case 37:
+	case 39:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line asn1.y:426
+//line asn1.y:464
 		{
 			yyVAL.GlobalModuleReference = GlobalModuleReference{yyDollar[1].name, yyDollar[2].Value}
 		}
-	case 38:
+	case 40:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:429
+//line asn1.y:467
 		{
 			yyVAL.Value = yyDollar[1].ObjectIdentifierValue
 		}
-	case 39:
+	case 41:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:430
+//line asn1.y:468
 		{
 			yyVAL.Value = yyDollar[1].DefinedValue
 		}
-	case 40:
+	case 42:
 		yyDollar = yyS[yypt-0 : yypt+1]
-//line asn1.y:431
+//line asn1.y:469
 		{
 			yyVAL.Value = nil
 		}
-	case 41:
+	case 43:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:434
+//line asn1.y:472
 		{
 			yyVAL.SymbolList = append(make([]Symbol, 0), yyDollar[1].Symbol)
 		}
-	case 42:
+	case 44:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line asn1.y:435
+//line asn1.y:473
 		{
 			yyVAL.SymbolList = append(yyDollar[1].SymbolList, yyDollar[3].Symbol)
 		}
-	case 44:
+	case 46:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:442
+//line asn1.y:480
 		{
 			yyVAL.Symbol = TypeReference(yyDollar[1].TypeReference)
 		}
-	case 45:
+	case 47:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:443
+//line asn1.y:481
 		{
 			yyVAL.Symbol = ModuleReference(yyDollar[1].name)
 		}
-	case 46:
+	case 48:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:444
+//line asn1.y:482
 		{
 			yyVAL.Symbol = ValueReference(yyDollar[1].ValueReference)
 		}
-	case 47:
+	case 49:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:450
+//line asn1.y:488
 		{
 			yyVAL.AssignmentList = AssignmentList{yyDollar[1].Assignment}
 		}
-	case 48:
+	case 50:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line asn1.y:451
+//line asn1.y:489
 		{
 			yyVAL.AssignmentList = append(yyDollar[1].AssignmentList, yyDollar[2].Assignment)
 		}
-	case 51:
+	case 54:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:467
+//line asn1.y:505
 		{
 			yyVAL.Type = yyDollar[1].TypeReference
 		}
-	case 52:
+	case 55:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line asn1.y:475
+//line asn1.y:513
 		{
 			yyVAL.DefinedValue = DefinedValue{ModuleReference(yyDollar[1].name), yyDollar[3].ValueReference}
 		}
-	case 53:
+	case 56:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:476
+//line asn1.y:514
 		{
 			yyVAL.DefinedValue = DefinedValue{ValueName: yyDollar[1].ValueReference}
 		}
-	case 54:
+	case 57:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line asn1.y:491
+//line asn1.y:529
 		{
 			yyVAL.Assignment = TypeAssignment{yyDollar[1].TypeReference, yyDollar[3].Type}
 		}
-	case 55:
+	case 58:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line asn1.y:494
+//line asn1.y:532
 		{
-			yyVAL.Assignment = ValueAssignment{yyDollar[1].ValueReference, yyDollar[2].Type, yyDollar[4].Value}
+			yyVAL.Assignment = ValueAssignment{yyDollar[1].ValueReference, yyDollar[2].Type, valueOfType(yyDollar[2].Type, yyDollar[4].Value)}
 		}
-	case 77:
+	case 59:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line asn1.y:535
+		{
+			yyVAL.Assignment = ValueAssignment{yyDollar[1].ValueReference, xmlValueType(yylex, yyDollar[3].XMLValue.Name), yyDollar[3].XMLValue}
+		}
+	case 81:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line asn1.y:541
+//line asn1.y:582
 		{
 			yyVAL.NamedType = NamedType{Identifier: Identifier(yyDollar[1].name), Type: yyDollar[2].Type}
 		}
-	case 81:
+	case 92:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line asn1.y:622
+		{
+			yyVAL.Value = BracedValue{}
+		}
+	case 93:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line asn1.y:623
+		{
+			yyVAL.Value = yyDollar[2].BracedValue
+		}
+	case 94:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:564
+//line asn1.y:626
 		{
-			yyVAL.Value = yyDollar[1].ObjectIdentifierValue
+			yyVAL.BracedValue = BracedValue{yyDollar[1].BracedComponent}
+		}
+	case 95:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line asn1.y:627
+		{
+			yyVAL.BracedValue = append(yyDollar[1].BracedValue, yyDollar[3].BracedComponent)
+		}
+	case 96:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line asn1.y:630
+		{
+			yyVAL.BracedComponent = BracedComponent{yyDollar[1].Value}
+		}
+	case 97:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line asn1.y:631
+		{
+			yyVAL.BracedComponent = append(yyDollar[1].BracedComponent, yyDollar[2].Value)
+		}
+	case 99:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line asn1.y:635
+		{
+			yyVAL.Value = NameAndNumber(yyDollar[1].ObjectIdElement)
+		}
+	case 102:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line asn1.y:648
+		{
+			yyVAL.Value = DefinedValue{ModuleReference(yyDollar[1].name), yyDollar[3].ValueReference}
 		}
-	case 83:
+	case 103:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:577
+//line asn1.y:654
 		{
 			yyVAL.Type = BooleanType{}
 		}
-	case 84:
+	case 104:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:580
+//line asn1.y:657
 		{
 			yyVAL.Value = Boolean(true)
 		}
-	case 85:
+	case 105:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:581
+//line asn1.y:658
 		{
 			yyVAL.Value = Boolean(false)
 		}
-	case 86:
+	case 106:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:586
+//line asn1.y:663
 		{
 			yyVAL.Type = IntegerType{}
 		}
-	case 87:
+	case 107:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line asn1.y:587
+//line asn1.y:664
 		{
 			yyVAL.Type = IntegerType{yyDollar[3].NamedNumberList}
 		}
-	case 88:
+	case 108:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:590
+//line asn1.y:667
 		{
 			yyVAL.NamedNumberList = []NamedNumber{yyDollar[1].NamedNumber}
 		}
-	case 89:
+	case 109:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line asn1.y:591
+//line asn1.y:668
 		{
 			yyVAL.NamedNumberList = append(yyDollar[1].NamedNumberList, yyDollar[3].NamedNumber)
 		}
-	case 90:
+	case 110:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line asn1.y:594
+//line asn1.y:671
 		{
 			yyVAL.NamedNumber = NamedNumber{Identifier(yyDollar[1].name), yyDollar[3].Number}
 		}
-	case 91:
+	case 111:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line asn1.y:595
+//line asn1.y:672
 		{
 			yyVAL.NamedNumber = NamedNumber{Identifier(yyDollar[1].name), yyDollar[3].DefinedValue}
 		}
-	case 92:
+	case 112:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:598
+//line asn1.y:675
 		{
 			yyVAL.Number = yyDollar[1].Number
 		}
-	case 93:
+	case 113:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line asn1.y:599
+//line asn1.y:676
 		{
 			yyVAL.Number = yyDollar[2].Number.UnaryMinus()
 		}
-	case 94:
+	case 114:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:604
+//line asn1.y:682
 		{
 			yyVAL.Value = yyDollar[1].Number
 		}
-	case 95:
+	case 115:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line asn1.y:683
+		{
+			yyVAL.Value = yyDollar[1].BigNumber
+		}
+	case 116:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line asn1.y:684
+		{
+			yyVAL.Value = yyDollar[2].BigNumber.UnaryMinus()
+		}
+	case 117:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:605
+//line asn1.y:685
 		{
 			yyVAL.Value = IdentifiedIntegerValue{Name: yyDollar[1].name}
 		}
-	case 96:
+	case 118:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line asn1.y:610
+//line asn1.y:690
 		{
 			yyVAL.Type = yyDollar[3].EnumeratedType
 		}
-	case 97:
+	case 119:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:613
+//line asn1.y:695
 		{
 			yyVAL.EnumeratedType = EnumeratedType{RootEnumeration: yyDollar[1].Enumeration}
 		}
-	case 98:
+	case 120:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line asn1.y:614
+//line asn1.y:696
 		{
-			yyVAL.EnumeratedType = EnumeratedType{RootEnumeration: yyDollar[1].Enumeration}
+			yyVAL.EnumeratedType = EnumeratedType{RootEnumeration: yyDollar[1].Enumeration, Extensible: true}
 		}
-	case 99:
+	case 121:
 		yyDollar = yyS[yypt-6 : yypt+1]
-//line asn1.y:615
+//line asn1.y:697
 		{
-			yyVAL.EnumeratedType = EnumeratedType{RootEnumeration: yyDollar[1].Enumeration, AdditionalEnumeration: yyDollar[6].Enumeration}
+			yyVAL.EnumeratedType = EnumeratedType{RootEnumeration: yyDollar[1].Enumeration, AdditionalEnumeration: yyDollar[6].Enumeration, Extensible: true}
 		}
-	case 102:
+	case 122:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:623
+//line asn1.y:700
 		{
 			yyVAL.Enumeration = []EnumerationItem{yyDollar[1].EnumerationItem}
 		}
-	case 103:
+	case 123:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line asn1.y:624
+//line asn1.y:701
 		{
-			yyVAL.Enumeration = append([]EnumerationItem{yyDollar[1].EnumerationItem}, yyDollar[3].Enumeration...)
+			yyVAL.Enumeration = append(yyDollar[1].Enumeration, yyDollar[3].EnumerationItem)
 		}
-	case 104:
+	case 124:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:627
+//line asn1.y:704
 		{
 			yyVAL.EnumerationItem = yyDollar[1].NamedNumber
 		}
-	case 105:
+	case 125:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:628
+//line asn1.y:705
 		{
 			yyVAL.EnumerationItem = Identifier(yyDollar[1].name)
 		}
-	case 106:
+	case 126:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:633
+//line asn1.y:710
 		{
 			yyVAL.Type = RealType{}
 		}
-	case 109:
+	case 129:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:642
+//line asn1.y:719
 		{
 			yyVAL.Value = yyDollar[1].Real
 		}
-	case 110:
+	case 130:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line asn1.y:643
+//line asn1.y:720
 		{
 			yyVAL.Value = yyDollar[2].Real.UnaryMinus()
 		}
-	case 111:
+	case 131:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:647
+//line asn1.y:724
 		{
 			yyVAL.Value = Real(math.Inf(1))
 		}
-	case 112:
+	case 132:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:648
+//line asn1.y:725
 		{
 			yyVAL.Value = Real(math.Inf(-1))
 		}
-	case 113:
-		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:652
-		{
-			yyVAL.Real = parseRealNumber(yyDollar[1].Number, 0, 0)
-		}
-	case 114:
+	case 133:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line asn1.y:653
+//line asn1.y:732
 		{
 			yyVAL.Real = parseRealNumber(yyDollar[1].Number, yyDollar[3].Number, 0)
 		}
-	case 115:
+	case 134:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line asn1.y:654
+//line asn1.y:733
 		{
 			yyVAL.Real = parseRealNumber(yyDollar[1].Number, yyDollar[3].Number, yyDollar[5].Number)
 		}
-	case 116:
+	case 135:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line asn1.y:655
+//line asn1.y:734
 		{
 			yyVAL.Real = parseRealNumber(yyDollar[1].Number, 0, yyDollar[3].Number)
 		}
-	case 118:
+	case 137:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line asn1.y:659
+//line asn1.y:738
 		{
 			yyVAL.Number = Number(-int(yyDollar[2].Number))
 		}
-	case 119:
+	case 138:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line asn1.y:664
+//line asn1.y:743
 		{
 			yyVAL.Type = BitStringType{}
 		}
-	case 120:
+	case 139:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line asn1.y:665
+//line asn1.y:744
 		{
 			yyVAL.Type = BitStringType{NamedBits: yyDollar[4].NamedBitList}
 		}
-	case 121:
+	case 140:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:668
+//line asn1.y:747
 		{
 			yyVAL.NamedBitList = append(make([]NamedBit, 0), yyDollar[1].NamedBit)
 		}
-	case 122:
+	case 141:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line asn1.y:669
+//line asn1.y:748
 		{
 			yyVAL.NamedBitList = append(yyDollar[1].NamedBitList, yyDollar[3].NamedBit)
 		}
-	case 123:
+	case 142:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line asn1.y:672
+//line asn1.y:751
 		{
 			yyVAL.NamedBit = NamedBit{Name: Identifier(yyDollar[1].name), Index: yyDollar[3].Number}
 		}
-	case 124:
+	case 143:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line asn1.y:673
+//line asn1.y:752
 		{
 			yyVAL.NamedBit = NamedBit{Name: Identifier(yyDollar[1].name), Index: yyDollar[3].DefinedValue}
 		}
-	case 125:
+	case 144:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line asn1.y:758
+		{
+			yyVAL.Value = parseBString(yyDollar[1].bstring)
+		}
+	case 145:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line asn1.y:759
+		{
+			yyVAL.Value = parseHString(yyDollar[1].hstring)
+		}
+	case 146:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line asn1.y:678
+//line asn1.y:765
 		{
 			yyVAL.Type = OctetStringType{}
 		}
-	case 126:
+	case 147:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:683
+//line asn1.y:779
 		{
 			yyVAL.Type = NullType{}
 		}
-	case 127:
+	case 148:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line asn1.y:784
+		{
+			yyVAL.Value = NullValue{}
+		}
+	case 149:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line asn1.y:688
+//line asn1.y:789
 		{
 			yyVAL.Type = SequenceType{}
 		}
-	case 128:
+	case 150:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line asn1.y:689
+//line asn1.y:790
 		{
-			yyVAL.Type = SequenceType{}
+			yyVAL.Type = SequenceType{Extensible: true}
 		}
-	case 129:
+	case 151:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line asn1.y:690
+//line asn1.y:791
 		{
-			yyVAL.Type = SequenceType{Components: append(yyDollar[3].ComponentTypeLists.Components, yyDollar[3].ComponentTypeLists.TrailingComponents...), ExtensionAdditions: yyDollar[3].ComponentTypeLists.ExtensionAdditions}
+			yyVAL.Type = SequenceType{Components: append(yyDollar[3].ComponentTypeLists.Components, yyDollar[3].ComponentTypeLists.TrailingComponents...), ExtensionAdditions: yyDollar[3].ComponentTypeLists.ExtensionAdditions, Extensible: yyDollar[3].ComponentTypeLists.Extensible}
 		}
-	case 134:
+	case 156:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:702
+//line asn1.y:802
 		{
 			yyVAL.ComponentTypeLists = ComponentTypeLists{Components: yyDollar[1].ComponentTypeList}
 		}
-	case 135:
+	case 157:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line asn1.y:803
+		{
+			yyVAL.ComponentTypeLists = ComponentTypeLists{Components: yyDollar[1].ComponentTypeList, ExtensionAdditions: yyDollar[4].ExtensionAdditions, Extensible: true}
+		}
+	case 158:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line asn1.y:703
+//line asn1.y:804
 		{
-			yyVAL.ComponentTypeLists = ComponentTypeLists{Components: yyDollar[1].ComponentTypeList, ExtensionAdditions: yyDollar[4].ExtensionAdditions}
+			yyVAL.ComponentTypeLists = ComponentTypeLists{Components: yyDollar[1].ComponentTypeList, ExtensionAdditions: yyDollar[4].ExtensionAdditions, Extensible: true}
 		}
-	case 136:
+	case 159:
 		yyDollar = yyS[yypt-7 : yypt+1]
-//line asn1.y:704
+//line asn1.y:805
 		{
-			yyVAL.ComponentTypeLists = ComponentTypeLists{Components: yyDollar[1].ComponentTypeList, ExtensionAdditions: yyDollar[4].ExtensionAdditions, TrailingComponents: yyDollar[7].ComponentTypeList}
+			yyVAL.ComponentTypeLists = ComponentTypeLists{Components: yyDollar[1].ComponentTypeList, ExtensionAdditions: yyDollar[4].ExtensionAdditions, TrailingComponents: yyDollar[7].ComponentTypeList, Extensible: true}
 		}
-	case 139:
-		yyDollar = yyS[yypt-2 : yypt+1]
-//line asn1.y:716
+	case 162:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line asn1.y:819
 		{
-			yyVAL.ExtensionAdditions = yyDollar[2].ExtensionAdditions
+			yyVAL.ExtensionAdditions = append(yyDollar[1].ExtensionAdditions, yyDollar[3].ExtensionAdditions...)
 		}
-	case 140:
+	case 163:
 		yyDollar = yyS[yypt-0 : yypt+1]
-//line asn1.y:717
+//line asn1.y:820
 		{
 			yyVAL.ExtensionAdditions = nil
 		}
-	case 141:
+	case 164:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:720
+//line asn1.y:823
 		{
-			yyVAL.ExtensionAdditions = append([]ExtensionAddition{}, yyDollar[1].ExtensionAdditions...)
+			yyVAL.ExtensionAdditions = []ExtensionAddition{yyDollar[1].ComponentType}
 		}
-	case 142:
-		yyDollar = yyS[yypt-3 : yypt+1]
-//line asn1.y:721
+	case 165:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line asn1.y:824
 		{
-			yyVAL.ExtensionAdditions = append(yyDollar[1].ExtensionAdditions, yyDollar[3].ExtensionAdditions...)
+			yyVAL.ExtensionAdditions = []ExtensionAddition{yyDollar[1].ExtensionAdditionGroup}
 		}
-	case 143:
-		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:724
+	case 166:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line asn1.y:826
 		{
-			yyVAL.ExtensionAdditions = []ExtensionAddition{yyDollar[1].ComponentType}
+			yyVAL.ExtensionAdditionGroup = ExtensionAdditionGroup{Version: yyDollar[2].Number, Components: yyDollar[3].ComponentTypeList}
 		}
-	case 144:
-		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:725
+	case 167:
+		yyDollar = yyS[yypt-0 : yypt+1]
+//line asn1.y:829
 		{
-			yyVAL.ExtensionAdditions = nil
+			yyVAL.Number = Number(0)
 		}
-	case 148:
+	case 168:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line asn1.y:830
+		{
+			yyVAL.Number = yyDollar[1].Number
+		}
+	case 169:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:734
+//line asn1.y:833
 		{
 			yyVAL.ComponentTypeList = append(make(ComponentTypeList, 0), yyDollar[1].ComponentType)
 		}
-	case 149:
+	case 170:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line asn1.y:735
+//line asn1.y:834
 		{
 			yyVAL.ComponentTypeList = append(yyDollar[1].ComponentTypeList, yyDollar[3].ComponentType)
 		}
-	case 150:
+	case 171:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:738
+//line asn1.y:837
 		{
 			yyVAL.ComponentType = NamedComponentType{NamedType: yyDollar[1].NamedType}
 		}
-	case 151:
+	case 172:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line asn1.y:739
+//line asn1.y:838
 		{
 			yyVAL.ComponentType = NamedComponentType{NamedType: yyDollar[1].NamedType, IsOptional: true}
 		}
-	case 152:
+	case 173:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line asn1.y:740
+//line asn1.y:839
 		{
-			yyVAL.ComponentType = NamedComponentType{NamedType: yyDollar[1].NamedType, Default: &yyDollar[3].Value}
+			defaultValue := valueOfType(yyDollar[1].NamedType.Type, yyDollar[3].Value)
+			yyVAL.ComponentType = NamedComponentType{NamedType: yyDollar[1].NamedType, Default: &defaultValue}
 		}
-	case 153:
+	case 174:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line asn1.y:741
+//line asn1.y:840
 		{
 			yyVAL.ComponentType = ComponentsOfComponentType{Type: yyDollar[3].Type}
 		}
-	case 154:
+	case 175:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line asn1.y:746
+//line asn1.y:845
 		{
 			yyVAL.Type = SetType{}
 		}
-	case 155:
+	case 176:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line asn1.y:747
+//line asn1.y:846
 		{
-			yyVAL.Type = SetType{}
+			yyVAL.Type = SetType{Extensible: true}
 		}
-	case 156:
+	case 177:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line asn1.y:748
+//line asn1.y:847
 		{
-			yyVAL.Type = SetType{Components: append(yyDollar[3].ComponentTypeLists.Components, yyDollar[3].ComponentTypeLists.TrailingComponents...), ExtensionAdditions: yyDollar[3].ComponentTypeLists.ExtensionAdditions}
+			yyVAL.Type = SetType{Components: append(yyDollar[3].ComponentTypeLists.Components, yyDollar[3].ComponentTypeLists.TrailingComponents...), ExtensionAdditions: yyDollar[3].ComponentTypeLists.ExtensionAdditions, Extensible: yyDollar[3].ComponentTypeLists.Extensible}
 		}
-	case 157:
+	case 178:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line asn1.y:753
+//line asn1.y:852
 		{
 			yyVAL.Type = SetOfType{yyDollar[3].Type}
 		}
-	case 158:
+	case 179:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line asn1.y:754
+//line asn1.y:853
 		{
 			yyVAL.Type = SetOfType{yyDollar[3].NamedType}
 		}
-	case 159:
+	case 180:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:758
+//line asn1.y:857
 		{
 			yyVAL.Type = AnyType{}
 		}
-	case 160:
+	case 181:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line asn1.y:759
+//line asn1.y:858
 		{
 			yyVAL.Type = AnyType{Identifier(yyDollar[4].name)}
 		}
-	case 161:
+	case 182:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line asn1.y:764
+//line asn1.y:863
 		{
 			yyVAL.Type = yyDollar[3].ChoiceType
 		}
-	case 162:
+	case 183:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line asn1.y:866
+		{
+			yyVAL.ChoiceType = ChoiceType{AlternativeTypeList: yyDollar[1].AlternativeTypeList, ExtensionTypes: yyDollar[4].ExtensionAdditionAlternativesList, Extensible: true}
+		}
+	case 184:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line asn1.y:767
+//line asn1.y:867
 		{
-			yyVAL.ChoiceType = ChoiceType{yyDollar[1].AlternativeTypeList, yyDollar[4].ExtensionAdditionAlternativesList}
+			yyVAL.ChoiceType = ChoiceType{AlternativeTypeList: yyDollar[1].AlternativeTypeList, ExtensionTypes: yyDollar[4].ExtensionAdditionAlternativesList, Extensible: true}
 		}
-	case 163:
+	case 185:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:768
+//line asn1.y:868
 		{
 			yyVAL.ChoiceType = ChoiceType{AlternativeTypeList: yyDollar[1].AlternativeTypeList}
 		}
-	case 164:
+	case 186:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line asn1.y:869
+		{
+			yyVAL.ChoiceType = ChoiceType{ExtensionTypes: yyDollar[2].ExtensionAdditionAlternativesList, Extensible: true}
+		}
+	case 187:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line asn1.y:769
+//line asn1.y:870
 		{
-			yyVAL.ChoiceType = ChoiceType{nil, yyDollar[2].ExtensionAdditionAlternativesList}
+			yyVAL.ChoiceType = ChoiceType{ExtensionTypes: yyDollar[2].ExtensionAdditionAlternativesList, Extensible: true}
 		}
-	case 166:
-		yyDollar = yyS[yypt-2 : yypt+1]
-//line asn1.y:776
+	case 189:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line asn1.y:878
 		{
-			yyVAL.ExtensionAdditionAlternativesList = yyDollar[2].ExtensionAdditionAlternativesList
+			yyVAL.ExtensionAdditionAlternativesList = append(yyDollar[1].ExtensionAdditionAlternativesList, yyDollar[3].ExtensionAdditionAlternative)
 		}
-	case 167:
+	case 190:
 		yyDollar = yyS[yypt-0 : yypt+1]
-//line asn1.y:777
+//line asn1.y:879
 		{
 			yyVAL.ExtensionAdditionAlternativesList = make([]ChoiceExtension, 0)
 		}
-	case 168:
+	case 191:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:780
-		{
-			yyVAL.ExtensionAdditionAlternativesList = append(make([]ChoiceExtension, 0), yyDollar[1].ExtensionAdditionAlternative)
-		}
-	case 169:
-		yyDollar = yyS[yypt-3 : yypt+1]
-//line asn1.y:781
+//line asn1.y:882
 		{
-			yyVAL.ExtensionAdditionAlternativesList = append(yyDollar[1].ExtensionAdditionAlternativesList, yyDollar[3].ExtensionAdditionAlternative)
+			yyVAL.ExtensionAdditionAlternative = yyDollar[1].ExtensionAdditionAlternativesGroup
 		}
-	case 170:
+	case 192:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:785
+//line asn1.y:883
 		{
 			yyVAL.ExtensionAdditionAlternative = yyDollar[1].NamedType
 		}
-	case 172:
+	case 193:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line asn1.y:886
+		{
+			yyVAL.ExtensionAdditionAlternativesGroup = ExtensionAdditionAlternativesGroup{Version: yyDollar[2].Number, Alternatives: yyDollar[3].AlternativeTypeList}
+		}
+	case 194:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:792
+//line asn1.y:889
 		{
 			yyVAL.AlternativeTypeList = append(make([]NamedType, 0), yyDollar[1].NamedType)
 		}
-	case 173:
+	case 195:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line asn1.y:793
+//line asn1.y:890
 		{
 			yyVAL.AlternativeTypeList = append(yyDollar[1].AlternativeTypeList, yyDollar[3].NamedType)
 		}
-	case 174:
+	case 196:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line asn1.y:895
+		{
+			yyVAL.Value = ChoiceValue{Identifier: Identifier(yyDollar[1].name), Value: yyDollar[3].Value}
+		}
+	case 197:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line asn1.y:798
+//line asn1.y:900
 		{
 			yyVAL.Type = TaggedType{Tag: yyDollar[1].Tag, Type: yyDollar[2].Type}
 		}
-	case 175:
+	case 198:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line asn1.y:799
+//line asn1.y:901
 		{
 			yyVAL.Type = TaggedType{Tag: yyDollar[1].Tag, Type: yyDollar[3].Type, TagType: TAGS_IMPLICIT, HasTagType: true}
 		}
-	case 176:
+	case 199:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line asn1.y:800
+//line asn1.y:902
 		{
 			yyVAL.Type = TaggedType{Tag: yyDollar[1].Tag, Type: yyDollar[3].Type, TagType: TAGS_EXPLICIT, HasTagType: true}
 		}
-	case 177:
+	case 200:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line asn1.y:803
+//line asn1.y:905
 		{
 			yyVAL.Tag = Tag{Class: yyDollar[2].Class, ClassNumber: yyDollar[3].Value}
 		}
-	case 178:
+	case 201:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:806
+//line asn1.y:908
 		{
 			yyVAL.Value = yyDollar[1].Number
 		}
-	case 179:
+	case 202:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:807
+//line asn1.y:909
 		{
 			yyVAL.Value = yyDollar[1].DefinedValue
 		}
-	case 180:
+	case 203:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:810
+//line asn1.y:912
 		{
 			yyVAL.Class = CLASS_UNIVERSAL
 		}
-	case 181:
+	case 204:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:811
+//line asn1.y:913
 		{
 			yyVAL.Class = CLASS_APPLICATION
 		}
-	case 182:
+	case 205:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:812
+//line asn1.y:914
 		{
 			yyVAL.Class = CLASS_PRIVATE
 		}
-	case 183:
+	case 206:
 		yyDollar = yyS[yypt-0 : yypt+1]
-//line asn1.y:813
+//line asn1.y:915
 		{
 			yyVAL.Class = CLASS_CONTEXT_SPECIFIC
 		}
-	case 184:
+	case 207:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line asn1.y:818
+//line asn1.y:920
 		{
 			yyVAL.Type = SequenceOfType{yyDollar[3].Type}
 		}
-	case 185:
+	case 208:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line asn1.y:819
+//line asn1.y:921
 		{
 			yyVAL.Type = SequenceOfType{yyDollar[3].NamedType}
 		}
-	case 186:
+	case 209:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line asn1.y:824
+//line asn1.y:926
 		{
 			yyVAL.Type = ObjectIdentifierType{}
 		}
-	case 187:
+	case 210:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line asn1.y:829
+//line asn1.y:931
 		{
 			yyVAL.ObjectIdentifierValue = yyDollar[2].ObjectIdentifierValue
 		}
-	case 188:
+	case 211:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line asn1.y:830
+//line asn1.y:932
 		{
 			yyVAL.ObjectIdentifierValue = append(ObjectIdentifierValue{ObjectIdElement{Reference: &yyDollar[2].DefinedValue}}, yyDollar[3].ObjectIdentifierValue...)
 		}
-	case 189:
+	case 212:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:833
+//line asn1.y:935
 		{
 			yyVAL.ObjectIdentifierValue = ObjectIdentifierValue{yyDollar[1].ObjectIdElement}
 		}
-	case 190:
+	case 213:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line asn1.y:834
+//line asn1.y:936
 		{
 			yyVAL.ObjectIdentifierValue = append(ObjectIdentifierValue{yyDollar[1].ObjectIdElement}, yyDollar[2].ObjectIdentifierValue...)
 		}
-	case 191:
+	case 214:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:837
+//line asn1.y:939
 		{
 			yyVAL.ObjectIdElement = ObjectIdElement{Name: yyDollar[1].name}
 		}
-	case 194:
+	case 217:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:840
+//line asn1.y:942
 		{
 			cpy := yyDollar[1].DefinedValue
 			yyVAL.ObjectIdElement = ObjectIdElement{Reference: &cpy}
 		}
-	case 195:
+	case 218:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:843
+//line asn1.y:945
 		{
 			yyVAL.ObjectIdElement = ObjectIdElement{ID: yyDollar[1].Number.IntValue()}
 		}
-	case 196:
+	case 219:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:844
+//line asn1.y:946
 		{
 			cpy := yyDollar[1].DefinedValue
 			yyVAL.ObjectIdElement = ObjectIdElement{Reference: &cpy}
 		}
-	case 197:
+	case 220:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line asn1.y:848
+//line asn1.y:950
 		{
 			yyVAL.ObjectIdElement = ObjectIdElement{Name: yyDollar[1].name, ID: yyDollar[3].ObjectIdElement.ID, Reference: yyDollar[3].ObjectIdElement.Reference}
 		}
-	case 201:
+	case 224:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:860
+//line asn1.y:962
 		{
 			yyVAL.Type = RestrictedStringType{LexType: BMPString}
 		}
-	case 202:
+	case 225:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:861
+//line asn1.y:963
 		{
 			yyVAL.Type = RestrictedStringType{LexType: GeneralString}
 		}
-	case 203:
+	case 226:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:862
+//line asn1.y:964
 		{
 			yyVAL.Type = RestrictedStringType{LexType: GraphicString}
 		}
-	case 204:
+	case 227:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:863
+//line asn1.y:965
 		{
 			yyVAL.Type = RestrictedStringType{LexType: IA5String}
 		}
-	case 205:
+	case 228:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:864
+//line asn1.y:966
 		{
 			yyVAL.Type = RestrictedStringType{LexType: ISO646String}
 		}
-	case 206:
+	case 229:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:865
+//line asn1.y:967
 		{
 			yyVAL.Type = RestrictedStringType{LexType: NumericString}
 		}
-	case 207:
+	case 230:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:866
+//line asn1.y:968
 		{
 			yyVAL.Type = RestrictedStringType{LexType: PrintableString}
 		}
-	case 208:
+	case 231:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:867
+//line asn1.y:969
 		{
 			yyVAL.Type = RestrictedStringType{LexType: TeletexString}
 		}
-	case 209:
+	case 232:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:868
+//line asn1.y:970
 		{
 			yyVAL.Type = RestrictedStringType{LexType: T61String}
 		}
-	case 210:
+	case 233:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:869
+//line asn1.y:971
 		{
 			yyVAL.Type = RestrictedStringType{LexType: UniversalString}
 		}
-	case 211:
+	case 234:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:870
+//line asn1.y:972
 		{
 			yyVAL.Type = RestrictedStringType{LexType: UTF8String}
 		}
-	case 212:
+	case 235:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:871
+//line asn1.y:973
 		{
 			yyVAL.Type = RestrictedStringType{LexType: VideotexString}
 		}
-	case 213:
+	case 236:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:872
+//line asn1.y:974
 		{
 			yyVAL.Type = RestrictedStringType{LexType: VisibleString}
 		}
-	case 214:
+	case 237:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line asn1.y:980
+		{
+			yyVAL.Value = CharacterStringValue{CString(yyDollar[1].cstring)}
+		}
+	case 239:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line asn1.y:877
+//line asn1.y:991
 		{
 			yyVAL.Type = CharacterStringType{}
 		}
-	case 215:
+	case 240:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:882
+//line asn1.y:996
 		{
 			yyVAL.Type = TypeReference("GeneralizedTime")
 		}
-	case 216:
+	case 241:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:883
+//line asn1.y:997
 		{
 			yyVAL.Type = TypeReference("UTCTime")
 		}
-	case 217:
+	case 242:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line asn1.y:888
+//line asn1.y:1002
 		{
 			yyVAL.Type = ConstraintedType{yyDollar[1].Type, yyDollar[2].Constraint}
 		}
-	case 219:
+	case 244:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line asn1.y:894
+//line asn1.y:1008
 		{
 			yyVAL.Type = ConstraintedType{SetOfType{yyDollar[4].Type}, yyDollar[2].Constraint}
 		}
-	case 220:
+	case 245:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line asn1.y:895
+//line asn1.y:1009
 		{
 			yyVAL.Type = ConstraintedType{SetOfType{yyDollar[4].Type}, SingleElementConstraint(yyDollar[2].Elements)}
 		}
-	case 221:
+	case 246:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line asn1.y:896
+//line asn1.y:1010
 		{
 			yyVAL.Type = ConstraintedType{SequenceOfType{yyDollar[4].Type}, yyDollar[2].Constraint}
 		}
-	case 222:
+	case 247:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line asn1.y:897
+//line asn1.y:1011
 		{
 			yyVAL.Type = ConstraintedType{SequenceOfType{yyDollar[4].Type}, SingleElementConstraint(yyDollar[2].Elements)}
 		}
-	case 223:
+	case 248:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line asn1.y:898
+//line asn1.y:1012
 		{
 			yyVAL.Type = ConstraintedType{SetOfType{yyDollar[4].NamedType}, yyDollar[2].Constraint}
 		}
-	case 224:
+	case 249:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line asn1.y:899
+//line asn1.y:1013
 		{
 			yyVAL.Type = ConstraintedType{SetOfType{yyDollar[4].NamedType}, SingleElementConstraint(yyDollar[2].Elements)}
 		}
-	case 225:
+	case 250:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line asn1.y:900
+//line asn1.y:1014
 		{
 			yyVAL.Type = ConstraintedType{SequenceOfType{yyDollar[4].NamedType}, yyDollar[2].Constraint}
 		}
-	case 226:
+	case 251:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line asn1.y:901
+//line asn1.y:1015
 		{
 			yyVAL.Type = ConstraintedType{SequenceOfType{yyDollar[4].NamedType}, SingleElementConstraint(yyDollar[2].Elements)}
 		}
-	case 227:
+	case 252:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line asn1.y:906
+//line asn1.y:1020
 		{
 			yyVAL.Constraint = Constraint{ConstraintSpec: yyDollar[2].ConstraintSpec}
 		}
-	case 228:
+	case 253:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:909
+//line asn1.y:1023
 		{
 			yyVAL.ConstraintSpec = yyDollar[1].SubtypeConstraint
 		}
-	case 231:
+	case 256:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line asn1.y:1034
+		{
+			yyVAL.ConstraintSpec = ContentsConstraint{Type: yyDollar[2].Type}
+		}
+	case 257:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line asn1.y:919
+//line asn1.y:1035
 		{
-			yyVAL.SubtypeConstraint = yyDollar[1].SubtypeConstraint
+			yyVAL.ConstraintSpec = ContentsConstraint{EncodedBy: yyDollar[3].Value}
 		}
-	case 232:
+	case 258:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line asn1.y:920
+//line asn1.y:1036
+		{
+			yyVAL.ConstraintSpec = ContentsConstraint{Type: yyDollar[2].Type, EncodedBy: yyDollar[5].Value}
+		}
+	case 261:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line asn1.y:1045
+		{
+			yyVAL.SubtypeConstraint = append(yyDollar[1].SubtypeConstraint, Unions{})
+		}
+	case 262:
+		yyDollar = yyS[yypt-5 : yypt+1]
+//line asn1.y:1046
 		{
 			yyVAL.SubtypeConstraint = append(yyDollar[1].SubtypeConstraint, yyDollar[5].ElementSetSpec)
 		}
-	case 233:
+	case 263:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:923
+//line asn1.y:1049
 		{
 			yyVAL.SubtypeConstraint = SubtypeConstraint{yyDollar[1].ElementSetSpec}
 		}
-	case 235:
+	case 265:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:929
+//line asn1.y:1055
 		{
 			yyVAL.ElementSetSpec = yyDollar[1].Unions
 		}
-	case 236:
+	case 266:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line asn1.y:930
+//line asn1.y:1056
 		{
 			yyVAL.ElementSetSpec = yyDollar[2].Exclusions
 		}
-	case 237:
+	case 267:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:933
+//line asn1.y:1059
 		{
 			yyVAL.Unions = Unions{yyDollar[1].Intersections}
 		}
-	case 238:
+	case 268:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line asn1.y:934
+//line asn1.y:1060
 		{
 			yyVAL.Unions = append(yyDollar[1].Unions, yyDollar[3].Intersections)
 		}
-	case 240:
+	case 270:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:940
+//line asn1.y:1066
 		{
 			yyVAL.Intersections = Intersections{yyDollar[1].IntersectionElements}
 		}
-	case 241:
+	case 271:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line asn1.y:941
+//line asn1.y:1067
 		{
 			yyVAL.Intersections = append(yyDollar[1].Intersections, yyDollar[3].IntersectionElements)
 		}
-	case 243:
+	case 273:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:947
+//line asn1.y:1073
 		{
 			yyVAL.IntersectionElements = IntersectionElements{Elements: yyDollar[1].Elements}
 		}
-	case 244:
+	case 274:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line asn1.y:948
+//line asn1.y:1074
 		{
 			yyVAL.IntersectionElements = IntersectionElements{Elements: yyDollar[1].Elements, Exclusions: yyDollar[2].Exclusions}
 		}
-	case 246:
+	case 276:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line asn1.y:954
+//line asn1.y:1080
 		{
 			yyVAL.Exclusions = Exclusions{yyDollar[2].Elements}
 		}
-	case 251:
+	case 281:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:963
+//line asn1.y:1089
 		{
 			yyVAL.Elements = yyDollar[1].Elements
 		}
-	case 252:
+	case 282:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line asn1.y:965
+//line asn1.y:1091
 		{
 			yyVAL.Elements = yyDollar[2].ElementSetSpec
 		}
-	case 258:
+	case 291:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:980
+//line asn1.y:1106
 		{
 			yyVAL.Elements = SingleValue{yyDollar[1].Value}
 		}
-	case 259:
+	case 292:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line asn1.y:1111
+		{
+			yyVAL.Elements = TypeConstraint{yyDollar[2].Type}
+		}
+	case 293:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line asn1.y:985
+//line asn1.y:1116
 		{
 			yyVAL.Elements = ValueRange{yyDollar[1].RangeEndpoint, yyDollar[3].RangeEndpoint}
 		}
-	case 260:
+	case 294:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:988
+//line asn1.y:1119
 		{
 			yyVAL.RangeEndpoint = RangeEndpoint{Value: yyDollar[1].Value}
 		}
-	case 261:
+	case 295:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line asn1.y:989
+//line asn1.y:1120
 		{
 			yyVAL.RangeEndpoint = RangeEndpoint{Value: yyDollar[1].Value, IsOpen: true}
 		}
-	case 262:
+	case 296:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:992
+//line asn1.y:1123
 		{
 			yyVAL.RangeEndpoint = RangeEndpoint{Value: yyDollar[1].Value}
 		}
-	case 263:
+	case 297:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line asn1.y:993
+//line asn1.y:1124
 		{
 			yyVAL.RangeEndpoint = RangeEndpoint{Value: yyDollar[2].Value, IsOpen: true}
 		}
-	case 265:
+	case 299:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:997
+//line asn1.y:1128
 		{
 			yyVAL.Value = nil
 		}
-	case 267:
+	case 301:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:1001
+//line asn1.y:1132
 		{
 			yyVAL.Value = nil
 		}
-	case 268:
+	case 302:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line asn1.y:1006
+//line asn1.y:1137
 		{
 			yyVAL.Elements = SizeConstraint{yyDollar[2].Constraint}
 		}
-	case 269:
+	case 303:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line asn1.y:1142
+		{
+			yyVAL.Elements = PermittedAlphabet{yyDollar[2].Constraint}
+		}
+	case 304:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line asn1.y:1011
+//line asn1.y:1147
 		{
 			yyVAL.Elements = TypeConstraint{yyDollar[1].Type}
 		}
-	case 270:
+	case 305:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line asn1.y:1016
+//line asn1.y:1152
 		{
-			yyVAL.Elements = InnerTypeConstraint{}
+			c := yyDollar[3].Constraint
+			yyVAL.Elements = InnerTypeConstraint{Component: &c}
 		}
-	case 271:
+	case 306:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line asn1.y:1017
+//line asn1.y:1153
+		{
+			yyVAL.Elements = yyDollar[3].InnerTypeConstraint
+		}
+	case 310:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line asn1.y:1163
+		{
+			yyVAL.InnerTypeConstraint = InnerTypeConstraint{Components: yyDollar[2].NamedConstraints}
+		}
+	case 311:
+		yyDollar = yyS[yypt-5 : yypt+1]
+//line asn1.y:1166
+		{
+			yyVAL.InnerTypeConstraint = InnerTypeConstraint{Components: yyDollar[4].NamedConstraints, Partial: true}
+		}
+	case 312:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line asn1.y:1169
+		{
+			yyVAL.NamedConstraints = []NamedConstraint{yyDollar[1].NamedConstraint}
+		}
+	case 313:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line asn1.y:1170
+		{
+			yyVAL.NamedConstraints = append([]NamedConstraint{yyDollar[1].NamedConstraint}, yyDollar[3].NamedConstraints...)
+		}
+	case 314:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line asn1.y:1173
+		{
+			yyVAL.NamedConstraint = yyDollar[2].NamedConstraint
+			yyVAL.NamedConstraint.Identifier = Identifier(yyDollar[1].name)
+		}
+	case 315:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line asn1.y:1176
+		{
+			yyVAL.NamedConstraint = NamedConstraint{Constraint: yyDollar[1].ValueConstraint, Presence: yyDollar[2].Presence}
+		}
+	case 316:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line asn1.y:1179
+		{
+			c := yyDollar[1].Constraint
+			yyVAL.ValueConstraint = &c
+		}
+	case 317:
+		yyDollar = yyS[yypt-0 : yypt+1]
+//line asn1.y:1180
+		{
+			yyVAL.ValueConstraint = nil
+		}
+	case 318:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line asn1.y:1183
+		{
+			yyVAL.Presence = PRESENCE_PRESENT
+		}
+	case 319:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line asn1.y:1184
+		{
+			yyVAL.Presence = PRESENCE_ABSENT
+		}
+	case 320:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line asn1.y:1185
+		{
+			yyVAL.Presence = PRESENCE_OPTIONAL
+		}
+	case 321:
+		yyDollar = yyS[yypt-0 : yypt+1]
+//line asn1.y:1186
+		{
+			yyVAL.Presence = PRESENCE_UNSPECIFIED
+		}
+	case 322:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line asn1.y:1191
 		{
-			yyVAL.Elements = InnerTypeConstraint{}
+			yyVAL.Elements = PatternConstraint{yyDollar[2].Value}
 		}
 	}
 	goto yystack /* stack new state and value */